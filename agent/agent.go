@@ -12,7 +12,6 @@ import (
 	"net/http"
 	"net/netip"
 	"os"
-	"os/exec"
 	"os/user"
 	"path/filepath"
 	"sort"
@@ -79,6 +78,9 @@ type Client interface {
 	PostMetadata(ctx context.Context, key string, req agentsdk.PostMetadataRequest) error
 	PatchStartupLogs(ctx context.Context, req agentsdk.PatchStartupLogs) error
 	GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerConfig, error)
+	PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error
+	PostConnection(ctx context.Context, req agentsdk.PostConnectionRequest) error
+	PostScriptStatus(ctx context.Context, req agentsdk.PostScriptStatusRequest) error
 }
 
 type Agent interface {
@@ -172,6 +174,12 @@ type agent struct {
 	closeMutex    sync.Mutex
 	closed        chan struct{}
 
+	// Connection events are tracked separately from connCloseWait, because
+	// connections are closed while closeMutex is held.
+	connectionReportsMutex  sync.Mutex
+	connectionReportsClosed bool
+	connectionReportsWait   sync.WaitGroup
+
 	envVars map[string]string
 
 	manifest                     atomic.Pointer[agentsdk.Manifest] // manifest is atomic because values can change after reconnection.
@@ -207,6 +215,12 @@ func (a *agent) init(ctx context.Context) {
 	sshSrv.AgentToken = func() string { return *a.sessionToken.Load() }
 	sshSrv.Manifest = &a.manifest
 	sshSrv.ServiceBanner = &a.serviceBanner
+	sshSrv.ReportSessionRecording = func(recording agentssh.SessionRecording) {
+		a.uploadSessionRecording(ctx, recording)
+	}
+	sshSrv.ReportConnection = func(connType codersdk.ConnectionType, remoteAddr net.Addr, port uint16) func() {
+		return a.reportConnection(ctx, connType, remoteAddr, port)
+	}
 	a.sshServer = sshSrv
 
 	go a.runLoop(ctx)
@@ -633,6 +647,14 @@ func (a *agent) run(ctx context.Context) error {
 		if err != nil {
 			return xerrors.Errorf("track startup script: %w", err)
 		}
+		scriptsDone := make(chan map[string]codersdk.WorkspaceAgentScriptStatus, 1)
+		err = a.trackConnGoroutine(func() {
+			defer close(scriptsDone)
+			scriptsDone <- a.runScripts(ctx, manifest.Scripts, codersdk.WorkspaceAgentScriptRunOnStart)
+		})
+		if err != nil {
+			return xerrors.Errorf("track scripts: %w", err)
+		}
 		go func() {
 			var timeout <-chan time.Time
 			// If timeout is zero, an older version of the coder
@@ -657,6 +679,20 @@ func (a *agent) run(ctx context.Context) error {
 				}
 				lifecycleState = codersdk.WorkspaceAgentLifecycleStartError
 			}
+
+			// Named scripts enforce their own timeouts.
+			statuses := <-scriptsDone
+			if ctx.Err() != nil {
+				return
+			}
+			switch worstScriptStatus(statuses) {
+			case codersdk.WorkspaceAgentScriptStatusError:
+				lifecycleState = codersdk.WorkspaceAgentLifecycleStartError
+			case codersdk.WorkspaceAgentScriptStatusTimeout:
+				if lifecycleState == codersdk.WorkspaceAgentLifecycleReady {
+					lifecycleState = codersdk.WorkspaceAgentLifecycleStartTimeout
+				}
+			}
 			a.setLifecycle(ctx, lifecycleState)
 		}()
 	}
@@ -742,6 +778,9 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 		Logger:         a.logger.Named("tailnet"),
 		ListenPort:     a.tailnetListenPort,
 		BlockEndpoints: disableDirectConnections,
+		ForwardTCPCallback: func(remoteAddr net.Addr, port uint16) func() {
+			return a.reportConnection(ctx, codersdk.ConnectionTypePortForward, remoteAddr, port)
+		},
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -928,22 +967,30 @@ func (a *agent) runCoordinator(ctx context.Context, network *tailnet.Conn) error
 }
 
 func (a *agent) runStartupScript(ctx context.Context, script string) error {
-	return a.runScript(ctx, "startup", script)
+	return a.runScript(ctx, "startup", "", script)
 }
 
 func (a *agent) runShutdownScript(ctx context.Context, script string) error {
-	return a.runScript(ctx, "shutdown", script)
+	return a.runScript(ctx, "shutdown", "", script)
 }
 
-func (a *agent) runScript(ctx context.Context, lifecycle, script string) (err error) {
+// runScript runs a script and writes its output to a log file. The output
+// of startup scripts and named scripts is also sent to the startup logs,
+// where the name of the script (if any) is used as the log source.
+func (a *agent) runScript(ctx context.Context, lifecycle, name, script string) (err error) {
 	if script == "" {
 		return nil
 	}
 
 	logger := a.logger.With(slog.F("lifecycle", lifecycle))
+	logFile := fmt.Sprintf("coder-%s-script.log", lifecycle)
+	if name != "" {
+		logger = logger.With(slog.F("script_name", name))
+		logFile = fmt.Sprintf("coder-script-%s.log", name)
+	}
 
 	logger.Info(ctx, fmt.Sprintf("running %s script", lifecycle), slog.F("script", script))
-	fileWriter, err := a.filesystem.OpenFile(filepath.Join(a.logDir, fmt.Sprintf("coder-%s-script.log", lifecycle)), os.O_CREATE|os.O_RDWR, 0o600)
+	fileWriter, err := a.filesystem.OpenFile(filepath.Join(a.logDir, logFile), os.O_CREATE|os.O_RDWR, 0o600)
 	if err != nil {
 		return xerrors.Errorf("open %s script log file: %w", lifecycle, err)
 	}
@@ -961,8 +1008,17 @@ func (a *agent) runScript(ctx context.Context, lifecycle, script string) (err er
 	cmd := cmdPty.AsExec()
 
 	var stdout, stderr io.Writer = fileWriter, fileWriter
-	if lifecycle == "startup" {
+	if lifecycle == "startup" || name != "" {
 		send, flushAndClose := agentsdk.StartupLogsSender(a.client.PatchStartupLogs, logger)
+		if name != "" {
+			sendLogs := send
+			send = func(ctx context.Context, logs ...agentsdk.StartupLog) error {
+				for i := range logs {
+					logs[i].Source = name
+				}
+				return sendLogs(ctx, logs...)
+			}
+		}
 		// If ctx is canceled here (or in a writer below), we may be
 		// discarding logs, but that's okay because we're shutting down
 		// anyway. We could consider creating a new context here if we
@@ -989,13 +1045,8 @@ func (a *agent) runScript(ctx context.Context, lifecycle, script string) (err er
 	defer func() {
 		end := time.Now()
 		execTime := end.Sub(start)
-		exitCode := 0
+		exitCode := scriptExitCode(err)
 		if err != nil {
-			exitCode = 255 // Unknown status.
-			var exitError *exec.ExitError
-			if xerrors.As(err, &exitError) {
-				exitCode = exitError.ExitCode()
-			}
 			logger.Warn(ctx, fmt.Sprintf("%s script failed", lifecycle), slog.F("execution_time", execTime), slog.F("exit_code", exitCode), slog.Error(err))
 		} else {
 			logger.Info(ctx, fmt.Sprintf("%s script completed", lifecycle), slog.F("execution_time", execTime), slog.F("exit_code", exitCode))
@@ -1089,6 +1140,16 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			return xerrors.Errorf("start command: %w", err)
 		}
 
+		var recorder *agentssh.Recorder
+		if manifest := a.manifest.Load(); manifest != nil && manifest.RecordSessions {
+			recorder = agentssh.NewRecorder(codersdk.WorkspaceAgentSessionRecordingTypeReconnectingPTY, msg.Width, msg.Height, "xterm-256color")
+			// The banner is kept in the buffer so that it's replayed
+			// to every connection, and is recorded like in SSH sessions.
+			banner := []byte(agentssh.SessionRecordingBanner + "\r\n\r\n")
+			_, _ = recorder.Write(banner)
+			_, _ = circularBuffer.Write(banner)
+		}
+
 		ctx, cancel := context.WithCancel(ctx)
 		rpty = &reconnectingPTY{
 			activeConns: map[string]net.Conn{
@@ -1100,6 +1161,7 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			// Timeouts created with an after func can be reset!
 			timeout:        time.AfterFunc(a.reconnectingPTYTimeout, cancel),
 			circularBuffer: circularBuffer,
+			recorder:       recorder,
 		}
 		// We don't need to separately monitor for the process exiting.
 		// When it exits, our ptty.OutputReader() will return EOF after
@@ -1120,6 +1182,9 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 					break
 				}
 				part := buffer[:read]
+				if recorder != nil {
+					_, _ = recorder.Write(part)
+				}
 				rpty.circularBufferMutex.Lock()
 				_, err = rpty.circularBuffer.Write(part)
 				rpty.circularBufferMutex.Unlock()
@@ -1147,6 +1212,9 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			_ = process.Kill()
 			rpty.Close()
 			a.reconnectingPTYs.Delete(msg.ID)
+			if recorder != nil {
+				a.sshServer.ReportSessionRecording(recorder.Close())
+			}
 		}); err != nil {
 			_ = process.Kill()
 			_ = ptty.Close()
@@ -1156,6 +1224,9 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		sendConnected <- rpty
 	}
 	// Resize the PTY to initial height + width.
+	if rpty.recorder != nil {
+		rpty.recorder.Resize(msg.Width, msg.Height)
+	}
 	err := rpty.ptty.Resize(msg.Height, msg.Width)
 	if err != nil {
 		// We can continue after this, it's not fatal!
@@ -1231,6 +1302,9 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		if req.Height == 0 || req.Width == 0 {
 			continue
 		}
+		if rpty.recorder != nil {
+			rpty.recorder.Resize(req.Width, req.Height)
+		}
 		err = rpty.ptty.Resize(req.Height, req.Width)
 		if err != nil {
 			// We can continue after this, it's not fatal!
@@ -1240,6 +1314,112 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 	}
 }
 
+// uploadSessionRecording uploads a session recording in the background. It
+// is retried until it succeeds, coderd rejects it, or the agent is closed.
+func (a *agent) uploadSessionRecording(ctx context.Context, recording agentssh.SessionRecording) {
+	logger := a.logger.With(slog.F("recording_id", recording.ID), slog.F("size", len(recording.Data)))
+	err := a.trackConnGoroutine(func() {
+		for r := retry.New(time.Second, 30*time.Second); r.Wait(ctx); {
+			err := a.client.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+				ID:        recording.ID,
+				Type:      recording.Type,
+				StartedAt: recording.StartedAt,
+				EndedAt:   recording.EndedAt,
+				Truncated: recording.Truncated,
+				Data:      recording.Data,
+			})
+			if err == nil {
+				logger.Debug(ctx, "uploaded session recording")
+				return
+			}
+			var sdkErr *codersdk.Error
+			if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() >= 400 && sdkErr.StatusCode() < 500 && sdkErr.StatusCode() != http.StatusTooManyRequests {
+				logger.Error(ctx, "session recording rejected", slog.Error(err))
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn(ctx, "failed to upload session recording, retrying", slog.Error(err))
+		}
+	})
+	if err != nil {
+		logger.Warn(ctx, "unable to upload session recording", slog.Error(err))
+	}
+}
+
+// reportConnection reports a connection to coderd in the background, so it
+// can be audited. The returned func reports the disconnect.
+func (a *agent) reportConnection(ctx context.Context, connType codersdk.ConnectionType, remoteAddr net.Addr, port uint16) (disconnected func()) {
+	ip := remoteAddr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	req := agentsdk.PostConnectionRequest{
+		ID:     uuid.New(),
+		Action: codersdk.AuditActionConnect,
+		Type:   connType,
+		Time:   time.Now(),
+		IP:     ip,
+		Port:   port,
+	}
+	connected := make(chan struct{})
+	a.postConnection(ctx, req, nil, connected)
+
+	return func() {
+		disconnect := req
+		disconnect.Action = codersdk.AuditActionDisconnect
+		disconnect.Time = time.Now()
+		disconnect.DurationMS = disconnect.Time.Sub(req.Time).Milliseconds()
+		a.postConnection(ctx, disconnect, connected, nil)
+	}
+}
+
+// postConnection posts a connection event in the background once after is
+// closed, and closes done when it's finished. It is retried until it
+// succeeds, coderd rejects it, or the agent is closed.
+func (a *agent) postConnection(ctx context.Context, req agentsdk.PostConnectionRequest, after <-chan struct{}, done chan<- struct{}) {
+	logger := a.logger.With(slog.F("connection_id", req.ID), slog.F("action", req.Action), slog.F("type", req.Type))
+	a.connectionReportsMutex.Lock()
+	defer a.connectionReportsMutex.Unlock()
+	if a.connectionReportsClosed {
+		if done != nil {
+			close(done)
+		}
+		logger.Warn(ctx, "unable to post connection event, agent is closed")
+		return
+	}
+	a.connectionReportsWait.Add(1)
+	go func() {
+		defer a.connectionReportsWait.Done()
+		if done != nil {
+			defer close(done)
+		}
+		if after != nil {
+			select {
+			case <-after:
+			case <-ctx.Done():
+				return
+			}
+		}
+		for r := retry.New(time.Second, 30*time.Second); r.Wait(ctx); {
+			err := a.client.PostConnection(ctx, req)
+			if err == nil {
+				return
+			}
+			var sdkErr *codersdk.Error
+			if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() >= 400 && sdkErr.StatusCode() < 500 && sdkErr.StatusCode() != http.StatusTooManyRequests {
+				logger.Error(ctx, "connection event rejected", slog.Error(err))
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn(ctx, "failed to post connection event, retrying", slog.Error(err))
+		}
+	}()
+}
+
 // startReportingConnectionStats runs the connection stats reporting goroutine.
 func (a *agent) startReportingConnectionStats(ctx context.Context) {
 	reportStats := func(networkStats map[netlogtype.Connection]netlogtype.Counts) {
@@ -1395,7 +1575,16 @@ func (a *agent) Close() error {
 	}
 
 	lifecycleState := codersdk.WorkspaceAgentLifecycleOff
-	if manifest := a.manifest.Load(); manifest != nil && manifest.ShutdownScript != "" {
+	manifest := a.manifest.Load()
+	var scriptsDone chan map[string]codersdk.WorkspaceAgentScriptStatus
+	if manifest != nil {
+		scriptsDone = make(chan map[string]codersdk.WorkspaceAgentScriptStatus, 1)
+		go func() {
+			defer close(scriptsDone)
+			scriptsDone <- a.runScripts(ctx, manifest.Scripts, codersdk.WorkspaceAgentScriptRunOnStop)
+		}()
+	}
+	if manifest != nil && manifest.ShutdownScript != "" {
 		scriptDone := make(chan error, 1)
 		go func() {
 			defer close(scriptDone)
@@ -1423,6 +1612,17 @@ func (a *agent) Close() error {
 			lifecycleState = codersdk.WorkspaceAgentLifecycleShutdownError
 		}
 	}
+	if scriptsDone != nil {
+		// Named scripts enforce their own timeouts.
+		switch worstScriptStatus(<-scriptsDone) {
+		case codersdk.WorkspaceAgentScriptStatusError:
+			lifecycleState = codersdk.WorkspaceAgentLifecycleShutdownError
+		case codersdk.WorkspaceAgentScriptStatusTimeout:
+			if lifecycleState == codersdk.WorkspaceAgentLifecycleOff {
+				lifecycleState = codersdk.WorkspaceAgentLifecycleShutdownTimeout
+			}
+		}
+	}
 
 	// Set final state and wait for it to be reported because context
 	// cancellation will stop the report loop.
@@ -1452,6 +1652,11 @@ lifecycleWaitLoop:
 	}
 	a.connCloseWait.Wait()
 
+	a.connectionReportsMutex.Lock()
+	a.connectionReportsClosed = true
+	a.connectionReportsMutex.Unlock()
+	a.connectionReportsWait.Wait()
+
 	return nil
 }
 
@@ -1463,6 +1668,8 @@ type reconnectingPTY struct {
 	circularBufferMutex sync.RWMutex
 	timeout             *time.Timer
 	ptty                pty.PTYCmd
+	// recorder is nil unless the session is recorded.
+	recorder *agentssh.Recorder
 }
 
 // Close ends all connections to the reconnecting
@@ -1133,6 +1133,133 @@ func TestAgent_StartupScript(t *testing.T) {
 	})
 }
 
+func TestAgent_Scripts(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("scripts use sh syntax")
+	}
+
+	// waitScripts waits until all scripts have reported a final status and
+	// returns the final status of each script.
+	waitScripts := func(t *testing.T, client *agenttest.Client, scripts []codersdk.WorkspaceAgentScript) map[uuid.UUID]agentsdk.PostScriptStatusRequest {
+		t.Helper()
+		final := map[uuid.UUID]agentsdk.PostScriptStatusRequest{}
+		require.Eventually(t, func() bool {
+			for _, status := range client.GetScriptStatuses() {
+				if status.Status.Done() {
+					final[status.ScriptID] = status
+				}
+			}
+			return len(final) == len(scripts)
+		}, testutil.WaitShort, testutil.IntervalFast)
+		return final
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		scripts := []codersdk.WorkspaceAgentScript{{
+			ID:        uuid.New(),
+			Name:      "second",
+			Script:    "echo second",
+			RunOn:     codersdk.WorkspaceAgentScriptRunOnStart,
+			DependsOn: []string{"first"},
+		}, {
+			ID:     uuid.New(),
+			Name:   "first",
+			Script: "sleep 0.2 && echo first",
+			RunOn:  codersdk.WorkspaceAgentScriptRunOnStart,
+		}, {
+			ID:     uuid.New(),
+			Name:   "stop",
+			Script: "echo stop",
+			RunOn:  codersdk.WorkspaceAgentScriptRunOnStop,
+		}}
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Scripts: scripts,
+		}, 0)
+
+		final := waitScripts(t, client, scripts[:2])
+		for _, script := range scripts[:2] {
+			status := final[script.ID]
+			require.Equal(t, codersdk.WorkspaceAgentScriptStatusSuccess, status.Status, script.Name)
+			require.NotNil(t, status.ExitCode)
+			require.EqualValues(t, 0, *status.ExitCode)
+			require.False(t, status.EndedAt.Before(status.StartedAt))
+		}
+		// The dependent script must not start before its dependency ended.
+		require.False(t, final[scripts[0].ID].StartedAt.Before(final[scripts[1].ID].EndedAt))
+
+		require.Eventually(t, func() bool {
+			got := client.GetLifecycleStates()
+			return len(got) > 0 && got[len(got)-1] == codersdk.WorkspaceAgentLifecycleReady
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		// Each script logs with its own source.
+		var logs []agentsdk.StartupLog
+		require.Eventually(t, func() bool {
+			logs = client.GetStartupLogs()
+			return len(logs) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, "first", logs[0].Source)
+		require.Equal(t, "first", logs[0].Output)
+		require.Equal(t, "second", logs[1].Source)
+		require.Equal(t, "second", logs[1].Output)
+	})
+
+	t.Run("FailureSkipsDependents", func(t *testing.T) {
+		t.Parallel()
+		scripts := []codersdk.WorkspaceAgentScript{{
+			ID:     uuid.New(),
+			Name:   "fail",
+			Script: "exit 3",
+			RunOn:  codersdk.WorkspaceAgentScriptRunOnStart,
+		}, {
+			ID:        uuid.New(),
+			Name:      "dependent",
+			Script:    "echo unreachable",
+			RunOn:     codersdk.WorkspaceAgentScriptRunOnStart,
+			DependsOn: []string{"fail"},
+		}}
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Scripts: scripts,
+		}, 0)
+
+		final := waitScripts(t, client, scripts)
+		require.Equal(t, codersdk.WorkspaceAgentScriptStatusError, final[scripts[0].ID].Status)
+		require.NotNil(t, final[scripts[0].ID].ExitCode)
+		require.EqualValues(t, 3, *final[scripts[0].ID].ExitCode)
+		require.Equal(t, codersdk.WorkspaceAgentScriptStatusSkipped, final[scripts[1].ID].Status)
+
+		require.Eventually(t, func() bool {
+			got := client.GetLifecycleStates()
+			return len(got) > 0 && got[len(got)-1] == codersdk.WorkspaceAgentLifecycleStartError
+		}, testutil.WaitShort, testutil.IntervalMedium)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		scripts := []codersdk.WorkspaceAgentScript{{
+			ID:             uuid.New(),
+			Name:           "slow",
+			Script:         "sleep 30",
+			RunOn:          codersdk.WorkspaceAgentScriptRunOnStart,
+			TimeoutSeconds: 1,
+		}}
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Scripts: scripts,
+		}, 0)
+
+		final := waitScripts(t, client, scripts)
+		require.Equal(t, codersdk.WorkspaceAgentScriptStatusTimeout, final[scripts[0].ID].Status)
+		require.Nil(t, final[scripts[0].ID].ExitCode)
+
+		require.Eventually(t, func() bool {
+			got := client.GetLifecycleStates()
+			return len(got) > 0 && got[len(got)-1] == codersdk.WorkspaceAgentLifecycleStartTimeout
+		}, testutil.WaitShort, testutil.IntervalMedium)
+	})
+}
+
 func TestAgent_Metadata(t *testing.T) {
 	t.Parallel()
 
@@ -1653,6 +1780,172 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	expectLine(matchEchoOutput)
 }
 
+func TestAgent_SessionRecording(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{RecordSessions: true}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+
+		err = session.RequestPty("xterm", 128, 128, ssh.TerminalModes{})
+		require.NoError(t, err)
+		var stdout bytes.Buffer
+		session.Stdout = &stdout
+		err = session.Run("echo recorded-output")
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), agentssh.SessionRecordingBanner)
+
+		var recordings []agentsdk.PostSessionRecordingRequest
+		require.Eventually(t, func() bool {
+			recordings = client.GetSessionRecordings()
+			return len(recordings) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		recording := recordings[0]
+		require.Equal(t, codersdk.WorkspaceAgentSessionRecordingTypeSSH, recording.Type)
+		require.False(t, recording.Truncated)
+		require.Contains(t, string(recording.Data), `"width":128,"height":128`)
+		require.Contains(t, string(recording.Data), agentssh.SessionRecordingBanner)
+		require.Contains(t, string(recording.Data), "recorded-output")
+	})
+
+	t.Run("ReconnectingPTY", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{RecordSessions: true}, 0)
+		netConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 80, 80, "sh -c 'echo recorded-output'")
+		require.NoError(t, err)
+		defer netConn.Close()
+
+		// The command exits immediately, which closes the connection.
+		output, err := io.ReadAll(netConn)
+		require.NoError(t, err)
+		require.Contains(t, string(output), agentssh.SessionRecordingBanner)
+
+		var recordings []agentsdk.PostSessionRecordingRequest
+		require.Eventually(t, func() bool {
+			recordings = client.GetSessionRecordings()
+			return len(recordings) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		recording := recordings[0]
+		require.Equal(t, codersdk.WorkspaceAgentSessionRecordingTypeReconnectingPTY, recording.Type)
+		require.Contains(t, string(recording.Data), agentssh.SessionRecordingBanner)
+		require.Contains(t, string(recording.Data), "recorded-output")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+
+		err = session.RequestPty("xterm", 128, 128, ssh.TerminalModes{})
+		require.NoError(t, err)
+		var stdout bytes.Buffer
+		session.Stdout = &stdout
+		err = session.Run("echo output")
+		require.NoError(t, err)
+		require.NotContains(t, stdout.String(), agentssh.SessionRecordingBanner)
+		require.Empty(t, client.GetSessionRecordings())
+	})
+}
+
+func TestAgent_ReportConnections(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		err = session.Run("echo test")
+		require.NoError(t, err)
+		_ = session.Close()
+		_ = sshClient.Close()
+
+		var connections []agentsdk.PostConnectionRequest
+		require.Eventually(t, func() bool {
+			connections = client.GetConnections()
+			return len(connections) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, codersdk.AuditActionConnect, connections[0].Action)
+		require.Equal(t, codersdk.AuditActionDisconnect, connections[1].Action)
+		require.Equal(t, connections[0].ID, connections[1].ID)
+		require.Equal(t, codersdk.ConnectionTypeSSH, connections[0].Type)
+		require.NotEmpty(t, connections[0].IP)
+	})
+
+	t.Run("PortForward", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer l.Close()
+		go func() {
+			for {
+				c, err := l.Accept()
+				if err != nil {
+					return
+				}
+				go testAccept(t, c)
+			}
+		}()
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		require.True(t, conn.AwaitReachable(ctx))
+		forwarded, err := conn.DialContext(ctx, "tcp", l.Addr().String())
+		require.NoError(t, err)
+		testDial(t, forwarded)
+		_ = forwarded.Close()
+
+		var connections []agentsdk.PostConnectionRequest
+		require.Eventually(t, func() bool {
+			connections = client.GetConnections()
+			return len(connections) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, codersdk.ConnectionTypePortForward, connections[0].Type)
+		require.Equal(t, uint16(l.Addr().(*net.TCPAddr).Port), connections[0].Port)
+		require.Equal(t, codersdk.AuditActionDisconnect, connections[1].Action)
+	})
+}
+
 func TestAgent_Dial(t *testing.T) {
 	t.Parallel()
 
@@ -47,6 +47,11 @@ const (
 	MagicSessionTypeVSCode = "vscode"
 	// MagicSessionTypeJetBrains is set in the SSH config by the JetBrains extension to identify itself.
 	MagicSessionTypeJetBrains = "jetbrains"
+
+	// MagicWorkingDirectoryEnvironmentVariable overrides the directory a
+	// command is executed in. Relative paths are resolved against the
+	// default directory. This is stripped from any commands being executed.
+	MagicWorkingDirectoryEnvironmentVariable = "CODER_SSH_WORKING_DIRECTORY"
 )
 
 type Server struct {
@@ -68,6 +73,13 @@ type Server struct {
 	AgentToken    func() string
 	Manifest      *atomic.Pointer[agentsdk.Manifest]
 	ServiceBanner *atomic.Pointer[codersdk.ServiceBannerConfig]
+	// ReportSessionRecording is called with the recording of each PTY
+	// session when the manifest enables session recording.
+	ReportSessionRecording func(SessionRecording)
+	// ReportConnection is called when an SSH connection or a port forward
+	// through SSH opens, and the returned func when it closes. The port is
+	// only set for port forwards.
+	ReportConnection func(connType codersdk.ConnectionType, remoteAddr net.Addr, port uint16) (disconnected func())
 
 	connCountVSCode     atomic.Int64
 	connCountJetBrains  atomic.Int64
@@ -109,7 +121,7 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 
 	srv := &ssh.Server{
 		ChannelHandlers: map[string]ssh.ChannelHandler{
-			"direct-tcpip":                   ssh.DirectTCPIPHandler,
+			"direct-tcpip":                   s.directTCPIPHandler,
 			"direct-streamlocal@openssh.com": directStreamLocalHandler,
 			"session":                        ssh.DefaultSessionHandler,
 		},
@@ -254,6 +266,15 @@ func (s *Server) sessionStart(session ssh.Session, extraEnv []string) (retErr er
 		magicType = strings.TrimPrefix(kv, MagicSessionTypeEnvironmentVariable+"=")
 		env = append(env[:index], env[index+1:]...)
 	}
+	var workingDirectory string
+	for index, kv := range env {
+		if !strings.HasPrefix(kv, MagicWorkingDirectoryEnvironmentVariable+"=") {
+			continue
+		}
+		workingDirectory = strings.TrimPrefix(kv, MagicWorkingDirectoryEnvironmentVariable+"=")
+		env = append(env[:index], env[index+1:]...)
+		break
+	}
 	switch magicType {
 	case MagicSessionTypeVSCode:
 		s.connCountVSCode.Add(1)
@@ -281,6 +302,28 @@ func (s *Server) sessionStart(session ssh.Session, extraEnv []string) (retErr er
 		return err
 	}
 
+	if workingDirectory != "" {
+		if !filepath.IsAbs(workingDirectory) {
+			workingDirectory = filepath.Join(cmd.Dir, workingDirectory)
+		}
+		info, err := os.Stat(workingDirectory)
+		if err == nil && !info.IsDir() {
+			err = xerrors.New("not a directory")
+		}
+		if err != nil {
+			ptyLabel := "no"
+			if isPty {
+				ptyLabel = "yes"
+			}
+			s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, ptyLabel, "working_directory").Add(1)
+			err = xerrors.Errorf("working directory %q: %w", workingDirectory, err)
+			// Let the user know why the command was not executed.
+			_, _ = fmt.Fprintln(session.Stderr(), err.Error())
+			return err
+		}
+		cmd.Dir = workingDirectory
+	}
+
 	if ssh.AgentRequested(session) {
 		l, err := ssh.NewAgentListener()
 		if err != nil {
@@ -347,6 +390,24 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	// See https://github.com/coder/coder/issues/3371.
 	session.DisablePTYEmulation()
 
+	var recorder *Recorder
+	if s.recordSessions() {
+		recorder = NewRecorder(codersdk.WorkspaceAgentSessionRecordingTypeSSH, uint16(sshPty.Window.Width), uint16(sshPty.Window.Height), sshPty.Term)
+		defer func() {
+			recording := recorder.Close()
+			if s.ReportSessionRecording != nil {
+				s.ReportSessionRecording(recording)
+			}
+		}()
+		// The banner is recorded too, so that viewers of the recording
+		// know the user was told.
+		err := showRecordingBanner(io.MultiWriter(session, recorder))
+		if err != nil {
+			s.logger.Error(ctx, "agent failed to show session recording banner", slog.Error(err))
+			s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "recording_banner").Add(1)
+		}
+	}
+
 	if isLoginShell(session.RawCommand()) {
 		serviceBanner := s.ServiceBanner.Load()
 		if serviceBanner != nil {
@@ -394,6 +455,9 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	}()
 	go func() {
 		for win := range windowSize {
+			if recorder != nil {
+				recorder.Resize(uint16(win.Width), uint16(win.Height))
+			}
 			resizeErr := ptty.Resize(uint16(win.Height), uint16(win.Width))
 			// If the pty is closed, then command has exited, no need to log.
 			if resizeErr != nil && !errors.Is(resizeErr, pty.ErrClosed) {
@@ -418,7 +482,11 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	//    after we've Read() all the buffered data from the PTY.
 	// 2. The client hangs up, which cancels the command's Context, and go will
 	//    kill the command's process.  This then has the same effect as (1).
-	n, err := io.Copy(session, ptty.OutputReader())
+	var output io.Writer = session
+	if recorder != nil {
+		output = io.MultiWriter(session, recorder)
+	}
+	n, err := io.Copy(output, ptty.OutputReader())
 	s.logger.Debug(ctx, "copy output done", slog.F("bytes", n), slog.Error(err))
 	if err != nil {
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "output_io_copy").Add(1)
@@ -441,6 +509,15 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	return nil
 }
 
+// recordSessions returns true if the manifest enables session recording.
+func (s *Server) recordSessions() bool {
+	if s.Manifest == nil {
+		return false
+	}
+	manifest := s.Manifest.Load()
+	return manifest != nil && manifest.RecordSessions
+}
+
 func (s *Server) sftpHandler(session ssh.Session) {
 	s.metrics.sftpConnectionsTotal.Add(1)
 
@@ -625,6 +702,10 @@ func (s *Server) handleConn(l net.Listener, c net.Conn) {
 	}
 	defer s.trackConn(l, c, false)
 	logger.Info(context.Background(), "started serving connection")
+	if s.ReportConnection != nil {
+		disconnected := s.ReportConnection(codersdk.ConnectionTypeSSH, c.RemoteAddr(), 0)
+		defer disconnected()
+	}
 	// note: srv.ConnectionCompleteCallback logs completion of the connection
 	s.srv.HandleConn(c)
 }
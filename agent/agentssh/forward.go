@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 
 	"github.com/gliderlabs/ssh"
@@ -13,6 +14,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/codersdk"
 )
 
 // streamLocalForwardPayload describes the extra data sent in a
@@ -201,3 +203,48 @@ func directStreamLocalHandler(_ *ssh.Server, _ *gossh.ServerConn, newChan gossh.
 
 	Bicopy(ctx, ch, dconn)
 }
+
+// directTCPIPPayload describes the extra data sent in a direct-tcpip channel
+// request, as specified in RFC 4254, Section 7.2.
+type directTCPIPPayload struct {
+	DestAddr   string
+	DestPort   uint32
+	OriginAddr string
+	OriginPort uint32
+}
+
+// directTCPIPHandler is a clone of ssh.DirectTCPIPHandler that reports the
+// forwarded connection.
+func (s *Server) directTCPIPHandler(srv *ssh.Server, conn *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+	var reqPayload directTCPIPPayload
+	err := gossh.Unmarshal(newChan.ExtraData(), &reqPayload)
+	if err != nil {
+		_ = newChan.Reject(gossh.ConnectionFailed, "could not parse direct-tcpip channel payload")
+		return
+	}
+	if srv.LocalPortForwardingCallback == nil || !srv.LocalPortForwardingCallback(ctx, reqPayload.DestAddr, reqPayload.DestPort) {
+		_ = newChan.Reject(gossh.Prohibited, "port forwarding is disabled")
+		return
+	}
+
+	dest := net.JoinHostPort(reqPayload.DestAddr, strconv.FormatUint(uint64(reqPayload.DestPort), 10))
+	var dialer net.Dialer
+	dconn, err := dialer.DialContext(ctx, "tcp", dest)
+	if err != nil {
+		_ = newChan.Reject(gossh.ConnectionFailed, err.Error())
+		return
+	}
+
+	ch, reqs, err := newChan.Accept()
+	if err != nil {
+		_ = dconn.Close()
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+
+	if s.ReportConnection != nil {
+		disconnected := s.ReportConnection(codersdk.ConnectionTypePortForward, conn.RemoteAddr(), uint16(reqPayload.DestPort))
+		defer disconnected()
+	}
+	Bicopy(ctx, ch, dconn)
+}
@@ -0,0 +1,179 @@
+package agentssh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// MaxSessionRecordingSize is the maximum size of a session recording. Output
+// written after the limit is reached is dropped and the recording is marked
+// as truncated.
+const MaxSessionRecordingSize = 10 << 20
+
+// SessionRecordingBanner is displayed to the user when a session is recorded.
+const SessionRecordingBanner = "This session is being recorded."
+
+// SessionRecording is a finished recording of an interactive terminal session.
+type SessionRecording struct {
+	ID        uuid.UUID
+	Type      codersdk.WorkspaceAgentSessionRecordingType
+	StartedAt time.Time
+	EndedAt   time.Time
+	// Truncated is true if output was dropped because the recording
+	// exceeded MaxSessionRecordingSize.
+	Truncated bool
+	// Data is the recording in the asciicast v2 format.
+	// See: https://docs.asciinema.org/manual/asciicast/v2/
+	Data []byte
+}
+
+// Recorder records the output of a terminal session in the asciicast v2
+// format. It is safe for concurrent use.
+type Recorder struct {
+	mu        sync.Mutex
+	id        uuid.UUID
+	typ       codersdk.WorkspaceAgentSessionRecordingType
+	startedAt time.Time
+	endedAt   time.Time
+	maxSize   int
+	width     uint16
+	height    uint16
+	buf       bytes.Buffer
+	// partial holds the trailing bytes of an incomplete UTF-8 sequence
+	// so that multi-byte characters split across writes are not mangled.
+	partial   []byte
+	truncated bool
+	closed    bool
+}
+
+// NewRecorder starts a recording of a terminal with the given size and
+// TERM value.
+func NewRecorder(typ codersdk.WorkspaceAgentSessionRecordingType, width, height uint16, term string) *Recorder {
+	r := &Recorder{
+		id:        uuid.New(),
+		typ:       typ,
+		startedAt: time.Now(),
+		maxSize:   MaxSessionRecordingSize,
+		width:     width,
+		height:    height,
+	}
+	header := struct {
+		Version   int               `json:"version"`
+		Width     uint16            `json:"width"`
+		Height    uint16            `json:"height"`
+		Timestamp int64             `json:"timestamp"`
+		Env       map[string]string `json:"env,omitempty"`
+	}{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.startedAt.Unix(),
+	}
+	if term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+	r.writeJSON(header)
+	return r
+}
+
+// Write records terminal output. It never returns an error so that it can
+// be used with io.MultiWriter without affecting the session.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.truncated {
+		return len(p), nil
+	}
+
+	data := append(r.partial, p...)
+	r.partial = nil
+	// Hold back an incomplete UTF-8 sequence at the end of the
+	// output until the rest of it is written.
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(data[i]) {
+			continue
+		}
+		if !utf8.FullRune(data[i:]) {
+			r.partial = bytes.Clone(data[i:])
+			data = data[:i]
+		}
+		break
+	}
+	if len(data) > 0 {
+		r.writeEvent("o", string(data))
+	}
+	return len(p), nil
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(width, height uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.truncated || (width == r.width && height == r.height) {
+		return
+	}
+	r.width, r.height = width, height
+	r.writeEvent("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Close stops the recording and returns it. Subsequent calls return the
+// same recording.
+func (r *Recorder) Close() SessionRecording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed {
+		r.closed = true
+		r.endedAt = time.Now()
+		if len(r.partial) > 0 && !r.truncated {
+			r.writeEvent("o", string(r.partial))
+		}
+		r.partial = nil
+	}
+	return SessionRecording{
+		ID:        r.id,
+		Type:      r.typ,
+		StartedAt: r.startedAt,
+		EndedAt:   r.endedAt,
+		Truncated: r.truncated,
+		Data:      bytes.Clone(r.buf.Bytes()),
+	}
+}
+
+// writeEvent appends an event line. The caller must hold mu.
+func (r *Recorder) writeEvent(code string, data string) {
+	elapsed := time.Since(r.startedAt).Seconds()
+	// Microsecond precision is plenty for playback.
+	elapsed = math.Round(elapsed*1e6) / 1e6
+	r.writeJSON([]interface{}{elapsed, code, data})
+}
+
+// writeJSON appends a line of JSON unless doing so would exceed the
+// maximum size. The caller must hold mu.
+func (r *Recorder) writeJSON(v interface{}) {
+	var line bytes.Buffer
+	enc := json.NewEncoder(&line)
+	enc.SetEscapeHTML(false)
+	// Encoding only fails for unsupported types, which are never used.
+	_ = enc.Encode(v)
+	if r.buf.Len()+line.Len() > r.maxSize {
+		r.truncated = true
+		return
+	}
+	_, _ = line.WriteTo(&r.buf)
+}
+
+// showRecordingBanner lets the user know that their session is recorded.
+func showRecordingBanner(session io.Writer) error {
+	return writeWithCarriageReturn(strings.NewReader(SessionRecordingBanner+"\n\n"), session)
+}
@@ -0,0 +1,103 @@
+package agentssh_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	// parse returns the header and events of an asciicast v2 recording.
+	parse := func(t *testing.T, data []byte) (map[string]interface{}, [][]interface{}) {
+		t.Helper()
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, agentssh.MaxSessionRecordingSize)
+		require.True(t, scanner.Scan(), "header")
+		var header map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+		var events [][]interface{}
+		for scanner.Scan() {
+			var event []interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+			require.Len(t, event, 3)
+			events = append(events, event)
+		}
+		require.NoError(t, scanner.Err())
+		return header, events
+	}
+
+	t.Run("Output", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := agentssh.NewRecorder(codersdk.WorkspaceAgentSessionRecordingTypeSSH, 80, 24, "xterm-256color")
+		_, err := recorder.Write([]byte("hello "))
+		require.NoError(t, err)
+		recorder.Resize(80, 24) // Unchanged, ignored.
+		recorder.Resize(120, 40)
+		_, err = recorder.Write([]byte("<world>"))
+		require.NoError(t, err)
+		recording := recorder.Close()
+
+		require.Equal(t, codersdk.WorkspaceAgentSessionRecordingTypeSSH, recording.Type)
+		require.False(t, recording.Truncated)
+		require.False(t, recording.EndedAt.Before(recording.StartedAt))
+
+		header, events := parse(t, recording.Data)
+		require.EqualValues(t, 2, header["version"])
+		require.EqualValues(t, 80, header["width"])
+		require.EqualValues(t, 24, header["height"])
+		require.Equal(t, map[string]interface{}{"TERM": "xterm-256color"}, header["env"])
+		require.Len(t, events, 3)
+		require.Equal(t, []interface{}{"o", "hello "}, events[0][1:])
+		require.Equal(t, []interface{}{"r", "120x40"}, events[1][1:])
+		require.Equal(t, []interface{}{"o", "<world>"}, events[2][1:])
+		// HTML characters should not be escaped.
+		require.Contains(t, string(recording.Data), "<world>")
+	})
+
+	t.Run("SplitUTF8", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := agentssh.NewRecorder(codersdk.WorkspaceAgentSessionRecordingTypeSSH, 80, 24, "")
+		euro := []byte("€")
+		_, _ = recorder.Write(append([]byte("a"), euro[:1]...))
+		_, _ = recorder.Write(euro[1:2])
+		_, _ = recorder.Write(append(euro[2:], 'b'))
+		recording := recorder.Close()
+
+		header, events := parse(t, recording.Data)
+		require.NotContains(t, header, "env")
+		var output strings.Builder
+		for _, event := range events {
+			output.WriteString(event[2].(string))
+		}
+		require.Equal(t, "a€b", output.String())
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := agentssh.NewRecorder(codersdk.WorkspaceAgentSessionRecordingTypeSSH, 80, 24, "")
+		chunk := bytes.Repeat([]byte("x"), 1<<20)
+		for i := 0; i < 12; i++ {
+			n, err := recorder.Write(chunk)
+			require.NoError(t, err)
+			require.Equal(t, len(chunk), n)
+		}
+		recording := recorder.Close()
+		require.True(t, recording.Truncated)
+		require.LessOrEqual(t, len(recording.Data), agentssh.MaxSessionRecordingSize)
+		// The recording must still be valid.
+		_, events := parse(t, recording.Data)
+		require.NotEmpty(t, events)
+	})
+}
@@ -53,6 +53,9 @@ type Client struct {
 	lifecycleStates []codersdk.WorkspaceAgentLifecycle
 	startup         agentsdk.PostStartupRequest
 	logs            []agentsdk.StartupLog
+	recordings      []agentsdk.PostSessionRecordingRequest
+	connections     []agentsdk.PostConnectionRequest
+	scriptStatuses  []agentsdk.PostScriptStatusRequest
 }
 
 func (c *Client) Manifest(_ context.Context) (agentsdk.Manifest, error) {
@@ -191,6 +194,48 @@ func (c *Client) GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerCo
 	return codersdk.ServiceBannerConfig{}, nil
 }
 
+func (c *Client) GetSessionRecordings() []agentsdk.PostSessionRecordingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recordings
+}
+
+func (c *Client) PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordings = append(c.recordings, req)
+	c.logger.Debug(ctx, "post session recording", slog.F("id", req.ID), slog.F("size", len(req.Data)))
+	return nil
+}
+
+func (c *Client) GetConnections() []agentsdk.PostConnectionRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connections
+}
+
+func (c *Client) PostConnection(ctx context.Context, req agentsdk.PostConnectionRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connections = append(c.connections, req)
+	c.logger.Debug(ctx, "post connection", slog.F("id", req.ID), slog.F("action", req.Action), slog.F("type", req.Type))
+	return nil
+}
+
+func (c *Client) GetScriptStatuses() []agentsdk.PostScriptStatusRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.scriptStatuses
+}
+
+func (c *Client) PostScriptStatus(ctx context.Context, req agentsdk.PostScriptStatusRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scriptStatuses = append(c.scriptStatuses, req)
+	c.logger.Debug(ctx, "post script status", slog.F("script_id", req.ScriptID), slog.F("status", req.Status))
+	return nil
+}
+
 type closeFunc func() error
 
 func (c closeFunc) Close() error {
@@ -0,0 +1,174 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+// runScripts runs the named scripts that run on the given event. Each script
+// starts once all the scripts it depends on have succeeded, and is skipped if
+// any of them did not. The status of each script is reported to coderd and
+// returned by name. Scripts that did not complete because ctx was canceled
+// have no status.
+func (a *agent) runScripts(ctx context.Context, scripts []codersdk.WorkspaceAgentScript, runOn codersdk.WorkspaceAgentScriptRunOn) map[string]codersdk.WorkspaceAgentScriptStatus {
+	type result struct {
+		done   chan struct{}
+		status codersdk.WorkspaceAgentScriptStatus
+	}
+	results := map[string]*result{}
+	for _, script := range scripts {
+		if script.RunOn != runOn {
+			continue
+		}
+		results[script.Name] = &result{done: make(chan struct{})}
+	}
+
+	var wg sync.WaitGroup
+	for _, script := range scripts {
+		if script.RunOn != runOn {
+			continue
+		}
+		script := script
+		res := results[script.Name]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(res.done)
+
+			for _, name := range script.DependsOn {
+				dep, ok := results[name]
+				if !ok {
+					// This is validated when the template is imported,
+					// so it should never happen.
+					a.logger.Warn(ctx, "script depends on unknown script", slog.F("script_name", script.Name), slog.F("depends_on", name))
+					res.status = a.skipScript(ctx, script)
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-dep.done:
+				}
+				if dep.status != codersdk.WorkspaceAgentScriptStatusSuccess {
+					res.status = a.skipScript(ctx, script)
+					return
+				}
+			}
+			res.status = a.runNamedScript(ctx, script)
+		}()
+	}
+	wg.Wait()
+
+	statuses := make(map[string]codersdk.WorkspaceAgentScriptStatus, len(results))
+	for name, res := range results {
+		if res.status != "" {
+			statuses[name] = res.status
+		}
+	}
+	return statuses
+}
+
+// runNamedScript runs a single script, killing it if it exceeds its timeout,
+// and reports its status. An empty status is returned if ctx is canceled.
+func (a *agent) runNamedScript(ctx context.Context, script codersdk.WorkspaceAgentScript) codersdk.WorkspaceAgentScriptStatus {
+	lifecycle := "startup"
+	if script.RunOn == codersdk.WorkspaceAgentScriptRunOnStop {
+		lifecycle = "shutdown"
+	}
+
+	startedAt := time.Now()
+	a.reportScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+		ScriptID:  script.ID,
+		Status:    codersdk.WorkspaceAgentScriptStatusRunning,
+		StartedAt: startedAt,
+	})
+
+	scriptCtx := ctx
+	if script.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		scriptCtx, cancel = context.WithTimeout(ctx, time.Duration(script.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+	err := a.runScript(scriptCtx, lifecycle, script.Name, script.Script)
+	if ctx.Err() != nil {
+		return ""
+	}
+
+	status := codersdk.WorkspaceAgentScriptStatusSuccess
+	var exitCode *int32
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		a.logger.Warn(ctx, "script timed out", slog.F("lifecycle", lifecycle), slog.F("script_name", script.Name), slog.F("timeout", script.TimeoutSeconds))
+		status = codersdk.WorkspaceAgentScriptStatusTimeout
+	case err != nil:
+		status = codersdk.WorkspaceAgentScriptStatusError
+		fallthrough
+	default:
+		code := int32(scriptExitCode(err))
+		exitCode = &code
+	}
+	a.reportScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+		ScriptID:  script.ID,
+		Status:    status,
+		ExitCode:  exitCode,
+		StartedAt: startedAt,
+		EndedAt:   time.Now(),
+	})
+	return status
+}
+
+// skipScript reports that a script was skipped because a script it depends
+// on did not succeed.
+func (a *agent) skipScript(ctx context.Context, script codersdk.WorkspaceAgentScript) codersdk.WorkspaceAgentScriptStatus {
+	a.logger.Info(ctx, "skipping script because a dependency did not succeed", slog.F("script_name", script.Name))
+	a.reportScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+		ScriptID: script.ID,
+		Status:   codersdk.WorkspaceAgentScriptStatusSkipped,
+	})
+	return codersdk.WorkspaceAgentScriptStatusSkipped
+}
+
+func (a *agent) reportScriptStatus(ctx context.Context, req agentsdk.PostScriptStatusRequest) {
+	err := a.client.PostScriptStatus(ctx, req)
+	if err != nil && ctx.Err() == nil {
+		a.logger.Warn(ctx, "failed to report script status", slog.F("script_id", req.ScriptID), slog.F("status", req.Status), slog.Error(err))
+	}
+}
+
+// worstScriptStatus reduces the statuses of completed scripts to error if any
+// script failed or was skipped, timeout if any script timed out, and success
+// otherwise.
+func worstScriptStatus(statuses map[string]codersdk.WorkspaceAgentScriptStatus) codersdk.WorkspaceAgentScriptStatus {
+	worst := codersdk.WorkspaceAgentScriptStatusSuccess
+	for _, status := range statuses {
+		switch status {
+		case codersdk.WorkspaceAgentScriptStatusError, codersdk.WorkspaceAgentScriptStatusSkipped:
+			return codersdk.WorkspaceAgentScriptStatusError
+		case codersdk.WorkspaceAgentScriptStatusTimeout:
+			worst = codersdk.WorkspaceAgentScriptStatusTimeout
+		}
+	}
+	return worst
+}
+
+// scriptExitCode returns the exit code of a script from the error returned
+// by running it.
+func scriptExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitError *exec.ExitError
+	if xerrors.As(err, &exitError) {
+		return exitError.ExitCode()
+	}
+	return 255 // Unknown status.
+}
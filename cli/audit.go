@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) audit() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "audit",
+		Short: "Manage audit logs",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.auditExport(),
+			r.auditVerify(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) auditExport() *clibase.Cmd {
+	var (
+		from   string
+		to     string
+		output string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "export",
+		Short: "Export audit logs as gzip compressed JSON lines",
+		Long: "The export has the same format as the archives written by the audit log retention policy.\n" + formatExamples(
+			example{
+				Description: "Export the audit logs of January 2023",
+				Command:     "coder audit export --from 2023-01-01 --to 2023-02-01 --output audit-logs-2023-01.jsonl.gz",
+			},
+			example{
+				Description: "Read the audit logs since a time",
+				Command:     "coder audit export --from 2023-01-01T12:00:00Z | gunzip",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			if from == "" {
+				return xerrors.New("--from is required")
+			}
+			fromTime, err := parseAuditTime(from)
+			if err != nil {
+				return xerrors.Errorf("parse --from: %w", err)
+			}
+			toTime := time.Now()
+			if to != "" {
+				toTime, err = parseAuditTime(to)
+				if err != nil {
+					return xerrors.Errorf("parse --to: %w", err)
+				}
+			}
+
+			export, err := client.ExportAuditLogs(inv.Context(), fromTime, toTime)
+			if err != nil {
+				return xerrors.Errorf("export audit logs: %w", err)
+			}
+			defer export.Close()
+
+			if output == "-" {
+				_, err = io.Copy(inv.Stdout, export)
+				if err != nil {
+					return xerrors.Errorf("write audit logs: %w", err)
+				}
+				return nil
+			}
+			file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return xerrors.Errorf("create %q: %w", output, err)
+			}
+			defer file.Close()
+			_, err = io.Copy(file, export)
+			if err != nil {
+				return xerrors.Errorf("write audit logs: %w", err)
+			}
+			return file.Close()
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "from",
+			Description: "Export audit logs created at or after this time, as a date (2006-01-02) or an RFC 3339 time.",
+			Value:       clibase.StringOf(&from),
+		},
+		{
+			Flag:        "to",
+			Description: "Export audit logs created before this time, as a date (2006-01-02) or an RFC 3339 time. Defaults to now.",
+			Value:       clibase.StringOf(&to),
+		},
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "File to write the export to, or - for stdout.",
+			Default:       "-",
+			Value:         clibase.StringOf(&output),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) auditVerify() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+			verification, ok := data.(codersdk.AuditLogVerification)
+			if !ok {
+				return nil, xerrors.Errorf("expected type %T, got %T", verification, data)
+			}
+			return renderAuditLogVerification(verification)
+		}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "verify",
+		Short: "Verify that audit logs were not modified or deleted",
+		Long: "Walks the audit log hash chain and reports audit logs that were modified, deleted or added " +
+			"without being chained, and signed checkpoints that don't match the chain. " +
+			"Exits with a non-zero code if any issues are found.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			verification, err := client.VerifyAuditLogs(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("verify audit logs: %w", err)
+			}
+
+			out, err := formatter.Format(inv.Context(), verification)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			if err != nil {
+				return err
+			}
+			if !verification.Valid {
+				return xerrors.New("audit log verification failed")
+			}
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type auditLogVerificationIssueRow struct {
+	Sequence   string `table:"sequence"`
+	Type       string `table:"type,default_sort"`
+	AuditLogID string `table:"audit log id"`
+	Detail     string `table:"detail"`
+}
+
+// renderAuditLogVerification renders a summary of the verification followed
+// by a table of the issues found.
+func renderAuditLogVerification(verification codersdk.AuditLogVerification) (string, error) {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "Verified %d audit logs up to sequence %d.\n", verification.Verified, verification.LastSequence)
+	if verification.Pruned > 0 {
+		_, _ = fmt.Fprintf(&sb, "%d audit logs were deleted by the retention policy.\n", verification.Pruned)
+	}
+	if verification.Legacy > 0 {
+		_, _ = fmt.Fprintf(&sb, "%d audit logs were created before hash chaining and cannot be verified.\n", verification.Legacy)
+	}
+	switch {
+	case verification.PublicKey == "":
+		sb.WriteString("Checkpoint signatures were not verified, because no audit log signing key is configured.\n")
+	case verification.LastCheckpointAt != nil:
+		_, _ = fmt.Fprintf(&sb, "%d signed checkpoints match the chain, the last one from %s.\n",
+			verification.Checkpoints, verification.LastCheckpointAt.Format(time.RFC3339))
+	default:
+		sb.WriteString("The chain has not been signed yet.\n")
+	}
+
+	if verification.Valid {
+		sb.WriteString("\nNo issues found.")
+		return sb.String(), nil
+	}
+
+	rows := make([]auditLogVerificationIssueRow, 0, len(verification.Issues))
+	for _, issue := range verification.Issues {
+		row := auditLogVerificationIssueRow{
+			Type:   string(issue.Type),
+			Detail: issue.Detail,
+		}
+		if issue.Sequence != 0 {
+			row.Sequence = fmt.Sprint(issue.Sequence)
+		}
+		if issue.AuditLogID != uuid.Nil {
+			row.AuditLogID = issue.AuditLogID.String()
+		}
+		rows = append(rows, row)
+	}
+	table, err := cliui.DisplayTable(rows, "", nil)
+	if err != nil {
+		return "", err
+	}
+	_, _ = fmt.Fprintf(&sb, "\n%s", table)
+	if verification.IssuesTruncated {
+		_, _ = fmt.Fprintf(&sb, "\nOnly the first %d issues are listed.", len(verification.Issues))
+	}
+	return sb.String(), nil
+}
+
+// parseAuditTime parses a date in the local time zone, or an RFC 3339 time.
+func parseAuditTime(s string) (time.Time, error) {
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,152 @@
+package cli_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbtestutil"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestAuditExport(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	now := time.Now().UTC()
+	resourceID := uuid.New()
+	err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+		ResourceID: resourceID,
+		Time:       now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	err = client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+		ResourceID: uuid.New(),
+		Time:       now.Add(-48 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	t.Run("Stdout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "audit", "export", "--from", now.Add(-2*time.Hour).Format(time.RFC3339))
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{resourceID}, exportedResourceIDs(t, buf))
+	})
+
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		output := filepath.Join(t.TempDir(), "audit-logs.jsonl.gz")
+		inv, root := clitest.New(t, "audit", "export",
+			"--from", now.Add(-72*time.Hour).Format(time.RFC3339),
+			"--to", now.Add(-24*time.Hour).Format(time.RFC3339),
+			"--output", output,
+		)
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		file, err := os.Open(output)
+		require.NoError(t, err)
+		defer file.Close()
+		require.Len(t, exportedResourceIDs(t, file), 1)
+	})
+
+	t.Run("MissingFrom", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "audit", "export")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "--from is required")
+	})
+}
+
+func TestAuditVerify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{})
+		require.NoError(t, err)
+
+		inv, root := clitest.New(t, "audit", "verify")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "Verified 1 audit logs up to sequence 1.")
+		require.Contains(t, buf.String(), "No issues found.")
+	})
+
+	t.Run("Issues", func(t *testing.T) {
+		t.Parallel()
+
+		db, pubsub := dbtestutil.NewDB(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database: db,
+			Pubsub:   pubsub,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{})
+		require.NoError(t, err)
+		alog := dbgen.AuditLog(t, db, database.AuditLog{})
+
+		inv, root := clitest.New(t, "audit", "verify")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "audit log verification failed")
+		require.Contains(t, buf.String(), alog.ID.String())
+		require.Contains(t, buf.String(), string(codersdk.AuditLogVerificationIssueUnchained))
+	})
+}
+
+func exportedResourceIDs(t *testing.T, r io.Reader) []uuid.UUID {
+	t.Helper()
+
+	zr, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	ids := []uuid.UUID{}
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var alog struct {
+			ResourceID uuid.UUID `json:"resource_id"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &alog))
+		ids = append(ids, alog.ResourceID)
+	}
+	require.NoError(t, scanner.Err())
+	return ids
+}
@@ -18,6 +18,9 @@ type AgentOptions struct {
 	Fetch         func(ctx context.Context, agentID uuid.UUID) (codersdk.WorkspaceAgent, error)
 	FetchLogs     func(ctx context.Context, agentID uuid.UUID, after int64, follow bool) (<-chan []codersdk.WorkspaceAgentStartupLog, io.Closer, error)
 	Wait          bool // If true, wait for the agent to be ready (startup script).
+	// WaitScripts waits for the start scripts that block login, even if
+	// Wait is false.
+	WaitScripts bool
 }
 
 // Agent displays a spinning indicator that waits for a workspace agent to connect.
@@ -81,6 +84,17 @@ func Agent(ctx context.Context, writer io.Writer, agentID uuid.UUID, opts AgentO
 		return xerrors.Errorf("fetch: %w", err)
 	}
 
+	// ready returns true once the agent no longer needs to be waited for.
+	ready := func(agent codersdk.WorkspaceAgent) bool {
+		if !agent.LifecycleState.Starting() {
+			return true
+		}
+		if opts.Wait {
+			return false
+		}
+		return !opts.WaitScripts || loginScriptsDone(agent)
+	}
+
 	sw := &stageWriter{w: writer}
 
 	showStartupLogs := false
@@ -124,8 +138,8 @@ func Agent(ctx context.Context, writer io.Writer, agentID uuid.UUID, opts AgentO
 			}
 
 			stage := "Running workspace agent startup script"
-			follow := opts.Wait
-			if !follow {
+			follow := !ready(agent)
+			if !opts.Wait {
 				stage += " (non-blocking)"
 			}
 			sw.Start(stage)
@@ -153,11 +167,11 @@ func Agent(ctx context.Context, writer io.Writer, agentID uuid.UUID, opts AgentO
 						}
 						agent = f.agent
 
-						// If the agent is no longer starting, stop following
-						// logs because FetchLogs will keep streaming forever.
+						// If the agent is ready, stop following logs
+						// because FetchLogs will keep streaming forever.
 						// We do one last non-follow request to ensure we have
 						// fetched all logs.
-						if !agent.LifecycleState.Starting() {
+						if ready(agent) {
 							_ = logsCloser.Close()
 							fetchedAgentWhileFollowing = nil
 
@@ -183,7 +197,7 @@ func Agent(ctx context.Context, writer io.Writer, agentID uuid.UUID, opts AgentO
 				return err
 			}
 
-			for follow && agent.LifecycleState.Starting() {
+			for follow && !ready(agent) {
 				if agent, err = fetch(); err != nil {
 					return xerrors.Errorf("fetch: %w", err)
 				}
@@ -235,6 +249,17 @@ func Agent(ctx context.Context, writer io.Writer, agentID uuid.UUID, opts AgentO
 	}
 }
 
+// loginScriptsDone returns true if the start scripts that block login are
+// done.
+func loginScriptsDone(agent codersdk.WorkspaceAgent) bool {
+	for _, script := range agent.Scripts {
+		if script.StartBlocksLogin && script.RunOn == codersdk.WorkspaceAgentScriptRunOnStart && !script.Status.Done() {
+			return false
+		}
+	}
+	return true
+}
+
 func troubleshootingMessage(agent codersdk.WorkspaceAgent, url string) string {
 	m := "For more information and troubleshooting, see " + url
 	if agent.TroubleshootingURL != "" {
@@ -90,6 +90,51 @@ func TestAgent(t *testing.T) {
 				"✔ Running workspace agent startup script (non-blocking)",
 			},
 		},
+		{
+			name: "Login blocking script",
+			opts: cliui.AgentOptions{
+				FetchInterval: time.Millisecond,
+				WaitScripts:   true,
+			},
+			iter: []func(context.Context, *codersdk.WorkspaceAgent, chan []codersdk.WorkspaceAgentStartupLog) error{
+				func(_ context.Context, agent *codersdk.WorkspaceAgent, logs chan []codersdk.WorkspaceAgentStartupLog) error {
+					agent.Status = codersdk.WorkspaceAgentConnected
+					agent.FirstConnectedAt = ptr.Ref(time.Now())
+					agent.LifecycleState = codersdk.WorkspaceAgentLifecycleStarting
+					agent.StartedAt = ptr.Ref(time.Now())
+					agent.Scripts = []codersdk.WorkspaceAgentScript{{
+						Name:             "dotfiles",
+						RunOn:            codersdk.WorkspaceAgentScriptRunOnStart,
+						StartBlocksLogin: true,
+						Status:           codersdk.WorkspaceAgentScriptStatusRunning,
+					}, {
+						Name:   "services",
+						RunOn:  codersdk.WorkspaceAgentScriptRunOnStart,
+						Status: codersdk.WorkspaceAgentScriptStatusRunning,
+					}}
+					return nil
+				},
+				func(_ context.Context, _ *codersdk.WorkspaceAgent, logs chan []codersdk.WorkspaceAgentStartupLog) error {
+					logs <- []codersdk.WorkspaceAgentStartupLog{{
+						CreatedAt: time.Now(),
+						Output:    "Installing dotfiles",
+					}}
+					return nil
+				},
+				func(_ context.Context, agent *codersdk.WorkspaceAgent, _ chan []codersdk.WorkspaceAgentStartupLog) error {
+					// The agent is still starting, but the script that
+					// blocks login is done.
+					agent.Scripts[0].Status = codersdk.WorkspaceAgentScriptStatusSuccess
+					return nil
+				},
+			},
+			want: []string{
+				"⧗ Running workspace agent startup script (non-blocking)",
+				"Installing dotfiles",
+				"Notice: The startup script is still running and your workspace may be incomplete.",
+				"For more information and troubleshooting, see",
+			},
+		},
 		{
 			name: "Disconnected",
 			opts: cliui.AgentOptions{
@@ -0,0 +1,97 @@
+package cliui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressRenderInterval limits how often the progress bar is redrawn.
+const progressRenderInterval = 100 * time.Millisecond
+
+// ProgressBar renders the progress of a transfer of a known size on a single
+// line. It implements io.Writer so that it can be used with io.TeeReader.
+type ProgressBar struct {
+	mu         sync.Mutex
+	writer     io.Writer
+	label      string
+	total      int64
+	current    int64
+	lastRender time.Time
+	done       bool
+}
+
+// NewProgressBar starts rendering a progress bar for a transfer of total
+// bytes. The bar starts at the given offset, which is useful when resuming
+// a transfer.
+func NewProgressBar(writer io.Writer, label string, offset, total int64) *ProgressBar {
+	p := &ProgressBar{
+		writer:  writer,
+		label:   label,
+		total:   total,
+		current: offset,
+	}
+	p.render()
+	return p
+}
+
+// Write advances the progress bar by the length of b.
+func (p *ProgressBar) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += int64(len(b))
+	if time.Since(p.lastRender) >= progressRenderInterval {
+		p.render()
+	}
+	return len(b), nil
+}
+
+// Done renders the final state of the progress bar and ends the line.
+func (p *ProgressBar) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done {
+		return
+	}
+	p.done = true
+	p.render()
+	_, _ = fmt.Fprintln(p.writer)
+}
+
+// render draws the progress bar. The caller must hold mu.
+func (p *ProgressBar) render() {
+	p.lastRender = time.Now()
+
+	const width = 30
+	ratio := 1.0
+	if p.total > 0 {
+		ratio = float64(p.current) / float64(p.total)
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * width)
+	bar := strings.Repeat("=", filled)
+	if filled < width {
+		bar += ">" + strings.Repeat(" ", width-filled-1)
+	}
+	_, _ = fmt.Fprintf(p.writer, "\r%s [%s] %3.0f%% %s / %s",
+		p.label, bar, ratio*100, FormatBytes(p.current), FormatBytes(p.total))
+}
+
+// FormatBytes returns a human-readable representation of a number of bytes
+// using binary prefixes, e.g. "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,46 @@
+package cliui_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/cliui"
+)
+
+func TestProgressBar(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	bar := cliui.NewProgressBar(&buf, "file", 512, 2048)
+	require.Contains(t, buf.String(), " 25% 512 B / 2.0 KiB")
+
+	_, err := bar.Write(make([]byte, 1536))
+	require.NoError(t, err)
+	bar.Done()
+	bar.Done()
+
+	lines := strings.Split(buf.String(), "\r")
+	last := lines[len(lines)-1]
+	require.Equal(t, "file ["+strings.Repeat("=", 30)+"] 100% 2.0 KiB / 2.0 KiB\n", last)
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		bytes    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	} {
+		require.Equal(t, tc.expected, cliui.FormatBytes(tc.bytes))
+	}
+}
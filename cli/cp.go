@@ -0,0 +1,491 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) cp() *clibase.Cmd {
+	var (
+		recursive bool
+		resume    bool
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "cp <source>... <destination>",
+		Short:       "Copy files to and from a workspace",
+		Long: "Workspace paths are written as <workspace>[.<agent>]:<path>. Relative workspace paths are " +
+			"resolved against the home directory of the workspace user. Sources may contain glob patterns, " +
+			"which must be quoted when they refer to workspace paths. File modes and modification times are " +
+			"preserved, and symbolic links within copied directories are copied as links.\n\n" +
+			formatExamples(
+				example{
+					Description: "Copy a file into the home directory of a workspace",
+					Command:     "coder cp ./main.go my-workspace:",
+				},
+				example{
+					Description: "Copy a directory out of a workspace",
+					Command:     "coder cp --recursive my-workspace.main:project/dist ./dist",
+				},
+				example{
+					Description: "Copy all log files out of a workspace, resuming partial downloads",
+					Command:     "coder cp --resume 'my-workspace:/var/log/*.log' ./logs",
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(2, -1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			sources := make([]copyLocation, 0, len(inv.Args)-1)
+			for _, arg := range inv.Args[:len(inv.Args)-1] {
+				sources = append(sources, parseCopyLocation(arg))
+			}
+			destination := parseCopyLocation(inv.Args[len(inv.Args)-1])
+
+			// Exactly one side of the copy must be a workspace.
+			workspaceName := destination.workspace
+			for _, source := range sources {
+				if source.remote() == destination.remote() {
+					if destination.remote() {
+						return xerrors.New("copying between workspaces is not supported")
+					}
+					return xerrors.New("either the sources or the destination must be a workspace path")
+				}
+				if source.remote() {
+					if workspaceName != "" && workspaceName != source.workspace {
+						return xerrors.New("all sources must be in the same workspace")
+					}
+					workspaceName = source.workspace
+				}
+			}
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, workspaceName)
+			if err != nil {
+				return err
+			}
+
+			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+				Fetch: client.WorkspaceAgent,
+			})
+			if err != nil {
+				if xerrors.Is(err, context.Canceled) {
+					return cliui.Canceled
+				}
+				return xerrors.Errorf("await agent: %w", err)
+			}
+
+			var logger slog.Logger
+			if r.verbose {
+				logger = slog.Make(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				Logger:         logger,
+				BlockEndpoints: r.disableDirect,
+			})
+			if err != nil {
+				return xerrors.Errorf("dial agent: %w", err)
+			}
+			defer conn.Close()
+			conn.AwaitReachable(ctx)
+
+			sshClient, err := conn.SSHClient(ctx)
+			if err != nil {
+				return xerrors.Errorf("ssh client: %w", err)
+			}
+			defer sshClient.Close()
+
+			sftpClient, err := sftp.NewClient(sshClient)
+			if err != nil {
+				return xerrors.Errorf("sftp client: %w", err)
+			}
+			defer sftpClient.Close()
+
+			c := &copier{
+				recursive: recursive,
+				resume:    resume,
+			}
+			if destination.remote() {
+				c.src, c.dst = localFS{}, remoteFS{client: sftpClient}
+			} else {
+				c.src, c.dst = remoteFS{client: sftpClient}, localFS{}
+			}
+			if isTTYErr(inv) {
+				c.progress = inv.Stderr
+			}
+
+			var paths []string
+			for _, source := range sources {
+				matches, err := expandCopySource(c.src, source.path)
+				if err != nil {
+					return err
+				}
+				paths = append(paths, matches...)
+			}
+
+			dstIsDir := false
+			dstInfo, err := c.dst.Stat(destination.path)
+			if err == nil {
+				dstIsDir = dstInfo.IsDir()
+			}
+			if len(paths) > 1 && !dstIsDir {
+				return xerrors.Errorf("destination %q is not a directory", destination.path)
+			}
+
+			for _, srcPath := range paths {
+				dstPath := destination.path
+				if dstIsDir {
+					dstPath = c.dst.Join(dstPath, c.src.Base(srcPath))
+				}
+				err = c.copy(srcPath, dstPath)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "recursive",
+			FlagShorthand: "r",
+			Env:           "CODER_CP_RECURSIVE",
+			Description:   "Copy directories recursively.",
+			Value:         clibase.BoolOf(&recursive),
+		},
+		{
+			Flag:        "resume",
+			Env:         "CODER_CP_RESUME",
+			Description: "Resume partial transfers by appending to destination files that are smaller than their source, and skip files that are already complete. Files that were modified since they were last copied are copied again.",
+			Value:       clibase.BoolOf(&resume),
+		},
+	}
+	return cmd
+}
+
+// copyLocation is a parsed argument of the cp command.
+type copyLocation struct {
+	// workspace is empty for local paths.
+	workspace string
+	path      string
+}
+
+func (l copyLocation) remote() bool {
+	return l.workspace != ""
+}
+
+// parseCopyLocation parses a local path or a workspace path in the format
+// <workspace>[.<agent>]:<path>. Arguments where the part before the colon
+// contains a path separator are local paths, as are Windows drive letters.
+func parseCopyLocation(arg string) copyLocation {
+	workspace, p, ok := strings.Cut(arg, ":")
+	if !ok || workspace == "" || strings.ContainsAny(workspace, `/\`) ||
+		(runtime.GOOS == "windows" && len(workspace) == 1) {
+		return copyLocation{path: arg}
+	}
+	// Relative paths are resolved against the home directory by the
+	// SFTP server, so "~" can be dropped.
+	if p == "~" {
+		p = ""
+	}
+	p = strings.TrimPrefix(p, "~/")
+	if p == "" {
+		p = "."
+	}
+	return copyLocation{workspace: workspace, path: p}
+}
+
+// expandCopySource expands a source path that contains glob patterns. An
+// error is returned if a pattern matches nothing.
+func expandCopySource(fsys copyFS, pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+	matches, err := fsys.Glob(pattern)
+	if err != nil {
+		return nil, xerrors.Errorf("expand %q: %w", pattern, err)
+	}
+	if len(matches) == 0 {
+		return nil, xerrors.Errorf("no files match %q", pattern)
+	}
+	return matches, nil
+}
+
+// copyFile is implemented by both *os.File and *sftp.File.
+type copyFile interface {
+	io.ReadWriteSeeker
+	io.Closer
+}
+
+// copyFS abstracts the local and remote file systems so that copies can be
+// performed in either direction.
+type copyFS interface {
+	Stat(name string) (fs.FileInfo, error)
+	Open(name string) (copyFile, error)
+	OpenFile(name string, flag int) (copyFile, error)
+	ReadDir(name string) ([]fs.FileInfo, error)
+	MkdirAll(name string) error
+	Chmod(name string, mode fs.FileMode) error
+	Chtimes(name string, atime, mtime time.Time) error
+	Readlink(name string) (string, error)
+	Symlink(target, name string) error
+	Remove(name string) error
+	Glob(pattern string) ([]string, error)
+	Join(elem ...string) string
+	Base(name string) string
+}
+
+type localFS struct{}
+
+func (localFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+func (localFS) Open(name string) (copyFile, error)    { return os.Open(name) }
+func (localFS) OpenFile(name string, flag int) (copyFile, error) {
+	return os.OpenFile(name, flag, 0o600)
+}
+func (localFS) MkdirAll(name string) error                { return os.MkdirAll(name, 0o700) }
+func (localFS) Chmod(name string, mode fs.FileMode) error { return os.Chmod(name, mode) }
+func (localFS) Readlink(name string) (string, error)      { return os.Readlink(name) }
+func (localFS) Symlink(target, name string) error         { return os.Symlink(target, name) }
+func (localFS) Remove(name string) error                  { return os.Remove(name) }
+func (localFS) Glob(pattern string) ([]string, error)     { return filepath.Glob(pattern) }
+func (localFS) Join(elem ...string) string                { return filepath.Join(elem...) }
+func (localFS) Base(name string) string                   { return filepath.Base(name) }
+
+func (localFS) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
+
+func (localFS) ReadDir(name string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+type remoteFS struct {
+	client *sftp.Client
+}
+
+func (f remoteFS) Stat(name string) (fs.FileInfo, error)      { return f.client.Stat(name) }
+func (f remoteFS) Open(name string) (copyFile, error)         { return f.client.Open(name) }
+func (f remoteFS) ReadDir(name string) ([]fs.FileInfo, error) { return f.client.ReadDir(name) }
+func (f remoteFS) MkdirAll(name string) error                 { return f.client.MkdirAll(name) }
+func (f remoteFS) Readlink(name string) (string, error)       { return f.client.ReadLink(name) }
+func (f remoteFS) Symlink(target, name string) error          { return f.client.Symlink(target, name) }
+func (f remoteFS) Remove(name string) error                   { return f.client.Remove(name) }
+func (f remoteFS) Glob(pattern string) ([]string, error)      { return f.client.Glob(pattern) }
+func (f remoteFS) Join(elem ...string) string                 { return f.client.Join(elem...) }
+func (remoteFS) Base(name string) string                      { return path.Base(name) }
+
+func (f remoteFS) OpenFile(name string, flag int) (copyFile, error) {
+	return f.client.OpenFile(name, flag)
+}
+
+func (f remoteFS) Chmod(name string, mode fs.FileMode) error {
+	return f.client.Chmod(name, mode)
+}
+
+func (f remoteFS) Chtimes(name string, atime, mtime time.Time) error {
+	return f.client.Chtimes(name, atime, mtime)
+}
+
+type copier struct {
+	recursive bool
+	resume    bool
+	src       copyFS
+	dst       copyFS
+	// progress is where progress bars are rendered. It is nil if progress
+	// should not be shown.
+	progress io.Writer
+}
+
+// copy copies a file or directory from srcPath to dstPath. Symbolic links
+// are followed for srcPath itself, but not within directories.
+func (c *copier) copy(srcPath, dstPath string) error {
+	info, err := c.src.Stat(srcPath)
+	if err != nil {
+		return xerrors.Errorf("stat %q: %w", srcPath, err)
+	}
+	return c.copyEntry(srcPath, dstPath, info)
+}
+
+// copyEntry copies a file, directory or symbolic link described by info.
+func (c *copier) copyEntry(srcPath, dstPath string, info fs.FileInfo) error {
+	if info.Mode()&fs.ModeSymlink != 0 {
+		return c.copySymlink(srcPath, dstPath)
+	}
+	if !info.IsDir() {
+		return c.copyFile(srcPath, dstPath, info)
+	}
+	if !c.recursive {
+		return xerrors.Errorf("%q is a directory, use --recursive to copy it", srcPath)
+	}
+
+	err := c.dst.MkdirAll(dstPath)
+	if err != nil {
+		return xerrors.Errorf("create directory %q: %w", dstPath, err)
+	}
+	entries, err := c.src.ReadDir(srcPath)
+	if err != nil {
+		return xerrors.Errorf("read directory %q: %w", srcPath, err)
+	}
+	for _, entry := range entries {
+		// Entries are not followed if they are symbolic links, so a link
+		// to a parent directory can't make the copy loop forever.
+		err = c.copyEntry(c.src.Join(srcPath, entry.Name()), c.dst.Join(dstPath, entry.Name()), entry)
+		if err != nil {
+			return err
+		}
+	}
+	// The mode is set last so that read-only directories can be populated.
+	err = c.dst.Chmod(dstPath, info.Mode().Perm())
+	if err != nil {
+		return xerrors.Errorf("chmod %q: %w", dstPath, err)
+	}
+	return nil
+}
+
+// copySymlink recreates the symbolic link at srcPath with the same target.
+func (c *copier) copySymlink(srcPath, dstPath string) error {
+	target, err := c.src.Readlink(srcPath)
+	if err != nil {
+		return xerrors.Errorf("read link %q: %w", srcPath, err)
+	}
+	existing, err := c.dst.Readlink(dstPath)
+	if err == nil {
+		if existing == target {
+			return nil
+		}
+		err = c.dst.Remove(dstPath)
+		if err != nil {
+			return xerrors.Errorf("remove %q: %w", dstPath, err)
+		}
+	}
+	err = c.dst.Symlink(target, dstPath)
+	if err != nil {
+		return xerrors.Errorf("create link %q: %w", dstPath, err)
+	}
+	return nil
+}
+
+func (c *copier) copyFile(srcPath, dstPath string, info fs.FileInfo) error {
+	if !info.Mode().IsRegular() {
+		return xerrors.Errorf("%q is not a regular file", srcPath)
+	}
+
+	var offset int64
+	if c.resume {
+		dstInfo, err := c.dst.Stat(dstPath)
+		if err == nil && dstInfo.Mode().IsRegular() {
+			// SFTP only has second precision.
+			srcTime, dstTime := info.ModTime().Unix(), dstInfo.ModTime().Unix()
+			switch {
+			case dstInfo.Size() == info.Size() && dstTime == srcTime:
+				// Complete files have the modification time of their
+				// source.
+				offset = dstInfo.Size()
+			case dstInfo.Size() < info.Size() && srcTime <= dstTime:
+				// The source wasn't modified after the partial file was
+				// last written to.
+				offset = dstInfo.Size()
+			}
+		}
+	}
+
+	// A complete file is skipped when resuming, but its mode is still set.
+	if offset == 0 || offset < info.Size() {
+		err := c.transfer(srcPath, dstPath, offset, info.Size())
+		if err != nil {
+			return err
+		}
+	}
+
+	err := c.dst.Chmod(dstPath, info.Mode().Perm())
+	if err != nil {
+		return xerrors.Errorf("chmod %q: %w", dstPath, err)
+	}
+	// The modification time is how resumed copies tell if the source
+	// changed.
+	err = c.dst.Chtimes(dstPath, info.ModTime(), info.ModTime())
+	if err != nil {
+		return xerrors.Errorf("chtimes %q: %w", dstPath, err)
+	}
+	return nil
+}
+
+// transfer copies the contents of srcPath from offset onwards into dstPath.
+// If offset is zero the destination is truncated first.
+func (c *copier) transfer(srcPath, dstPath string, offset, size int64) error {
+	src, err := c.src.Open(srcPath)
+	if err != nil {
+		return xerrors.Errorf("open %q: %w", srcPath, err)
+	}
+	defer src.Close()
+
+	flag := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flag |= os.O_TRUNC
+	}
+	dst, err := c.dst.OpenFile(dstPath, flag)
+	if err != nil {
+		return xerrors.Errorf("open %q: %w", dstPath, err)
+	}
+	defer dst.Close()
+
+	if offset > 0 {
+		_, err = src.Seek(offset, io.SeekStart)
+		if err != nil {
+			return xerrors.Errorf("seek %q: %w", srcPath, err)
+		}
+		_, err = dst.Seek(offset, io.SeekStart)
+		if err != nil {
+			return xerrors.Errorf("seek %q: %w", dstPath, err)
+		}
+	}
+
+	var reader io.Reader = src
+	if c.progress != nil {
+		bar := cliui.NewProgressBar(c.progress, c.src.Base(srcPath), offset, size)
+		defer bar.Done()
+		reader = io.TeeReader(src, bar)
+	}
+	_, err = io.Copy(dst, reader)
+	if err != nil {
+		return xerrors.Errorf("copy %q to %q: %w", srcPath, dstPath, err)
+	}
+	err = dst.Close()
+	if err != nil {
+		return xerrors.Errorf("close %q: %w", dstPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCp(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not preserved on Windows")
+	}
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Workspace) {
+		t.Helper()
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		t.Cleanup(func() {
+			_ = agentCloser.Close()
+		})
+		return client, workspace
+	}
+
+	run := func(t *testing.T, client *codersdk.Client, args ...string) error {
+		t.Helper()
+		inv, root := clitest.New(t, append([]string{"cp"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		return inv.WithContext(ctx).Run()
+	}
+
+	// The agent runs on the same machine as the test, so workspace paths
+	// are checked on the local file system.
+	t.Run("Upload", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+		src := filepath.Join(t.TempDir(), "script.sh")
+		require.NoError(t, os.WriteFile(src, []byte("#!/bin/sh\n"), 0o750))
+		dst := filepath.Join(t.TempDir(), "uploaded.sh")
+
+		err := run(t, client, src, workspace.Name+":"+dst)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "#!/bin/sh\n", string(data))
+		info, err := os.Stat(dst)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+	})
+
+	t.Run("DownloadGlob", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+		srcDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "a.log"), []byte("a"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "b.log"), []byte("b"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "c.txt"), []byte("c"), 0o600))
+		dstDir := t.TempDir()
+
+		err := run(t, client, workspace.Name+":"+filepath.Join(srcDir, "*.log"), dstDir)
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(dstDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		data, err := os.ReadFile(filepath.Join(dstDir, "b.log"))
+		require.NoError(t, err)
+		require.Equal(t, "b", string(data))
+		info, err := os.Stat(filepath.Join(dstDir, "b.log"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+
+		err = run(t, client, workspace.Name+":"+filepath.Join(srcDir, "*.md"), dstDir)
+		require.ErrorContains(t, err, "no files match")
+	})
+
+	t.Run("Recursive", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+		srcDir := filepath.Join(t.TempDir(), "project")
+		require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "nested"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "nested", "file"), []byte("hello"), 0o600))
+		dstDir := t.TempDir()
+
+		err := run(t, client, srcDir, workspace.Name+":"+dstDir)
+		require.ErrorContains(t, err, "use --recursive")
+
+		err = run(t, client, "--recursive", srcDir, workspace.Name+":"+dstDir)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dstDir, "project", "nested", "file"))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+		src := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(src, []byte("hello world"), 0o600))
+		// The partial destination has different contents so that we can
+		// tell it was appended to rather than overwritten.
+		dst := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(dst, []byte("HELLO"), 0o600))
+
+		err := run(t, client, "--resume", workspace.Name+":"+src, dst)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "HELLO world", string(data))
+
+		// Without --resume the file is copied again.
+		err = run(t, client, workspace.Name+":"+src, dst)
+		require.NoError(t, err)
+		data, err = os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(data))
+	})
+
+	t.Run("RecursiveSymlinks", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+		srcDir := filepath.Join(t.TempDir(), "project")
+		require.NoError(t, os.MkdirAll(srcDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "file"), []byte("hello"), 0o600))
+		// Following this link would copy the directory into itself forever.
+		require.NoError(t, os.Symlink("..", filepath.Join(srcDir, "parent")))
+		require.NoError(t, os.Symlink("file", filepath.Join(srcDir, "link")))
+		dstDir := t.TempDir()
+
+		err := run(t, client, "--recursive", workspace.Name+":"+srcDir, dstDir)
+		require.NoError(t, err)
+
+		target, err := os.Readlink(filepath.Join(dstDir, "project", "parent"))
+		require.NoError(t, err)
+		require.Equal(t, "..", target)
+		target, err = os.Readlink(filepath.Join(dstDir, "project", "link"))
+		require.NoError(t, err)
+		require.Equal(t, "file", target)
+
+		// Copying again replaces links that changed.
+		require.NoError(t, os.Remove(filepath.Join(srcDir, "link")))
+		require.NoError(t, os.Symlink("parent", filepath.Join(srcDir, "link")))
+		err = run(t, client, "--recursive", workspace.Name+":"+srcDir, dstDir)
+		require.NoError(t, err)
+		target, err = os.Readlink(filepath.Join(dstDir, "project", "link"))
+		require.NoError(t, err)
+		require.Equal(t, "parent", target)
+	})
+
+	t.Run("ResumeModified", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+		src := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(src, []byte("hello world"), 0o600))
+		dst := filepath.Join(t.TempDir(), "file")
+
+		err := run(t, client, workspace.Name+":"+src, dst)
+		require.NoError(t, err)
+		srcInfo, err := os.Stat(src)
+		require.NoError(t, err)
+		dstInfo, err := os.Stat(dst)
+		require.NoError(t, err)
+		require.Equal(t, srcInfo.ModTime().Unix(), dstInfo.ModTime().Unix())
+
+		// A source with the same size that was modified later is copied
+		// again.
+		require.NoError(t, os.WriteFile(src, []byte("HELLO WORLD"), 0o600))
+		require.NoError(t, os.Chtimes(src, time.Now().Add(time.Hour), time.Now().Add(time.Hour)))
+		err = run(t, client, "--resume", workspace.Name+":"+src, dst)
+		require.NoError(t, err)
+		data, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "HELLO WORLD", string(data))
+
+		// A partial file is not appended to if the source was modified
+		// after it was written.
+		require.NoError(t, os.WriteFile(dst, []byte("hello"), 0o600))
+		require.NoError(t, os.Chtimes(dst, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+		err = run(t, client, "--resume", workspace.Name+":"+src, dst)
+		require.NoError(t, err)
+		data, err = os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "HELLO WORLD", string(data))
+	})
+
+	t.Run("BothLocal", func(t *testing.T) {
+		t.Parallel()
+		client, _ := setup(t)
+		err := run(t, client, "./a", "./b")
+		require.ErrorContains(t, err, "must be a workspace path")
+	})
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+
+	"github.com/coder/coder/agent/agentssh"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+// execTimeoutExitCode is returned when a command exceeds its timeout. It
+// matches the exit code of timeout(1).
+const execTimeoutExitCode = 124
+
+func (r *RootCmd) exec() *clibase.Cmd {
+	var (
+		env     []string
+		workdir string
+		timeout time.Duration
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "exec <workspace>[.<agent>] -- <command> [args...]",
+		Short:       "Run a command in a workspace without a terminal",
+		Long: "Standard input is forwarded to the command, and its standard output and error are " +
+			"streamed separately. The exit code of the command is used as the exit code of this command.\n\n" +
+			formatExamples(
+				example{
+					Description: "Run a command in a workspace",
+					Command:     "coder exec my-workspace -- make test",
+				},
+				example{
+					Description: "Run a command in a specific agent and directory with extra environment variables",
+					Command:     "coder exec my-workspace.main --workdir project --env CI=true -- go test ./...",
+				},
+				example{
+					Description: "Forward standard input to the command",
+					Command:     "tar -cz . | coder exec my-workspace -- tar -xz -C /tmp/upload",
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(2, -1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			for _, kv := range env {
+				if !strings.Contains(kv, "=") {
+					return xerrors.Errorf("invalid environment variable %q, must be in the format KEY=VALUE", kv)
+				}
+			}
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+				Fetch: client.WorkspaceAgent,
+			})
+			if err != nil {
+				if xerrors.Is(err, context.Canceled) {
+					return cliui.Canceled
+				}
+				return xerrors.Errorf("await agent: %w", err)
+			}
+
+			var logger slog.Logger
+			if r.verbose {
+				logger = slog.Make(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				Logger:         logger,
+				BlockEndpoints: r.disableDirect,
+			})
+			if err != nil {
+				return xerrors.Errorf("dial agent: %w", err)
+			}
+			defer conn.Close()
+			conn.AwaitReachable(ctx)
+
+			sshClient, err := conn.SSHClient(ctx)
+			if err != nil {
+				return xerrors.Errorf("ssh client: %w", err)
+			}
+			defer sshClient.Close()
+
+			sshSession, err := sshClient.NewSession()
+			if err != nil {
+				return xerrors.Errorf("ssh session: %w", err)
+			}
+			defer sshSession.Close()
+
+			for _, kv := range env {
+				key, value, _ := strings.Cut(kv, "=")
+				err = sshSession.Setenv(key, value)
+				if err != nil {
+					return xerrors.Errorf("set environment variable %q: %w", key, err)
+				}
+			}
+			if workdir != "" {
+				err = sshSession.Setenv(agentssh.MagicWorkingDirectoryEnvironmentVariable, workdir)
+				if err != nil {
+					return xerrors.Errorf("set working directory: %w", err)
+				}
+			}
+
+			// The session waits for stdin to be closed if it is assigned
+			// directly, so copy it ourselves to avoid blocking on commands
+			// that do not read it.
+			stdin, err := sshSession.StdinPipe()
+			if err != nil {
+				return xerrors.Errorf("stdin pipe: %w", err)
+			}
+			go func() {
+				_, _ = io.Copy(stdin, inv.Stdin)
+				_ = stdin.Close()
+			}()
+			sshSession.Stdout = inv.Stdout
+			sshSession.Stderr = inv.Stderr
+
+			// The arguments are joined with spaces and run by the user's
+			// shell, just like OpenSSH does.
+			err = sshSession.Start(strings.Join(inv.Args[1:], " "))
+			if err != nil {
+				return xerrors.Errorf("start command: %w", err)
+			}
+
+			var timeoutC <-chan time.Time
+			if timeout > 0 {
+				t := time.NewTimer(timeout)
+				defer t.Stop()
+				timeoutC = t.C
+			}
+			done := make(chan error, 1)
+			go func() {
+				done <- sshSession.Wait()
+			}()
+			select {
+			case err = <-done:
+			case <-timeoutC:
+				_ = sshSession.Close()
+				return &ExitError{
+					Code: execTimeoutExitCode,
+					Err:  xerrors.Errorf("command timed out after %s", timeout),
+				}
+			case <-ctx.Done():
+				_ = sshSession.Close()
+				return ctx.Err()
+			}
+
+			var exitErr *gossh.ExitError
+			if errors.As(err, &exitErr) {
+				return &ExitError{Code: exitErr.ExitStatus()}
+			}
+			if err != nil {
+				// If the connection drops unexpectedly, we get an
+				// ExitMissingError but no other error details, so try to at
+				// least give the user a better message
+				if errors.Is(err, &gossh.ExitMissingError{}) {
+					return xerrors.New("SSH connection ended unexpectedly")
+				}
+				return xerrors.Errorf("command failed: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "env",
+			FlagShorthand: "e",
+			Env:           "CODER_EXEC_ENV",
+			Description:   "Set environment variables for the command in the format KEY=VALUE.",
+			Value:         clibase.StringArrayOf(&env),
+		},
+		{
+			Flag:          "workdir",
+			FlagShorthand: "w",
+			Env:           "CODER_EXEC_WORKDIR",
+			Description:   "The directory to run the command in. Relative paths are resolved against the agent's default directory.",
+			Value:         clibase.StringOf(&workdir),
+		},
+		{
+			Flag:        "timeout",
+			Env:         "CODER_EXEC_TIMEOUT",
+			Description: fmt.Sprintf("Kill the command if it does not complete within this duration, and exit with code %d. Zero disables the timeout.", execTimeoutExitCode),
+			Default:     "0s",
+			Value:       clibase.DurationOf(&timeout),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,119 @@
+package cli_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("commands use sh syntax")
+	}
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Workspace) {
+		t.Helper()
+		client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		t.Cleanup(func() {
+			_ = agentCloser.Close()
+		})
+		return client, workspace
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+		dir := t.TempDir()
+
+		inv, root := clitest.New(t, "exec", workspace.Name,
+			"--env", "FOO=bar", "--workdir", dir, "--",
+			"echo", "$FOO", "&&", "pwd", "&&", "cat", "&&", "echo", "oops", ">&2",
+		)
+		clitest.SetupConfig(t, client, root)
+		var stdout, stderr bytes.Buffer
+		inv.Stdin = strings.NewReader("input\n")
+		inv.Stdout = &stdout
+		inv.Stderr = &stderr
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		realDir, err := filepath.EvalSymlinks(dir)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		require.Len(t, lines, 3)
+		require.Equal(t, "bar", lines[0])
+		require.Contains(t, []string{dir, realDir}, lines[1])
+		require.Equal(t, "input", lines[2])
+		require.Contains(t, stderr.String(), "oops")
+		require.NotContains(t, stdout.String(), "oops")
+	})
+
+	t.Run("ExitCode", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		inv, root := clitest.New(t, "exec", workspace.Name, "--", "exit", "3")
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		var exitErr *cli.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		require.Equal(t, 3, exitErr.Code)
+		require.NoError(t, exitErr.Err)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		inv, root := clitest.New(t, "exec", workspace.Name, "--timeout", "1s", "--", "sleep", "30")
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		var exitErr *cli.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		require.Equal(t, 124, exitErr.Code)
+		require.ErrorContains(t, exitErr, "timed out")
+	})
+
+	t.Run("InvalidWorkdir", func(t *testing.T) {
+		t.Parallel()
+		client, workspace := setup(t)
+
+		inv, root := clitest.New(t, "exec", workspace.Name, "--workdir", filepath.Join(t.TempDir(), "missing"), "--", "true")
+		clitest.SetupConfig(t, client, root)
+		var stderr bytes.Buffer
+		inv.Stderr = &stderr
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		var exitErr *cli.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		require.NotEqual(t, 0, exitErr.Code)
+		require.Contains(t, stderr.String(), "working directory")
+	})
+}
@@ -193,10 +193,7 @@ func (r *RootCmd) login() *clibase.Cmd {
 				if err != nil {
 					return xerrors.Errorf("create initial user: %w", err)
 				}
-				resp, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
-					Email:    email,
-					Password: password,
-				})
+				resp, err := loginWithPassword(inv, client, email, password)
 				if err != nil {
 					return xerrors.Errorf("login with password: %w", err)
 				}
@@ -320,6 +317,60 @@ func (r *RootCmd) login() *clibase.Cmd {
 	return cmd
 }
 
+// loginWithPassword logs in with an email and password, and prompts for a
+// multi-factor authentication code if the user needs one. Users that must
+// enroll an authenticator app first are given a secret to add to it.
+func loginWithPassword(inv *clibase.Invocation, client *codersdk.Client, email, password string) (codersdk.LoginWithPasswordResponse, error) {
+	ctx := inv.Context()
+	req := codersdk.LoginWithPasswordRequest{
+		Email:    email,
+		Password: password,
+	}
+	resp, err := client.LoginWithPassword(ctx, req)
+	var promptText string
+	switch {
+	case codersdk.IsTOTPEnrollmentRequired(err):
+		enrollment, err := client.EnrollTOTPWithPassword(ctx, codersdk.EnrollTOTPWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		if err != nil {
+			return codersdk.LoginWithPasswordResponse{}, xerrors.Errorf("enroll authenticator app: %w", err)
+		}
+		_, _ = fmt.Fprintf(inv.Stdout, "%s\n\n\tSecret: %s\n\tURL: %s\n\n",
+			cliui.DefaultStyles.Paragraph.Render("Your account requires multi-factor authentication. Add this secret to your authenticator app, or import the URL:"),
+			enrollment.Secret, enrollment.URL)
+		promptText = "Code from your authenticator app:"
+	case codersdk.IsTOTPCodeRequired(err):
+		promptText = "Code from your authenticator app or a recovery code:"
+	default:
+		return resp, err
+	}
+
+	req.TOTPCode, err = cliui.Prompt(inv, cliui.PromptOptions{
+		Text: promptText,
+		Validate: func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return xerrors.New("Enter a code.")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return codersdk.LoginWithPasswordResponse{}, xerrors.Errorf("code prompt: %w", err)
+	}
+	resp, err = client.LoginWithPassword(ctx, req)
+	if err != nil {
+		return codersdk.LoginWithPasswordResponse{}, err
+	}
+	if len(resp.RecoveryCodes) > 0 {
+		_, _ = fmt.Fprintf(inv.Stdout, "%s\n\n\t%s\n\n",
+			cliui.DefaultStyles.Paragraph.Render("Save these recovery codes somewhere safe. Each one can be used once to log in if you lose your authenticator app:"),
+			strings.Join(resp.RecoveryCodes, "\n\t"))
+	}
+	return resp, nil
+}
+
 // isWSL determines if coder-cli is running within Windows Subsystem for Linux
 func isWSL() (bool, error) {
 	if runtime.GOOS == goosDarwin || runtime.GOOS == goosWindows {
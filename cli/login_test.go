@@ -3,7 +3,9 @@ package cli_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -11,7 +13,9 @@ import (
 	"github.com/coder/coder/cli/clitest"
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/totp"
 	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
 )
 
 func TestLogin(t *testing.T) {
@@ -109,6 +113,28 @@ func TestLogin(t *testing.T) {
 		<-doneChan
 	})
 
+	t.Run("InitialUserRequireAdminMFA", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.RequireAdminMFA = true
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		inv, _ := clitest.New(t, "login", client.URL.String(), "--first-user-username", "testuser", "--first-user-email", "user@coder.com", "--first-user-password", "SomeSecurePassword!", "--first-user-trial")
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Start(t, inv)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		pty.ExpectMatch("Secret: ")
+		secret := strings.TrimSpace(pty.ReadLine(ctx))
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		require.NoError(t, err)
+		pty.ExpectMatch("Code from your authenticator app")
+		pty.WriteLine(code)
+		pty.ExpectMatch("recovery codes")
+		pty.ExpectMatch("Welcome to Coder")
+	})
+
 	t.Run("InitialUserTTYConfirmPasswordFailAndReprompt", func(t *testing.T) {
 		t.Parallel()
 		ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) logs() *clibase.Cmd {
+	var (
+		follow      bool
+		buildNumber int64
+		agentName   string
+		since       time.Duration
+		formatter   = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+				line, ok := data.(logsLine)
+				if !ok {
+					return nil, xerrors.Errorf("expected type %T, got %T", line, data)
+				}
+				return line.text(), nil
+			}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "logs <workspace>",
+		Short:       "Show the build and agent startup logs of a workspace",
+		Long: "The provisioner logs of the build are shown together with the startup logs of its agents, " +
+			"ordered by time.\n\n" +
+			formatExamples(
+				example{
+					Description: "Show the logs of the latest build of a workspace",
+					Command:     "coder logs my-workspace",
+				},
+				example{
+					Description: "Follow the logs of a starting workspace until its agents are ready",
+					Command:     "coder logs --follow my-workspace",
+				},
+				example{
+					Description: "Show the startup logs of an agent from the last 10 minutes as JSON",
+					Command:     "coder logs my-workspace --agent main --since 10m --output json",
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			workspace, err := NamedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			build := workspace.LatestBuild
+			if buildNumber > 0 {
+				build, err = client.WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx, workspace.OwnerName, workspace.Name, strconv.FormatInt(buildNumber, 10))
+				if err != nil {
+					return xerrors.Errorf("get build %d: %w", buildNumber, err)
+				}
+			}
+
+			w := &logsWriter{
+				ctx:       ctx,
+				out:       inv.Stdout,
+				formatter: formatter,
+			}
+			if since > 0 {
+				w.since = time.Now().Add(-since)
+			}
+
+			// Without --follow all logs are collected up front so that
+			// they can be sorted into a single stream.
+			w.buffer = !follow
+
+			if follow {
+				// The stream is closed once the build job completes.
+				logs, closer, err := client.WorkspaceBuildLogsAfter(ctx, build.ID, 0)
+				if err != nil {
+					return xerrors.Errorf("follow build logs: %w", err)
+				}
+				for log := range logs {
+					w.writeBuildLog(build, log)
+				}
+				_ = closer.Close()
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// Agents are only known once the build has completed.
+				build, err = client.WorkspaceBuild(ctx, build.ID)
+				if err != nil {
+					return xerrors.Errorf("get build: %w", err)
+				}
+			} else {
+				logs, err := client.WorkspaceBuildLogs(ctx, build.ID, 0)
+				if err != nil {
+					return xerrors.Errorf("get build logs: %w", err)
+				}
+				for _, log := range logs {
+					w.writeBuildLog(build, log)
+				}
+			}
+
+			var agents []codersdk.WorkspaceAgent
+			for _, resource := range build.Resources {
+				for _, agent := range resource.Agents {
+					if agentName == "" || agent.Name == agentName {
+						agents = append(agents, agent)
+					}
+				}
+			}
+			if agentName != "" && len(agents) == 0 {
+				return xerrors.Errorf("agent %q not found in build #%d", agentName, build.BuildNumber)
+			}
+
+			var eg errgroup.Group
+			for _, agent := range agents {
+				agent := agent
+				eg.Go(func() error {
+					return streamAgentStartupLogs(ctx, client, agent, follow, func(log codersdk.WorkspaceAgentStartupLog) {
+						w.writeAgentLog(agent, log)
+					})
+				})
+			}
+			err = eg.Wait()
+			// Logs that were collected are shown even if an agent failed.
+			if flushErr := w.flush(); err == nil {
+				err = flushErr
+			}
+			return err
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "follow",
+			FlagShorthand: "f",
+			Env:           "CODER_LOGS_FOLLOW",
+			Description:   "Follow the logs until the build has completed and all agents have finished starting. Fails if an agent times out or disconnects before it has finished starting.",
+			Value:         clibase.BoolOf(&follow),
+		},
+		{
+			Flag:        "build",
+			Env:         "CODER_LOGS_BUILD",
+			Description: "Show the logs of the build with this number instead of the latest build.",
+			Value:       clibase.Int64Of(&buildNumber),
+		},
+		{
+			Flag:        "agent",
+			Env:         "CODER_LOGS_AGENT",
+			Description: "Only show the startup logs of the agent with this name.",
+			Value:       clibase.StringOf(&agentName),
+		},
+		{
+			Flag:        "since",
+			Env:         "CODER_LOGS_SINCE",
+			Description: "Only show logs written within this duration, e.g. 10m.",
+			Value:       clibase.DurationOf(&since),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// streamAgentStartupLogs passes the startup logs of an agent to emit. When
+// following, logs are streamed until the agent has finished starting. An
+// error is returned if the agent times out or disconnects before that.
+//
+//nolint:revive // Follow is a control flag.
+func streamAgentStartupLogs(ctx context.Context, client *codersdk.Client, agent codersdk.WorkspaceAgent, follow bool, emit func(codersdk.WorkspaceAgentStartupLog)) error {
+	var lastID int64
+	fetchRemaining := func() error {
+		logs, closer, err := client.WorkspaceAgentStartupLogsAfter(ctx, agent.ID, lastID, false)
+		if err != nil {
+			return xerrors.Errorf("get startup logs of agent %q: %w", agent.Name, err)
+		}
+		defer closer.Close()
+		for chunk := range logs {
+			for _, log := range chunk {
+				emit(log)
+			}
+		}
+		return nil
+	}
+	if !follow || !agent.LifecycleState.Starting() {
+		return fetchRemaining()
+	}
+	// An agent that isn't connected won't finish starting until it
+	// reconnects, which may never happen.
+	unreachable := func(agent codersdk.WorkspaceAgent) error {
+		err := fetchRemaining()
+		if err != nil {
+			return err
+		}
+		return xerrors.Errorf("agent %q is not connected (status %s), so its startup logs can't be followed", agent.Name, agent.Status)
+	}
+	switch agent.Status {
+	case codersdk.WorkspaceAgentTimeout, codersdk.WorkspaceAgentDisconnected:
+		return unreachable(agent)
+	}
+
+	logs, closer, err := client.WorkspaceAgentStartupLogsAfter(ctx, agent.ID, 0, true)
+	if err != nil {
+		return xerrors.Errorf("follow startup logs of agent %q: %w", agent.Name, err)
+	}
+	defer closer.Close()
+
+	// The log stream never ends, so the agent is polled to find out when it
+	// has finished starting.
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			latest, err := client.WorkspaceAgent(ctx, agent.ID)
+			if err != nil {
+				return xerrors.Errorf("get agent %q: %w", agent.Name, err)
+			}
+			switch latest.Status {
+			case codersdk.WorkspaceAgentTimeout, codersdk.WorkspaceAgentDisconnected:
+				_ = closer.Close()
+				return unreachable(latest)
+			}
+			if latest.LifecycleState.Starting() {
+				continue
+			}
+			_ = closer.Close()
+			// Fetch anything written between the last streamed log and
+			// the agent becoming ready.
+			return fetchRemaining()
+		case chunk, ok := <-logs:
+			if !ok {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return xerrors.Errorf("startup log stream of agent %q closed unexpectedly", agent.Name)
+			}
+			for _, log := range chunk {
+				emit(log)
+				lastID = log.ID
+			}
+		}
+	}
+}
+
+// logsLine is a single line of output of the logs command.
+type logsLine struct {
+	Time  time.Time         `json:"time"`
+	Level codersdk.LogLevel `json:"level"`
+	// Source is either "build" or "agent".
+	Source      string `json:"source"`
+	BuildNumber int32  `json:"build_number,omitempty"`
+	Stage       string `json:"stage,omitempty"`
+	Agent       string `json:"agent,omitempty"`
+	// Script is the name of the agent script that wrote the log, if any.
+	Script string `json:"script,omitempty"`
+	Output string `json:"output"`
+}
+
+// text formats a line for the text output format.
+func (line logsLine) text() string {
+	source := fmt.Sprintf("build #%d", line.BuildNumber)
+	if line.Source == "agent" {
+		source = line.Agent
+		if line.Script != "" {
+			source += "/" + line.Script
+		}
+	}
+	output := line.Output
+	if output == "" {
+		output = line.Stage
+	}
+	return fmt.Sprintf("%s %-5s [%s] %s", line.Time.Local().Format("2006-01-02 15:04:05.000"), line.Level, source, output)
+}
+
+// logsWriter formats build and agent logs. It is safe for concurrent use.
+type logsWriter struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	out       io.Writer
+	formatter *cliui.OutputFormatter
+	since     time.Time
+	// buffer holds lines back until flush is called, so that they can be
+	// sorted by time.
+	buffer bool
+	lines  []logsLine
+	// err is the first error that occurred while formatting a line.
+	err error
+}
+
+func (w *logsWriter) writeBuildLog(build codersdk.WorkspaceBuild, log codersdk.ProvisionerJobLog) {
+	w.write(logsLine{
+		Time:        log.CreatedAt,
+		Level:       log.Level,
+		Source:      "build",
+		BuildNumber: build.BuildNumber,
+		Stage:       log.Stage,
+		Output:      log.Output,
+	})
+}
+
+func (w *logsWriter) writeAgentLog(agent codersdk.WorkspaceAgent, log codersdk.WorkspaceAgentStartupLog) {
+	w.write(logsLine{
+		Time:   log.CreatedAt,
+		Level:  log.Level,
+		Source: "agent",
+		Agent:  agent.Name,
+		Script: log.Source,
+		Output: log.Output,
+	})
+}
+
+func (w *logsWriter) write(line logsLine) {
+	if line.Time.Before(w.since) {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.buffer {
+		w.lines = append(w.lines, line)
+		return
+	}
+	w.print(line)
+}
+
+// flush writes the buffered lines sorted by time, and returns the first
+// error that occurred while formatting lines.
+func (w *logsWriter) flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	sort.SliceStable(w.lines, func(i, j int) bool {
+		return w.lines[i].Time.Before(w.lines[j].Time)
+	})
+	for _, line := range w.lines {
+		w.print(line)
+	}
+	w.lines = nil
+	return w.err
+}
+
+// print writes a line. Each line is formatted on its own, so that followed
+// logs are shown as they arrive. The caller must hold mu.
+func (w *logsWriter) print(line logsLine) {
+	out, err := w.formatter.Format(w.ctx, line)
+	if err != nil {
+		if w.err == nil {
+			w.err = xerrors.Errorf("format log: %w", err)
+		}
+		return
+	}
+	_, _ = fmt.Fprintln(w.out, out)
+}
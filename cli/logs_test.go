@@ -0,0 +1,142 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/testutil"
+)
+
+func TestLogs(t *testing.T) {
+	t.Parallel()
+
+	withStartupScript := func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Name = "main"
+		agents[0].StartupScript = "echo hello from startup"
+		return agents
+	}
+	startAgent := func(t *testing.T, client *codersdk.Client, agentToken string) {
+		t.Helper()
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(agentToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent"),
+		})
+		t.Cleanup(func() {
+			_ = agentCloser.Close()
+		})
+	}
+
+	t.Run("Build", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, _ := setupWorkspaceForAgent(t, nil)
+
+		inv, root := clitest.New(t, "logs", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "[build #1] Setting up")
+	})
+
+	t.Run("FollowJSON", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentToken := setupWorkspaceForAgent(t, withStartupScript)
+
+		inv, root := clitest.New(t, "logs", workspace.Name, "--follow", "--agent", "main", "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		done := make(chan error, 1)
+		go func() {
+			done <- inv.WithContext(ctx).Run()
+		}()
+		// The agent starts after the command so that its logs have to
+		// be followed.
+		startAgent(t, client, agentToken)
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for logs to complete")
+		case err := <-done:
+			require.NoError(t, err)
+		}
+
+		var sources []string
+		// Each log is written as a separate JSON object.
+		decoder := json.NewDecoder(&stdout)
+		for decoder.More() {
+			var entry struct {
+				Source string `json:"source"`
+				Agent  string `json:"agent"`
+				Output string `json:"output"`
+			}
+			require.NoError(t, decoder.Decode(&entry))
+			sources = append(sources, entry.Source)
+			if entry.Source == "agent" {
+				assert.Equal(t, "main", entry.Agent)
+				assert.Equal(t, "hello from startup", entry.Output)
+			}
+		}
+		require.Contains(t, sources, "build")
+		require.Equal(t, "agent", sources[len(sources)-1])
+	})
+
+	t.Run("FollowTimeout", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, _ := setupWorkspaceForAgent(t, func(agents []*proto.Agent) []*proto.Agent {
+			agents = withStartupScript(agents)
+			agents[0].ConnectionTimeoutSeconds = 1
+			return agents
+		})
+
+		// The agent never connects, so following stops once it times out.
+		inv, root := clitest.New(t, "logs", workspace.Name, "--follow")
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, `agent "main" is not connected`)
+	})
+
+	t.Run("UnknownAgent", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, _ := setupWorkspaceForAgent(t, withStartupScript)
+
+		inv, root := clitest.New(t, "logs", workspace.Name, "--agent", "missing")
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, `agent "missing" not found`)
+	})
+
+	t.Run("UnknownBuild", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, _ := setupWorkspaceForAgent(t, nil)
+
+		inv, root := clitest.New(t, "logs", workspace.Name, "--build", "5")
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "get build 5")
+	})
+}
@@ -45,6 +45,9 @@ func (*RootCmd) resetPassword() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("retrieving user: %w", err)
 			}
+			if user.IsServiceAccount {
+				return xerrors.Errorf("user %q is a service account and cannot have a password", user.Username)
+			}
 
 			password, err := cliui.Prompt(inv, cliui.PromptOptions{
 				Text:   "Enter new " + cliui.DefaultStyles.Field.Render("password") + ":",
@@ -26,7 +26,7 @@ func (r *RootCmd) restart() *clibase.Cmd {
 			ctx := inv.Context()
 			out := inv.Stdout
 
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			workspace, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,375 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) roles() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "roles",
+		Short: "Manage custom roles",
+		Long: "Custom roles grant permissions in addition to the built-in roles. They are assigned to users like the built-in roles.\n" + formatExamples(
+			example{
+				Description: "Create a role that can view all workspaces but not connect to them",
+				Command:     "coder roles create workspace-viewer --permission workspace:read --deny workspace_execution:*",
+			},
+			example{
+				Description: "Create a role that can manage the templates of the current organization",
+				Command:     "coder roles create template-manager --org --permission template:*",
+			},
+			example{
+				Description: "List the custom site wide roles",
+				Command:     "coder roles ls",
+			},
+		),
+		Aliases: []string{"role"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.roleCreate(),
+			r.roleList(),
+			r.roleEdit(),
+			r.roleDelete(),
+		},
+	}
+	return cmd
+}
+
+// roleOrgOption toggles between site wide roles and the roles of the
+// current organization.
+func roleOrgOption(org *bool) clibase.Option {
+	return clibase.Option{
+		Flag:        "org",
+		Description: "Manage the custom roles of the current organization instead of the site wide roles.",
+		Value:       clibase.BoolOf(org),
+	}
+}
+
+// rolePermissionOptions are the options to set the permissions of a role.
+func rolePermissionOptions(allow, deny *[]string) clibase.OptionSet {
+	return clibase.OptionSet{
+		{
+			Flag:        "permission",
+			Description: "Allow an action on a resource type, given as <resource>:<action>. Either may be * to match everything.",
+			Value:       clibase.StringArrayOf(allow),
+		},
+		{
+			Flag:        "deny",
+			Description: "Deny an action on a resource type, given as <resource>:<action>. Denied actions take precedence over allowed ones.",
+			Value:       clibase.StringArrayOf(deny),
+		},
+	}
+}
+
+// rolePermissions parses the permissions of the --permission and --deny
+// flags. The server validates the resource types and actions.
+func rolePermissions(allow, deny []string) ([]codersdk.Permission, error) {
+	perms := make([]codersdk.Permission, 0, len(allow)+len(deny))
+	parse := func(raw string, negate bool) error {
+		resource, action, ok := strings.Cut(raw, ":")
+		if !ok || resource == "" || action == "" {
+			return xerrors.Errorf("permission %q must be in the form <resource>:<action>", raw)
+		}
+		perms = append(perms, codersdk.Permission{
+			Negate:       negate,
+			ResourceType: codersdk.RBACResource(resource),
+			Action:       action,
+		})
+		return nil
+	}
+	for _, raw := range allow {
+		if err := parse(raw, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, raw := range deny {
+		if err := parse(raw, true); err != nil {
+			return nil, err
+		}
+	}
+	return perms, nil
+}
+
+// customRoles lists the site wide custom roles, or the custom roles of the
+// current organization.
+func customRoles(inv *clibase.Invocation, client *codersdk.Client, org bool) ([]codersdk.CustomRole, error) {
+	if !org {
+		return client.CustomSiteRoles(inv.Context())
+	}
+	organization, err := CurrentOrganization(inv, client)
+	if err != nil {
+		return nil, xerrors.Errorf("current organization: %w", err)
+	}
+	return client.CustomOrganizationRoles(inv.Context(), organization.ID)
+}
+
+func customRoleByName(inv *clibase.Invocation, client *codersdk.Client, org bool, name string) (codersdk.CustomRole, error) {
+	roles, err := customRoles(inv, client, org)
+	if err != nil {
+		return codersdk.CustomRole{}, xerrors.Errorf("list roles: %w", err)
+	}
+	for _, role := range roles {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+	if !org {
+		return codersdk.CustomRole{}, xerrors.Errorf("no site wide role named %q, use --org for organization roles", name)
+	}
+	return codersdk.CustomRole{}, xerrors.Errorf("no organization role named %q", name)
+}
+
+func (r *RootCmd) roleCreate() *clibase.Cmd {
+	var (
+		displayName string
+		org         bool
+		allow       []string
+		deny        []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a custom role",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			perms, err := rolePermissions(allow, deny)
+			if err != nil {
+				return err
+			}
+			req := codersdk.CreateCustomRoleRequest{
+				Name:        inv.Args[0],
+				DisplayName: displayName,
+				Permissions: perms,
+			}
+
+			var role codersdk.CustomRole
+			if org {
+				organization, err := CurrentOrganization(inv, client)
+				if err != nil {
+					return xerrors.Errorf("current organization: %w", err)
+				}
+				role, err = client.CreateCustomOrganizationRole(inv.Context(), organization.ID, req)
+				if err != nil {
+					return xerrors.Errorf("create role: %w", err)
+				}
+			} else {
+				role, err = client.CreateCustomSiteRole(inv.Context(), req)
+				if err != nil {
+					return xerrors.Errorf("create role: %w", err)
+				}
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully created role %s! Assign it as %s.\n",
+				cliui.DefaultStyles.Keyword.Render(role.Name),
+				cliui.DefaultStyles.Code.Render(role.AssignName),
+			)
+			return nil
+		},
+	}
+
+	cmd.Options = append(clibase.OptionSet{
+		{
+			Flag:        "display-name",
+			Description: "Set a human-readable name for the role. Defaults to the name.",
+			Value:       clibase.StringOf(&displayName),
+		},
+		roleOrgOption(&org),
+	}, rolePermissionOptions(&allow, &deny)...)
+	return cmd
+}
+
+// roleTableRow is the type provided to the OutputFormatter.
+type roleTableRow struct {
+	// For JSON format:
+	codersdk.CustomRole `table:"-"`
+
+	// For table format:
+	Name        string    `json:"-" table:"name,default_sort"`
+	DisplayName string    `json:"-" table:"display name"`
+	AssignName  string    `json:"-" table:"assign name"`
+	Permissions []string  `json:"-" table:"permissions"`
+	UpdatedAt   time.Time `json:"-" table:"updated at"`
+}
+
+func roleTableRowFromRole(role codersdk.CustomRole) roleTableRow {
+	perms := make([]string, 0, len(role.Permissions))
+	for _, perm := range role.Permissions {
+		entry := fmt.Sprintf("%s:%s", perm.ResourceType, perm.Action)
+		if perm.Negate {
+			entry = "deny " + entry
+		}
+		perms = append(perms, entry)
+	}
+	return roleTableRow{
+		CustomRole:  role,
+		Name:        role.Name,
+		DisplayName: role.DisplayName,
+		AssignName:  role.AssignName,
+		Permissions: perms,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func (r *RootCmd) roleList() *clibase.Cmd {
+	var (
+		org       bool
+		formatter = cliui.NewOutputFormatter(
+			cliui.TableFormat([]roleTableRow{}, []string{"name", "display name", "permissions", "updated at"}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List custom roles",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			roles, err := customRoles(inv, client, org)
+			if err != nil {
+				return xerrors.Errorf("list roles: %w", err)
+			}
+
+			if len(roles) == 0 {
+				cliui.Infof(
+					inv.Stderr,
+					"No custom roles found.\n",
+				)
+				return nil
+			}
+
+			rows := make([]roleTableRow, 0, len(roles))
+			for _, role := range roles {
+				rows = append(rows, roleTableRowFromRole(role))
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{roleOrgOption(&org)}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) roleEdit() *clibase.Cmd {
+	var (
+		displayName string
+		org         bool
+		allow       []string
+		deny        []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "edit <name>",
+		Short: "Edit a custom role",
+		Long:  "Passing any --permission or --deny flag replaces all permissions of the role. The new permissions apply to everyone the role is assigned to.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			role, err := customRoleByName(inv, client, org, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			req := codersdk.UpdateCustomRoleRequest{
+				DisplayName: role.DisplayName,
+				Permissions: role.Permissions,
+			}
+			if displayName != "" {
+				req.DisplayName = displayName
+			}
+			if len(allow) > 0 || len(deny) > 0 {
+				req.Permissions, err = rolePermissions(allow, deny)
+				if err != nil {
+					return err
+				}
+			}
+
+			role, err = client.UpdateCustomRole(inv.Context(), role.ID, req)
+			if err != nil {
+				return xerrors.Errorf("update role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully updated role %s!\n", cliui.DefaultStyles.Keyword.Render(role.Name))
+			return nil
+		},
+	}
+
+	cmd.Options = append(clibase.OptionSet{
+		{
+			Flag:        "display-name",
+			Description: "Set a human-readable name for the role.",
+			Value:       clibase.StringOf(&displayName),
+		},
+		roleOrgOption(&org),
+	}, rolePermissionOptions(&allow, &deny)...)
+	return cmd
+}
+
+func (r *RootCmd) roleDelete() *clibase.Cmd {
+	var org bool
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <name>",
+		Short: "Delete a custom role and unassign it from all users",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			role, err := customRoleByName(inv, client, org, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete role %s? It is unassigned from all users.", cliui.DefaultStyles.Code.Render(role.Name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteCustomRole(inv.Context(), role.ID)
+			if err != nil {
+				return xerrors.Errorf("delete role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Deleted role %s at %s!\n",
+				cliui.DefaultStyles.Keyword.Render(role.Name),
+				cliui.DefaultStyles.DateTimeStamp.Render(time.Now().Format(time.Stamp)),
+			)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		roleOrgOption(&org),
+		cliui.SkipPromptOption(),
+	}
+	return cmd
+}
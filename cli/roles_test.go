@@ -0,0 +1,100 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "roles", "create", "workspace-viewer",
+			"--permission", "workspace:read",
+			"--deny", "workspace_execution:*",
+		)
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "workspace-viewer")
+
+		inv, root = clitest.New(t, "roles", "edit", "workspace-viewer", "--display-name", "Workspace Viewer")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		inv, root = clitest.New(t, "roles", "ls", "--output=json")
+		clitest.SetupConfig(t, client, root)
+		buf = new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var roles []codersdk.CustomRole
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &roles))
+		require.Len(t, roles, 1)
+		require.Equal(t, "Workspace Viewer", roles[0].DisplayName)
+		require.Equal(t, []codersdk.Permission{
+			{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+			{Negate: true, ResourceType: codersdk.ResourceWorkspaceExecution, Action: "*"},
+		}, roles[0].Permissions)
+
+		inv, root = clitest.New(t, "roles", "rm", "workspace-viewer", "--yes")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		roles, err = client.CustomSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+	})
+
+	t.Run("OrgRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "roles", "create", "template-manager", "--org", "--permission", "template:*")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		roles, err := client.CustomOrganizationRoles(ctx, first.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		require.Equal(t, "template-manager", roles[0].Name)
+
+		// The role is not a site wide role.
+		inv, root = clitest.New(t, "roles", "edit", "template-manager", "--display-name", "Template Manager")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "--org")
+	})
+
+	t.Run("InvalidPermission", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "roles", "create", "broken", "--permission", "workspace")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "<resource>:<action>")
+	})
+}
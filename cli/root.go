@@ -76,6 +76,7 @@ var errUnauthenticated = xerrors.New(notLoggedInMessage)
 func (r *RootCmd) Core() []*clibase.Cmd {
 	// Please re-sort this list alphabetically if you change it!
 	return []*clibase.Cmd{
+		r.audit(),
 		r.dotfiles(),
 		r.login(),
 		r.logout(),
@@ -83,6 +84,7 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.portForward(),
 		r.publickey(),
 		r.resetPassword(),
+		r.roles(),
 		r.state(),
 		r.templates(),
 		r.tokens(),
@@ -91,9 +93,12 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 
 		// Workspace Commands
 		r.configSSH(),
+		r.cp(),
 		r.create(),
 		r.deleteWorkspace(),
+		r.exec(),
 		r.list(),
+		r.logs(),
 		r.ping(),
 		r.rename(),
 		r.schedules(),
@@ -102,7 +107,9 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.ssh(),
 		r.start(),
 		r.stop(),
+		r.transfer(),
 		r.update(),
+		r.workspaces(),
 		r.restart(),
 		r.stat(),
 
@@ -137,6 +144,15 @@ func (r *RootCmd) RunMain(subcommands []*clibase.Cmd) {
 			//nolint:revive
 			os.Exit(1)
 		}
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.Err != nil {
+				f := prettyErrorFormatter{w: os.Stderr}
+				f.format(exitErr.Err)
+			}
+			//nolint:revive
+			os.Exit(exitErr.Code)
+		}
 		f := prettyErrorFormatter{w: os.Stderr}
 		f.format(err)
 		//nolint:revive
@@ -642,10 +658,10 @@ func splitNamedWorkspace(identifier string) (owner string, workspaceName string,
 	return owner, workspaceName, nil
 }
 
-// namedWorkspace fetches and returns a workspace by an identifier, which may be either
+// NamedWorkspace fetches and returns a workspace by an identifier, which may be either
 // a bare name (for a workspace owned by the current user) or a "user/workspace" combination,
 // where user is either a username or UUID.
-func namedWorkspace(ctx context.Context, client *codersdk.Client, identifier string) (codersdk.Workspace, error) {
+func NamedWorkspace(ctx context.Context, client *codersdk.Client, identifier string) (codersdk.Workspace, error) {
 	owner, name, err := splitNamedWorkspace(identifier)
 	if err != nil {
 		return codersdk.Workspace{}, err
@@ -906,6 +922,25 @@ func isConnectionError(err error) bool {
 	return xerrors.As(err, &dnsErr) || xerrors.As(err, &opErr)
 }
 
+// ExitError is returned by commands that must exit with a specific code,
+// such as the exit code of a command run in a workspace. Err is printed
+// if it is not nil.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit code %d", e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 type prettyErrorFormatter struct {
 	w io.Writer
 }
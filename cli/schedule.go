@@ -83,7 +83,7 @@ func (r *RootCmd) scheduleShow() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			workspace, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
@@ -110,7 +110,7 @@ func (r *RootCmd) scheduleStart() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			workspace, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
@@ -132,7 +132,7 @@ func (r *RootCmd) scheduleStart() *clibase.Cmd {
 				return err
 			}
 
-			updated, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			updated, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
@@ -158,7 +158,7 @@ func (r *RootCmd) scheduleStop() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			workspace, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
@@ -178,7 +178,7 @@ func (r *RootCmd) scheduleStop() *clibase.Cmd {
 				return err
 			}
 
-			updated, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			updated, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
@@ -207,7 +207,7 @@ func (r *RootCmd) scheduleOverride() *clibase.Cmd {
 				return err
 			}
 
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			workspace, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return xerrors.Errorf("get workspace: %w", err)
 			}
@@ -232,7 +232,7 @@ func (r *RootCmd) scheduleOverride() *clibase.Cmd {
 				return err
 			}
 
-			updated, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			updated, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
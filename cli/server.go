@@ -11,6 +11,7 @@ import (
 	"crypto/x509"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -62,6 +63,7 @@ import (
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/cli/config"
 	"github.com/coder/coder/coderd"
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/autobuild"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbfake"
@@ -74,6 +76,7 @@ import (
 	"github.com/coder/coder/coderd/gitsshkey"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/prebuilds"
 	"github.com/coder/coder/coderd/prometheusmetrics"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
@@ -95,6 +98,97 @@ import (
 	"github.com/coder/wgtunnel/tunnelsdk"
 )
 
+// ReadOAuth2LoginProvidersFromEnv reads generic OAuth2 login providers from
+// CODER_OAUTH2_LOGIN_PROVIDER_<n>_<KEY> environment variables.
+func ReadOAuth2LoginProvidersFromEnv(environ []string) ([]codersdk.OAuth2LoginProviderConfig, error) {
+	// The index numbers must be in-order.
+	sort.Strings(environ)
+
+	var providers []codersdk.OAuth2LoginProviderConfig
+	for _, v := range clibase.ParseEnviron(environ, "CODER_OAUTH2_LOGIN_PROVIDER_") {
+		tokens := strings.SplitN(v.Name, "_", 2)
+		if len(tokens) != 2 {
+			return nil, xerrors.Errorf("invalid env var: %s", v.Name)
+		}
+
+		providerNum, err := strconv.Atoi(tokens[0])
+		if err != nil {
+			return nil, xerrors.Errorf("parse number: %s", v.Name)
+		}
+
+		var provider codersdk.OAuth2LoginProviderConfig
+		switch {
+		case len(providers) < providerNum:
+			return nil, xerrors.Errorf(
+				"provider num %v skipped: %s",
+				len(providers),
+				v.Name,
+			)
+		case len(providers) == providerNum:
+			// At the next next provider.
+			providers = append(providers, provider)
+		case len(providers) == providerNum+1:
+			// At the current provider.
+			provider = providers[providerNum]
+		}
+
+		key := tokens[1]
+		switch key {
+		case "ID":
+			provider.ID = v.Value
+		case "DISPLAY_NAME":
+			provider.DisplayName = v.Value
+		case "ICON_URL":
+			provider.IconURL = v.Value
+		case "CLIENT_ID":
+			provider.ClientID = v.Value
+		case "CLIENT_SECRET":
+			provider.ClientSecret = v.Value
+		case "AUTH_URL":
+			provider.AuthURL = v.Value
+		case "TOKEN_URL":
+			provider.TokenURL = v.Value
+		case "USER_INFO_URL":
+			provider.UserInfoURL = v.Value
+		case "SCOPES":
+			provider.Scopes = strings.Split(v.Value, " ")
+		case "ID_FIELD":
+			provider.IDField = v.Value
+		case "USERNAME_FIELD":
+			provider.UsernameField = v.Value
+		case "EMAIL_FIELD":
+			provider.EmailField = v.Value
+		case "EMAIL_VERIFIED_FIELD":
+			provider.EmailVerifiedField = v.Value
+		case "IGNORE_EMAIL_VERIFIED":
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse bool: %s", v.Value)
+			}
+			provider.IgnoreEmailVerified = b
+		case "GROUPS_FIELD":
+			provider.GroupsField = v.Value
+		case "GROUP_MAPPING":
+			err := json.Unmarshal([]byte(v.Value), &provider.GroupMapping)
+			if err != nil {
+				return nil, xerrors.Errorf("parse group mapping: %s", v.Name)
+			}
+		case "ALLOWED_GROUPS":
+			provider.AllowedGroups = strings.Split(v.Value, ",")
+		case "ALLOW_SIGNUPS":
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse bool: %s", v.Value)
+			}
+			provider.AllowSignups = b
+		case "EMAIL_DOMAIN":
+			provider.EmailDomain = strings.Split(v.Value, ",")
+		}
+		providers[providerNum] = provider
+	}
+	return providers, nil
+}
+
 // ReadGitAuthProvidersFromEnv is provided for compatibility purposes with the
 // viper CLI.
 // DEPRECATED
@@ -605,6 +699,35 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}
 			}
 
+			if cfg.LDAP.URL.String() != "" {
+				options.LDAPConfig, err = configureLDAP(cfg.LDAP)
+				if err != nil {
+					return xerrors.Errorf("configure ldap: %w", err)
+				}
+			}
+
+			if cfg.AuditLogSigningKey != "" {
+				options.AuditLogSigningKey, err = audit.ParseSigningKey(cfg.AuditLogSigningKey.String())
+				if err != nil {
+					return xerrors.Errorf("parse audit log signing key: %w", err)
+				}
+			}
+
+			options.AuditLogRetention, err = configureAuditLogRetention(cfg.AuditLogRetention)
+			if err != nil {
+				return xerrors.Errorf("configure audit log retention: %w", err)
+			}
+
+			oauth2LoginProvidersEnv, err := ReadOAuth2LoginProvidersFromEnv(os.Environ())
+			if err != nil {
+				return xerrors.Errorf("read oauth2 login providers from env: %w", err)
+			}
+			cfg.OAuth2.LoginProviders.Value = append(cfg.OAuth2.LoginProviders.Value, oauth2LoginProvidersEnv...)
+			options.OAuth2LoginProviders, err = configureOAuth2LoginProviders(cfg.AccessURL.Value(), cfg.OAuth2.LoginProviders.Value)
+			if err != nil {
+				return xerrors.Errorf("configure oauth2 login providers: %w", err)
+			}
+
 			if cfg.InMemoryDatabase {
 				// This is only used for testing.
 				options.Database = dbfake.New()
@@ -884,9 +1007,15 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer shutdownConns()
 
 			// Ensures that old database entries are cleaned up over time!
-			purger := dbpurge.New(ctx, logger, options.Database)
+			purger := dbpurge.New(ctx, logger, options.Database, options.AuditLogRetention)
 			defer purger.Close()
 
+			// Signs the audit log hash chain, so it cannot be rewritten.
+			if options.AuditLogSigningKey != nil {
+				checkpointer := audit.NewCheckpointer(ctx, logger, options.Database, options.AuditLogSigningKey)
+				defer checkpointer.Close()
+			}
+
 			// Wrap the server in middleware that redirects to the access URL if
 			// the request is not to a local IP.
 			var handler http.Handler = coderAPI.RootHandler
@@ -953,6 +1082,11 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			autobuildExecutor := autobuild.NewExecutor(ctx, options.Database, coderAPI.TemplateScheduleStore, logger, autobuildTicker.C)
 			autobuildExecutor.Run()
 
+			prebuildsTicker := time.NewTicker(cfg.AutobuildPollInterval.Value())
+			defer prebuildsTicker.Stop()
+			prebuildsReconciler := prebuilds.NewReconciler(ctx, options.Database, coderAPI.PrebuildsMetrics, logger, prebuildsTicker.C)
+			prebuildsReconciler.Run()
+
 			hangDetectorTicker := time.NewTicker(cfg.JobHangDetectorInterval.Value())
 			defer hangDetectorTicker.Stop()
 			hangDetector := unhanger.New(ctx, options.Database, options.Pubsub, logger, hangDetectorTicker.C)
@@ -1492,7 +1626,150 @@ func configureCAPool(tlsClientCAFile string, tlsConfig *tls.Config) error {
 	return nil
 }
 
+func configureAuditLogRetention(cfg codersdk.AuditLogRetentionConfig) (dbpurge.AuditLogRetention, error) {
+	retention := dbpurge.AuditLogRetention{
+		Default:       cfg.Period.Value(),
+		ResourceTypes: map[database.ResourceType]time.Duration{},
+	}
+	for resourceType, period := range cfg.ResourceTypes.Value {
+		if !database.ResourceType(resourceType).Valid() {
+			return dbpurge.AuditLogRetention{}, xerrors.Errorf("unknown resource type %q in audit-log-retention-resource-types", resourceType)
+		}
+		duration, err := time.ParseDuration(period)
+		if err != nil {
+			return dbpurge.AuditLogRetention{}, xerrors.Errorf("parse retention period of %q: %w", resourceType, err)
+		}
+		retention.ResourceTypes[database.ResourceType(resourceType)] = duration
+	}
+	if cfg.ArchiveDir != "" {
+		retention.Archiver = audit.NewDirArchiver(cfg.ArchiveDir.String())
+	}
+	return retention, nil
+}
+
+func configureLDAP(cfg codersdk.LDAPConfig) (*coderd.LDAPConfig, error) {
+	switch cfg.URL.Scheme {
+	case "ldap", "ldaps":
+	default:
+		return nil, xerrors.Errorf("LDAP URL scheme must be ldap or ldaps, got %q", cfg.URL.Scheme)
+	}
+	if cfg.StartTLS && cfg.URL.Scheme == "ldaps" {
+		return nil, xerrors.New("LDAP StartTLS cannot be used with an ldaps URL")
+	}
+	if cfg.SearchBaseDN == "" {
+		return nil, xerrors.New("LDAP search base DN must be set!")
+	}
+	if !strings.Contains(cfg.SearchFilter.String(), "%s") {
+		return nil, xerrors.Errorf("LDAP search filter %q must contain %%s", cfg.SearchFilter.String())
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if cfg.TLSCAFile != "" {
+		data, err := os.ReadFile(cfg.TLSCAFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", cfg.TLSCAFile.String(), err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(data) {
+			return nil, xerrors.Errorf("failed to parse CA certificate in ldap-tls-ca-file")
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	return &coderd.LDAPConfig{
+		URL:               cfg.URL.String(),
+		StartTLS:          cfg.StartTLS.Value(),
+		TLSConfig:         tlsConfig,
+		BindDN:            cfg.BindDN.String(),
+		BindPassword:      cfg.BindPassword.String(),
+		SearchBaseDN:      cfg.SearchBaseDN.String(),
+		SearchFilter:      cfg.SearchFilter.String(),
+		UsernameAttribute: cfg.UsernameAttribute.String(),
+		EmailAttribute:    cfg.EmailAttribute.String(),
+		GroupsAttribute:   cfg.GroupsAttribute.String(),
+		GroupMapping:      cfg.GroupMapping.Value,
+		AllowSignups:      cfg.AllowSignups.Value(),
+		SignInText:        cfg.SignInText.String(),
+	}, nil
+}
+
 //nolint:revive // Ignore flag-parameter: parameter 'allowEveryone' seems to be a control flag, avoid control coupling (revive)
+func configureOAuth2LoginProviders(accessURL *url.URL, configs []codersdk.OAuth2LoginProviderConfig) ([]*coderd.OAuth2LoginProvider, error) {
+	ids := map[string]struct{}{}
+	providers := make([]*coderd.OAuth2LoginProvider, 0, len(configs))
+	for _, cfg := range configs {
+		if cfg.ID == "" {
+			return nil, xerrors.New("oauth2 login provider id must be set")
+		}
+		if err := httpapi.NameValid(cfg.ID); err != nil {
+			return nil, xerrors.Errorf("oauth2 login provider id %q: %w", cfg.ID, err)
+		}
+		if cfg.ID == "github" {
+			return nil, xerrors.New("oauth2 login provider id \"github\" is reserved")
+		}
+		if _, ok := ids[cfg.ID]; ok {
+			return nil, xerrors.Errorf("multiple oauth2 login providers with id %q", cfg.ID)
+		}
+		ids[cfg.ID] = struct{}{}
+		if cfg.ClientID == "" {
+			return nil, xerrors.Errorf("oauth2 login provider %q: client id must be set", cfg.ID)
+		}
+		if cfg.AuthURL == "" || cfg.TokenURL == "" || cfg.UserInfoURL == "" {
+			return nil, xerrors.Errorf("oauth2 login provider %q: auth, token and user info urls must be set", cfg.ID)
+		}
+
+		redirectURL, err := accessURL.Parse(fmt.Sprintf("/api/v2/users/oauth2/%s/callback", cfg.ID))
+		if err != nil {
+			return nil, xerrors.Errorf("parse oauth2 login provider %q callback url: %w", cfg.ID, err)
+		}
+		provider := &coderd.OAuth2LoginProvider{
+			OAuth2Config: &oauth2.Config{
+				ClientID:     cfg.ClientID,
+				ClientSecret: cfg.ClientSecret,
+				Endpoint: oauth2.Endpoint{
+					AuthURL:  cfg.AuthURL,
+					TokenURL: cfg.TokenURL,
+				},
+				RedirectURL: redirectURL.String(),
+				Scopes:      cfg.Scopes,
+			},
+			ID:                  cfg.ID,
+			DisplayName:         cfg.DisplayName,
+			IconURL:             cfg.IconURL,
+			UserInfoURL:         cfg.UserInfoURL,
+			IDField:             cfg.IDField,
+			UsernameField:       cfg.UsernameField,
+			EmailField:          cfg.EmailField,
+			EmailVerifiedField:  cfg.EmailVerifiedField,
+			IgnoreEmailVerified: cfg.IgnoreEmailVerified,
+			GroupsField:         cfg.GroupsField,
+			GroupMapping:        cfg.GroupMapping,
+			AllowedGroups:       cfg.AllowedGroups,
+			AllowSignups:        cfg.AllowSignups,
+			EmailDomain:         cfg.EmailDomain,
+		}
+		if provider.DisplayName == "" {
+			provider.DisplayName = cfg.ID
+		}
+		if provider.IDField == "" {
+			provider.IDField = "id"
+		}
+		if provider.UsernameField == "" {
+			provider.UsernameField = "username"
+		}
+		if provider.EmailField == "" {
+			provider.EmailField = "email"
+		}
+		if provider.EmailVerifiedField == "" {
+			provider.EmailVerifiedField = "email_verified"
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
 func configureGithubOAuth2(accessURL *url.URL, clientID, clientSecret string, allowSignups, allowEveryone bool, allowOrgs []string, rawTeams []string, enterpriseBaseURL string) (*coderd.GithubOAuth2Config, error) {
 	redirectURL, err := accessURL.Parse("/api/v2/users/oauth2/github/callback")
 	if err != nil {
@@ -104,6 +104,79 @@ func TestReadGitAuthProvidersFromEnv(t *testing.T) {
 	})
 }
 
+func TestReadOAuth2LoginProvidersFromEnv(t *testing.T) {
+	t.Parallel()
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		providers, err := cli.ReadOAuth2LoginProvidersFromEnv([]string{
+			"HOME=/home/frodo",
+		})
+		require.NoError(t, err)
+		require.Empty(t, providers)
+	})
+	t.Run("SkipKey", func(t *testing.T) {
+		t.Parallel()
+		providers, err := cli.ReadOAuth2LoginProvidersFromEnv([]string{
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_ID=invalid",
+			"CODER_OAUTH2_LOGIN_PROVIDER_2_ID=invalid",
+		})
+		require.Error(t, err, "%+v", providers)
+		require.Empty(t, providers)
+	})
+	t.Run("InvalidGroupMapping", func(t *testing.T) {
+		t.Parallel()
+		_, err := cli.ReadOAuth2LoginProvidersFromEnv([]string{
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_GROUP_MAPPING=devs",
+		})
+		require.Error(t, err)
+	})
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		providers, err := cli.ReadOAuth2LoginProvidersFromEnv([]string{
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_ID=gitlab",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_DISPLAY_NAME=GitLab",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_CLIENT_ID=sid",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_CLIENT_SECRET=hunter12",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_AUTH_URL=https://gitlab.example.com/oauth/authorize",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_TOKEN_URL=https://gitlab.example.com/oauth/token",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_USER_INFO_URL=https://gitlab.example.com/api/v4/user",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_SCOPES=read_user openid",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_EMAIL_FIELD=emails[0]",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_EMAIL_VERIFIED_FIELD=confirmed",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_IGNORE_EMAIL_VERIFIED=true",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_GROUPS_FIELD=groups",
+			`CODER_OAUTH2_LOGIN_PROVIDER_0_GROUP_MAPPING={"coder/devs":"devs"}`,
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_ALLOWED_GROUPS=coder/devs,coder/ops",
+			"CODER_OAUTH2_LOGIN_PROVIDER_0_ALLOW_SIGNUPS=true",
+			"CODER_OAUTH2_LOGIN_PROVIDER_1_ID=gitea",
+			"CODER_OAUTH2_LOGIN_PROVIDER_1_EMAIL_DOMAIN=coder.com",
+		})
+		require.NoError(t, err)
+		require.Len(t, providers, 2)
+
+		// Validate the first provider.
+		assert.Equal(t, "gitlab", providers[0].ID)
+		assert.Equal(t, "GitLab", providers[0].DisplayName)
+		assert.Equal(t, "sid", providers[0].ClientID)
+		assert.Equal(t, "hunter12", providers[0].ClientSecret)
+		assert.Equal(t, "https://gitlab.example.com/oauth/authorize", providers[0].AuthURL)
+		assert.Equal(t, "https://gitlab.example.com/oauth/token", providers[0].TokenURL)
+		assert.Equal(t, "https://gitlab.example.com/api/v4/user", providers[0].UserInfoURL)
+		assert.Equal(t, []string{"read_user", "openid"}, providers[0].Scopes)
+		assert.Equal(t, "emails[0]", providers[0].EmailField)
+		assert.Equal(t, "confirmed", providers[0].EmailVerifiedField)
+		assert.True(t, providers[0].IgnoreEmailVerified)
+		assert.Equal(t, "groups", providers[0].GroupsField)
+		assert.Equal(t, map[string]string{"coder/devs": "devs"}, providers[0].GroupMapping)
+		assert.Equal(t, []string{"coder/devs", "coder/ops"}, providers[0].AllowedGroups)
+		assert.True(t, providers[0].AllowSignups)
+
+		// Validate the second provider.
+		assert.Equal(t, "gitea", providers[1].ID)
+		assert.Equal(t, []string{"coder.com"}, providers[1].EmailDomain)
+	})
+}
+
 func TestServer(t *testing.T) {
 	t.Parallel()
 
@@ -137,13 +137,16 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			}
 
 			// Select the startup script behavior based on template configuration or flags.
-			var wait bool
+			var wait, waitScripts bool
 			switch waitEnum {
 			case "yes":
 				wait = true
 			case "no":
 				wait = false
 			case "auto":
+				// Scripts can block login even if the startup script
+				// doesn't.
+				waitScripts = true
 				switch workspaceAgent.StartupScriptBehavior {
 				case codersdk.WorkspaceAgentStartupScriptBehaviorBlocking:
 					wait = true
@@ -158,6 +161,7 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			// The `--no-wait` flag is deprecated, but for now, check it.
 			if noWait {
 				wait = false
+				waitScripts = false
 			}
 
 			templateVersion, err := client.TemplateVersion(ctx, workspace.LatestBuild.TemplateVersionID)
@@ -185,9 +189,10 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			// OpenSSH passes stderr directly to the calling TTY.
 			// This is required in "stdio" mode so a connecting indicator can be displayed.
 			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
-				Fetch:     client.WorkspaceAgent,
-				FetchLogs: client.WorkspaceAgentStartupLogsAfter,
-				Wait:      wait,
+				Fetch:       client.WorkspaceAgent,
+				FetchLogs:   client.WorkspaceAgentStartupLogsAfter,
+				Wait:        wait,
+				WaitScripts: waitScripts,
 			})
 			if err != nil {
 				if xerrors.Is(err, context.Canceled) {
@@ -394,7 +399,7 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 	waitOption := clibase.Option{
 		Flag:        "wait",
 		Env:         "CODER_SSH_WAIT",
-		Description: "Specifies whether or not to wait for the startup script to finish executing. Auto means that the agent startup script behavior configured in the workspace template is used.",
+		Description: "Specifies whether or not to wait for the startup script to finish executing. Auto means that the agent startup script behavior configured in the workspace template is used, and that scripts which block login are waited for.",
 		Default:     "auto",
 		Value:       clibase.EnumOf(&waitEnum, "yes", "no", "auto"),
 	}
@@ -534,7 +539,7 @@ func getWorkspaceAndAgent(ctx context.Context, inv *clibase.Invocation, client *
 		err            error
 	)
 
-	workspace, err = namedWorkspace(ctx, client, workspaceParts[0])
+	workspace, err = NamedWorkspace(ctx, client, workspaceParts[0])
 	if err != nil {
 		return codersdk.Workspace{}, codersdk.WorkspaceAgent{}, err
 	}
@@ -40,7 +40,7 @@ func (r *RootCmd) statePull() *clibase.Cmd {
 			var err error
 			var build codersdk.WorkspaceBuild
 			if buildNumber == 0 {
-				workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+				workspace, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 				if err != nil {
 					return err
 				}
@@ -91,7 +91,7 @@ func (r *RootCmd) statePush() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			workspace, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return err
 			}
@@ -28,6 +28,8 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 		allowUserCancelWorkspaceJobs bool
 		allowUserAutostart           bool
 		allowUserAutostop            bool
+		recordSessions               bool
+		prebuiltWorkspaces           int64
 	)
 	client := new(codersdk.Client)
 
@@ -90,6 +92,13 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			if unsetRestartRequirementDaysOfWeek {
 				restartRequirementDaysOfWeek = []string{}
 			}
+			// Keep the current recording setting unless the flag is given.
+			if !inv.ParsedFlags().Changed("record-sessions") {
+				recordSessions = template.RecordSessions
+			}
+			if !inv.ParsedFlags().Changed("prebuilt-workspaces") {
+				prebuiltWorkspaces = int64(template.PrebuiltWorkspaces)
+			}
 
 			// NOTE: coderd will ignore empty fields.
 			req := codersdk.UpdateTemplateMeta{
@@ -108,6 +117,8 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				AllowUserCancelWorkspaceJobs: allowUserCancelWorkspaceJobs,
 				AllowUserAutostart:           allowUserAutostart,
 				AllowUserAutostop:            allowUserAutostop,
+				RecordSessions:               recordSessions,
+				PrebuiltWorkspaces:           int32(prebuiltWorkspaces),
 			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
@@ -204,6 +215,16 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			Default:     "true",
 			Value:       clibase.BoolOf(&allowUserAutostop),
 		},
+		{
+			Flag:        "record-sessions",
+			Description: "Record interactive terminal sessions in workspaces created from this template.",
+			Value:       clibase.BoolOf(&recordSessions),
+		},
+		{
+			Flag:        "prebuilt-workspaces",
+			Description: "Number of prebuilt workspaces to keep ready for users to claim when they create a workspace from this template.",
+			Value:       clibase.Int64Of(&prebuiltWorkspaces),
+		},
 		cliui.SkipPromptOption(),
 	}
 
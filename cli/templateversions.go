@@ -23,12 +23,17 @@ func (r *RootCmd) templateVersions() *clibase.Cmd {
 				Description: "List versions of a specific template",
 				Command:     "coder templates versions list my-template",
 			},
+			example{
+				Description: "Show what changed between two versions of a template",
+				Command:     "coder templates versions diff my-template v1 v2",
+			},
 		),
 		Handler: func(inv *clibase.Invocation) error {
 			return inv.Command.HelpHandler(inv)
 		},
 		Children: []*clibase.Cmd{
 			r.templateVersionsList(),
+			r.templateVersionsDiff(),
 		},
 	}
 
@@ -82,6 +87,128 @@ func (r *RootCmd) templateVersionsList() *clibase.Cmd {
 	return cmd
 }
 
+func (r *RootCmd) templateVersionsDiff() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+			diff, ok := data.(codersdk.TemplateVersionDiff)
+			if !ok {
+				return nil, xerrors.Errorf("expected type %T, got %T", diff, data)
+			}
+			return renderTemplateVersionDiff(diff), nil
+		}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+
+	cmd := &clibase.Cmd{
+		Use: "diff <template> <from-version> <to-version>",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(3),
+			r.InitClient(client),
+		),
+		Short: "Show the changes between two versions of the specified template",
+		Long: "Compares the template files, variables, parameters and resources of two template versions. " +
+			"Showing template files requires permission to update the template.",
+		Handler: func(inv *clibase.Invocation) error {
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			template, err := client.TemplateByName(inv.Context(), organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			from, err := client.TemplateVersionByName(inv.Context(), template.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("get template version %q: %w", inv.Args[1], err)
+			}
+			to, err := client.TemplateVersionByName(inv.Context(), template.ID, inv.Args[2])
+			if err != nil {
+				return xerrors.Errorf("get template version %q: %w", inv.Args[2], err)
+			}
+
+			diff, err := client.TemplateVersionDiff(inv.Context(), from.ID, to.ID)
+			if err != nil {
+				return xerrors.Errorf("diff template versions: %w", err)
+			}
+
+			out, err := formatter.Format(inv.Context(), diff)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// renderTemplateVersionDiff renders a summary of the changed variables,
+// parameters and resources followed by the unified diffs of the files.
+func renderTemplateVersionDiff(diff codersdk.TemplateVersionDiff) string {
+	var sb strings.Builder
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(&sb, "%s:\n", title)
+		for _, line := range lines {
+			_, _ = fmt.Fprintf(&sb, "  %s\n", line)
+		}
+		sb.WriteString("\n")
+	}
+
+	var variables []string
+	for _, v := range diff.Variables {
+		variables = append(variables, templateVersionDiffLine(v.Change, v.Name, v.Fields))
+	}
+	section("Variables", variables)
+
+	var parameters []string
+	for _, p := range diff.Parameters {
+		parameters = append(parameters, templateVersionDiffLine(p.Change, p.Name, p.Fields))
+	}
+	section("Parameters", parameters)
+
+	var resources []string
+	for _, res := range diff.Resources {
+		resources = append(resources, templateVersionDiffLine(res.Change, res.Type+"."+res.Name, res.Fields))
+		for _, agent := range res.Agents {
+			resources = append(resources, "  "+templateVersionDiffLine(agent.Change, "agent "+agent.Name, agent.Fields))
+		}
+	}
+	section("Resources", resources)
+
+	for _, file := range diff.Files {
+		if file.Binary {
+			_, _ = fmt.Fprintf(&sb, "Binary file %s %s\n", file.Path, file.Change)
+			continue
+		}
+		sb.WriteString(file.Diff)
+	}
+
+	if sb.Len() == 0 {
+		return "No changes."
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func templateVersionDiffLine(change codersdk.TemplateVersionDiffChange, name string, fields []string) string {
+	switch change {
+	case codersdk.TemplateVersionDiffChangeAdded:
+		return "+ " + name
+	case codersdk.TemplateVersionDiffChangeRemoved:
+		return "- " + name
+	default:
+		if len(fields) == 0 {
+			return "~ " + name
+		}
+		return "~ " + name + ": " + strings.Join(fields, ", ")
+	}
+}
+
 type templateVersionRow struct {
 	// For json format:
 	TemplateVersion codersdk.TemplateVersion `table:"-"`
@@ -1,12 +1,15 @@
 package cli_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/cli/clitest"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/provisionersdk/proto"
 	"github.com/coder/coder/pty/ptytest"
 )
 
@@ -36,4 +39,43 @@ func TestTemplateVersions(t *testing.T) {
 		pty.ExpectMatch(version.CreatedBy.Username)
 		pty.ExpectMatch("Active")
 	})
+
+	t.Run("DiffVersions", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		from := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, from.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, from.ID)
+		to := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Parameters: []*proto.RichParameter{{Name: "region", Type: "string"}},
+					},
+				},
+			}},
+			ProvisionApply: echo.ProvisionComplete,
+		}, template.ID)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, to.ID)
+
+		inv, root := clitest.New(t, "templates", "versions", "diff", template.Name, from.Name, to.Name)
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "Parameters:\n  + region")
+
+		inv, root = clitest.New(t, "templates", "versions", "diff", template.Name, from.Name, from.Name)
+		clitest.SetupConfig(t, client, root)
+		stdout.Reset()
+		inv.Stdout = &stdout
+
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Equal(t, "No changes.\n", stdout.String())
+	})
 }
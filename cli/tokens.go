@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
@@ -22,6 +25,14 @@ func (r *RootCmd) tokens() *clibase.Cmd {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			example{
+				Description: "Create a token that can only start and stop a workspace",
+				Command:     "coder tokens create --scope workspace_start_stop --resource workspace:my-workspace",
+			},
+			example{
+				Description: "Create a token for a service account",
+				Command:     "coder tokens create --user ci-bot --lifetime 8760h",
+			},
 			example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -48,6 +59,9 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 	var (
 		tokenLifetime time.Duration
 		name          string
+		scope         string
+		resources     []string
+		user          string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -58,9 +72,20 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			res, err := client.CreateToken(inv.Context(), codersdk.Me, codersdk.CreateTokenRequest{
+			allowList := make([]string, 0, len(resources))
+			for _, resource := range resources {
+				entry, err := tokenAllowListEntry(inv, client, resource)
+				if err != nil {
+					return xerrors.Errorf("resource %q: %w", resource, err)
+				}
+				allowList = append(allowList, entry)
+			}
+
+			res, err := client.CreateToken(inv.Context(), user, codersdk.CreateTokenRequest{
 				Lifetime:  tokenLifetime,
+				Scope:     codersdk.APIKeyScope(scope),
 				TokenName: name,
+				AllowList: allowList,
 			})
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
@@ -87,11 +112,94 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			Description:   "Specify a human-readable name.",
 			Value:         clibase.StringOf(&name),
 		},
+		{
+			Flag:        "scope",
+			Env:         "CODER_TOKEN_SCOPE",
+			Description: "Specify what the token is allowed to do.",
+			Default:     string(codersdk.APIKeyScopeAll),
+			Value: clibase.EnumOf(&scope,
+				string(codersdk.APIKeyScopeAll),
+				string(codersdk.APIKeyScopeApplicationConnect),
+				string(codersdk.APIKeyScopeReadOnly),
+				string(codersdk.APIKeyScopeWorkspaceStartStop),
+				string(codersdk.APIKeyScopeTemplatePush),
+				string(codersdk.APIKeyScopeUserRead),
+			),
+		},
+		{
+			Flag:        "resource",
+			Description: "Restrict the token to a workspace or template, given as workspace:<[owner/]name> or template:<name>. Restricting the token to a workspace also allows it to use the template of the workspace.",
+			Value:       clibase.StringArrayOf(&resources),
+		},
+		{
+			Flag:        "user",
+			Description: "Create the token for another user, such as a service account. Requires permission to manage the tokens of the user.",
+			Default:     codersdk.Me,
+			Value:       clibase.StringOf(&user),
+		},
 	}
 
 	return cmd
 }
 
+// tokenAllowListEntry resolves a workspace:<name> or template:<name> resource
+// to the allow list entry of a token.
+func tokenAllowListEntry(inv *clibase.Invocation, client *codersdk.Client, resource string) (string, error) {
+	resourceType, name, ok := strings.Cut(resource, ":")
+	if !ok {
+		return "", xerrors.New("must be in the form workspace:<name> or template:<name>")
+	}
+	switch codersdk.RBACResource(resourceType) {
+	case codersdk.ResourceWorkspace:
+		workspace, err := NamedWorkspace(inv.Context(), client, name)
+		if err != nil {
+			return "", xerrors.Errorf("get workspace: %w", err)
+		}
+		return codersdk.APIKeyAllowListEntry(codersdk.ResourceWorkspace, workspace.ID), nil
+	case codersdk.ResourceTemplate:
+		org, err := CurrentOrganization(inv, client)
+		if err != nil {
+			return "", xerrors.Errorf("get current organization: %w", err)
+		}
+		template, err := client.TemplateByName(inv.Context(), org.ID, name)
+		if err != nil {
+			return "", xerrors.Errorf("get template: %w", err)
+		}
+		return codersdk.APIKeyAllowListEntry(codersdk.ResourceTemplate, template.ID), nil
+	default:
+		return "", xerrors.Errorf("unsupported resource type %q, must be workspace or template", resourceType)
+	}
+}
+
+// tokenResourceNames returns the allow list of a token with the names of
+// its resources in place of their IDs. Resources that cannot be fetched are
+// shown by their ID.
+func tokenResourceNames(ctx context.Context, client *codersdk.Client, allowList []string) []string {
+	names := make([]string, 0, len(allowList))
+	for _, entry := range allowList {
+		resourceType, rawID, _ := strings.Cut(entry, ":")
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			names = append(names, entry)
+			continue
+		}
+		switch codersdk.RBACResource(resourceType) {
+		case codersdk.ResourceWorkspace:
+			workspace, err := client.Workspace(ctx, id)
+			if err == nil {
+				entry = fmt.Sprintf("%s:%s/%s", resourceType, workspace.OwnerName, workspace.Name)
+			}
+		case codersdk.ResourceTemplate:
+			template, err := client.Template(ctx, id)
+			if err == nil {
+				entry = fmt.Sprintf("%s:%s", resourceType, template.Name)
+			}
+		}
+		names = append(names, entry)
+	}
+	return names
+}
+
 // tokenListRow is the type provided to the OutputFormatter.
 type tokenListRow struct {
 	// For JSON format:
@@ -104,9 +212,11 @@ type tokenListRow struct {
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
 	Owner     string    `json:"-" table:"owner"`
+	Scope     string    `json:"-" table:"scope"`
+	Resources []string  `json:"-" table:"resources"`
 }
 
-func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
+func tokenListRowFromToken(token codersdk.APIKeyWithOwner, resources []string) tokenListRow {
 	return tokenListRow{
 		APIKey:    token.APIKey,
 		ID:        token.ID,
@@ -115,12 +225,14 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 		ExpiresAt: token.ExpiresAt,
 		CreatedAt: token.CreatedAt,
 		Owner:     token.Username,
+		Scope:     string(token.Scope),
+		Resources: resources,
 	}
 }
 
 func (r *RootCmd) listTokens() *clibase.Cmd {
 	// we only display the 'owner' column if the --all argument is passed in
-	defaultCols := []string{"id", "name", "last used", "expires at", "created at"}
+	defaultCols := []string{"id", "name", "scope", "resources", "last used", "expires at", "created at"}
 	if slices.Contains(os.Args, "-a") || slices.Contains(os.Args, "--all") {
 		defaultCols = append(defaultCols, "owner")
 	}
@@ -161,7 +273,7 @@ func (r *RootCmd) listTokens() *clibase.Cmd {
 			displayTokens = make([]tokenListRow, len(tokens))
 
 			for i, token := range tokens {
-				displayTokens[i] = tokenListRowFromToken(token)
+				displayTokens[i] = tokenListRowFromToken(token, tokenResourceNames(inv.Context(), client, token.AllowList))
 			}
 
 			out, err := formatter.Format(inv.Context(), displayTokens)
@@ -208,9 +320,14 @@ func (r *RootCmd) removeToken() *clibase.Cmd {
 				return xerrors.Errorf("delete api key: %w", err)
 			}
 
+			restriction := ""
+			if len(token.AllowList) > 0 {
+				restriction = fmt.Sprintf(" restricted to %s", strings.Join(tokenResourceNames(inv.Context(), client, token.AllowList), ", "))
+			}
 			cliui.Infof(
 				inv.Stdout,
-				"Token has been deleted.",
+				"Token with scope %s%s has been deleted.",
+				token.Scope, restriction,
 			)
 
 			return nil
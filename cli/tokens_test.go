@@ -78,3 +78,50 @@ func TestTokens(t *testing.T) {
 	require.NotEmpty(t, res)
 	require.Contains(t, res, "deleted")
 }
+
+func TestTokensRestricted(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "tokens", "create", "--name", "push", "--scope", "template_push", "--resource", "template:"+template.Name)
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	key, err := client.APIKeyByName(ctx, codersdk.Me, "push")
+	require.NoError(t, err)
+	require.Equal(t, codersdk.APIKeyScopeTemplatePush, key.Scope)
+	require.Equal(t, []string{codersdk.APIKeyAllowListEntry(codersdk.ResourceTemplate, template.ID)}, key.AllowList)
+
+	inv, root = clitest.New(t, "tokens", "ls")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	res := buf.String()
+	require.Contains(t, res, "SCOPE")
+	require.Contains(t, res, "template_push")
+	require.Contains(t, res, "template:"+template.Name)
+
+	inv, root = clitest.New(t, "tokens", "rm", "push")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "template_push restricted to template:"+template.Name)
+
+	inv, root = clitest.New(t, "tokens", "create", "--resource", "user:"+user.UserID.String())
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "unsupported resource type")
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) transfer() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "transfer <workspace> <user>",
+		Short:       "Transfer a workspace to another user",
+		Long: "The workspace is rebuilt for the new owner. Agent tokens and the session " +
+			"token of the previous owner are revoked.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: clibase.OptionSet{
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *clibase.Invocation) error {
+			workspace, err := NamedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Transfer %s from %s to %s?", workspace.Name, workspace.OwnerName, inv.Args[1]),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			build, err := client.TransferWorkspace(inv.Context(), workspace.ID, codersdk.TransferWorkspaceRequest{
+				Owner: inv.Args[1],
+			})
+			if err != nil {
+				return xerrors.Errorf("transfer workspace: %w", err)
+			}
+
+			err = cliui.WorkspaceBuild(inv.Context(), inv.Stdout, client, build.ID)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "\nThe %s workspace has been transferred to %s!\n", cliui.DefaultStyles.Keyword.Render(workspace.Name), cliui.DefaultStyles.Keyword.Render(build.WorkspaceOwnerName))
+			return nil
+		},
+	}
+	return cmd
+}
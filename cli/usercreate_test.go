@@ -4,10 +4,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/cli/clitest"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
 )
 
 func TestUserCreate(t *testing.T) {
@@ -37,4 +40,19 @@ func TestUserCreate(t *testing.T) {
 		}
 		<-doneChan
 	})
+	t.Run("ServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		inv, root := clitest.New(t, "users", "create", "--username", "bot", "--email", "bot@coder.com", "--service-account")
+		clitest.SetupConfig(t, client, root)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		user, err := client.User(ctx, "bot")
+		require.NoError(t, err)
+		require.True(t, user.ServiceAccount)
+		require.Equal(t, codersdk.LoginTypeNone, user.LoginType)
+	})
 }
@@ -15,7 +15,7 @@ import (
 
 func (r *RootCmd) userList() *clibase.Cmd {
 	formatter := cliui.NewOutputFormatter(
-		cliui.TableFormat([]codersdk.User{}, []string{"username", "email", "created_at", "status"}),
+		cliui.TableFormat([]codersdk.User{}, []string{"username", "email", "created_at", "status", "service account"}),
 		cliui.JSONFormat(),
 	)
 	client := new(codersdk.Client)
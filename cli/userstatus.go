@@ -22,7 +22,7 @@ func (r *RootCmd) createUserStatusCommand(sdkStatus codersdk.UserStatus) *clibas
 		verb = "activate"
 		pastVerb = "activated"
 		aliases = []string{"active"}
-		short = "Update a user's status to 'active'. Active users can fully interact with the platform"
+		short = "Update a user's status to 'active'. Active users can fully interact with the platform, and activating a locked user unlocks their account"
 	case codersdk.UserStatusSuspended:
 		verb = "suspend"
 		pastVerb = "suspended"
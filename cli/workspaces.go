@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) workspaces() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "workspaces",
+		Short:       "Manage many workspaces at once",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.workspacesBulk(),
+		},
+	}
+	return cmd
+}
+
+// bulkWorkspaceRow is the type provided to the OutputFormatter.
+type bulkWorkspaceRow struct {
+	// For JSON format:
+	codersdk.BulkWorkspaceActionResult `table:"-"`
+
+	// For table format:
+	Workspace string `json:"-" table:"workspace,default_sort"`
+	Result    string `json:"-" table:"result"`
+	Build     string `json:"-" table:"build"`
+	Error     string `json:"-" table:"error"`
+}
+
+func bulkWorkspaceRows(res codersdk.BulkWorkspaceActionResponse) []bulkWorkspaceRow {
+	rows := make([]bulkWorkspaceRow, 0, len(res.Results))
+	for _, result := range res.Results {
+		row := bulkWorkspaceRow{
+			BulkWorkspaceActionResult: result,
+			Workspace:                 result.OwnerName + "/" + result.WorkspaceName,
+			Error:                     result.Error,
+		}
+		switch {
+		case res.DryRun:
+			row.Result = "dry run"
+		case result.Error != "":
+			row.Result = "failed"
+		default:
+			row.Result = "queued"
+		}
+		if result.BuildID != nil {
+			row.Build = result.BuildID.String()
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (r *RootCmd) workspacesBulk() *clibase.Cmd {
+	var (
+		searchQuery string
+		dryRun      bool
+		formatter   = cliui.NewOutputFormatter(
+			cliui.TableFormat([]bulkWorkspaceRow{}, nil),
+			cliui.JSONFormat(),
+		)
+	)
+	actions := []string{
+		string(codersdk.BulkWorkspaceActionStart),
+		string(codersdk.BulkWorkspaceActionStop),
+		string(codersdk.BulkWorkspaceActionUpdate),
+		string(codersdk.BulkWorkspaceActionDelete),
+	}
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "bulk { start | stop | update | delete }",
+		Short: "Start, stop, update or delete all workspaces matching a search query",
+		Long: "Builds are queued for every matching workspace and the result is reported per workspace. " +
+			"The update action only selects workspaces that are outdated. Every action is recorded in the audit log.\n\n" +
+			formatExamples(
+				example{
+					Description: "List the running workspaces of a template that would be stopped",
+					Command:     `coder workspaces bulk stop --search "template:docker status:running" --dry-run`,
+				},
+				example{
+					Description: "Update all workspaces of a template to its active version",
+					Command:     `coder workspaces bulk update --search "template:docker" --yes`,
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			action := codersdk.BulkWorkspaceAction(inv.Args[0])
+			valid := false
+			for _, a := range actions {
+				if string(action) == a {
+					valid = true
+				}
+			}
+			if !valid {
+				return xerrors.Errorf("unknown action %q, must be one of: %s", action, strings.Join(actions, ", "))
+			}
+			if searchQuery == "" {
+				return xerrors.New("a search query is required to select workspaces, see --search")
+			}
+
+			req := codersdk.BulkWorkspaceActionRequest{
+				Action: action,
+				Query:  searchQuery,
+				DryRun: true,
+			}
+			res, err := client.BulkWorkspaceAction(inv.Context(), req)
+			if err != nil {
+				return xerrors.Errorf("select workspaces: %w", err)
+			}
+			if len(res.Results) == 0 {
+				_, _ = fmt.Fprintln(inv.Stderr, "No workspaces match the search query.")
+				return nil
+			}
+
+			if !dryRun {
+				names := make([]string, 0, len(res.Results))
+				for _, result := range res.Results {
+					names = append(names, "  "+result.OwnerName+"/"+result.WorkspaceName)
+				}
+				_, _ = fmt.Fprintf(inv.Stderr, "The following workspaces will be affected:\n%s\n\n", strings.Join(names, "\n"))
+				_, err = cliui.Prompt(inv, cliui.PromptOptions{
+					Text:      fmt.Sprintf("Confirm %s %d workspace(s)?", action, len(res.Results)),
+					IsConfirm: true,
+				})
+				if err != nil {
+					return err
+				}
+
+				req.DryRun = false
+				res, err = client.BulkWorkspaceAction(inv.Context(), req)
+				if err != nil {
+					return xerrors.Errorf("%s workspaces: %w", action, err)
+				}
+			}
+
+			out, err := formatter.Format(inv.Context(), bulkWorkspaceRows(res))
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			if err != nil {
+				return err
+			}
+
+			var failed int
+			for _, result := range res.Results {
+				if result.Error != "" {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return xerrors.Errorf("%d of %d workspace(s) failed to %s", failed, len(res.Results), action)
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "search",
+			Env:         "CODER_WORKSPACES_BULK_SEARCH",
+			Description: "Search query to select workspaces, in the same format as the workspace list filter.",
+			Value:       clibase.StringOf(&searchQuery),
+		},
+		{
+			Flag:        "dry-run",
+			Env:         "CODER_WORKSPACES_BULK_DRY_RUN",
+			Description: "List the selected workspaces without building them.",
+			Value:       clibase.BoolOf(&dryRun),
+		},
+		cliui.SkipPromptOption(),
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
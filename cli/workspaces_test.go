@@ -0,0 +1,100 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspacesBulk(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Template, codersdk.Workspace) {
+		t.Helper()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		return client, template, workspace
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+		client, template, workspace := setup(t)
+
+		inv, root := clitest.New(t, "workspaces", "bulk", "stop", "--search", "template:"+template.Name, "--dry-run")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), workspace.OwnerName+"/"+workspace.Name)
+		require.Contains(t, stdout.String(), "dry run")
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		t.Parallel()
+		client, template, workspace := setup(t)
+
+		inv, root := clitest.New(t, "workspaces", "bulk", "stop", "--search", "template:"+template.Name+" status:running", "--yes", "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var results []codersdk.BulkWorkspaceActionResult
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+		require.Len(t, results, 1)
+		require.Equal(t, workspace.ID, results[0].WorkspaceID)
+		require.NotNil(t, results[0].BuildID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, *results[0].BuildID)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, build.Transition)
+	})
+
+	t.Run("NoMatches", func(t *testing.T) {
+		t.Parallel()
+		client, template, _ := setup(t)
+
+		inv, root := clitest.New(t, "workspaces", "bulk", "start", "--search", "template:"+template.Name+" status:stopped")
+		clitest.SetupConfig(t, client, root)
+		var stderr bytes.Buffer
+		inv.Stderr = &stderr
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, stderr.String(), "No workspaces match the search query.")
+	})
+
+	t.Run("UnknownAction", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "workspaces", "bulk", "restart", "--search", "owner:me")
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, `unknown action "restart"`)
+	})
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/apikey"
@@ -80,12 +82,22 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	allowList, validErrs := api.tokenAllowList(ctx, createToken.AllowList)
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid allow list.",
+			Validations: validErrs,
+		})
+		return
+	}
+
 	cookie, key, err := api.createAPIKey(ctx, apikey.CreateParams{
 		UserID:           user.ID,
 		LoginType:        database.LoginTypeToken,
 		DeploymentValues: api.DeploymentValues,
 		ExpiresAt:        database.Now().Add(lifeTime),
 		Scope:            scope,
+		AllowList:        allowList,
 		LifetimeSeconds:  int64(lifeTime.Seconds()),
 		TokenName:        tokenName,
 	})
@@ -373,6 +385,49 @@ func (api *API) validateAPIKeyLifetime(lifetime time.Duration) error {
 	return nil
 }
 
+// tokenAllowList validates the allow list of a token and returns the entries
+// to store. Workspaces can only be built with their template, so restricting a
+// token to a workspace also allows its template.
+func (api *API) tokenAllowList(ctx context.Context, entries []string) ([]string, []codersdk.ValidationError) {
+	var (
+		allowList []string
+		validErrs []codersdk.ValidationError
+	)
+	add := func(entry string) {
+		if !slices.Contains(allowList, entry) {
+			allowList = append(allowList, entry)
+		}
+	}
+	for _, entry := range entries {
+		resource, rawID, _ := strings.Cut(entry, ":")
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "allow_list", Detail: fmt.Sprintf("%q must be in the form <type>:<uuid>.", entry)})
+			continue
+		}
+		switch codersdk.RBACResource(resource) {
+		case codersdk.ResourceWorkspace:
+			workspace, err := api.Database.GetWorkspaceByID(ctx, id)
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "allow_list", Detail: fmt.Sprintf("Workspace %q not found.", id)})
+				continue
+			}
+			add(codersdk.APIKeyAllowListEntry(codersdk.ResourceWorkspace, workspace.ID))
+			add(codersdk.APIKeyAllowListEntry(codersdk.ResourceTemplate, workspace.TemplateID))
+		case codersdk.ResourceTemplate:
+			template, err := api.Database.GetTemplateByID(ctx, id)
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "allow_list", Detail: fmt.Sprintf("Template %q not found.", id)})
+				continue
+			}
+			add(codersdk.APIKeyAllowListEntry(codersdk.ResourceTemplate, template.ID))
+		default:
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "allow_list", Detail: fmt.Sprintf("Tokens cannot be restricted to resources of type %q.", resource)})
+		}
+	}
+	return allowList, validErrs
+}
+
 func (api *API) createAPIKey(ctx context.Context, params apikey.CreateParams) (*http.Cookie, *database.APIKey, error) {
 	key, sessionToken, err := apikey.Generate(params)
 	if err != nil {
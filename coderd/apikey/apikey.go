@@ -24,6 +24,7 @@ type CreateParams struct {
 	ExpiresAt       time.Time
 	LifetimeSeconds int64
 	Scope           database.APIKeyScope
+	AllowList       []string
 	TokenName       string
 	RemoteAddr      string
 }
@@ -65,11 +66,18 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		scope = params.Scope
 	}
 	switch scope {
-	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
+	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect,
+		database.APIKeyScopeReadOnly, database.APIKeyScopeWorkspaceStartStop,
+		database.APIKeyScopeTemplatePush, database.APIKeyScopeUserRead:
 	default:
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
 
+	allowList := params.AllowList
+	if allowList == nil {
+		allowList = []string{}
+	}
+
 	token := fmt.Sprintf("%s-%s", keyID, keySecret)
 
 	return database.InsertAPIKeyParams{
@@ -90,6 +98,7 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		HashedSecret: hashed[:],
 		LoginType:    params.LoginType,
 		Scope:        scope,
+		AllowList:    allowList,
 		TokenName:    params.TokenName,
 	}, token, nil
 }
@@ -84,6 +84,181 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenScopes(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, otherVersion.ID)
+	otherTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, otherVersion.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	otherWorkspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, otherWorkspace.LatestBuild.ID)
+
+	tokenClient := func(ctx context.Context, t *testing.T, req codersdk.CreateTokenRequest) *codersdk.Client {
+		res, err := client.CreateToken(ctx, codersdk.Me, req)
+		require.NoError(t, err)
+		tokenClient := codersdk.New(client.URL)
+		tokenClient.SetSessionToken(res.Key)
+		return tokenClient
+	}
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		readOnly := tokenClient(ctx, t, codersdk.CreateTokenRequest{
+			Scope: codersdk.APIKeyScopeReadOnly,
+		})
+		_, err := readOnly.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		_, err = readOnly.Template(ctx, template.ID)
+		require.NoError(t, err)
+
+		err = readOnly.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("WorkspaceStartStop", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		startStop := tokenClient(ctx, t, codersdk.CreateTokenRequest{
+			Scope:     codersdk.APIKeyScopeWorkspaceStartStop,
+			AllowList: []string{codersdk.APIKeyAllowListEntry(codersdk.ResourceWorkspace, workspace.ID)},
+		})
+		build, err := startStop.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+
+		// Deleting is not starting or stopping.
+		_, err = startStop.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.Error(t, err)
+
+		// Neither is changing the workspace in any other way.
+		forbidden := func(err error) {
+			t.Helper()
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		}
+		forbidden(startStop.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{
+			Name: "renamed",
+		}))
+		forbidden(startStop.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{}))
+		forbidden(startStop.UpdateWorkspaceAutostart(ctx, workspace.ID, codersdk.UpdateWorkspaceAutostartRequest{}))
+		renamed, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, workspace.Name, renamed.Name)
+
+		// Other workspaces are out of reach.
+		_, err = startStop.Workspace(ctx, otherWorkspace.ID)
+		require.Error(t, err)
+		_, err = startStop.CreateWorkspaceBuild(ctx, otherWorkspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("AllowListUserData", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		restricted := tokenClient(ctx, t, codersdk.CreateTokenRequest{
+			AllowList: []string{codersdk.APIKeyAllowListEntry(codersdk.ResourceWorkspace, workspace.ID)},
+		})
+		forbidden := func(err error) {
+			t.Helper()
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		}
+
+		// The owner can be read, but not their data.
+		_, err := restricted.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = restricted.GitSSHKey(ctx, codersdk.Me)
+		forbidden(err)
+		forbidden(restricted.UpdateUserPassword(ctx, codersdk.Me, codersdk.UpdateUserPasswordRequest{
+			OldPassword: coderdtest.FirstUserParams.Password,
+			Password:    "SomeOtherSecurePassword!",
+		}))
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.NoError(t, err)
+
+		// Only the organizations of the owner can be read.
+		_, err = restricted.Organization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+	})
+
+	t.Run("TemplatePush", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		push := tokenClient(ctx, t, codersdk.CreateTokenRequest{
+			Scope:     codersdk.APIKeyScopeTemplatePush,
+			AllowList: []string{codersdk.APIKeyAllowListEntry(codersdk.ResourceTemplate, template.ID)},
+		})
+		version := coderdtest.UpdateTemplateVersion(t, push, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+		_, err := push.Template(ctx, otherTemplate.ID)
+		require.Error(t, err)
+		_, err = push.Workspace(ctx, workspace.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidAllowList", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, entry := range []string{
+			"workspace",
+			codersdk.APIKeyAllowListEntry(codersdk.ResourceWorkspace, template.ID),
+			codersdk.APIKeyAllowListEntry(codersdk.ResourceUser, user.UserID),
+		} {
+			_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+				AllowList: []string{entry},
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, entry)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), entry)
+		}
+	})
+
+	t.Run("ListAllowList", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "restricted",
+			Scope:     codersdk.APIKeyScopeWorkspaceStartStop,
+			AllowList: []string{codersdk.APIKeyAllowListEntry(codersdk.ResourceWorkspace, workspace.ID)},
+		})
+		require.NoError(t, err)
+		key, err := client.APIKeyByName(ctx, codersdk.Me, "restricted")
+		require.NoError(t, err)
+		require.Equal(t, codersdk.APIKeyScopeWorkspaceStartStop, key.Scope)
+		// The template of the workspace is implied.
+		require.ElementsMatch(t, []string{
+			codersdk.APIKeyAllowListEntry(codersdk.ResourceWorkspace, workspace.ID),
+			codersdk.APIKeyAllowListEntry(codersdk.ResourceTemplate, template.ID),
+		}, key.AllowList)
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
@@ -1,7 +1,9 @@
 package coderd
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/ed25519"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -18,9 +20,9 @@ import (
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/db2sdk"
+	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
-	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/searchquery"
 	"github.com/coder/coder/codersdk"
 )
@@ -83,6 +85,120 @@ func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// exportAuditLogsBatchSize is the number of audit logs read from the database
+// at once when exporting.
+const exportAuditLogsBatchSize = 1000
+
+// @Summary Export audit logs
+// @ID export-audit-logs
+// @Security CoderSessionToken
+// @Tags Audit
+// @Param from query string true "Start time, inclusive" format(date-time)
+// @Param to query string true "End time, exclusive" format(date-time)
+// @Success 200
+// @Router /audit/export [get]
+func (api *API) exportAuditLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().Required("from").Required("to")
+	vals := r.URL.Query()
+	from := p.Time3339Nano(vals, time.Time{}, "from")
+	to := p.Time3339Nano(vals, time.Time{}, "to")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	if !from.Before(to) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter \"from\" must be before \"to\".",
+		})
+		return
+	}
+
+	// The first batch is read before writing the response, so permission
+	// errors can still be returned.
+	params := database.GetAuditLogsByTimeParams{
+		AfterTime:  from,
+		Before:     to,
+		LimitCount: exportAuditLogsBatchSize,
+	}
+	dblogs, err := api.Database.GetAuditLogsByTime(ctx, params)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/gzip")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf(
+		"audit-logs-%s-%s.jsonl.gz", from.Format("20060102T150405Z"), to.Format("20060102T150405Z"),
+	)))
+	rw.WriteHeader(http.StatusOK)
+
+	// On failure the gzip stream is left unterminated, so the client can't
+	// mistake a partial export for a complete one.
+	zw := gzip.NewWriter(rw)
+	for len(dblogs) > 0 {
+		err = audit.WriteArchive(zw, dblogs)
+		if err != nil {
+			api.Logger.Warn(ctx, "write audit log export", slog.Error(err))
+			return
+		}
+		if len(dblogs) < exportAuditLogsBatchSize {
+			break
+		}
+		last := dblogs[len(dblogs)-1]
+		params.AfterTime = last.Time
+		params.AfterID = last.ID
+		dblogs, err = api.Database.GetAuditLogsByTime(ctx, params)
+		if err != nil {
+			api.Logger.Warn(ctx, "get audit logs to export", slog.Error(err))
+			return
+		}
+	}
+	err = zw.Close()
+	if err != nil {
+		api.Logger.Warn(ctx, "write audit log export", slog.Error(err))
+	}
+}
+
+// @Summary Verify audit logs
+// @ID verify-audit-logs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Success 200 {object} codersdk.AuditLogVerification
+// @Router /audit/verify [get]
+func (api *API) verifyAuditLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var publicKey ed25519.PublicKey
+	if api.AuditLogSigningKey != nil {
+		publicKey, _ = api.AuditLogSigningKey.Public().(ed25519.PublicKey)
+	}
+	verification, err := audit.VerifyChain(ctx, api.Database, publicKey, api.AuditLogRetention.Period, database.Now())
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error verifying audit logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, verification)
+}
+
 // @Summary Generate fake audit log
 // @ID generate-fake-audit-log
 // @Security CoderSessionToken
@@ -141,7 +257,7 @@ func (api *API) generateFakeAuditLog(rw http.ResponseWriter, r *http.Request) {
 		params.AdditionalFields = json.RawMessage("{}")
 	}
 
-	_, err = api.Database.InsertAuditLog(ctx, database.InsertAuditLogParams{
+	_, err = audit.InsertLog(ctx, api.Database, database.InsertAuditLogParams{
 		ID:               uuid.New(),
 		Time:             params.Time,
 		UserID:           user.ID,
@@ -183,18 +299,18 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 
 	if dblog.UserUsername.Valid {
 		user = &codersdk.User{
-			ID:        dblog.UserID,
-			Username:  dblog.UserUsername.String,
-			Email:     dblog.UserEmail.String,
-			CreatedAt: dblog.UserCreatedAt.Time,
-			Status:    codersdk.UserStatus(dblog.UserStatus.UserStatus),
-			Roles:     []codersdk.Role{},
-			AvatarURL: dblog.UserAvatarUrl.String,
+			ID:             dblog.UserID,
+			Username:       dblog.UserUsername.String,
+			Email:          dblog.UserEmail.String,
+			CreatedAt:      dblog.UserCreatedAt.Time,
+			Status:         codersdk.UserStatus(dblog.UserStatus.UserStatus),
+			Roles:          []codersdk.Role{},
+			AvatarURL:      dblog.UserAvatarUrl.String,
+			ServiceAccount: dblog.UserIsServiceAccount.Bool,
 		}
 
 		for _, roleName := range dblog.UserRoles {
-			rbacRole, _ := rbac.RoleByName(roleName)
-			user.Roles = append(user.Roles, db2sdk.Role(rbacRole))
+			user.Roles = append(user.Roles, db2sdk.RoleByName(roleName))
 		}
 	}
 
@@ -372,6 +488,13 @@ func (api *API) auditLogResourceLink(ctx context.Context, alog database.GetAudit
 		return fmt.Sprintf("/@%s/%s/builds/%s",
 			workspaceOwner.Username, additionalFields.WorkspaceName, additionalFields.BuildNumber)
 
+	case database.ResourceTypeWorkspaceAgent, database.ResourceTypeWorkspaceApp:
+		if len(additionalFields.WorkspaceOwner) == 0 || len(additionalFields.WorkspaceName) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("/@%s/%s",
+			additionalFields.WorkspaceOwner, additionalFields.WorkspaceName)
+
 	default:
 		return ""
 	}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// WriteArchive writes audit logs as JSON lines. Archives are gzip compressed
+// JSON lines, so concatenated archives are valid archives too.
+func WriteArchive(w io.Writer, alogs []database.AuditLog) error {
+	for _, alog := range alogs {
+		line, err := MarshalLog(alog)
+		if err != nil {
+			return xerrors.Errorf("marshal audit log %s: %w", alog.ID, err)
+		}
+		_, err = w.Write(append(line, '\n'))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Archiver stores audit logs before they are deleted by the retention policy.
+type Archiver interface {
+	// Archive is called with batches of audit logs in ascending order of time.
+	// The audit logs are only deleted if it succeeds.
+	Archive(ctx context.Context, alogs []database.AuditLog) error
+}
+
+// NewDirArchiver writes each batch of audit logs to an archive in dir, named
+// after the time of the first and last audit log.
+func NewDirArchiver(dir string) Archiver {
+	return dirArchiver{dir: dir}
+}
+
+type dirArchiver struct {
+	dir string
+}
+
+const archiveTimeFormat = "20060102T150405.000000Z"
+
+func (a dirArchiver) Archive(_ context.Context, alogs []database.AuditLog) error {
+	if len(alogs) == 0 {
+		return nil
+	}
+	err := os.MkdirAll(a.dir, 0o700)
+	if err != nil {
+		return xerrors.Errorf("create archive directory: %w", err)
+	}
+
+	// Write to a temporary file first, so partial archives are never left
+	// behind with the final name.
+	file, err := os.CreateTemp(a.dir, ".audit-logs-*")
+	if err != nil {
+		return xerrors.Errorf("create archive: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+	zw := gzip.NewWriter(file)
+	err = WriteArchive(zw, alogs)
+	if err != nil {
+		return xerrors.Errorf("write archive: %w", err)
+	}
+	err = zw.Close()
+	if err != nil {
+		return xerrors.Errorf("write archive: %w", err)
+	}
+	err = file.Close()
+	if err != nil {
+		return xerrors.Errorf("close archive: %w", err)
+	}
+
+	name := fmt.Sprintf("audit-logs-%s-%s.jsonl.gz",
+		alogs[0].Time.UTC().Format(archiveTimeFormat),
+		alogs[len(alogs)-1].Time.UTC().Format(archiveTimeFormat),
+	)
+	err = os.Rename(file.Name(), filepath.Join(a.dir, name))
+	if err != nil {
+		return xerrors.Errorf("rename archive: %w", err)
+	}
+	return nil
+}
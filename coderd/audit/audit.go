@@ -17,6 +17,15 @@ type AdditionalFields struct {
 	BuildNumber    string               `json:"build_number"`
 	BuildReason    database.BuildReason `json:"build_reason"`
 	WorkspaceOwner string               `json:"workspace_owner"`
+
+	// The following fields are set for connect and disconnect events.
+	ConnectionType string `json:"connection_type,omitempty"`
+	AgentName      string `json:"agent_name,omitempty"`
+	AppSlug        string `json:"app_slug,omitempty"`
+	Port           uint16 `json:"port,omitempty"`
+	// DurationMS is how long the connection was open, set for disconnect
+	// events.
+	DurationMS int64 `json:"duration_ms,omitempty"`
 }
 
 func NewNop() Auditor {
@@ -0,0 +1,422 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	// verifyBatchSize is the number of hashes verified at once.
+	verifyBatchSize = 1000
+	// maxVerificationIssues is the number of issues listed by VerifyChain.
+	maxVerificationIssues = 100
+)
+
+// HashLog returns the hash of an audit log in the hash chain. It covers the
+// hash of the previous audit log, the time and resource type of the audit log
+// and the hash of its content, so modifying any of them changes the hash. The
+// time, resource type and content hash are kept when the audit log is deleted
+// by the retention policy, so pruned audit logs can still be verified.
+func HashLog(prevHash []byte, alog database.AuditLog) []byte {
+	return chainHash(prevHash, alog.Time, alog.ResourceType, ContentHash(alog))
+}
+
+// ContentHash returns the hash of every column of an audit log.
+func ContentHash(alog database.AuditLog) []byte {
+	h := sha256.New()
+	writeContent(lengthPrefixed(h), alog)
+	return h.Sum(nil)
+}
+
+func chainHash(prevHash []byte, t time.Time, resourceType database.ResourceType, contentHash []byte) []byte {
+	h := sha256.New()
+	write := lengthPrefixed(h)
+	write(prevHash)
+	write([]byte(t.UTC().Format(time.RFC3339Nano)))
+	write([]byte(resourceType))
+	write(contentHash)
+	return h.Sum(nil)
+}
+
+// legacyHashLog returns the hash of audit logs chained before content hashes
+// were kept. It covers the hash of the previous audit log and the content.
+func legacyHashLog(prevHash []byte, alog database.AuditLog) []byte {
+	h := sha256.New()
+	write := lengthPrefixed(h)
+	write(prevHash)
+	writeContent(write, alog)
+	return h.Sum(nil)
+}
+
+// lengthPrefixed writes fields prefixed with their length, so content can't be
+// moved from one field to the next without changing the hash.
+func lengthPrefixed(w io.Writer) func([]byte) {
+	return func(b []byte) {
+		_ = binary.Write(w, binary.BigEndian, uint64(len(b)))
+		_, _ = w.Write(b)
+	}
+}
+
+func writeContent(write func([]byte), alog database.AuditLog) {
+	write(alog.ID[:])
+	write([]byte(alog.Time.UTC().Format(time.RFC3339Nano)))
+	write(alog.UserID[:])
+	write(alog.OrganizationID[:])
+	if alog.Ip.Valid {
+		write([]byte(alog.Ip.IPNet.String()))
+	} else {
+		write(nil)
+	}
+	write([]byte(strconv.FormatBool(alog.UserAgent.Valid)))
+	write([]byte(alog.UserAgent.String))
+	write([]byte(alog.ResourceType))
+	write(alog.ResourceID[:])
+	write([]byte(alog.ResourceTarget))
+	write([]byte(alog.Action))
+	write(alog.Diff)
+	write([]byte(strconv.FormatInt(int64(alog.StatusCode), 10)))
+	write(alog.AdditionalFields)
+	write(alog.RequestID[:])
+	write([]byte(alog.ResourceIcon))
+}
+
+// InsertLog inserts an audit log and appends it to the hash chain.
+func InsertLog(ctx context.Context, db database.Store, params database.InsertAuditLogParams) (database.AuditLog, error) {
+	var alog database.AuditLog
+	err := db.InTx(func(tx database.Store) error {
+		// Audit logs are appended to the chain one at a time.
+		err := tx.AcquireLock(ctx, database.LockIDAuditLogChain)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		prev, err := tx.GetLatestAuditLogHash(ctx)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get latest audit log hash: %w", err)
+		}
+		// The first audit log of the chain has an empty previous hash.
+		prevHash := prev.Hash
+		if prevHash == nil {
+			prevHash = []byte{}
+		}
+
+		// The hash is taken from the inserted row, as the database may
+		// normalize columns like diff.
+		alog, err = tx.InsertAuditLog(ctx, params)
+		if err != nil {
+			return xerrors.Errorf("insert audit log: %w", err)
+		}
+		_, err = tx.InsertAuditLogHash(ctx, database.InsertAuditLogHashParams{
+			Sequence:   prev.Sequence + 1,
+			AuditLogID: alog.ID,
+			PrevHash:   prevHash,
+			Hash:       HashLog(prevHash, alog),
+			CreatedAt:  database.Now(),
+			// The time, resource type and content hash are kept after the
+			// audit log is deleted, to verify it was past its retention
+			// period.
+			AuditLogTime: sql.NullTime{Time: alog.Time, Valid: true},
+			ResourceType: database.NullResourceType{ResourceType: alog.ResourceType, Valid: true},
+			ContentHash:  ContentHash(alog),
+		})
+		if err != nil {
+			return xerrors.Errorf("insert audit log hash: %w", err)
+		}
+		return nil
+	}, nil)
+	return alog, err
+}
+
+// VerifyChain walks the audit log hash chain and reports audit logs that were
+// modified, deleted or added without being chained. Checkpoint signatures are
+// verified with publicKey, unless it's nil. If it's set, audit logs must also
+// be covered by a checkpoint within CheckpointInterval of being chained.
+//
+// retention returns the retention period of a resource type, or zero if audit
+// logs of the type are kept forever. Pruned audit logs must have been past
+// their retention period when they were deleted, and no older audit log of the
+// same resource type may be kept.
+func VerifyChain(ctx context.Context, db database.Store, publicKey ed25519.PublicKey, retention func(database.ResourceType) time.Duration, now time.Time) (codersdk.AuditLogVerification, error) {
+	v := &verifier{
+		result: codersdk.AuditLogVerification{
+			Issues: []codersdk.AuditLogVerificationIssue{},
+		},
+	}
+	if publicKey != nil {
+		v.result.PublicKey = hex.EncodeToString(publicKey)
+	}
+
+	// Audit logs appended while verifying are not verified.
+	head, err := db.GetLatestAuditLogHash(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.AuditLogVerification{}, xerrors.Errorf("get latest audit log hash: %w", err)
+	}
+	v.result.LastSequence = head.Sequence
+
+	checkpoints, err := db.GetAuditLogCheckpoints(ctx)
+	if err != nil {
+		return codersdk.AuditLogVerification{}, xerrors.Errorf("get audit log checkpoints: %w", err)
+	}
+	checkpointHashes := make(map[int64][]byte, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		if publicKey != nil && !ed25519.Verify(publicKey, checkpointMessage(checkpoint.Sequence, checkpoint.Hash), checkpoint.Signature) {
+			v.issue(codersdk.AuditLogVerificationIssueCheckpoint, checkpoint.Sequence, uuid.Nil,
+				"The signature of the checkpoint is invalid.")
+			continue
+		}
+		if checkpoint.Sequence > head.Sequence {
+			v.issue(codersdk.AuditLogVerificationIssueTruncated, checkpoint.Sequence, uuid.Nil,
+				fmt.Sprintf("The chain ends at sequence %d, but was signed up to sequence %d.", head.Sequence, checkpoint.Sequence))
+			continue
+		}
+		checkpointHashes[checkpoint.Sequence] = checkpoint.Hash
+	}
+
+	var (
+		prev      database.AuditLogHash
+		firstTime time.Time
+		// oldestKept is the time of the oldest audit log that is kept, by
+		// resource type.
+		oldestKept = map[database.ResourceType]time.Time{}
+		// invalidPruned are the sequences of pruned audit logs that were
+		// already reported.
+		invalidPruned = map[int64]struct{}{}
+		// unsigned is the first hash after the last checkpoint that is
+		// older than CheckpointInterval.
+		unsigned database.AuditLogHash
+		// contentHashed is set once an audit log with a content hash is
+		// chained. Every later audit log must have one.
+		contentHashed bool
+	)
+	for prev.Sequence < head.Sequence {
+		hashes, err := db.GetAuditLogHashes(ctx, database.GetAuditLogHashesParams{
+			AfterSequence: prev.Sequence,
+			MaxSequence:   head.Sequence,
+			LimitCount:    verifyBatchSize,
+		})
+		if err != nil {
+			return codersdk.AuditLogVerification{}, xerrors.Errorf("get audit log hashes: %w", err)
+		}
+		if len(hashes) == 0 {
+			break
+		}
+		// Pruned audit logs are fetched too, as they must not exist.
+		ids := make([]uuid.UUID, 0, len(hashes))
+		for _, hash := range hashes {
+			ids = append(ids, hash.AuditLogID)
+		}
+		alogs, err := db.GetAuditLogsByIDs(ctx, ids)
+		if err != nil {
+			return codersdk.AuditLogVerification{}, xerrors.Errorf("get audit logs: %w", err)
+		}
+		alogsByID := make(map[uuid.UUID]database.AuditLog, len(alogs))
+		for _, alog := range alogs {
+			alogsByID[alog.ID] = alog
+		}
+
+		for _, hash := range hashes {
+			switch {
+			case hash.Sequence != prev.Sequence+1:
+				v.issue(codersdk.AuditLogVerificationIssueGap, hash.Sequence, hash.AuditLogID,
+					fmt.Sprintf("Sequences %d to %d are missing from the chain.", prev.Sequence+1, hash.Sequence-1))
+			case !bytes.Equal(hash.PrevHash, prev.Hash):
+				v.issue(codersdk.AuditLogVerificationIssueBrokenLink, hash.Sequence, hash.AuditLogID,
+					"The previous hash doesn't match the hash of the previous audit log.")
+			}
+
+			alog, exists := alogsByID[hash.AuditLogID]
+			if exists {
+				if kept, ok := oldestKept[alog.ResourceType]; !ok || alog.Time.Before(kept) {
+					oldestKept[alog.ResourceType] = alog.Time
+				}
+			}
+			legacy := hash.ContentHash == nil
+			if !legacy {
+				contentHashed = true
+			}
+			switch {
+			case legacy && contentHashed:
+				v.issue(codersdk.AuditLogVerificationIssueModified, hash.Sequence, hash.AuditLogID,
+					"The content hash kept with the hash was removed.")
+			case !legacy && (!hash.AuditLogTime.Valid || !hash.ResourceType.Valid ||
+				!bytes.Equal(chainHash(hash.PrevHash, hash.AuditLogTime.Time, hash.ResourceType.ResourceType, hash.ContentHash), hash.Hash)):
+				v.issue(codersdk.AuditLogVerificationIssueModified, hash.Sequence, hash.AuditLogID,
+					"The time, resource type or content hash kept with the hash doesn't match it.")
+			case hash.PrunedAt.Valid:
+				detail := verifyPruned(hash, exists, retention, now)
+				if detail != "" {
+					invalidPruned[hash.Sequence] = struct{}{}
+					v.issue(codersdk.AuditLogVerificationIssuePruned, hash.Sequence, hash.AuditLogID, detail)
+				} else {
+					v.result.Pruned++
+				}
+			case !exists:
+				v.issue(codersdk.AuditLogVerificationIssueMissing, hash.Sequence, hash.AuditLogID,
+					"The audit log was deleted.")
+			case legacy && !bytes.Equal(legacyHashLog(hash.PrevHash, alog), hash.Hash),
+				!legacy && !bytes.Equal(HashLog(hash.PrevHash, alog), hash.Hash):
+				v.issue(codersdk.AuditLogVerificationIssueModified, hash.Sequence, hash.AuditLogID,
+					"The audit log doesn't match its hash.")
+			case (hash.AuditLogTime.Valid && !hash.AuditLogTime.Time.Equal(alog.Time)) ||
+				(hash.ResourceType.Valid && hash.ResourceType.ResourceType != alog.ResourceType):
+				v.issue(codersdk.AuditLogVerificationIssueModified, hash.Sequence, hash.AuditLogID,
+					"The time or resource type kept with the hash doesn't match the audit log.")
+			default:
+				v.result.Verified++
+				if firstTime.IsZero() {
+					firstTime = alog.Time
+				}
+			}
+
+			signed := false
+			if checkpointHash, ok := checkpointHashes[hash.Sequence]; ok {
+				delete(checkpointHashes, hash.Sequence)
+				if bytes.Equal(checkpointHash, hash.Hash) {
+					v.result.Checkpoints++
+					signed = true
+				} else {
+					v.issue(codersdk.AuditLogVerificationIssueCheckpoint, hash.Sequence, hash.AuditLogID,
+						"The hash doesn't match the signed checkpoint.")
+				}
+			}
+			switch {
+			case signed:
+				unsigned = database.AuditLogHash{}
+			case unsigned.Sequence == 0 && hash.CreatedAt.Before(now.Add(-CheckpointInterval)):
+				unsigned = hash
+			}
+			prev = hash
+		}
+	}
+	if prev.Sequence < head.Sequence {
+		v.issue(codersdk.AuditLogVerificationIssueGap, head.Sequence, uuid.Nil,
+			fmt.Sprintf("Sequences %d to %d are missing from the chain.", prev.Sequence+1, head.Sequence))
+	}
+	// The retention policy deletes the oldest audit logs of a resource type
+	// first, so pruned audit logs must be older than every audit log kept.
+	resourceTypes := maps.Keys(oldestKept)
+	slices.Sort(resourceTypes)
+	for _, resourceType := range resourceTypes {
+		pruned, err := db.GetPrunedAuditLogHashesAfter(ctx, database.GetPrunedAuditLogHashesAfterParams{
+			ResourceType: resourceType,
+			After:        oldestKept[resourceType],
+			MaxSequence:  head.Sequence,
+			LimitCount:   maxVerificationIssues + 1,
+		})
+		if err != nil {
+			return codersdk.AuditLogVerification{}, xerrors.Errorf("get pruned audit log hashes: %w", err)
+		}
+		for _, hash := range pruned {
+			if _, ok := invalidPruned[hash.Sequence]; ok {
+				continue
+			}
+			v.result.Pruned--
+			v.issue(codersdk.AuditLogVerificationIssuePruned, hash.Sequence, hash.AuditLogID,
+				fmt.Sprintf("The audit log was pruned, but an older %s audit log was kept.", resourceType))
+		}
+	}
+	// Checkpoints are signed every CheckpointInterval, so older audit logs
+	// must be covered by one.
+	if publicKey != nil && unsigned.Sequence != 0 {
+		v.issue(codersdk.AuditLogVerificationIssueUnsigned, unsigned.Sequence, unsigned.AuditLogID,
+			fmt.Sprintf("Sequences %d to %d were not signed within %s.", unsigned.Sequence, prev.Sequence, CheckpointInterval))
+	}
+	// Checkpoints of sequences that are missing from the chain.
+	for sequence := range checkpointHashes {
+		v.issue(codersdk.AuditLogVerificationIssueCheckpoint, sequence, uuid.Nil,
+			"The signed checkpoint is missing from the chain.")
+	}
+	for i := len(checkpoints) - 1; i >= 0; i-- {
+		if checkpoints[i].Sequence <= head.Sequence {
+			createdAt := checkpoints[i].CreatedAt
+			v.result.LastCheckpointAt = &createdAt
+			break
+		}
+	}
+
+	// Audit logs older than the chain were created before hash chaining
+	// was introduced. If nothing is chained yet, no audit logs are.
+	chainStart := firstTime
+	if head.Sequence == 0 {
+		chainStart = time.Now().Add(time.Hour)
+	}
+	if !chainStart.IsZero() {
+		v.result.Legacy, err = db.CountUnchainedAuditLogs(ctx, chainStart)
+		if err != nil {
+			return codersdk.AuditLogVerification{}, xerrors.Errorf("count unchained audit logs: %w", err)
+		}
+	}
+	unchained, err := db.GetUnchainedAuditLogs(ctx, database.GetUnchainedAuditLogsParams{
+		After:      chainStart,
+		LimitCount: maxVerificationIssues + 1,
+	})
+	if err != nil {
+		return codersdk.AuditLogVerification{}, xerrors.Errorf("get unchained audit logs: %w", err)
+	}
+	for _, alog := range unchained {
+		v.issue(codersdk.AuditLogVerificationIssueUnchained, 0, alog.ID,
+			"The audit log was added without being appended to the chain.")
+	}
+
+	v.result.Valid = len(v.result.Issues) == 0
+	return v.result, nil
+}
+
+type verifier struct {
+	result codersdk.AuditLogVerification
+}
+
+// verifyPruned returns why a pruned audit log was not deleted by the retention
+// policy, or an empty string if it was.
+func verifyPruned(hash database.AuditLogHash, exists bool, retention func(database.ResourceType) time.Duration, now time.Time) string {
+	if exists {
+		return "The audit log is marked as pruned, but wasn't deleted."
+	}
+	if hash.PrunedAt.Time.After(now) {
+		return "The audit log is marked as pruned in the future."
+	}
+	if !hash.AuditLogTime.Valid || !hash.ResourceType.Valid {
+		return "The time and resource type of the pruned audit log are unknown."
+	}
+	var period time.Duration
+	if retention != nil {
+		period = retention(hash.ResourceType.ResourceType)
+	}
+	if period <= 0 {
+		return fmt.Sprintf("The audit log was pruned, but %s audit logs are kept forever.", hash.ResourceType.ResourceType)
+	}
+	if hash.AuditLogTime.Time.After(hash.PrunedAt.Time.Add(-period)) {
+		return fmt.Sprintf("The audit log was pruned before the end of its retention period of %s.", period)
+	}
+	return ""
+}
+
+func (v *verifier) issue(issueType codersdk.AuditLogVerificationIssueType, sequence int64, auditLogID uuid.UUID, detail string) {
+	if len(v.result.Issues) >= maxVerificationIssues {
+		v.result.IssuesTruncated = true
+		return
+	}
+	v.result.Issues = append(v.result.Issues, codersdk.AuditLogVerificationIssue{
+		Type:       issueType,
+		Sequence:   sequence,
+		AuditLogID: auditLogID,
+		Detail:     detail,
+	})
+}
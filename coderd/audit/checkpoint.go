@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+)
+
+// CheckpointInterval is how often the audit log hash chain is signed.
+const CheckpointInterval = time.Hour
+
+// ParseSigningKey parses a hex encoded Ed25519 seed, as generated by
+// "openssl rand -hex 32".
+func ParseSigningKey(s string) (ed25519.PrivateKey, error) {
+	seed, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, xerrors.Errorf("decode hex: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, xerrors.Errorf("key must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// checkpointMessage returns the message signed by a checkpoint.
+func checkpointMessage(sequence int64, hash []byte) []byte {
+	msg := []byte("coder-audit-log-checkpoint")
+	msg = binary.BigEndian.AppendUint64(msg, uint64(sequence))
+	return append(msg, hash...)
+}
+
+// Checkpoint signs the latest hash of the audit log chain, unless it was
+// already signed. Without checkpoints, the chain could be rewritten or
+// truncated by someone with write access to the database.
+func Checkpoint(ctx context.Context, db database.Store, key ed25519.PrivateKey, now time.Time) error {
+	return db.InTx(func(tx database.Store) error {
+		// Replicas must not sign the same hash concurrently.
+		err := tx.AcquireLock(ctx, database.LockIDAuditLogChain)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		head, err := tx.GetLatestAuditLogHash(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("get latest audit log hash: %w", err)
+		}
+		latest, err := tx.GetLatestAuditLogCheckpoint(ctx)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get latest audit log checkpoint: %w", err)
+		}
+		if latest.Sequence >= head.Sequence {
+			return nil
+		}
+		_, err = tx.InsertAuditLogCheckpoint(ctx, database.InsertAuditLogCheckpointParams{
+			Sequence:  head.Sequence,
+			Hash:      head.Hash,
+			Signature: ed25519.Sign(key, checkpointMessage(head.Sequence, head.Hash)),
+			CreatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert audit log checkpoint: %w", err)
+		}
+		return nil
+	}, nil)
+}
+
+// NewCheckpointer signs the audit log hash chain every CheckpointInterval.
+// It is the caller's responsibility to call Close on the returned instance.
+func NewCheckpointer(ctx context.Context, logger slog.Logger, db database.Store, key ed25519.PrivateKey) io.Closer {
+	closed := make(chan struct{})
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system signs the chain without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	go func() {
+		defer close(closed)
+
+		ticker := time.NewTicker(CheckpointInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := Checkpoint(ctx, db, key, database.Now())
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return
+				}
+				logger.Error(ctx, "failed to sign audit log chain", slog.Error(err))
+			}
+		}
+	}()
+	return &checkpointer{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+type checkpointer struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (c *checkpointer) Close() error {
+	c.cancel()
+	<-c.closed
+	return nil
+}
@@ -18,6 +18,12 @@ type Auditable interface {
 		database.AuditableGroup |
 		database.License |
 		database.WorkspaceProxy |
+		database.Webhook |
+		database.OAuth2ProviderApp |
+		database.OAuth2ProviderAppSecret |
+		database.CustomRole |
+		database.WorkspaceAgent |
+		database.WorkspaceApp |
 		database.AuditOAuthConvertState
 }
 
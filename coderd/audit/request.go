@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
@@ -42,6 +43,24 @@ type Request[T Auditable] struct {
 	Action database.AuditAction
 }
 
+type BackgroundAuditParams[T Auditable] struct {
+	Audit Auditor
+	Log   slog.Logger
+
+	UserID           uuid.UUID
+	OrganizationID   uuid.UUID
+	RequestID        uuid.UUID
+	Time             time.Time
+	Status           int
+	IP               string
+	UserAgent        string
+	Action           database.AuditAction
+	AdditionalFields json.RawMessage
+
+	New T
+	Old T
+}
+
 type BuildAuditParams[T Auditable] struct {
 	Audit Auditor
 	Log   slog.Logger
@@ -84,6 +103,18 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return strconv.Itoa(int(typed.ID))
 	case database.WorkspaceProxy:
 		return typed.Name
+	case database.Webhook:
+		return typed.Name
+	case database.OAuth2ProviderApp:
+		return typed.Name
+	case database.OAuth2ProviderAppSecret:
+		return typed.DisplaySecret
+	case database.CustomRole:
+		return typed.RoleName()
+	case database.WorkspaceAgent:
+		return typed.Name
+	case database.WorkspaceApp:
+		return typed.Slug
 	case database.AuditOAuthConvertState:
 		return string(typed.ToLoginType)
 	default:
@@ -113,6 +144,18 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 		return typed.UUID
 	case database.WorkspaceProxy:
 		return typed.ID
+	case database.Webhook:
+		return typed.ID
+	case database.OAuth2ProviderApp:
+		return typed.ID
+	case database.OAuth2ProviderAppSecret:
+		return typed.ID
+	case database.CustomRole:
+		return typed.ID
+	case database.WorkspaceAgent:
+		return typed.ID
+	case database.WorkspaceApp:
+		return typed.ID
 	case database.AuditOAuthConvertState:
 		// The merge state is for the given user
 		return typed.UserID
@@ -143,6 +186,18 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeLicense
 	case database.WorkspaceProxy:
 		return database.ResourceTypeWorkspaceProxy
+	case database.Webhook:
+		return database.ResourceTypeWebhook
+	case database.OAuth2ProviderApp:
+		return database.ResourceTypeOAuth2ProviderApp
+	case database.OAuth2ProviderAppSecret:
+		return database.ResourceTypeOAuth2ProviderAppSecret
+	case database.CustomRole:
+		return database.ResourceTypeCustomRole
+	case database.WorkspaceAgent:
+		return database.ResourceTypeWorkspaceAgent
+	case database.WorkspaceApp:
+		return database.ResourceTypeWorkspaceApp
 	case database.AuditOAuthConvertState:
 		return database.ResourceTypeConvertLogin
 	default:
@@ -285,6 +340,103 @@ func BuildAudit[T Auditable](ctx context.Context, p *BuildAuditParams[T]) {
 	}
 }
 
+// ExportRequest creates an audit log for one of several resources changed by
+// a single request, such as a bulk workspace action. Unlike InitRequest, the
+// status code is provided per resource. The audit log is committed upon
+// invocation.
+func ExportRequest[T Auditable](ctx context.Context, p *RequestParams, status int, old, new T) {
+	key, ok := httpmw.APIKeyOptional(p.Request)
+	if !ok {
+		return
+	}
+
+	diffRaw := []byte("{}")
+	if status < 400 {
+		diff := Diff(p.Audit, old, new)
+		var err error
+		diffRaw, err = json.Marshal(diff)
+		if err != nil {
+			p.Log.Warn(ctx, "marshal diff", slog.Error(err))
+			diffRaw = []byte("{}")
+		}
+	}
+
+	additionalFields := p.AdditionalFields
+	if additionalFields == nil {
+		additionalFields = json.RawMessage("{}")
+	}
+
+	auditLog := database.AuditLog{
+		ID:               uuid.New(),
+		Time:             database.Now(),
+		UserID:           key.UserID,
+		Ip:               parseIP(p.Request.RemoteAddr),
+		UserAgent:        sql.NullString{String: p.Request.UserAgent(), Valid: true},
+		ResourceType:     either(old, new, ResourceType[T], p.Action),
+		ResourceID:       either(old, new, ResourceID[T], p.Action),
+		ResourceTarget:   either(old, new, ResourceTarget[T], p.Action),
+		Action:           p.Action,
+		Diff:             diffRaw,
+		StatusCode:       int32(status),
+		RequestID:        httpmw.RequestID(p.Request),
+		AdditionalFields: additionalFields,
+	}
+	err := p.Audit.Export(ctx, auditLog)
+	if err != nil {
+		p.Log.Error(ctx, "export audit log",
+			slog.F("audit_log", auditLog),
+			slog.Error(err),
+		)
+	}
+}
+
+// BackgroundAudit creates an audit log for an event that didn't come from an
+// API request, such as a connection to a workspace. The audit log is committed
+// upon invocation.
+func BackgroundAudit[T Auditable](ctx context.Context, p *BackgroundAuditParams[T]) {
+	diffRaw := []byte("{}")
+	diff := Diff(p.Audit, p.Old, p.New)
+	if len(diff) > 0 {
+		var err error
+		diffRaw, err = json.Marshal(diff)
+		if err != nil {
+			p.Log.Warn(ctx, "marshal diff", slog.Error(err))
+			diffRaw = []byte("{}")
+		}
+	}
+
+	if p.AdditionalFields == nil {
+		p.AdditionalFields = json.RawMessage("{}")
+	}
+	if p.Time.IsZero() {
+		p.Time = database.Now()
+	}
+
+	auditLog := database.AuditLog{
+		ID:               uuid.New(),
+		Time:             p.Time,
+		UserID:           p.UserID,
+		OrganizationID:   p.OrganizationID,
+		Ip:               parseIP(p.IP),
+		UserAgent:        sql.NullString{String: p.UserAgent, Valid: p.UserAgent != ""},
+		ResourceType:     either(p.Old, p.New, ResourceType[T], p.Action),
+		ResourceID:       either(p.Old, p.New, ResourceID[T], p.Action),
+		ResourceTarget:   either(p.Old, p.New, ResourceTarget[T], p.Action),
+		Action:           p.Action,
+		Diff:             diffRaw,
+		StatusCode:       int32(p.Status),
+		RequestID:        p.RequestID,
+		AdditionalFields: p.AdditionalFields,
+	}
+	err := p.Audit.Export(ctx, auditLog)
+	if err != nil {
+		p.Log.Error(ctx, "export audit log",
+			slog.F("audit_log", auditLog),
+			slog.Error(err),
+		)
+	}
+}
+
 func either[T Auditable, R any](old, new T, fn func(T) R, auditAction database.AuditAction) R {
 	if ResourceID(new) != uuid.Nil {
 		return fn(new)
@@ -1,19 +1,31 @@
 package coderd_test
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbpurge"
+	"github.com/coder/coder/coderd/database/dbtestutil"
 	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
 )
 
 func TestAuditLogs(t *testing.T) {
@@ -86,6 +98,410 @@ func TestAuditLogs(t *testing.T) {
 	})
 }
 
+func TestExportAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		resourceIDs := []uuid.UUID{}
+		for i := 3; i > 0; i-- {
+			resourceID := uuid.New()
+			resourceIDs = append(resourceIDs, resourceID)
+			err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+				ResourceID: resourceID,
+				Time:       now.Add(-time.Duration(i) * time.Hour),
+			})
+			require.NoError(t, err)
+		}
+
+		// The range includes from, and excludes to.
+		export, err := client.ExportAuditLogs(ctx, now.Add(-2*time.Hour), now.Add(-time.Hour))
+		require.NoError(t, err)
+		defer export.Close()
+		zr, err := gzip.NewReader(export)
+		require.NoError(t, err)
+		exported := []uuid.UUID{}
+		scanner := bufio.NewScanner(zr)
+		for scanner.Scan() {
+			var alog struct {
+				ResourceID uuid.UUID `json:"resource_id"`
+				UserID     uuid.UUID `json:"user_id"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &alog))
+			require.Equal(t, user.UserID, alog.UserID)
+			exported = append(exported, alog.ResourceID)
+		}
+		require.NoError(t, scanner.Err())
+		require.Equal(t, resourceIDs[1:2], exported)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		_, err := member.ExportAuditLogs(ctx, time.Now().Add(-time.Hour), time.Now())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.ExportAuditLogs(ctx, time.Now(), time.Now().Add(-time.Hour))
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestVerifyAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	// setup creates a deployment with three chained audit logs.
+	setup := func(t *testing.T) (*codersdk.Client, database.Store, ed25519.PrivateKey) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		db, pubsub := dbtestutil.NewDB(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database:           db,
+			Pubsub:             pubsub,
+			AuditLogSigningKey: key,
+			AuditLogRetention: dbpurge.AuditLogRetention{
+				Default: 30 * 24 * time.Hour,
+				ResourceTypes: map[database.ResourceType]time.Duration{
+					database.ResourceTypeTemplate: 0,
+				},
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		for i := 0; i < 3; i++ {
+			err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+				ResourceID: uuid.New(),
+			})
+			require.NoError(t, err)
+		}
+		return client, db, key
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client, db, key := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		// Audit logs older than the chain can't be verified.
+		_ = dbgen.AuditLog(t, db, database.AuditLog{
+			Time: database.Now().Add(-time.Hour),
+		})
+		err := audit.Checkpoint(ctx, db, key, database.Now())
+		require.NoError(t, err)
+		err = client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{})
+		require.NoError(t, err)
+
+		verification, err := client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.True(t, verification.Valid, "issues: %v", verification.Issues)
+		require.Empty(t, verification.Issues)
+		require.Equal(t, int64(4), verification.Verified)
+		require.Equal(t, int64(4), verification.LastSequence)
+		require.Equal(t, int64(1), verification.Legacy)
+		require.Equal(t, int64(1), verification.Checkpoints)
+		require.NotNil(t, verification.LastCheckpointAt)
+		require.Equal(t, hex.EncodeToString(key.Public().(ed25519.PublicKey)), verification.PublicKey)
+	})
+
+	t.Run("Pruned", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		old := createOldAuditLog(ctx, t, client, db, codersdk.ResourceTypeUser, 40*24*time.Hour)
+		err := db.DeleteAuditLogsByIDs(ctx, []uuid.UUID{old.AuditLogID})
+		require.NoError(t, err)
+
+		verification, err := client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.True(t, verification.Valid, "issues: %v", verification.Issues)
+		require.Equal(t, int64(3), verification.Verified)
+		require.Equal(t, int64(1), verification.Pruned)
+	})
+
+	t.Run("PrunedWithinRetention", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		first, err := db.GetAuditLogHashes(ctx, database.GetAuditLogHashesParams{
+			MaxSequence: 1,
+			LimitCount:  1,
+		})
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+		err = db.DeleteAuditLogsByIDs(ctx, []uuid.UUID{first[0].AuditLogID})
+		require.NoError(t, err)
+
+		requirePrunedIssue(ctx, t, client, first[0])
+	})
+
+	t.Run("PrunedKeptForever", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		old := createOldAuditLog(ctx, t, client, db, codersdk.ResourceTypeTemplate, 400*24*time.Hour)
+		err := db.DeleteAuditLogsByIDs(ctx, []uuid.UUID{old.AuditLogID})
+		require.NoError(t, err)
+
+		requirePrunedIssue(ctx, t, client, old)
+	})
+
+	t.Run("PrunedNotOldest", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_ = createOldAuditLog(ctx, t, client, db, codersdk.ResourceTypeUser, 50*24*time.Hour)
+		newer := createOldAuditLog(ctx, t, client, db, codersdk.ResourceTypeUser, 40*24*time.Hour)
+		// Only the newer audit log is deleted, while the older one is kept.
+		err := db.DeleteAuditLogsByIDs(ctx, []uuid.UUID{newer.AuditLogID})
+		require.NoError(t, err)
+
+		requirePrunedIssue(ctx, t, client, newer)
+	})
+
+	t.Run("PrunedExists", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		old := createOldAuditLog(ctx, t, client, db, codersdk.ResourceTypeUser, 40*24*time.Hour)
+		alogs, err := db.GetAuditLogsByIDs(ctx, []uuid.UUID{old.AuditLogID})
+		require.NoError(t, err)
+		require.Len(t, alogs, 1)
+		// The audit log is marked as pruned, but restored.
+		err = db.DeleteAuditLogsByIDs(ctx, []uuid.UUID{old.AuditLogID})
+		require.NoError(t, err)
+		_, err = db.InsertAuditLog(ctx, database.InsertAuditLogParams(alogs[0]))
+		require.NoError(t, err)
+
+		requirePrunedIssue(ctx, t, client, old)
+	})
+
+	t.Run("PrunedTimeModified", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		head, err := db.GetLatestAuditLogHash(ctx)
+		require.NoError(t, err)
+		// A recent audit log is deleted, and its time is moved back so it
+		// looks like it was past its retention period.
+		alog := dbgen.AuditLog(t, db, database.AuditLog{
+			ResourceType: database.ResourceTypeUser,
+			Time:         database.Now(),
+		})
+		params := chainParams(head, alog)
+		params.AuditLogTime.Time = alog.Time.Add(-40 * 24 * time.Hour)
+		_, err = db.InsertAuditLogHash(ctx, params)
+		require.NoError(t, err)
+		err = db.DeleteAuditLogsByIDs(ctx, []uuid.UUID{alog.ID})
+		require.NoError(t, err)
+
+		verification, err := client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.False(t, verification.Valid)
+		require.Len(t, verification.Issues, 1, "issues: %v", verification.Issues)
+		require.Equal(t, codersdk.AuditLogVerificationIssueModified, verification.Issues[0].Type)
+		require.Equal(t, head.Sequence+1, verification.Issues[0].Sequence)
+		require.Equal(t, alog.ID, verification.Issues[0].AuditLogID)
+		require.Zero(t, verification.Pruned)
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		t.Parallel()
+		client, db, key := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		head, err := db.GetLatestAuditLogHash(ctx)
+		require.NoError(t, err)
+		// The audit log was chained longer than the checkpoint interval ago,
+		// but no checkpoint covers it.
+		alog := dbgen.AuditLog(t, db, database.AuditLog{})
+		params := chainParams(head, alog)
+		params.CreatedAt = database.Now().Add(-2 * audit.CheckpointInterval)
+		_, err = db.InsertAuditLogHash(ctx, params)
+		require.NoError(t, err)
+
+		verification, err := client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.False(t, verification.Valid)
+		require.Len(t, verification.Issues, 1)
+		require.Equal(t, codersdk.AuditLogVerificationIssueUnsigned, verification.Issues[0].Type)
+		require.Equal(t, head.Sequence+1, verification.Issues[0].Sequence)
+
+		// Signing the chain resolves it.
+		err = audit.Checkpoint(ctx, db, key, database.Now())
+		require.NoError(t, err)
+		verification, err = client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.True(t, verification.Valid, "issues: %v", verification.Issues)
+	})
+
+	t.Run("Unchained", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		alog := dbgen.AuditLog(t, db, database.AuditLog{})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		verification, err := client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.False(t, verification.Valid)
+		require.Len(t, verification.Issues, 1)
+		require.Equal(t, codersdk.AuditLogVerificationIssueUnchained, verification.Issues[0].Type)
+		require.Equal(t, alog.ID, verification.Issues[0].AuditLogID)
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		head, err := db.GetLatestAuditLogHash(ctx)
+		require.NoError(t, err)
+		// The hash was taken before the audit log was modified.
+		alog := dbgen.AuditLog(t, db, database.AuditLog{})
+		original := alog
+		original.ResourceTarget = "original"
+		params := chainParams(head, original)
+		_, err = db.InsertAuditLogHash(ctx, params)
+		require.NoError(t, err)
+
+		verification, err := client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.False(t, verification.Valid)
+		require.Len(t, verification.Issues, 1)
+		require.Equal(t, codersdk.AuditLogVerificationIssueModified, verification.Issues[0].Type)
+		require.Equal(t, head.Sequence+1, verification.Issues[0].Sequence)
+		require.Equal(t, alog.ID, verification.Issues[0].AuditLogID)
+	})
+
+	t.Run("Gap", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		head, err := db.GetLatestAuditLogHash(ctx)
+		require.NoError(t, err)
+		alog := dbgen.AuditLog(t, db, database.AuditLog{})
+		params := chainParams(head, alog)
+		params.Sequence++
+		_, err = db.InsertAuditLogHash(ctx, params)
+		require.NoError(t, err)
+
+		verification, err := client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.False(t, verification.Valid)
+		require.Len(t, verification.Issues, 1)
+		require.Equal(t, codersdk.AuditLogVerificationIssueGap, verification.Issues[0].Type)
+	})
+
+	t.Run("InvalidCheckpoint", func(t *testing.T) {
+		t.Parallel()
+		client, db, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		head, err := db.GetLatestAuditLogHash(ctx)
+		require.NoError(t, err)
+		// Signed with a different key.
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		err = audit.Checkpoint(ctx, db, otherKey, database.Now())
+		require.NoError(t, err)
+
+		verification, err := client.VerifyAuditLogs(ctx)
+		require.NoError(t, err)
+		require.False(t, verification.Valid)
+		require.Len(t, verification.Issues, 1)
+		require.Equal(t, codersdk.AuditLogVerificationIssueCheckpoint, verification.Issues[0].Type)
+		require.Equal(t, head.Sequence, verification.Issues[0].Sequence)
+		require.Zero(t, verification.Checkpoints)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		_, err := member.VerifyAuditLogs(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+// createOldAuditLog appends an audit log of the given age to the chain, and
+// returns its hash.
+func createOldAuditLog(ctx context.Context, t *testing.T, client *codersdk.Client, db database.Store, resourceType codersdk.ResourceType, age time.Duration) database.AuditLogHash {
+	t.Helper()
+
+	err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+		ResourceType: resourceType,
+		Time:         database.Now().Add(-age),
+	})
+	require.NoError(t, err)
+	head, err := db.GetLatestAuditLogHash(ctx)
+	require.NoError(t, err)
+	return head
+}
+
+// chainParams returns the parameters that append an audit log to the chain
+// after head.
+func chainParams(head database.AuditLogHash, alog database.AuditLog) database.InsertAuditLogHashParams {
+	return database.InsertAuditLogHashParams{
+		Sequence:     head.Sequence + 1,
+		AuditLogID:   alog.ID,
+		PrevHash:     head.Hash,
+		Hash:         audit.HashLog(head.Hash, alog),
+		CreatedAt:    database.Now(),
+		AuditLogTime: sql.NullTime{Time: alog.Time, Valid: true},
+		ResourceType: database.NullResourceType{ResourceType: alog.ResourceType, Valid: true},
+		ContentHash:  audit.ContentHash(alog),
+	}
+}
+
+func requirePrunedIssue(ctx context.Context, t *testing.T, client *codersdk.Client, hash database.AuditLogHash) {
+	t.Helper()
+
+	verification, err := client.VerifyAuditLogs(ctx)
+	require.NoError(t, err)
+	require.False(t, verification.Valid)
+	require.Len(t, verification.Issues, 1, "issues: %v", verification.Issues)
+	require.Equal(t, codersdk.AuditLogVerificationIssuePruned, verification.Issues[0].Type)
+	require.Equal(t, hash.Sequence, verification.Issues[0].Sequence)
+	require.Equal(t, hash.AuditLogID, verification.Issues[0].AuditLogID)
+	require.Zero(t, verification.Pruned)
+}
+
 func TestAuditLogsFilter(t *testing.T) {
 	t.Parallel()
 
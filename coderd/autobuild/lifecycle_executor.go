@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/db2sdk"
 	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/prebuilds"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/wsbuilder"
 	"github.com/coder/coder/codersdk"
@@ -123,6 +124,11 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	eg.SetLimit(10)
 
 	for _, ws := range workspaces {
+		if ws.OwnerID == prebuilds.OwnerID {
+			// Prebuilt workspaces are kept running by the prebuilds
+			// reconciler until they are claimed.
+			continue
+		}
 		wsID := ws.ID
 		log := e.log.With(slog.F("workspace_id", wsID))
 
@@ -2,6 +2,7 @@ package coderd
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -45,6 +46,7 @@ import (
 	"github.com/coder/coder/coderd/awsidentity"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/database/dbpurge"
 	"github.com/coder/coder/coderd/database/pubsub"
 	"github.com/coder/coder/coderd/gitauth"
 	"github.com/coder/coder/coderd/gitsshkey"
@@ -52,6 +54,8 @@ import (
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/metricscache"
+	"github.com/coder/coder/coderd/notification"
+	"github.com/coder/coder/coderd/prebuilds"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/schedule"
@@ -59,6 +63,7 @@ import (
 	"github.com/coder/coder/coderd/tracing"
 	"github.com/coder/coder/coderd/updatecheck"
 	"github.com/coder/coder/coderd/util/slice"
+	"github.com/coder/coder/coderd/webhook"
 	"github.com/coder/coder/coderd/workspaceapps"
 	"github.com/coder/coder/coderd/wsconncache"
 	"github.com/coder/coder/codersdk"
@@ -99,6 +104,13 @@ type Options struct {
 	// CacheDir is used for caching files served by the API.
 	CacheDir string
 
+	// AuditLogSigningKey signs checkpoints of the audit log hash chain. The
+	// signatures are not verified if it's nil.
+	AuditLogSigningKey ed25519.PrivateKey
+	// AuditLogRetention is used to verify that audit logs were deleted by
+	// the retention policy.
+	AuditLogRetention dbpurge.AuditLogRetention
+
 	Auditor                        audit.Auditor
 	AgentConnectionUpdateFrequency time.Duration
 	AgentInactiveDisconnectTimeout time.Duration
@@ -108,6 +120,8 @@ type Options struct {
 	GoogleTokenValidator           *idtoken.Validator
 	GithubOAuth2Config             *GithubOAuth2Config
 	OIDCConfig                     *OIDCConfig
+	LDAPConfig                     *LDAPConfig
+	OAuth2LoginProviders           []*OAuth2LoginProvider
 	PrometheusRegistry             *prometheus.Registry
 	SecureAuthCookie               bool
 	StrictTransportSecurityCfg     httpmw.HSTSConfig
@@ -339,6 +353,21 @@ func New(options *Options) *API {
 	if err != nil {
 		panic(xerrors.Errorf("get deployment ID: %w", err))
 	}
+	webhooks := webhook.New(ctx, webhook.Options{
+		Database:   options.Database,
+		Logger:     options.Logger.Named("webhooks"),
+		HTTPClient: options.HTTPClient,
+	})
+	var notificationSender notification.Sender
+	if options.DeploymentValues.Notifications.Email.From != "" {
+		notificationSender = notification.NewSMTPSender(options.DeploymentValues.Notifications.Email)
+	}
+	notifications := notification.New(ctx, notification.Options{
+		Database:  options.Database,
+		Logger:    options.Logger.Named("notifications"),
+		Sender:    notificationSender,
+		AccessURL: options.AccessURL,
+	})
 	api := &API{
 		ctx:          ctx,
 		cancel:       cancel,
@@ -363,11 +392,15 @@ func New(options *Options) *API {
 			options.AppSecurityKey,
 		),
 		metricsCache:                metricsCache,
+		Webhooks:                    webhooks,
+		Notifications:               notifications,
+		PrebuildsMetrics:            prebuilds.NewMetrics(options.PrometheusRegistry),
 		Auditor:                     atomic.Pointer[audit.Auditor]{},
 		TemplateScheduleStore:       options.TemplateScheduleStore,
 		UserQuietHoursScheduleStore: options.UserQuietHoursScheduleStore,
 		Experiments:                 experiments,
 		healthCheckGroup:            &singleflight.Group[string, *healthcheck.Report]{},
+		tailnetClients:              newTailnetClients(),
 	}
 	if options.UpdateCheckOptions != nil {
 		api.updateChecker = updatecheck.New(
@@ -385,7 +418,8 @@ func New(options *Options) *API {
 	api.Auditor.Store(&options.Auditor)
 	api.TailnetCoordinator.Store(&options.TailnetCoordinator)
 	if api.Experiments.Enabled(codersdk.ExperimentSingleTailnet) {
-		api.agentProvider, err = NewServerTailnet(api.ctx,
+		var serverTailnet *ServerTailnet
+		serverTailnet, err = NewServerTailnet(api.ctx,
 			options.Logger,
 			options.DERPServer,
 			options.DERPMap,
@@ -397,6 +431,10 @@ func New(options *Options) *API {
 		if err != nil {
 			panic("failed to setup server tailnet: " + err.Error())
 		}
+		for _, prefix := range serverTailnet.conn.Addresses() {
+			api.tailnetClients.addServer(prefix.Addr())
+		}
+		api.agentProvider = serverTailnet
 	} else {
 		api.agentProvider = &wsconncache.AgentProvider{
 			Cache: wsconncache.New(api._dialWorkspaceAgentTailnet, 0),
@@ -418,6 +456,8 @@ func New(options *Options) *API {
 
 		DisablePathApps:  options.DeploymentValues.DisablePathApps.Value(),
 		SecureAuthCookie: options.DeploymentValues.SecureAuthCookie.Value(),
+
+		ReportConnection: api.AuditConnection,
 	}
 
 	apiKeyMiddleware := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -561,6 +601,8 @@ func New(options *Options) *API {
 			)
 
 			r.Get("/", api.auditLogs)
+			r.Get("/export", api.exportAuditLogs)
+			r.Get("/verify", api.verifyAuditLogs)
 			r.Post("/testgenerate", api.generateFakeAuditLog)
 		})
 		r.Route("/files", func(r chi.Router) {
@@ -603,6 +645,10 @@ func New(options *Options) *API {
 						})
 					})
 				})
+				r.Route("/roles", func(r chi.Router) {
+					r.Get("/", api.customOrganizationRoles)
+					r.Post("/", api.postCustomOrganizationRole)
+				})
 				r.Route("/members", func(r chi.Router) {
 					r.Get("/roles", api.assignableOrgRoles)
 					r.Route("/{user}", func(r chi.Router) {
@@ -616,6 +662,21 @@ func New(options *Options) *API {
 				})
 			})
 		})
+		r.Route("/roles", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+			r.Get("/", api.customSiteRoles)
+			r.Post("/", api.postCustomSiteRole)
+			r.Route("/{role}", func(r chi.Router) {
+				r.Use(
+					httpmw.ExtractCustomRoleParam(options.Database),
+				)
+				r.Get("/", api.customRole)
+				r.Patch("/", api.patchCustomRole)
+				r.Delete("/", api.deleteCustomRole)
+			})
+		})
 		r.Route("/templates/{template}", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -646,6 +707,7 @@ func New(options *Options) *API {
 			r.Get("/rich-parameters", api.templateVersionRichParameters)
 			r.Get("/gitauth", api.templateVersionGitAuth)
 			r.Get("/variables", api.templateVersionVariables)
+			r.Get("/diff/{othertemplateversion}", api.templateVersionDiff)
 			r.Get("/resources", api.templateVersionResources)
 			r.Get("/logs", api.templateVersionLogs)
 			r.Route("/dry-run", func(r chi.Router) {
@@ -669,6 +731,8 @@ func New(options *Options) *API {
 				// This value is intentionally increased during tests.
 				r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
 				r.Post("/login", api.postLogin)
+				r.Post("/ldap/login", api.postLoginLDAP)
+				r.Post("/login/totp", api.postLoginTOTP)
 				r.Route("/oauth2", func(r chi.Router) {
 					r.Route("/github", func(r chi.Router) {
 						r.Use(
@@ -677,6 +741,15 @@ func New(options *Options) *API {
 						)
 						r.Get("/callback", api.userOAuth2Github)
 					})
+					for _, provider := range options.OAuth2LoginProviders {
+						r.Route("/"+provider.ID, func(r chi.Router) {
+							r.Use(
+								httpmw.ExtractOAuth2(provider, options.HTTPClient, nil),
+								apiKeyMiddlewareOptional,
+							)
+							r.Get("/callback", api.userOAuth2LoginProvider(provider))
+						})
+					}
 				})
 				r.Route("/oidc/callback", func(r chi.Router) {
 					r.Use(
@@ -741,6 +814,24 @@ func New(options *Options) *API {
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.Route("/mfa", func(r chi.Router) {
+						r.Get("/", api.userMFA)
+						r.Delete("/", api.deleteUserMFA)
+						r.Post("/totp", api.postUserTOTP)
+						r.Post("/totp/verify", api.postUserTOTPVerify)
+						r.Post("/recovery-codes", api.postUserRecoveryCodes)
+					})
+					r.Route("/notifications/preferences", func(r chi.Router) {
+						r.Get("/", api.userNotificationPreferences)
+						r.Put("/", api.putUserNotificationPreferences)
+					})
+					r.Route("/oauth2-provider/apps", func(r chi.Router) {
+						r.Get("/", api.userOAuth2ProviderApps)
+						r.Route("/{app}", func(r chi.Router) {
+							r.Use(httpmw.ExtractOAuth2ProviderAppParam(options.Database))
+							r.Delete("/", api.deleteUserOAuth2ProviderApp)
+						})
+					})
 				})
 			})
 		})
@@ -767,12 +858,15 @@ func New(options *Options) *API {
 				r.Get("/metadata", api.workspaceAgentManifest)
 				r.Post("/startup", api.postWorkspaceAgentStartup)
 				r.Patch("/startup-logs", api.patchWorkspaceAgentStartupLogs)
+				r.Post("/session-recordings", api.postWorkspaceAgentSessionRecording)
 				r.Post("/app-health", api.postWorkspaceAppHealth)
 				r.Get("/gitauth", api.workspaceAgentsGitAuth)
 				r.Get("/gitsshkey", api.agentGitSSHKey)
 				r.Get("/coordinate", api.workspaceAgentCoordinate)
 				r.Post("/report-stats", api.workspaceAgentReportStats)
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
+				r.Post("/report-script-status", api.workspaceAgentReportScriptStatus)
+				r.Post("/report-connection", api.workspaceAgentReportConnection)
 				r.Post("/metadata/{key}", api.workspaceAgentPostMetadata)
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
@@ -792,6 +886,10 @@ func New(options *Options) *API {
 				r.Get("/watch-metadata", api.watchWorkspaceAgentMetadata)
 				r.Get("/startup-logs", api.workspaceAgentStartupLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Route("/session-recordings", func(r chi.Router) {
+					r.Get("/", api.workspaceAgentSessionRecordings)
+					r.Get("/{recording}", api.workspaceAgentSessionRecording)
+				})
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
@@ -803,6 +901,7 @@ func New(options *Options) *API {
 				apiKeyMiddleware,
 			)
 			r.Get("/", api.workspaces)
+			r.Post("/bulk", api.postWorkspacesBulk)
 			r.Route("/{workspace}", func(r chi.Router) {
 				r.Use(
 					httpmw.ExtractWorkspaceParam(options.Database),
@@ -822,8 +921,48 @@ func New(options *Options) *API {
 				r.Get("/watch", api.watchWorkspace)
 				r.Put("/extend", api.putExtendWorkspace)
 				r.Put("/lock", api.putWorkspaceLock)
+				r.Put("/owner", api.putWorkspaceOwner)
+			})
+		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.webhooks)
+			r.Post("/", api.postWebhook)
+			r.Route("/{webhook}", func(r chi.Router) {
+				r.Use(httpmw.ExtractWebhookParam(options.Database))
+				r.Get("/", api.webhook)
+				r.Patch("/", api.patchWebhook)
+				r.Delete("/", api.deleteWebhook)
+				r.Get("/deliveries", api.webhookDeliveries)
 			})
 		})
+		r.Route("/oauth2-provider/apps", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.oAuth2ProviderApps)
+			r.Post("/", api.postOAuth2ProviderApp)
+			r.Route("/{app}", func(r chi.Router) {
+				r.Use(httpmw.ExtractOAuth2ProviderAppParam(options.Database))
+				r.Get("/", api.oAuth2ProviderApp)
+				r.Put("/", api.putOAuth2ProviderApp)
+				r.Delete("/", api.deleteOAuth2ProviderApp)
+				r.Route("/secrets", func(r chi.Router) {
+					r.Get("/", api.oAuth2ProviderAppSecrets)
+					r.Post("/", api.postOAuth2ProviderAppSecret)
+					r.Route("/{secretID}", func(r chi.Router) {
+						r.Use(httpmw.ExtractOAuth2ProviderAppSecretParam(options.Database))
+						r.Delete("/", api.deleteOAuth2ProviderAppSecret)
+					})
+				})
+			})
+		})
+		r.Route("/oauth2", func(r chi.Router) {
+			r.With(apiKeyMiddleware).Route("/authorize", func(r chi.Router) {
+				r.Get("/", api.getOAuth2ProviderAppAuthorize)
+				r.Post("/", api.postOAuth2ProviderAppAuthorize)
+			})
+			// Apps authenticate with their client secret.
+			r.Post("/tokens", api.postOAuth2ProviderAppToken)
+		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -968,11 +1107,20 @@ type API struct {
 	WebsocketWaitGroup sync.WaitGroup
 	derpCloseFunc      func()
 
+	// Webhooks delivers events to the webhooks registered by admins.
+	Webhooks *webhook.Dispatcher
+	// Notifications sends email notifications to users.
+	Notifications *notification.Notifier
+	// PrebuildsMetrics are shared by the prebuilt workspace reconciler and
+	// workspace creation.
+	PrebuildsMetrics *prebuilds.Metrics
+
 	metricsCache          *metricscache.Cache
 	updateChecker         *updatecheck.Checker
 	WorkspaceAppsProvider workspaceapps.SignedTokenProvider
 	workspaceAppServer    *workspaceapps.Server
 	agentProvider         workspaceapps.AgentProvider
+	tailnetClients        *tailnetClients
 
 	// Experiments contains the list of experiments currently enabled.
 	// This is used to gate features that are not yet ready for production.
@@ -991,7 +1139,10 @@ func (api *API) Close() error {
 	api.WebsocketWaitGroup.Wait()
 	api.WebsocketWaitMutex.Unlock()
 
+	api.workspaceAppServer.CloseAppSessions()
 	api.metricsCache.Close()
+	_ = api.Webhooks.Close()
+	_ = api.Notifications.Close()
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
@@ -1071,6 +1222,8 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 		Provisioners:                daemon.Provisioners,
 		GitAuthConfigs:              api.GitAuthConfigs,
 		Telemetry:                   api.Telemetry,
+		Webhooks:                    api.Webhooks,
+		Notifications:               api.Notifications,
 		Tracer:                      tracer,
 		Tags:                        tags,
 		QuotaCommitter:              &api.QuotaCommitter,
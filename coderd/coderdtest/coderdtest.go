@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -58,6 +59,7 @@ import (
 	"github.com/coder/coder/coderd/awsidentity"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/database/dbpurge"
 	"github.com/coder/coder/coderd/database/dbtestutil"
 	"github.com/coder/coder/coderd/database/pubsub"
 	"github.com/coder/coder/coderd/gitauth"
@@ -65,6 +67,7 @@ import (
 	"github.com/coder/coder/coderd/healthcheck"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/prebuilds"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
@@ -100,11 +103,18 @@ type Options struct {
 	GithubOAuth2Config    *coderd.GithubOAuth2Config
 	RealIPConfig          *httpmw.RealIPConfig
 	OIDCConfig            *coderd.OIDCConfig
+	LDAPConfig            *coderd.LDAPConfig
+	OAuth2LoginProviders  []*coderd.OAuth2LoginProvider
 	GoogleTokenValidator  *idtoken.Validator
 	SSHKeygenAlgorithm    gitsshkey.Algorithm
 	AutobuildTicker       <-chan time.Time
 	AutobuildStats        chan<- autobuild.Stats
+	// PrebuildsTicker runs the prebuilt workspace reconciler if set.
+	PrebuildsTicker <-chan time.Time
+	PrebuildsStats  chan<- prebuilds.Stats
 	Auditor               audit.Auditor
+	AuditLogSigningKey    ed25519.PrivateKey
+	AuditLogRetention     dbpurge.AuditLogRetention
 	TLSCertificates       []tls.Certificate
 	GitAuthConfigs        []*gitauth.Config
 	TrialGenerator        func(context.Context, string) error
@@ -368,11 +378,15 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			GitAuthConfigs:                 options.GitAuthConfigs,
 
 			Auditor:                     options.Auditor,
+			AuditLogSigningKey:          options.AuditLogSigningKey,
+			AuditLogRetention:           options.AuditLogRetention,
 			AWSCertificates:             options.AWSCertificates,
 			AzureCertificates:           options.AzureCertificates,
 			GithubOAuth2Config:          options.GithubOAuth2Config,
 			RealIPConfig:                options.RealIPConfig,
 			OIDCConfig:                  options.OIDCConfig,
+			LDAPConfig:                  options.LDAPConfig,
+			OAuth2LoginProviders:        options.OAuth2LoginProviders,
 			GoogleTokenValidator:        options.GoogleTokenValidator,
 			SSHKeygenAlgorithm:          options.SSHKeygenAlgorithm,
 			DERPServer:                  derpServer,
@@ -410,6 +424,22 @@ func NewWithAPI(t testing.TB, options *Options) (*codersdk.Client, io.Closer, *c
 	// We set the handler after server creation for the access URL.
 	coderAPI := coderd.New(newOptions)
 	setHandler(coderAPI.RootHandler)
+	if options.PrebuildsTicker != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		if options.PrebuildsStats != nil {
+			t.Cleanup(func() {
+				close(options.PrebuildsStats)
+			})
+		}
+		prebuilds.NewReconciler(
+			ctx,
+			coderAPI.Database,
+			coderAPI.PrebuildsMetrics,
+			slogtest.Make(t, nil).Named("prebuilds.reconciler").Leveled(slog.LevelDebug),
+			options.PrebuildsTicker,
+		).WithStatsChannel(options.PrebuildsStats).Run()
+	}
 	var provisionerCloser io.Closer = nopcloser{}
 	if options.IncludeProvisionerDaemon {
 		provisionerCloser = NewProvisionerDaemon(t, coderAPI)
@@ -0,0 +1,371 @@
+// Package ldaptest provides an in-process LDAP server for tests. It speaks
+// enough of the protocol for simple binds, searches with equality and
+// presence filters, and StartTLS.
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/testutil"
+)
+
+const (
+	appBindRequest        = 0
+	appBindResponse       = 1
+	appUnbindRequest      = 2
+	appSearchRequest      = 3
+	appSearchResultEntry  = 4
+	appSearchResultDone   = 5
+	appExtendedRequest    = 23
+	appExtendedResponse   = 24
+	filterAnd             = 0
+	filterOr              = 1
+	filterNot             = 2
+	filterEqualityMatch   = 3
+	filterPresent         = 7
+	resultSuccess         = 0
+	resultProtocolError   = 2
+	resultSizeLimit       = 4
+	resultAuthUnsupported = 7
+	resultInvalidCreds    = 49
+	resultUnwilling       = 53
+	oidStartTLS           = "1.3.6.1.4.1.1466.20037"
+)
+
+// Entry is an object in the directory. Entries with a password can be bound
+// as.
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Mode selects how clients connect to the server.
+type Mode int
+
+const (
+	// ModePlain serves ldap:// without TLS.
+	ModePlain Mode = iota
+	// ModeStartTLS serves ldap:// and accepts the StartTLS extended
+	// operation.
+	ModeStartTLS
+	// ModeLDAPS serves ldaps://.
+	ModeLDAPS
+)
+
+type Server struct {
+	// URL is the address clients connect to.
+	URL string
+	// RootCAs trusts the certificate of the server in ModeStartTLS and
+	// ModeLDAPS.
+	RootCAs *x509.CertPool
+
+	mu      sync.Mutex
+	entries []Entry
+	binds   []string
+}
+
+// New starts a server with the given entries. It is closed when the test
+// ends.
+func New(t testing.TB, mode Mode, entries ...Entry) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &Server{
+		URL:     "ldap://" + listener.Addr().String(),
+		entries: entries,
+	}
+	var tlsConfig *tls.Config
+	if mode != ModePlain {
+		cert := testutil.GenerateTLSCertificate(t, "localhost")
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		srv.RootCAs = x509.NewCertPool()
+		srv.RootCAs.AddCert(leaf)
+		tlsConfig = &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		}
+	}
+	if mode == ModeLDAPS {
+		listener = tls.NewListener(listener, tlsConfig)
+		srv.URL = "ldaps://" + listener.Addr().String()
+	}
+
+	var (
+		wg     sync.WaitGroup
+		connMu sync.Mutex
+		conns  = map[net.Conn]struct{}{}
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			connMu.Lock()
+			conns[conn] = struct{}{}
+			connMu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				srv.serve(conn, mode, tlsConfig)
+				connMu.Lock()
+				delete(conns, conn)
+				connMu.Unlock()
+			}()
+		}
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+		connMu.Lock()
+		for conn := range conns {
+			_ = conn.Close()
+		}
+		connMu.Unlock()
+		wg.Wait()
+	})
+	return srv
+}
+
+// Binds returns the DNs that successfully bound to the server, in order.
+func (s *Server) Binds() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.binds...)
+}
+
+// SetEntries replaces the entries of the directory.
+func (s *Server) SetEntries(entries ...Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = entries
+}
+
+func (s *Server) serve(conn net.Conn, mode Mode, tlsConfig *tls.Config) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		packet, err := ber.ReadPacket(reader)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) < 2 {
+			return
+		}
+		messageID, ok := packet.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+		op := packet.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			return
+		}
+
+		switch op.Tag {
+		case appBindRequest:
+			code := s.bind(op)
+			err = writeResult(conn, messageID, appBindResponse, code)
+		case appSearchRequest:
+			err = s.search(conn, messageID, op)
+		case appExtendedRequest:
+			name := ""
+			if len(op.Children) > 0 {
+				name = op.Children[0].Data.String()
+			}
+			if name != oidStartTLS || mode != ModeStartTLS {
+				err = writeResult(conn, messageID, appExtendedResponse, resultProtocolError)
+				break
+			}
+			err = writeResult(conn, messageID, appExtendedResponse, resultSuccess)
+			if err != nil {
+				return
+			}
+			tlsConn := tls.Server(conn, tlsConfig)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+		case appUnbindRequest:
+			return
+		default:
+			err = writeResult(conn, messageID, appExtendedResponse, resultUnwilling)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) bind(op *ber.Packet) int64 {
+	if len(op.Children) < 3 {
+		return resultProtocolError
+	}
+	dn, _ := op.Children[1].Value.(string)
+	auth := op.Children[2]
+	if auth.ClassType != ber.ClassContext || auth.Tag != 0 {
+		return resultAuthUnsupported
+	}
+	password := auth.Data.String()
+	if dn == "" && password == "" {
+		// Anonymous bind.
+		return resultSuccess
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, entry := range s.entries {
+		if !strings.EqualFold(entry.DN, dn) {
+			continue
+		}
+		if entry.Password == "" || entry.Password != password {
+			return resultInvalidCreds
+		}
+		s.binds = append(s.binds, entry.DN)
+		return resultSuccess
+	}
+	return resultInvalidCreds
+}
+
+func (s *Server) search(conn net.Conn, messageID int64, op *ber.Packet) error {
+	if len(op.Children) < 8 {
+		return writeResult(conn, messageID, appSearchResultDone, resultProtocolError)
+	}
+	baseDN, _ := op.Children[0].Value.(string)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var attributes []string
+	for _, attr := range op.Children[7].Children {
+		name, _ := attr.Value.(string)
+		attributes = append(attributes, name)
+	}
+
+	s.mu.Lock()
+	entries := append([]Entry(nil), s.entries...)
+	s.mu.Unlock()
+
+	sent := int64(0)
+	for _, entry := range entries {
+		if !inBase(entry.DN, baseDN) || !matches(entry, filter) {
+			continue
+		}
+		if sizeLimit > 0 && sent == sizeLimit {
+			return writeResult(conn, messageID, appSearchResultDone, resultSizeLimit)
+		}
+		err := writeEntry(conn, messageID, entry, attributes)
+		if err != nil {
+			return err
+		}
+		sent++
+	}
+	return writeResult(conn, messageID, appSearchResultDone, resultSuccess)
+}
+
+func inBase(dn, baseDN string) bool {
+	if baseDN == "" {
+		return true
+	}
+	dn, baseDN = strings.ToLower(dn), strings.ToLower(baseDN)
+	return dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+}
+
+func matches(entry Entry, filter *ber.Packet) bool {
+	if filter.ClassType != ber.ClassContext {
+		return false
+	}
+	switch filter.Tag {
+	case filterAnd:
+		for _, child := range filter.Children {
+			if !matches(entry, child) {
+				return false
+			}
+		}
+		return true
+	case filterOr:
+		for _, child := range filter.Children {
+			if matches(entry, child) {
+				return true
+			}
+		}
+		return false
+	case filterNot:
+		return len(filter.Children) == 1 && !matches(entry, filter.Children[0])
+	case filterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name, _ := filter.Children[0].Value.(string)
+		value, _ := filter.Children[1].Value.(string)
+		for _, v := range attribute(entry, name) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case filterPresent:
+		return len(attribute(entry, filter.Data.String())) > 0
+	default:
+		return false
+	}
+}
+
+func attribute(entry Entry, name string) []string {
+	for key, values := range entry.Attributes {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func writeEntry(conn net.Conn, messageID int64, entry Entry, attributes []string) error {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, appSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "Object Name"))
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, name := range attributes {
+		values := attribute(entry, name)
+		if len(values) == 0 {
+			continue
+		}
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attr.AppendChild(set)
+		attrs.AppendChild(attr)
+	}
+	op.AppendChild(attrs)
+	return writeMessage(conn, messageID, op)
+}
+
+func writeResult(conn net.Conn, messageID int64, tag ber.Tag, code int64) error {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return writeMessage(conn, messageID, op)
+}
+
+func writeMessage(conn net.Conn, messageID int64, op *ber.Packet) error {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	packet.AppendChild(op)
+	_, err := conn.Write(packet.Bytes())
+	return err
+}
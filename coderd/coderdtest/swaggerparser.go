@@ -303,7 +303,10 @@ func assertSecurityDefined(t *testing.T, comment SwaggerComment) {
 	if comment.router == "/updatecheck" ||
 		comment.router == "/buildinfo" ||
 		comment.router == "/" ||
-		comment.router == "/users/login" {
+		comment.router == "/users/login" ||
+		comment.router == "/users/ldap/login" ||
+		comment.router == "/users/login/totp" ||
+		comment.router == "/oauth2/tokens" {
 		return // endpoints do not require authorization
 	}
 	assert.Equal(t, "CoderSessionToken", comment.security, "@Security must be equal CoderSessionToken")
@@ -313,7 +316,7 @@ func assertAccept(t *testing.T, comment SwaggerComment) {
 	var hasRequestBody bool
 	for _, c := range comment.parameters {
 		if c.name == "request" && c.kind == "body" ||
-			c.name == "file" && c.kind == "formData" {
+			c.kind == "formData" {
 			hasRequestBody = true
 			break
 		}
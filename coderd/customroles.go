@@ -0,0 +1,368 @@
+package coderd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get custom site roles
+// @ID get-custom-site-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Success 200 {array} codersdk.CustomRole
+// @Router /roles [get]
+func (api *API) customSiteRoles(rw http.ResponseWriter, r *http.Request) {
+	api.listCustomRoles(rw, r, uuid.NullUUID{})
+}
+
+// @Summary Get custom organization roles
+// @ID get-custom-organization-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.CustomRole
+// @Router /organizations/{organization}/roles [get]
+func (api *API) customOrganizationRoles(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.listCustomRoles(rw, r, uuid.NullUUID{UUID: organization.ID, Valid: true})
+}
+
+func (api *API) listCustomRoles(rw http.ResponseWriter, r *http.Request, orgID uuid.NullUUID) {
+	ctx := r.Context()
+	roles, err := api.Database.GetCustomRoles(ctx, []string{})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.CustomRole, 0, len(roles))
+	for _, role := range roles {
+		if role.OrganizationID != orgID {
+			continue
+		}
+		converted = append(converted, convertCustomRole(role))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get custom role by ID
+// @ID get-custom-role-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role ID" format(uuid)
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{role} [get]
+func (api *API) customRole(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, convertCustomRole(httpmw.CustomRoleParam(r)))
+}
+
+// @Summary Create custom site role
+// @ID create-custom-site-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param request body codersdk.CreateCustomRoleRequest true "Create custom role request"
+// @Success 201 {object} codersdk.CustomRole
+// @Router /roles [post]
+func (api *API) postCustomSiteRole(rw http.ResponseWriter, r *http.Request) {
+	api.createCustomRole(rw, r, uuid.NullUUID{})
+}
+
+// @Summary Create custom organization role
+// @ID create-custom-organization-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateCustomRoleRequest true "Create custom role request"
+// @Success 201 {object} codersdk.CustomRole
+// @Router /organizations/{organization}/roles [post]
+func (api *API) postCustomOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.createCustomRole(rw, r, uuid.NullUUID{UUID: organization.ID, Valid: true})
+}
+
+func (api *API) createCustomRole(rw http.ResponseWriter, r *http.Request, orgID uuid.NullUUID) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if rbac.IsBuiltInRole(req.Name) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Role name %q is reserved for a built-in role.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is reserved.",
+			}},
+		})
+		return
+	}
+	permissions, ok := api.customRolePermissions(rw, r, orgID, req.Permissions)
+	if !ok {
+		return
+	}
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = req.Name
+	}
+
+	now := database.Now()
+	role, err := api.Database.InsertCustomRole(ctx, database.InsertCustomRoleParams{
+		ID:             uuid.New(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Name:           req.Name,
+		DisplayName:    displayName,
+		OrganizationID: orgID,
+		Permissions:    permissions,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Role with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = role
+	httpapi.Write(ctx, rw, http.StatusCreated, convertCustomRole(role))
+}
+
+// @Summary Update custom role
+// @ID update-custom-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role ID" format(uuid)
+// @Param request body codersdk.UpdateCustomRoleRequest true "Update custom role request"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /roles/{role} [patch]
+func (api *API) patchCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		role              = httpmw.CustomRoleParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = role
+
+	var req codersdk.UpdateCustomRoleRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	permissions, ok := api.customRolePermissions(rw, r, role.OrganizationID, req.Permissions)
+	if !ok {
+		return
+	}
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = role.Name
+	}
+
+	updated, err := api.Database.UpdateCustomRole(ctx, database.UpdateCustomRoleParams{
+		ID:          role.ID,
+		UpdatedAt:   database.Now(),
+		DisplayName: displayName,
+		Permissions: permissions,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, convertCustomRole(updated))
+}
+
+// @Summary Delete custom role
+// @Description Deleting a role unassigns it from all users and organization members.
+// @ID delete-custom-role
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param role path string true "Role ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /roles/{role} [delete]
+func (api *API) deleteCustomRole(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		role              = httpmw.CustomRoleParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = role
+
+	err := api.Database.DeleteCustomRole(ctx, role.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Role has been deleted!",
+	})
+}
+
+// customRolePermissions validates the permissions of a custom role and
+// encodes them for the database. Users can only grant permissions they have
+// themselves, so a custom role can't be used to escalate privileges.
+func (api *API) customRolePermissions(rw http.ResponseWriter, r *http.Request, orgID uuid.NullUUID, perms []codersdk.Permission) (json.RawMessage, bool) {
+	ctx := r.Context()
+	resourceTypes := map[string]bool{rbac.WildcardSymbol: true}
+	for _, resource := range rbac.AllResources() {
+		resourceTypes[resource.Type] = true
+	}
+	actions := map[rbac.Action]bool{rbac.WildcardSymbol: true}
+	for _, action := range rbac.AllActions() {
+		actions[action] = true
+	}
+
+	rbacPerms := make([]rbac.Permission, 0, len(perms))
+	for i, perm := range perms {
+		field := fmt.Sprintf("permissions[%d]", i)
+		if !resourceTypes[string(perm.ResourceType)] {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid permission.",
+				Validations: []codersdk.ValidationError{{
+					Field:  field,
+					Detail: fmt.Sprintf("Unknown resource type %q.", perm.ResourceType),
+				}},
+			})
+			return nil, false
+		}
+		if !actions[rbac.Action(perm.Action)] {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid permission.",
+				Validations: []codersdk.ValidationError{{
+					Field:  field,
+					Detail: fmt.Sprintf("Unknown action %q.", perm.Action),
+				}},
+			})
+			return nil, false
+		}
+
+		// Denying an action never grants more than the user has.
+		if !perm.Negate {
+			object := rbac.Object{Type: string(perm.ResourceType)}
+			if orgID.Valid {
+				object = object.InOrg(orgID.UUID)
+			}
+			if !api.Authorize(r, rbac.Action(perm.Action), object) {
+				httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+					Message: "You cannot grant permissions you do not have.",
+					Detail:  fmt.Sprintf("You are not allowed to %s %s.", perm.Action, perm.ResourceType),
+				})
+				return nil, false
+			}
+		}
+
+		rbacPerms = append(rbacPerms, rbac.Permission{
+			Negate:       perm.Negate,
+			ResourceType: string(perm.ResourceType),
+			Action:       rbac.Action(perm.Action),
+		})
+	}
+
+	permissions, err := json.Marshal(rbacPerms)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error encoding permissions.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	return permissions, true
+}
+
+func convertCustomRole(role database.CustomRole) codersdk.CustomRole {
+	// The permissions are stored in the same format, they are validated when
+	// the role is created or updated.
+	perms := make([]codersdk.Permission, 0)
+	_ = json.Unmarshal(role.Permissions, &perms)
+
+	converted := codersdk.CustomRole{
+		ID:          role.ID,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+		Name:        role.Name,
+		DisplayName: role.DisplayName,
+		Permissions: perms,
+		AssignName:  role.RoleName(),
+	}
+	if role.OrganizationID.Valid {
+		converted.OrganizationID = &role.OrganizationID.UUID
+	}
+	return converted
+}
@@ -0,0 +1,246 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		auditor.ResetLogs()
+
+		role, err := client.CreateCustomSiteRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name: "workspace-viewer",
+			Permissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "workspace-viewer", role.Name)
+		require.Equal(t, "workspace-viewer", role.DisplayName)
+		require.Equal(t, "workspace-viewer", role.AssignName)
+		require.Nil(t, role.OrganizationID)
+
+		_, err = client.CreateCustomSiteRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:        "workspace-viewer",
+			Permissions: []codersdk.Permission{},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		role, err = client.UpdateCustomRole(ctx, role.ID, codersdk.UpdateCustomRoleRequest{
+			DisplayName: "Workspace Viewer",
+			Permissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+				{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Workspace Viewer", role.DisplayName)
+		require.Len(t, role.Permissions, 2)
+
+		roles, err := client.CustomSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		require.Equal(t, role, roles[0])
+
+		err = client.DeleteCustomRole(ctx, role.ID)
+		require.NoError(t, err)
+		_, err = client.CustomRole(ctx, role.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, 3)
+		assert.Equal(t, database.AuditActionCreate, logs[0].Action)
+		assert.Equal(t, database.ResourceTypeCustomRole, logs[0].ResourceType)
+		assert.Equal(t, database.AuditActionWrite, logs[1].Action)
+		assert.Equal(t, database.AuditActionDelete, logs[2].Action)
+	})
+
+	t.Run("ReservedName", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateCustomSiteRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:        rbac.RoleOwner(),
+			Permissions: []codersdk.Permission{},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidPermission", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateCustomSiteRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name: "invalid",
+			Permissions: []codersdk.Permission{
+				{ResourceType: "spaceship", Action: "read"},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "permissions[0]", apiErr.Validations[0].Field)
+	})
+
+	t.Run("MemberCannotCreate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CreateCustomSiteRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:        "viewer",
+			Permissions: []codersdk.Permission{},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("OrgAdminCannotCreateSiteRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		orgAdmin, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID, rbac.RoleOrgAdmin(first.OrganizationID))
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := orgAdmin.CreateCustomSiteRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name: "viewer",
+			Permissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		role, err := orgAdmin.CreateCustomOrganizationRole(ctx, first.OrganizationID, codersdk.CreateCustomRoleRequest{
+			Name: "viewer",
+			Permissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, rbac.RoleName("viewer", first.OrganizationID.String()), role.AssignName)
+
+		roles, err := orgAdmin.CustomOrganizationRoles(ctx, first.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+	})
+
+	t.Run("AssignSiteRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.DeploymentConfig(ctx)
+		require.Error(t, err, "members cannot read the deployment config")
+
+		role, err := client.CreateCustomSiteRole(ctx, codersdk.CreateCustomRoleRequest{
+			Name:        "deployment-viewer",
+			DisplayName: "Deployment Viewer",
+			Permissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceDeploymentValues, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+
+		assignable, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Contains(t, assignable, codersdk.AssignableRoles{
+			Role:       codersdk.Role{Name: role.AssignName, DisplayName: role.DisplayName},
+			Assignable: true,
+		})
+
+		_, err = client.UpdateUserRoles(ctx, user.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.AssignName},
+		})
+		require.NoError(t, err)
+
+		_, err = member.DeploymentConfig(ctx)
+		require.NoError(t, err)
+
+		// Deleting the role unassigns it.
+		err = client.DeleteCustomRole(ctx, role.ID)
+		require.NoError(t, err)
+		user, err = client.User(ctx, user.ID.String())
+		require.NoError(t, err)
+		require.Empty(t, user.Roles)
+		_, err = member.DeploymentConfig(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("AssignOrgRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := client.CreateCustomOrganizationRole(ctx, first.OrganizationID, codersdk.CreateCustomRoleRequest{
+			Name: "template-viewer",
+			Permissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+
+		member, err := client.UpdateOrganizationMemberRoles(ctx, first.OrganizationID, user.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.AssignName},
+		})
+		require.NoError(t, err)
+		require.Contains(t, member.Roles, codersdk.Role{Name: role.AssignName, DisplayName: role.AssignName})
+
+		// An organization role can't be assigned as a site wide role.
+		_, err = client.UpdateUserRoles(ctx, user.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.AssignName},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("AssignUnknownRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateUserRoles(ctx, user.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{"does-not-exist"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
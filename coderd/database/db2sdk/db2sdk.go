@@ -116,11 +116,11 @@ func User(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 		Roles:           make([]codersdk.Role, 0, len(user.RBACRoles)),
 		AvatarURL:       user.AvatarURL.String,
 		LoginType:       codersdk.LoginType(user.LoginType),
+		ServiceAccount:  user.IsServiceAccount,
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedUser.Roles = append(convertedUser.Roles, Role(rbacRole))
+		convertedUser.Roles = append(convertedUser.Roles, RoleByName(roleName))
 	}
 
 	return convertedUser
@@ -132,3 +132,16 @@ func Role(role rbac.Role) codersdk.Role {
 		Name:        role.Name,
 	}
 }
+
+// RoleByName returns the role of the given name. Custom roles are stored in
+// the database, so their name is used as the display name.
+func RoleByName(name string) codersdk.Role {
+	rbacRole, err := rbac.RoleByName(name)
+	if err != nil {
+		return codersdk.Role{
+			DisplayName: name,
+			Name:        name,
+		}
+	}
+	return Role(rbacRole)
+}
@@ -17,6 +17,7 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/coderd/util/slice"
 )
 
@@ -106,6 +107,78 @@ func New(db database.Store, authorizer rbac.Authorizer, logger slog.Logger) data
 	}
 }
 
+func (q *querier) CountUnchainedAuditLogs(ctx context.Context, before time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return 0, err
+	}
+	return q.db.CountUnchainedAuditLogs(ctx, before)
+}
+
+func (q *querier) GetAuditLogCheckpoints(ctx context.Context) ([]database.AuditLogCheckpoint, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogCheckpoints(ctx)
+}
+
+func (q *querier) GetAuditLogHashes(ctx context.Context, arg database.GetAuditLogHashesParams) ([]database.AuditLogHash, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogHashes(ctx, arg)
+}
+
+func (q *querier) GetAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.AuditLog, error) {
+	// Like GetAuditLogsOffset, only the global audit log permission is checked.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogsByIDs(ctx, ids)
+}
+
+func (q *querier) GetLatestAuditLogCheckpoint(ctx context.Context) (database.AuditLogCheckpoint, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return database.AuditLogCheckpoint{}, err
+	}
+	return q.db.GetLatestAuditLogCheckpoint(ctx)
+}
+
+func (q *querier) GetLatestAuditLogHash(ctx context.Context) (database.AuditLogHash, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return database.AuditLogHash{}, err
+	}
+	return q.db.GetLatestAuditLogHash(ctx)
+}
+
+func (q *querier) GetPrunedAuditLogHashesAfter(ctx context.Context, arg database.GetPrunedAuditLogHashesAfterParams) ([]database.AuditLogHash, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetPrunedAuditLogHashesAfter(ctx, arg)
+}
+
+func (q *querier) GetUnchainedAuditLogs(ctx context.Context, arg database.GetUnchainedAuditLogsParams) ([]database.AuditLog, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetUnchainedAuditLogs(ctx, arg)
+}
+
+func (q *querier) InsertAuditLogCheckpoint(ctx context.Context, arg database.InsertAuditLogCheckpointParams) (database.AuditLogCheckpoint, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.AuditLogCheckpoint{}, err
+	}
+	return q.db.InsertAuditLogCheckpoint(ctx, arg)
+}
+
+func (q *querier) InsertAuditLogHash(ctx context.Context, arg database.InsertAuditLogHashParams) (database.AuditLogHash, error) {
+	// Hashes are inserted along with the audit log they belong to.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceAuditLog); err != nil {
+		return database.AuditLogHash{}, err
+	}
+	return q.db.InsertAuditLogHash(ctx, arg)
+}
+
 func (q *querier) Wrappers() []string {
 	return append(q.db.Wrappers(), wrapname)
 }
@@ -169,7 +242,7 @@ var (
 					rbac.ResourceSystem.Type:         {rbac.WildcardSymbol},
 					rbac.ResourceTemplate.Type:       {rbac.ActionRead, rbac.ActionUpdate},
 					rbac.ResourceWorkspace.Type:      {rbac.ActionRead, rbac.ActionUpdate},
-					rbac.ResourceWorkspaceBuild.Type: {rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWorkspaceBuild.Type: {rbac.ActionCreate, rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -197,6 +270,28 @@ var (
 		Scope: rbac.ScopeAll,
 	}.WithCachedASTValue()
 
+	// See prebuilds package.
+	subjectPrebuilds = rbac.Subject{
+		ID: uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Name:        "prebuilds",
+				DisplayName: "Prebuilt Workspaces Daemon",
+				Site: rbac.Permissions(map[string][]rbac.Action{
+					rbac.ResourceFile.Type:           {rbac.ActionRead},
+					rbac.ResourceSystem.Type:         {rbac.WildcardSymbol},
+					rbac.ResourceTemplate.Type:       {rbac.ActionRead},
+					rbac.ResourceUser.Type:           {rbac.ActionRead},
+					rbac.ResourceWorkspace.Type:      {rbac.ActionCreate, rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWorkspaceBuild.Type: {rbac.ActionCreate, rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
 	subjectSystemRestricted = rbac.Subject{
 		ID: uuid.Nil.String(),
 		Roles: rbac.Roles([]rbac.Role{
@@ -204,20 +299,22 @@ var (
 				Name:        "system",
 				DisplayName: "Coder",
 				Site: rbac.Permissions(map[string][]rbac.Action{
-					rbac.ResourceWildcard.Type:           {rbac.ActionRead},
-					rbac.ResourceAPIKey.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceRoleAssignment.Type:     {rbac.ActionCreate, rbac.ActionDelete},
-					rbac.ResourceSystem.Type:             {rbac.WildcardSymbol},
-					rbac.ResourceOrganization.Type:       {rbac.ActionCreate},
-					rbac.ResourceOrganizationMember.Type: {rbac.ActionCreate},
-					rbac.ResourceOrgRoleAssignment.Type:  {rbac.ActionCreate},
-					rbac.ResourceUser.Type:               {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceUserData.Type:           {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceWorkspace.Type:          {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceBuild.Type:     {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceExecution.Type: {rbac.ActionCreate},
-					rbac.ResourceWorkspaceProxy.Type:     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWildcard.Type:                   {rbac.ActionRead},
+					rbac.ResourceAPIKey.Type:                     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceGroup.Type:                      {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceOAuth2ProviderAppSecret.Type:    {rbac.ActionUpdate},
+					rbac.ResourceOAuth2ProviderAppCodeToken.Type: {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceRoleAssignment.Type:             {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceSystem.Type:                     {rbac.WildcardSymbol},
+					rbac.ResourceOrganization.Type:               {rbac.ActionCreate},
+					rbac.ResourceOrganizationMember.Type:         {rbac.ActionCreate},
+					rbac.ResourceOrgRoleAssignment.Type:          {rbac.ActionCreate},
+					rbac.ResourceUser.Type:                       {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceUserData.Type:                   {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceWorkspace.Type:                  {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceBuild.Type:             {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceExecution.Type:         {rbac.ActionCreate},
+					rbac.ResourceWorkspaceProxy.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -245,6 +342,12 @@ func AsHangDetector(ctx context.Context) context.Context {
 	return context.WithValue(ctx, authContextKey{}, subjectHangDetector)
 }
 
+// AsPrebuilds returns a context with an actor that has permissions required
+// for managing and claiming prebuilt workspaces.
+func AsPrebuilds(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authContextKey{}, subjectPrebuilds)
+}
+
 // AsSystemRestricted returns a context with an actor that has permissions
 // required for various system operations (login, logout, metrics cache).
 func AsSystemRestricted(ctx context.Context) context.Context {
@@ -547,11 +650,11 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 		if !shouldBeOrgRoles && isOrgRole {
 			return xerrors.Errorf("Must only update site wide roles")
 		}
+	}
 
-		// All roles should be valid roles
-		if _, err := rbac.RoleByName(r); err != nil {
-			return xerrors.Errorf("%q is not a supported role", r)
-		}
+	// All roles should be valid roles, custom roles must exist.
+	if _, err := rolestore.Expand(ctx, q.db, grantedRoles); err != nil {
+		return err
 	}
 
 	if len(added) > 0 {
@@ -639,6 +742,13 @@ func (q *querier) AcquireLock(ctx context.Context, id int64) error {
 	return q.db.AcquireLock(ctx, id)
 }
 
+func (q *querier) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireNotificationMessages(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -647,6 +757,28 @@ func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.Acquir
 	return q.db.AcquireProvisionerJob(ctx, arg)
 }
 
+func (q *querier) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireWebhookDeliveries(ctx, arg)
+}
+
+func (q *querier) ClaimPrebuiltWorkspace(ctx context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.Workspace, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+	if err != nil {
+		return database.Workspace{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.Workspace{}, err
+	}
+	// Claiming a workspace is equivalent to creating it for the new owner.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceWorkspace.InOrg(workspace.OrganizationID).WithOwner(arg.NewOwnerID.String())); err != nil {
+		return database.Workspace{}, err
+	}
+	return q.db.ClaimPrebuiltWorkspace(ctx, arg)
+}
+
 func (q *querier) CleanTailnetCoordinators(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -678,6 +810,13 @@ func (q *querier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, u
 	return q.db.DeleteApplicationConnectAPIKeysByUserID(ctx, userID)
 }
 
+func (q *querier) DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteAuditLogsByIDs(ctx, ids)
+}
+
 func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -685,6 +824,10 @@ func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	return q.db.DeleteCoordinator(ctx, id)
 }
 
+func (q *querier) DeleteCustomRole(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetCustomRoleByID, q.db.DeleteCustomRole)(ctx, id)
+}
+
 func (q *querier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
 	return deleteQ(q.log, q.auth, q.db.GetGitSSHKey, q.db.DeleteGitSSHKey)(ctx, userID)
 }
@@ -722,6 +865,74 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppByID, q.db.DeleteOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetchAndQuery(q.log, q.auth, rbac.ActionDelete, q.db.GetOAuth2ProviderAppCodeByID, q.db.DeleteOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String())); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID, q.db.DeleteOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	// Tokens belong to the user of their API key.
+	key, err := q.db.GetAPIKeyByID(ctx, apiKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(key.UserID.String())); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return q.db.DeleteOAuth2ProviderAppTokenByAPIKeyID(ctx, apiKeyID)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String())); err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
+}
+
+func (q *querier) DeleteOldNotificationMessages(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldNotificationMessages(ctx)
+}
+
+func (q *querier) DeleteOldUserPasswordHistory(ctx context.Context, arg database.DeleteOldUserPasswordHistoryParams) error {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject())
+	if err != nil {
+		// Admins can update passwords for other users.
+		err = q.authorizeContext(ctx, rbac.ActionUpdate, u.RBACObject())
+		if err != nil {
+			return err
+		}
+	}
+	return q.db.DeleteOldUserPasswordHistory(ctx, arg)
+}
+
+func (q *querier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWebhookDeliveries(ctx)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -757,6 +968,49 @@ func (q *querier) DeleteTailnetClient(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetClient(ctx, arg)
 }
 
+func (q *querier) DeleteUserLoginFailures(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUser.WithID(userID)); err != nil {
+		return err
+	}
+	return q.db.DeleteUserLoginFailures(ctx, userID)
+}
+
+func (q *querier) DeleteUserTOTPByUserID(ctx context.Context, userID uuid.UUID) error {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionDelete, u.UserDataRBACObject())
+	if err != nil {
+		// Admins can reset multi-factor authentication for other users.
+		err = q.authorizeContext(ctx, rbac.ActionUpdate, u.RBACObject())
+		if err != nil {
+			return err
+		}
+	}
+	return q.db.DeleteUserTOTPByUserID(ctx, userID)
+}
+
+func (q *querier) DeleteUserTOTPRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionDelete, u.UserDataRBACObject())
+	if err != nil {
+		// Admins can reset multi-factor authentication for other users.
+		err = q.authorizeContext(ctx, rbac.ActionUpdate, u.RBACObject())
+		if err != nil {
+			return err
+		}
+	}
+	return q.db.DeleteUserTOTPRecoveryCodesByUserID(ctx, userID)
+}
+
+func (q *querier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetWebhookByID, q.db.DeleteWebhookByID)(ctx, id)
+}
+
 func (q *querier) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByID)(ctx, id)
 }
@@ -769,6 +1023,10 @@ func (q *querier) GetAPIKeysByLoginType(ctx context.Context, loginType database.
 	return fetchWithPostFilter(q.auth, q.db.GetAPIKeysByLoginType)(ctx, loginType)
 }
 
+func (q *querier) GetAPIKeysByLoginTypeExpiringBetween(ctx context.Context, arg database.GetAPIKeysByLoginTypeExpiringBetweenParams) ([]database.APIKey, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetAPIKeysByLoginTypeExpiringBetween)(ctx, arg)
+}
+
 func (q *querier) GetAPIKeysByUserID(ctx context.Context, params database.GetAPIKeysByUserIDParams) ([]database.APIKey, error) {
 	return fetchWithPostFilter(q.auth, q.db.GetAPIKeysByUserID)(ctx, database.GetAPIKeysByUserIDParams{LoginType: params.LoginType, UserID: params.UserID})
 }
@@ -789,6 +1047,14 @@ func (q *querier) GetAppSecurityKey(ctx context.Context) (string, error) {
 	return q.db.GetAppSecurityKey(ctx)
 }
 
+func (q *querier) GetAuditLogsByTime(ctx context.Context, arg database.GetAuditLogsByTimeParams) ([]database.AuditLog, error) {
+	// Like GetAuditLogsOffset, only the global audit log permission is checked.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogsByTime(ctx, arg)
+}
+
 func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	// To optimize audit logs, we only check the global audit log permission once.
 	// This is because we expect a large unbounded set of audit logs, and applying a SQL
@@ -806,6 +1072,14 @@ func (q *querier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUI
 	return q.db.GetAuthorizationUserRoles(ctx, userID)
 }
 
+func (q *querier) GetCustomRoleByID(ctx context.Context, id uuid.UUID) (database.CustomRole, error) {
+	return fetch(q.log, q.auth, q.db.GetCustomRoleByID)(ctx, id)
+}
+
+func (q *querier) GetCustomRoles(ctx context.Context, names []string) ([]database.CustomRole, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetCustomRoles)(ctx, names)
+}
+
 func (q *querier) GetDERPMeshKey(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -839,6 +1113,13 @@ func (q *querier) GetDeploymentWorkspaceStats(ctx context.Context) (database.Get
 	return q.db.GetDeploymentWorkspaceStats(ctx)
 }
 
+func (q *querier) GetEnabledWebhooksByEvent(ctx context.Context, event database.WebhookEvent) ([]database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetEnabledWebhooksByEvent(ctx, event)
+}
+
 func (q *querier) GetFileByHashAndCreator(ctx context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
 	file, err := q.db.GetFileByHashAndCreator(ctx, arg)
 	if err != nil {
@@ -952,6 +1233,13 @@ func (q *querier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, id
 	return q.db.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, ids)
 }
 
+func (q *querier) GetLatestWorkspaceBuildsWithDeadlineBetween(ctx context.Context, arg database.GetLatestWorkspaceBuildsWithDeadlineBetweenParams) ([]database.WorkspaceBuild, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetLatestWorkspaceBuildsWithDeadlineBetween(ctx, arg)
+}
+
 func (q *querier) GetLicenseByID(ctx context.Context, id int32) (database.License, error) {
 	return fetch(q.log, q.auth, q.db.GetLicenseByID)(ctx, id)
 }
@@ -968,6 +1256,56 @@ func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByHashedSecret)(ctx, hashedSecret)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetOAuth2ProviderAppSecretsByAppID)(ctx, appID)
+}
+
+func (q *querier) GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx context.Context, hashedRefreshToken []byte) (database.OAuth2ProviderAppToken, error) {
+	token, err := q.db.GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx, hashedRefreshToken)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	// Tokens belong to the user of their API key.
+	key, err := q.db.GetAPIKeyByID(ctx, token.APIKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppCodeToken.WithID(token.ID).WithOwner(key.UserID.String())); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return token, nil
+}
+
+func (q *querier) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	fetch := func(ctx context.Context, _ interface{}) ([]database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderApps(ctx)
+	}
+	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
+}
+
+func (q *querier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.GetOAuth2ProviderAppsByUserIDRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(userID.String())); err != nil {
+		return nil, err
+	}
+	return q.db.GetOAuth2ProviderAppsByUserID(ctx, userID)
+}
+
 func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -1376,6 +1714,57 @@ func (q *querier) GetUserLinkByUserIDLoginType(ctx context.Context, arg database
 	return q.db.GetUserLinkByUserIDLoginType(ctx, arg)
 }
 
+func (q *querier) GetUserLoginFailureCounts(ctx context.Context, arg database.GetUserLoginFailureCountsParams) (database.GetUserLoginFailureCountsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUser.WithID(arg.UserID)); err != nil {
+		return database.GetUserLoginFailureCountsRow{}, err
+	}
+	return q.db.GetUserLoginFailureCounts(ctx, arg)
+}
+
+func (q *querier) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.UserNotificationPreference, error) {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, u.UserDataRBACObject()); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserNotificationPreferences(ctx, userID)
+}
+
+// GetUserPasswordHistory is only used to update the password of a user, so it
+// requires the same permission.
+func (q *querier) GetUserPasswordHistory(ctx context.Context, arg database.GetUserPasswordHistoryParams) ([]database.UserPasswordHistory, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject())
+	if err != nil {
+		// Admins can update passwords for other users.
+		err = q.authorizeContext(ctx, rbac.ActionUpdate, u.RBACObject())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return q.db.GetUserPasswordHistory(ctx, arg)
+}
+
+func (q *querier) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	return fetch(q.log, q.auth, q.db.GetUserTOTPByUserID)(ctx, userID)
+}
+
+func (q *querier) GetUserTOTPRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserTOTPRecoveryCode, error) {
+	u, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, u.UserDataRBACObject()); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserTOTPRecoveryCodesByUserID(ctx, userID)
+}
+
 func (q *querier) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	// This does the filtering in SQL.
 	prep, err := prepareSQLFilter(ctx, q.auth, rbac.ActionRead, rbac.ResourceUser.Type)
@@ -1397,6 +1786,25 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	return fetch(q.log, q.auth, q.db.GetWebhookByID)(ctx, id)
+}
+
+func (q *querier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	// Deliveries are visible to anyone who can read the webhook.
+	if _, err := q.GetWebhookByID(ctx, arg.WebhookID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhookDeliveriesByWebhookID(ctx, arg)
+}
+
+func (q *querier) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	fetch := func(ctx context.Context, _ interface{}) ([]database.Webhook, error) {
+		return q.db.GetWebhooks(ctx)
+	}
+	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
+}
+
 // GetWorkspaceAgentByAuthToken is used in http middleware to get the workspace agent.
 // This should only be used by a system user in that middleware.
 func (q *querier) GetWorkspaceAgentByAuthToken(ctx context.Context, authToken uuid.UUID) (database.WorkspaceAgent, error) {
@@ -1451,6 +1859,45 @@ func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentI
 	return q.db.GetWorkspaceAgentMetadata(ctx, workspaceAgentID)
 }
 
+func (q *querier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
+}
+
+func (q *querier) GetWorkspaceAgentSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgentSessionRecording, error) {
+	recording, err := q.db.GetWorkspaceAgentSessionRecordingByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceAgentSessionRecording{}, err
+	}
+
+	workspace, err := q.db.GetWorkspaceByID(ctx, recording.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAgentSessionRecording{}, err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionRead, workspace.SessionRecordingRBAC())
+	if err != nil {
+		return database.WorkspaceAgentSessionRecording{}, err
+	}
+	return recording, nil
+}
+
+func (q *querier) GetWorkspaceAgentSessionRecordingsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.GetWorkspaceAgentSessionRecordingsByAgentIDRow, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionRead, workspace.SessionRecordingRBAC())
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentSessionRecordingsByAgentID(ctx, agentID)
+}
+
 func (q *querier) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentStartupLogsAfterParams) ([]database.WorkspaceAgentStartupLog, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, arg.AgentID)
 	if err != nil {
@@ -1721,10 +2168,18 @@ func (q *querier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesP
 	return q.db.GetAuthorizedWorkspaces(ctx, arg, prep)
 }
 
+func (q *querier) GetWorkspacesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.Workspace, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetWorkspacesByOwnerID)(ctx, ownerID)
+}
+
 func (q *querier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	return q.db.GetWorkspacesEligibleForTransition(ctx, now)
 }
 
+func (q *querier) GetWorkspacesLockedSince(ctx context.Context, lockedSince time.Time) ([]database.Workspace, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetWorkspacesLockedSince)(ctx, lockedSince)
+}
+
 func (q *querier) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	return insert(q.log, q.auth,
 		rbac.ResourceAPIKey.WithOwner(arg.UserID.String()),
@@ -1740,6 +2195,14 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	obj := rbac.ResourceCustomRole
+	if arg.OrganizationID.Valid {
+		obj = obj.InOrg(arg.OrganizationID.UUID)
+	}
+	return insert(q.log, q.auth, obj, q.db.InsertCustomRole)(ctx, arg)
+}
+
 func (q *querier) InsertDERPMeshKey(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -1784,6 +2247,36 @@ func (q *querier) InsertLicense(ctx context.Context, arg database.InsertLicenseP
 	return q.db.InsertLicense(ctx, arg)
 }
 
+func (q *querier) InsertNotificationMessage(ctx context.Context, arg database.InsertNotificationMessageParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertNotificationMessage(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderApp, q.db.InsertOAuth2ProviderApp)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	obj := rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String())
+	return insert(q.log, q.auth, obj, q.db.InsertOAuth2ProviderAppCode)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppSecret, q.db.InsertOAuth2ProviderAppSecret)(ctx, arg)
+}
+
+func (q *querier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	// Tokens belong to the user of their API key.
+	key, err := q.db.GetAPIKeyByID(ctx, arg.APIKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	obj := rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(key.UserID.String())
+	return insert(q.log, q.auth, obj, q.db.InsertOAuth2ProviderAppToken)(ctx, arg)
+}
+
 func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
@@ -1905,6 +2398,51 @@ func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLin
 	return q.db.InsertUserLink(ctx, arg)
 }
 
+func (q *querier) InsertUserLoginFailure(ctx context.Context, arg database.InsertUserLoginFailureParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUser.WithID(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.InsertUserLoginFailure(ctx, arg)
+}
+
+func (q *querier) InsertUserPasswordHistory(ctx context.Context, arg database.InsertUserPasswordHistoryParams) error {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject())
+	if err != nil {
+		// Admins can update passwords for other users.
+		err = q.authorizeContext(ctx, rbac.ActionUpdate, u.RBACObject())
+		if err != nil {
+			return err
+		}
+	}
+	return q.db.InsertUserPasswordHistory(ctx, arg)
+}
+
+func (q *querier) InsertUserTOTPRecoveryCodes(ctx context.Context, arg database.InsertUserTOTPRecoveryCodesParams) error {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, u.UserDataRBACObject()); err != nil {
+		return err
+	}
+	return q.db.InsertUserTOTPRecoveryCodes(ctx, arg)
+}
+
+func (q *querier) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	return insert(q.log, q.auth, rbac.ResourceWebhook, q.db.InsertWebhook)(ctx, arg)
+}
+
+func (q *querier) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWebhookDelivery(ctx, arg)
+}
+
 func (q *querier) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	obj := rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(arg.OrganizationID)
 	return insert(q.log, q.auth, obj, q.db.InsertWorkspace)(ctx, arg)
@@ -1929,6 +2467,26 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentScript(ctx context.Context, arg database.InsertWorkspaceAgentScriptParams) (database.WorkspaceAgentScript, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+	return q.db.InsertWorkspaceAgentScript(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceAgentSessionRecording(ctx context.Context, arg database.InsertWorkspaceAgentSessionRecordingParams) (database.WorkspaceAgentSessionRecording, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return database.WorkspaceAgentSessionRecording{}, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, workspace.SessionRecordingRBAC()); err != nil {
+		return database.WorkspaceAgentSessionRecording{}, err
+	}
+
+	return q.db.InsertWorkspaceAgentSessionRecording(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg database.InsertWorkspaceAgentStartupLogsParams) ([]database.WorkspaceAgentStartupLog, error) {
 	return q.db.InsertWorkspaceAgentStartupLogs(ctx, arg)
 }
@@ -1960,7 +2518,7 @@ func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertW
 		return err
 	}
 
-	var action rbac.Action = rbac.ActionUpdate
+	var action rbac.Action = rbac.ActionCreate
 	if arg.Transition == database.WorkspaceTransitionDelete {
 		action = rbac.ActionDelete
 	}
@@ -1984,7 +2542,13 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 		return err
 	}
 
-	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	// The parameters are part of the build, so inserting them is authorized
+	// the same way as inserting the build.
+	var action rbac.Action = rbac.ActionCreate
+	if build.Transition == database.WorkspaceTransitionDelete {
+		action = rbac.ActionDelete
+	}
+	err = q.authorizeContext(ctx, action, workspace.WorkspaceBuildRBAC(build.Transition))
 	if err != nil {
 		return err
 	}
@@ -2028,6 +2592,13 @@ func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKe
 	return update(q.log, q.auth, fetch, q.db.UpdateAPIKeyByID)(ctx, arg)
 }
 
+func (q *querier) UpdateCustomRole(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	fetch := func(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+		return q.db.GetCustomRoleByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateCustomRole)(ctx, arg)
+}
+
 func (q *querier) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 		return q.db.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{UserID: arg.UserID, ProviderID: arg.ProviderID})
@@ -2070,6 +2641,27 @@ func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemb
 	return q.db.UpdateMemberRoles(ctx, arg)
 }
 
+func (q *querier) UpdateNotificationMessageByID(ctx context.Context, arg database.UpdateNotificationMessageByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateNotificationMessageByID(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderAppByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppByID)(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+		return q.db.GetOAuth2ProviderAppSecretByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppSecretByID)(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -2370,6 +2962,27 @@ func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserS
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserStatus)(ctx, arg)
 }
 
+func (q *querier) UpdateUserTOTPUsed(ctx context.Context, arg database.UpdateUserTOTPUsedParams) (database.UserTOTP, error) {
+	fetch := func(ctx context.Context, arg database.UpdateUserTOTPUsedParams) (database.UserTOTP, error) {
+		return q.db.GetUserTOTPByUserID(ctx, arg.UserID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserTOTPUsed)(ctx, arg)
+}
+
+func (q *querier) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+		return q.db.GetWebhookByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWebhookByID)(ctx, arg)
+}
+
+func (q *querier) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWebhookDeliveryByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -2377,6 +2990,24 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceACLByID)(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceAgentAuthTokensByWorkspaceID(ctx context.Context, arg database.UpdateWorkspaceAgentAuthTokensByWorkspaceIDParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceAgentAuthTokensByWorkspaceID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -2411,6 +3042,19 @@ func (q *querier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.UpdateWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentScriptStatusByID(ctx context.Context, arg database.UpdateWorkspaceAgentScriptStatusByIDParams) (database.WorkspaceAgentScript, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	return q.db.UpdateWorkspaceAgentScriptStatusByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	agent, err := q.db.GetWorkspaceAgentByID(ctx, arg.ID)
 	if err != nil {
@@ -2518,6 +3162,22 @@ func (q *querier) UpdateWorkspaceLockedDeletingAt(ctx context.Context, arg datab
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceLockedDeletingAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+	if err != nil {
+		return database.Workspace{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.Workspace{}, err
+	}
+	// Transferring a workspace is equivalent to creating it for the new
+	// owner.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceWorkspace.InOrg(workspace.OrganizationID).WithOwner(arg.OwnerID.String())); err != nil {
+		return database.Workspace{}, err
+	}
+	return q.db.UpdateWorkspaceOwnerByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2608,6 +3268,39 @@ func (q *querier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (d
 	return q.db.UpsertTailnetCoordinator(ctx, id)
 }
 
+func (q *querier) UpsertUserNotificationPreference(ctx context.Context, arg database.UpsertUserNotificationPreferenceParams) error {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return err
+	}
+	return q.db.UpsertUserNotificationPreference(ctx, arg)
+}
+
+func (q *querier) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.UpsertUserTOTP(ctx, arg)
+}
+
+func (q *querier) UseUserTOTPRecoveryCode(ctx context.Context, arg database.UseUserTOTPRecoveryCodeParams) (database.UserTOTPRecoveryCode, error) {
+	u, err := q.db.GetUserByID(ctx, arg.UserID)
+	if err != nil {
+		return database.UserTOTPRecoveryCode{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.UserDataRBACObject()); err != nil {
+		return database.UserTOTPRecoveryCode{}, err
+	}
+	return q.db.UseUserTOTPRecoveryCode(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
@@ -2642,6 +3335,30 @@ func (q *querier) GetAuthorizedWorkspaces(ctx context.Context, arg database.GetW
 	return q.GetWorkspaces(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceGroupRoles(ctx context.Context, id uuid.UUID) ([]database.WorkspaceGroup, error) {
+	// An actor is authorized to read workspace group roles if they are authorized to read the workspace.
+	workspace, err := q.db.GetWorkspaceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceGroupRoles(ctx, id)
+}
+
+func (q *querier) GetWorkspaceUserRoles(ctx context.Context, id uuid.UUID) ([]database.WorkspaceUser, error) {
+	// An actor is authorized to query workspace user roles if they are authorized to read the workspace.
+	workspace, err := q.db.GetWorkspaceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceUserRoles(ctx, id)
+}
+
 // GetAuthorizedUsers is not required for dbauthz since GetUsers is already
 // authenticated.
 func (q *querier) GetAuthorizedUsers(ctx context.Context, arg database.GetUsersParams, _ rbac.PreparedAuthorized) ([]database.GetUsersRow, error) {
@@ -207,6 +207,18 @@ func (s *MethodTestSuite) TestAPIKey() {
 			Asserts(a, rbac.ActionRead, b, rbac.ActionRead).
 			Returns(slice.New(a, b))
 	}))
+	s.Run("GetAPIKeysByLoginTypeExpiringBetween", s.Subtest(func(db database.Store, check *expects) {
+		a, _ := dbgen.APIKey(s.T(), db, database.APIKey{LoginType: database.LoginTypeToken, ExpiresAt: time.Now().Add(time.Hour)})
+		_, _ = dbgen.APIKey(s.T(), db, database.APIKey{LoginType: database.LoginTypeToken, ExpiresAt: time.Now().Add(48 * time.Hour)})
+		_, _ = dbgen.APIKey(s.T(), db, database.APIKey{LoginType: database.LoginTypePassword, ExpiresAt: time.Now().Add(time.Hour)})
+		check.Args(database.GetAPIKeysByLoginTypeExpiringBetweenParams{
+			LoginType: database.LoginTypeToken,
+			StartTime: time.Now(),
+			EndTime:   time.Now().Add(24 * time.Hour),
+		}).
+			Asserts(a, rbac.ActionRead).
+			Returns(slice.New(a))
+	}))
 	s.Run("InsertAPIKey", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(database.InsertAPIKeyParams{
@@ -237,6 +249,73 @@ func (s *MethodTestSuite) TestAuditLogs() {
 			Limit: 10,
 		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
 	}))
+	s.Run("GetAuditLogsByTime", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(database.GetAuditLogsByTimeParams{
+			Before:     time.Now().Add(time.Hour),
+			LimitCount: 10,
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
+	s.Run("GetAuditLogsByIDs", s.Subtest(func(db database.Store, check *expects) {
+		alog := dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args([]uuid.UUID{alog.ID}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead).Returns([]database.AuditLog{alog})
+	}))
+	s.Run("GetUnchainedAuditLogs", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(database.GetUnchainedAuditLogsParams{
+			LimitCount: 10,
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
+	s.Run("CountUnchainedAuditLogs", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(time.Now().Add(time.Hour)).Asserts(rbac.ResourceAuditLog, rbac.ActionRead).Returns(int64(1))
+	}))
+	s.Run("InsertAuditLogHash", s.Subtest(func(db database.Store, check *expects) {
+		alog := dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(database.InsertAuditLogHashParams{
+			Sequence:   1,
+			AuditLogID: alog.ID,
+			PrevHash:   []byte{},
+			Hash:       []byte{1},
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionCreate)
+	}))
+	s.Run("GetLatestAuditLogHash", s.Subtest(func(db database.Store, check *expects) {
+		alog := dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		hash, err := db.InsertAuditLogHash(context.Background(), database.InsertAuditLogHashParams{
+			Sequence:   1,
+			AuditLogID: alog.ID,
+			PrevHash:   []byte{},
+			Hash:       []byte{1},
+		})
+		require.NoError(s.T(), err)
+		check.Args().Asserts(rbac.ResourceAuditLog, rbac.ActionRead).Returns(hash)
+	}))
+	s.Run("GetAuditLogHashes", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetAuditLogHashesParams{
+			MaxSequence: 10,
+			LimitCount:  10,
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
+	s.Run("GetPrunedAuditLogHashesAfter", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetPrunedAuditLogHashesAfterParams{
+			ResourceType: database.ResourceTypeWorkspace,
+			MaxSequence:  10,
+			LimitCount:   10,
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
+	s.Run("GetLatestAuditLogCheckpoint", s.Subtest(func(db database.Store, check *expects) {
+		checkpoint, err := db.InsertAuditLogCheckpoint(context.Background(), database.InsertAuditLogCheckpointParams{
+			Sequence:  1,
+			Hash:      []byte{1},
+			Signature: []byte{2},
+			CreatedAt: database.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args().Asserts(rbac.ResourceAuditLog, rbac.ActionRead).Returns(checkpoint)
+	}))
+	s.Run("GetAuditLogCheckpoints", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
 }
 
 func (s *MethodTestSuite) TestFile() {
@@ -620,6 +699,236 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 	}))
 }
 
+func (s *MethodTestSuite) TestWebhook() {
+	s.Run("InsertWebhook", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWebhookParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionCreate)
+	}))
+	s.Run("GetWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(w.ID).Asserts(w, rbac.ActionRead).Returns(w)
+	}))
+	s.Run("GetWebhooks", s.Subtest(func(db database.Store, check *expects) {
+		w1 := dbgen.Webhook(s.T(), db, database.Webhook{Name: "a"})
+		w2 := dbgen.Webhook(s.T(), db, database.Webhook{Name: "b"})
+		check.Args().Asserts(w1, rbac.ActionRead, w2, rbac.ActionRead).Returns(slice.New(w1, w2))
+	}))
+	s.Run("UpdateWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.UpdateWebhookByIDParams{
+			ID:   w.ID,
+			Name: w.Name,
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(w.ID).Asserts(w, rbac.ActionDelete)
+	}))
+	s.Run("GetWebhookDeliveriesByWebhookID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: w.ID,
+		}).Asserts(w, rbac.ActionRead).Returns([]database.WebhookDelivery{})
+	}))
+}
+
+func (s *MethodTestSuite) TestCustomRole() {
+	s.Run("InsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertCustomRoleParams{
+			ID:          uuid.New(),
+			Name:        "viewer",
+			Permissions: json.RawMessage("[]"),
+		}).Asserts(rbac.ResourceCustomRole, rbac.ActionCreate)
+	}))
+	s.Run("GetCustomRoleByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(r.ID).Asserts(r, rbac.ActionRead).Returns(r)
+	}))
+	s.Run("GetCustomRoles", s.Subtest(func(db database.Store, check *expects) {
+		r1 := dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "a"})
+		r2 := dbgen.CustomRole(s.T(), db, database.CustomRole{Name: "b"})
+		check.Args([]string{}).Asserts(r1, rbac.ActionRead, r2, rbac.ActionRead).Returns(slice.New(r1, r2))
+	}))
+	s.Run("UpdateCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(database.UpdateCustomRoleParams{
+			ID:          r.ID,
+			DisplayName: r.DisplayName,
+			Permissions: r.Permissions,
+		}).Asserts(r, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.CustomRole(s.T(), db, database.CustomRole{})
+		check.Args(r.ID).Asserts(r, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderApp() {
+	s.Run("InsertOAuth2ProviderApp", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertOAuth2ProviderAppParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(app, rbac.ActionRead).Returns(app)
+	}))
+	s.Run("GetOAuth2ProviderApps", s.Subtest(func(db database.Store, check *expects) {
+		a1 := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "a"})
+		a2 := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "b"})
+		check.Args().Asserts(a1, rbac.ActionRead, a2, rbac.ActionRead).Returns(slice.New(a1, a2))
+	}))
+	s.Run("UpdateOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.UpdateOAuth2ProviderAppByIDParams{
+			ID:   app.ID,
+			Name: app.Name,
+		}).Asserts(app, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(app, rbac.ActionDelete)
+	}))
+	s.Run("GetOAuth2ProviderAppsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(u.ID).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(u.ID.String()), rbac.ActionRead).Returns([]database.GetOAuth2ProviderAppsByUserIDRow{{
+			ID:          app.ID,
+			Name:        app.Name,
+			Icon:        app.Icon,
+			CallbackURL: app.CallbackURL,
+			TokenCount:  1,
+			LastUsed:    key.LastUsed,
+		}})
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppSecret() {
+	s.Run("InsertOAuth2ProviderAppSecret", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppSecretParams{
+			ID:    uuid.New(),
+			AppID: app.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.ID).Asserts(secret, rbac.ActionRead).Returns(secret)
+	}))
+	s.Run("GetOAuth2ProviderAppSecretsByAppID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(app.ID).Asserts(secret, rbac.ActionRead).Returns([]database.OAuth2ProviderAppSecret{secret})
+	}))
+	s.Run("UpdateOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(database.UpdateOAuth2ProviderAppSecretByIDParams{
+			ID: secret.ID,
+		}).Asserts(secret, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.ID).Asserts(secret, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppCodeToken() {
+	s.Run("InsertOAuth2ProviderAppCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppCodeParams{
+			ID:     uuid.New(),
+			UserID: u.ID,
+			AppID:  app.ID,
+			Scope:  database.APIKeyScopeAll,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(u.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{UserID: u.ID, AppID: app.ID})
+		check.Args(code.ID).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderAppCodeByHashedSecret", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{UserID: u.ID, AppID: app.ID})
+		check.Args(code.HashedSecret).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{UserID: u.ID, AppID: app.ID})
+		check.Args(code.ID).Asserts(code, rbac.ActionDelete).Returns(code)
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodesByAppAndUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		_ = dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{UserID: u.ID, AppID: app.ID})
+		check.Args(database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(u.ID.String()), rbac.ActionDelete)
+	}))
+	s.Run("InsertOAuth2ProviderAppToken", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		check.Args(database.InsertOAuth2ProviderAppTokenParams{
+			ID:          uuid.New(),
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(u.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderAppTokenByHashedRefreshToken", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(token.HashedRefreshToken).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithID(token.ID).WithOwner(u.ID.String()), rbac.ActionRead).Returns(token)
+	}))
+	s.Run("DeleteOAuth2ProviderAppTokenByAPIKeyID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(key.ID).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(u.ID.String()), rbac.ActionDelete).Returns(token)
+	}))
+	s.Run("DeleteOAuth2ProviderAppTokensByAppAndUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(u.ID.String()), rbac.ActionDelete)
+	}))
+}
+
 func (s *MethodTestSuite) TestTemplate() {
 	s.Run("GetPreviousTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
 		tvid := uuid.New()
@@ -952,6 +1261,118 @@ func (s *MethodTestSuite) TestUser() {
 			UpdatedAt: key.UpdatedAt,
 		}).Asserts(key, rbac.ActionUpdate).Returns(key)
 	}))
+	s.Run("GetUserNotificationPreferences", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u.UserDataRBACObject(), rbac.ActionRead).Returns([]database.UserNotificationPreference{})
+	}))
+	s.Run("UpsertUserNotificationPreference", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserNotificationPreferenceParams{
+			UserID:   u.ID,
+			Kind:     database.NotificationKindWorkspaceAutostop,
+			Disabled: true,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetUserTOTPByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		userTOTP, err := db.UpsertUserTOTP(context.Background(), database.UpsertUserTOTPParams{
+			UserID: u.ID,
+			Secret: "secret",
+		})
+		require.NoError(s.T(), err)
+		check.Args(u.ID).Asserts(userTOTP, rbac.ActionRead).Returns(userTOTP)
+	}))
+	s.Run("UpsertUserTOTP", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserTOTPParams{
+			UserID: u.ID,
+			Secret: "secret",
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("UpdateUserTOTPUsed", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		userTOTP, err := db.UpsertUserTOTP(context.Background(), database.UpsertUserTOTPParams{
+			UserID: u.ID,
+			Secret: "secret",
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateUserTOTPUsedParams{
+			UserID:       u.ID,
+			LastUsedStep: 1,
+		}).Asserts(userTOTP, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteUserTOTPByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u.UserDataRBACObject(), rbac.ActionDelete).Returns()
+	}))
+	s.Run("GetUserTOTPRecoveryCodesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u.UserDataRBACObject(), rbac.ActionRead).Returns([]database.UserTOTPRecoveryCode{})
+	}))
+	s.Run("InsertUserTOTPRecoveryCodes", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserTOTPRecoveryCodesParams{
+			UserID:      u.ID,
+			HashedCodes: [][]byte{[]byte("code")},
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionCreate).Returns()
+	}))
+	s.Run("UseUserTOTPRecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		err := db.InsertUserTOTPRecoveryCodes(context.Background(), database.InsertUserTOTPRecoveryCodesParams{
+			UserID:      u.ID,
+			HashedCodes: [][]byte{[]byte("code")},
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UseUserTOTPRecoveryCodeParams{
+			UserID:     u.ID,
+			HashedCode: []byte("code"),
+			UsedAt:     sql.NullTime{Time: time.Now(), Valid: true},
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteUserTOTPRecoveryCodesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u.UserDataRBACObject(), rbac.ActionDelete).Returns()
+	}))
+	s.Run("InsertUserLoginFailure", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserLoginFailureParams{
+			UserID:    u.ID,
+			CreatedAt: time.Now(),
+		}).Asserts(rbac.ResourceUser.WithID(u.ID), rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetUserLoginFailureCounts", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetUserLoginFailureCountsParams{
+			UserID: u.ID,
+			Since:  time.Now().Add(-time.Hour),
+		}).Asserts(rbac.ResourceUser.WithID(u.ID), rbac.ActionRead).Returns(database.GetUserLoginFailureCountsRow{})
+	}))
+	s.Run("DeleteUserLoginFailures", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUser.WithID(u.ID), rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetUserPasswordHistory", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetUserPasswordHistoryParams{
+			UserID:     u.ID,
+			LimitCount: 5,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate).Returns([]database.UserPasswordHistory{})
+	}))
+	s.Run("InsertUserPasswordHistory", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserPasswordHistoryParams{
+			UserID:         u.ID,
+			HashedPassword: []byte("hash"),
+			CreatedAt:      time.Now(),
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteOldUserPasswordHistory", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.DeleteOldUserPasswordHistoryParams{
+			UserID: u.ID,
+			Keep:   5,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate).Returns()
+	}))
 	s.Run("GetGitAuthLink", s.Subtest(func(db database.Store, check *expects) {
 		link := dbgen.GitAuthLink(s.T(), db, database.GitAuthLink{})
 		check.Args(database.GetGitAuthLinkParams{
@@ -1063,6 +1484,52 @@ func (s *MethodTestSuite) TestWorkspace() {
 			StartupLogsOverflowed: true,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAgentScriptStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		script := dbgen.WorkspaceAgentScript(s.T(), db, database.WorkspaceAgentScript{AgentID: agt.ID})
+		check.Args(database.UpdateWorkspaceAgentScriptStatusByIDParams{
+			ID:      script.ID,
+			AgentID: agt.ID,
+			Status:  database.WorkspaceAgentScriptStatusRunning,
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceAgentSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.InsertWorkspaceAgentSessionRecordingParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+			Type:        database.SessionRecordingTypeSSH,
+		}).Asserts(ws.SessionRecordingRBAC(), rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceAgentSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		recording := dbgen.WorkspaceAgentSessionRecording(s.T(), db, database.WorkspaceAgentSessionRecording{
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		check.Args(recording.ID).Asserts(ws.SessionRecordingRBAC(), rbac.ActionRead).Returns(recording)
+	}))
+	s.Run("GetWorkspaceAgentSessionRecordingsByAgentID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		_ = dbgen.WorkspaceAgentSessionRecording(s.T(), db, database.WorkspaceAgentSessionRecording{
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		check.Args(agt.ID).Asserts(ws.SessionRecordingRBAC(), rbac.ActionRead)
+	}))
 	s.Run("UpdateWorkspaceAgentStartupByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1142,6 +1609,23 @@ func (s *MethodTestSuite) TestWorkspace() {
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
 		check.Args(agt.ID).Asserts(ws, rbac.ActionRead).Returns(ws)
 	}))
+	s.Run("GetWorkspacesLockedSince", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		err := db.UpdateTemplateScheduleByID(context.Background(), database.UpdateTemplateScheduleByIDParams{
+			ID:        tpl.ID,
+			LockedTTL: int64(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{TemplateID: tpl.ID})
+		err = db.UpdateWorkspaceLockedDeletingAt(context.Background(), database.UpdateWorkspaceLockedDeletingAtParams{
+			ID:       ws.ID,
+			LockedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		require.NoError(s.T(), err)
+		ws, err = db.GetWorkspaceByID(context.Background(), ws.ID)
+		require.NoError(s.T(), err)
+		check.Args(time.Now().Add(-time.Hour)).Asserts(ws, rbac.ActionRead).Returns(slice.New(ws))
+	}))
 	s.Run("GetWorkspaceByOwnerIDAndName", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.GetWorkspaceByOwnerIDAndNameParams{
@@ -1150,6 +1634,14 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Name:    ws.Name,
 		}).Asserts(ws, rbac.ActionRead).Returns(ws)
 	}))
+	s.Run("GetWorkspaceGroupRoles", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceUserRoles", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead)
+	}))
 	s.Run("GetWorkspaceResourceByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1184,7 +1676,7 @@ func (s *MethodTestSuite) TestWorkspace() {
 			WorkspaceID: w.ID,
 			Transition:  database.WorkspaceTransitionStart,
 			Reason:      database.BuildReasonInitiator,
-		}).Asserts(w.WorkspaceBuildRBAC(database.WorkspaceTransitionStart), rbac.ActionUpdate)
+		}).Asserts(w.WorkspaceBuildRBAC(database.WorkspaceTransitionStart), rbac.ActionCreate)
 	}))
 	s.Run("Delete/InsertWorkspaceBuild", s.Subtest(func(db database.Store, check *expects) {
 		w := dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1201,7 +1693,7 @@ func (s *MethodTestSuite) TestWorkspace() {
 			WorkspaceBuildID: b.ID,
 			Name:             []string{"foo", "bar"},
 			Value:            []string{"baz", "qux"},
-		}).Asserts(w, rbac.ActionUpdate)
+		}).Asserts(w.WorkspaceBuildRBAC(b.Transition), rbac.ActionCreate)
 	}))
 	s.Run("UpdateWorkspace", s.Subtest(func(db database.Store, check *expects) {
 		w := dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1211,6 +1703,37 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: w.ID,
 		}).Asserts(w, rbac.ActionUpdate).Returns(expected)
 	}))
+	s.Run("UpdateWorkspaceOwnerByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateWorkspaceOwnerByIDParams{
+			ID:      w.ID,
+			OwnerID: u.ID,
+		}).Asserts(w, rbac.ActionUpdate, rbac.ResourceWorkspace.InOrg(w.OrganizationID).WithOwner(u.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("ClaimPrebuiltWorkspace", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.ClaimPrebuiltWorkspaceParams{
+			ID:               w.ID,
+			PrebuildsOwnerID: w.OwnerID,
+			NewOwnerID:       u.ID,
+			Name:             "claimed",
+		}).Asserts(w, rbac.ActionUpdate, rbac.ResourceWorkspace.InOrg(w.OrganizationID).WithOwner(u.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspacesByOwnerID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(ws.OwnerID).Asserts(ws, rbac.ActionRead).Returns([]database.Workspace{ws})
+	}))
+	s.Run("UpdateWorkspaceAgentAuthTokensByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		_ = dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentAuthTokensByWorkspaceIDParams{
+			WorkspaceID: ws.ID,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("InsertWorkspaceAgentStat", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.InsertWorkspaceAgentStatParams{
@@ -1228,6 +1751,12 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Health: database.WorkspaceAppHealthDisabled,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceACLByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceACLByIDParams{
+			ID: ws.ID,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAutostart", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.UpdateWorkspaceAutostartParams{
@@ -1410,6 +1939,92 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
 	}))
+	s.Run("GetEnabledWebhooksByEvent", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.WebhookEventUserCreated).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("InsertWebhookDelivery", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.InsertWebhookDeliveryParams{
+			ID:        uuid.New(),
+			WebhookID: w.ID,
+			Event:     database.WebhookEventUserCreated,
+			Payload:   []byte("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AcquireWebhookDeliveriesParams{
+			Now:      time.Now(),
+			LimitOpt: 10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWebhookDeliveryByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Webhook(s.T(), db, database.Webhook{})
+		id := uuid.New()
+		err := db.InsertWebhookDelivery(context.Background(), database.InsertWebhookDeliveryParams{
+			ID:        id,
+			WebhookID: w.ID,
+			Event:     database.WebhookEventUserCreated,
+			Payload:   []byte("{}"),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWebhookDeliveryByIDParams{
+			ID:     id,
+			Status: database.WebhookDeliveryStatusDelivered,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOldWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteAuditLogsByIDs", s.Subtest(func(db database.Store, check *expects) {
+		alog := dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args([]uuid.UUID{alog.ID}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("InsertAuditLogCheckpoint", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertAuditLogCheckpointParams{
+			Sequence:  1,
+			Hash:      []byte{1},
+			Signature: []byte{2},
+			CreatedAt: database.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("InsertNotificationMessage", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertNotificationMessageParams{
+			ID:     uuid.New(),
+			UserID: u.ID,
+			Kind:   database.NotificationKindTokenExpiring,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AcquireNotificationMessagesParams{
+			Now:            time.Now(),
+			LeaseExpiresAt: time.Now().Add(time.Minute),
+			LimitOpt:       10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateNotificationMessageByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		id := uuid.New()
+		err := db.InsertNotificationMessage(context.Background(), database.InsertNotificationMessageParams{
+			ID:     id,
+			UserID: u.ID,
+			Kind:   database.NotificationKindTokenExpiring,
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateNotificationMessageByIDParams{
+			ID:     id,
+			Status: database.NotificationMessageStatusSent,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOldNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("GetLatestWorkspaceBuildsWithDeadlineBetween", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetLatestWorkspaceBuildsWithDeadlineBetweenParams{
+			StartTime: time.Now(),
+			EndTime:   time.Now().Add(time.Hour),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: add provisioner job resource type
 		_ = dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
@@ -1499,6 +2114,24 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			SharingLevel: database.AppSharingLevelOwner,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceAgentScript", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentScriptParams{
+			ID:      uuid.New(),
+			AgentID: uuid.New(),
+			Name:    "script",
+			RunOn:   database.WorkspaceAgentScriptRunOnStart,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceAgentScriptsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		script := dbgen.WorkspaceAgentScript(s.T(), db, database.WorkspaceAgentScript{AgentID: agt.ID})
+		check.Args([]uuid.UUID{agt.ID}).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead).
+			Returns([]database.WorkspaceAgentScript{script})
+	}))
 	s.Run("InsertWorkspaceResourceMetadata", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceResourceMetadataParams{
 			WorkspaceResourceID: uuid.New(),
@@ -1,6 +1,7 @@
 package dbfake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -96,6 +97,196 @@ type FakeQuerier struct {
 	*data
 }
 
+func (q *FakeQuerier) CountUnchainedAuditLogs(_ context.Context, before time.Time) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, alog := range q.auditLogs {
+		if alog.Time.Before(before) && !q.isAuditLogChainedNoLock(alog.ID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *FakeQuerier) GetAuditLogCheckpoints(_ context.Context) ([]database.AuditLogCheckpoint, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	checkpoints := slices.Clone(q.auditLogCheckpoints)
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Sequence < checkpoints[j].Sequence
+	})
+	return checkpoints, nil
+}
+
+func (q *FakeQuerier) GetAuditLogHashes(_ context.Context, arg database.GetAuditLogHashesParams) ([]database.AuditLogHash, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	hashes := make([]database.AuditLogHash, 0)
+	for _, hash := range q.auditLogHashes {
+		if hash.Sequence > arg.AfterSequence && hash.Sequence <= arg.MaxSequence {
+			hashes = append(hashes, hash)
+		}
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i].Sequence < hashes[j].Sequence
+	})
+	if len(hashes) > int(arg.LimitCount) {
+		hashes = hashes[:arg.LimitCount]
+	}
+	return hashes, nil
+}
+
+func (q *FakeQuerier) GetAuditLogsByIDs(_ context.Context, ids []uuid.UUID) ([]database.AuditLog, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if slices.Contains(ids, alog.ID) {
+			logs = append(logs, alog)
+		}
+	}
+	return logs, nil
+}
+
+func (q *FakeQuerier) GetLatestAuditLogCheckpoint(_ context.Context) (database.AuditLogCheckpoint, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var latest database.AuditLogCheckpoint
+	for _, checkpoint := range q.auditLogCheckpoints {
+		if checkpoint.Sequence > latest.Sequence {
+			latest = checkpoint
+		}
+	}
+	if latest.Sequence == 0 {
+		return database.AuditLogCheckpoint{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
+func (q *FakeQuerier) GetLatestAuditLogHash(_ context.Context) (database.AuditLogHash, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var latest database.AuditLogHash
+	for _, hash := range q.auditLogHashes {
+		if hash.Sequence > latest.Sequence {
+			latest = hash
+		}
+	}
+	if latest.Sequence == 0 {
+		return database.AuditLogHash{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
+func (q *FakeQuerier) GetPrunedAuditLogHashesAfter(_ context.Context, arg database.GetPrunedAuditLogHashesAfterParams) ([]database.AuditLogHash, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	hashes := make([]database.AuditLogHash, 0)
+	for _, hash := range q.auditLogHashes {
+		if !hash.PrunedAt.Valid || !hash.ResourceType.Valid || !hash.AuditLogTime.Valid {
+			continue
+		}
+		if hash.ResourceType.ResourceType == arg.ResourceType && hash.AuditLogTime.Time.After(arg.After) && hash.Sequence <= arg.MaxSequence {
+			hashes = append(hashes, hash)
+		}
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i].Sequence < hashes[j].Sequence
+	})
+	if len(hashes) > int(arg.LimitCount) {
+		hashes = hashes[:arg.LimitCount]
+	}
+	return hashes, nil
+}
+
+func (q *FakeQuerier) GetUnchainedAuditLogs(_ context.Context, arg database.GetUnchainedAuditLogsParams) ([]database.AuditLog, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if alog.Time.Before(arg.After) || q.isAuditLogChainedNoLock(alog.ID) {
+			continue
+		}
+		logs = append(logs, alog)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		if !logs[i].Time.Equal(logs[j].Time) {
+			return logs[i].Time.Before(logs[j].Time)
+		}
+		return bytes.Compare(logs[i].ID[:], logs[j].ID[:]) < 0
+	})
+	if len(logs) > int(arg.LimitCount) {
+		logs = logs[:arg.LimitCount]
+	}
+	return logs, nil
+}
+
+func (q *FakeQuerier) InsertAuditLogCheckpoint(_ context.Context, arg database.InsertAuditLogCheckpointParams) (database.AuditLogCheckpoint, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.AuditLogCheckpoint{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, checkpoint := range q.auditLogCheckpoints {
+		if checkpoint.Sequence == arg.Sequence {
+			return database.AuditLogCheckpoint{}, errDuplicateKey
+		}
+	}
+	checkpoint := database.AuditLogCheckpoint(arg)
+	q.auditLogCheckpoints = append(q.auditLogCheckpoints, checkpoint)
+	return checkpoint, nil
+}
+
+func (q *FakeQuerier) InsertAuditLogHash(_ context.Context, arg database.InsertAuditLogHashParams) (database.AuditLogHash, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.AuditLogHash{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, hash := range q.auditLogHashes {
+		if hash.Sequence == arg.Sequence || hash.AuditLogID == arg.AuditLogID {
+			return database.AuditLogHash{}, errDuplicateKey
+		}
+	}
+	hash := database.AuditLogHash{
+		Sequence:     arg.Sequence,
+		AuditLogID:   arg.AuditLogID,
+		PrevHash:     arg.PrevHash,
+		Hash:         arg.Hash,
+		CreatedAt:    arg.CreatedAt,
+		AuditLogTime: arg.AuditLogTime,
+		ResourceType: arg.ResourceType,
+		ContentHash:  arg.ContentHash,
+	}
+	q.auditLogHashes = append(q.auditLogHashes, hash)
+	return hash, nil
+}
+
 func (*FakeQuerier) Wrappers() []string {
 	return []string{}
 }
@@ -116,12 +307,20 @@ type data struct {
 	// New tables
 	workspaceAgentStats       []database.WorkspaceAgentStat
 	auditLogs                 []database.AuditLog
+	auditLogHashes            []database.AuditLogHash
+	auditLogCheckpoints       []database.AuditLogCheckpoint
+	customRoles               []database.CustomRole
 	files                     []database.File
 	gitAuthLinks              []database.GitAuthLink
 	gitSSHKey                 []database.GitSSHKey
 	groupMembers              []database.GroupMember
 	groups                    []database.Group
 	licenses                  []database.License
+	notificationMessages      []database.NotificationMessage
+	oauth2ProviderApps        []database.OAuth2ProviderApp
+	oauth2ProviderAppSecrets  []database.OAuth2ProviderAppSecret
+	oauth2ProviderAppCodes    []database.OAuth2ProviderAppCode
+	oauth2ProviderAppTokens   []database.OAuth2ProviderAppToken
 	parameterSchemas          []database.ParameterSchema
 	provisionerDaemons        []database.ProvisionerDaemon
 	provisionerJobLogs        []database.ProvisionerJobLog
@@ -131,9 +330,18 @@ type data struct {
 	templateVersionParameters []database.TemplateVersionParameter
 	templateVersionVariables  []database.TemplateVersionVariable
 	templates                 []database.TemplateTable
+	userLoginFailures         []database.UserLoginFailure
+	userNotificationPrefs     []database.UserNotificationPreference
+	userPasswordHistory       []database.UserPasswordHistory
+	userTOTPs                 []database.UserTOTP
+	userTOTPRecoveryCodes     []database.UserTOTPRecoveryCode
+	webhooks                  []database.Webhook
+	webhookDeliveries         []database.WebhookDelivery
 	workspaceAgents           []database.WorkspaceAgent
 	workspaceAgentMetadata    []database.WorkspaceAgentMetadatum
 	workspaceAgentLogs        []database.WorkspaceAgentStartupLog
+	workspaceAgentRecordings  []database.WorkspaceAgentSessionRecording
+	workspaceAgentScripts     []database.WorkspaceAgentScript
 	workspaceApps             []database.WorkspaceApp
 	workspaceBuilds           []database.WorkspaceBuildTable
 	workspaceBuildParameters  []database.WorkspaceBuildParameter
@@ -267,23 +475,33 @@ func (q *FakeQuerier) getUserByIDNoLock(id uuid.UUID) (database.User, error) {
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) isAuditLogChainedNoLock(id uuid.UUID) bool {
+	for _, hash := range q.auditLogHashes {
+		if hash.AuditLogID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func convertUsers(users []database.User, count int64) []database.GetUsersRow {
 	rows := make([]database.GetUsersRow, len(users))
 	for i, u := range users {
 		rows[i] = database.GetUsersRow{
-			ID:             u.ID,
-			Email:          u.Email,
-			Username:       u.Username,
-			HashedPassword: u.HashedPassword,
-			CreatedAt:      u.CreatedAt,
-			UpdatedAt:      u.UpdatedAt,
-			Status:         u.Status,
-			RBACRoles:      u.RBACRoles,
-			LoginType:      u.LoginType,
-			AvatarURL:      u.AvatarURL,
-			Deleted:        u.Deleted,
-			LastSeenAt:     u.LastSeenAt,
-			Count:          count,
+			ID:               u.ID,
+			Email:            u.Email,
+			Username:         u.Username,
+			HashedPassword:   u.HashedPassword,
+			CreatedAt:        u.CreatedAt,
+			UpdatedAt:        u.UpdatedAt,
+			Status:           u.Status,
+			RBACRoles:        u.RBACRoles,
+			LoginType:        u.LoginType,
+			AvatarURL:        u.AvatarURL,
+			Deleted:          u.Deleted,
+			LastSeenAt:       u.LastSeenAt,
+			IsServiceAccount: u.IsServiceAccount,
+			Count:            count,
 		}
 	}
 
@@ -578,6 +796,30 @@ func (q *FakeQuerier) getGroupByIDNoLock(_ context.Context, id uuid.UUID) (datab
 	return database.Group{}, sql.ErrNoRows
 }
 
+// deleteOAuth2ProviderAppTokensNoLock deletes the tokens matching the filter
+// and their API keys, like the trigger on the table does.
+func (q *FakeQuerier) deleteOAuth2ProviderAppTokensNoLock(filter func(token database.OAuth2ProviderAppToken) bool) {
+	keyIDs := make(map[string]struct{})
+	tokens := make([]database.OAuth2ProviderAppToken, 0, len(q.oauth2ProviderAppTokens))
+	for _, token := range q.oauth2ProviderAppTokens {
+		if filter(token) {
+			keyIDs[token.APIKeyID] = struct{}{}
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	q.oauth2ProviderAppTokens = tokens
+
+	keys := make([]database.APIKey, 0, len(q.apiKeys))
+	for _, key := range q.apiKeys {
+		if _, ok := keyIDs[key.ID]; ok {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	q.apiKeys = keys
+}
+
 // isNull is only used in dbfake, so reflect is ok. Use this to make the logic
 // look more similar to the postgres.
 func isNull(v interface{}) bool {
@@ -600,6 +842,44 @@ func (*FakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
 
+func (q *FakeQuerier) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	due := make([]int, 0)
+	for i, message := range q.notificationMessages {
+		if message.Status != database.NotificationMessageStatusPending {
+			continue
+		}
+		if message.NextAttemptAt.After(arg.Now) {
+			continue
+		}
+		due = append(due, i)
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return q.notificationMessages[due[i]].NextAttemptAt.Before(q.notificationMessages[due[j]].NextAttemptAt)
+	})
+	if len(due) > int(arg.LimitOpt) {
+		due = due[:arg.LimitOpt]
+	}
+
+	messages := make([]database.NotificationMessage, 0, len(due))
+	for _, i := range due {
+		message := q.notificationMessages[i]
+		message.Attempts++
+		message.LastAttemptAt = sql.NullTime{Time: arg.Now, Valid: true}
+		message.NextAttemptAt = arg.LeaseExpiresAt
+		q.notificationMessages[i] = message
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
 func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -655,6 +935,87 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	enabled := make(map[uuid.UUID]bool)
+	for _, webhook := range q.webhooks {
+		enabled[webhook.ID] = webhook.Enabled
+	}
+	due := make([]int, 0)
+	for i, delivery := range q.webhookDeliveries {
+		if delivery.Status != database.WebhookDeliveryStatusPending {
+			continue
+		}
+		if delivery.NextAttemptAt.After(arg.Now) {
+			continue
+		}
+		if !enabled[delivery.WebhookID] {
+			continue
+		}
+		due = append(due, i)
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return q.webhookDeliveries[due[i]].NextAttemptAt.Before(q.webhookDeliveries[due[j]].NextAttemptAt)
+	})
+	if len(due) > int(arg.LimitOpt) {
+		due = due[:arg.LimitOpt]
+	}
+
+	deliveries := make([]database.WebhookDelivery, 0, len(due))
+	for _, i := range due {
+		delivery := q.webhookDeliveries[i]
+		delivery.Attempts++
+		delivery.LastAttemptAt = sql.NullTime{Time: arg.Now, Valid: true}
+		delivery.NextAttemptAt = arg.LeaseExpiresAt
+		q.webhookDeliveries[i] = delivery
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (q *FakeQuerier) ClaimPrebuiltWorkspace(_ context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.Workspace, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.Workspace{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.ID != arg.ID || workspace.OwnerID != arg.PrebuildsOwnerID {
+			continue
+		}
+		for _, other := range q.workspaces {
+			if other.Deleted || other.ID == workspace.ID || other.OwnerID != arg.NewOwnerID {
+				continue
+			}
+			if strings.EqualFold(other.Name, arg.Name) {
+				return database.Workspace{}, errDuplicateKey
+			}
+		}
+
+		workspace.OwnerID = arg.NewOwnerID
+		workspace.Name = arg.Name
+		workspace.AutostartSchedule = arg.AutostartSchedule
+		workspace.Ttl = arg.Ttl
+		workspace.UpdatedAt = arg.Now
+		workspace.LastUsedAt = arg.Now
+		q.workspaces[i] = workspace
+
+		return workspace, nil
+	}
+
+	return database.Workspace{}, sql.ErrNoRows
+}
+
 func (*FakeQuerier) CleanTailnetCoordinators(_ context.Context) error {
 	return ErrUnimplemented
 }
@@ -669,6 +1030,11 @@ func (q *FakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 		}
 		q.apiKeys[index] = q.apiKeys[len(q.apiKeys)-1]
 		q.apiKeys = q.apiKeys[:len(q.apiKeys)-1]
+
+		// OAuth2 tokens are deleted in cascade.
+		q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+			return token.APIKeyID == id
+		})
 		return nil
 	}
 	return sql.ErrNoRows
@@ -700,10 +1066,69 @@ func (q *FakeQuerier) DeleteApplicationConnectAPIKeysByUserID(_ context.Context,
 	return nil
 }
 
+func (q *FakeQuerier) DeleteAuditLogsByIDs(_ context.Context, ids []uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logs := make([]database.AuditLog, 0, len(q.auditLogs))
+	deleted := make([]uuid.UUID, 0, len(ids))
+	for _, alog := range q.auditLogs {
+		if !slices.Contains(ids, alog.ID) {
+			logs = append(logs, alog)
+			continue
+		}
+		deleted = append(deleted, alog.ID)
+	}
+	q.auditLogs = logs
+	for i, hash := range q.auditLogHashes {
+		if slices.Contains(deleted, hash.AuditLogID) {
+			q.auditLogHashes[i].PrunedAt = sql.NullTime{Time: database.Now(), Valid: true}
+		}
+	}
+	return nil
+}
+
 func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteCustomRole(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, role := range q.customRoles {
+		if role.ID != id {
+			continue
+		}
+		q.customRoles = append(q.customRoles[:i], q.customRoles[i+1:]...)
+
+		// The role is removed from the users and members it is assigned to
+		// by a trigger.
+		removeRole := func(names []string) []string {
+			remaining := make([]string, 0, len(names))
+			for _, name := range names {
+				if name != role.RoleName() {
+					remaining = append(remaining, name)
+				}
+			}
+			return remaining
+		}
+		if !role.OrganizationID.Valid {
+			for j, user := range q.users {
+				q.users[j].RBACRoles = removeRole(user.RBACRoles)
+			}
+			return nil
+		}
+		for j, member := range q.organizationMembers {
+			if member.OrganizationID == role.OrganizationID.UUID {
+				q.organizationMembers[j].Roles = removeRole(member.Roles)
+			}
+		}
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) DeleteGitSSHKey(_ context.Context, userID uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -789,6 +1214,200 @@ func (q *FakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error)
 	return 0, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, app := range q.oauth2ProviderApps {
+		if app.ID != id {
+			continue
+		}
+		q.oauth2ProviderApps = append(q.oauth2ProviderApps[:i], q.oauth2ProviderApps[i+1:]...)
+
+		// Secrets and codes are deleted in cascade, and with the secrets
+		// their tokens.
+		secretIDs := make(map[uuid.UUID]struct{})
+		secrets := make([]database.OAuth2ProviderAppSecret, 0, len(q.oauth2ProviderAppSecrets))
+		for _, secret := range q.oauth2ProviderAppSecrets {
+			if secret.AppID == id {
+				secretIDs[secret.ID] = struct{}{}
+				continue
+			}
+			secrets = append(secrets, secret)
+		}
+		q.oauth2ProviderAppSecrets = secrets
+		codes := make([]database.OAuth2ProviderAppCode, 0, len(q.oauth2ProviderAppCodes))
+		for _, code := range q.oauth2ProviderAppCodes {
+			if code.AppID == id {
+				continue
+			}
+			codes = append(codes, code)
+		}
+		q.oauth2ProviderAppCodes = codes
+		q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+			_, ok := secretIDs[token.AppSecretID]
+			return ok
+		})
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes[:i], q.oauth2ProviderAppCodes[i+1:]...)
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	codes := make([]database.OAuth2ProviderAppCode, 0, len(q.oauth2ProviderAppCodes))
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.AppID == arg.AppID && code.UserID == arg.UserID {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	q.oauth2ProviderAppCodes = codes
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID != id {
+			continue
+		}
+		q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets[:i], q.oauth2ProviderAppSecrets[i+1:]...)
+
+		// Tokens are deleted in cascade.
+		q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+			return token.AppSecretID == id
+		})
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.APIKeyID != apiKeyID {
+			continue
+		}
+		q.deleteOAuth2ProviderAppTokensNoLock(func(t database.OAuth2ProviderAppToken) bool {
+			return t.ID == token.ID
+		})
+		return token, nil
+	}
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	secretIDs := make(map[uuid.UUID]struct{})
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.AppID == arg.AppID {
+			secretIDs[secret.ID] = struct{}{}
+		}
+	}
+	keyIDs := make(map[string]struct{})
+	for _, key := range q.apiKeys {
+		if key.UserID == arg.UserID {
+			keyIDs[key.ID] = struct{}{}
+		}
+	}
+	q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+		_, secretOK := secretIDs[token.AppSecretID]
+		_, keyOK := keyIDs[token.APIKeyID]
+		return secretOK && keyOK
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldNotificationMessages(ctx context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	before := database.Now().Add(-30 * 24 * time.Hour)
+	messages := make([]database.NotificationMessage, 0, len(q.notificationMessages))
+	for _, message := range q.notificationMessages {
+		if message.Status != database.NotificationMessageStatusPending && message.CreatedAt.Before(before) {
+			continue
+		}
+		messages = append(messages, message)
+	}
+	q.notificationMessages = messages
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldUserPasswordHistory(_ context.Context, arg database.DeleteOldUserPasswordHistoryParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	// The history is appended in order, so the latest passwords are last.
+	kept := 0
+	history := make([]database.UserPasswordHistory, 0, len(q.userPasswordHistory))
+	for i := len(q.userPasswordHistory) - 1; i >= 0; i-- {
+		entry := q.userPasswordHistory[i]
+		if entry.UserID == arg.UserID {
+			if kept >= int(arg.Keep) {
+				continue
+			}
+			kept++
+		}
+		history = append([]database.UserPasswordHistory{entry}, history...)
+	}
+	q.userPasswordHistory = history
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	before := database.Now().Add(-30 * 24 * time.Hour)
+	deliveries := make([]database.WebhookDelivery, 0, len(q.webhookDeliveries))
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.Status != database.WebhookDeliveryStatusPending && delivery.CreatedAt.Before(before) {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	q.webhookDeliveries = deliveries
+	return nil
+}
+
 func (*FakeQuerier) DeleteOldWorkspaceAgentStartupLogs(_ context.Context) error {
 	// noop
 	return nil
@@ -820,6 +1439,72 @@ func (*FakeQuerier) DeleteTailnetClient(context.Context, database.DeleteTailnetC
 	return database.DeleteTailnetClientRow{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteUserLoginFailures(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	failures := make([]database.UserLoginFailure, 0, len(q.userLoginFailures))
+	for _, failure := range q.userLoginFailures {
+		if failure.UserID != userID {
+			failures = append(failures, failure)
+		}
+	}
+	q.userLoginFailures = failures
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserTOTPByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID != userID {
+			continue
+		}
+		q.userTOTPs = append(q.userTOTPs[:i], q.userTOTPs[i+1:]...)
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserTOTPRecoveryCodesByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	codes := make([]database.UserTOTPRecoveryCode, 0, len(q.userTOTPRecoveryCodes))
+	for _, code := range q.userTOTPRecoveryCodes {
+		if code.UserID != userID {
+			codes = append(codes, code)
+		}
+	}
+	q.userTOTPRecoveryCodes = codes
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, webhook := range q.webhooks {
+		if webhook.ID != id {
+			continue
+		}
+		q.webhooks = append(q.webhooks[:i], q.webhooks[i+1:]...)
+
+		// Deliveries are deleted in cascade.
+		deliveries := make([]database.WebhookDelivery, 0, len(q.webhookDeliveries))
+		for _, delivery := range q.webhookDeliveries {
+			if delivery.WebhookID == id {
+				continue
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		q.webhookDeliveries = deliveries
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetAPIKeyByID(_ context.Context, id string) (database.APIKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -864,6 +1549,28 @@ func (q *FakeQuerier) GetAPIKeysByLoginType(_ context.Context, t database.LoginT
 	return apiKeys, nil
 }
 
+func (q *FakeQuerier) GetAPIKeysByLoginTypeExpiringBetween(ctx context.Context, arg database.GetAPIKeysByLoginTypeExpiringBetweenParams) ([]database.APIKey, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	apiKeys := make([]database.APIKey, 0)
+	for _, key := range q.apiKeys {
+		if key.LoginType != arg.LoginType {
+			continue
+		}
+		if !key.ExpiresAt.After(arg.StartTime) || key.ExpiresAt.After(arg.EndTime) {
+			continue
+		}
+		apiKeys = append(apiKeys, key)
+	}
+	return apiKeys, nil
+}
+
 func (q *FakeQuerier) GetAPIKeysByUserID(_ context.Context, params database.GetAPIKeysByUserIDParams) ([]database.APIKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -896,7 +1603,7 @@ func (q *FakeQuerier) GetActiveUserCount(_ context.Context) (int64, error) {
 
 	active := int64(0)
 	for _, u := range q.users {
-		if u.Status == database.UserStatusActive && !u.Deleted {
+		if u.Status == database.UserStatusActive && !u.Deleted && !u.IsServiceAccount {
 			active++
 		}
 	}
@@ -910,6 +1617,42 @@ func (q *FakeQuerier) GetAppSecurityKey(_ context.Context) (string, error) {
 	return q.appSecurityKey, nil
 }
 
+func (q *FakeQuerier) GetAuditLogsByTime(_ context.Context, arg database.GetAuditLogsByTimeParams) ([]database.AuditLog, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if alog.Time.Before(arg.AfterTime) || (alog.Time.Equal(arg.AfterTime) && bytes.Compare(alog.ID[:], arg.AfterID[:]) <= 0) {
+			continue
+		}
+		if !alog.Time.Before(arg.Before) {
+			continue
+		}
+		if len(arg.ResourceTypes) > 0 && !slices.Contains(arg.ResourceTypes, alog.ResourceType) {
+			continue
+		}
+		if slices.Contains(arg.ExcludeResourceTypes, alog.ResourceType) {
+			continue
+		}
+		logs = append(logs, alog)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		if !logs[i].Time.Equal(logs[j].Time) {
+			return logs[i].Time.Before(logs[j].Time)
+		}
+		return bytes.Compare(logs[i].ID[:], logs[j].ID[:]) < 0
+	})
+	if len(logs) > int(arg.LimitCount) {
+		logs = logs[:arg.LimitCount]
+	}
+	return logs, nil
+}
+
 func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -963,31 +1706,41 @@ func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAudi
 				continue
 			}
 		}
+		if arg.ConnectionType != "" {
+			var fields struct {
+				ConnectionType string `json:"connection_type"`
+			}
+			_ = json.Unmarshal(alog.AdditionalFields, &fields)
+			if fields.ConnectionType != arg.ConnectionType {
+				continue
+			}
+		}
 
 		user, err := q.getUserByIDNoLock(alog.UserID)
 		userValid := err == nil
 
 		logs = append(logs, database.GetAuditLogsOffsetRow{
-			ID:               alog.ID,
-			RequestID:        alog.RequestID,
-			OrganizationID:   alog.OrganizationID,
-			Ip:               alog.Ip,
-			UserAgent:        alog.UserAgent,
-			ResourceType:     alog.ResourceType,
-			ResourceID:       alog.ResourceID,
-			ResourceTarget:   alog.ResourceTarget,
-			ResourceIcon:     alog.ResourceIcon,
-			Action:           alog.Action,
-			Diff:             alog.Diff,
-			StatusCode:       alog.StatusCode,
-			AdditionalFields: alog.AdditionalFields,
-			UserID:           alog.UserID,
-			UserUsername:     sql.NullString{String: user.Username, Valid: userValid},
-			UserEmail:        sql.NullString{String: user.Email, Valid: userValid},
-			UserCreatedAt:    sql.NullTime{Time: user.CreatedAt, Valid: userValid},
-			UserStatus:       database.NullUserStatus{UserStatus: user.Status, Valid: userValid},
-			UserRoles:        user.RBACRoles,
-			Count:            0,
+			ID:                   alog.ID,
+			RequestID:            alog.RequestID,
+			OrganizationID:       alog.OrganizationID,
+			Ip:                   alog.Ip,
+			UserAgent:            alog.UserAgent,
+			ResourceType:         alog.ResourceType,
+			ResourceID:           alog.ResourceID,
+			ResourceTarget:       alog.ResourceTarget,
+			ResourceIcon:         alog.ResourceIcon,
+			Action:               alog.Action,
+			Diff:                 alog.Diff,
+			StatusCode:           alog.StatusCode,
+			AdditionalFields:     alog.AdditionalFields,
+			UserID:               alog.UserID,
+			UserUsername:         sql.NullString{String: user.Username, Valid: userValid},
+			UserEmail:            sql.NullString{String: user.Email, Valid: userValid},
+			UserCreatedAt:        sql.NullTime{Time: user.CreatedAt, Valid: userValid},
+			UserStatus:           database.NullUserStatus{UserStatus: user.Status, Valid: userValid},
+			UserRoles:            user.RBACRoles,
+			UserIsServiceAccount: sql.NullBool{Bool: user.IsServiceAccount, Valid: userValid},
+			Count:                0,
 		})
 
 		if len(logs) >= int(arg.Limit) {
@@ -1046,6 +1799,35 @@ func (q *FakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 	}, nil
 }
 
+func (q *FakeQuerier) GetCustomRoleByID(_ context.Context, id uuid.UUID) (database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, role := range q.customRoles {
+		if role.ID == id {
+			return role, nil
+		}
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetCustomRoles(_ context.Context, names []string) ([]database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	roles := make([]database.CustomRole, 0)
+	for _, role := range q.customRoles {
+		if len(names) > 0 && !slices.Contains(names, role.Name) {
+			continue
+		}
+		roles = append(roles, role)
+	}
+	sort.SliceStable(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+	return roles, nil
+}
+
 func (q *FakeQuerier) GetDERPMeshKey(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1197,7 +1979,20 @@ func (q *FakeQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (database
 			continue
 		}
 	}
-	return stat, nil
+	return stat, nil
+}
+
+func (q *FakeQuerier) GetEnabledWebhooksByEvent(ctx context.Context, event database.WebhookEvent) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := make([]database.Webhook, 0)
+	for _, webhook := range q.webhooks {
+		if webhook.Enabled && slices.Contains(webhook.Events, event) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
 }
 
 func (q *FakeQuerier) GetFileByHashAndCreator(_ context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
@@ -1448,6 +2243,48 @@ func (q *FakeQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(_ context.Context,
 	return returnBuilds, nil
 }
 
+func (q *FakeQuerier) GetLatestWorkspaceBuildsWithDeadlineBetween(ctx context.Context, arg database.GetLatestWorkspaceBuildsWithDeadlineBetweenParams) ([]database.WorkspaceBuild, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	builds := make([]database.WorkspaceBuild, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if build.Transition != database.WorkspaceTransitionStart {
+			continue
+		}
+		if !build.Deadline.After(arg.StartTime) || build.Deadline.After(arg.EndTime) {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !job.CompletedAt.Valid || job.Error.String != "" {
+			continue
+		}
+		builds = append(builds, build)
+	}
+	return builds, nil
+}
+
 func (q *FakeQuerier) GetLicenseByID(_ context.Context, id int32) (database.License, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1480,6 +2317,150 @@ func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	return q.logoURL, nil
 }
 
+func (q *FakeQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == id {
+			return app, nil
+		}
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if bytes.Equal(code.HashedSecret, hashedSecret) {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	secrets := make([]database.OAuth2ProviderAppSecret, 0)
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.AppID == appID {
+			secrets = append(secrets, secret)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		if secrets[i].CreatedAt.Equal(secrets[j].CreatedAt) {
+			return secrets[i].ID.String() < secrets[j].ID.String()
+		}
+		return secrets[i].CreatedAt.Before(secrets[j].CreatedAt)
+	})
+	return secrets, nil
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx context.Context, hashedRefreshToken []byte) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if bytes.Equal(token.HashedRefreshToken, hashedRefreshToken) {
+			return token, nil
+		}
+	}
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	apps := slices.Clone(q.oauth2ProviderApps)
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].Name == apps[j].Name {
+			return apps[i].ID.String() < apps[j].ID.String()
+		}
+		return apps[i].Name < apps[j].Name
+	})
+	return apps, nil
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.GetOAuth2ProviderAppsByUserIDRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make(map[uuid.UUID]database.GetOAuth2ProviderAppsByUserIDRow)
+	for _, token := range q.oauth2ProviderAppTokens {
+		var key database.APIKey
+		for _, k := range q.apiKeys {
+			if k.ID == token.APIKeyID {
+				key = k
+				break
+			}
+		}
+		if key.UserID != userID {
+			continue
+		}
+		var secret database.OAuth2ProviderAppSecret
+		for _, s := range q.oauth2ProviderAppSecrets {
+			if s.ID == token.AppSecretID {
+				secret = s
+				break
+			}
+		}
+		for _, app := range q.oauth2ProviderApps {
+			if app.ID != secret.AppID {
+				continue
+			}
+			row, ok := rows[app.ID]
+			if !ok {
+				row = database.GetOAuth2ProviderAppsByUserIDRow{
+					ID:          app.ID,
+					Name:        app.Name,
+					Icon:        app.Icon,
+					CallbackURL: app.CallbackURL,
+				}
+			}
+			row.TokenCount++
+			if key.LastUsed.After(row.LastUsed) {
+				row.LastUsed = key.LastUsed
+			}
+			rows[app.ID] = row
+		}
+	}
+
+	apps := maps.Values(rows)
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+	return apps, nil
+}
+
 func (q *FakeQuerier) GetOAuthSigningKey(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2445,6 +3426,87 @@ func (q *FakeQuerier) GetUserLinkByUserIDLoginType(_ context.Context, params dat
 	return database.UserLink{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetUserLoginFailureCounts(_ context.Context, arg database.GetUserLoginFailureCountsParams) (database.GetUserLoginFailureCountsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.GetUserLoginFailureCountsRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var counts database.GetUserLoginFailureCountsRow
+	for _, failure := range q.userLoginFailures {
+		if failure.UserID != arg.UserID || !failure.CreatedAt.After(arg.Since) {
+			continue
+		}
+		counts.Failures++
+		if failure.IPAddress.IPNet.IP.Equal(arg.IPAddress.IPNet.IP) {
+			counts.IPFailures++
+		}
+	}
+	return counts, nil
+}
+
+func (q *FakeQuerier) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.UserNotificationPreference, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	preferences := make([]database.UserNotificationPreference, 0)
+	for _, preference := range q.userNotificationPrefs {
+		if preference.UserID == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+func (q *FakeQuerier) GetUserPasswordHistory(_ context.Context, arg database.GetUserPasswordHistoryParams) ([]database.UserPasswordHistory, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	history := make([]database.UserPasswordHistory, 0)
+	for i := len(q.userPasswordHistory) - 1; i >= 0 && len(history) < int(arg.LimitCount); i-- {
+		if q.userPasswordHistory[i].UserID == arg.UserID {
+			history = append(history, q.userPasswordHistory[i])
+		}
+	}
+	return history, nil
+}
+
+func (q *FakeQuerier) GetUserTOTPByUserID(_ context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID == userID {
+			return userTOTP, nil
+		}
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserTOTPRecoveryCodesByUserID(_ context.Context, userID uuid.UUID) ([]database.UserTOTPRecoveryCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	codes := make([]database.UserTOTPRecoveryCode, 0)
+	for _, code := range q.userTOTPRecoveryCodes {
+		if code.UserID == userID {
+			codes = append(codes, code)
+		}
+	}
+	sort.SliceStable(codes, func(i, j int) bool {
+		return codes[i].CreatedAt.Before(codes[j].CreatedAt)
+	})
+	return codes, nil
+}
+
 func (q *FakeQuerier) GetUsers(_ context.Context, params database.GetUsersParams) ([]database.GetUsersRow, error) {
 	if err := validateDatabaseType(params); err != nil {
 		return nil, err
@@ -2577,6 +3639,53 @@ func (q *FakeQuerier) GetUsersByIDs(_ context.Context, ids []uuid.UUID) ([]datab
 	return users, nil
 }
 
+func (q *FakeQuerier) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	deliveries := make([]database.WebhookDelivery, 0)
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.WebhookID == arg.WebhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if arg.LimitOpt > 0 && len(deliveries) > int(arg.LimitOpt) {
+		deliveries = deliveries[:arg.LimitOpt]
+	}
+	return deliveries, nil
+}
+
+func (q *FakeQuerier) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := slices.Clone(q.webhooks)
+	slices.SortFunc(webhooks, func(a, b database.Webhook) bool {
+		return a.Name < b.Name
+	})
+	return webhooks, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentByAuthToken(_ context.Context, authToken uuid.UUID) (database.WorkspaceAgent, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2640,6 +3749,61 @@ func (q *FakeQuerier) GetWorkspaceAgentMetadata(_ context.Context, workspaceAgen
 	return metadata, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentScriptsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	scripts := make([]database.WorkspaceAgentScript, 0)
+	for _, script := range q.workspaceAgentScripts {
+		if slices.Contains(ids, script.AgentID) {
+			scripts = append(scripts, script)
+		}
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].Name < scripts[j].Name
+	})
+	return scripts, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentSessionRecordingByID(_ context.Context, id uuid.UUID) (database.WorkspaceAgentSessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, recording := range q.workspaceAgentRecordings {
+		if recording.ID == id {
+			return recording, nil
+		}
+	}
+	return database.WorkspaceAgentSessionRecording{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentSessionRecordingsByAgentID(_ context.Context, agentID uuid.UUID) ([]database.GetWorkspaceAgentSessionRecordingsByAgentIDRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	recordings := make([]database.GetWorkspaceAgentSessionRecordingsByAgentIDRow, 0)
+	for _, recording := range q.workspaceAgentRecordings {
+		if recording.AgentID != agentID {
+			continue
+		}
+		recordings = append(recordings, database.GetWorkspaceAgentSessionRecordingsByAgentIDRow{
+			ID:          recording.ID,
+			CreatedAt:   recording.CreatedAt,
+			WorkspaceID: recording.WorkspaceID,
+			AgentID:     recording.AgentID,
+			Type:        recording.Type,
+			StartedAt:   recording.StartedAt,
+			EndedAt:     recording.EndedAt,
+			Truncated:   recording.Truncated,
+			Size:        recording.Size,
+		})
+	}
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].StartedAt.After(recordings[j].StartedAt)
+	})
+	return recordings, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentStartupLogsAfter(_ context.Context, arg database.GetWorkspaceAgentStartupLogsAfterParams) ([]database.WorkspaceAgentStartupLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -3288,6 +4452,23 @@ func (q *FakeQuerier) GetWorkspaces(ctx context.Context, arg database.GetWorkspa
 	return workspaceRows, err
 }
 
+func (q *FakeQuerier) GetWorkspacesByOwnerID(_ context.Context, ownerID uuid.UUID) ([]database.Workspace, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspaces := make([]database.Workspace, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.OwnerID != ownerID {
+			continue
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	slices.SortFunc(workspaces, func(a, b database.Workspace) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return workspaces, nil
+}
+
 func (q *FakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3340,6 +4521,23 @@ func (q *FakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, no
 	return workspaces, nil
 }
 
+func (q *FakeQuerier) GetWorkspacesLockedSince(ctx context.Context, lockedSince time.Time) ([]database.Workspace, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspaces := make([]database.Workspace, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted || !workspace.DeletingAt.Valid {
+			continue
+		}
+		if !workspace.LockedAt.Valid || workspace.LockedAt.Time.Before(lockedSince) {
+			continue
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	return workspaces, nil
+}
+
 func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.APIKey{}, err
@@ -3372,6 +4570,7 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		LoginType:       arg.LoginType,
 		Scope:           arg.Scope,
 		TokenName:       arg.TokenName,
+		AllowList:       arg.AllowList,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -3403,6 +4602,35 @@ func (q *FakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 	return alog, nil
 }
 
+func (q *FakeQuerier) InsertCustomRole(_ context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, role := range q.customRoles {
+		if role.Name == arg.Name && role.OrganizationID == arg.OrganizationID {
+			return database.CustomRole{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	role := database.CustomRole{
+		ID:             arg.ID,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+		Name:           arg.Name,
+		DisplayName:    arg.DisplayName,
+		OrganizationID: arg.OrganizationID,
+		Permissions:    arg.Permissions,
+	}
+	q.customRoles = append(q.customRoles, role)
+	return role, nil
+}
+
 func (q *FakeQuerier) InsertDERPMeshKey(_ context.Context, id string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -3555,6 +4783,141 @@ func (q *FakeQuerier) InsertLicense(
 	return l, nil
 }
 
+func (q *FakeQuerier) InsertNotificationMessage(ctx context.Context, arg database.InsertNotificationMessageParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, message := range q.notificationMessages {
+		if message.ID == arg.ID {
+			return nil
+		}
+	}
+	q.notificationMessages = append(q.notificationMessages, database.NotificationMessage{
+		ID:            arg.ID,
+		UserID:        arg.UserID,
+		CreatedAt:     arg.CreatedAt,
+		Kind:          arg.Kind,
+		Recipient:     arg.Recipient,
+		Subject:       arg.Subject,
+		Body:          arg.Body,
+		Status:        database.NotificationMessageStatusPending,
+		NextAttemptAt: arg.NextAttemptAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.Name == arg.Name {
+			return database.OAuth2ProviderApp{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	app := database.OAuth2ProviderApp{
+		ID:          arg.ID,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+		Name:        arg.Name,
+		Icon:        arg.Icon,
+		CallbackURL: arg.CallbackURL,
+	}
+	q.oauth2ProviderApps = append(q.oauth2ProviderApps, app)
+	return app, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if bytes.Equal(code.HashedSecret, arg.HashedSecret) {
+			return database.OAuth2ProviderAppCode{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	code := database.OAuth2ProviderAppCode{
+		ID:            arg.ID,
+		CreatedAt:     arg.CreatedAt,
+		ExpiresAt:     arg.ExpiresAt,
+		HashedSecret:  arg.HashedSecret,
+		UserID:        arg.UserID,
+		AppID:         arg.AppID,
+		RedirectURI:   arg.RedirectURI,
+		Scope:         arg.Scope,
+		CodeChallenge: arg.CodeChallenge,
+	}
+	q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
+	return code, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	secret := database.OAuth2ProviderAppSecret{
+		ID:            arg.ID,
+		CreatedAt:     arg.CreatedAt,
+		HashedSecret:  arg.HashedSecret,
+		DisplaySecret: arg.DisplaySecret,
+		AppID:         arg.AppID,
+	}
+	q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets, secret)
+	return secret, nil
+}
+
+func (q *FakeQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if bytes.Equal(token.HashedRefreshToken, arg.HashedRefreshToken) || token.APIKeyID == arg.APIKeyID {
+			return database.OAuth2ProviderAppToken{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	token := database.OAuth2ProviderAppToken{
+		ID:                 arg.ID,
+		CreatedAt:          arg.CreatedAt,
+		ExpiresAt:          arg.ExpiresAt,
+		HashedRefreshToken: arg.HashedRefreshToken,
+		AppSecretID:        arg.AppSecretID,
+		APIKeyID:           arg.APIKeyID,
+	}
+	q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens, token)
+	return token, nil
+}
+
 func (q *FakeQuerier) InsertOrganization(_ context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Organization{}, err
@@ -3833,16 +5196,25 @@ func (q *FakeQuerier) InsertUser(_ context.Context, arg database.InsertUserParam
 		}
 	}
 
+	// Same as the PostgreSQL constraint!
+	if arg.IsServiceAccount && arg.LoginType != database.LoginTypeNone {
+		return database.User{}, &pq.Error{
+			Constraint: "service_account_login_type",
+			Table:      "users",
+		}
+	}
+
 	user := database.User{
-		ID:             arg.ID,
-		Email:          arg.Email,
-		HashedPassword: arg.HashedPassword,
-		CreatedAt:      arg.CreatedAt,
-		UpdatedAt:      arg.UpdatedAt,
-		Username:       arg.Username,
-		Status:         database.UserStatusActive,
-		RBACRoles:      arg.RBACRoles,
-		LoginType:      arg.LoginType,
+		ID:               arg.ID,
+		Email:            arg.Email,
+		HashedPassword:   arg.HashedPassword,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		Username:         arg.Username,
+		Status:           database.UserStatusActive,
+		RBACRoles:        arg.RBACRoles,
+		LoginType:        arg.LoginType,
+		IsServiceAccount: arg.IsServiceAccount,
 	}
 	q.users = append(q.users, user)
 	return user, nil
@@ -3890,6 +5262,125 @@ func (q *FakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 	return link, nil
 }
 
+func (q *FakeQuerier) InsertUserLoginFailure(_ context.Context, arg database.InsertUserLoginFailureParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userLoginFailures = append(q.userLoginFailures, database.UserLoginFailure{
+		UserID:    arg.UserID,
+		IPAddress: arg.IPAddress,
+		CreatedAt: arg.CreatedAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) InsertUserPasswordHistory(_ context.Context, arg database.InsertUserPasswordHistoryParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, entry := range q.userPasswordHistory {
+		if entry.UserID == arg.UserID && bytes.Equal(entry.HashedPassword, arg.HashedPassword) {
+			return errDuplicateKey
+		}
+	}
+	q.userPasswordHistory = append(q.userPasswordHistory, database.UserPasswordHistory{
+		UserID:         arg.UserID,
+		HashedPassword: arg.HashedPassword,
+		CreatedAt:      arg.CreatedAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) InsertUserTOTPRecoveryCodes(ctx context.Context, arg database.InsertUserTOTPRecoveryCodesParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, hashedCode := range arg.HashedCodes {
+		for _, code := range q.userTOTPRecoveryCodes {
+			if code.UserID == arg.UserID && bytes.Equal(code.HashedCode, hashedCode) {
+				return errDuplicateKey
+			}
+		}
+		q.userTOTPRecoveryCodes = append(q.userTOTPRecoveryCodes, database.UserTOTPRecoveryCode{
+			UserID:     arg.UserID,
+			HashedCode: hashedCode,
+			CreatedAt:  arg.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func (q *FakeQuerier) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.Name == arg.Name {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	webhook := database.Webhook{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Name:      arg.Name,
+		Url:       arg.Url,
+		Secret:    arg.Secret,
+		Events:    arg.Events,
+		Enabled:   arg.Enabled,
+	}
+	q.webhooks = append(q.webhooks, webhook)
+	return webhook, nil
+}
+
+func (q *FakeQuerier) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.ID == arg.ID {
+			return nil
+		}
+	}
+	q.webhookDeliveries = append(q.webhookDeliveries, database.WebhookDelivery{
+		ID:            arg.ID,
+		WebhookID:     arg.WebhookID,
+		CreatedAt:     arg.CreatedAt,
+		Event:         arg.Event,
+		Payload:       arg.Payload,
+		Status:        database.WebhookDeliveryStatusPending,
+		NextAttemptAt: arg.NextAttemptAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -3910,6 +5401,8 @@ func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		AutostartSchedule: arg.AutostartSchedule,
 		Ttl:               arg.Ttl,
 		LastUsedAt:        arg.LastUsedAt,
+		UserACL:           database.WorkspaceACL{},
+		GroupACL:          database.WorkspaceACL{},
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -3968,6 +5461,74 @@ func (q *FakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentScript(_ context.Context, arg database.InsertWorkspaceAgentScriptParams) (database.WorkspaceAgentScript, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, script := range q.workspaceAgentScripts {
+		if script.ID == arg.ID || (script.AgentID == arg.AgentID && script.Name == arg.Name) {
+			return database.WorkspaceAgentScript{}, errDuplicateKey
+		}
+	}
+
+	dependsOn := arg.DependsOn
+	if dependsOn == nil {
+		dependsOn = []string{}
+	}
+	script := database.WorkspaceAgentScript{
+		ID:               arg.ID,
+		CreatedAt:        arg.CreatedAt,
+		AgentID:          arg.AgentID,
+		Name:             arg.Name,
+		DisplayName:      arg.DisplayName,
+		Script:           arg.Script,
+		RunOn:            arg.RunOn,
+		TimeoutSeconds:   arg.TimeoutSeconds,
+		StartBlocksLogin: arg.StartBlocksLogin,
+		DependsOn:        dependsOn,
+		Status:           database.WorkspaceAgentScriptStatusPending,
+	}
+	q.workspaceAgentScripts = append(q.workspaceAgentScripts, script)
+	return script, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceAgentSessionRecording(_ context.Context, arg database.InsertWorkspaceAgentSessionRecordingParams) (database.WorkspaceAgentSessionRecording, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentSessionRecording{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, recording := range q.workspaceAgentRecordings {
+		if recording.ID == arg.ID {
+			return database.WorkspaceAgentSessionRecording{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	recording := database.WorkspaceAgentSessionRecording{
+		ID:          arg.ID,
+		CreatedAt:   arg.CreatedAt,
+		WorkspaceID: arg.WorkspaceID,
+		AgentID:     arg.AgentID,
+		Type:        arg.Type,
+		StartedAt:   arg.StartedAt,
+		EndedAt:     arg.EndedAt,
+		Truncated:   arg.Truncated,
+		Size:        arg.Size,
+		Data:        arg.Data,
+	}
+	q.workspaceAgentRecordings = append(q.workspaceAgentRecordings, recording)
+	return recording, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentStartupLogs(_ context.Context, arg database.InsertWorkspaceAgentStartupLogsParams) ([]database.WorkspaceAgentStartupLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -3989,6 +5550,7 @@ func (q *FakeQuerier) InsertWorkspaceAgentStartupLogs(_ context.Context, arg dat
 			AgentID:   arg.AgentID,
 			CreatedAt: arg.CreatedAt[index],
 			Level:     arg.Level[index],
+			Source:    arg.Source[index],
 			Output:    output,
 		})
 		outputLength += int32(len(output))
@@ -4241,6 +5803,28 @@ func (q *FakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPI
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateCustomRole(_ context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, role := range q.customRoles {
+		if role.ID != arg.ID {
+			continue
+		}
+		role.UpdatedAt = arg.UpdatedAt
+		role.DisplayName = arg.DisplayName
+		role.Permissions = arg.Permissions
+		q.customRoles[i] = role
+		return role, nil
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateGitAuthLink(_ context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.GitAuthLink{}, err
@@ -4295,46 +5879,117 @@ func (q *FakeQuerier) UpdateGroupByID(_ context.Context, arg database.UpdateGrou
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for i, group := range q.groups {
-		if group.ID == arg.ID {
-			group.Name = arg.Name
-			group.AvatarURL = arg.AvatarURL
-			group.QuotaAllowance = arg.QuotaAllowance
-			q.groups[i] = group
-			return group, nil
+	for i, group := range q.groups {
+		if group.ID == arg.ID {
+			group.Name = arg.Name
+			group.AvatarURL = arg.AvatarURL
+			group.QuotaAllowance = arg.QuotaAllowance
+			q.groups[i] = group
+			return group, nil
+		}
+	}
+	return database.Group{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.OrganizationMember{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, mem := range q.organizationMembers {
+		if mem.UserID == arg.UserID && mem.OrganizationID == arg.OrgID {
+			uniqueRoles := make([]string, 0, len(arg.GrantedRoles))
+			exist := make(map[string]struct{})
+			for _, r := range arg.GrantedRoles {
+				if _, ok := exist[r]; ok {
+					continue
+				}
+				exist[r] = struct{}{}
+				uniqueRoles = append(uniqueRoles, r)
+			}
+			sort.Strings(uniqueRoles)
+
+			mem.Roles = uniqueRoles
+			q.organizationMembers[i] = mem
+			return mem, nil
+		}
+	}
+
+	return database.OrganizationMember{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateNotificationMessageByID(ctx context.Context, arg database.UpdateNotificationMessageByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, message := range q.notificationMessages {
+		if message.ID != arg.ID {
+			continue
+		}
+		message.Status = arg.Status
+		message.NextAttemptAt = arg.NextAttemptAt
+		message.Error = arg.Error
+		message.SentAt = arg.SentAt
+		q.notificationMessages[i] = message
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.Name == arg.Name && app.ID != arg.ID {
+			return database.OAuth2ProviderApp{}, errDuplicateKey
 		}
 	}
-	return database.Group{}, sql.ErrNoRows
+	for i, app := range q.oauth2ProviderApps {
+		if app.ID != arg.ID {
+			continue
+		}
+		app.UpdatedAt = arg.UpdatedAt
+		app.Name = arg.Name
+		app.Icon = arg.Icon
+		app.CallbackURL = arg.CallbackURL
+		q.oauth2ProviderApps[i] = app
+		return app, nil
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
 }
 
-func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
-	if err := validateDatabaseType(arg); err != nil {
-		return database.OrganizationMember{}, err
+func (q *FakeQuerier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
 	}
 
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for i, mem := range q.organizationMembers {
-		if mem.UserID == arg.UserID && mem.OrganizationID == arg.OrgID {
-			uniqueRoles := make([]string, 0, len(arg.GrantedRoles))
-			exist := make(map[string]struct{})
-			for _, r := range arg.GrantedRoles {
-				if _, ok := exist[r]; ok {
-					continue
-				}
-				exist[r] = struct{}{}
-				uniqueRoles = append(uniqueRoles, r)
-			}
-			sort.Strings(uniqueRoles)
-
-			mem.Roles = uniqueRoles
-			q.organizationMembers[i] = mem
-			return mem, nil
+	for i, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID != arg.ID {
+			continue
 		}
+		secret.LastUsedAt = arg.LastUsedAt
+		q.oauth2ProviderAppSecrets[i] = secret
+		return secret, nil
 	}
-
-	return database.OrganizationMember{}, sql.ErrNoRows
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
 }
 
 func (q *FakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.UpdateProvisionerJobByIDParams) error {
@@ -4503,6 +6158,8 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.DisplayName = arg.DisplayName
 		tpl.Description = arg.Description
 		tpl.Icon = arg.Icon
+		tpl.RecordSessions = arg.RecordSessions
+		tpl.PrebuiltWorkspaces = arg.PrebuiltWorkspaces
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -4725,6 +6382,13 @@ func (q *FakeQuerier) UpdateUserLoginType(_ context.Context, arg database.Update
 
 	for i, u := range q.users {
 		if u.ID == arg.UserID {
+			// Same as the PostgreSQL constraint!
+			if u.IsServiceAccount && arg.NewLoginType != database.LoginTypeNone {
+				return database.User{}, &pq.Error{
+					Constraint: "service_account_login_type",
+					Table:      "users",
+				}
+			}
 			u.LoginType = arg.NewLoginType
 			if arg.NewLoginType != database.LoginTypePassword {
 				u.HashedPassword = []byte{}
@@ -4830,6 +6494,82 @@ func (q *FakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUse
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateUserTOTPUsed(ctx context.Context, arg database.UpdateUserTOTPUsedParams) (database.UserTOTP, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID != arg.UserID || userTOTP.LastUsedStep >= arg.LastUsedStep {
+			continue
+		}
+		userTOTP.Enabled = true
+		userTOTP.LastUsedStep = arg.LastUsedStep
+		userTOTP.UpdatedAt = arg.UpdatedAt
+		q.userTOTPs[i] = userTOTP
+		return userTOTP, nil
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.Name == arg.Name && webhook.ID != arg.ID {
+			return database.Webhook{}, errDuplicateKey
+		}
+	}
+	for i, webhook := range q.webhooks {
+		if webhook.ID != arg.ID {
+			continue
+		}
+		webhook.UpdatedAt = arg.UpdatedAt
+		webhook.Name = arg.Name
+		webhook.Url = arg.Url
+		webhook.Secret = arg.Secret
+		webhook.Events = arg.Events
+		webhook.Enabled = arg.Enabled
+		q.webhooks[i] = webhook
+		return webhook, nil
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, delivery := range q.webhookDeliveries {
+		if delivery.ID != arg.ID {
+			continue
+		}
+		delivery.Status = arg.Status
+		delivery.NextAttemptAt = arg.NextAttemptAt
+		delivery.StatusCode = arg.StatusCode
+		delivery.Error = arg.Error
+		delivery.DeliveredAt = arg.DeliveredAt
+		q.webhookDeliveries[i] = delivery
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -4860,6 +6600,59 @@ func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceACLByID(_ context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.ID == arg.ID {
+			workspace.GroupACL = arg.GroupACL
+			workspace.UserACL = arg.UserACL
+
+			q.workspaces[i] = workspace
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWorkspaceAgentAuthTokensByWorkspaceID(_ context.Context, arg database.UpdateWorkspaceAgentAuthTokensByWorkspaceIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	jobIDs := make(map[uuid.UUID]struct{})
+	for _, build := range q.workspaceBuilds {
+		if build.WorkspaceID == arg.WorkspaceID {
+			jobIDs[build.JobID] = struct{}{}
+		}
+	}
+	resourceIDs := make(map[uuid.UUID]struct{})
+	for _, resource := range q.workspaceResources {
+		if _, ok := jobIDs[resource.JobID]; ok {
+			resourceIDs[resource.ID] = struct{}{}
+		}
+	}
+	for i, agent := range q.workspaceAgents {
+		if _, ok := resourceIDs[agent.ResourceID]; !ok {
+			continue
+		}
+		agent.AuthToken = uuid.New()
+		agent.UpdatedAt = arg.UpdatedAt
+		q.workspaceAgents[i] = agent
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -4924,6 +6717,29 @@ func (q *FakeQuerier) UpdateWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) UpdateWorkspaceAgentScriptStatusByID(_ context.Context, arg database.UpdateWorkspaceAgentScriptStatusByIDParams) (database.WorkspaceAgentScript, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentScript{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, script := range q.workspaceAgentScripts {
+		if script.ID != arg.ID || script.AgentID != arg.AgentID {
+			continue
+		}
+		script.Status = arg.Status
+		script.ExitCode = arg.ExitCode
+		script.StartedAt = arg.StartedAt
+		script.EndedAt = arg.EndedAt
+		q.workspaceAgentScripts[i] = script
+		return script, nil
+	}
+	return database.WorkspaceAgentScript{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentStartupByID(_ context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5121,6 +6937,39 @@ func (q *FakeQuerier) UpdateWorkspaceLockedDeletingAt(_ context.Context, arg dat
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceOwnerByID(_ context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Workspace{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.ID != arg.ID {
+			continue
+		}
+		for _, other := range q.workspaces {
+			if other.Deleted || other.ID == workspace.ID || other.OwnerID != arg.OwnerID {
+				continue
+			}
+			if strings.EqualFold(other.Name, workspace.Name) {
+				return database.Workspace{}, errDuplicateKey
+			}
+		}
+
+		workspace.OwnerID = arg.OwnerID
+		workspace.UpdatedAt = arg.UpdatedAt
+		workspace.UserACL = database.WorkspaceACL{}
+		workspace.GroupACL = database.WorkspaceACL{}
+		q.workspaces[i] = workspace
+
+		return workspace, nil
+	}
+
+	return database.Workspace{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceProxy(_ context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5265,6 +7114,83 @@ func (*FakeQuerier) UpsertTailnetCoordinator(context.Context, uuid.UUID) (databa
 	return database.TailnetCoordinator{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) UpsertUserNotificationPreference(ctx context.Context, arg database.UpsertUserNotificationPreferenceParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, preference := range q.userNotificationPrefs {
+		if preference.UserID != arg.UserID || preference.Kind != arg.Kind {
+			continue
+		}
+		preference.Disabled = arg.Disabled
+		preference.UpdatedAt = arg.UpdatedAt
+		q.userNotificationPrefs[i] = preference
+		return nil
+	}
+	q.userNotificationPrefs = append(q.userNotificationPrefs, database.UserNotificationPreference{
+		UserID:    arg.UserID,
+		Kind:      arg.Kind,
+		Disabled:  arg.Disabled,
+		UpdatedAt: arg.UpdatedAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID != arg.UserID {
+			continue
+		}
+		userTOTP.Secret = arg.Secret
+		userTOTP.Enabled = false
+		userTOTP.LastUsedStep = 0
+		userTOTP.UpdatedAt = arg.CreatedAt
+		q.userTOTPs[i] = userTOTP
+		return userTOTP, nil
+	}
+	userTOTP := database.UserTOTP{
+		UserID:    arg.UserID,
+		Secret:    arg.Secret,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.CreatedAt,
+	}
+	q.userTOTPs = append(q.userTOTPs, userTOTP)
+	return userTOTP, nil
+}
+
+func (q *FakeQuerier) UseUserTOTPRecoveryCode(ctx context.Context, arg database.UseUserTOTPRecoveryCodeParams) (database.UserTOTPRecoveryCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserTOTPRecoveryCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, code := range q.userTOTPRecoveryCodes {
+		if code.UserID != arg.UserID || !bytes.Equal(code.HashedCode, arg.HashedCode) || code.UsedAt.Valid {
+			continue
+		}
+		code.UsedAt = arg.UsedAt
+		q.userTOTPRecoveryCodes[i] = code
+		return code, nil
+	}
+	return database.UserTOTPRecoveryCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -5415,7 +7341,7 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 
 	if prepared != nil {
 		// Call this to match the same function calls as the SQL implementation.
-		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWithoutACL())
+		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWithACL())
 		if err != nil {
 			return nil, err
 		}
@@ -5657,6 +7583,70 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 	return q.convertToWorkspaceRowsNoLock(ctx, workspaces, int64(beforePageCount)), nil
 }
 
+func (q *FakeQuerier) GetWorkspaceGroupRoles(_ context.Context, id uuid.UUID) ([]database.WorkspaceGroup, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspace, err := q.getWorkspaceByIDNoLock(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]database.WorkspaceGroup, 0, len(workspace.GroupACL))
+	for k, v := range workspace.GroupACL {
+		group, err := q.getGroupByIDNoLock(context.Background(), uuid.MustParse(k))
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get group by ID: %w", err)
+		}
+		// We don't delete groups from the map if they
+		// get deleted so just skip.
+		if xerrors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+
+		groups = append(groups, database.WorkspaceGroup{
+			Group:   group,
+			Actions: v,
+		})
+	}
+
+	return groups, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceUserRoles(_ context.Context, id uuid.UUID) ([]database.WorkspaceUser, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspace, err := q.getWorkspaceByIDNoLock(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]database.WorkspaceUser, 0, len(workspace.UserACL))
+	for k, v := range workspace.UserACL {
+		user, err := q.getUserByIDNoLock(uuid.MustParse(k))
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get user by ID: %w", err)
+		}
+		// We don't delete users from the map if they
+		// get deleted so just skip.
+		if xerrors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+
+		if user.Deleted || user.Status == database.UserStatusSuspended {
+			continue
+		}
+
+		users = append(users, database.WorkspaceUser{
+			User:    user,
+			Actions: v,
+		})
+	}
+
+	return users, nil
+}
+
 func (q *FakeQuerier) GetAuthorizedUsers(ctx context.Context, arg database.GetUsersParams, prepared rbac.PreparedAuthorized) ([]database.GetUsersRow, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -213,14 +213,15 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 
 func User(t testing.TB, db database.Store, orig database.User) database.User {
 	user, err := db.InsertUser(genCtx, database.InsertUserParams{
-		ID:             takeFirst(orig.ID, uuid.New()),
-		Email:          takeFirst(orig.Email, namesgenerator.GetRandomName(1)),
-		Username:       takeFirst(orig.Username, namesgenerator.GetRandomName(1)),
-		HashedPassword: takeFirstSlice(orig.HashedPassword, []byte(must(cryptorand.String(32)))),
-		CreatedAt:      takeFirst(orig.CreatedAt, database.Now()),
-		UpdatedAt:      takeFirst(orig.UpdatedAt, database.Now()),
-		RBACRoles:      takeFirstSlice(orig.RBACRoles, []string{}),
-		LoginType:      takeFirst(orig.LoginType, database.LoginTypePassword),
+		ID:               takeFirst(orig.ID, uuid.New()),
+		Email:            takeFirst(orig.Email, namesgenerator.GetRandomName(1)),
+		Username:         takeFirst(orig.Username, namesgenerator.GetRandomName(1)),
+		HashedPassword:   takeFirstSlice(orig.HashedPassword, []byte(must(cryptorand.String(32)))),
+		CreatedAt:        takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt:        takeFirst(orig.UpdatedAt, database.Now()),
+		RBACRoles:        takeFirstSlice(orig.RBACRoles, []string{}),
+		LoginType:        takeFirst(orig.LoginType, database.LoginTypePassword),
+		IsServiceAccount: orig.IsServiceAccount,
 	})
 	require.NoError(t, err, "insert user")
 
@@ -385,6 +386,23 @@ func WorkspaceApp(t testing.TB, db database.Store, orig database.WorkspaceApp) d
 	return resource
 }
 
+func WorkspaceAgentScript(t testing.TB, db database.Store, orig database.WorkspaceAgentScript) database.WorkspaceAgentScript {
+	script, err := db.InsertWorkspaceAgentScript(genCtx, database.InsertWorkspaceAgentScriptParams{
+		ID:               takeFirst(orig.ID, uuid.New()),
+		CreatedAt:        takeFirst(orig.CreatedAt, database.Now()),
+		AgentID:          takeFirst(orig.AgentID, uuid.New()),
+		Name:             takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		DisplayName:      takeFirst(orig.DisplayName, namesgenerator.GetRandomName(1)),
+		Script:           takeFirst(orig.Script, "true"),
+		RunOn:            takeFirst(orig.RunOn, database.WorkspaceAgentScriptRunOnStart),
+		TimeoutSeconds:   orig.TimeoutSeconds,
+		StartBlocksLogin: orig.StartBlocksLogin,
+		DependsOn:        takeFirstSlice(orig.DependsOn, []string{}),
+	})
+	require.NoError(t, err, "insert script")
+	return script
+}
+
 func WorkspaceResource(t testing.TB, db database.Store, orig database.WorkspaceResource) database.WorkspaceResource {
 	resource, err := db.InsertWorkspaceResource(genCtx, database.InsertWorkspaceResourceParams{
 		ID:         takeFirst(orig.ID, uuid.New()),
@@ -559,6 +577,107 @@ func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.Workspace
 	return scheme
 }
 
+func WorkspaceAgentSessionRecording(t testing.TB, db database.Store, orig database.WorkspaceAgentSessionRecording) database.WorkspaceAgentSessionRecording {
+	data := takeFirstSlice(orig.Data, []byte(`{"version":2,"width":80,"height":24}`+"\n"))
+	recording, err := db.InsertWorkspaceAgentSessionRecording(genCtx, database.InsertWorkspaceAgentSessionRecordingParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		CreatedAt:   takeFirst(orig.CreatedAt, database.Now()),
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentID:     takeFirst(orig.AgentID, uuid.New()),
+		Type:        takeFirst(orig.Type, database.SessionRecordingTypeSSH),
+		StartedAt:   takeFirst(orig.StartedAt, database.Now().Add(-time.Minute)),
+		EndedAt:     takeFirst(orig.EndedAt, database.Now()),
+		Truncated:   orig.Truncated,
+		Size:        takeFirst(orig.Size, int64(len(data))),
+		Data:        data,
+	})
+	require.NoError(t, err, "insert session recording")
+	return recording
+}
+
+func Webhook(t testing.TB, db database.Store, orig database.Webhook) database.Webhook {
+	webhook, err := db.InsertWebhook(genCtx, database.InsertWebhookParams{
+		ID:        takeFirst(orig.ID, uuid.New()),
+		CreatedAt: takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt: takeFirst(orig.UpdatedAt, database.Now()),
+		Name:      takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		Url:       takeFirst(orig.Url, "http://localhost/webhook"),
+		Secret:    takeFirst(orig.Secret, must(cryptorand.String(32))),
+		Events:    takeFirstSlice(orig.Events, []database.WebhookEvent{database.WebhookEventUserCreated}),
+		Enabled:   orig.Enabled,
+	})
+	require.NoError(t, err, "insert webhook")
+	return webhook
+}
+
+func OAuth2ProviderApp(t testing.TB, db database.Store, seed database.OAuth2ProviderApp) database.OAuth2ProviderApp {
+	app, err := db.InsertOAuth2ProviderApp(genCtx, database.InsertOAuth2ProviderAppParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		CreatedAt:   takeFirst(seed.CreatedAt, database.Now()),
+		UpdatedAt:   takeFirst(seed.UpdatedAt, database.Now()),
+		Name:        takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		Icon:        takeFirst(seed.Icon, ""),
+		CallbackURL: takeFirst(seed.CallbackURL, "http://localhost/callback"),
+	})
+	require.NoError(t, err, "insert oauth2 app")
+	return app
+}
+
+func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAuth2ProviderAppSecret) database.OAuth2ProviderAppSecret {
+	secret, err := db.InsertOAuth2ProviderAppSecret(genCtx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, database.Now()),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
+		DisplaySecret: takeFirst(seed.DisplaySecret, "secret"),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+	})
+	require.NoError(t, err, "insert oauth2 app secret")
+	return secret
+}
+
+func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
+	code, err := db.InsertOAuth2ProviderAppCode(genCtx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, database.Now()),
+		ExpiresAt:     takeFirst(seed.ExpiresAt, database.Now().Add(10*time.Minute)),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte(must(cryptorand.String(32)))),
+		UserID:        takeFirst(seed.UserID, uuid.New()),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+		RedirectURI:   takeFirst(seed.RedirectURI, ""),
+		Scope:         takeFirst(seed.Scope, database.APIKeyScopeAll),
+		CodeChallenge: takeFirst(seed.CodeChallenge, "challenge"),
+	})
+	require.NoError(t, err, "insert oauth2 app code")
+	return code
+}
+
+func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth2ProviderAppToken) database.OAuth2ProviderAppToken {
+	token, err := db.InsertOAuth2ProviderAppToken(genCtx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:                 takeFirst(seed.ID, uuid.New()),
+		CreatedAt:          takeFirst(seed.CreatedAt, database.Now()),
+		ExpiresAt:          takeFirst(seed.ExpiresAt, database.Now().Add(time.Hour)),
+		HashedRefreshToken: takeFirstSlice(seed.HashedRefreshToken, []byte(must(cryptorand.String(32)))),
+		AppSecretID:        takeFirst(seed.AppSecretID, uuid.New()),
+		APIKeyID:           takeFirst(seed.APIKeyID),
+	})
+	require.NoError(t, err, "insert oauth2 app token")
+	return token
+}
+
+func CustomRole(t testing.TB, db database.Store, seed database.CustomRole) database.CustomRole {
+	role, err := db.InsertCustomRole(genCtx, database.InsertCustomRoleParams{
+		ID:             takeFirst(seed.ID, uuid.New()),
+		CreatedAt:      takeFirst(seed.CreatedAt, database.Now()),
+		UpdatedAt:      takeFirst(seed.UpdatedAt, database.Now()),
+		Name:           takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		DisplayName:    takeFirst(seed.DisplayName, "Custom Role"),
+		OrganizationID: seed.OrganizationID,
+		Permissions:    takeFirstSlice(seed.Permissions, json.RawMessage("[]")),
+	})
+	require.NoError(t, err, "insert custom role")
+	return role
+}
+
 func must[V any](v V, err error) V {
 	if err != nil {
 		panic(err)
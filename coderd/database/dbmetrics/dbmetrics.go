@@ -61,6 +61,76 @@ type metricsStore struct {
 	txDuration     prometheus.Histogram
 }
 
+func (m metricsStore) CountUnchainedAuditLogs(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountUnchainedAuditLogs(ctx, before)
+	m.queryLatencies.WithLabelValues("CountUnchainedAuditLogs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetAuditLogCheckpoints(ctx context.Context) ([]database.AuditLogCheckpoint, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogCheckpoints(ctx)
+	m.queryLatencies.WithLabelValues("GetAuditLogCheckpoints").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetAuditLogHashes(ctx context.Context, arg database.GetAuditLogHashesParams) ([]database.AuditLogHash, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogHashes(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAuditLogHashes").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.AuditLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogsByIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetAuditLogsByIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetLatestAuditLogCheckpoint(ctx context.Context) (database.AuditLogCheckpoint, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestAuditLogCheckpoint(ctx)
+	m.queryLatencies.WithLabelValues("GetLatestAuditLogCheckpoint").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetLatestAuditLogHash(ctx context.Context) (database.AuditLogHash, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestAuditLogHash(ctx)
+	m.queryLatencies.WithLabelValues("GetLatestAuditLogHash").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetPrunedAuditLogHashesAfter(ctx context.Context, arg database.GetPrunedAuditLogHashesAfterParams) ([]database.AuditLogHash, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPrunedAuditLogHashesAfter(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetPrunedAuditLogHashesAfter").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUnchainedAuditLogs(ctx context.Context, arg database.GetUnchainedAuditLogsParams) ([]database.AuditLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUnchainedAuditLogs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUnchainedAuditLogs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertAuditLogCheckpoint(ctx context.Context, arg database.InsertAuditLogCheckpointParams) (database.AuditLogCheckpoint, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertAuditLogCheckpoint(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertAuditLogCheckpoint").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertAuditLogHash(ctx context.Context, arg database.InsertAuditLogHashParams) (database.AuditLogHash, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertAuditLogHash(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertAuditLogHash").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) Wrappers() []string {
 	return append(m.s.Wrappers(), wrapname)
 }
@@ -86,6 +156,13 @@ func (m metricsStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64)
 	return err
 }
 
+func (m metricsStore) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireNotificationMessages(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireNotificationMessages").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	start := time.Now()
 	provisionerJob, err := m.s.AcquireProvisionerJob(ctx, arg)
@@ -93,6 +170,20 @@ func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.Ac
 	return provisionerJob, err
 }
 
+func (m metricsStore) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireWebhookDeliveries(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) ClaimPrebuiltWorkspace(ctx context.Context, arg database.ClaimPrebuiltWorkspaceParams) (database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.ClaimPrebuiltWorkspace(ctx, arg)
+	m.queryLatencies.WithLabelValues("ClaimPrebuiltWorkspace").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.CleanTailnetCoordinators(ctx)
@@ -121,12 +212,26 @@ func (m metricsStore) DeleteApplicationConnectAPIKeysByUserID(ctx context.Contex
 	return err
 }
 
+func (m metricsStore) DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteAuditLogsByIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("DeleteAuditLogsByIDs").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	defer m.queryLatencies.WithLabelValues("DeleteCoordinator").Observe(time.Since(start).Seconds())
 	return m.s.DeleteCoordinator(ctx, id)
 }
 
+func (m metricsStore) DeleteCustomRole(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteCustomRole(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteCustomRole").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteGitSSHKey(ctx, userID)
@@ -162,6 +267,69 @@ func (m metricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error
 	return licenseID, err
 }
 
+func (m metricsStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodesByAppAndUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppTokenByAPIKeyID(ctx context.Context, apiKeyID string) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOAuth2ProviderAppTokenByAPIKeyID(ctx, apiKeyID)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppTokenByAPIKeyID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppTokensByAppAndUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldNotificationMessages(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldNotificationMessages(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldNotificationMessages").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldUserPasswordHistory(ctx context.Context, arg database.DeleteOldUserPasswordHistoryParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldUserPasswordHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOldUserPasswordHistory").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldWebhookDeliveries(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWebhookDeliveries(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldWebhookDeliveries").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.DeleteOldWorkspaceAgentStartupLogs(ctx)
@@ -195,6 +363,34 @@ func (m metricsStore) DeleteTailnetClient(ctx context.Context, arg database.Dele
 	return m.s.DeleteTailnetClient(ctx, arg)
 }
 
+func (m metricsStore) DeleteUserLoginFailures(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserLoginFailures(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserLoginFailures").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteUserTOTPByUserID(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserTOTPByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserTOTPByUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteUserTOTPRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserTOTPRecoveryCodesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserTOTPRecoveryCodesByUserID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWebhookByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByID(ctx, id)
@@ -216,6 +412,13 @@ func (m metricsStore) GetAPIKeysByLoginType(ctx context.Context, loginType datab
 	return apiKeys, err
 }
 
+func (m metricsStore) GetAPIKeysByLoginTypeExpiringBetween(ctx context.Context, arg database.GetAPIKeysByLoginTypeExpiringBetweenParams) ([]database.APIKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAPIKeysByLoginTypeExpiringBetween(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAPIKeysByLoginTypeExpiringBetween").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAPIKeysByUserID(ctx context.Context, arg database.GetAPIKeysByUserIDParams) ([]database.APIKey, error) {
 	start := time.Now()
 	apiKeys, err := m.s.GetAPIKeysByUserID(ctx, arg)
@@ -244,6 +447,13 @@ func (m metricsStore) GetAppSecurityKey(ctx context.Context) (string, error) {
 	return key, err
 }
 
+func (m metricsStore) GetAuditLogsByTime(ctx context.Context, arg database.GetAuditLogsByTimeParams) ([]database.AuditLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogsByTime(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAuditLogsByTime").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	start := time.Now()
 	rows, err := m.s.GetAuditLogsOffset(ctx, arg)
@@ -258,6 +468,20 @@ func (m metricsStore) GetAuthorizationUserRoles(ctx context.Context, userID uuid
 	return row, err
 }
 
+func (m metricsStore) GetCustomRoleByID(ctx context.Context, id uuid.UUID) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoleByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetCustomRoleByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetCustomRoles(ctx context.Context, names []string) ([]database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetCustomRoles(ctx, names)
+	m.queryLatencies.WithLabelValues("GetCustomRoles").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetDERPMeshKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	key, err := m.s.GetDERPMeshKey(ctx)
@@ -300,6 +524,13 @@ func (m metricsStore) GetDeploymentWorkspaceStats(ctx context.Context) (database
 	return row, err
 }
 
+func (m metricsStore) GetEnabledWebhooksByEvent(ctx context.Context, event database.WebhookEvent) ([]database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetEnabledWebhooksByEvent(ctx, event)
+	m.queryLatencies.WithLabelValues("GetEnabledWebhooksByEvent").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetFileByHashAndCreator(ctx context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
 	start := time.Now()
 	file, err := m.s.GetFileByHashAndCreator(ctx, arg)
@@ -398,6 +629,13 @@ func (m metricsStore) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context
 	return builds, err
 }
 
+func (m metricsStore) GetLatestWorkspaceBuildsWithDeadlineBetween(ctx context.Context, arg database.GetLatestWorkspaceBuildsWithDeadlineBetweenParams) ([]database.WorkspaceBuild, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestWorkspaceBuildsWithDeadlineBetween(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetLatestWorkspaceBuildsWithDeadlineBetween").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetLicenseByID(ctx context.Context, id int32) (database.License, error) {
 	start := time.Now()
 	license, err := m.s.GetLicenseByID(ctx, id)
@@ -419,6 +657,62 @@ func (m metricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m metricsStore) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByHashedSecret(ctx context.Context, hashedSecret []byte) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByHashedSecret(ctx, hashedSecret)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByHashedSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretsByAppID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx context.Context, hashedRefreshToken []byte) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx, hashedRefreshToken)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppTokenByHashedRefreshToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderApps(ctx)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderApps").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.GetOAuth2ProviderAppsByUserIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuthSigningKey(ctx)
@@ -732,6 +1026,41 @@ func (m metricsStore) GetUserLinkByUserIDLoginType(ctx context.Context, arg data
 	return link, err
 }
 
+func (m metricsStore) GetUserLoginFailureCounts(ctx context.Context, arg database.GetUserLoginFailureCountsParams) (database.GetUserLoginFailureCountsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserLoginFailureCounts(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserLoginFailureCounts").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]database.UserNotificationPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserNotificationPreferences(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserNotificationPreferences").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserPasswordHistory(ctx context.Context, arg database.GetUserPasswordHistoryParams) ([]database.UserPasswordHistory, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserPasswordHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserPasswordHistory").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserTOTPByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserTOTPByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserTOTPRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserTOTPRecoveryCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserTOTPRecoveryCodesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserTOTPRecoveryCodesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	start := time.Now()
 	users, err := m.s.GetUsers(ctx, arg)
@@ -746,6 +1075,27 @@ func (m metricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]dat
 	return users, err
 }
 
+func (m metricsStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookDeliveriesByWebhookID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWebhookDeliveriesByWebhookID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhooks(ctx)
+	m.queryLatencies.WithLabelValues("GetWebhooks").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentByAuthToken(ctx context.Context, authToken uuid.UUID) (database.WorkspaceAgent, error) {
 	start := time.Now()
 	agent, err := m.s.GetWorkspaceAgentByAuthToken(ctx, authToken)
@@ -781,6 +1131,27 @@ func (m metricsStore) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAg
 	return metadata, err
 }
 
+func (m metricsStore) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentScriptsByAgentIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgentSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentSessionRecordingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentSessionRecordingsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.GetWorkspaceAgentSessionRecordingsByAgentIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentSessionRecordingsByAgentID(ctx, agentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentSessionRecordingsByAgentID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentStartupLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentStartupLogsAfterParams) ([]database.WorkspaceAgentStartupLog, error) {
 	start := time.Now()
 	logs, err := m.s.GetWorkspaceAgentStartupLogsAfter(ctx, arg)
@@ -998,6 +1369,13 @@ func (m metricsStore) GetWorkspaces(ctx context.Context, arg database.GetWorkspa
 	return workspaces, err
 }
 
+func (m metricsStore) GetWorkspacesByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacesByOwnerID(ctx, ownerID)
+	m.queryLatencies.WithLabelValues("GetWorkspacesByOwnerID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	start := time.Now()
 	workspaces, err := m.s.GetWorkspacesEligibleForTransition(ctx, now)
@@ -1005,6 +1383,13 @@ func (m metricsStore) GetWorkspacesEligibleForTransition(ctx context.Context, no
 	return workspaces, err
 }
 
+func (m metricsStore) GetWorkspacesLockedSince(ctx context.Context, lockedSince time.Time) ([]database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacesLockedSince(ctx, lockedSince)
+	m.queryLatencies.WithLabelValues("GetWorkspacesLockedSince").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	start := time.Now()
 	key, err := m.s.InsertAPIKey(ctx, arg)
@@ -1026,6 +1411,13 @@ func (m metricsStore) InsertAuditLog(ctx context.Context, arg database.InsertAud
 	return log, err
 }
 
+func (m metricsStore) InsertCustomRole(ctx context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertCustomRole(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertCustomRole").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertDERPMeshKey(ctx context.Context, value string) error {
 	start := time.Now()
 	err := m.s.InsertDERPMeshKey(ctx, value)
@@ -1082,6 +1474,41 @@ func (m metricsStore) InsertLicense(ctx context.Context, arg database.InsertLice
 	return license, err
 }
 
+func (m metricsStore) InsertNotificationMessage(ctx context.Context, arg database.InsertNotificationMessageParams) error {
+	start := time.Now()
+	r0 := m.s.InsertNotificationMessage(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertNotificationMessage").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderApp(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderApp").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppSecret(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppSecret").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppToken").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
@@ -1173,6 +1600,41 @@ func (m metricsStore) InsertUserLink(ctx context.Context, arg database.InsertUse
 	return link, err
 }
 
+func (m metricsStore) InsertUserLoginFailure(ctx context.Context, arg database.InsertUserLoginFailureParams) error {
+	start := time.Now()
+	r0 := m.s.InsertUserLoginFailure(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserLoginFailure").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) InsertUserPasswordHistory(ctx context.Context, arg database.InsertUserPasswordHistoryParams) error {
+	start := time.Now()
+	r0 := m.s.InsertUserPasswordHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserPasswordHistory").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) InsertUserTOTPRecoveryCodes(ctx context.Context, arg database.InsertUserTOTPRecoveryCodesParams) error {
+	start := time.Now()
+	r0 := m.s.InsertUserTOTPRecoveryCodes(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserTOTPRecoveryCodes").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhook(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhook").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWebhookDelivery(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhookDelivery").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.InsertWorkspace(ctx, arg)
@@ -1194,6 +1656,20 @@ func (m metricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) InsertWorkspaceAgentScript(ctx context.Context, arg database.InsertWorkspaceAgentScriptParams) (database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentScript(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentScript").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWorkspaceAgentSessionRecording(ctx context.Context, arg database.InsertWorkspaceAgentSessionRecordingParams) (database.WorkspaceAgentSessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentSessionRecording(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentSessionRecording").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspaceAgentStartupLogs(ctx context.Context, arg database.InsertWorkspaceAgentStartupLogsParams) ([]database.WorkspaceAgentStartupLog, error) {
 	start := time.Now()
 	logs, err := m.s.InsertWorkspaceAgentStartupLogs(ctx, arg)
@@ -1271,6 +1747,13 @@ func (m metricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateA
 	return err
 }
 
+func (m metricsStore) UpdateCustomRole(ctx context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateCustomRole(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateCustomRole").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateGitAuthLink(ctx, arg)
@@ -1299,6 +1782,27 @@ func (m metricsStore) UpdateMemberRoles(ctx context.Context, arg database.Update
 	return member, err
 }
 
+func (m metricsStore) UpdateNotificationMessageByID(ctx context.Context, arg database.UpdateNotificationMessageByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateNotificationMessageByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateNotificationMessageByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobByID(ctx, arg)
@@ -1453,6 +1957,27 @@ func (m metricsStore) UpdateUserStatus(ctx context.Context, arg database.UpdateU
 	return user, err
 }
 
+func (m metricsStore) UpdateUserTOTPUsed(ctx context.Context, arg database.UpdateUserTOTPUsedParams) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserTOTPUsed(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserTOTPUsed").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateWebhookByID(ctx context.Context, arg database.UpdateWebhookByIDParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWebhookByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWebhookDeliveryByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookDeliveryByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.UpdateWorkspace(ctx, arg)
@@ -1460,6 +1985,20 @@ func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWo
 	return workspace, err
 }
 
+func (m metricsStore) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceACLByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceACLByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpdateWorkspaceAgentAuthTokensByWorkspaceID(ctx context.Context, arg database.UpdateWorkspaceAgentAuthTokensByWorkspaceIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentAuthTokensByWorkspaceID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentAuthTokensByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
@@ -1481,6 +2020,13 @@ func (m metricsStore) UpdateWorkspaceAgentMetadata(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) UpdateWorkspaceAgentScriptStatusByID(ctx context.Context, arg database.UpdateWorkspaceAgentScriptStatusByIDParams) (database.WorkspaceAgentScript, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceAgentScriptStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentScriptStatusByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentStartupByID(ctx, arg)
@@ -1544,6 +2090,13 @@ func (m metricsStore) UpdateWorkspaceLockedDeletingAt(ctx context.Context, arg d
 	return r0
 }
 
+func (m metricsStore) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceOwnerByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceOwnerByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.UpdateWorkspaceProxy(ctx, arg)
@@ -1632,6 +2185,27 @@ func (m metricsStore) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID
 	return m.s.UpsertTailnetCoordinator(ctx, id)
 }
 
+func (m metricsStore) UpsertUserNotificationPreference(ctx context.Context, arg database.UpsertUserNotificationPreferenceParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertUserNotificationPreference(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserNotificationPreference").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserTOTP(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserTOTP").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UseUserTOTPRecoveryCode(ctx context.Context, arg database.UseUserTOTPRecoveryCodeParams) (database.UserTOTPRecoveryCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.UseUserTOTPRecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("UseUserTOTPRecoveryCode").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
@@ -1660,6 +2234,20 @@ func (m metricsStore) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 	return workspaces, err
 }
 
+func (m metricsStore) GetWorkspaceGroupRoles(ctx context.Context, id uuid.UUID) ([]database.WorkspaceGroup, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceGroupRoles(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceGroupRoles").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceUserRoles(ctx context.Context, id uuid.UUID) ([]database.WorkspaceUser, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceUserRoles(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceUserRoles").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetAuthorizedUsers(ctx context.Context, arg database.GetUsersParams, prepared rbac.PreparedAuthorized) ([]database.GetUsersRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetAuthorizedUsers(ctx, arg, prepared)
@@ -6,22 +6,49 @@ import (
 	"io"
 	"time"
 
+	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbauthz"
 )
 
 const (
 	delay = 24 * time.Hour
+	// auditLogBatchSize is the number of audit logs archived and deleted at
+	// once.
+	auditLogBatchSize = 1000
 )
 
+// AuditLogRetention configures how long audit logs are kept.
+type AuditLogRetention struct {
+	// Default is how long audit logs are kept. Zero keeps them forever.
+	Default time.Duration
+	// ResourceTypes overrides Default for audit logs of the given resource
+	// types. Zero keeps them forever.
+	ResourceTypes map[database.ResourceType]time.Duration
+	// Archiver stores audit logs before they are deleted, if set.
+	Archiver audit.Archiver
+}
+
+// Period returns how long audit logs of a resource type are kept. Zero keeps
+// them forever.
+func (r AuditLogRetention) Period(resourceType database.ResourceType) time.Duration {
+	if period, ok := r.ResourceTypes[resourceType]; ok {
+		return period
+	}
+	return r.Default
+}
+
 // New creates a new periodically purging database instance.
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for cleaning up old, unused resources from the database that take up space.
-func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
+func New(ctx context.Context, logger slog.Logger, db database.Store, auditLogRetention AuditLogRetention) io.Closer {
 	closed := make(chan struct{})
 	ctx, cancelFunc := context.WithCancel(ctx)
 	//nolint:gocritic // The system purges old db records without user input.
@@ -45,6 +72,15 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 			eg.Go(func() error {
 				return db.DeleteOldWorkspaceAgentStats(ctx)
 			})
+			eg.Go(func() error {
+				return db.DeleteOldWebhookDeliveries(ctx)
+			})
+			eg.Go(func() error {
+				return db.DeleteOldNotificationMessages(ctx)
+			})
+			eg.Go(func() error {
+				return PurgeAuditLogs(ctx, logger, db, auditLogRetention, time.Now())
+			})
 			err := eg.Wait()
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
@@ -62,6 +98,106 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 	}
 }
 
+// PurgeAuditLogs deletes the audit logs that are older than their retention
+// period at now. If an archiver is set, audit logs are only deleted once they
+// are archived.
+func PurgeAuditLogs(ctx context.Context, logger slog.Logger, db database.Store, retention AuditLogRetention, now time.Time) error {
+	// Resource types with their own retention period are purged separately.
+	overridden := maps.Keys(retention.ResourceTypes)
+	if retention.Default > 0 {
+		err := purgeAuditLogs(ctx, logger, db, retention.Archiver, database.GetAuditLogsByTimeParams{
+			Before:               now.Add(-retention.Default),
+			ExcludeResourceTypes: overridden,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for resourceType, period := range retention.ResourceTypes {
+		if period <= 0 {
+			continue
+		}
+		err := purgeAuditLogs(ctx, logger, db, retention.Archiver, database.GetAuditLogsByTimeParams{
+			Before:        now.Add(-period),
+			ResourceTypes: []database.ResourceType{resourceType},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func purgeAuditLogs(ctx context.Context, logger slog.Logger, db database.Store, archiver audit.Archiver, params database.GetAuditLogsByTimeParams) error {
+	params.LimitCount = auditLogBatchSize
+	deleted := 0
+	for {
+		var (
+			alogs  []database.AuditLog
+			locked bool
+		)
+		// Every replica purges audit logs, so each batch is fetched,
+		// archived and deleted while holding a lock to archive it only once.
+		err := db.InTx(func(tx database.Store) error {
+			var err error
+			locked, err = tx.TryAcquireLock(ctx, database.LockIDAuditLogPurge)
+			if err != nil {
+				return xerrors.Errorf("acquire lock: %w", err)
+			}
+			if !locked {
+				return nil
+			}
+			alogs, err = tx.GetAuditLogsByTime(ctx, params)
+			if err != nil {
+				return xerrors.Errorf("get audit logs: %w", err)
+			}
+			if len(alogs) == 0 {
+				return nil
+			}
+			if archiver != nil {
+				err = archiver.Archive(ctx, alogs)
+				if err != nil {
+					return xerrors.Errorf("archive audit logs: %w", err)
+				}
+			}
+			ids := make([]uuid.UUID, 0, len(alogs))
+			for _, alog := range alogs {
+				ids = append(ids, alog.ID)
+			}
+			err = tx.DeleteAuditLogsByIDs(ctx, ids)
+			if err != nil {
+				return xerrors.Errorf("delete audit logs: %w", err)
+			}
+			return nil
+		}, nil)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			logger.Debug(ctx, "audit logs are being purged by another replica")
+			break
+		}
+		if len(alogs) == 0 {
+			break
+		}
+		deleted += len(alogs)
+		if len(alogs) < auditLogBatchSize {
+			break
+		}
+		last := alogs[len(alogs)-1]
+		params.AfterTime = last.Time
+		params.AfterID = last.ID
+	}
+	if deleted > 0 {
+		logger.Info(ctx, "purged old audit logs",
+			slog.F("before", params.Before),
+			slog.F("resource_types", params.ResourceTypes),
+			slog.F("count", deleted),
+		)
+	}
+	return nil
+}
+
 type instance struct {
 	cancel context.CancelFunc
 	closed chan struct{}
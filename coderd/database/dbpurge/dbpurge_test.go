@@ -1,15 +1,27 @@
 package dbpurge_test
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/goleak"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
 	"github.com/coder/coder/coderd/database/dbpurge"
 )
 
@@ -20,7 +32,174 @@ func TestMain(m *testing.M) {
 // Ensures no goroutines leak.
 func TestPurge(t *testing.T) {
 	t.Parallel()
-	purger := dbpurge.New(context.Background(), slogtest.Make(t, nil), dbfake.New())
+	purger := dbpurge.New(context.Background(), slogtest.Make(t, nil), dbfake.New(), dbpurge.AuditLogRetention{})
 	err := purger.Close()
 	require.NoError(t, err)
 }
+
+func TestPurgeAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Retention", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx = context.Background()
+			db  = dbfake.New()
+			now = time.Now()
+			day = 24 * time.Hour
+			dir = t.TempDir()
+		)
+		auditLog := func(age time.Duration, resourceType database.ResourceType) database.AuditLog {
+			return dbgen.AuditLog(t, db, database.AuditLog{
+				Time:         now.Add(-age),
+				ResourceType: resourceType,
+			})
+		}
+		oldOrganization := auditLog(40*day, database.ResourceTypeOrganization)
+		newOrganization := auditLog(10*day, database.ResourceTypeOrganization)
+		oldBuild := auditLog(70*day, database.ResourceTypeWorkspaceBuild)
+		newBuild := auditLog(40*day, database.ResourceTypeWorkspaceBuild)
+		oldTemplate := auditLog(400*day, database.ResourceTypeTemplate)
+
+		err := dbpurge.PurgeAuditLogs(ctx, slogtest.Make(t, nil), db, dbpurge.AuditLogRetention{
+			Default: 30 * day,
+			ResourceTypes: map[database.ResourceType]time.Duration{
+				database.ResourceTypeWorkspaceBuild: 60 * day,
+				// Templates are kept forever.
+				database.ResourceTypeTemplate: 0,
+			},
+			Archiver: audit.NewDirArchiver(dir),
+		}, now)
+		require.NoError(t, err)
+
+		remaining := remainingAuditLogs(t, db, now)
+		require.ElementsMatch(t, []uuid.UUID{newOrganization.ID, newBuild.ID, oldTemplate.ID}, remaining)
+		require.ElementsMatch(t, []uuid.UUID{oldOrganization.ID, oldBuild.ID}, archivedAuditLogs(t, dir))
+	})
+
+	t.Run("Batches", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx = context.Background()
+			db  = dbfake.New()
+			now = time.Now()
+			dir = t.TempDir()
+		)
+		// Some audit logs share a time, so they are paginated by ID.
+		for i := 0; i < 2500; i++ {
+			_ = dbgen.AuditLog(t, db, database.AuditLog{
+				Time: now.Add(-time.Hour - time.Duration(i/2)*time.Second),
+			})
+		}
+
+		err := dbpurge.PurgeAuditLogs(ctx, slogtest.Make(t, nil), db, dbpurge.AuditLogRetention{
+			Default:  time.Minute,
+			Archiver: audit.NewDirArchiver(dir),
+		}, now)
+		require.NoError(t, err)
+		require.Empty(t, remainingAuditLogs(t, db, now))
+		require.Len(t, archivedAuditLogs(t, dir), 2500)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbfake.New()
+		now := time.Now()
+		for i := 0; i < 50; i++ {
+			_ = dbgen.AuditLog(t, db, database.AuditLog{
+				Time: now.Add(-time.Hour - time.Duration(i)*time.Second),
+			})
+		}
+
+		// Replicas purge at the same time, and each audit log must be
+		// archived once.
+		archiver := &countingArchiver{archived: map[uuid.UUID]int{}}
+		var eg errgroup.Group
+		for i := 0; i < 5; i++ {
+			eg.Go(func() error {
+				return dbpurge.PurgeAuditLogs(context.Background(), slogtest.Make(t, nil), db, dbpurge.AuditLogRetention{
+					Default:  time.Minute,
+					Archiver: archiver,
+				}, now)
+			})
+		}
+		require.NoError(t, eg.Wait())
+		require.Empty(t, remainingAuditLogs(t, db, now))
+		require.Len(t, archiver.archived, 50)
+		for id, count := range archiver.archived {
+			require.Equal(t, 1, count, "audit log %s archived %d times", id, count)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbfake.New()
+		now := time.Now()
+		alog := dbgen.AuditLog(t, db, database.AuditLog{
+			Time: now.Add(-1000 * 24 * time.Hour),
+		})
+		err := dbpurge.PurgeAuditLogs(context.Background(), slogtest.Make(t, nil), db, dbpurge.AuditLogRetention{}, now)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{alog.ID}, remainingAuditLogs(t, db, now))
+	})
+}
+
+type countingArchiver struct {
+	mu       sync.Mutex
+	archived map[uuid.UUID]int
+}
+
+func (a *countingArchiver) Archive(_ context.Context, alogs []database.AuditLog) error {
+	// Give other purges the chance to fetch the same audit logs.
+	time.Sleep(10 * time.Millisecond)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, alog := range alogs {
+		a.archived[alog.ID]++
+	}
+	return nil
+}
+
+func remainingAuditLogs(t *testing.T, db database.Store, now time.Time) []uuid.UUID {
+	t.Helper()
+
+	alogs, err := db.GetAuditLogsByTime(context.Background(), database.GetAuditLogsByTimeParams{
+		Before:     now.Add(time.Hour),
+		LimitCount: 10000,
+	})
+	require.NoError(t, err)
+	ids := []uuid.UUID{}
+	for _, alog := range alogs {
+		ids = append(ids, alog.ID)
+	}
+	return ids
+}
+
+func archivedAuditLogs(t *testing.T, dir string) []uuid.UUID {
+	t.Helper()
+
+	archives, err := filepath.Glob(filepath.Join(dir, "audit-logs-*.jsonl.gz"))
+	require.NoError(t, err)
+	ids := []uuid.UUID{}
+	for _, archive := range archives {
+		file, err := os.Open(archive)
+		require.NoError(t, err)
+		zr, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(zr)
+		for scanner.Scan() {
+			var alog struct {
+				ID uuid.UUID `json:"id"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &alog))
+			ids = append(ids, alog.ID)
+		}
+		require.NoError(t, scanner.Err())
+		_ = file.Close()
+	}
+	return ids
+}
@@ -2,7 +2,11 @@
 
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'read_only',
+    'workspace_start_stop',
+    'template_push',
+    'user_read'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
@@ -19,7 +23,9 @@ CREATE TYPE audit_action AS ENUM (
     'stop',
     'login',
     'logout',
-    'register'
+    'register',
+    'connect',
+    'disconnect'
 );
 
 CREATE TYPE build_reason AS ENUM (
@@ -49,11 +55,27 @@ CREATE TYPE login_type AS ENUM (
     'github',
     'oidc',
     'token',
-    'none'
+    'none',
+    'oauth2_provider_app',
+    'ldap',
+    'oauth2'
 );
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
 
+CREATE TYPE notification_kind AS ENUM (
+    'workspace_autostop',
+    'workspace_build_failed',
+    'workspace_locked',
+    'token_expiring'
+);
+
+CREATE TYPE notification_message_status AS ENUM (
+    'pending',
+    'sent',
+    'failed'
+);
+
 CREATE TYPE parameter_destination_scheme AS ENUM (
     'none',
     'environment_variable',
@@ -103,7 +125,18 @@ CREATE TYPE resource_type AS ENUM (
     'workspace_build',
     'license',
     'workspace_proxy',
-    'convert_login'
+    'convert_login',
+    'webhook',
+    'oauth2_provider_app',
+    'oauth2_provider_app_secret',
+    'custom_role',
+    'workspace_agent',
+    'workspace_app'
+);
+
+CREATE TYPE session_recording_type AS ENUM (
+    'ssh',
+    'reconnecting_pty'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -113,7 +146,24 @@ CREATE TYPE startup_script_behavior AS ENUM (
 
 CREATE TYPE user_status AS ENUM (
     'active',
-    'suspended'
+    'suspended',
+    'locked'
+);
+
+CREATE TYPE webhook_delivery_status AS ENUM (
+    'pending',
+    'delivered',
+    'failed'
+);
+
+CREATE TYPE webhook_event AS ENUM (
+    'workspace_build_started',
+    'workspace_build_succeeded',
+    'workspace_build_failed',
+    'workspace_autostop_imminent',
+    'template_version_activated',
+    'user_created',
+    'user_suspended'
 );
 
 CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
@@ -128,6 +178,20 @@ CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'off'
 );
 
+CREATE TYPE workspace_agent_script_run_on AS ENUM (
+    'start',
+    'stop'
+);
+
+CREATE TYPE workspace_agent_script_status AS ENUM (
+    'pending',
+    'running',
+    'success',
+    'error',
+    'timeout',
+    'skipped'
+);
+
 CREATE TYPE workspace_agent_subsystem AS ENUM (
     'envbuilder',
     'envbox',
@@ -147,6 +211,35 @@ CREATE TYPE workspace_transition AS ENUM (
     'delete'
 );
 
+CREATE FUNCTION delete_deleted_custom_role_assignments() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+BEGIN
+	IF (OLD.organization_id IS NULL) THEN
+		UPDATE users
+		SET rbac_roles = array_remove(rbac_roles, OLD.name)
+		WHERE OLD.name = ANY(rbac_roles);
+	ELSE
+		UPDATE organization_members
+		SET roles = array_remove(roles, OLD.name || ':' || OLD.organization_id::text)
+		WHERE organization_id = OLD.organization_id;
+	END IF;
+	RETURN OLD;
+END;
+$$;
+
+CREATE FUNCTION delete_deleted_oauth2_provider_app_token_api_key() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+BEGIN
+	DELETE FROM api_keys
+	WHERE id = OLD.api_key_id;
+	RETURN OLD;
+END;
+$$;
+
 CREATE FUNCTION delete_deleted_user_api_keys() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -226,11 +319,53 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    allow_list text[] DEFAULT '{}'::text[] NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.allow_list IS 'Resources the key is restricted to, in the form <type>:<id>. The key is not restricted if the list is empty.';
+
+CREATE TABLE audit_log_checkpoints (
+    sequence bigint NOT NULL,
+    hash bytea NOT NULL,
+    signature bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE audit_log_checkpoints IS 'Signed hashes of the audit log chain, so the chain cannot be rewritten or truncated without the deployment signing key.';
+
+CREATE TABLE audit_log_hashes (
+    sequence bigint NOT NULL,
+    audit_log_id uuid NOT NULL,
+    prev_hash bytea NOT NULL,
+    hash bytea NOT NULL,
+    pruned_at timestamp with time zone,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    audit_log_time timestamp with time zone,
+    resource_type resource_type,
+    content_hash bytea
+);
+
+COMMENT ON TABLE audit_log_hashes IS 'Links audit logs into a hash chain, so modified or deleted audit logs can be detected. Hashes are kept when audit logs are deleted by the retention policy.';
+
+COMMENT ON COLUMN audit_log_hashes.sequence IS 'Position of the audit log in the chain, starting at 1 without gaps.';
+
+COMMENT ON COLUMN audit_log_hashes.prev_hash IS 'Hash of the previous audit log in the chain, empty for the first one.';
+
+COMMENT ON COLUMN audit_log_hashes.hash IS 'SHA-256 hash of the previous hash, and the time, resource type and content hash of the audit log. Audit logs chained before content hashes were kept hash their content directly.';
+
+COMMENT ON COLUMN audit_log_hashes.pruned_at IS 'When the audit log was deleted by the retention policy.';
+
+COMMENT ON COLUMN audit_log_hashes.created_at IS 'When the audit log was appended to the chain.';
+
+COMMENT ON COLUMN audit_log_hashes.audit_log_time IS 'Time of the audit log, kept to verify that pruned audit logs were past their retention period.';
+
+COMMENT ON COLUMN audit_log_hashes.resource_type IS 'Resource type of the audit log, kept to verify that pruned audit logs were past their retention period.';
+
+COMMENT ON COLUMN audit_log_hashes.content_hash IS 'SHA-256 hash of the content of the audit log. The hash chains it with the time and resource type of the audit log, so they can be verified after it is deleted. Null for audit logs chained before it was added.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -249,6 +384,22 @@ CREATE TABLE audit_logs (
     resource_icon text NOT NULL
 );
 
+CREATE TABLE custom_roles (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name text NOT NULL,
+    display_name text NOT NULL,
+    organization_id uuid,
+    permissions jsonb DEFAULT '[]'::jsonb NOT NULL
+);
+
+COMMENT ON TABLE custom_roles IS 'Roles defined by admins in addition to the built-in roles. They are assigned by name like the built-in roles.';
+
+COMMENT ON COLUMN custom_roles.organization_id IS 'The organization of an organization role, null for a site wide role.';
+
+COMMENT ON COLUMN custom_roles.permissions IS 'The permissions granted by the role. They apply site wide for site wide roles and to the organization for organization roles.';
+
 CREATE TABLE files (
     hash character varying(64) NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -309,6 +460,81 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE notification_messages (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    kind notification_kind NOT NULL,
+    recipient text NOT NULL,
+    subject text NOT NULL,
+    body text NOT NULL,
+    status notification_message_status DEFAULT 'pending'::notification_message_status NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone NOT NULL,
+    last_attempt_at timestamp with time zone,
+    error text DEFAULT ''::text NOT NULL,
+    sent_at timestamp with time zone
+);
+
+COMMENT ON TABLE notification_messages IS 'The queue of notifications sent to users. Pending messages are retried until they are sent or run out of attempts.';
+
+COMMENT ON COLUMN notification_messages.recipient IS 'The email address of the user at the time the message was enqueued.';
+
+COMMENT ON COLUMN notification_messages.next_attempt_at IS 'The time at which the message is attempted next. While an attempt is in progress this is the time at which another replica may retry it.';
+
+CREATE TABLE oauth2_provider_app_codes (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    hashed_secret bytea NOT NULL,
+    user_id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    redirect_uri text NOT NULL,
+    scope api_key_scope NOT NULL,
+    code_challenge text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Authorization codes that are exchanged for tokens once. They are only valid for a short time.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.redirect_uri IS 'The redirect URI given in the authorization request, empty if none was given. The token request must repeat it.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The PKCE code challenge, the base64url encoded SHA-256 hash of the code verifier.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone,
+    hashed_secret bytea NOT NULL,
+    display_secret text NOT NULL,
+    app_id uuid NOT NULL
+);
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail end of the original secret so secrets can be told apart.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    hashed_refresh_token bytea NOT NULL,
+    app_secret_id uuid NOT NULL,
+    api_key_id text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_tokens IS 'Tokens issued to apps. The access token is the API key, the refresh token can be exchanged for a new one until expires_at.';
+
+CREATE TABLE oauth2_provider_apps (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name character varying(64) NOT NULL,
+    icon character varying(256) NOT NULL,
+    callback_url text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'Third-party applications that use Coder as an OAuth2 provider to call the API on behalf of users.';
+
+COMMENT ON COLUMN oauth2_provider_apps.callback_url IS 'The URL users are redirected to after authorizing the app. Redirect URIs must be equal to or below it.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -557,11 +783,15 @@ CREATE TABLE users (
     avatar_url text,
     deleted boolean DEFAULT false NOT NULL,
     last_seen_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
-    quiet_hours_schedule text DEFAULT ''::text NOT NULL
+    quiet_hours_schedule text DEFAULT ''::text NOT NULL,
+    is_service_account boolean DEFAULT false NOT NULL,
+    CONSTRAINT service_account_login_type CHECK (((NOT is_service_account) OR (login_type = 'none'::login_type)))
 );
 
 COMMENT ON COLUMN users.quiet_hours_schedule IS 'Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user''s quiet hours. If empty, the default quiet hours on the instance is used instead.';
 
+COMMENT ON COLUMN users.is_service_account IS 'Service accounts are non-human users that authenticate with tokens only. They cannot log in and do not count towards the licensed user limit.';
+
 CREATE VIEW visible_users AS
  SELECT users.id,
     users.username,
@@ -613,7 +843,9 @@ CREATE TABLE templates (
     inactivity_ttl bigint DEFAULT 0 NOT NULL,
     locked_ttl bigint DEFAULT 0 NOT NULL,
     restart_requirement_days_of_week smallint DEFAULT 0 NOT NULL,
-    restart_requirement_weeks bigint DEFAULT 0 NOT NULL
+    restart_requirement_weeks bigint DEFAULT 0 NOT NULL,
+    record_sessions boolean DEFAULT false NOT NULL,
+    prebuilt_workspaces integer DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -630,6 +862,10 @@ COMMENT ON COLUMN templates.restart_requirement_days_of_week IS 'A bitmap of day
 
 COMMENT ON COLUMN templates.restart_requirement_weeks IS 'The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.';
 
+COMMENT ON COLUMN templates.record_sessions IS 'Record interactive terminal sessions in workspaces created from this template.';
+
+COMMENT ON COLUMN templates.prebuilt_workspaces IS 'Number of prebuilt workspaces to keep ready for users to claim when they create a workspace from this template.';
+
 CREATE VIEW template_with_users AS
  SELECT templates.id,
     templates.created_at,
@@ -655,6 +891,8 @@ CREATE VIEW template_with_users AS
     templates.locked_ttl,
     templates.restart_requirement_days_of_week,
     templates.restart_requirement_weeks,
+    templates.record_sessions,
+    templates.prebuilt_workspaces,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username
    FROM (public.templates
@@ -671,6 +909,91 @@ CREATE TABLE user_links (
     oauth_expiry timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE TABLE user_login_failures (
+    user_id uuid NOT NULL,
+    ip_address inet NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_login_failures IS 'Failed password logins of users. They are counted to lock accounts and are deleted when the user logs in or is activated.';
+
+CREATE TABLE user_notification_preferences (
+    user_id uuid NOT NULL,
+    kind notification_kind NOT NULL,
+    disabled boolean DEFAULT false NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_notification_preferences IS 'Notification kinds users have opted out of. Users receive all kinds of notifications without a row.';
+
+CREATE TABLE user_password_history (
+    user_id uuid NOT NULL,
+    hashed_password bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_password_history IS 'Previous passwords of users that cannot be reused when the password history policy is enabled.';
+
+CREATE TABLE user_totp (
+    user_id uuid NOT NULL,
+    secret text NOT NULL,
+    enabled boolean DEFAULT false NOT NULL,
+    last_used_step bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_totp IS 'Authenticator app secrets of users that use TOTP multi-factor authentication.';
+
+COMMENT ON COLUMN user_totp.enabled IS 'Secrets are enabled once the user proves their authenticator app generates codes for it.';
+
+COMMENT ON COLUMN user_totp.last_used_step IS 'The time step of the last accepted code. Codes for it and earlier steps are rejected so they cannot be replayed.';
+
+CREATE TABLE user_totp_recovery_codes (
+    user_id uuid NOT NULL,
+    hashed_code bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    used_at timestamp with time zone
+);
+
+COMMENT ON TABLE user_totp_recovery_codes IS 'Single-use codes that replace a TOTP code when the authenticator app is lost.';
+
+CREATE TABLE webhook_deliveries (
+    id uuid NOT NULL,
+    webhook_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    event webhook_event NOT NULL,
+    payload jsonb NOT NULL,
+    status webhook_delivery_status DEFAULT 'pending'::webhook_delivery_status NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone NOT NULL,
+    last_attempt_at timestamp with time zone,
+    status_code integer,
+    error text DEFAULT ''::text NOT NULL,
+    delivered_at timestamp with time zone
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'The delivery log of webhooks. Pending deliveries are retried until they are delivered or run out of attempts.';
+
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'The time at which the delivery is attempted next. While an attempt is in progress this is the time at which another replica may retry it.';
+
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'The HTTP status code of the last attempt, NULL if no response was received.';
+
+CREATE TABLE webhooks (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name character varying(64) NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    events webhook_event[] DEFAULT '{}'::webhook_event[] NOT NULL,
+    enabled boolean DEFAULT true NOT NULL
+);
+
+COMMENT ON COLUMN webhooks.secret IS 'The key used to sign delivered payloads with HMAC-SHA256.';
+
+COMMENT ON COLUMN webhooks.events IS 'The events that are delivered to the webhook.';
+
 CREATE UNLOGGED TABLE workspace_agent_metadata (
     workspace_agent_id uuid NOT NULL,
     display_name character varying(127) NOT NULL,
@@ -683,14 +1006,59 @@ CREATE UNLOGGED TABLE workspace_agent_metadata (
     collected_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE TABLE workspace_agent_scripts (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    agent_id uuid NOT NULL,
+    name character varying(64) NOT NULL,
+    display_name text NOT NULL,
+    script text NOT NULL,
+    run_on workspace_agent_script_run_on NOT NULL,
+    timeout_seconds integer NOT NULL,
+    start_blocks_login boolean NOT NULL,
+    depends_on text[] DEFAULT '{}'::text[] NOT NULL,
+    status workspace_agent_script_status DEFAULT 'pending'::workspace_agent_script_status NOT NULL,
+    exit_code integer,
+    started_at timestamp with time zone,
+    ended_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_agent_scripts IS 'Named scripts run by the agent when the workspace starts or stops.';
+
+COMMENT ON COLUMN workspace_agent_scripts.timeout_seconds IS 'The number of seconds the script may run before it is killed, or 0 for no timeout.';
+
+COMMENT ON COLUMN workspace_agent_scripts.depends_on IS 'Names of scripts that must complete successfully before this script runs.';
+
+COMMENT ON COLUMN workspace_agent_scripts.exit_code IS 'The exit code of the script, NULL if it has not exited.';
+
+CREATE TABLE workspace_agent_session_recordings (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    workspace_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    type session_recording_type NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone NOT NULL,
+    truncated boolean DEFAULT false NOT NULL,
+    size bigint NOT NULL,
+    data bytea NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_session_recordings IS 'Recordings of interactive terminal sessions in the asciicast v2 format.';
+
+COMMENT ON COLUMN workspace_agent_session_recordings.truncated IS 'Whether the agent stopped recording output because the recording exceeded the maximum size.';
+
 CREATE TABLE workspace_agent_startup_logs (
     agent_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
     output character varying(1024) NOT NULL,
     id bigint NOT NULL,
-    level log_level DEFAULT 'info'::log_level NOT NULL
+    level log_level DEFAULT 'info'::log_level NOT NULL,
+    source character varying(64) DEFAULT ''::character varying NOT NULL
 );
 
+COMMENT ON COLUMN workspace_agent_startup_logs.source IS 'The name of the script that produced the log, empty for the startup script.';
+
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -918,9 +1286,15 @@ CREATE TABLE workspaces (
     ttl bigint,
     last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
     locked_at timestamp with time zone,
-    deleting_at timestamp with time zone
+    deleting_at timestamp with time zone,
+    user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    group_acl jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
+COMMENT ON COLUMN workspaces.user_acl IS 'Users the workspace is shared with, mapped to the actions they are allowed to perform.';
+
+COMMENT ON COLUMN workspaces.group_acl IS 'Groups the workspace is shared with, mapped to the actions their members are allowed to perform.';
+
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('licenses_id_seq'::regclass);
 
 ALTER TABLE ONLY provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
@@ -935,9 +1309,21 @@ ALTER TABLE ONLY workspace_agent_stats
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY audit_log_checkpoints
+    ADD CONSTRAINT audit_log_checkpoints_pkey PRIMARY KEY (sequence);
+
+ALTER TABLE ONLY audit_log_hashes
+    ADD CONSTRAINT audit_log_hashes_audit_log_id_key UNIQUE (audit_log_id);
+
+ALTER TABLE ONLY audit_log_hashes
+    ADD CONSTRAINT audit_log_hashes_pkey PRIMARY KEY (sequence);
+
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY files
     ADD CONSTRAINT files_hash_created_by_key UNIQUE (hash, created_by);
 
@@ -965,6 +1351,33 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_hashed_secret_key UNIQUE (hashed_secret);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_key UNIQUE (api_key_id);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_hashed_refresh_token_key UNIQUE (hashed_refresh_token);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_name_key UNIQUE (name);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
@@ -1025,12 +1438,42 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
 
+ALTER TABLE ONLY user_notification_preferences
+    ADD CONSTRAINT user_notification_preferences_pkey PRIMARY KEY (user_id, kind);
+
+ALTER TABLE ONLY user_password_history
+    ADD CONSTRAINT user_password_history_pkey PRIMARY KEY (user_id, hashed_password);
+
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_totp_recovery_codes
+    ADD CONSTRAINT user_totp_recovery_codes_pkey PRIMARY KEY (user_id, hashed_code);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_name_key UNIQUE (name);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
 
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_agent_id_name_key UNIQUE (agent_id, name);
+
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_session_recordings
+    ADD CONSTRAINT workspace_agent_session_recordings_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_startup_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
@@ -1070,6 +1513,8 @@ ALTER TABLE ONLY workspace_resources
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
+CREATE UNIQUE INDEX custom_roles_name_organization_id_idx ON custom_roles USING btree (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
 CREATE INDEX idx_agent_stats_created_at ON workspace_agent_stats USING btree (created_at);
 
 CREATE INDEX idx_agent_stats_user_id ON workspace_agent_stats USING btree (user_id);
@@ -1104,16 +1549,26 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX notification_messages_pending_idx ON notification_messages USING btree (next_attempt_at) WHERE (status = 'pending'::notification_message_status);
+
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
 
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
+CREATE INDEX user_login_failures_user_id_created_at_idx ON user_login_failures USING btree (user_id, created_at);
+
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE (status = 'pending'::webhook_delivery_status);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
+CREATE INDEX workspace_agent_session_recordings_agent_id_started_at_idx ON workspace_agent_session_recordings USING btree (agent_id, started_at);
+
 CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_startup_logs USING btree (agent_id, id);
 
 CREATE INDEX workspace_agents_auth_token_idx ON workspace_agents USING btree (auth_token);
@@ -1132,6 +1587,10 @@ CREATE TRIGGER tailnet_notify_client_change AFTER INSERT OR DELETE OR UPDATE ON
 
 CREATE TRIGGER tailnet_notify_coordinator_heartbeat AFTER INSERT OR UPDATE ON tailnet_coordinators FOR EACH ROW EXECUTE FUNCTION tailnet_notify_coordinator_heartbeat();
 
+CREATE TRIGGER trigger_delete_custom_role AFTER DELETE ON custom_roles FOR EACH ROW EXECUTE FUNCTION delete_deleted_custom_role_assignments();
+
+CREATE TRIGGER trigger_delete_oauth2_provider_app_token AFTER DELETE ON oauth2_provider_app_tokens FOR EACH ROW EXECUTE FUNCTION delete_deleted_oauth2_provider_app_token_api_key();
+
 CREATE TRIGGER trigger_insert_apikeys BEFORE INSERT ON api_keys FOR EACH ROW EXECUTE FUNCTION insert_apikey_fail_if_user_deleted();
 
 CREATE TRIGGER trigger_update_users AFTER INSERT OR UPDATE ON users FOR EACH ROW WHEN ((new.deleted = true)) EXECUTE FUNCTION delete_deleted_user_api_keys();
@@ -1139,6 +1598,9 @@ CREATE TRIGGER trigger_update_users AFTER INSERT OR UPDATE ON users FOR EACH ROW
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
@@ -1151,6 +1613,24 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -1196,9 +1676,36 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_login_failures
+    ADD CONSTRAINT user_login_failures_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_notification_preferences
+    ADD CONSTRAINT user_notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_password_history
+    ADD CONSTRAINT user_password_history_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_totp_recovery_codes
+    ADD CONSTRAINT user_totp_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_scripts
+    ADD CONSTRAINT workspace_agent_scripts_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_session_recordings
+    ADD CONSTRAINT workspace_agent_session_recordings_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_session_recordings
+    ADD CONSTRAINT workspace_agent_session_recordings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_startup_logs
     ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -0,0 +1,49 @@
+BEGIN;
+
+CREATE TYPE session_recording_type AS ENUM (
+	'ssh',
+	'reconnecting_pty'
+);
+
+CREATE TABLE workspace_agent_session_recordings (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	type session_recording_type NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone NOT NULL,
+	truncated boolean NOT NULL DEFAULT false,
+	size bigint NOT NULL,
+	data bytea NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE workspace_agent_session_recordings IS 'Recordings of interactive terminal sessions in the asciicast v2 format.';
+COMMENT ON COLUMN workspace_agent_session_recordings.truncated IS 'Whether the agent stopped recording output because the recording exceeded the maximum size.';
+
+CREATE INDEX workspace_agent_session_recordings_agent_id_started_at_idx ON workspace_agent_session_recordings USING btree (agent_id, started_at);
+
+ALTER TABLE templates
+	ADD COLUMN record_sessions boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.record_sessions IS 'Record interactive terminal sessions in workspaces created from this template.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
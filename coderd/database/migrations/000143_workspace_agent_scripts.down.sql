@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE workspace_agent_startup_logs DROP COLUMN source;
+
+DROP TABLE workspace_agent_scripts;
+
+DROP TYPE workspace_agent_script_status;
+
+DROP TYPE workspace_agent_script_run_on;
+
+COMMIT;
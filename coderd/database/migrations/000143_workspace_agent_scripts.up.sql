@@ -0,0 +1,46 @@
+BEGIN;
+
+CREATE TYPE workspace_agent_script_run_on AS ENUM (
+	'start',
+	'stop'
+);
+
+CREATE TYPE workspace_agent_script_status AS ENUM (
+	'pending',
+	'running',
+	'success',
+	'error',
+	'timeout',
+	'skipped'
+);
+
+CREATE TABLE workspace_agent_scripts (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	name character varying(64) NOT NULL,
+	display_name text NOT NULL,
+	script text NOT NULL,
+	run_on workspace_agent_script_run_on NOT NULL,
+	timeout_seconds integer NOT NULL,
+	start_blocks_login boolean NOT NULL,
+	depends_on text[] NOT NULL DEFAULT '{}',
+	status workspace_agent_script_status NOT NULL DEFAULT 'pending',
+	exit_code integer,
+	started_at timestamp with time zone,
+	ended_at timestamp with time zone,
+	PRIMARY KEY (id),
+	UNIQUE (agent_id, name)
+);
+
+COMMENT ON TABLE workspace_agent_scripts IS 'Named scripts run by the agent when the workspace starts or stops.';
+COMMENT ON COLUMN workspace_agent_scripts.timeout_seconds IS 'The number of seconds the script may run before it is killed, or 0 for no timeout.';
+COMMENT ON COLUMN workspace_agent_scripts.depends_on IS 'Names of scripts that must complete successfully before this script runs.';
+COMMENT ON COLUMN workspace_agent_scripts.exit_code IS 'The exit code of the script, NULL if it has not exited.';
+
+ALTER TABLE workspace_agent_startup_logs
+	ADD COLUMN source character varying(64) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN workspace_agent_startup_logs.source IS 'The name of the script that produced the log, empty for the startup script.';
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- The webhook resource type cannot be removed from the resource_type enum.
+BEGIN;
+
+DROP TABLE webhook_deliveries;
+
+DROP TABLE webhooks;
+
+DROP TYPE webhook_delivery_status;
+
+DROP TYPE webhook_event;
+
+COMMIT;
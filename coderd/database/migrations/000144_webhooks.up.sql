@@ -0,0 +1,61 @@
+-- This has to be outside a transaction
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'webhook';
+
+BEGIN;
+
+CREATE TYPE webhook_event AS ENUM (
+	'workspace_build_started',
+	'workspace_build_succeeded',
+	'workspace_build_failed',
+	'workspace_autostop_imminent',
+	'template_version_activated',
+	'user_created',
+	'user_suspended'
+);
+
+CREATE TYPE webhook_delivery_status AS ENUM (
+	'pending',
+	'delivered',
+	'failed'
+);
+
+CREATE TABLE webhooks (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	name character varying(64) NOT NULL,
+	url text NOT NULL,
+	secret text NOT NULL,
+	events webhook_event[] NOT NULL DEFAULT '{}',
+	enabled boolean NOT NULL DEFAULT true,
+	PRIMARY KEY (id),
+	UNIQUE (name)
+);
+
+COMMENT ON COLUMN webhooks.secret IS 'The key used to sign delivered payloads with HMAC-SHA256.';
+COMMENT ON COLUMN webhooks.events IS 'The events that are delivered to the webhook.';
+
+CREATE TABLE webhook_deliveries (
+	id uuid NOT NULL,
+	webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	event webhook_event NOT NULL,
+	payload jsonb NOT NULL,
+	status webhook_delivery_status NOT NULL DEFAULT 'pending',
+	attempts integer NOT NULL DEFAULT 0,
+	next_attempt_at timestamp with time zone NOT NULL,
+	last_attempt_at timestamp with time zone,
+	status_code integer,
+	error text NOT NULL DEFAULT '',
+	delivered_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'The delivery log of webhooks. Pending deliveries are retried until they are delivered or run out of attempts.';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'The time at which the delivery is attempted next. While an attempt is in progress this is the time at which another replica may retry it.';
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'The HTTP status code of the last attempt, NULL if no response was received.';
+
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries (webhook_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE user_notification_preferences;
+
+DROP TABLE notification_messages;
+
+DROP TYPE notification_message_status;
+
+DROP TYPE notification_kind;
+
+COMMIT;
@@ -0,0 +1,49 @@
+BEGIN;
+
+CREATE TYPE notification_kind AS ENUM (
+	'workspace_autostop',
+	'workspace_build_failed',
+	'workspace_locked',
+	'token_expiring'
+);
+
+CREATE TYPE notification_message_status AS ENUM (
+	'pending',
+	'sent',
+	'failed'
+);
+
+CREATE TABLE notification_messages (
+	id uuid NOT NULL,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	kind notification_kind NOT NULL,
+	recipient text NOT NULL,
+	subject text NOT NULL,
+	body text NOT NULL,
+	status notification_message_status NOT NULL DEFAULT 'pending',
+	attempts integer NOT NULL DEFAULT 0,
+	next_attempt_at timestamp with time zone NOT NULL,
+	last_attempt_at timestamp with time zone,
+	error text NOT NULL DEFAULT '',
+	sent_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE notification_messages IS 'The queue of notifications sent to users. Pending messages are retried until they are sent or run out of attempts.';
+COMMENT ON COLUMN notification_messages.recipient IS 'The email address of the user at the time the message was enqueued.';
+COMMENT ON COLUMN notification_messages.next_attempt_at IS 'The time at which the message is attempted next. While an attempt is in progress this is the time at which another replica may retry it.';
+
+CREATE INDEX notification_messages_pending_idx ON notification_messages (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE user_notification_preferences (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	kind notification_kind NOT NULL,
+	disabled boolean NOT NULL DEFAULT false,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id, kind)
+);
+
+COMMENT ON TABLE user_notification_preferences IS 'Notification kinds users have opted out of. Users receive all kinds of notifications without a row.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE workspaces
+	DROP COLUMN user_acl,
+	DROP COLUMN group_acl;
+
+COMMIT;
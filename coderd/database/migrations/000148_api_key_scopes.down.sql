@@ -0,0 +1,6 @@
+-- The new scopes cannot be removed from the api_key_scope enum.
+BEGIN;
+
+ALTER TABLE api_keys DROP COLUMN allow_list;
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- This has to be outside a transaction
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'read_only';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'workspace_start_stop';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'template_push';
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'user_read';
+
+BEGIN;
+
+ALTER TABLE api_keys ADD COLUMN allow_list text[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.allow_list IS 'Resources the key is restricted to, in the form <type>:<id>. The key is not restricted if the list is empty.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE users
+	DROP CONSTRAINT service_account_login_type,
+	DROP COLUMN is_service_account;
+
+COMMIT;
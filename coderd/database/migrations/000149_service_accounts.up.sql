@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE users
+	ADD COLUMN is_service_account boolean NOT NULL DEFAULT false,
+	ADD CONSTRAINT service_account_login_type CHECK ((NOT is_service_account) OR login_type = 'none'::login_type);
+
+COMMENT ON COLUMN users.is_service_account IS 'Service accounts are non-human users that authenticate with tokens only. They cannot log in and do not count towards the licensed user limit.';
+
+COMMIT;
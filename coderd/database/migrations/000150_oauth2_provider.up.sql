@@ -0,0 +1,84 @@
+-- This has to be outside a transaction
+ALTER TYPE login_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app';
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app';
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'oauth2_provider_app_secret';
+
+BEGIN;
+
+CREATE TABLE oauth2_provider_apps (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	name character varying(64) NOT NULL,
+	icon character varying(256) NOT NULL,
+	callback_url text NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (name)
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'Third-party applications that use Coder as an OAuth2 provider to call the API on behalf of users.';
+COMMENT ON COLUMN oauth2_provider_apps.callback_url IS 'The URL users are redirected to after authorizing the app. Redirect URIs must be equal to or below it.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone,
+	hashed_secret bytea NOT NULL,
+	display_secret text NOT NULL,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail end of the original secret so secrets can be told apart.';
+
+CREATE TABLE oauth2_provider_app_codes (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	hashed_secret bytea NOT NULL,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	redirect_uri text NOT NULL,
+	scope api_key_scope NOT NULL,
+	code_challenge text NOT NULL,
+	PRIMARY KEY (id),
+	UNIQUE (hashed_secret)
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Authorization codes that are exchanged for tokens once. They are only valid for a short time.';
+COMMENT ON COLUMN oauth2_provider_app_codes.redirect_uri IS 'The redirect URI given in the authorization request, empty if none was given. The token request must repeat it.';
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The PKCE code challenge, the base64url encoded SHA-256 hash of the code verifier.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	hashed_refresh_token bytea NOT NULL,
+	app_secret_id uuid NOT NULL REFERENCES oauth2_provider_app_secrets (id) ON DELETE CASCADE,
+	api_key_id text NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+	PRIMARY KEY (id),
+	UNIQUE (hashed_refresh_token),
+	UNIQUE (api_key_id)
+);
+
+COMMENT ON TABLE oauth2_provider_app_tokens IS 'Tokens issued to apps. The access token is the API key, the refresh token can be exchanged for a new one until expires_at.';
+
+-- Deleting a token, for example because the secret or app it was issued to is
+-- deleted, must revoke the API key as well.
+CREATE FUNCTION delete_deleted_oauth2_provider_app_token_api_key() RETURNS trigger
+	LANGUAGE plpgsql
+	AS $$
+DECLARE
+BEGIN
+	DELETE FROM api_keys
+	WHERE id = OLD.api_key_id;
+	RETURN OLD;
+END;
+$$;
+
+CREATE TRIGGER trigger_delete_oauth2_provider_app_token
+	AFTER DELETE ON oauth2_provider_app_tokens
+	FOR EACH ROW
+	EXECUTE PROCEDURE delete_deleted_oauth2_provider_app_token_api_key();
+
+COMMIT;
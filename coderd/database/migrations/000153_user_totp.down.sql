@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE user_totp_recovery_codes;
+DROP TABLE user_totp;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE user_totp (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	secret text NOT NULL,
+	enabled boolean NOT NULL DEFAULT false,
+	last_used_step bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id)
+);
+
+COMMENT ON TABLE user_totp IS 'Authenticator app secrets of users that use TOTP multi-factor authentication.';
+COMMENT ON COLUMN user_totp.enabled IS 'Secrets are enabled once the user proves their authenticator app generates codes for it.';
+COMMENT ON COLUMN user_totp.last_used_step IS 'The time step of the last accepted code. Codes for it and earlier steps are rejected so they cannot be replayed.';
+
+CREATE TABLE user_totp_recovery_codes (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	hashed_code bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	used_at timestamp with time zone,
+	PRIMARY KEY (user_id, hashed_code)
+);
+
+COMMENT ON TABLE user_totp_recovery_codes IS 'Single-use codes that replace a TOTP code when the authenticator app is lost.';
+
+COMMIT;
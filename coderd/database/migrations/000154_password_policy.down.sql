@@ -0,0 +1,10 @@
+-- The locked status cannot be removed from its enum, so locked users are
+-- suspended instead.
+BEGIN;
+
+UPDATE users SET status = 'suspended' WHERE status = 'locked';
+
+DROP TABLE user_password_history;
+DROP TABLE user_login_failures;
+
+COMMIT;
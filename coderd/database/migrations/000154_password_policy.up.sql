@@ -0,0 +1,25 @@
+-- This has to be outside a transaction
+ALTER TYPE user_status ADD VALUE IF NOT EXISTS 'locked';
+
+BEGIN;
+
+CREATE TABLE user_login_failures (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	ip_address inet NOT NULL,
+	created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX user_login_failures_user_id_created_at_idx ON user_login_failures USING btree (user_id, created_at);
+
+COMMENT ON TABLE user_login_failures IS 'Failed password logins of users. They are counted to lock accounts and are deleted when the user logs in or is activated.';
+
+CREATE TABLE user_password_history (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	hashed_password bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id, hashed_password)
+);
+
+COMMENT ON TABLE user_password_history IS 'Previous passwords of users that cannot be reused when the password history policy is enabled.';
+
+COMMIT;
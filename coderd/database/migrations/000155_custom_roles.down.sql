@@ -0,0 +1,9 @@
+-- The new resource type cannot be removed from its enum.
+BEGIN;
+
+DROP TRIGGER IF EXISTS trigger_delete_custom_role ON custom_roles;
+DROP FUNCTION IF EXISTS delete_deleted_custom_role_assignments;
+
+DROP TABLE custom_roles;
+
+COMMIT;
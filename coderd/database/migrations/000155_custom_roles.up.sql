@@ -0,0 +1,48 @@
+-- This has to be outside a transaction
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'custom_role';
+
+BEGIN;
+
+CREATE TABLE custom_roles (
+	id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	name text NOT NULL,
+	display_name text NOT NULL,
+	organization_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+	permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE custom_roles IS 'Roles defined by admins in addition to the built-in roles. They are assigned by name like the built-in roles.';
+COMMENT ON COLUMN custom_roles.organization_id IS 'The organization of an organization role, null for a site wide role.';
+COMMENT ON COLUMN custom_roles.permissions IS 'The permissions granted by the role. They apply site wide for site wide roles and to the organization for organization roles.';
+
+CREATE UNIQUE INDEX custom_roles_name_organization_id_idx ON custom_roles (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+-- Deleting a role must unassign it, otherwise users would keep a role name
+-- that can no longer be expanded.
+CREATE FUNCTION delete_deleted_custom_role_assignments() RETURNS trigger
+	LANGUAGE plpgsql
+	AS $$
+DECLARE
+BEGIN
+	IF (OLD.organization_id IS NULL) THEN
+		UPDATE users
+		SET rbac_roles = array_remove(rbac_roles, OLD.name)
+		WHERE OLD.name = ANY(rbac_roles);
+	ELSE
+		UPDATE organization_members
+		SET roles = array_remove(roles, OLD.name || ':' || OLD.organization_id::text)
+		WHERE organization_id = OLD.organization_id;
+	END IF;
+	RETURN OLD;
+END;
+$$;
+
+CREATE TRIGGER trigger_delete_custom_role
+	AFTER DELETE ON custom_roles
+	FOR EACH ROW
+	EXECUTE PROCEDURE delete_deleted_custom_role_assignments();
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE audit_log_checkpoints;
+DROP TABLE audit_log_hashes;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE audit_log_hashes (
+	sequence bigint NOT NULL,
+	audit_log_id uuid NOT NULL,
+	prev_hash bytea NOT NULL,
+	hash bytea NOT NULL,
+	pruned_at timestamp with time zone,
+	PRIMARY KEY (sequence),
+	UNIQUE (audit_log_id)
+);
+
+COMMENT ON TABLE audit_log_hashes IS 'Links audit logs into a hash chain, so modified or deleted audit logs can be detected. Hashes are kept when audit logs are deleted by the retention policy.';
+COMMENT ON COLUMN audit_log_hashes.sequence IS 'Position of the audit log in the chain, starting at 1 without gaps.';
+COMMENT ON COLUMN audit_log_hashes.prev_hash IS 'Hash of the previous audit log in the chain, empty for the first one.';
+COMMENT ON COLUMN audit_log_hashes.hash IS 'SHA-256 hash of the previous hash and the content of the audit log.';
+COMMENT ON COLUMN audit_log_hashes.pruned_at IS 'When the audit log was deleted by the retention policy.';
+
+CREATE TABLE audit_log_checkpoints (
+	sequence bigint NOT NULL,
+	hash bytea NOT NULL,
+	signature bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (sequence)
+);
+
+COMMENT ON TABLE audit_log_checkpoints IS 'Signed hashes of the audit log chain, so the chain cannot be rewritten or truncated without the deployment signing key.';
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE audit_log_hashes
+	DROP COLUMN created_at,
+	DROP COLUMN audit_log_time,
+	DROP COLUMN resource_type;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+ALTER TABLE audit_log_hashes
+	ADD COLUMN created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+	ADD COLUMN audit_log_time timestamp with time zone,
+	ADD COLUMN resource_type resource_type;
+
+UPDATE
+	audit_log_hashes
+SET
+	audit_log_time = audit_logs."time",
+	resource_type = audit_logs.resource_type
+FROM
+	audit_logs
+WHERE
+	audit_logs.id = audit_log_hashes.audit_log_id;
+
+COMMENT ON COLUMN audit_log_hashes.created_at IS 'When the audit log was appended to the chain.';
+COMMENT ON COLUMN audit_log_hashes.audit_log_time IS 'Time of the audit log, kept to verify that pruned audit logs were past their retention period.';
+COMMENT ON COLUMN audit_log_hashes.resource_type IS 'Resource type of the audit log, kept to verify that pruned audit logs were past their retention period.';
+
+COMMIT;
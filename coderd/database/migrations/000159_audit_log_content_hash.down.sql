@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE audit_log_hashes
+	DROP COLUMN content_hash;
+
+COMMENT ON COLUMN audit_log_hashes.hash IS 'SHA-256 hash of the previous hash and the content of the audit log.';
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE audit_log_hashes
+	ADD COLUMN content_hash bytea;
+
+COMMENT ON COLUMN audit_log_hashes.content_hash IS 'SHA-256 hash of the content of the audit log. The hash chains it with the time and resource type of the audit log, so they can be verified after it is deleted. Null for audit logs chained before it was added.';
+
+COMMENT ON COLUMN audit_log_hashes.hash IS 'SHA-256 hash of the previous hash, and the time, resource type and content hash of the audit log. Audit logs chained before content hashes were kept hash their content directly.';
+
+COMMIT;
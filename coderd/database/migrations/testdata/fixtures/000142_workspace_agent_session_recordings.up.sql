@@ -0,0 +1,21 @@
+INSERT INTO workspace_agent_session_recordings (
+	id,
+	created_at,
+	workspace_id,
+	agent_id,
+	type,
+	started_at,
+	ended_at,
+	size,
+	data
+) VALUES (
+	'e7bd5d4c-0a1f-4ab3-9d5c-3b5b2a1f3c11',
+	NOW(),
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'ssh',
+	NOW() - INTERVAL '1 minute',
+	NOW(),
+	2,
+	'{}'
+);
@@ -0,0 +1,31 @@
+INSERT INTO workspace_agent_scripts (
+	id,
+	created_at,
+	agent_id,
+	name,
+	display_name,
+	script,
+	run_on,
+	timeout_seconds,
+	start_blocks_login,
+	depends_on,
+	status,
+	exit_code,
+	started_at,
+	ended_at
+) VALUES (
+	'b1c2d3e4-5f60-4718-8a9b-0c1d2e3f4a5b',
+	NOW(),
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'dotfiles',
+	'Dotfiles',
+	'coder dotfiles -y',
+	'start',
+	300,
+	true,
+	'{}',
+	'success',
+	0,
+	NOW() - INTERVAL '1 minute',
+	NOW()
+);
@@ -0,0 +1,47 @@
+INSERT INTO webhooks (
+	id,
+	created_at,
+	updated_at,
+	name,
+	url,
+	secret,
+	events,
+	enabled
+) VALUES (
+	'5c1a8e2b-7d3f-4b6a-9e0c-2f4d6a8b0c1e',
+	NOW(),
+	NOW(),
+	'chat',
+	'https://chat.example.com/hooks/coder',
+	'secret',
+	'{workspace_build_failed,user_created}',
+	true
+);
+
+INSERT INTO webhook_deliveries (
+	id,
+	webhook_id,
+	created_at,
+	event,
+	payload,
+	status,
+	attempts,
+	next_attempt_at,
+	last_attempt_at,
+	status_code,
+	error,
+	delivered_at
+) VALUES (
+	'8e2f4a6b-1c3d-4e5f-a7b9-c0d1e2f3a4b5',
+	'5c1a8e2b-7d3f-4b6a-9e0c-2f4d6a8b0c1e',
+	NOW(),
+	'user_created',
+	'{"event": "user_created"}',
+	'delivered',
+	1,
+	NOW(),
+	NOW(),
+	204,
+	'',
+	NOW()
+);
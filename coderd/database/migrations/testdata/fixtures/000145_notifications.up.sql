@@ -0,0 +1,41 @@
+INSERT INTO notification_messages (
+	id,
+	user_id,
+	created_at,
+	kind,
+	recipient,
+	subject,
+	body,
+	status,
+	attempts,
+	next_attempt_at,
+	last_attempt_at,
+	error,
+	sent_at
+) VALUES (
+	'3f6c2a1e-9b8d-4c7e-a5f4-1d2e3c4b5a69',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	NOW(),
+	'workspace_build_failed',
+	'admin@coder.com',
+	'Workspace build failed',
+	'The build of your workspace failed.',
+	'sent',
+	1,
+	NOW(),
+	NOW(),
+	'',
+	NOW()
+);
+
+INSERT INTO user_notification_preferences (
+	user_id,
+	kind,
+	disabled,
+	updated_at
+) VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'token_expiring',
+	true,
+	NOW()
+);
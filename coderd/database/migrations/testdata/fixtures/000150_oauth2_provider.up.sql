@@ -0,0 +1,69 @@
+INSERT INTO oauth2_provider_apps (
+	id,
+	created_at,
+	updated_at,
+	name,
+	icon,
+	callback_url
+) VALUES (
+	'b0a6a4c2-5e3d-4f1b-8c7a-9d2e1f0a3b4c',
+	NOW(),
+	NOW(),
+	'dashboard',
+	'/emojis/1f4ca.png',
+	'https://dashboard.example.com/oauth/callback'
+);
+
+INSERT INTO oauth2_provider_app_secrets (
+	id,
+	created_at,
+	last_used_at,
+	hashed_secret,
+	display_secret,
+	app_id
+) VALUES (
+	'c1b7b5d3-6f4e-4a2c-9d8b-0e3f2a1b4c5d',
+	NOW(),
+	NOW(),
+	'\x50ae3bffeac2d2a30e3a7534ca67400c4190c513f2044b5721954b72a1b66ca0',
+	'a1b2c3',
+	'b0a6a4c2-5e3d-4f1b-8c7a-9d2e1f0a3b4c'
+);
+
+INSERT INTO oauth2_provider_app_codes (
+	id,
+	created_at,
+	expires_at,
+	hashed_secret,
+	user_id,
+	app_id,
+	redirect_uri,
+	scope,
+	code_challenge
+) VALUES (
+	'd2c8c6e4-7a5f-4b3d-8e9c-1f4a3b2c5d6e',
+	NOW(),
+	NOW(),
+	'\x665200a4744e4f318551a7ca6944e070774e8903a680e8a0a592e0f2c328efb3',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'b0a6a4c2-5e3d-4f1b-8c7a-9d2e1f0a3b4c',
+	'',
+	'all',
+	'E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM'
+);
+
+INSERT INTO oauth2_provider_app_tokens (
+	id,
+	created_at,
+	expires_at,
+	hashed_refresh_token,
+	app_secret_id,
+	api_key_id
+) VALUES (
+	'e3d9d7f5-8b6a-4c4e-9f0d-2a5b4c3d6e7f',
+	NOW(),
+	NOW(),
+	'\x7a7b3c1d2e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b',
+	'c1b7b5d3-6f4e-4a2c-9d8b-0e3f2a1b4c5d',
+	'WEG2T4MNno'
+);
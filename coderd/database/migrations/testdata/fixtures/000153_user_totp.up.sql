@@ -0,0 +1,27 @@
+INSERT INTO user_totp (
+	user_id,
+	secret,
+	enabled,
+	last_used_step,
+	created_at,
+	updated_at
+) VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'JBSWY3DPEHPK3PXP',
+	true,
+	56789012,
+	NOW(),
+	NOW()
+);
+
+INSERT INTO user_totp_recovery_codes (
+	user_id,
+	hashed_code,
+	created_at,
+	used_at
+) VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'\x9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08',
+	NOW(),
+	NULL
+);
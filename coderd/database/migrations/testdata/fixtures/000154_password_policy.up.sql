@@ -0,0 +1,19 @@
+INSERT INTO user_login_failures (
+	user_id,
+	ip_address,
+	created_at
+) VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'127.0.0.1',
+	NOW()
+);
+
+INSERT INTO user_password_history (
+	user_id,
+	hashed_password,
+	created_at
+) VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'\x2432612431302451',
+	NOW()
+);
@@ -0,0 +1,17 @@
+INSERT INTO custom_roles (
+	id,
+	created_at,
+	updated_at,
+	name,
+	display_name,
+	organization_id,
+	permissions
+) VALUES (
+	'5a5e3c1b-1b2f-4b8e-9d0c-4f1e2a3b4c5d',
+	NOW(),
+	NOW(),
+	'workspace-viewer',
+	'Workspace Viewer',
+	NULL,
+	'[{"negate": false, "resource_type": "workspace", "action": "read"}]'
+);
@@ -0,0 +1,25 @@
+INSERT INTO audit_log_hashes (
+	sequence,
+	audit_log_id,
+	prev_hash,
+	hash,
+	pruned_at
+) VALUES (
+	1,
+	'9cf5b1a5-5c8b-4b6a-9a3e-2f4c1d7e8a90',
+	'\x',
+	'\x5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef',
+	NOW()
+);
+
+INSERT INTO audit_log_checkpoints (
+	sequence,
+	hash,
+	signature,
+	created_at
+) VALUES (
+	1,
+	'\x5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef',
+	'\x00',
+	NOW()
+);
@@ -3,9 +3,12 @@ package database
 import (
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/rbac"
 )
@@ -92,11 +95,49 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 		return rbac.ScopeAll
 	case APIKeyScopeApplicationConnect:
 		return rbac.ScopeApplicationConnect
+	case APIKeyScopeReadOnly:
+		return rbac.ScopeReadOnly
+	case APIKeyScopeWorkspaceStartStop:
+		return rbac.ScopeWorkspaceStartStop
+	case APIKeyScopeTemplatePush:
+		return rbac.ScopeTemplatePush
+	case APIKeyScopeUserRead:
+		return rbac.ScopeUserRead
 	default:
 		panic("developer error: unknown scope type " + string(s))
 	}
 }
 
+// RBACScope returns the scope requests authenticated with the key are
+// authorized with. Keys with an allow list can only affect the listed
+// resources, read their owner and read the given organizations the owner is
+// a member of.
+func (k APIKey) RBACScope(organizationIDs []uuid.UUID) (rbac.ExpandableScope, error) {
+	if len(k.AllowList) == 0 {
+		return k.Scope.ToRBAC(), nil
+	}
+
+	allowList := []string{
+		// The bare user ID would allow the user data of the owner too, like
+		// their git SSH key and password.
+		rbac.AllowResource(rbac.ResourceUser, k.UserID.String()),
+		// Files have no parent resource, they are required to push
+		// template versions.
+		rbac.AllowAllOfType(rbac.ResourceFile),
+	}
+	for _, organizationID := range organizationIDs {
+		allowList = append(allowList, rbac.AllowResource(rbac.ResourceOrganization, organizationID.String()))
+	}
+	for _, entry := range k.AllowList {
+		_, id, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, xerrors.Errorf("invalid allow list entry %q", entry)
+		}
+		allowList = append(allowList, id)
+	}
+	return rbac.RestrictedScope(k.Scope.ToRBAC(), allowList)
+}
+
 func (k APIKey) RBACObject() rbac.Object {
 	return rbac.ResourceAPIKey.WithIDString(k.ID).
 		WithOwner(k.UserID.String())
@@ -141,7 +182,9 @@ func (g Group) RBACObject() rbac.Object {
 func (w Workspace) RBACObject() rbac.Object {
 	return rbac.ResourceWorkspace.WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL).
+		WithGroupACL(w.GroupACL)
 }
 
 func (w Workspace) ExecutionRBAC() rbac.Object {
@@ -150,10 +193,13 @@ func (w Workspace) ExecutionRBAC() rbac.Object {
 		return w.LockedRBAC()
 	}
 
+	// Users and groups the workspace is shared with can connect to it.
 	return rbac.ResourceWorkspaceExecution.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL).
+		WithGroupACL(w.GroupACL)
 }
 
 func (w Workspace) ApplicationConnectRBAC() rbac.Object {
@@ -165,7 +211,9 @@ func (w Workspace) ApplicationConnectRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceApplicationConnect.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL).
+		WithGroupACL(w.GroupACL)
 }
 
 func (w Workspace) WorkspaceBuildRBAC(transition WorkspaceTransition) rbac.Object {
@@ -184,6 +232,13 @@ func (w Workspace) WorkspaceBuildRBAC(transition WorkspaceTransition) rbac.Objec
 		WithOwner(w.OwnerID.String())
 }
 
+func (w Workspace) SessionRecordingRBAC() rbac.Object {
+	return rbac.ResourceWorkspaceSessionRecording.
+		WithID(w.ID).
+		InOrg(w.OrganizationID).
+		WithOwner(w.OwnerID.String())
+}
+
 func (w Workspace) LockedRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceLocked.
 		WithID(w.ID).
@@ -248,6 +303,10 @@ func (u GitSSHKey) RBACObject() rbac.Object {
 	return rbac.ResourceUserData.WithID(u.UserID).WithOwner(u.UserID.String())
 }
 
+func (u UserTOTP) RBACObject() rbac.Object {
+	return rbac.ResourceUserData.WithID(u.UserID).WithOwner(u.UserID.String())
+}
+
 func (u GitAuthLink) RBACObject() rbac.Object {
 	// I assume UserData is ok?
 	return rbac.ResourceUserData.WithID(u.UserID).WithOwner(u.UserID.String())
@@ -262,6 +321,40 @@ func (l License) RBACObject() rbac.Object {
 	return rbac.ResourceLicense.WithIDString(strconv.FormatInt(int64(l.ID), 10))
 }
 
+func (w Webhook) RBACObject() rbac.Object {
+	return rbac.ResourceWebhook.WithID(w.ID)
+}
+
+func (a OAuth2ProviderApp) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderApp.WithID(a.ID)
+}
+
+func (s OAuth2ProviderAppSecret) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppSecret.WithID(s.ID)
+}
+
+func (c OAuth2ProviderAppCode) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppCodeToken.WithID(c.ID).WithOwner(c.UserID.String())
+}
+
+func (r CustomRole) RBACObject() rbac.Object {
+	obj := rbac.ResourceCustomRole.WithID(r.ID)
+	if r.OrganizationID.Valid {
+		obj = obj.InOrg(r.OrganizationID.UUID)
+	}
+	return obj
+}
+
+// RoleName is the name the role is assigned by. Organization roles are
+// scoped to their organization like the built-in organization roles.
+func (r CustomRole) RoleName() string {
+	orgID := ""
+	if r.OrganizationID.Valid {
+		orgID = r.OrganizationID.UUID.String()
+	}
+	return rbac.RoleName(r.Name, orgID)
+}
+
 type WorkspaceAgentConnectionStatus struct {
 	Status           WorkspaceAgentStatus `json:"status"`
 	FirstConnectedAt *time.Time           `json:"first_connected_at"`
@@ -321,18 +414,19 @@ func ConvertUserRows(rows []GetUsersRow) []User {
 	users := make([]User, len(rows))
 	for i, r := range rows {
 		users[i] = User{
-			ID:             r.ID,
-			Email:          r.Email,
-			Username:       r.Username,
-			HashedPassword: r.HashedPassword,
-			CreatedAt:      r.CreatedAt,
-			UpdatedAt:      r.UpdatedAt,
-			Status:         r.Status,
-			RBACRoles:      r.RBACRoles,
-			LoginType:      r.LoginType,
-			AvatarURL:      r.AvatarURL,
-			Deleted:        r.Deleted,
-			LastSeenAt:     r.LastSeenAt,
+			ID:               r.ID,
+			Email:            r.Email,
+			Username:         r.Username,
+			HashedPassword:   r.HashedPassword,
+			CreatedAt:        r.CreatedAt,
+			UpdatedAt:        r.UpdatedAt,
+			Status:           r.Status,
+			RBACRoles:        r.RBACRoles,
+			LoginType:        r.LoginType,
+			AvatarURL:        r.AvatarURL,
+			Deleted:          r.Deleted,
+			LastSeenAt:       r.LastSeenAt,
+			IsServiceAccount: r.IsServiceAccount,
 		}
 	}
 
@@ -85,6 +85,8 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.LockedTTL,
 			&i.RestartRequirementDaysOfWeek,
 			&i.RestartRequirementWeeks,
+			&i.RecordSessions,
+			&i.PrebuiltWorkspaces,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -187,13 +189,99 @@ func (q *sqlQuerier) GetTemplateGroupRoles(ctx context.Context, id uuid.UUID) ([
 
 type workspaceQuerier interface {
 	GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspacesParams, prepared rbac.PreparedAuthorized) ([]GetWorkspacesRow, error)
+	GetWorkspaceGroupRoles(ctx context.Context, id uuid.UUID) ([]WorkspaceGroup, error)
+	GetWorkspaceUserRoles(ctx context.Context, id uuid.UUID) ([]WorkspaceUser, error)
+}
+
+type WorkspaceUser struct {
+	User
+	Actions Actions `db:"actions"`
+}
+
+func (q *sqlQuerier) GetWorkspaceUserRoles(ctx context.Context, id uuid.UUID) ([]WorkspaceUser, error) {
+	const query = `
+	SELECT
+		perms.value as actions, users.*
+	FROM
+		users
+	JOIN
+		(
+			SELECT
+				*
+			FROM
+				jsonb_each_text(
+					(
+						SELECT
+							workspaces.user_acl
+						FROM
+							workspaces
+						WHERE
+							id = $1
+					)
+				)
+		) AS perms
+	ON
+		users.id::text = perms.key
+	WHERE
+		users.deleted = false
+	AND
+		users.status = 'active';
+	`
+
+	var wus []WorkspaceUser
+	err := q.db.SelectContext(ctx, &wus, query, id.String())
+	if err != nil {
+		return nil, xerrors.Errorf("select user actions: %w", err)
+	}
+
+	return wus, nil
+}
+
+type WorkspaceGroup struct {
+	Group
+	Actions Actions `db:"actions"`
+}
+
+func (q *sqlQuerier) GetWorkspaceGroupRoles(ctx context.Context, id uuid.UUID) ([]WorkspaceGroup, error) {
+	const query = `
+	SELECT
+		perms.value as actions, groups.*
+	FROM
+		groups
+	JOIN
+		(
+			SELECT
+				*
+			FROM
+				jsonb_each_text(
+					(
+						SELECT
+							workspaces.group_acl
+						FROM
+							workspaces
+						WHERE
+							id = $1
+					)
+				)
+		) AS perms
+	ON
+		groups.id::text = perms.key;
+	`
+
+	var wgs []WorkspaceGroup
+	err := q.db.SelectContext(ctx, &wgs, query, id.String())
+	if err != nil {
+		return nil, xerrors.Errorf("select group roles: %w", err)
+	}
+
+	return wgs, nil
 }
 
 // GetAuthorizedWorkspaces returns all workspaces that the user is authorized to access.
 // This code is copied from `GetWorkspaces` and adds the authorized filter WHERE
 // clause.
 func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspacesParams, prepared rbac.PreparedAuthorized) ([]GetWorkspacesRow, error) {
-	authorizedFilter, err := prepared.CompileToSQL(ctx, rbac.ConfigWithoutACL())
+	authorizedFilter, err := prepared.CompileToSQL(ctx, rbac.ConfigWithACL())
 	if err != nil {
 		return nil, xerrors.Errorf("compile authorized filter: %w", err)
 	}
@@ -241,6 +329,8 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.LastUsedAt,
 			&i.LockedAt,
 			&i.DeletingAt,
+			&i.UserACL,
+			&i.GroupACL,
 			&i.TemplateName,
 			&i.TemplateVersionID,
 			&i.TemplateVersionName,
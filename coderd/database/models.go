@@ -21,6 +21,10 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeReadOnly           APIKeyScope = "read_only"
+	APIKeyScopeWorkspaceStartStop APIKeyScope = "workspace_start_stop"
+	APIKeyScopeTemplatePush       APIKeyScope = "template_push"
+	APIKeyScopeUserRead           APIKeyScope = "user_read"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -61,7 +65,11 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeReadOnly,
+		APIKeyScopeWorkspaceStartStop,
+		APIKeyScopeTemplatePush,
+		APIKeyScopeUserRead:
 		return true
 	}
 	return false
@@ -71,6 +79,10 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeReadOnly,
+		APIKeyScopeWorkspaceStartStop,
+		APIKeyScopeTemplatePush,
+		APIKeyScopeUserRead,
 	}
 }
 
@@ -138,14 +150,16 @@ func AllAppSharingLevelValues() []AppSharingLevel {
 type AuditAction string
 
 const (
-	AuditActionCreate   AuditAction = "create"
-	AuditActionWrite    AuditAction = "write"
-	AuditActionDelete   AuditAction = "delete"
-	AuditActionStart    AuditAction = "start"
-	AuditActionStop     AuditAction = "stop"
-	AuditActionLogin    AuditAction = "login"
-	AuditActionLogout   AuditAction = "logout"
-	AuditActionRegister AuditAction = "register"
+	AuditActionCreate     AuditAction = "create"
+	AuditActionWrite      AuditAction = "write"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionStart      AuditAction = "start"
+	AuditActionStop       AuditAction = "stop"
+	AuditActionLogin      AuditAction = "login"
+	AuditActionLogout     AuditAction = "logout"
+	AuditActionRegister   AuditAction = "register"
+	AuditActionConnect    AuditAction = "connect"
+	AuditActionDisconnect AuditAction = "disconnect"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -192,7 +206,9 @@ func (e AuditAction) Valid() bool {
 		AuditActionStop,
 		AuditActionLogin,
 		AuditActionLogout,
-		AuditActionRegister:
+		AuditActionRegister,
+		AuditActionConnect,
+		AuditActionDisconnect:
 		return true
 	}
 	return false
@@ -208,6 +224,8 @@ func AllAuditActionValues() []AuditAction {
 		AuditActionLogin,
 		AuditActionLogout,
 		AuditActionRegister,
+		AuditActionConnect,
+		AuditActionDisconnect,
 	}
 }
 
@@ -410,11 +428,14 @@ func AllLogSourceValues() []LogSource {
 type LoginType string
 
 const (
-	LoginTypePassword LoginType = "password"
-	LoginTypeGithub   LoginType = "github"
-	LoginTypeOIDC     LoginType = "oidc"
-	LoginTypeToken    LoginType = "token"
-	LoginTypeNone     LoginType = "none"
+	LoginTypePassword          LoginType = "password"
+	LoginTypeGithub            LoginType = "github"
+	LoginTypeOIDC              LoginType = "oidc"
+	LoginTypeToken             LoginType = "token"
+	LoginTypeNone              LoginType = "none"
+	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
+	LoginTypeLDAP              LoginType = "ldap"
+	LoginTypeOAuth2            LoginType = "oauth2"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -458,7 +479,10 @@ func (e LoginType) Valid() bool {
 		LoginTypeGithub,
 		LoginTypeOIDC,
 		LoginTypeToken,
-		LoginTypeNone:
+		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeLDAP,
+		LoginTypeOAuth2:
 		return true
 	}
 	return false
@@ -471,6 +495,134 @@ func AllLoginTypeValues() []LoginType {
 		LoginTypeOIDC,
 		LoginTypeToken,
 		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeLDAP,
+		LoginTypeOAuth2,
+	}
+}
+
+type NotificationKind string
+
+const (
+	NotificationKindWorkspaceAutostop    NotificationKind = "workspace_autostop"
+	NotificationKindWorkspaceBuildFailed NotificationKind = "workspace_build_failed"
+	NotificationKindWorkspaceLocked      NotificationKind = "workspace_locked"
+	NotificationKindTokenExpiring        NotificationKind = "token_expiring"
+)
+
+func (e *NotificationKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationKind(s)
+	case string:
+		*e = NotificationKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationKind: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationKind struct {
+	NotificationKind NotificationKind `json:"notification_kind"`
+	Valid            bool             `json:"valid"` // Valid is true if NotificationKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationKind), nil
+}
+
+func (e NotificationKind) Valid() bool {
+	switch e {
+	case NotificationKindWorkspaceAutostop,
+		NotificationKindWorkspaceBuildFailed,
+		NotificationKindWorkspaceLocked,
+		NotificationKindTokenExpiring:
+		return true
+	}
+	return false
+}
+
+func AllNotificationKindValues() []NotificationKind {
+	return []NotificationKind{
+		NotificationKindWorkspaceAutostop,
+		NotificationKindWorkspaceBuildFailed,
+		NotificationKindWorkspaceLocked,
+		NotificationKindTokenExpiring,
+	}
+}
+
+type NotificationMessageStatus string
+
+const (
+	NotificationMessageStatusPending NotificationMessageStatus = "pending"
+	NotificationMessageStatusSent    NotificationMessageStatus = "sent"
+	NotificationMessageStatusFailed  NotificationMessageStatus = "failed"
+)
+
+func (e *NotificationMessageStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationMessageStatus(s)
+	case string:
+		*e = NotificationMessageStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationMessageStatus: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationMessageStatus struct {
+	NotificationMessageStatus NotificationMessageStatus `json:"notification_message_status"`
+	Valid                     bool                      `json:"valid"` // Valid is true if NotificationMessageStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationMessageStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationMessageStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationMessageStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationMessageStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationMessageStatus), nil
+}
+
+func (e NotificationMessageStatus) Valid() bool {
+	switch e {
+	case NotificationMessageStatusPending,
+		NotificationMessageStatusSent,
+		NotificationMessageStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllNotificationMessageStatusValues() []NotificationMessageStatus {
+	return []NotificationMessageStatus{
+		NotificationMessageStatusPending,
+		NotificationMessageStatusSent,
+		NotificationMessageStatusFailed,
 	}
 }
 
@@ -889,18 +1041,24 @@ func AllProvisionerTypeValues() []ProvisionerType {
 type ResourceType string
 
 const (
-	ResourceTypeOrganization    ResourceType = "organization"
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeGitSshKey       ResourceType = "git_ssh_key"
-	ResourceTypeApiKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
+	ResourceTypeOrganization            ResourceType = "organization"
+	ResourceTypeTemplate                ResourceType = "template"
+	ResourceTypeTemplateVersion         ResourceType = "template_version"
+	ResourceTypeUser                    ResourceType = "user"
+	ResourceTypeWorkspace               ResourceType = "workspace"
+	ResourceTypeGitSshKey               ResourceType = "git_ssh_key"
+	ResourceTypeApiKey                  ResourceType = "api_key"
+	ResourceTypeGroup                   ResourceType = "group"
+	ResourceTypeWorkspaceBuild          ResourceType = "workspace_build"
+	ResourceTypeLicense                 ResourceType = "license"
+	ResourceTypeWorkspaceProxy          ResourceType = "workspace_proxy"
+	ResourceTypeConvertLogin            ResourceType = "convert_login"
+	ResourceTypeWebhook                 ResourceType = "webhook"
+	ResourceTypeOAuth2ProviderApp       ResourceType = "oauth2_provider_app"
+	ResourceTypeOAuth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
+	ResourceTypeCustomRole              ResourceType = "custom_role"
+	ResourceTypeWorkspaceAgent          ResourceType = "workspace_agent"
+	ResourceTypeWorkspaceApp            ResourceType = "workspace_app"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -951,7 +1109,13 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeWorkspaceBuild,
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
-		ResourceTypeConvertLogin:
+		ResourceTypeConvertLogin,
+		ResourceTypeWebhook,
+		ResourceTypeOAuth2ProviderApp,
+		ResourceTypeOAuth2ProviderAppSecret,
+		ResourceTypeCustomRole,
+		ResourceTypeWorkspaceAgent,
+		ResourceTypeWorkspaceApp:
 		return true
 	}
 	return false
@@ -971,6 +1135,70 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
 		ResourceTypeConvertLogin,
+		ResourceTypeWebhook,
+		ResourceTypeOAuth2ProviderApp,
+		ResourceTypeOAuth2ProviderAppSecret,
+		ResourceTypeCustomRole,
+		ResourceTypeWorkspaceAgent,
+		ResourceTypeWorkspaceApp,
+	}
+}
+
+type SessionRecordingType string
+
+const (
+	SessionRecordingTypeSSH             SessionRecordingType = "ssh"
+	SessionRecordingTypeReconnectingPTY SessionRecordingType = "reconnecting_pty"
+)
+
+func (e *SessionRecordingType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SessionRecordingType(s)
+	case string:
+		*e = SessionRecordingType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SessionRecordingType: %T", src)
+	}
+	return nil
+}
+
+type NullSessionRecordingType struct {
+	SessionRecordingType SessionRecordingType `json:"session_recording_type"`
+	Valid                bool                 `json:"valid"` // Valid is true if SessionRecordingType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSessionRecordingType) Scan(value interface{}) error {
+	if value == nil {
+		ns.SessionRecordingType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SessionRecordingType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSessionRecordingType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SessionRecordingType), nil
+}
+
+func (e SessionRecordingType) Valid() bool {
+	switch e {
+	case SessionRecordingTypeSSH,
+		SessionRecordingTypeReconnectingPTY:
+		return true
+	}
+	return false
+}
+
+func AllSessionRecordingTypeValues() []SessionRecordingType {
+	return []SessionRecordingType{
+		SessionRecordingTypeSSH,
+		SessionRecordingTypeReconnectingPTY,
 	}
 }
 
@@ -1037,6 +1265,7 @@ type UserStatus string
 const (
 	UserStatusActive    UserStatus = "active"
 	UserStatusSuspended UserStatus = "suspended"
+	UserStatusLocked    UserStatus = "locked"
 )
 
 func (e *UserStatus) Scan(src interface{}) error {
@@ -1077,7 +1306,8 @@ func (ns NullUserStatus) Value() (driver.Value, error) {
 func (e UserStatus) Valid() bool {
 	switch e {
 	case UserStatusActive,
-		UserStatusSuspended:
+		UserStatusSuspended,
+		UserStatusLocked:
 		return true
 	}
 	return false
@@ -1087,6 +1317,141 @@ func AllUserStatusValues() []UserStatus {
 	return []UserStatus{
 		UserStatusActive,
 		UserStatusSuspended,
+		UserStatusLocked,
+	}
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhook_delivery_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
+func (e WebhookDeliveryStatus) Valid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusDelivered,
+		WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllWebhookDeliveryStatusValues() []WebhookDeliveryStatus {
+	return []WebhookDeliveryStatus{
+		WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusDelivered,
+		WebhookDeliveryStatusFailed,
+	}
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceBuildStarted     WebhookEvent = "workspace_build_started"
+	WebhookEventWorkspaceBuildSucceeded   WebhookEvent = "workspace_build_succeeded"
+	WebhookEventWorkspaceBuildFailed      WebhookEvent = "workspace_build_failed"
+	WebhookEventWorkspaceAutostopImminent WebhookEvent = "workspace_autostop_imminent"
+	WebhookEventTemplateVersionActivated  WebhookEvent = "template_version_activated"
+	WebhookEventUserCreated               WebhookEvent = "user_created"
+	WebhookEventUserSuspended             WebhookEvent = "user_suspended"
+)
+
+func (e *WebhookEvent) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookEvent(s)
+	case string:
+		*e = WebhookEvent(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookEvent: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookEvent struct {
+	WebhookEvent WebhookEvent `json:"webhook_event"`
+	Valid        bool         `json:"valid"` // Valid is true if WebhookEvent is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookEvent) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookEvent, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookEvent.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookEvent) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookEvent), nil
+}
+
+func (e WebhookEvent) Valid() bool {
+	switch e {
+	case WebhookEventWorkspaceBuildStarted,
+		WebhookEventWorkspaceBuildSucceeded,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventWorkspaceAutostopImminent,
+		WebhookEventTemplateVersionActivated,
+		WebhookEventUserCreated,
+		WebhookEventUserSuspended:
+		return true
+	}
+	return false
+}
+
+func AllWebhookEventValues() []WebhookEvent {
+	return []WebhookEvent{
+		WebhookEventWorkspaceBuildStarted,
+		WebhookEventWorkspaceBuildSucceeded,
+		WebhookEventWorkspaceBuildFailed,
+		WebhookEventWorkspaceAutostopImminent,
+		WebhookEventTemplateVersionActivated,
+		WebhookEventUserCreated,
+		WebhookEventUserSuspended,
 	}
 }
 
@@ -1169,6 +1534,134 @@ func AllWorkspaceAgentLifecycleStateValues() []WorkspaceAgentLifecycleState {
 	}
 }
 
+type WorkspaceAgentScriptRunOn string
+
+const (
+	WorkspaceAgentScriptRunOnStart WorkspaceAgentScriptRunOn = "start"
+	WorkspaceAgentScriptRunOnStop  WorkspaceAgentScriptRunOn = "stop"
+)
+
+func (e *WorkspaceAgentScriptRunOn) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentScriptRunOn(s)
+	case string:
+		*e = WorkspaceAgentScriptRunOn(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentScriptRunOn: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentScriptRunOn struct {
+	WorkspaceAgentScriptRunOn WorkspaceAgentScriptRunOn `json:"workspace_agent_script_run_on"`
+	Valid                     bool                      `json:"valid"` // Valid is true if WorkspaceAgentScriptRunOn is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentScriptRunOn) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentScriptRunOn, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentScriptRunOn.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentScriptRunOn) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentScriptRunOn), nil
+}
+
+func (e WorkspaceAgentScriptRunOn) Valid() bool {
+	switch e {
+	case WorkspaceAgentScriptRunOnStart,
+		WorkspaceAgentScriptRunOnStop:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentScriptRunOnValues() []WorkspaceAgentScriptRunOn {
+	return []WorkspaceAgentScriptRunOn{
+		WorkspaceAgentScriptRunOnStart,
+		WorkspaceAgentScriptRunOnStop,
+	}
+}
+
+type WorkspaceAgentScriptStatus string
+
+const (
+	WorkspaceAgentScriptStatusPending WorkspaceAgentScriptStatus = "pending"
+	WorkspaceAgentScriptStatusRunning WorkspaceAgentScriptStatus = "running"
+	WorkspaceAgentScriptStatusSuccess WorkspaceAgentScriptStatus = "success"
+	WorkspaceAgentScriptStatusError   WorkspaceAgentScriptStatus = "error"
+	WorkspaceAgentScriptStatusTimeout WorkspaceAgentScriptStatus = "timeout"
+	WorkspaceAgentScriptStatusSkipped WorkspaceAgentScriptStatus = "skipped"
+)
+
+func (e *WorkspaceAgentScriptStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentScriptStatus(s)
+	case string:
+		*e = WorkspaceAgentScriptStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentScriptStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentScriptStatus struct {
+	WorkspaceAgentScriptStatus WorkspaceAgentScriptStatus `json:"workspace_agent_script_status"`
+	Valid                      bool                       `json:"valid"` // Valid is true if WorkspaceAgentScriptStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentScriptStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentScriptStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentScriptStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentScriptStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentScriptStatus), nil
+}
+
+func (e WorkspaceAgentScriptStatus) Valid() bool {
+	switch e {
+	case WorkspaceAgentScriptStatusPending,
+		WorkspaceAgentScriptStatusRunning,
+		WorkspaceAgentScriptStatusSuccess,
+		WorkspaceAgentScriptStatusError,
+		WorkspaceAgentScriptStatusTimeout,
+		WorkspaceAgentScriptStatusSkipped:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentScriptStatusValues() []WorkspaceAgentScriptStatus {
+	return []WorkspaceAgentScriptStatus{
+		WorkspaceAgentScriptStatusPending,
+		WorkspaceAgentScriptStatusRunning,
+		WorkspaceAgentScriptStatusSuccess,
+		WorkspaceAgentScriptStatusError,
+		WorkspaceAgentScriptStatusTimeout,
+		WorkspaceAgentScriptStatusSkipped,
+	}
+}
+
 type WorkspaceAgentSubsystem string
 
 const (
@@ -1369,6 +1862,37 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// Resources the key is restricted to, in the form <type>:<id>. The key is not restricted if the list is empty.
+	AllowList []string `db:"allow_list" json:"allow_list"`
+}
+
+// Signed hashes of the audit log chain, so the chain cannot be rewritten or truncated without the deployment signing key.
+type AuditLogCheckpoint struct {
+	Sequence  int64     `db:"sequence" json:"sequence"`
+	Hash      []byte    `db:"hash" json:"hash"`
+	Signature []byte    `db:"signature" json:"signature"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Links audit logs into a hash chain, so modified or deleted audit logs can be detected. Hashes are kept when audit logs are deleted by the retention policy.
+type AuditLogHash struct {
+	// Position of the audit log in the chain, starting at 1 without gaps.
+	Sequence   int64     `db:"sequence" json:"sequence"`
+	AuditLogID uuid.UUID `db:"audit_log_id" json:"audit_log_id"`
+	// Hash of the previous audit log in the chain, empty for the first one.
+	PrevHash []byte `db:"prev_hash" json:"prev_hash"`
+	// SHA-256 hash of the previous hash, and the time, resource type and content hash of the audit log. Audit logs chained before content hashes were kept hash their content directly.
+	Hash []byte `db:"hash" json:"hash"`
+	// When the audit log was deleted by the retention policy.
+	PrunedAt sql.NullTime `db:"pruned_at" json:"pruned_at"`
+	// When the audit log was appended to the chain.
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// Time of the audit log, kept to verify that pruned audit logs were past their retention period.
+	AuditLogTime sql.NullTime `db:"audit_log_time" json:"audit_log_time"`
+	// Resource type of the audit log, kept to verify that pruned audit logs were past their retention period.
+	ResourceType NullResourceType `db:"resource_type" json:"resource_type"`
+	// SHA-256 hash of the content of the audit log. The hash chains it with the time and resource type of the audit log, so they can be verified after it is deleted. Null for audit logs chained before it was added.
+	ContentHash []byte `db:"content_hash" json:"content_hash"`
 }
 
 type AuditLog struct {
@@ -1389,6 +1913,19 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Roles defined by admins in addition to the built-in roles. They are assigned by name like the built-in roles.
+type CustomRole struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	// The organization of an organization role, null for a site wide role.
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+	// The permissions granted by the role. They apply site wide for site wide roles and to the organization for organization roles.
+	Permissions json.RawMessage `db:"permissions" json:"permissions"`
+}
+
 type File struct {
 	Hash      string    `db:"hash" json:"hash"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
@@ -1438,6 +1975,71 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// The queue of notifications sent to users. Pending messages are retried until they are sent or run out of attempts.
+type NotificationMessage struct {
+	ID        uuid.UUID        `db:"id" json:"id"`
+	UserID    uuid.UUID        `db:"user_id" json:"user_id"`
+	CreatedAt time.Time        `db:"created_at" json:"created_at"`
+	Kind      NotificationKind `db:"kind" json:"kind"`
+	// The email address of the user at the time the message was enqueued.
+	Recipient string                    `db:"recipient" json:"recipient"`
+	Subject   string                    `db:"subject" json:"subject"`
+	Body      string                    `db:"body" json:"body"`
+	Status    NotificationMessageStatus `db:"status" json:"status"`
+	Attempts  int32                     `db:"attempts" json:"attempts"`
+	// The time at which the message is attempted next. While an attempt is in progress this is the time at which another replica may retry it.
+	NextAttemptAt time.Time    `db:"next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt sql.NullTime `db:"last_attempt_at" json:"last_attempt_at"`
+	Error         string       `db:"error" json:"error"`
+	SentAt        sql.NullTime `db:"sent_at" json:"sent_at"`
+}
+
+// Third-party applications that use Coder as an OAuth2 provider to call the API on behalf of users.
+type OAuth2ProviderApp struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
+	Icon      string    `db:"icon" json:"icon"`
+	// The URL users are redirected to after authorizing the app. Redirect URIs must be equal to or below it.
+	CallbackURL string `db:"callback_url" json:"callback_url"`
+}
+
+// Authorization codes that are exchanged for tokens once. They are only valid for a short time.
+type OAuth2ProviderAppCode struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	AppID        uuid.UUID `db:"app_id" json:"app_id"`
+	// The redirect URI given in the authorization request, empty if none was given. The token request must repeat it.
+	RedirectURI string      `db:"redirect_uri" json:"redirect_uri"`
+	Scope       APIKeyScope `db:"scope" json:"scope"`
+	// The PKCE code challenge, the base64url encoded SHA-256 hash of the code verifier.
+	CodeChallenge string `db:"code_challenge" json:"code_challenge"`
+}
+
+type OAuth2ProviderAppSecret struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt   sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	HashedSecret []byte       `db:"hashed_secret" json:"hashed_secret"`
+	// The tail end of the original secret so secrets can be told apart.
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+}
+
+// Tokens issued to apps. The access token is the API key, the refresh token can be exchanged for a new one until expires_at.
+type OAuth2ProviderAppToken struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt          time.Time `db:"expires_at" json:"expires_at"`
+	HashedRefreshToken []byte    `db:"hashed_refresh_token" json:"hashed_refresh_token"`
+	AppSecretID        uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID           string    `db:"api_key_id" json:"api_key_id"`
+}
+
 type Organization struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -1593,6 +2195,8 @@ type Template struct {
 	LockedTTL                    int64           `db:"locked_ttl" json:"locked_ttl"`
 	RestartRequirementDaysOfWeek int16           `db:"restart_requirement_days_of_week" json:"restart_requirement_days_of_week"`
 	RestartRequirementWeeks      int64           `db:"restart_requirement_weeks" json:"restart_requirement_weeks"`
+	RecordSessions               bool            `db:"record_sessions" json:"record_sessions"`
+	PrebuiltWorkspaces           int32           `db:"prebuilt_workspaces" json:"prebuilt_workspaces"`
 	CreatedByAvatarURL           sql.NullString  `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername            string          `db:"created_by_username" json:"created_by_username"`
 }
@@ -1629,6 +2233,10 @@ type TemplateTable struct {
 	RestartRequirementDaysOfWeek int16 `db:"restart_requirement_days_of_week" json:"restart_requirement_days_of_week"`
 	// The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.
 	RestartRequirementWeeks int64 `db:"restart_requirement_weeks" json:"restart_requirement_weeks"`
+	// Record interactive terminal sessions in workspaces created from this template.
+	RecordSessions bool `db:"record_sessions" json:"record_sessions"`
+	// Number of prebuilt workspaces to keep ready for users to claim when they create a workspace from this template.
+	PrebuiltWorkspaces int32 `db:"prebuilt_workspaces" json:"prebuilt_workspaces"`
 }
 
 // Joins in the username + avatar url of the created by user.
@@ -1733,6 +2341,8 @@ type User struct {
 	LastSeenAt     time.Time      `db:"last_seen_at" json:"last_seen_at"`
 	// Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user's quiet hours. If empty, the default quiet hours on the instance is used instead.
 	QuietHoursSchedule string `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	// Service accounts are non-human users that authenticate with tokens only. They cannot log in and do not count towards the licensed user limit.
+	IsServiceAccount bool `db:"is_service_account" json:"is_service_account"`
 }
 
 type UserLink struct {
@@ -1744,6 +2354,48 @@ type UserLink struct {
 	OAuthExpiry       time.Time `db:"oauth_expiry" json:"oauth_expiry"`
 }
 
+// Failed password logins of users. They are counted to lock accounts and are deleted when the user logs in or is activated.
+type UserLoginFailure struct {
+	UserID    uuid.UUID   `db:"user_id" json:"user_id"`
+	IPAddress pqtype.Inet `db:"ip_address" json:"ip_address"`
+	CreatedAt time.Time   `db:"created_at" json:"created_at"`
+}
+
+// Notification kinds users have opted out of. Users receive all kinds of notifications without a row.
+type UserNotificationPreference struct {
+	UserID    uuid.UUID        `db:"user_id" json:"user_id"`
+	Kind      NotificationKind `db:"kind" json:"kind"`
+	Disabled  bool             `db:"disabled" json:"disabled"`
+	UpdatedAt time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// Previous passwords of users that cannot be reused when the password history policy is enabled.
+type UserPasswordHistory struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// Authenticator app secrets of users that use TOTP multi-factor authentication.
+type UserTOTP struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Secret string    `db:"secret" json:"secret"`
+	// Secrets are enabled once the user proves their authenticator app generates codes for it.
+	Enabled bool `db:"enabled" json:"enabled"`
+	// The time step of the last accepted code. Codes for it and earlier steps are rejected so they cannot be replayed.
+	LastUsedStep int64     `db:"last_used_step" json:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Single-use codes that replace a TOTP code when the authenticator app is lost.
+type UserTOTPRecoveryCode struct {
+	UserID     uuid.UUID    `db:"user_id" json:"user_id"`
+	HashedCode []byte       `db:"hashed_code" json:"hashed_code"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	UsedAt     sql.NullTime `db:"used_at" json:"used_at"`
+}
+
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
 type VisibleUser struct {
 	ID        uuid.UUID      `db:"id" json:"id"`
@@ -1751,6 +2403,37 @@ type VisibleUser struct {
 	AvatarURL sql.NullString `db:"avatar_url" json:"avatar_url"`
 }
 
+type Webhook struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
+	Url       string    `db:"url" json:"url"`
+	// The key used to sign delivered payloads with HMAC-SHA256.
+	Secret string `db:"secret" json:"secret"`
+	// The events that are delivered to the webhook.
+	Events  []WebhookEvent `db:"events" json:"events"`
+	Enabled bool           `db:"enabled" json:"enabled"`
+}
+
+// The delivery log of webhooks. Pending deliveries are retried until they are delivered or run out of attempts.
+type WebhookDelivery struct {
+	ID        uuid.UUID             `db:"id" json:"id"`
+	WebhookID uuid.UUID             `db:"webhook_id" json:"webhook_id"`
+	CreatedAt time.Time             `db:"created_at" json:"created_at"`
+	Event     WebhookEvent          `db:"event" json:"event"`
+	Payload   json.RawMessage       `db:"payload" json:"payload"`
+	Status    WebhookDeliveryStatus `db:"status" json:"status"`
+	Attempts  int32                 `db:"attempts" json:"attempts"`
+	// The time at which the delivery is attempted next. While an attempt is in progress this is the time at which another replica may retry it.
+	NextAttemptAt time.Time    `db:"next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt sql.NullTime `db:"last_attempt_at" json:"last_attempt_at"`
+	// The HTTP status code of the last attempt, NULL if no response was received.
+	StatusCode  sql.NullInt32 `db:"status_code" json:"status_code"`
+	Error       string        `db:"error" json:"error"`
+	DeliveredAt sql.NullTime  `db:"delivered_at" json:"delivered_at"`
+}
+
 type Workspace struct {
 	ID                uuid.UUID      `db:"id" json:"id"`
 	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
@@ -1765,6 +2448,10 @@ type Workspace struct {
 	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
 	LockedAt          sql.NullTime   `db:"locked_at" json:"locked_at"`
 	DeletingAt        sql.NullTime   `db:"deleting_at" json:"deleting_at"`
+	// Users the workspace is shared with, mapped to the actions they are allowed to perform.
+	UserACL WorkspaceACL `db:"user_acl" json:"user_acl"`
+	// Groups the workspace is shared with, mapped to the actions their members are allowed to perform.
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
 }
 
 type WorkspaceAgent struct {
@@ -1829,12 +2516,50 @@ type WorkspaceAgentMetadatum struct {
 	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
 }
 
+// Named scripts run by the agent when the workspace starts or stops.
+type WorkspaceAgentScript struct {
+	ID          uuid.UUID                 `db:"id" json:"id"`
+	CreatedAt   time.Time                 `db:"created_at" json:"created_at"`
+	AgentID     uuid.UUID                 `db:"agent_id" json:"agent_id"`
+	Name        string                    `db:"name" json:"name"`
+	DisplayName string                    `db:"display_name" json:"display_name"`
+	Script      string                    `db:"script" json:"script"`
+	RunOn       WorkspaceAgentScriptRunOn `db:"run_on" json:"run_on"`
+	// The number of seconds the script may run before it is killed, or 0 for no timeout.
+	TimeoutSeconds   int32 `db:"timeout_seconds" json:"timeout_seconds"`
+	StartBlocksLogin bool  `db:"start_blocks_login" json:"start_blocks_login"`
+	// Names of scripts that must complete successfully before this script runs.
+	DependsOn []string                   `db:"depends_on" json:"depends_on"`
+	Status    WorkspaceAgentScriptStatus `db:"status" json:"status"`
+	// The exit code of the script, NULL if it has not exited.
+	ExitCode  sql.NullInt32 `db:"exit_code" json:"exit_code"`
+	StartedAt sql.NullTime  `db:"started_at" json:"started_at"`
+	EndedAt   sql.NullTime  `db:"ended_at" json:"ended_at"`
+}
+
+// Recordings of interactive terminal sessions in the asciicast v2 format.
+type WorkspaceAgentSessionRecording struct {
+	ID          uuid.UUID            `db:"id" json:"id"`
+	CreatedAt   time.Time            `db:"created_at" json:"created_at"`
+	WorkspaceID uuid.UUID            `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID            `db:"agent_id" json:"agent_id"`
+	Type        SessionRecordingType `db:"type" json:"type"`
+	StartedAt   time.Time            `db:"started_at" json:"started_at"`
+	EndedAt     time.Time            `db:"ended_at" json:"ended_at"`
+	// Whether the agent stopped recording output because the recording exceeded the maximum size.
+	Truncated bool   `db:"truncated" json:"truncated"`
+	Size      int64  `db:"size" json:"size"`
+	Data      []byte `db:"data" json:"data"`
+}
+
 type WorkspaceAgentStartupLog struct {
 	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	Output    string    `db:"output" json:"output"`
 	ID        int64     `db:"id" json:"id"`
 	Level     LogLevel  `db:"level" json:"level"`
+	// The name of the script that produced the log, empty for the startup script.
+	Source string `db:"source" json:"source"`
 }
 
 type WorkspaceAgentStat struct {
@@ -27,6 +27,16 @@ SELECT * FROM api_keys WHERE last_used > $1;
 -- name: GetAPIKeysByLoginType :many
 SELECT * FROM api_keys WHERE login_type = $1;
 
+-- name: GetAPIKeysByLoginTypeExpiringBetween :many
+SELECT
+	*
+FROM
+	api_keys
+WHERE
+	login_type = @login_type
+	AND expires_at > @start_time :: timestamptz
+	AND expires_at <= @end_time :: timestamptz;
+
 -- name: GetAPIKeysByUserID :many
 SELECT * FROM api_keys WHERE login_type = $1 AND user_id = $2;
 
@@ -44,7 +54,8 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		allow_list
 	)
 VALUES
 	(@id,
@@ -53,7 +64,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name, @allow_list) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -9,6 +9,7 @@ SELECT
     users.status AS user_status,
     users.rbac_roles AS user_roles,
     users.avatar_url AS user_avatar_url,
+    users.is_service_account AS user_is_service_account,
     COUNT(audit_logs.*) OVER () AS count
 FROM
     audit_logs
@@ -98,6 +99,12 @@ WHERE
             workspace_builds.reason::text = @build_reason
         ELSE true
     END
+	-- Filter by connection_type
+	AND CASE
+		WHEN @connection_type :: text != '' THEN
+			additional_fields ->> 'connection_type' = @connection_type :: text
+		ELSE true
+	END
 ORDER BY
     "time" DESC
 LIMIT
@@ -126,3 +133,182 @@ INSERT INTO
     )
 VALUES
 	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING *;
+
+-- GetAuditLogsByTime returns audit logs created before @before in ascending
+-- order of time, for archiving and purging them in batches. Pagination uses
+-- the time and ID of the last audit log of the previous batch.
+-- name: GetAuditLogsByTime :many
+SELECT
+	*
+FROM
+	audit_logs
+WHERE
+	("time", id) > (@after_time :: timestamp with time zone, @after_id :: uuid)
+	AND "time" < @before :: timestamp with time zone
+	-- Filter by resource types, or all of them if empty.
+	AND CASE
+		WHEN cardinality(@resource_types :: resource_type[]) > 0 THEN
+			resource_type = ANY(@resource_types :: resource_type[])
+		ELSE true
+	END
+	AND CASE
+		WHEN cardinality(@exclude_resource_types :: resource_type[]) > 0 THEN
+			NOT resource_type = ANY(@exclude_resource_types :: resource_type[])
+		ELSE true
+	END
+ORDER BY
+	"time" ASC, id ASC
+LIMIT
+	@limit_count :: int;
+
+-- The hashes of deleted audit logs are kept, so the hash chain can still be
+-- verified.
+-- name: DeleteAuditLogsByIDs :exec
+WITH deleted AS (
+	DELETE FROM
+		audit_logs
+	WHERE
+		id = ANY(@ids :: uuid[])
+	RETURNING
+		id
+)
+UPDATE
+	audit_log_hashes
+SET
+	pruned_at = NOW()
+WHERE
+	audit_log_id IN (SELECT id FROM deleted);
+
+-- name: GetAuditLogsByIDs :many
+SELECT
+	*
+FROM
+	audit_logs
+WHERE
+	id = ANY(@ids :: uuid[]);
+
+-- GetUnchainedAuditLogs returns the audit logs created from @after that are not
+-- part of the hash chain, in ascending order of time.
+-- name: GetUnchainedAuditLogs :many
+SELECT
+	*
+FROM
+	audit_logs
+WHERE
+	"time" >= @after :: timestamp with time zone
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			audit_log_hashes
+		WHERE
+			audit_log_hashes.audit_log_id = audit_logs.id
+	)
+ORDER BY
+	"time" ASC, id ASC
+LIMIT
+	@limit_count :: int;
+
+-- name: CountUnchainedAuditLogs :one
+SELECT
+	COUNT(*)
+FROM
+	audit_logs
+WHERE
+	"time" < @before :: timestamp with time zone
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			audit_log_hashes
+		WHERE
+			audit_log_hashes.audit_log_id = audit_logs.id
+	);
+
+-- name: InsertAuditLogHash :one
+INSERT INTO
+	audit_log_hashes (
+		sequence,
+		audit_log_id,
+		prev_hash,
+		hash,
+		created_at,
+		audit_log_time,
+		resource_type,
+		content_hash
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: GetLatestAuditLogHash :one
+SELECT
+	*
+FROM
+	audit_log_hashes
+ORDER BY
+	sequence DESC
+LIMIT
+	1;
+
+-- GetAuditLogHashes returns the hashes of the chain after @after_sequence up to
+-- and including @max_sequence, in order, for verifying the chain in batches.
+-- name: GetAuditLogHashes :many
+SELECT
+	*
+FROM
+	audit_log_hashes
+WHERE
+	sequence > @after_sequence :: bigint
+	AND sequence <= @max_sequence :: bigint
+ORDER BY
+	sequence ASC
+LIMIT
+	@limit_count :: int;
+
+-- GetPrunedAuditLogHashesAfter returns the hashes of pruned audit logs of a
+-- resource type that are newer than @after, up to and including @max_sequence.
+-- The retention policy deletes the oldest audit logs first, so these were
+-- pruned while an older audit log was kept.
+-- name: GetPrunedAuditLogHashesAfter :many
+SELECT
+	*
+FROM
+	audit_log_hashes
+WHERE
+	pruned_at IS NOT NULL
+	AND resource_type = @resource_type :: resource_type
+	AND audit_log_time > @after :: timestamp with time zone
+	AND sequence <= @max_sequence :: bigint
+ORDER BY
+	sequence ASC
+LIMIT
+	@limit_count :: int;
+
+-- name: InsertAuditLogCheckpoint :one
+INSERT INTO
+	audit_log_checkpoints (
+		sequence,
+		hash,
+		signature,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4) RETURNING *;
+
+-- name: GetLatestAuditLogCheckpoint :one
+SELECT
+	*
+FROM
+	audit_log_checkpoints
+ORDER BY
+	sequence DESC
+LIMIT
+	1;
+
+-- name: GetAuditLogCheckpoints :many
+SELECT
+	*
+FROM
+	audit_log_checkpoints
+ORDER BY
+	sequence ASC;
@@ -0,0 +1,57 @@
+-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		created_at,
+		updated_at,
+		name,
+		display_name,
+		organization_id,
+		permissions
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetCustomRoles :many
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	-- Filter by name, the organization of org roles is not part of the name.
+	CASE
+		WHEN cardinality(@names :: text[]) > 0 THEN
+			name = ANY(@names :: text[])
+		ELSE true
+	END
+ORDER BY
+	name ASC;
+
+-- name: GetCustomRoleByID :one
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	id = $1
+LIMIT
+	1;
+
+-- name: UpdateCustomRole :one
+UPDATE
+	custom_roles
+SET
+	updated_at = @updated_at,
+	display_name = @display_name,
+	permissions = @permissions
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteCustomRole :exec
+-- The trigger_delete_custom_role trigger removes the role from the users and
+-- organization members it is assigned to.
+DELETE FROM
+	custom_roles
+WHERE
+	id = $1;
@@ -0,0 +1,87 @@
+-- name: InsertNotificationMessage :exec
+-- Messages with an existing ID are ignored, which allows the same
+-- notification to be enqueued by several replicas.
+INSERT INTO
+	notification_messages (
+		id,
+		user_id,
+		created_at,
+		kind,
+		recipient,
+		subject,
+		body,
+		next_attempt_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: AcquireNotificationMessages :many
+-- Acquires pending messages that are due. Their next attempt is pushed back
+-- until the lease expires, so that other replicas do not attempt them at the
+-- same time.
+UPDATE
+	notification_messages
+SET
+	attempts = attempts + 1,
+	last_attempt_at = @now :: timestamptz,
+	next_attempt_at = @lease_expires_at :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			nested.id
+		FROM
+			notification_messages AS nested
+		WHERE
+			nested.status = 'pending' :: notification_message_status
+			AND nested.next_attempt_at <= @now :: timestamptz
+		ORDER BY
+			nested.next_attempt_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			@limit_opt :: int
+	)
+RETURNING *;
+
+-- name: UpdateNotificationMessageByID :exec
+UPDATE
+	notification_messages
+SET
+	status = @status,
+	next_attempt_at = @next_attempt_at,
+	error = @error,
+	sent_at = @sent_at
+WHERE
+	id = @id;
+
+-- name: DeleteOldNotificationMessages :exec
+-- Delete messages that are no longer pending and are older than 30 days.
+DELETE FROM
+	notification_messages
+WHERE
+	status != 'pending' :: notification_message_status
+	AND created_at < NOW() - INTERVAL '30 days';
+
+-- name: GetUserNotificationPreferences :many
+SELECT
+	*
+FROM
+	user_notification_preferences
+WHERE
+	user_id = $1;
+
+-- name: UpsertUserNotificationPreference :exec
+INSERT INTO
+	user_notification_preferences (
+		user_id,
+		kind,
+		disabled,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (user_id, kind) DO UPDATE
+SET
+	disabled = $3,
+	updated_at = $4;
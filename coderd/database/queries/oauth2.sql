@@ -0,0 +1,158 @@
+-- name: GetOAuth2ProviderApps :many
+SELECT * FROM oauth2_provider_apps ORDER BY (name, id) ASC;
+
+-- name: GetOAuth2ProviderAppByID :one
+SELECT * FROM oauth2_provider_apps WHERE id = $1;
+
+-- name: InsertOAuth2ProviderApp :one
+INSERT INTO oauth2_provider_apps (
+	id,
+	created_at,
+	updated_at,
+	name,
+	icon,
+	callback_url
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE oauth2_provider_apps SET
+	updated_at = $2,
+	name = $3,
+	icon = $4,
+	callback_url = $5
+WHERE id = $1 RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppByID :exec
+DELETE FROM oauth2_provider_apps WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT * FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT * FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY (created_at, id) ASC;
+
+-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO oauth2_provider_app_secrets (
+	id,
+	created_at,
+	hashed_secret,
+	display_secret,
+	app_id
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5
+) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppSecretByID :one
+UPDATE oauth2_provider_app_secrets SET
+	last_used_at = $2
+WHERE id = $1 RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppSecretByID :exec
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT * FROM oauth2_provider_app_codes WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByHashedSecret :one
+SELECT * FROM oauth2_provider_app_codes WHERE hashed_secret = $1;
+
+-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO oauth2_provider_app_codes (
+	id,
+	created_at,
+	expires_at,
+	hashed_secret,
+	user_id,
+	app_id,
+	redirect_uri,
+	scope,
+	code_challenge
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$9
+) RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppCodeByID :one
+DELETE FROM oauth2_provider_app_codes WHERE id = $1 RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppCodesByAppAndUserID :exec
+DELETE FROM oauth2_provider_app_codes WHERE app_id = $1 AND user_id = $2;
+
+-- name: GetOAuth2ProviderAppTokenByHashedRefreshToken :one
+SELECT * FROM oauth2_provider_app_tokens WHERE hashed_refresh_token = $1;
+
+-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO oauth2_provider_app_tokens (
+	id,
+	created_at,
+	expires_at,
+	hashed_refresh_token,
+	app_secret_id,
+	api_key_id
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+) RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppTokenByAPIKeyID :one
+-- The API key of the token is deleted by a trigger.
+DELETE FROM oauth2_provider_app_tokens WHERE api_key_id = $1 RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+-- The API keys of the tokens are deleted by a trigger.
+DELETE FROM
+	oauth2_provider_app_tokens
+USING
+	oauth2_provider_app_secrets, api_keys
+WHERE
+	oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+	AND api_keys.id = oauth2_provider_app_tokens.api_key_id
+	AND oauth2_provider_app_secrets.app_id = @app_id
+	AND api_keys.user_id = @user_id;
+
+-- name: GetOAuth2ProviderAppsByUserID :many
+-- Returns the apps a user has authorized, with the number of tokens they hold
+-- and when one of them was last used.
+SELECT
+	oauth2_provider_apps.id,
+	oauth2_provider_apps.name,
+	oauth2_provider_apps.icon,
+	oauth2_provider_apps.callback_url,
+	COUNT(DISTINCT oauth2_provider_app_tokens.id) AS token_count,
+	MAX(api_keys.last_used)::timestamptz AS last_used
+FROM
+	oauth2_provider_app_tokens
+	INNER JOIN oauth2_provider_app_secrets
+		ON oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+	INNER JOIN oauth2_provider_apps
+		ON oauth2_provider_apps.id = oauth2_provider_app_secrets.app_id
+	INNER JOIN api_keys
+		ON api_keys.id = oauth2_provider_app_tokens.api_key_id
+WHERE
+	api_keys.user_id = $1
+GROUP BY
+	oauth2_provider_apps.id
+ORDER BY
+	oauth2_provider_apps.name ASC;
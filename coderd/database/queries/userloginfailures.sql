@@ -0,0 +1,23 @@
+-- name: InsertUserLoginFailure :exec
+INSERT INTO
+	user_login_failures (user_id, ip_address, created_at)
+VALUES
+	($1, $2, $3);
+
+-- name: GetUserLoginFailureCounts :one
+-- Counts the failed logins of a user since a time, in total and from a single
+-- IP address.
+SELECT
+	COUNT(*) AS failures,
+	COUNT(*) FILTER (WHERE ip_address = @ip_address) AS ip_failures
+FROM
+	user_login_failures
+WHERE
+	user_id = @user_id
+	AND created_at > @since;
+
+-- name: DeleteUserLoginFailures :exec
+DELETE FROM
+	user_login_failures
+WHERE
+	user_id = $1;
@@ -0,0 +1,36 @@
+-- name: GetUserPasswordHistory :many
+SELECT
+	*
+FROM
+	user_password_history
+WHERE
+	user_id = @user_id
+ORDER BY
+	created_at DESC
+LIMIT
+	@limit_count;
+
+-- name: InsertUserPasswordHistory :exec
+INSERT INTO
+	user_password_history (user_id, hashed_password, created_at)
+VALUES
+	($1, $2, $3);
+
+-- name: DeleteOldUserPasswordHistory :exec
+-- Deletes all but the latest passwords of a user.
+DELETE FROM
+	user_password_history
+WHERE
+	user_password_history.user_id = @user_id
+	AND user_password_history.hashed_password NOT IN (
+		SELECT
+			latest.hashed_password
+		FROM
+			user_password_history AS latest
+		WHERE
+			latest.user_id = @user_id
+		ORDER BY
+			latest.created_at DESC
+		LIMIT
+			@keep
+	);
@@ -0,0 +1,88 @@
+-- name: GetUserTOTPByUserID :one
+SELECT
+	*
+FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: UpsertUserTOTP :one
+-- Starts enrollment with a new secret. Enrollment restarts if the secret was
+-- never enabled.
+INSERT INTO
+	user_totp (
+		user_id,
+		secret,
+		enabled,
+		last_used_step,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, false, 0, $3, $3)
+ON CONFLICT (user_id)
+DO UPDATE SET
+	secret = $2,
+	enabled = false,
+	last_used_step = 0,
+	updated_at = $3
+RETURNING *;
+
+-- name: UpdateUserTOTPUsed :one
+-- Records that a code was used. No rows are returned if a code for the same or
+-- a later step was already used, so codes cannot be replayed.
+UPDATE
+	user_totp
+SET
+	enabled = true,
+	last_used_step = @last_used_step,
+	updated_at = @updated_at
+WHERE
+	user_id = @user_id
+	AND last_used_step < @last_used_step
+RETURNING *;
+
+-- name: DeleteUserTOTPByUserID :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: GetUserTOTPRecoveryCodesByUserID :many
+SELECT
+	*
+FROM
+	user_totp_recovery_codes
+WHERE
+	user_id = $1
+ORDER BY
+	created_at ASC;
+
+-- name: InsertUserTOTPRecoveryCodes :exec
+INSERT INTO
+	user_totp_recovery_codes (
+		user_id,
+		hashed_code,
+		created_at
+	)
+SELECT
+	@user_id :: uuid,
+	unnest(@hashed_codes :: bytea[]),
+	@created_at :: timestamptz;
+
+-- name: UseUserTOTPRecoveryCode :one
+UPDATE
+	user_totp_recovery_codes
+SET
+	used_at = @used_at
+WHERE
+	user_id = @user_id
+	AND hashed_code = @hashed_code
+	AND used_at IS NULL
+RETURNING *;
+
+-- name: DeleteUserTOTPRecoveryCodesByUserID :exec
+DELETE FROM
+	user_totp_recovery_codes
+WHERE
+	user_id = $1;
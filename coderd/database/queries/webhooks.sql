@@ -0,0 +1,141 @@
+-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (
+		id,
+		created_at,
+		updated_at,
+		name,
+		url,
+		secret,
+		events,
+		enabled
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: GetWebhooks :many
+SELECT
+	*
+FROM
+	webhooks
+ORDER BY
+	name ASC;
+
+-- name: GetWebhookByID :one
+SELECT
+	*
+FROM
+	webhooks
+WHERE
+	id = $1
+LIMIT
+	1;
+
+-- name: GetEnabledWebhooksByEvent :many
+SELECT
+	*
+FROM
+	webhooks
+WHERE
+	enabled
+	AND @event :: webhook_event = ANY(events);
+
+-- name: UpdateWebhookByID :one
+UPDATE
+	webhooks
+SET
+	updated_at = @updated_at,
+	name = @name,
+	url = @url,
+	secret = @secret,
+	events = @events,
+	enabled = @enabled
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteWebhookByID :exec
+DELETE FROM
+	webhooks
+WHERE
+	id = $1;
+
+-- name: InsertWebhookDelivery :exec
+-- Deliveries with an existing ID are ignored, which allows the same event to
+-- be enqueued by several replicas.
+INSERT INTO
+	webhook_deliveries (
+		id,
+		webhook_id,
+		created_at,
+		event,
+		payload,
+		next_attempt_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = @webhook_id
+ORDER BY
+	created_at DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: AcquireWebhookDeliveries :many
+-- Acquires pending deliveries that are due. Their next attempt is pushed back
+-- until the lease expires, so that other replicas do not attempt them at the
+-- same time.
+UPDATE
+	webhook_deliveries
+SET
+	attempts = attempts + 1,
+	last_attempt_at = @now :: timestamptz,
+	next_attempt_at = @lease_expires_at :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			nested.id
+		FROM
+			webhook_deliveries AS nested
+		INNER JOIN
+			webhooks ON webhooks.id = nested.webhook_id
+		WHERE
+			nested.status = 'pending' :: webhook_delivery_status
+			AND nested.next_attempt_at <= @now :: timestamptz
+			AND webhooks.enabled
+		ORDER BY
+			nested.next_attempt_at
+		FOR UPDATE OF nested
+		SKIP LOCKED
+		LIMIT
+			@limit_opt :: int
+	)
+RETURNING *;
+
+-- name: UpdateWebhookDeliveryByID :exec
+UPDATE
+	webhook_deliveries
+SET
+	status = @status,
+	next_attempt_at = @next_attempt_at,
+	status_code = @status_code,
+	error = @error,
+	delivered_at = @delivered_at
+WHERE
+	id = @id;
+
+-- name: DeleteOldWebhookDeliveries :exec
+-- Delete deliveries that are no longer pending and are older than 30 days.
+DELETE FROM
+	webhook_deliveries
+WHERE
+	status != 'pending' :: webhook_delivery_status
+	AND created_at < NOW() - INTERVAL '30 days';
@@ -165,12 +165,13 @@ WITH new_length AS (
 	startup_logs_length = startup_logs_length + @output_length WHERE workspace_agents.id = @agent_id
 )
 INSERT INTO
-		workspace_agent_startup_logs (agent_id, created_at, output, level)
+		workspace_agent_startup_logs (agent_id, created_at, output, level, source)
 	SELECT
 		@agent_id :: uuid AS agent_id,
 		unnest(@created_at :: timestamptz [ ]) AS created_at,
 		unnest(@output :: VARCHAR(1024) [ ]) AS output,
-		unnest(@level :: log_level [ ]) AS level
+		unnest(@level :: log_level [ ]) AS level,
+		unnest(@source :: VARCHAR(64) [ ]) AS source
 	RETURNING workspace_agent_startup_logs.*;
 
 -- If an agent hasn't connected in the last 7 days, we purge it's logs.
@@ -199,3 +200,23 @@ WHERE
     	WHERE
 			wb.workspace_id = @workspace_id :: uuid
 	);
+
+-- name: UpdateWorkspaceAgentAuthTokensByWorkspaceID :exec
+-- Issues new auth tokens to the agents of all builds of the workspace, so
+-- that previously issued tokens can no longer be used.
+UPDATE
+	workspace_agents
+SET
+	auth_token = gen_random_uuid(),
+	updated_at = @updated_at
+WHERE
+	resource_id IN (
+		SELECT
+			workspace_resources.id
+		FROM
+			workspace_resources
+		JOIN
+			workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+		WHERE
+			workspace_builds.workspace_id = @workspace_id :: uuid
+	);
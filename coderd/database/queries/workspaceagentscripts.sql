@@ -0,0 +1,39 @@
+-- name: InsertWorkspaceAgentScript :one
+INSERT INTO
+	workspace_agent_scripts (
+		id,
+		created_at,
+		agent_id,
+		name,
+		display_name,
+		script,
+		run_on,
+		timeout_seconds,
+		start_blocks_login,
+		depends_on
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+
+-- name: GetWorkspaceAgentScriptsByAgentIDs :many
+SELECT
+	*
+FROM
+	workspace_agent_scripts
+WHERE
+	agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	name ASC;
+
+-- name: UpdateWorkspaceAgentScriptStatusByID :one
+UPDATE
+	workspace_agent_scripts
+SET
+	status = @status,
+	exit_code = @exit_code,
+	started_at = @started_at,
+	ended_at = @ended_at
+WHERE
+	id = @id
+	AND agent_id = @agent_id
+RETURNING *;
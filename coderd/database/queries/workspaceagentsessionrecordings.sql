@@ -0,0 +1,45 @@
+-- name: InsertWorkspaceAgentSessionRecording :one
+INSERT INTO
+	workspace_agent_session_recordings (
+		id,
+		created_at,
+		workspace_id,
+		agent_id,
+		type,
+		started_at,
+		ended_at,
+		truncated,
+		size,
+		data
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+
+-- name: GetWorkspaceAgentSessionRecordingByID :one
+SELECT
+	*
+FROM
+	workspace_agent_session_recordings
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceAgentSessionRecordingsByAgentID :many
+-- Recordings are listed without their data, which can be large. Use
+-- GetWorkspaceAgentSessionRecordingByID to fetch the data of a single
+-- recording.
+SELECT
+	id,
+	created_at,
+	workspace_id,
+	agent_id,
+	type,
+	started_at,
+	ended_at,
+	truncated,
+	size
+FROM
+	workspace_agent_session_recordings
+WHERE
+	agent_id = $1
+ORDER BY
+	started_at DESC;
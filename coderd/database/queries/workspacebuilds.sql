@@ -105,6 +105,33 @@ JOIN
 	 workspace_build_with_user AS wb
 ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number;
 
+-- name: GetLatestWorkspaceBuildsWithDeadlineBetween :many
+-- Returns the latest builds of running workspaces that will be stopped
+-- automatically at a deadline within the given range.
+SELECT
+	workspace_builds.*
+FROM
+	workspace_build_with_user AS workspace_builds
+INNER JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+INNER JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+WHERE
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS latest
+		WHERE
+			latest.workspace_id = workspace_builds.workspace_id
+	)
+	AND workspaces.deleted = false
+	AND workspace_builds.transition = 'start' :: workspace_transition
+	AND provisioner_jobs.completed_at IS NOT NULL
+	AND COALESCE(provisioner_jobs.error, '') = ''
+	AND workspace_builds.deadline > @start_time :: timestamptz
+	AND workspace_builds.deadline <= @end_time :: timestamptz;
+
 -- name: InsertWorkspaceBuild :exec
 INSERT INTO
 	workspace_builds (
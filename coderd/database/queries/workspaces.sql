@@ -288,6 +288,16 @@ WHERE
 	AND LOWER("name") = LOWER(@name)
 ORDER BY created_at DESC;
 
+-- name: GetWorkspacesByOwnerID :many
+SELECT
+	*
+FROM
+	workspaces
+WHERE
+	owner_id = @owner_id
+	AND deleted = false
+ORDER BY created_at ASC;
+
 -- name: InsertWorkspace :one
 INSERT INTO
 	workspaces (
@@ -323,6 +333,47 @@ WHERE
 	AND deleted = false
 RETURNING *;
 
+-- name: UpdateWorkspaceOwnerByID :one
+-- The workspace is no longer shared once it changes hands.
+UPDATE
+	workspaces
+SET
+	owner_id = $2,
+	updated_at = $3,
+	user_acl = '{}'::jsonb,
+	group_acl = '{}'::jsonb
+WHERE
+	id = $1
+	AND deleted = false
+RETURNING *;
+
+-- name: ClaimPrebuiltWorkspace :one
+-- Transfers a prebuilt workspace to the user claiming it. The owner is
+-- compared so that a workspace can only be claimed once.
+UPDATE
+	workspaces
+SET
+	owner_id = @new_owner_id,
+	name = @name,
+	autostart_schedule = @autostart_schedule,
+	ttl = @ttl,
+	updated_at = @now,
+	last_used_at = @now
+WHERE
+	id = @id
+	AND owner_id = @prebuilds_owner_id
+	AND deleted = false
+RETURNING *;
+
+-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	group_acl = @group_acl,
+	user_acl = @user_acl
+WHERE
+	id = @id;
+
 -- name: UpdateWorkspaceAutostart :exec
 UPDATE
 	workspaces
@@ -474,6 +525,18 @@ WHERE
 		)
 	) AND workspaces.deleted = 'false';
 
+-- name: GetWorkspacesLockedSince :many
+-- Returns workspaces that were locked since the given time and are scheduled
+-- to be deleted.
+SELECT
+	*
+FROM
+	workspaces
+WHERE
+	deleted = false
+	AND locked_at >= @locked_since :: timestamptz
+	AND deleting_at IS NOT NULL;
+
 -- name: UpdateWorkspaceLockedDeletingAt :exec
 UPDATE
 	workspaces
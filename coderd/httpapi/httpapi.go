@@ -67,6 +67,12 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	// OAuth2 app names are shown to users on the consent screen, so they
+	// follow the same rules as template display names.
+	err = Validate.RegisterValidation("oauth2_app_name", templateDisplayNameValidator)
+	if err != nil {
+		panic(err)
+	}
 
 	templateVersionNameValidator := func(fl validator.FieldLevel) bool {
 		f := fl.Field().Interface()
@@ -22,6 +22,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -184,6 +185,12 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		tokenFunc = cfg.SessionTokenFunc
 	}
 	token := tokenFunc(r)
+	if token == "" && cfg.SessionTokenFunc == nil {
+		// OAuth2 apps send their access tokens as bearer tokens. This is not
+		// part of APITokenFromRequest because workspace apps may use the
+		// Authorization header for their own purposes.
+		token = bearerTokenFromRequest(r)
+	}
 	if token == "" {
 		return optionalWrite(http.StatusUnauthorized, codersdk.Response{
 			Message: SignedOutErrorMessage,
@@ -399,14 +406,48 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	// Keys with an allow list may only read the organizations their owner is
+	// a member of.
+	var organizationIDs []uuid.UUID
+	if len(key.AllowList) > 0 {
+		// nolint:gocritic
+		rows, err := cfg.DB.GetOrganizationIDsByMemberIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{key.UserID})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return write(http.StatusInternalServerError, codersdk.Response{
+				Message: internalErrorMessage,
+				Detail:  fmt.Sprintf("Internal error fetching user's organizations. %s", err.Error()),
+			})
+		}
+		for _, row := range rows {
+			organizationIDs = append(organizationIDs, row.OrganizationIDs...)
+		}
+	}
+
+	scope, err := key.RBACScope(organizationIDs)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error expanding API key scope. %s", err.Error()),
+		})
+	}
+
+	// nolint:gocritic // Custom roles are expanded for the user.
+	actorRoles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), cfg.DB, roles.Roles)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error expanding user's roles. %s", err.Error()),
+		})
+	}
+
 	// Actor is the user's authorization context.
 	authz := Authorization{
 		ActorName: roles.Username,
 		Actor: rbac.Subject{
 			ID:     key.UserID.String(),
-			Roles:  rbac.RoleNames(roles.Roles),
+			Roles:  actorRoles,
 			Groups: roles.Groups,
-			Scope:  rbac.ScopeName(key.Scope),
+			Scope:  scope,
 		}.WithCachedASTValue(),
 	}
 
@@ -444,6 +485,16 @@ func APITokenFromRequest(r *http.Request) string {
 	return ""
 }
 
+// bearerTokenFromRequest returns the token of an "Authorization: Bearer"
+// header, or an empty string if there is none.
+func bearerTokenFromRequest(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
 // SplitAPIToken verifies the format of an API key and returns the split ID and
 // secret.
 //
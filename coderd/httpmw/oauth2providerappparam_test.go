@@ -0,0 +1,100 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/httpmw"
+)
+
+func TestOAuth2ProviderAppParam(t *testing.T) {
+	t.Parallel()
+
+	setup := func(db database.Store, appID, secretID string) (*chi.Mux, *http.Request) {
+		r := httptest.NewRequest("GET", "/", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("app", appID)
+		rctx.URLParams.Add("secretID", secretID)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		router := chi.NewRouter()
+		router.Use(
+			httpmw.ExtractOAuth2ProviderAppParam(db),
+			httpmw.ExtractOAuth2ProviderAppSecretParam(db),
+		)
+		return router, r
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			db     = dbfake.New()
+			app    = dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+			secret = dbgen.OAuth2ProviderAppSecret(t, db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+			w      = httptest.NewRecorder()
+		)
+
+		router, r := setup(db, app.ID.String(), secret.ID.String())
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, app, httpmw.OAuth2ProviderAppParam(r))
+			require.Equal(t, secret, httpmw.OAuth2ProviderAppSecretParam(r))
+			w.WriteHeader(http.StatusOK)
+		})
+		router.ServeHTTP(w, r)
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("AppNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			db = dbfake.New()
+			w  = httptest.NewRecorder()
+		)
+
+		router, r := setup(db, uuid.NewString(), uuid.NewString())
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		router.ServeHTTP(w, r)
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("SecretOfOtherApp", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			db     = dbfake.New()
+			app    = dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+			other  = dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+			secret = dbgen.OAuth2ProviderAppSecret(t, db, database.OAuth2ProviderAppSecret{AppID: other.ID})
+			w      = httptest.NewRecorder()
+		)
+
+		router, r := setup(db, app.ID.String(), secret.ID.String())
+		router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		router.ServeHTTP(w, r)
+
+		res := w.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
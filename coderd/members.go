@@ -9,7 +9,9 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/database/db2sdk"
+	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
@@ -81,10 +83,12 @@ func (api *API) updateOrganizationMemberRoles(ctx context.Context, args database
 		if roleOrg != args.OrgID {
 			return database.OrganizationMember{}, xerrors.Errorf("Must only pass roles for org %q", args.OrgID.String())
 		}
+	}
 
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.OrganizationMember{}, xerrors.Errorf("%q is not a supported role", r)
-		}
+	// All roles should be valid roles, custom roles must exist.
+	//nolint:gocritic // Custom roles are looked up regardless of who assigns them.
+	if _, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), api.Database, args.GrantedRoles); err != nil {
+		return database.OrganizationMember{}, err
 	}
 
 	updatedUser, err := api.Database.UpdateMemberRoles(ctx, args)
@@ -104,8 +108,7 @@ func convertOrganizationMember(mem database.OrganizationMember) codersdk.Organiz
 	}
 
 	for _, roleName := range mem.Roles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedMember.Roles = append(convertedMember.Roles, db2sdk.Role(rbacRole))
+		convertedMember.Roles = append(convertedMember.Roles, db2sdk.RoleByName(roleName))
 	}
 	return convertedMember
 }
@@ -0,0 +1,519 @@
+// Package notification sends notifications to users by email. Messages are
+// stored in a queue in the database and retried with exponential backoff
+// until they are sent.
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+)
+
+const (
+	// DefaultInterval is how often due messages and upcoming events are
+	// checked when no interval is provided.
+	DefaultInterval = time.Minute
+	// DefaultMaxAttempts is the number of attempts after which a message is
+	// marked as failed when no maximum is provided.
+	DefaultMaxAttempts = 5
+	// DefaultRetryBackoff is the delay before the first retry of a message
+	// when no backoff is provided. It doubles with every attempt.
+	DefaultRetryBackoff = time.Minute
+	// DefaultAutostopWindow is how long before the deadline of a workspace
+	// its owner is notified when no window is provided.
+	DefaultAutostopWindow = 30 * time.Minute
+	// DefaultTokenExpiryWindow is how long before a token expires its owner
+	// is notified when no window is provided.
+	DefaultTokenExpiryWindow = 3 * 24 * time.Hour
+	// DefaultTimeout is the timeout of a single attempt when no timeout is
+	// provided.
+	DefaultTimeout = 30 * time.Second
+
+	// lockedWindow is how long after a workspace is locked its owner is
+	// notified. It bounds the query, so that owners are not notified about
+	// workspaces that were locked before notifications were enabled.
+	lockedWindow = 24 * time.Hour
+	// maxRetryBackoff caps the exponential backoff between attempts.
+	maxRetryBackoff = time.Hour
+	// messagesPerRun is the maximum number of messages attempted at once.
+	messagesPerRun = 10
+)
+
+// Enqueuer enqueues notifications for events that happen in coderd. Errors
+// are logged rather than returned, as notifications must never fail the
+// operation that triggered them.
+type Enqueuer interface {
+	// WorkspaceBuildFailed notifies the owner of the workspace that the
+	// build failed.
+	WorkspaceBuildFailed(ctx context.Context, buildID uuid.UUID, jobError string)
+}
+
+// NewNop returns an Enqueuer that does nothing.
+func NewNop() Enqueuer {
+	return nop{}
+}
+
+type nop struct{}
+
+func (nop) WorkspaceBuildFailed(context.Context, uuid.UUID, string) {}
+
+type Options struct {
+	Database database.Store
+	Logger   slog.Logger
+	// Sender delivers the messages. Notifications are disabled if it is
+	// nil.
+	Sender    Sender
+	AccessURL *url.URL
+
+	// Interval is how often due messages and upcoming events are checked.
+	// Messages for events that happen in coderd are sent immediately.
+	Interval time.Duration
+	// MaxAttempts is the number of attempts after which a message is marked
+	// as failed.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry of a message. It
+	// doubles with every attempt, up to an hour.
+	RetryBackoff time.Duration
+	// AutostopWindow is how long before the deadline of a workspace its
+	// owner is notified.
+	AutostopWindow time.Duration
+	// TokenExpiryWindow is how long before a token expires its owner is
+	// notified.
+	TokenExpiryWindow time.Duration
+	// Timeout is the timeout of a single attempt.
+	Timeout time.Duration
+}
+
+// Notifier renders notifications into a queue in the database and sends
+// them to users. Messages are acquired with a lease, so several replicas
+// can run a notifier concurrently.
+type Notifier struct {
+	opts   Options
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	notify chan struct{}
+
+	// enqueued holds the IDs of messages for upcoming events that were
+	// already enqueued, with the time after which they can be forgotten.
+	enqueuedMu sync.Mutex
+	enqueued   map[uuid.UUID]time.Time
+}
+
+var _ Enqueuer = (*Notifier)(nil)
+
+// New starts a notifier. Close must be called to stop it.
+func New(ctx context.Context, opts Options) *Notifier {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = DefaultRetryBackoff
+	}
+	if opts.AutostopWindow == 0 {
+		opts.AutostopWindow = DefaultAutostopWindow
+	}
+	if opts.TokenExpiryWindow == 0 {
+		opts.TokenExpiryWindow = DefaultTokenExpiryWindow
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	//nolint:gocritic // The notifier reads the objects of all users.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	n := &Notifier{
+		opts:     opts,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		notify:   make(chan struct{}, 1),
+		enqueued: map[uuid.UUID]time.Time{},
+	}
+	if opts.Sender == nil {
+		close(n.done)
+		return n
+	}
+	go n.loop()
+	return n
+}
+
+// Close stops the notifier. Messages that are in flight are retried after
+// their lease expires.
+func (n *Notifier) Close() error {
+	n.cancel()
+	<-n.done
+	return nil
+}
+
+func (n *Notifier) WorkspaceBuildFailed(ctx context.Context, buildID uuid.UUID, jobError string) {
+	if n.opts.Sender == nil {
+		return
+	}
+	//nolint:gocritic // The notifier reads the workspace of any user.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	err := n.enqueueWorkspaceBuildFailed(ctx, buildID, jobError)
+	if err != nil {
+		n.opts.Logger.Error(ctx, "enqueue workspace build failed notification",
+			slog.F("workspace_build_id", buildID),
+			slog.Error(err),
+		)
+		return
+	}
+	n.wake()
+}
+
+func (n *Notifier) enqueueWorkspaceBuildFailed(ctx context.Context, buildID uuid.UUID, jobError string) error {
+	build, err := n.opts.Database.GetWorkspaceBuildByID(ctx, buildID)
+	if err != nil {
+		return xerrors.Errorf("get workspace build: %w", err)
+	}
+	workspace, err := n.opts.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	return n.enqueue(ctx, workspace.OwnerID, messageID(workspace.OwnerID, database.NotificationKindWorkspaceBuildFailed, build.ID.String()), func(owner database.User) templateData {
+		return templateData{
+			Kind:          database.NotificationKindWorkspaceBuildFailed,
+			WorkspaceName: workspace.Name,
+			WorkspaceURL:  n.workspaceURL(owner, workspace),
+			BuildNumber:   build.BuildNumber,
+			Transition:    build.Transition,
+			BuildError:    jobError,
+		}
+	})
+}
+
+// enqueue renders a message for the user and stores it in the queue,
+// unless the user is inactive or opted out of the kind of notification.
+// Messages with an existing ID are ignored.
+func (n *Notifier) enqueue(ctx context.Context, userID uuid.UUID, id uuid.UUID, data func(user database.User) templateData) error {
+	user, err := n.opts.Database.GetUserByID(ctx, userID)
+	if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+	if user.Deleted || user.Status != database.UserStatusActive || user.Email == "" {
+		return nil
+	}
+
+	td := data(user)
+	preferences, err := n.opts.Database.GetUserNotificationPreferences(ctx, user.ID)
+	if err != nil {
+		return xerrors.Errorf("get notification preferences: %w", err)
+	}
+	for _, preference := range preferences {
+		if preference.Kind == td.Kind && preference.Disabled {
+			return nil
+		}
+	}
+
+	td.AccessURL = n.opts.AccessURL.String()
+	td.Username = user.Username
+	subject, body, err := render(td)
+	if err != nil {
+		return xerrors.Errorf("render %s notification: %w", td.Kind, err)
+	}
+	now := database.Now()
+	err = n.opts.Database.InsertNotificationMessage(ctx, database.InsertNotificationMessageParams{
+		ID:            id,
+		UserID:        user.ID,
+		CreatedAt:     now,
+		Kind:          td.Kind,
+		Recipient:     user.Email,
+		Subject:       subject,
+		Body:          body,
+		NextAttemptAt: now,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert message: %w", err)
+	}
+	return nil
+}
+
+// messageID derives the ID of a message from the event it is about, so that
+// the same event enqueued by several replicas is only sent once.
+func messageID(userID uuid.UUID, kind database.NotificationKind, event string) uuid.UUID {
+	return uuid.NewSHA1(userID, []byte(fmt.Sprintf("%s:%s", kind, event)))
+}
+
+func (n *Notifier) workspaceURL(owner database.User, workspace database.Workspace) string {
+	return n.opts.AccessURL.JoinPath(fmt.Sprintf("@%s", owner.Username), workspace.Name).String()
+}
+
+func (n *Notifier) wake() {
+	select {
+	case n.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (n *Notifier) loop() {
+	defer close(n.done)
+
+	ticker := time.NewTicker(n.opts.Interval)
+	defer ticker.Stop()
+	for {
+		n.enqueueUpcoming(n.ctx)
+		if n.send(n.ctx) {
+			// A full batch was sent, so there may be more.
+			n.wake()
+		}
+
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-n.notify:
+		}
+	}
+}
+
+// upcoming is a notification about an event that is detected by polling.
+type upcoming struct {
+	userID uuid.UUID
+	id     uuid.UUID
+	// forgetAt is the time after which the event can no longer be detected,
+	// so its ID no longer has to be remembered.
+	forgetAt time.Time
+	data     func(user database.User) templateData
+}
+
+// enqueueUpcoming enqueues notifications for imminent autostops, locked
+// workspaces and expiring tokens.
+func (n *Notifier) enqueueUpcoming(ctx context.Context) {
+	now := database.Now()
+	var events []upcoming
+	for _, list := range []func(context.Context, time.Time) ([]upcoming, error){
+		n.autostops,
+		n.lockedWorkspaces,
+		n.expiringTokens,
+	} {
+		listed, err := list(ctx, now)
+		if err != nil {
+			if ctx.Err() == nil {
+				n.opts.Logger.Error(ctx, "list upcoming notifications", slog.Error(err))
+			}
+			continue
+		}
+		events = append(events, listed...)
+	}
+
+	n.enqueuedMu.Lock()
+	defer n.enqueuedMu.Unlock()
+	for id, forgetAt := range n.enqueued {
+		if forgetAt.Before(now) {
+			delete(n.enqueued, id)
+		}
+	}
+	for _, event := range events {
+		if _, ok := n.enqueued[event.id]; ok {
+			continue
+		}
+		err := n.enqueue(ctx, event.userID, event.id, event.data)
+		if err != nil {
+			if ctx.Err() == nil {
+				n.opts.Logger.Error(ctx, "enqueue notification",
+					slog.F("user_id", event.userID),
+					slog.Error(err),
+				)
+			}
+			continue
+		}
+		n.enqueued[event.id] = event.forgetAt
+	}
+}
+
+func (n *Notifier) autostops(ctx context.Context, now time.Time) ([]upcoming, error) {
+	builds, err := n.opts.Database.GetLatestWorkspaceBuildsWithDeadlineBetween(ctx, database.GetLatestWorkspaceBuildsWithDeadlineBetweenParams{
+		StartTime: now,
+		EndTime:   now.Add(n.opts.AutostopWindow),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace builds with imminent autostop: %w", err)
+	}
+	events := make([]upcoming, 0, len(builds))
+	for _, build := range builds {
+		build := build
+		workspace, err := n.opts.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+		if err != nil {
+			// Skip the workspace so one failure doesn't hold back the
+			// notifications for every other workspace.
+			n.opts.Logger.Error(ctx, "get workspace for autostop notification",
+				slog.F("workspace_id", build.WorkspaceID),
+				slog.Error(err),
+			)
+			continue
+		}
+		// The ID includes the deadline, so the owner is notified again if
+		// the deadline is extended.
+		event := fmt.Sprintf("%s:%d", build.ID, build.Deadline.UnixNano())
+		events = append(events, upcoming{
+			userID:   workspace.OwnerID,
+			id:       messageID(workspace.OwnerID, database.NotificationKindWorkspaceAutostop, event),
+			forgetAt: build.Deadline,
+			data: func(owner database.User) templateData {
+				return templateData{
+					Kind:          database.NotificationKindWorkspaceAutostop,
+					WorkspaceName: workspace.Name,
+					WorkspaceURL:  n.workspaceURL(owner, workspace),
+					Deadline:      build.Deadline,
+				}
+			},
+		})
+	}
+	return events, nil
+}
+
+func (n *Notifier) lockedWorkspaces(ctx context.Context, now time.Time) ([]upcoming, error) {
+	workspaces, err := n.opts.Database.GetWorkspacesLockedSince(ctx, now.Add(-lockedWindow))
+	if err != nil {
+		return nil, xerrors.Errorf("get locked workspaces: %w", err)
+	}
+	events := make([]upcoming, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		workspace := workspace
+		event := fmt.Sprintf("%s:%d", workspace.ID, workspace.LockedAt.Time.UnixNano())
+		events = append(events, upcoming{
+			userID:   workspace.OwnerID,
+			id:       messageID(workspace.OwnerID, database.NotificationKindWorkspaceLocked, event),
+			forgetAt: workspace.LockedAt.Time.Add(lockedWindow),
+			data: func(owner database.User) templateData {
+				return templateData{
+					Kind:          database.NotificationKindWorkspaceLocked,
+					WorkspaceName: workspace.Name,
+					WorkspaceURL:  n.workspaceURL(owner, workspace),
+					DeletingAt:    workspace.DeletingAt.Time,
+				}
+			},
+		})
+	}
+	return events, nil
+}
+
+func (n *Notifier) expiringTokens(ctx context.Context, now time.Time) ([]upcoming, error) {
+	keys, err := n.opts.Database.GetAPIKeysByLoginTypeExpiringBetween(ctx, database.GetAPIKeysByLoginTypeExpiringBetweenParams{
+		LoginType: database.LoginTypeToken,
+		StartTime: now,
+		EndTime:   now.Add(n.opts.TokenExpiryWindow),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get expiring tokens: %w", err)
+	}
+	events := make([]upcoming, 0, len(keys))
+	for _, key := range keys {
+		key := key
+		event := fmt.Sprintf("%s:%d", key.ID, key.ExpiresAt.UnixNano())
+		events = append(events, upcoming{
+			userID:   key.UserID,
+			id:       messageID(key.UserID, database.NotificationKindTokenExpiring, event),
+			forgetAt: key.ExpiresAt,
+			data: func(database.User) templateData {
+				return templateData{
+					Kind:      database.NotificationKindTokenExpiring,
+					TokenName: key.TokenName,
+					ExpiresAt: key.ExpiresAt,
+				}
+			},
+		})
+	}
+	return events, nil
+}
+
+// send attempts the messages that are due. It returns true if the number
+// of messages reached the limit of a single run.
+func (n *Notifier) send(ctx context.Context) bool {
+	now := database.Now()
+	messages, err := n.opts.Database.AcquireNotificationMessages(ctx, database.AcquireNotificationMessagesParams{
+		Now: now,
+		// Other replicas skip the messages until the attempts would have
+		// timed out.
+		LeaseExpiresAt: now.Add(2 * n.opts.Timeout),
+		LimitOpt:       messagesPerRun,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			n.opts.Logger.Error(ctx, "acquire notification messages", slog.Error(err))
+		}
+		return false
+	}
+
+	// We only use errgroup here for convenience of API, not for early
+	// cancellation. Failures are stored with the message.
+	var eg errgroup.Group
+	for _, message := range messages {
+		message := message
+		eg.Go(func() error {
+			n.attempt(ctx, message)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return len(messages) == messagesPerRun
+}
+
+// attempt sends a message and stores the result.
+func (n *Notifier) attempt(ctx context.Context, message database.NotificationMessage) {
+	logger := n.opts.Logger.With(
+		slog.F("message_id", message.ID),
+		slog.F("user_id", message.UserID),
+		slog.F("kind", message.Kind),
+		slog.F("attempt", message.Attempts),
+	)
+
+	sendCtx, cancel := context.WithTimeout(ctx, n.opts.Timeout)
+	err := n.opts.Sender.Send(sendCtx, message.Recipient, message.Subject, message.Body)
+	cancel()
+	if ctx.Err() != nil {
+		// The message is retried by the next notifier that acquires it.
+		return
+	}
+
+	now := database.Now()
+	params := database.UpdateNotificationMessageByIDParams{
+		ID:            message.ID,
+		Status:        database.NotificationMessageStatusSent,
+		NextAttemptAt: now,
+		SentAt:        sql.NullTime{Time: now, Valid: true},
+	}
+	if err != nil {
+		logger.Warn(ctx, "notification attempt failed", slog.Error(err))
+		params.Error = err.Error()
+		params.SentAt = sql.NullTime{}
+		if isPermanent(err) || int(message.Attempts) >= n.opts.MaxAttempts {
+			params.Status = database.NotificationMessageStatusFailed
+		} else {
+			params.Status = database.NotificationMessageStatusPending
+			params.NextAttemptAt = now.Add(n.backoff(message.Attempts))
+		}
+	}
+	err = n.opts.Database.UpdateNotificationMessageByID(ctx, params)
+	if err != nil && ctx.Err() == nil {
+		logger.Error(ctx, "update notification message", slog.Error(err))
+	}
+}
+
+// backoff returns the delay before the attempt after the given one.
+func (n *Notifier) backoff(attempts int32) time.Duration {
+	backoff := n.opts.RetryBackoff
+	for i := int32(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,515 @@
+package notification_test
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/notification"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type receivedEmail struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// smtpServer starts a local SMTP server that records emails. The status
+// function returns the reply code to the end of the data of each attempt.
+func smtpServer(t *testing.T, status func(attempt int64) int) (string, <-chan receivedEmail, *atomic.Int64) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	received := make(chan receivedEmail, 32)
+	var attempts atomic.Int64
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serveSMTP(t, textproto.NewConn(conn), func() int {
+					return status(attempts.Add(1))
+				}, received)
+			}()
+		}
+	}()
+	return listener.Addr().String(), received, &attempts
+}
+
+func serveSMTP(t *testing.T, conn *textproto.Conn, status func() int, received chan<- receivedEmail) {
+	var from, to string
+	_ = conn.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = conn.PrintfLine("250 localhost")
+		case "MAIL":
+			from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			to = strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := conn.ReadDotBytes()
+			if !assert.NoError(t, err) {
+				return
+			}
+			code := status()
+			if code != 250 {
+				_ = conn.PrintfLine("%d Try again later", code)
+				continue
+			}
+			msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(data))))
+			if !assert.NoError(t, err) {
+				return
+			}
+			body, err := io.ReadAll(msg.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, from, msg.Header.Get("From"))
+			assert.Equal(t, to, msg.Header.Get("To"))
+			received <- receivedEmail{
+				From:    from,
+				To:      to,
+				Subject: subject,
+				Body:    string(body),
+			}
+			_ = conn.PrintfLine("250 OK")
+		case "RSET", "NOOP":
+			_ = conn.PrintfLine("250 OK")
+		case "QUIT":
+			_ = conn.PrintfLine("221 Bye")
+			return
+		default:
+			_ = conn.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func alwaysOK(int64) int {
+	return 250
+}
+
+func sender(smarthost string) notification.Sender {
+	var cfg codersdk.NotificationsEmailConfig
+	_ = cfg.From.Set("coder@example.com")
+	_ = cfg.Smarthost.Set(smarthost)
+	return notification.NewSMTPSender(cfg)
+}
+
+var accessURL = &url.URL{Scheme: "https", Host: "coder.example.com"}
+
+func waitForEmail(ctx context.Context, t *testing.T, received <-chan receivedEmail) receivedEmail {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for email")
+		return receivedEmail{}
+	case email := <-received:
+		return email
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WorkspaceBuildFailed", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		smarthost, received, _ := smtpServer(t, alwaysOK)
+
+		notifier := notification.New(ctx, notification.Options{
+			Database:  db,
+			Logger:    slogtest.Make(t, nil),
+			Sender:    sender(smarthost),
+			AccessURL: accessURL,
+		})
+		defer notifier.Close()
+
+		user := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			BuildNumber: 3,
+			Transition:  database.WorkspaceTransitionStart,
+		})
+		notifier.WorkspaceBuildFailed(ctx, build.ID, "terraform apply failed")
+
+		email := waitForEmail(ctx, t, received)
+		require.Equal(t, "coder@example.com", email.From)
+		require.Equal(t, user.Email, email.To)
+		require.Equal(t, fmt.Sprintf("Workspace %s failed to start", workspace.Name), email.Subject)
+		require.Contains(t, email.Body, fmt.Sprintf("Hi %s,", user.Username))
+		require.Contains(t, email.Body, "Build #3")
+		require.Contains(t, email.Body, "Error: terraform apply failed")
+		require.Contains(t, email.Body, fmt.Sprintf("https://coder.example.com/@%s/%s", user.Username, workspace.Name))
+		require.Contains(t, email.Body, "To stop receiving failed workspace build emails")
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		smarthost, received, attempts := smtpServer(t, func(attempt int64) int {
+			if attempt < 3 {
+				return 451
+			}
+			return 250
+		})
+
+		notifier := notification.New(ctx, notification.Options{
+			Database:     db,
+			Logger:       slogtest.Make(t, nil),
+			Sender:       sender(smarthost),
+			AccessURL:    accessURL,
+			Interval:     testutil.IntervalFast,
+			RetryBackoff: time.Millisecond,
+		})
+		defer notifier.Close()
+
+		user := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: workspace.ID})
+		notifier.WorkspaceBuildFailed(ctx, build.ID, "")
+
+		email := waitForEmail(ctx, t, received)
+		require.Equal(t, user.Email, email.To)
+		require.EqualValues(t, 3, attempts.Load())
+	})
+
+	t.Run("PermanentFailure", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		smarthost, _, attempts := smtpServer(t, func(int64) int {
+			return 550
+		})
+
+		notifier := notification.New(ctx, notification.Options{
+			Database:     db,
+			Logger:       slogtest.Make(t, nil),
+			Sender:       sender(smarthost),
+			AccessURL:    accessURL,
+			Interval:     testutil.IntervalFast,
+			RetryBackoff: time.Millisecond,
+		})
+		defer notifier.Close()
+
+		user := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: workspace.ID})
+		notifier.WorkspaceBuildFailed(ctx, build.ID, "")
+
+		require.Eventually(t, func() bool {
+			return attempts.Load() == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		// Let the notifier tick a few more times to ensure the message is
+		// not retried.
+		time.Sleep(10 * testutil.IntervalFast)
+		require.EqualValues(t, 1, attempts.Load())
+	})
+
+	t.Run("OptOut", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		smarthost, received, _ := smtpServer(t, alwaysOK)
+
+		notifier := notification.New(ctx, notification.Options{
+			Database:  db,
+			Logger:    slogtest.Make(t, nil),
+			Sender:    sender(smarthost),
+			AccessURL: accessURL,
+		})
+		defer notifier.Close()
+
+		optedOut := dbgen.User(t, db, database.User{})
+		err := db.UpsertUserNotificationPreference(ctx, database.UpsertUserNotificationPreferenceParams{
+			UserID:    optedOut.ID,
+			Kind:      database.NotificationKindWorkspaceBuildFailed,
+			Disabled:  true,
+			UpdatedAt: database.Now(),
+		})
+		require.NoError(t, err)
+		suspended := dbgen.User(t, db, database.User{})
+		suspended, err = db.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+			ID:        suspended.ID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: database.Now(),
+		})
+		require.NoError(t, err)
+		optedIn := dbgen.User(t, db, database.User{})
+
+		for _, user := range []database.User{optedOut, suspended, optedIn} {
+			workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID})
+			build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{WorkspaceID: workspace.ID})
+			notifier.WorkspaceBuildFailed(ctx, build.ID, "")
+		}
+
+		email := waitForEmail(ctx, t, received)
+		require.Equal(t, optedIn.Email, email.To)
+		select {
+		case email := <-received:
+			t.Fatalf("unexpected email to %s", email.To)
+		default:
+		}
+	})
+
+	t.Run("WorkspaceAutostop", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		smarthost, received, attempts := smtpServer(t, alwaysOK)
+
+		user := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID})
+		job := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			CompletedAt: sql.NullTime{Time: database.Now(), Valid: true},
+		})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			JobID:       job.ID,
+			Transition:  database.WorkspaceTransitionStart,
+			Deadline:    database.Now().Add(10 * time.Minute),
+		})
+		// A workspace that is stopped later should not be notified yet.
+		laterJob := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			CompletedAt: sql.NullTime{Time: database.Now(), Valid: true},
+		})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID}).ID,
+			JobID:       laterJob.ID,
+			Transition:  database.WorkspaceTransitionStart,
+			Deadline:    database.Now().Add(2 * time.Hour),
+		})
+
+		// Several notifiers simulate several replicas.
+		for i := 0; i < 2; i++ {
+			notifier := notification.New(ctx, notification.Options{
+				Database:  db,
+				Logger:    slogtest.Make(t, nil),
+				Sender:    sender(smarthost),
+				AccessURL: accessURL,
+				Interval:  testutil.IntervalFast,
+			})
+			defer notifier.Close()
+		}
+
+		email := waitForEmail(ctx, t, received)
+		require.Equal(t, user.Email, email.To)
+		require.Equal(t, fmt.Sprintf("Workspace %s will stop soon", workspace.Name), email.Subject)
+
+		// Let the notifiers tick a few more times to ensure the email is only
+		// sent once.
+		time.Sleep(10 * testutil.IntervalFast)
+		require.EqualValues(t, 1, attempts.Load())
+	})
+
+	t.Run("WorkspaceAutostopSkipsFailedWorkspace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		smarthost, received, _ := smtpServer(t, alwaysOK)
+
+		user := dbgen.User(t, db, database.User{})
+		for i := 0; i < 2; i++ {
+			workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID})
+			job := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+				CompletedAt: sql.NullTime{Time: database.Now(), Valid: true},
+			})
+			_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+				WorkspaceID: workspace.ID,
+				JobID:       job.ID,
+				Transition:  database.WorkspaceTransitionStart,
+				Deadline:    database.Now().Add(10 * time.Minute),
+			})
+		}
+		// Every workspace but the first one looked up fails, so exactly one
+		// notification is expected no matter the order.
+		store := &failingWorkspaceStore{Store: db}
+
+		notifier := notification.New(ctx, notification.Options{
+			Database:  store,
+			Logger:    slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+			Sender:    sender(smarthost),
+			AccessURL: accessURL,
+			Interval:  testutil.IntervalFast,
+		})
+		defer notifier.Close()
+
+		email := waitForEmail(ctx, t, received)
+		require.Equal(t, user.Email, email.To)
+		require.Contains(t, email.Subject, "will stop soon")
+	})
+
+	t.Run("WorkspaceLocked", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		smarthost, received, _ := smtpServer(t, alwaysOK)
+
+		user := dbgen.User(t, db, database.User{})
+		template := dbgen.Template(t, db, database.Template{})
+		err := db.UpdateTemplateScheduleByID(ctx, database.UpdateTemplateScheduleByIDParams{
+			ID:        template.ID,
+			UpdatedAt: database.Now(),
+			LockedTTL: int64(7 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+		workspace := dbgen.Workspace(t, db, database.Workspace{
+			OwnerID:    user.ID,
+			TemplateID: template.ID,
+		})
+		lockedAt := database.Now()
+		err = db.UpdateWorkspaceLockedDeletingAt(ctx, database.UpdateWorkspaceLockedDeletingAtParams{
+			ID:       workspace.ID,
+			LockedAt: sql.NullTime{Time: lockedAt, Valid: true},
+		})
+		require.NoError(t, err)
+
+		notifier := notification.New(ctx, notification.Options{
+			Database:  db,
+			Logger:    slogtest.Make(t, nil),
+			Sender:    sender(smarthost),
+			AccessURL: accessURL,
+		})
+		defer notifier.Close()
+
+		email := waitForEmail(ctx, t, received)
+		require.Equal(t, user.Email, email.To)
+		require.Equal(t, fmt.Sprintf("Workspace %s was locked due to inactivity", workspace.Name), email.Subject)
+		require.Contains(t, email.Body, lockedAt.Add(7*24*time.Hour).UTC().Format("Mon, 02 Jan 2006 15:04 MST"))
+	})
+
+	t.Run("TokenExpiring", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		smarthost, received, _ := smtpServer(t, alwaysOK)
+
+		user := dbgen.User(t, db, database.User{})
+		_, _ = dbgen.APIKey(t, db, database.APIKey{
+			UserID:    user.ID,
+			LoginType: database.LoginTypeToken,
+			TokenName: "ci",
+			ExpiresAt: database.Now().Add(24 * time.Hour),
+		})
+		// Neither session tokens nor tokens that expire later are notified.
+		_, _ = dbgen.APIKey(t, db, database.APIKey{
+			UserID:    user.ID,
+			LoginType: database.LoginTypePassword,
+			ExpiresAt: database.Now().Add(time.Hour),
+		})
+		_, _ = dbgen.APIKey(t, db, database.APIKey{
+			UserID:    user.ID,
+			LoginType: database.LoginTypeToken,
+			TokenName: "later",
+			ExpiresAt: database.Now().Add(30 * 24 * time.Hour),
+		})
+
+		notifier := notification.New(ctx, notification.Options{
+			Database:  db,
+			Logger:    slogtest.Make(t, nil),
+			Sender:    sender(smarthost),
+			AccessURL: accessURL,
+		})
+		defer notifier.Close()
+
+		email := waitForEmail(ctx, t, received)
+		require.Equal(t, user.Email, email.To)
+		require.Equal(t, "Token ci will expire soon", email.Subject)
+		select {
+		case email := <-received:
+			t.Fatalf("unexpected email %q", email.Subject)
+		case <-time.After(10 * testutil.IntervalFast):
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		notifier := notification.New(context.Background(), notification.Options{
+			Database:  db,
+			Logger:    slogtest.Make(t, nil),
+			AccessURL: accessURL,
+		})
+		notifier.WorkspaceBuildFailed(context.Background(), uuid.New(), "")
+		require.NoError(t, notifier.Close())
+	})
+
+	t.Run("Nop", func(t *testing.T) {
+		t.Parallel()
+		notification.NewNop().WorkspaceBuildFailed(context.Background(), uuid.New(), "")
+	})
+}
+
+// failingWorkspaceStore fails to get every workspace but the first one
+// it's asked for.
+type failingWorkspaceStore struct {
+	database.Store
+
+	mu    sync.Mutex
+	first uuid.UUID
+}
+
+func (s *failingWorkspaceStore) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (database.Workspace, error) {
+	s.mu.Lock()
+	if s.first == uuid.Nil {
+		s.first = id
+	}
+	ok := s.first == id
+	s.mu.Unlock()
+	if !ok {
+		return database.Workspace{}, xerrors.New("boom")
+	}
+	return s.Store.GetWorkspaceByID(ctx, id)
+}
@@ -0,0 +1,147 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// Sender delivers a notification to a recipient.
+type Sender interface {
+	Send(ctx context.Context, recipient, subject, body string) error
+}
+
+// SMTPSender sends notifications as plain text emails through an SMTP
+// server.
+type SMTPSender struct {
+	from      string
+	smarthost string
+	username  string
+	password  string
+	forceTLS  bool
+}
+
+var _ Sender = (*SMTPSender)(nil)
+
+// NewSMTPSender returns a sender for the email notification options of the
+// deployment.
+func NewSMTPSender(cfg codersdk.NotificationsEmailConfig) *SMTPSender {
+	return &SMTPSender{
+		from:      cfg.From.String(),
+		smarthost: cfg.Smarthost.String(),
+		username:  cfg.Username.String(),
+		password:  cfg.Password.String(),
+		forceTLS:  cfg.ForceTLS.Value(),
+	}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, recipient, subject, body string) error {
+	host, _, err := net.SplitHostPort(s.smarthost)
+	if err != nil {
+		return xerrors.Errorf("parse smarthost %q: %w", s.smarthost, err)
+	}
+
+	var conn net.Conn
+	dialer := &net.Dialer{}
+	if s.forceTLS {
+		conn, err = (&tls.Dialer{
+			NetDialer: dialer,
+			Config: &tls.Config{
+				ServerName: host,
+				MinVersion: tls.VersionTLS12,
+			},
+		}).DialContext(ctx, "tcp", s.smarthost)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", s.smarthost)
+	}
+	if err != nil {
+		return xerrors.Errorf("dial smarthost: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return xerrors.Errorf("create client: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && !s.forceTLS {
+		err = client.StartTLS(&tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		})
+		if err != nil {
+			return xerrors.Errorf("starttls: %w", err)
+		}
+	}
+	if s.username != "" {
+		err = client.Auth(smtp.PlainAuth("", s.username, s.password, host))
+		if err != nil {
+			return xerrors.Errorf("authenticate: %w", err)
+		}
+	}
+	err = client.Mail(s.from)
+	if err != nil {
+		return xerrors.Errorf("mail from: %w", err)
+	}
+	err = client.Rcpt(recipient)
+	if err != nil {
+		return xerrors.Errorf("rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return xerrors.Errorf("data: %w", err)
+	}
+	_, err = w.Write(s.message(recipient, subject, body))
+	if err != nil {
+		return xerrors.Errorf("write message: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return xerrors.Errorf("close message: %w", err)
+	}
+	return client.Quit()
+}
+
+// message formats a plain text email. The SMTP client converts line endings
+// to CRLF.
+func (s *SMTPSender) message(recipient, subject, body string) []byte {
+	domain := "coder"
+	if _, after, ok := strings.Cut(s.from, "@"); ok {
+		domain = strings.Trim(after, "> ")
+	}
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "From: %s\n", s.from)
+	_, _ = fmt.Fprintf(&b, "To: %s\n", recipient)
+	_, _ = fmt.Fprintf(&b, "Subject: %s\n", mime.QEncoding.Encode("utf-8", subject))
+	_, _ = fmt.Fprintf(&b, "Date: %s\n", time.Now().Format(time.RFC1123Z))
+	_, _ = fmt.Fprintf(&b, "Message-ID: <%s@%s>\n", uuid.NewString(), domain)
+	_, _ = fmt.Fprintf(&b, "MIME-Version: 1.0\n")
+	_, _ = fmt.Fprintf(&b, "Content-Type: text/plain; charset=UTF-8\n")
+	_, _ = fmt.Fprintf(&b, "Content-Transfer-Encoding: 8bit\n")
+	_, _ = fmt.Fprintf(&b, "\n%s", body)
+	return []byte(b.String())
+}
+
+// isPermanent reports whether the error is a permanent SMTP failure, in
+// which case retrying the message will not succeed.
+func isPermanent(err error) bool {
+	var smtpErr *textproto.Error
+	return errors.As(err, &smtpErr) && smtpErr.Code >= 500
+}
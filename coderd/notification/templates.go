@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// kindDescriptions are used in the footer of every message to tell users
+// which notifications they can opt out of.
+var kindDescriptions = map[database.NotificationKind]string{
+	database.NotificationKindWorkspaceAutostop:    "workspace autostop",
+	database.NotificationKindWorkspaceBuildFailed: "failed workspace build",
+	database.NotificationKindWorkspaceLocked:      "locked workspace",
+	database.NotificationKindTokenExpiring:        "token expiry",
+}
+
+var templates = func() map[database.NotificationKind]*template.Template {
+	funcs := template.FuncMap{
+		"formatTime": func(t time.Time) string {
+			return t.UTC().Format("Mon, 02 Jan 2006 15:04 MST")
+		},
+	}
+	templates := make(map[database.NotificationKind]*template.Template)
+	for _, kind := range database.AllNotificationKindValues() {
+		name := fmt.Sprintf("%s.tmpl", kind)
+		templates[kind] = template.Must(template.New(name).Funcs(funcs).ParseFS(templateFS, "templates/footer.tmpl", "templates/"+name))
+	}
+	return templates
+}()
+
+// templateData is passed to the templates of all kinds of notifications.
+// Only the fields relevant to the kind are set.
+type templateData struct {
+	Kind            database.NotificationKind
+	KindDescription string
+	AccessURL       string
+	Username        string
+
+	WorkspaceName string
+	WorkspaceURL  string
+	BuildNumber   int32
+	Transition    database.WorkspaceTransition
+	BuildError    string
+	Deadline      time.Time
+	DeletingAt    time.Time
+
+	TokenName string
+	ExpiresAt time.Time
+}
+
+// render returns the subject and body of a notification.
+func render(data templateData) (subject string, body string, err error) {
+	tmpl, ok := templates[data.Kind]
+	if !ok {
+		return "", "", xerrors.Errorf("no template for notification kind %q", data.Kind)
+	}
+	data.KindDescription = kindDescriptions[data.Kind]
+
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, "subject", data)
+	if err != nil {
+		return "", "", xerrors.Errorf("render subject: %w", err)
+	}
+	subject = strings.TrimSpace(buf.String())
+	buf.Reset()
+	err = tmpl.ExecuteTemplate(&buf, "body", data)
+	if err != nil {
+		return "", "", xerrors.Errorf("render body: %w", err)
+	}
+	return subject, buf.String(), nil
+}
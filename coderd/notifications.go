@@ -0,0 +1,128 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get user notification preferences
+// @ID get-user-notification-preferences
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [get]
+func (api *API) userNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	preferences, err := api.Database.GetUserNotificationPreferences(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationPreferences(preferences))
+}
+
+// @Summary Update user notification preferences
+// @ID update-user-notification-preferences
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateNotificationPreferencesRequest true "Notification preferences"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [put]
+func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	var req codersdk.UpdateNotificationPreferencesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	var validErrs []codersdk.ValidationError
+	for i, preference := range req.Preferences {
+		if !slices.Contains(codersdk.NotificationKinds, preference.Kind) {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  fmt.Sprintf("preferences[%d].kind", i),
+				Detail: fmt.Sprintf("Unknown notification kind %q.", preference.Kind),
+			})
+		}
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid notification preferences.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	var preferences []database.UserNotificationPreference
+	err := api.Database.InTx(func(tx database.Store) error {
+		now := database.Now()
+		for _, preference := range req.Preferences {
+			err := tx.UpsertUserNotificationPreference(ctx, database.UpsertUserNotificationPreferenceParams{
+				UserID:    user.ID,
+				Kind:      database.NotificationKind(preference.Kind),
+				Disabled:  !preference.Enabled,
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		var err error
+		preferences, err = tx.GetUserNotificationPreferences(ctx, user.ID)
+		return err
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationPreferences(preferences))
+}
+
+// convertNotificationPreferences returns a preference for every kind of
+// notification. Kinds without a stored preference are enabled.
+func convertNotificationPreferences(preferences []database.UserNotificationPreference) []codersdk.NotificationPreference {
+	converted := make([]codersdk.NotificationPreference, 0, len(codersdk.NotificationKinds))
+	for _, kind := range codersdk.NotificationKinds {
+		enabled := true
+		for _, preference := range preferences {
+			if preference.Kind == database.NotificationKind(kind) {
+				enabled = !preference.Disabled
+			}
+		}
+		converted = append(converted, codersdk.NotificationPreference{
+			Kind:    kind,
+			Enabled: enabled,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,93 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		preferences, err := memberClient.NotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, preferences, len(codersdk.NotificationKinds))
+		for _, preference := range preferences {
+			require.True(t, preference.Enabled, preference.Kind)
+		}
+
+		preferences, err = memberClient.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{{
+				Kind:    codersdk.NotificationKindWorkspaceAutostop,
+				Enabled: false,
+			}},
+		})
+		require.NoError(t, err)
+		require.Len(t, preferences, len(codersdk.NotificationKinds))
+		for _, preference := range preferences {
+			require.Equal(t, preference.Kind != codersdk.NotificationKindWorkspaceAutostop, preference.Enabled, preference.Kind)
+		}
+
+		// Preferences that are omitted are left unchanged.
+		preferences, err = memberClient.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{{
+				Kind:    codersdk.NotificationKindTokenExpiring,
+				Enabled: false,
+			}},
+		})
+		require.NoError(t, err)
+		for _, preference := range preferences {
+			switch preference.Kind {
+			case codersdk.NotificationKindWorkspaceAutostop, codersdk.NotificationKindTokenExpiring:
+				require.False(t, preference.Enabled, preference.Kind)
+			default:
+				require.True(t, preference.Enabled, preference.Kind)
+			}
+		}
+	})
+
+	t.Run("UnknownKind", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{{
+				Kind: "carrier_pigeon",
+			}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := memberClient.UpdateNotificationPreferences(ctx, first.UserID.String(), codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{{
+				Kind: codersdk.NotificationKindTokenExpiring,
+			}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
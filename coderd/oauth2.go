@@ -0,0 +1,990 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/apikey"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/cryptorand"
+)
+
+const (
+	// oauth2CodeLifetime is how long an authorization code can be exchanged
+	// for a token.
+	oauth2CodeLifetime = 10 * time.Minute
+	// oauth2RefreshTokenLifetime is how long a refresh token can be exchanged
+	// for a new token. Every exchange issues a new refresh token.
+	oauth2RefreshTokenLifetime = 30 * 24 * time.Hour
+)
+
+// @Summary Get OAuth2 apps
+// @ID get-oauth2-apps
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Success 200 {array} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [get]
+func (api *API) oAuth2ProviderApps(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apps, err := api.Database.GetOAuth2ProviderApps(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 apps.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.OAuth2ProviderApp, 0, len(apps))
+	for _, app := range apps {
+		converted = append(converted, convertOAuth2ProviderApp(api.AccessURL, app))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get OAuth2 app
+// @ID get-oauth2-app
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [get]
+func (api *API) oAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	app := httpmw.OAuth2ProviderAppParam(r)
+	httpapi.Write(r.Context(), rw, http.StatusOK, convertOAuth2ProviderApp(api.AccessURL, app))
+}
+
+// @Summary Create OAuth2 app
+// @ID create-oauth2-app
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param request body codersdk.PostOAuth2ProviderAppRequest true "Create OAuth2 app request"
+// @Success 201 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [post]
+func (api *API) postOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.PostOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	now := database.Now()
+	app, err := api.Database.InsertOAuth2ProviderApp(ctx, database.InsertOAuth2ProviderAppParams{
+		ID:          uuid.New(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Name:        req.Name,
+		Icon:        req.Icon,
+		CallbackURL: req.CallbackURL,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("OAuth2 app with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = app
+	httpapi.Write(ctx, rw, http.StatusCreated, convertOAuth2ProviderApp(api.AccessURL, app))
+}
+
+// @Summary Update OAuth2 app
+// @ID update-oauth2-app
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Param request body codersdk.PutOAuth2ProviderAppRequest true "Update OAuth2 app request"
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [put]
+func (api *API) putOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderAppParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = app
+
+	var req codersdk.PutOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	updated, err := api.Database.UpdateOAuth2ProviderAppByID(ctx, database.UpdateOAuth2ProviderAppByIDParams{
+		ID:          app.ID,
+		UpdatedAt:   database.Now(),
+		Name:        req.Name,
+		Icon:        req.Icon,
+		CallbackURL: req.CallbackURL,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("OAuth2 app with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, convertOAuth2ProviderApp(api.AccessURL, updated))
+}
+
+// @Summary Delete OAuth2 app
+// @ID delete-oauth2-app
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /oauth2-provider/apps/{app} [delete]
+func (api *API) deleteOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderAppParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderApp](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = app
+
+	// Secrets, codes and tokens are deleted in cascade, and the API keys of
+	// the tokens with them.
+	err := api.Database.DeleteOAuth2ProviderAppByID(ctx, app.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "OAuth2 app has been deleted!",
+	})
+}
+
+// @Summary Get OAuth2 app secrets
+// @ID get-oauth2-app-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 200 {array} codersdk.OAuth2ProviderAppSecret
+// @Router /oauth2-provider/apps/{app}/secrets [get]
+func (api *API) oAuth2ProviderAppSecrets(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		app = httpmw.OAuth2ProviderAppParam(r)
+	)
+
+	secrets, err := api.Database.GetOAuth2ProviderAppSecretsByAppID(ctx, app.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 app secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.OAuth2ProviderAppSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		converted = append(converted, convertOAuth2ProviderAppSecret(secret))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Create OAuth2 app secret
+// @ID create-oauth2-app-secret
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Success 201 {object} codersdk.OAuth2ProviderAppSecretFull
+// @Router /oauth2-provider/apps/{app}/secrets [post]
+func (api *API) postOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		app               = httpmw.OAuth2ProviderAppParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderAppSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	secret, hashed, err := generateOAuth2Secret()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to generate OAuth2 client secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	dbSecret, err := api.Database.InsertOAuth2ProviderAppSecret(ctx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:           uuid.New(),
+		CreatedAt:    database.Now(),
+		HashedSecret: hashed,
+		// Only the end of the secret is stored so secrets can be told apart.
+		DisplaySecret: secret[len(secret)-6:],
+		AppID:         app.ID,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 client secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = dbSecret
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.OAuth2ProviderAppSecretFull{
+		ID:               dbSecret.ID,
+		ClientSecretFull: secret,
+	})
+}
+
+// @Summary Delete OAuth2 app secret
+// @ID delete-oauth2-app-secret
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID" format(uuid)
+// @Param secretID path string true "Secret ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /oauth2-provider/apps/{app}/secrets/{secretID} [delete]
+func (api *API) deleteOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		secret            = httpmw.OAuth2ProviderAppSecretParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.OAuth2ProviderAppSecret](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = secret
+
+	// Tokens issued with the secret are deleted in cascade.
+	err := api.Database.DeleteOAuth2ProviderAppSecretByID(ctx, secret.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OAuth2 client secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "OAuth2 client secret has been deleted!",
+	})
+}
+
+// @Summary Get OAuth2 authorization request
+// @Description Validates an authorization request and returns what the user
+// @Description consents to.
+// @ID get-oauth2-authorization-request
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param client_id query string true "Client ID" format(uuid)
+// @Param response_type query string true "Response type" Enums(code)
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "PKCE code challenge method" Enums(S256)
+// @Param redirect_uri query string false "Redirect URI"
+// @Param scope query string false "Scope of the API key"
+// @Param state query string false "State"
+// @Success 200 {object} codersdk.OAuth2Authorization
+// @Router /oauth2/authorize [get]
+func (api *API) getOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authorization, _, ok := api.parseOAuth2AuthorizeRequest(ctx, rw, r)
+	if !ok {
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, authorization)
+}
+
+// @Summary Authorize OAuth2 app
+// @Description Consents to an authorization request on behalf of the
+// @Description authenticated user. The response contains the URI the user
+// @Description is redirected to, with the authorization code.
+// @ID authorize-oauth2-app
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param client_id query string true "Client ID" format(uuid)
+// @Param response_type query string true "Response type" Enums(code)
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "PKCE code challenge method" Enums(S256)
+// @Param redirect_uri query string false "Redirect URI"
+// @Param scope query string false "Scope of the API key"
+// @Param state query string false "State"
+// @Success 200 {object} codersdk.OAuth2AuthorizeResponse
+// @Router /oauth2/authorize [post]
+func (api *API) postOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
+	authorization, req, ok := api.parseOAuth2AuthorizeRequest(ctx, rw, r)
+	if !ok {
+		return
+	}
+
+	code, hashed, err := generateOAuth2Secret()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to generate OAuth2 authorization code.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	now := database.Now()
+	_, err = api.Database.InsertOAuth2ProviderAppCode(ctx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            uuid.New(),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(oauth2CodeLifetime),
+		HashedSecret:  hashed,
+		UserID:        apiKey.UserID,
+		AppID:         authorization.App.ID,
+		RedirectURI:   req.RedirectURI,
+		Scope:         database.APIKeyScope(authorization.Scope),
+		CodeChallenge: req.CodeChallenge,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 authorization code.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The redirect URI was validated, so it parses.
+	redirect, _ := url.Parse(authorization.RedirectURI)
+	query := redirect.Query()
+	query.Set("code", code)
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirect.RawQuery = query.Encode()
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OAuth2AuthorizeResponse{
+		RedirectURI: redirect.String(),
+	})
+}
+
+// parseOAuth2AuthorizeRequest validates the authorization request in the
+// query parameters, writing a response and returning false if it is invalid.
+func (api *API) parseOAuth2AuthorizeRequest(ctx context.Context, rw http.ResponseWriter, r *http.Request) (codersdk.OAuth2Authorization, codersdk.OAuth2AuthorizeRequest, bool) {
+	vals := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	p.Required("client_id").Required("response_type").Required("code_challenge").Required("code_challenge_method")
+	req := codersdk.OAuth2AuthorizeRequest{
+		ClientID:            p.UUID(vals, uuid.Nil, "client_id"),
+		RedirectURI:         p.String(vals, "", "redirect_uri"),
+		Scope:               codersdk.APIKeyScope(p.String(vals, string(codersdk.APIKeyScopeAll), "scope")),
+		State:               p.String(vals, "", "state"),
+		ResponseType:        p.String(vals, "", "response_type"),
+		CodeChallenge:       p.String(vals, "", "code_challenge"),
+		CodeChallengeMethod: p.String(vals, "", "code_challenge_method"),
+	}
+	p.ErrorExcessParams(vals)
+	if req.ResponseType != "" && req.ResponseType != "code" {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "response_type",
+			Detail: `Only the "code" response type is supported.`,
+		})
+	}
+	if req.CodeChallengeMethod != "" && req.CodeChallengeMethod != "S256" {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "code_challenge_method",
+			Detail: `Only the "S256" code challenge method is supported.`,
+		})
+	}
+	if !database.APIKeyScope(req.Scope).Valid() {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "scope",
+			Detail: fmt.Sprintf("Unknown scope %q.", req.Scope),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid OAuth2 authorization request.",
+			Validations: p.Errors,
+		})
+		return codersdk.OAuth2Authorization{}, req, false
+	}
+
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, req.ClientID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid OAuth2 authorization request.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "client_id",
+				Detail: "No OAuth2 app with this client ID exists.",
+			}},
+		})
+		return codersdk.OAuth2Authorization{}, req, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return codersdk.OAuth2Authorization{}, req, false
+	}
+
+	// Errors with the redirect URI are never redirected, the user would be
+	// sent to a URI the app did not register.
+	redirect, err := oauth2RedirectURI(app.CallbackURL, req.RedirectURI)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid OAuth2 authorization request.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "redirect_uri",
+				Detail: err.Error(),
+			}},
+		})
+		return codersdk.OAuth2Authorization{}, req, false
+	}
+
+	return codersdk.OAuth2Authorization{
+		App:         convertOAuth2ProviderApp(api.AccessURL, app),
+		Scope:       req.Scope,
+		RedirectURI: redirect.String(),
+	}, req, true
+}
+
+// @Summary OAuth2 token exchange
+// @Description Exchanges an authorization code or a refresh token for an
+// @Description access token, as defined by RFC 6749. The access token is
+// @Description an API key and can be sent as a bearer token. Clients
+// @Description authenticate with HTTP basic auth or the client_id and
+// @Description client_secret parameters.
+// @ID oauth2-token-exchange
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Tags OAuth2
+// @Param grant_type formData string true "Grant type" Enums(authorization_code,refresh_token)
+// @Param client_id formData string false "Client ID, required without basic auth"
+// @Param client_secret formData string false "Client secret, required without basic auth"
+// @Param code formData string false "Authorization code, required with grant type authorization_code"
+// @Param code_verifier formData string false "PKCE code verifier, required with grant type authorization_code"
+// @Param redirect_uri formData string false "Redirect URI, required if it was in the authorization request"
+// @Param refresh_token formData string false "Refresh token, required with grant type refresh_token"
+// @Success 200 {object} codersdk.OAuth2TokenResponse
+// @Failure 400 {object} codersdk.OAuth2Error
+// @Failure 401 {object} codersdk.OAuth2Error
+// @Router /oauth2/tokens [post]
+func (api *API) postOAuth2ProviderAppToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		// The token endpoint is not authenticated with an API key, apps
+		// authenticate with their client secret instead.
+		//nolint:gocritic // System needs to read apps, codes and tokens.
+		sysCtx            = dbauthz.AsSystemRestricted(ctx)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+	// Token responses must not be cached.
+	rw.Header().Set("Cache-Control", "no-store")
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_request", "The request body could not be parsed.")
+		return
+	}
+
+	app, secret, ok := api.authenticateOAuth2Client(sysCtx, rw, r)
+	if !ok {
+		return
+	}
+
+	var (
+		userID uuid.UUID
+		scope  database.APIKeyScope
+	)
+	switch grantType := r.PostForm.Get("grant_type"); grantType {
+	case "authorization_code":
+		code, ok := api.redeemOAuth2Code(sysCtx, rw, r, app)
+		if !ok {
+			return
+		}
+		userID, scope = code.UserID, code.Scope
+	case "refresh_token":
+		key, ok := api.redeemOAuth2RefreshToken(sysCtx, rw, r, app)
+		if !ok {
+			return
+		}
+		userID, scope = key.UserID, key.Scope
+	case "":
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_request", "The grant_type parameter is required.")
+		return
+	default:
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "unsupported_grant_type", fmt.Sprintf("Grant type %q is not supported.", grantType))
+		return
+	}
+	aReq.UserID = userID
+
+	user, err := api.Database.GetUserByID(sysCtx, userID)
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error fetching user.")
+		return
+	}
+	if user.Deleted || user.Status != database.UserStatusActive {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The user cannot use Coder.")
+		return
+	}
+
+	cookie, key, err := api.createAPIKey(sysCtx, apikey.CreateParams{
+		UserID:           userID,
+		LoginType:        database.LoginTypeOAuth2ProviderApp,
+		DeploymentValues: api.DeploymentValues,
+		Scope:            scope,
+		RemoteAddr:       r.RemoteAddr,
+	})
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to create API key.")
+		return
+	}
+	refreshToken, hashedRefreshToken, err := generateOAuth2Secret()
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Failed to generate refresh token.")
+		return
+	}
+	now := database.Now()
+	_, err = api.Database.InsertOAuth2ProviderAppToken(sysCtx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:                 uuid.New(),
+		CreatedAt:          now,
+		ExpiresAt:          now.Add(oauth2RefreshTokenLifetime),
+		HashedRefreshToken: hashedRefreshToken,
+		AppSecretID:        secret.ID,
+		APIKeyID:           key.ID,
+	})
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error creating token.")
+		return
+	}
+	_, err = api.Database.UpdateOAuth2ProviderAppSecretByID(sysCtx, database.UpdateOAuth2ProviderAppSecretByIDParams{
+		ID:         secret.ID,
+		LastUsedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error updating client secret.")
+		return
+	}
+
+	aReq.New = *key
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OAuth2TokenResponse{
+		AccessToken:  cookie.Value,
+		TokenType:    "Bearer",
+		ExpiresIn:    key.LifetimeSeconds,
+		RefreshToken: refreshToken,
+	})
+}
+
+// authenticateOAuth2Client returns the app and secret the client
+// authenticated with, writing an error and returning false if it did not.
+func (api *API) authenticateOAuth2Client(ctx context.Context, rw http.ResponseWriter, r *http.Request) (database.OAuth2ProviderApp, database.OAuth2ProviderAppSecret, bool) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+	appID, err := uuid.Parse(clientID)
+	if err != nil || clientSecret == "" {
+		writeOAuth2Error(ctx, rw, http.StatusUnauthorized, "invalid_client", "Client authentication failed.")
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+	}
+
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, appID)
+	if httpapi.Is404Error(err) {
+		writeOAuth2Error(ctx, rw, http.StatusUnauthorized, "invalid_client", "Client authentication failed.")
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+	}
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error fetching OAuth2 app.")
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+	}
+	secrets, err := api.Database.GetOAuth2ProviderAppSecretsByAppID(ctx, app.ID)
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error fetching OAuth2 client secrets.")
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+	}
+	hashed := sha256.Sum256([]byte(clientSecret))
+	for _, secret := range secrets {
+		if subtle.ConstantTimeCompare(secret.HashedSecret, hashed[:]) == 1 {
+			return app, secret, true
+		}
+	}
+	writeOAuth2Error(ctx, rw, http.StatusUnauthorized, "invalid_client", "Client authentication failed.")
+	return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, false
+}
+
+// redeemOAuth2Code validates and deletes the authorization code of a token
+// request, writing an error and returning false if it is invalid.
+func (api *API) redeemOAuth2Code(ctx context.Context, rw http.ResponseWriter, r *http.Request, app database.OAuth2ProviderApp) (database.OAuth2ProviderAppCode, bool) {
+	hashed := sha256.Sum256([]byte(r.PostForm.Get("code")))
+	code, err := api.Database.GetOAuth2ProviderAppCodeByHashedSecret(ctx, hashed[:])
+	if httpapi.Is404Error(err) {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The authorization code is invalid.")
+		return database.OAuth2ProviderAppCode{}, false
+	}
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error fetching authorization code.")
+		return database.OAuth2ProviderAppCode{}, false
+	}
+	if code.AppID != app.ID {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The authorization code is invalid.")
+		return database.OAuth2ProviderAppCode{}, false
+	}
+
+	// Codes can only be used once, even if the request fails. Concurrent
+	// requests race to delete the code, only the one that deletes it may
+	// redeem it.
+	_, err = api.Database.DeleteOAuth2ProviderAppCodeByID(ctx, code.ID)
+	if httpapi.Is404Error(err) {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The authorization code is invalid.")
+		return database.OAuth2ProviderAppCode{}, false
+	}
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error deleting authorization code.")
+		return database.OAuth2ProviderAppCode{}, false
+	}
+
+	if code.ExpiresAt.Before(database.Now()) {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The authorization code has expired.")
+		return database.OAuth2ProviderAppCode{}, false
+	}
+	if code.RedirectURI != r.PostForm.Get("redirect_uri") {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The redirect_uri does not match the authorization request.")
+		return database.OAuth2ProviderAppCode{}, false
+	}
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	challenge := base64.RawURLEncoding.EncodeToString(verifier[:])
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) != 1 {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The code_verifier does not match the code challenge.")
+		return database.OAuth2ProviderAppCode{}, false
+	}
+	return code, true
+}
+
+// redeemOAuth2RefreshToken validates a refresh token and revokes it along
+// with its API key, writing an error and returning false if it is invalid.
+// The returned API key is the revoked one.
+func (api *API) redeemOAuth2RefreshToken(ctx context.Context, rw http.ResponseWriter, r *http.Request, app database.OAuth2ProviderApp) (database.APIKey, bool) {
+	hashed := sha256.Sum256([]byte(r.PostForm.Get("refresh_token")))
+	token, err := api.Database.GetOAuth2ProviderAppTokenByHashedRefreshToken(ctx, hashed[:])
+	if httpapi.Is404Error(err) {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The refresh token is invalid.")
+		return database.APIKey{}, false
+	}
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error fetching refresh token.")
+		return database.APIKey{}, false
+	}
+	secret, err := api.Database.GetOAuth2ProviderAppSecretByID(ctx, token.AppSecretID)
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error fetching OAuth2 client secret.")
+		return database.APIKey{}, false
+	}
+	if secret.AppID != app.ID {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The refresh token is invalid.")
+		return database.APIKey{}, false
+	}
+	if token.ExpiresAt.Before(database.Now()) {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The refresh token has expired.")
+		return database.APIKey{}, false
+	}
+	key, err := api.Database.GetAPIKeyByID(ctx, token.APIKeyID)
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error fetching API key.")
+		return database.APIKey{}, false
+	}
+
+	// Refresh tokens are rotated. Deleting the token deletes the API key
+	// with it. Concurrent requests race to delete the token, only the one
+	// that deletes it may redeem it.
+	_, err = api.Database.DeleteOAuth2ProviderAppTokenByAPIKeyID(ctx, key.ID)
+	if httpapi.Is404Error(err) {
+		writeOAuth2Error(ctx, rw, http.StatusBadRequest, "invalid_grant", "The refresh token is invalid.")
+		return database.APIKey{}, false
+	}
+	if err != nil {
+		writeOAuth2Error(ctx, rw, http.StatusInternalServerError, "server_error", "Internal error deleting refresh token.")
+		return database.APIKey{}, false
+	}
+	return key, true
+}
+
+// @Summary Get authorized OAuth2 apps
+// @ID get-authorized-oauth2-apps
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.OAuth2AuthorizedApp
+// @Router /users/{user}/oauth2-provider/apps [get]
+func (api *API) userOAuth2ProviderApps(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	rows, err := api.Database.GetOAuth2ProviderAppsByUserID(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching authorized OAuth2 apps.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apps := make([]codersdk.OAuth2AuthorizedApp, 0, len(rows))
+	for _, row := range rows {
+		apps = append(apps, codersdk.OAuth2AuthorizedApp{
+			ID:          row.ID,
+			Name:        row.Name,
+			Icon:        row.Icon,
+			CallbackURL: row.CallbackURL,
+			TokenCount:  row.TokenCount,
+			LastUsedAt:  row.LastUsed,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apps)
+}
+
+// @Summary Revoke authorized OAuth2 app
+// @Description Revokes all the codes and tokens an app holds for the user.
+// @ID revoke-authorized-oauth2-app
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param user path string true "User ID, name, or me"
+// @Param app path string true "App ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /users/{user}/oauth2-provider/apps/{app} [delete]
+func (api *API) deleteUserOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+		app  = httpmw.OAuth2ProviderAppParam(r)
+	)
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteOAuth2ProviderAppCodesByAppAndUserID(ctx, database.DeleteOAuth2ProviderAppCodesByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete codes: %w", err)
+		}
+		// The API keys of the tokens are deleted with them.
+		err = tx.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete tokens: %w", err)
+		}
+		return nil
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "OAuth2 app has been revoked!",
+	})
+}
+
+// oauth2RedirectURI returns the URI users are redirected to after an
+// authorization request. A redirect URI in the request must be the callback
+// URL of the app or a path below it.
+func oauth2RedirectURI(callbackURL, redirectURI string) (*url.URL, error) {
+	callback, err := url.Parse(callbackURL)
+	if err != nil {
+		return nil, xerrors.Errorf("The callback URL of the app is invalid: %w", err)
+	}
+	if redirectURI == "" {
+		return callback, nil
+	}
+
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		return nil, xerrors.Errorf("The redirect URI is invalid: %w", err)
+	}
+	if redirect.Fragment != "" {
+		return nil, xerrors.New("The redirect URI must not have a fragment.")
+	}
+	if redirect.Scheme != callback.Scheme || redirect.Host != callback.Host {
+		return nil, xerrors.New("The redirect URI must have the same origin as the callback URL of the app.")
+	}
+	// Paths like "/callback/../other" would otherwise pass the prefix check.
+	if redirect.Path != "" && path.Clean(redirect.Path) != strings.TrimSuffix(redirect.Path, "/") {
+		return nil, xerrors.New("The redirect URI must have a clean path.")
+	}
+	prefix := strings.TrimSuffix(callback.Path, "/") + "/"
+	if redirect.Path != callback.Path && !strings.HasPrefix(redirect.Path, prefix) {
+		return nil, xerrors.New("The redirect URI must be the callback URL of the app or below it.")
+	}
+	return redirect, nil
+}
+
+// generateOAuth2Secret returns a random secret and its hash. Client secrets,
+// authorization codes and refresh tokens are all stored hashed.
+func generateOAuth2Secret() (string, []byte, error) {
+	secret, err := cryptorand.String(40)
+	if err != nil {
+		return "", nil, err
+	}
+	hashed := sha256.Sum256([]byte(secret))
+	return secret, hashed[:], nil
+}
+
+func writeOAuth2Error(ctx context.Context, rw http.ResponseWriter, status int, code, description string) {
+	httpapi.Write(ctx, rw, status, codersdk.OAuth2Error{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
+
+func convertOAuth2ProviderApp(accessURL *url.URL, app database.OAuth2ProviderApp) codersdk.OAuth2ProviderApp {
+	return codersdk.OAuth2ProviderApp{
+		ID:          app.ID,
+		Name:        app.Name,
+		CallbackURL: app.CallbackURL,
+		Icon:        app.Icon,
+		Endpoints: codersdk.OAuth2AppEndpoints{
+			Authorization: accessURL.ResolveReference(&url.URL{Path: "/oauth2/authorize"}).String(),
+			Token:         accessURL.ResolveReference(&url.URL{Path: "/api/v2/oauth2/tokens"}).String(),
+		},
+	}
+}
+
+func convertOAuth2ProviderAppSecret(secret database.OAuth2ProviderAppSecret) codersdk.OAuth2ProviderAppSecret {
+	converted := codersdk.OAuth2ProviderAppSecret{
+		ID:                    secret.ID,
+		CreatedAt:             secret.CreatedAt,
+		ClientSecretTruncated: secret.DisplaySecret,
+	}
+	if secret.LastUsedAt.Valid {
+		converted.LastUsedAt = &secret.LastUsedAt.Time
+	}
+	return converted
+}
@@ -0,0 +1,380 @@
+package coderd_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestOAuth2ProviderApps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		auditor.ResetLogs()
+
+		app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "my-app",
+			CallbackURL: "http://localhost/callback",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "my-app", app.Name)
+		require.Equal(t, client.URL.String()+"/api/v2/oauth2/tokens", app.Endpoints.Token)
+
+		_, err = client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "my-app",
+			CallbackURL: "http://localhost/other",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		app, err = client.PutOAuth2ProviderApp(ctx, app.ID, codersdk.PutOAuth2ProviderAppRequest{
+			Name:        "my-app",
+			CallbackURL: "http://localhost/new-callback",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost/new-callback", app.CallbackURL)
+
+		apps, err := client.OAuth2ProviderApps(ctx)
+		require.NoError(t, err)
+		require.Len(t, apps, 1)
+		require.Equal(t, app, apps[0])
+
+		secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, secret.ClientSecretFull)
+		secrets, err := client.OAuth2ProviderAppSecrets(ctx, app.ID)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, secret.ClientSecretFull[len(secret.ClientSecretFull)-6:], secrets[0].ClientSecretTruncated)
+		require.Nil(t, secrets[0].LastUsedAt)
+		err = client.DeleteOAuth2ProviderAppSecret(ctx, app.ID, secret.ID)
+		require.NoError(t, err)
+
+		err = client.DeleteOAuth2ProviderApp(ctx, app.ID)
+		require.NoError(t, err)
+		_, err = client.OAuth2ProviderApp(ctx, app.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, 5)
+		assert.Equal(t, database.AuditActionCreate, logs[0].Action)
+		assert.Equal(t, database.ResourceTypeOAuth2ProviderApp, logs[0].ResourceType)
+		assert.Equal(t, database.AuditActionWrite, logs[1].Action)
+		assert.Equal(t, database.AuditActionCreate, logs[2].Action)
+		assert.Equal(t, database.ResourceTypeOAuth2ProviderAppSecret, logs[2].ResourceType)
+		assert.Equal(t, database.AuditActionDelete, logs[3].Action)
+		assert.Equal(t, database.ResourceTypeOAuth2ProviderAppSecret, logs[3].ResourceType)
+		assert.Equal(t, database.AuditActionDelete, logs[4].Action)
+		assert.Equal(t, database.ResourceTypeOAuth2ProviderApp, logs[4].ResourceType)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+			Name:        "my-app",
+			CallbackURL: "http://localhost/callback",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestOAuth2ProviderAppAuthorize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Flow", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		app, config := setupOAuth2ProviderApp(ctx, t, client)
+		auditor.ResetLogs()
+
+		verifier, challenge := oauth2PKCE()
+		authorization, err := member.OAuth2Authorization(ctx, codersdk.OAuth2AuthorizeRequest{
+			ClientID:            app.ID,
+			Scope:               codersdk.APIKeyScopeReadOnly,
+			ResponseType:        "code",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: "S256",
+		})
+		require.NoError(t, err)
+		require.Equal(t, app.ID, authorization.App.ID)
+		require.Equal(t, codersdk.APIKeyScopeReadOnly, authorization.Scope)
+		require.Equal(t, app.CallbackURL, authorization.RedirectURI)
+
+		code := authorizeOAuth2ProviderApp(ctx, t, member, codersdk.OAuth2AuthorizeRequest{
+			ClientID:            app.ID,
+			Scope:               codersdk.APIKeyScopeReadOnly,
+			State:               "state",
+			ResponseType:        "code",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: "S256",
+		})
+
+		oauthCtx := context.WithValue(ctx, oauth2.HTTPClient, client.HTTPClient)
+		_, err = config.Exchange(oauthCtx, code, oauth2.SetAuthURLParam("code_verifier", "wrong"))
+		require.Error(t, err)
+		// A failed exchange still uses the code.
+		_, err = config.Exchange(oauthCtx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.Error(t, err)
+
+		code = authorizeOAuth2ProviderApp(ctx, t, member, codersdk.OAuth2AuthorizeRequest{
+			ClientID:            app.ID,
+			Scope:               codersdk.APIKeyScopeReadOnly,
+			ResponseType:        "code",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: "S256",
+		})
+		token, err := config.Exchange(oauthCtx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.NoError(t, err)
+		require.Equal(t, "Bearer", token.TokenType)
+		require.NotEmpty(t, token.RefreshToken)
+
+		appClient := codersdk.New(client.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		me, err := appClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, me.ID)
+		key, err := appClient.APIKeyByID(ctx, codersdk.Me, appClient.SessionToken()[:10])
+		require.NoError(t, err)
+		require.Equal(t, codersdk.APIKeyScopeReadOnly, key.Scope)
+		require.Equal(t, codersdk.LoginTypeOAuth2ProviderApp, key.LoginType)
+
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, 1)
+		assert.Equal(t, database.AuditActionCreate, logs[0].Action)
+		assert.Equal(t, database.ResourceTypeApiKey, logs[0].ResourceType)
+		assert.Equal(t, user.ID, logs[0].UserID)
+
+		// Refreshing rotates both the access and the refresh token.
+		token.Expiry = time.Now().Add(-time.Minute)
+		refreshed, err := config.TokenSource(oauthCtx, token).Token()
+		require.NoError(t, err)
+		require.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+		require.NotEqual(t, token.RefreshToken, refreshed.RefreshToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		_, err = config.TokenSource(oauthCtx, token).Token()
+		require.Error(t, err)
+
+		appClient.SetSessionToken(refreshed.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		apps, err := member.OAuth2AuthorizedApps(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, apps, 1)
+		require.Equal(t, app.ID, apps[0].ID)
+		require.EqualValues(t, 1, apps[0].TokenCount)
+
+		err = member.RevokeOAuth2ProviderApp(ctx, codersdk.Me, app.ID)
+		require.NoError(t, err)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+		refreshed.Expiry = time.Now().Add(-time.Minute)
+		_, err = config.TokenSource(oauthCtx, refreshed).Token()
+		require.Error(t, err)
+		apps, err = member.OAuth2AuthorizedApps(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, apps)
+	})
+
+	t.Run("RedirectURI", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		app, config := setupOAuth2ProviderApp(ctx, t, client)
+		verifier, challenge := oauth2PKCE()
+
+		for _, redirectURI := range []string{
+			"http://example.com/callback",
+			"https://localhost/callback",
+			"http://localhost/other",
+			"http://localhost/callback/../other",
+			"http://localhost/callback#fragment",
+		} {
+			_, err := client.OAuth2Authorization(ctx, codersdk.OAuth2AuthorizeRequest{
+				ClientID:            app.ID,
+				RedirectURI:         redirectURI,
+				ResponseType:        "code",
+				CodeChallenge:       challenge,
+				CodeChallengeMethod: "S256",
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, redirectURI)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), redirectURI)
+		}
+
+		// The token request must repeat the redirect URI of the
+		// authorization request.
+		config.RedirectURL = app.CallbackURL + "/sub"
+		code := authorizeOAuth2ProviderApp(ctx, t, client, codersdk.OAuth2AuthorizeRequest{
+			ClientID:            app.ID,
+			RedirectURI:         config.RedirectURL,
+			ResponseType:        "code",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: "S256",
+		})
+		oauthCtx := context.WithValue(ctx, oauth2.HTTPClient, client.HTTPClient)
+		_, err := config.Exchange(oauthCtx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		require.NoError(t, err)
+	})
+
+	t.Run("ConcurrentRedeem", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		app, config := setupOAuth2ProviderApp(ctx, t, client)
+		verifier, challenge := oauth2PKCE()
+		oauthCtx := context.WithValue(ctx, oauth2.HTTPClient, client.HTTPClient)
+
+		// redeemConcurrently runs the redemption several times at once and
+		// returns the tokens of the ones that succeeded. The others must
+		// fail with invalid_grant.
+		redeemConcurrently := func(redeem func() (*oauth2.Token, error)) []*oauth2.Token {
+			const attempts = 5
+			var (
+				wg     sync.WaitGroup
+				mu     sync.Mutex
+				tokens []*oauth2.Token
+			)
+			for i := 0; i < attempts; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					token, err := redeem()
+					if err != nil {
+						var retrieveErr *oauth2.RetrieveError
+						if assert.ErrorAs(t, err, &retrieveErr) {
+							assert.Equal(t, http.StatusBadRequest, retrieveErr.Response.StatusCode)
+						}
+						return
+					}
+					mu.Lock()
+					tokens = append(tokens, token)
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+			return tokens
+		}
+
+		code := authorizeOAuth2ProviderApp(ctx, t, client, codersdk.OAuth2AuthorizeRequest{
+			ClientID:            app.ID,
+			ResponseType:        "code",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: "S256",
+		})
+		tokens := redeemConcurrently(func() (*oauth2.Token, error) {
+			return config.Exchange(oauthCtx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		})
+		require.Len(t, tokens, 1)
+
+		token := tokens[0]
+		token.Expiry = time.Now().Add(-time.Minute)
+		tokens = redeemConcurrently(func() (*oauth2.Token, error) {
+			return config.TokenSource(oauthCtx, token).Token()
+		})
+		require.Len(t, tokens, 1)
+	})
+
+	t.Run("InvalidClient", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		app, config := setupOAuth2ProviderApp(ctx, t, client)
+		verifier, challenge := oauth2PKCE()
+
+		code := authorizeOAuth2ProviderApp(ctx, t, client, codersdk.OAuth2AuthorizeRequest{
+			ClientID:            app.ID,
+			ResponseType:        "code",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: "S256",
+		})
+		config.ClientSecret = "wrong"
+		oauthCtx := context.WithValue(ctx, oauth2.HTTPClient, client.HTTPClient)
+		_, err := config.Exchange(oauthCtx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+		var retrieveErr *oauth2.RetrieveError
+		require.ErrorAs(t, err, &retrieveErr)
+		require.Equal(t, http.StatusUnauthorized, retrieveErr.Response.StatusCode)
+	})
+}
+
+// setupOAuth2ProviderApp creates an app with a secret and returns an OAuth2
+// config for it.
+func setupOAuth2ProviderApp(ctx context.Context, t *testing.T, client *codersdk.Client) (codersdk.OAuth2ProviderApp, *oauth2.Config) {
+	t.Helper()
+
+	app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:        "my-app",
+		CallbackURL: "http://localhost/callback",
+	})
+	require.NoError(t, err)
+	secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.NoError(t, err)
+
+	return app, &oauth2.Config{
+		ClientID:     app.ID.String(),
+		ClientSecret: secret.ClientSecretFull,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   app.Endpoints.Authorization,
+			TokenURL:  app.Endpoints.Token,
+			AuthStyle: oauth2.AuthStyleInHeader,
+		},
+	}
+}
+
+// authorizeOAuth2ProviderApp consents to the request and returns the
+// authorization code.
+func authorizeOAuth2ProviderApp(ctx context.Context, t *testing.T, client *codersdk.Client, req codersdk.OAuth2AuthorizeRequest) string {
+	t.Helper()
+
+	res, err := client.AuthorizeOAuth2ProviderApp(ctx, req)
+	require.NoError(t, err)
+	redirect, err := url.Parse(res.RedirectURI)
+	require.NoError(t, err)
+	require.Equal(t, req.State, redirect.Query().Get("state"))
+	code := redirect.Query().Get("code")
+	require.NotEmpty(t, code)
+	return code
+}
+
+func oauth2PKCE() (verifier string, challenge string) {
+	verifier = "verifier-that-is-at-least-forty-three-characters-long"
+	hashed := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(hashed[:])
+}
@@ -0,0 +1,186 @@
+package coderd
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/sqlc-dev/pqtype"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/userpassword"
+)
+
+// passwordPolicy returns the password policy of the deployment.
+func (api *API) passwordPolicy() userpassword.Policy {
+	return userpassword.Policy{
+		MinLength:        int(api.DeploymentValues.PasswordPolicy.MinLength.Value()),
+		CharacterClasses: int(api.DeploymentValues.PasswordPolicy.CharacterClasses.Value()),
+	}
+}
+
+// passwordReused returns true if the password matches one of the previous
+// passwords of the user that the policy prevents from being reused.
+func (api *API) passwordReused(ctx context.Context, user database.User, password string) (bool, error) {
+	keep := api.DeploymentValues.PasswordPolicy.History.Value()
+	if keep <= 0 {
+		return false, nil
+	}
+	history, err := api.Database.GetUserPasswordHistory(ctx, database.GetUserPasswordHistoryParams{
+		UserID:     user.ID,
+		LimitCount: int32(keep),
+	})
+	if err != nil {
+		return false, xerrors.Errorf("get user password history: %w", err)
+	}
+	for _, entry := range history {
+		match, _ := userpassword.Compare(string(entry.HashedPassword), password)
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rememberPassword adds the current password of the user to their history
+// before it is changed.
+func (api *API) rememberPassword(ctx context.Context, tx database.Store, user database.User) error {
+	keep := api.DeploymentValues.PasswordPolicy.History.Value()
+	if keep <= 0 || len(user.HashedPassword) == 0 {
+		return nil
+	}
+	err := tx.InsertUserPasswordHistory(ctx, database.InsertUserPasswordHistoryParams{
+		UserID:         user.ID,
+		HashedPassword: user.HashedPassword,
+		CreatedAt:      database.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("insert user password history: %w", err)
+	}
+	err = tx.DeleteOldUserPasswordHistory(ctx, database.DeleteOldUserPasswordHistoryParams{
+		UserID: user.ID,
+		Keep:   int32(keep),
+	})
+	if err != nil {
+		return xerrors.Errorf("delete old user password history: %w", err)
+	}
+	return nil
+}
+
+// loginLockoutEnabled returns true if failed logins of the user are tracked.
+// Only password users are tracked, as failures of other users cannot be
+// caused by guessing their password.
+func (api *API) loginLockoutEnabled(user database.User) bool {
+	return api.DeploymentValues.PasswordPolicy.LockoutThreshold.Value() > 0 &&
+		user.LoginType == database.LoginTypePassword
+}
+
+// loginRateLimited returns true if an owner has exceeded the lockout threshold
+// from the IP address. Owners are never locked, so a deployment can't lose all
+// of its admins. Their logins from the IP address are rejected instead.
+func (api *API) loginRateLimited(ctx context.Context, user database.User, remoteAddr string) (bool, error) {
+	if !api.loginLockoutEnabled(user) || !slices.Contains(user.RBACRoles, rbac.RoleOwner()) {
+		return false, nil
+	}
+	//nolint:gocritic // Failed logins are tracked before the user is authenticated.
+	counts, err := api.Database.GetUserLoginFailureCounts(dbauthz.AsSystemRestricted(ctx), database.GetUserLoginFailureCountsParams{
+		UserID:    user.ID,
+		IPAddress: loginIPAddress(remoteAddr),
+		Since:     database.Now().Add(-api.DeploymentValues.PasswordPolicy.LockoutWindow.Value()),
+	})
+	if err != nil {
+		return false, xerrors.Errorf("get user login failure counts: %w", err)
+	}
+	return counts.IPFailures >= api.DeploymentValues.PasswordPolicy.LockoutThreshold.Value(), nil
+}
+
+// recordLoginFailure tracks a failed password login of the user and locks
+// their account once they exceed the lockout threshold.
+func (api *API) recordLoginFailure(ctx context.Context, user database.User, remoteAddr string) error {
+	if !api.loginLockoutEnabled(user) {
+		return nil
+	}
+	//nolint:gocritic // Failed logins are tracked before the user is authenticated.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	ip := loginIPAddress(remoteAddr)
+	err := api.Database.InsertUserLoginFailure(sysCtx, database.InsertUserLoginFailureParams{
+		UserID:    user.ID,
+		IPAddress: ip,
+		CreatedAt: database.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("insert user login failure: %w", err)
+	}
+	if user.Status != database.UserStatusActive || slices.Contains(user.RBACRoles, rbac.RoleOwner()) {
+		return nil
+	}
+	counts, err := api.Database.GetUserLoginFailureCounts(sysCtx, database.GetUserLoginFailureCountsParams{
+		UserID:    user.ID,
+		IPAddress: ip,
+		Since:     database.Now().Add(-api.DeploymentValues.PasswordPolicy.LockoutWindow.Value()),
+	})
+	if err != nil {
+		return xerrors.Errorf("get user login failure counts: %w", err)
+	}
+	if counts.Failures < api.DeploymentValues.PasswordPolicy.LockoutThreshold.Value() {
+		return nil
+	}
+
+	lockedUser, err := api.Database.UpdateUserStatus(sysCtx, database.UpdateUserStatusParams{
+		ID:        user.ID,
+		Status:    database.UserStatusLocked,
+		UpdatedAt: database.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("lock user: %w", err)
+	}
+	api.Logger.Info(ctx, "locked user after failed logins",
+		slog.F("user_id", user.ID),
+		slog.F("failures", counts.Failures),
+	)
+	audit.BuildAudit(ctx, &audit.BuildAuditParams[database.User]{
+		Audit:  *api.Auditor.Load(),
+		Log:    api.Logger,
+		UserID: user.ID,
+		Status: http.StatusOK,
+		Action: database.AuditActionWrite,
+		Old:    user,
+		New:    lockedUser,
+	})
+	return nil
+}
+
+// clearLoginFailures forgets the failed logins of the user, so they no longer
+// count towards the lockout threshold.
+func (api *API) clearLoginFailures(ctx context.Context, user database.User) error {
+	if !api.loginLockoutEnabled(user) {
+		return nil
+	}
+	//nolint:gocritic // Failed logins are cleared on login and by admins.
+	err := api.Database.DeleteUserLoginFailures(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		return xerrors.Errorf("delete user login failures: %w", err)
+	}
+	return nil
+}
+
+func loginIPAddress(remoteAddr string) pqtype.Inet {
+	ip := net.ParseIP(remoteAddr)
+	if ip == nil {
+		ip = net.IPv4(0, 0, 0, 0)
+	}
+	bitlen := len(ip) * 8
+	return pqtype.Inet{
+		IPNet: net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(bitlen, bitlen),
+		},
+		Valid: true,
+	}
+}
@@ -0,0 +1,205 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateUser", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.PasswordPolicy.MinLength = 19
+		dv.PasswordPolicy.CharacterClasses = 3
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, password := range []string{
+			// Too short.
+			"Secure!Pass1",
+			// Not enough character classes.
+			"SomeSecurePasswordToo",
+			// Too common.
+			"Password1234",
+		} {
+			_, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+				Email:          "policy@coder.com",
+				Username:       "policy",
+				Password:       password,
+				OrganizationID: first.OrganizationID,
+			})
+			requireStatusCode(t, err, http.StatusBadRequest)
+		}
+
+		_, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          "policy@coder.com",
+			Username:       "policy",
+			Password:       "SomeSecurePassword1!",
+			OrganizationID: first.OrganizationID,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("History", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.PasswordPolicy.History = 2
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		updatePassword := func(password string) error {
+			return client.UpdateUserPassword(ctx, member.ID.String(), codersdk.UpdateUserPasswordRequest{
+				Password: password,
+			})
+		}
+
+		require.NoError(t, updatePassword("FirstNewPassword!"))
+		requireStatusCode(t, updatePassword("SomeSecurePassword!"), http.StatusBadRequest)
+		require.NoError(t, updatePassword("SecondNewPassword!"))
+		require.NoError(t, updatePassword("ThirdNewPassword!"))
+		requireStatusCode(t, updatePassword("FirstNewPassword!"), http.StatusBadRequest)
+		// Only the last two passwords are remembered.
+		require.NoError(t, updatePassword("SomeSecurePassword!"))
+	})
+}
+
+func TestLoginLockout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		dv := coderdtest.DeploymentValues(t)
+		dv.PasswordPolicy.LockoutThreshold = 3
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor:          auditor,
+			DeploymentValues: dv,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		login := func(password string) error {
+			_, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+				Email:    member.Email,
+				Password: password,
+			})
+			return err
+		}
+
+		require.NoError(t, login("SomeSecurePassword!"))
+		for i := 0; i < 3; i++ {
+			requireStatusCode(t, login("wrong"), http.StatusUnauthorized)
+		}
+		err := login("SomeSecurePassword!")
+		requireStatusCode(t, err, http.StatusUnauthorized)
+		require.ErrorContains(t, err, "locked")
+
+		member, err = client.User(ctx, member.ID.String())
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusLocked, member.Status)
+		// The lockout is audited as a change the user made to themselves.
+		var audited bool
+		for _, log := range auditor.AuditLogs() {
+			if log.ResourceType == database.ResourceTypeUser && log.ResourceID == member.ID &&
+				log.UserID == member.ID && log.Action == database.AuditActionWrite {
+				audited = true
+			}
+		}
+		require.True(t, audited)
+
+		member, err = client.UpdateUserStatus(ctx, member.ID.String(), codersdk.UserStatusActive)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusActive, member.Status)
+
+		// The failures before the user was activated are forgotten.
+		requireStatusCode(t, login("wrong"), http.StatusUnauthorized)
+		require.NoError(t, login("SomeSecurePassword!"))
+	})
+
+	t.Run("MFA", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.PasswordPolicy.LockoutThreshold = 3
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		_ = enrollTOTP(t, memberClient)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		login := func(password, code string) error {
+			_, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+				Email:    member.Email,
+				Password: password,
+				TOTPCode: code,
+			})
+			return err
+		}
+
+		// A correct password doesn't clear the failures before the code is
+		// verified, and incorrect codes are failures too.
+		requireStatusCode(t, login("wrong", ""), http.StatusUnauthorized)
+		for i := 0; i < 2; i++ {
+			err := login("SomeSecurePassword!", "12345678")
+			requireStatusCode(t, err, http.StatusUnauthorized)
+			require.ErrorContains(t, err, "Incorrect multi-factor authentication code")
+		}
+		err := login("SomeSecurePassword!", "")
+		requireStatusCode(t, err, http.StatusUnauthorized)
+		require.ErrorContains(t, err, "locked")
+
+		member, err = client.User(ctx, member.ID.String())
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusLocked, member.Status)
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.PasswordPolicy.LockoutThreshold = 2
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		login := func(password string) error {
+			_, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+				Email:    coderdtest.FirstUserParams.Email,
+				Password: password,
+			})
+			return err
+		}
+
+		for i := 0; i < 2; i++ {
+			requireStatusCode(t, login("wrong"), http.StatusUnauthorized)
+		}
+		requireStatusCode(t, login(coderdtest.FirstUserParams.Password), http.StatusTooManyRequests)
+
+		owner, err := client.User(ctx, first.UserID.String())
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusActive, owner.Status)
+	})
+}
+
+func requireStatusCode(t *testing.T, err error, status int) {
+	t.Helper()
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, status, apiErr.StatusCode())
+}
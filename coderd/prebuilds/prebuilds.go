@@ -0,0 +1,261 @@
+// Package prebuilds keeps pools of prebuilt workspaces ready for templates
+// that request them. Creating a workspace from such a template claims a
+// prebuilt workspace instead of provisioning a new one from scratch.
+package prebuilds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/db2sdk"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	// MaxPoolSize is the maximum number of prebuilt workspaces a template
+	// can request.
+	MaxPoolSize = 100
+
+	// OwnerUsername is the username of the user that owns prebuilt
+	// workspaces until they are claimed. Usernames of real users can't
+	// contain underscores, so it never collides with one.
+	OwnerUsername = "prebuilds_owner"
+	ownerEmail    = "prebuilds@coder.internal"
+)
+
+// OwnerID is the ID of the user that owns prebuilt workspaces until they are
+// claimed. The user is created by the reconciler and is suspended, so it can
+// never log in.
+var OwnerID = uuid.MustParse("c42fdf75-3097-471c-8c33-fb52454d81c0")
+
+// ErrNoneAvailable is returned by Claim if no prebuilt workspace matches.
+var ErrNoneAvailable = xerrors.New("no prebuilt workspace available")
+
+// Metrics are the Prometheus metrics of the prebuilt workspace pools.
+type Metrics struct {
+	desired         *prometheus.GaugeVec
+	ready           *prometheus.GaugeVec
+	claims          *prometheus.CounterVec
+	misses          *prometheus.CounterVec
+	reconcileErrors prometheus.Counter
+}
+
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	auto := promauto.With(reg)
+
+	return &Metrics{
+		desired: auto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "prebuilds",
+			Name:      "desired",
+			Help:      "The number of prebuilt workspaces requested by the template.",
+		}, []string{"template_name"}),
+		ready: auto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "prebuilds",
+			Name:      "ready",
+			Help:      "The number of prebuilt workspaces that are ready to be claimed.",
+		}, []string{"template_name"}),
+		claims: auto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "prebuilds",
+			Name:      "claims_total",
+			Help:      "The number of workspaces created by claiming a prebuilt workspace.",
+		}, []string{"template_name"}),
+		misses: auto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "prebuilds",
+			Name:      "misses_total",
+			Help:      "The number of workspaces provisioned from scratch because no prebuilt workspace matched.",
+		}, []string{"template_name"}),
+		reconcileErrors: auto.NewCounter(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "prebuilds",
+			Name:      "reconcile_errors_total",
+			Help:      "The number of errors while reconciling the prebuilt workspace pools.",
+		}),
+	}
+}
+
+// Claimed records that a workspace was created by claiming a prebuilt
+// workspace of the template.
+func (m *Metrics) Claimed(templateName string) {
+	m.claims.WithLabelValues(templateName).Inc()
+}
+
+// Missed records that a workspace of a template with prebuilt workspaces was
+// provisioned from scratch.
+func (m *Metrics) Missed(templateName string) {
+	m.misses.WithLabelValues(templateName).Inc()
+}
+
+// ClaimParams are the settings of the workspace the user asked for.
+type ClaimParams struct {
+	OwnerID             uuid.UUID
+	Name                string
+	AutostartSchedule   sql.NullString
+	Ttl                 sql.NullInt64
+	RichParameterValues []codersdk.WorkspaceBuildParameter
+}
+
+// Claim transfers a ready prebuilt workspace of the template to the user. Only
+// workspaces built from the active template version with parameters matching
+// the requested values are considered. ErrNoneAvailable is returned if none
+// matches.
+//
+// The caller is expected to start a build of the claimed workspace so that
+// the provisioner picks up the new owner.
+func Claim(ctx context.Context, db database.Store, template database.Template, params ClaimParams) (database.Workspace, error) {
+	pool, err := poolOf(ctx, db, template)
+	if err != nil {
+		return database.Workspace{}, err
+	}
+
+	for _, prebuild := range pool {
+		if !prebuild.ready() {
+			continue
+		}
+		buildParameters, err := db.GetWorkspaceBuildParameters(ctx, prebuild.build.ID)
+		if err != nil {
+			return database.Workspace{}, xerrors.Errorf("get build parameters: %w", err)
+		}
+		if !parametersMatch(buildParameters, params.RichParameterValues) {
+			continue
+		}
+
+		now := database.Now()
+		workspace, err := db.ClaimPrebuiltWorkspace(ctx, database.ClaimPrebuiltWorkspaceParams{
+			ID:                prebuild.workspace.ID,
+			PrebuildsOwnerID:  OwnerID,
+			NewOwnerID:        params.OwnerID,
+			Name:              params.Name,
+			AutostartSchedule: params.AutostartSchedule,
+			Ttl:               params.Ttl,
+			Now:               now,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// Someone else claimed the workspace first.
+			continue
+		}
+		if err != nil {
+			return database.Workspace{}, xerrors.Errorf("claim prebuilt workspace: %w", err)
+		}
+		return workspace, nil
+	}
+	return database.Workspace{}, ErrNoneAvailable
+}
+
+// parametersMatch returns whether a workspace built with the given
+// parameters is what the user would get with the requested values.
+func parametersMatch(built []database.WorkspaceBuildParameter, requested []codersdk.WorkspaceBuildParameter) bool {
+	values := make(map[string]string, len(built))
+	for _, parameter := range built {
+		values[parameter.Name] = parameter.Value
+	}
+	for _, parameter := range requested {
+		value, ok := values[parameter.Name]
+		if !ok || value != parameter.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// prebuild is a workspace of a pool with its latest build.
+type prebuild struct {
+	workspace database.Workspace
+	build     database.WorkspaceBuild
+	job       database.ProvisionerJob
+	template  database.Template
+}
+
+func (p prebuild) status() codersdk.ProvisionerJobStatus {
+	return db2sdk.ProvisionerJobStatus(p.job)
+}
+
+// ready returns whether the workspace was successfully started with the
+// active version of the template.
+func (p prebuild) ready() bool {
+	return p.build.Transition == database.WorkspaceTransitionStart &&
+		p.status() == codersdk.ProvisionerJobSucceeded &&
+		p.build.TemplateVersionID == p.template.ActiveVersionID
+}
+
+// building returns whether the workspace is being started.
+func (p prebuild) building() bool {
+	status := p.status()
+	return p.build.Transition == database.WorkspaceTransitionStart &&
+		(status == codersdk.ProvisionerJobPending || status == codersdk.ProvisionerJobRunning)
+}
+
+// poolOf returns the prebuilt workspaces of the template, oldest first.
+func poolOf(ctx context.Context, db database.Store, template database.Template) ([]prebuild, error) {
+	pools, err := pools(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	pool := pools[template.ID]
+	for i := range pool {
+		pool[i].template = template
+	}
+	return pool, nil
+}
+
+// pools returns the prebuilt workspaces grouped by template, oldest first.
+func pools(ctx context.Context, db database.Store) (map[uuid.UUID][]prebuild, error) {
+	workspaces, err := db.GetWorkspacesByOwnerID(ctx, OwnerID)
+	if err != nil {
+		return nil, xerrors.Errorf("get prebuilt workspaces: %w", err)
+	}
+	if len(workspaces) == 0 {
+		return map[uuid.UUID][]prebuild{}, nil
+	}
+
+	workspaceIDs := make([]uuid.UUID, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		workspaceIDs = append(workspaceIDs, workspace.ID)
+	}
+	builds, err := db.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, workspaceIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get latest builds: %w", err)
+	}
+	buildsByWorkspace := make(map[uuid.UUID]database.WorkspaceBuild, len(builds))
+	jobIDs := make([]uuid.UUID, 0, len(builds))
+	for _, build := range builds {
+		buildsByWorkspace[build.WorkspaceID] = build
+		jobIDs = append(jobIDs, build.JobID)
+	}
+	jobs, err := db.GetProvisionerJobsByIDs(ctx, jobIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get provisioner jobs: %w", err)
+	}
+	jobsByID := make(map[uuid.UUID]database.ProvisionerJob, len(jobs))
+	for _, job := range jobs {
+		jobsByID[job.ID] = job
+	}
+
+	pools := make(map[uuid.UUID][]prebuild)
+	for _, workspace := range workspaces {
+		build, ok := buildsByWorkspace[workspace.ID]
+		if !ok {
+			continue
+		}
+		job, ok := jobsByID[build.JobID]
+		if !ok {
+			continue
+		}
+		pools[workspace.TemplateID] = append(pools[workspace.TemplateID], prebuild{
+			workspace: workspace,
+			build:     build,
+			job:       job,
+		})
+	}
+	return pools, nil
+}
@@ -0,0 +1,292 @@
+package prebuilds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/wsbuilder"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/cryptorand"
+)
+
+// Reconciler creates and deletes prebuilt workspaces so that every template
+// has as many ready workspaces as it requests.
+type Reconciler struct {
+	ctx     context.Context
+	db      database.Store
+	metrics *Metrics
+	log     slog.Logger
+	tick    <-chan time.Time
+	statsCh chan<- Stats
+}
+
+// Stats contains information about one run of Reconciler.
+type Stats struct {
+	// Created and Deleted count the prebuilt workspaces by template.
+	Created map[uuid.UUID]int
+	Deleted map[uuid.UUID]int
+	Elapsed time.Duration
+	Error   error
+}
+
+// NewReconciler returns a new prebuilt workspace reconciler.
+func NewReconciler(ctx context.Context, db database.Store, metrics *Metrics, log slog.Logger, tick <-chan time.Time) *Reconciler {
+	return &Reconciler{
+		//nolint:gocritic // The reconciler has a limited set of permissions.
+		ctx:     dbauthz.AsPrebuilds(ctx),
+		db:      db,
+		metrics: metrics,
+		tick:    tick,
+		log:     log.Named("prebuilds"),
+	}
+}
+
+// WithStatsChannel will cause Reconciler to push a Stats to ch after
+// every tick.
+func (r *Reconciler) WithStatsChannel(ch chan<- Stats) *Reconciler {
+	r.statsCh = ch
+	return r
+}
+
+// Run will cause the reconciler to reconcile the pools on every tick from
+// its channel. It will stop when its context is Done, or when its channel is
+// closed.
+func (r *Reconciler) Run() {
+	go func() {
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case t, ok := <-r.tick:
+				if !ok {
+					return
+				}
+				stats := r.runOnce(t)
+				if stats.Error != nil {
+					r.log.Error(r.ctx, "error reconciling prebuilt workspaces", slog.Error(stats.Error))
+				}
+				if r.statsCh != nil {
+					select {
+					case <-r.ctx.Done():
+						return
+					case r.statsCh <- stats:
+					}
+				}
+				r.log.Debug(r.ctx, "run stats", slog.F("elapsed", stats.Elapsed), slog.F("created", stats.Created), slog.F("deleted", stats.Deleted))
+			}
+		}
+	}()
+}
+
+func (r *Reconciler) runOnce(t time.Time) (stats Stats) {
+	var errs []error
+	stats = Stats{
+		Created: make(map[uuid.UUID]int),
+		Deleted: make(map[uuid.UUID]int),
+	}
+	defer func() {
+		stats.Elapsed = time.Since(t)
+		stats.Error = errors.Join(errs...)
+		for range errs {
+			r.metrics.reconcileErrors.Inc()
+		}
+	}()
+
+	// Every replica runs the reconciler, so each pass holds a lock to keep
+	// replicas from creating workspaces for the same deficit.
+	err := r.db.InTx(func(tx database.Store) error {
+		locked, err := tx.TryAcquireLock(r.ctx, database.LockIDPrebuildsReconcile)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !locked {
+			r.log.Debug(r.ctx, "another replica is reconciling prebuilt workspaces")
+			return nil
+		}
+		errs = append(errs, r.reconcile(tx, stats)...)
+		return nil
+	}, nil)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return stats
+}
+
+// reconcile reconciles the pools of all templates.
+func (r *Reconciler) reconcile(tx database.Store, stats Stats) []error {
+	err := r.ensureOwner(tx)
+	if err != nil {
+		return []error{xerrors.Errorf("ensure prebuilds owner: %w", err)}
+	}
+	templates, err := tx.GetTemplates(r.ctx)
+	if err != nil {
+		return []error{xerrors.Errorf("get templates: %w", err)}
+	}
+	pools, err := pools(r.ctx, tx)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, template := range templates {
+		if template.Deleted {
+			continue
+		}
+		pool := pools[template.ID]
+		if template.PrebuiltWorkspaces == 0 && len(pool) == 0 {
+			continue
+		}
+		for i := range pool {
+			pool[i].template = template
+		}
+		created, deleted, err := r.reconcileTemplate(tx, template, pool)
+		stats.Created[template.ID] = created
+		stats.Deleted[template.ID] = deleted
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("reconcile template %q: %w", template.Name, err))
+		}
+	}
+	return errs
+}
+
+// reconcileTemplate deletes prebuilt workspaces that can no longer be claimed
+// and creates new ones until the pool has the requested size.
+func (r *Reconciler) reconcileTemplate(tx database.Store, template database.Template, pool []prebuild) (created int, deleted int, err error) {
+	var (
+		ready    []prebuild
+		building int
+		remove   []prebuild
+	)
+	for _, prebuild := range pool {
+		switch {
+		case prebuild.build.Transition == database.WorkspaceTransitionDelete:
+			// Being deleted already.
+		case prebuild.building():
+			building++
+		case prebuild.ready():
+			ready = append(ready, prebuild)
+		case prebuild.status() == codersdk.ProvisionerJobPending,
+			prebuild.status() == codersdk.ProvisionerJobRunning,
+			prebuild.status() == codersdk.ProvisionerJobCanceling:
+			// Wait for the job to finish before deciding.
+		default:
+			// Stopped, failed or built from an outdated template version.
+			remove = append(remove, prebuild)
+		}
+	}
+
+	desired := int(template.PrebuiltWorkspaces)
+	// Remove excess workspaces, the newest first.
+	for excess := len(ready) + building - desired; excess > 0 && len(ready) > 0; excess-- {
+		remove = append(remove, ready[len(ready)-1])
+		ready = ready[:len(ready)-1]
+	}
+	r.metrics.desired.WithLabelValues(template.Name).Set(float64(desired))
+	r.metrics.ready.WithLabelValues(template.Name).Set(float64(len(ready)))
+
+	var errs []error
+	for _, prebuild := range remove {
+		builder := wsbuilder.New(prebuild.workspace, database.WorkspaceTransitionDelete).
+			Reason(database.BuildReasonInitiator).
+			Initiator(OwnerID)
+		_, _, err := builder.Build(r.ctx, tx, nil)
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("delete prebuilt workspace %q: %w", prebuild.workspace.Name, err))
+			continue
+		}
+		deleted++
+	}
+	for i := len(ready) + building; i < desired; i++ {
+		err := r.create(tx, template)
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("create prebuilt workspace: %w", err))
+			// Creating more would most likely fail the same way.
+			break
+		}
+		created++
+	}
+	return created, deleted, errors.Join(errs...)
+}
+
+func (r *Reconciler) create(db database.Store, template database.Template) error {
+	suffix, err := cryptorand.HexString(4)
+	if err != nil {
+		return xerrors.Errorf("generate name: %w", err)
+	}
+
+	return db.InTx(func(tx database.Store) error {
+		now := database.Now()
+		// Prebuilt workspaces have no schedule, the user claiming one
+		// decides on it.
+		workspace, err := tx.InsertWorkspace(r.ctx, database.InsertWorkspaceParams{
+			ID:             uuid.New(),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			OwnerID:        OwnerID,
+			OrganizationID: template.OrganizationID,
+			TemplateID:     template.ID,
+			Name:           fmt.Sprintf("prebuild-%s", suffix),
+			LastUsedAt:     now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert workspace: %w", err)
+		}
+		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+			Reason(database.BuildReasonInitiator).
+			Initiator(OwnerID).
+			ActiveVersion()
+		_, _, err = builder.Build(r.ctx, tx, nil)
+		return err
+	}, nil)
+}
+
+// ensureOwner creates the user owning prebuilt workspaces if it does not
+// exist yet.
+func (r *Reconciler) ensureOwner(db database.Store) error {
+	// Creating users is beyond the permissions of the reconciler.
+	//nolint:gocritic // The owner is a system user.
+	ctx := dbauthz.AsSystemRestricted(r.ctx)
+	_, err := db.GetUserByID(ctx, OwnerID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get user: %w", err)
+	}
+
+	return db.InTx(func(tx database.Store) error {
+		now := database.Now()
+		_, err := tx.InsertUser(ctx, database.InsertUserParams{
+			ID:             OwnerID,
+			Email:          ownerEmail,
+			Username:       OwnerUsername,
+			HashedPassword: []byte{},
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			RBACRoles:      []string{},
+			LoginType:      database.LoginTypePassword,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert user: %w", err)
+		}
+		// The user only exists to own workspaces.
+		_, err = tx.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+			ID:        OwnerID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("suspend user: %w", err)
+		}
+		return nil
+	}, nil)
+}
@@ -0,0 +1,147 @@
+package prebuilds_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/prebuilds"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestReconciler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan prebuilds.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			PrebuildsTicker:          tickCh,
+			PrebuildsStats:           statsCh,
+		})
+		user     = coderdtest.CreateFirstUser(t, client)
+		version  = coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	tick := func() prebuilds.Stats {
+		tickCh <- time.Now()
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		return stats
+	}
+	awaitPool := func(n int) []codersdk.Workspace {
+		res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Owner: prebuilds.OwnerUsername})
+		require.NoError(t, err)
+		require.Len(t, res.Workspaces, n)
+		for _, workspace := range res.Workspaces {
+			coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		}
+		return res.Workspaces
+	}
+
+	// Templates without prebuilt workspaces are left alone.
+	stats := tick()
+	assert.Empty(t, stats.Created)
+
+	_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		PrebuiltWorkspaces: 2,
+	})
+	require.NoError(t, err)
+	stats = tick()
+	assert.Equal(t, 2, stats.Created[template.ID])
+	awaitPool(2)
+
+	// The pool is full.
+	stats = tick()
+	assert.Equal(t, 0, stats.Created[template.ID])
+	assert.Equal(t, 0, stats.Deleted[template.ID])
+
+	// Claiming a workspace refills the pool.
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	workspace := coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+	assert.Greater(t, workspace.LatestBuild.BuildNumber, int32(1))
+	stats = tick()
+	assert.Equal(t, 1, stats.Created[template.ID])
+	awaitPool(2)
+
+	// Shrinking the pool deletes ready workspaces.
+	_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		PrebuiltWorkspaces: 0,
+	})
+	require.NoError(t, err)
+	stats = tick()
+	assert.Equal(t, 2, stats.Deleted[template.ID])
+}
+
+func TestOwnerUsername(t *testing.T) {
+	t.Parallel()
+
+	// Users can't take the username of the owner, so creating the owner
+	// never fails.
+	require.Error(t, httpapi.NameValid(prebuilds.OwnerUsername))
+}
+
+func TestReconcilerReplicas(t *testing.T) {
+	t.Parallel()
+
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		PrebuiltWorkspaces: 2,
+	})
+	require.NoError(t, err)
+
+	// Every replica runs a reconciler on the same database.
+	metrics := prebuilds.NewMetrics(prometheus.NewRegistry())
+	const replicas = 3
+	tickChs := make([]chan time.Time, replicas)
+	statsCh := make(chan prebuilds.Stats)
+	for i := range tickChs {
+		tickChs[i] = make(chan time.Time)
+		prebuilds.NewReconciler(ctx, api.Database, metrics, slogtest.Make(t, nil), tickChs[i]).
+			WithStatsChannel(statsCh).Run()
+	}
+	now := time.Now()
+	for _, tickCh := range tickChs {
+		tickCh <- now
+	}
+
+	created := 0
+	for range tickChs {
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		created += stats.Created[template.ID]
+	}
+	require.Equal(t, 2, created)
+
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Owner: prebuilds.OwnerUsername})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 2)
+	for _, workspace := range res.Workspaces {
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	}
+}
@@ -33,9 +33,11 @@ import (
 	"github.com/coder/coder/coderd/database/pubsub"
 	"github.com/coder/coder/coderd/gitauth"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/notification"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/tracing"
+	"github.com/coder/coder/coderd/webhook"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/provisioner"
 	"github.com/coder/coder/provisionerd/proto"
@@ -58,6 +60,8 @@ type Server struct {
 	Database                    database.Store
 	Pubsub                      pubsub.Pubsub
 	Telemetry                   telemetry.Reporter
+	Webhooks                    webhook.Publisher
+	Notifications               notification.Enqueuer
 	Tracer                      trace.Tracer
 	QuotaCommitter              *atomic.Pointer[proto.QuotaCommitter]
 	Auditor                     *atomic.Pointer[audit.Auditor]
@@ -197,6 +201,7 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("publish workspace update: %s", err))
 		}
+		server.Webhooks.WorkspaceBuild(ctx, database.WebhookEventWorkspaceBuildStarted, workspaceBuild.ID, "")
 
 		var workspaceOwnerOIDCAccessToken string
 		if server.OIDCConfig != nil {
@@ -679,6 +684,8 @@ func (server *Server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*p
 		if err != nil {
 			return nil, xerrors.Errorf("update workspace: %w", err)
 		}
+		server.Webhooks.WorkspaceBuild(ctx, database.WebhookEventWorkspaceBuildFailed, build.ID, failJob.Error)
+		server.Notifications.WorkspaceBuildFailed(ctx, build.ID, failJob.Error)
 	case *proto.FailedJob_TemplateImport_:
 	}
 
@@ -1066,6 +1073,7 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 		if err != nil {
 			return nil, xerrors.Errorf("update workspace: %w", err)
 		}
+		server.Webhooks.WorkspaceBuild(ctx, database.WebhookEventWorkspaceBuildSucceeded, workspaceBuild.ID, "")
 	case *proto.CompletedJob_TemplateDryRun_:
 		for _, resource := range jobType.TemplateDryRun.Resources {
 			server.Logger.Info(ctx, "inserting template dry-run job resource",
@@ -1185,6 +1193,11 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			prAgent.StartupScriptBehavior = string(codersdk.WorkspaceAgentStartupScriptBehaviorNonBlocking)
 		}
 
+		err = validateAgentScripts(prAgent.Scripts)
+		if err != nil {
+			return xerrors.Errorf("agent %q: %w", prAgent.Name, err)
+		}
+
 		agentID := uuid.New()
 		dbAgent, err := db.InsertWorkspaceAgent(ctx, database.InsertWorkspaceAgentParams{
 			ID:                   agentID,
@@ -1233,6 +1246,36 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			}
 		}
 
+		for _, script := range prAgent.Scripts {
+			runOn := database.WorkspaceAgentScriptRunOnStart
+			if script.RunOn != "" {
+				runOn = database.WorkspaceAgentScriptRunOn(script.RunOn)
+			}
+			displayName := script.DisplayName
+			if displayName == "" {
+				displayName = script.Name
+			}
+			dependsOn := script.DependsOn
+			if dependsOn == nil {
+				dependsOn = []string{}
+			}
+			_, err := db.InsertWorkspaceAgentScript(ctx, database.InsertWorkspaceAgentScriptParams{
+				ID:               uuid.New(),
+				CreatedAt:        database.Now(),
+				AgentID:          agentID,
+				Name:             script.Name,
+				DisplayName:      displayName,
+				Script:           script.Script,
+				RunOn:            runOn,
+				TimeoutSeconds:   script.TimeoutSeconds,
+				StartBlocksLogin: script.StartBlocksLogin,
+				DependsOn:        dependsOn,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent script %q: %w", script.Name, err)
+			}
+		}
+
 		for _, app := range prAgent.Apps {
 			slug := app.Slug
 			if slug == "" {
@@ -1314,7 +1357,9 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 	return nil
 }
 
-func workspaceSessionTokenName(workspace database.Workspace) string {
+// WorkspaceSessionTokenName returns the name of the session token that is
+// issued to the owner of the workspace for every build.
+func WorkspaceSessionTokenName(workspace database.Workspace) string {
 	return fmt.Sprintf("%s_%s_session_token", workspace.OwnerID, workspace.ID)
 }
 
@@ -1323,7 +1368,7 @@ func (server *Server) regenerateSessionToken(ctx context.Context, user database.
 		UserID:           user.ID,
 		LoginType:        user.LoginType,
 		DeploymentValues: server.DeploymentValues,
-		TokenName:        workspaceSessionTokenName(workspace),
+		TokenName:        WorkspaceSessionTokenName(workspace),
 		LifetimeSeconds:  int64(server.DeploymentValues.MaxTokenLifetime.Value().Seconds()),
 	})
 	if err != nil {
@@ -1353,7 +1398,7 @@ func deleteSessionToken(ctx context.Context, db database.Store, workspace databa
 	err := db.InTx(func(tx database.Store) error {
 		key, err := tx.GetAPIKeyByName(ctx, database.GetAPIKeyByNameParams{
 			UserID:    workspace.OwnerID,
-			TokenName: workspaceSessionTokenName(workspace),
+			TokenName: WorkspaceSessionTokenName(workspace),
 		})
 		if err == nil {
 			err = tx.DeleteAPIKeyByID(ctx, key.ID)
@@ -1549,3 +1594,74 @@ func redactTemplateVariable(templateVariable *sdkproto.TemplateVariable) *sdkpro
 	}
 	return maybeRedacted
 }
+
+// validateAgentScripts ensures script names are unique and valid, and that
+// every dependency refers to a script that runs at the same time without
+// forming a cycle.
+func validateAgentScripts(scripts []*sdkproto.Agent_Script) error {
+	byName := make(map[string]*sdkproto.Agent_Script, len(scripts))
+	for _, script := range scripts {
+		if !provisioner.AppSlugRegex.MatchString(script.Name) {
+			return xerrors.Errorf("script name %q does not match regex %q", script.Name, provisioner.AppSlugRegex.String())
+		}
+		if _, exists := byName[script.Name]; exists {
+			return xerrors.Errorf("duplicate script name %q", script.Name)
+		}
+		switch script.RunOn {
+		case "", string(database.WorkspaceAgentScriptRunOnStart), string(database.WorkspaceAgentScriptRunOnStop):
+		default:
+			return xerrors.Errorf("script %q: invalid run_on %q", script.Name, script.RunOn)
+		}
+		if script.TimeoutSeconds < 0 {
+			return xerrors.Errorf("script %q: timeout must not be negative", script.Name)
+		}
+		byName[script.Name] = script
+	}
+	runOn := func(script *sdkproto.Agent_Script) string {
+		if script.RunOn == "" {
+			return string(database.WorkspaceAgentScriptRunOnStart)
+		}
+		return script.RunOn
+	}
+	for _, script := range scripts {
+		for _, name := range script.DependsOn {
+			dependency, ok := byName[name]
+			if !ok {
+				return xerrors.Errorf("script %q depends on unknown script %q", script.Name, name)
+			}
+			if runOn(dependency) != runOn(script) {
+				return xerrors.Errorf("script %q depends on script %q which runs on %s", script.Name, name, runOn(dependency))
+			}
+		}
+	}
+
+	// Detect cycles with a depth-first search.
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(scripts))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return xerrors.Errorf("script dependency cycle detected at %q", name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dependency := range byName[name].DependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	for _, script := range scripts {
+		if err := visit(script.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
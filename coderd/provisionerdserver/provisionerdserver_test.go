@@ -24,9 +24,11 @@ import (
 	"github.com/coder/coder/coderd/database/dbgen"
 	"github.com/coder/coder/coderd/database/pubsub"
 	"github.com/coder/coder/coderd/gitauth"
+	"github.com/coder/coder/coderd/notification"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
+	"github.com/coder/coder/coderd/webhook"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/provisionerd/proto"
 	"github.com/coder/coder/provisionersdk"
@@ -69,6 +71,8 @@ func TestAcquireJob(t *testing.T) {
 			Database:                    db,
 			Pubsub:                      ps,
 			Telemetry:                   telemetry.NewNoop(),
+			Webhooks:                    webhook.NewNop(),
+			Notifications:               notification.NewNop(),
 			AcquireJobDebounce:          time.Hour,
 			Auditor:                     mockAuditor(),
 			TemplateScheduleStore:       testTemplateScheduleStore(),
@@ -1471,6 +1475,111 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		})
 		require.ErrorContains(t, err, "duplicate app slug")
 	})
+	t.Run("InvalidScripts", func(t *testing.T) {
+		t.Parallel()
+		for _, tc := range []struct {
+			name    string
+			scripts []*sdkproto.Agent_Script
+			err     string
+		}{{
+			name:    "DuplicateName",
+			scripts: []*sdkproto.Agent_Script{{Name: "a"}, {Name: "a"}},
+			err:     "duplicate script name",
+		}, {
+			name:    "InvalidName",
+			scripts: []*sdkproto.Agent_Script{{Name: "Not Valid"}},
+			err:     "does not match regex",
+		}, {
+			name:    "InvalidRunOn",
+			scripts: []*sdkproto.Agent_Script{{Name: "a", RunOn: "reboot"}},
+			err:     "invalid run_on",
+		}, {
+			name:    "UnknownDependency",
+			scripts: []*sdkproto.Agent_Script{{Name: "a", DependsOn: []string{"b"}}},
+			err:     "depends on unknown script",
+		}, {
+			name: "DependencyRunsOnStop",
+			scripts: []*sdkproto.Agent_Script{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", RunOn: "stop"},
+			},
+			err: "which runs on stop",
+		}, {
+			name: "Cycle",
+			scripts: []*sdkproto.Agent_Script{
+				{Name: "a", DependsOn: []string{"c"}},
+				{Name: "b", DependsOn: []string{"a"}},
+				{Name: "c", DependsOn: []string{"b"}},
+			},
+			err: "dependency cycle",
+		}} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				err := insert(dbfake.New(), uuid.New(), &sdkproto.Resource{
+					Name: "something",
+					Type: "aws_instance",
+					Agents: []*sdkproto.Agent{{
+						Name:    "dev",
+						Scripts: tc.scripts,
+					}},
+				})
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+	t.Run("Scripts", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Scripts: []*sdkproto.Agent_Script{{
+					Name:             "dotfiles",
+					DisplayName:      "Dotfiles",
+					Script:           "coder dotfiles -y",
+					TimeoutSeconds:   60,
+					StartBlocksLogin: true,
+				}, {
+					Name:      "services",
+					Script:    "start-services",
+					DependsOn: []string{"dotfiles"},
+				}, {
+					Name:   "backup",
+					Script: "backup",
+					RunOn:  "stop",
+				}},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		// Scripts block login individually, so the startup script
+		// behavior is unchanged.
+		require.Equal(t, database.StartupScriptBehaviorNonBlocking, agents[0].StartupScriptBehavior)
+
+		scripts, err := db.GetWorkspaceAgentScriptsByAgentIDs(ctx, []uuid.UUID{agents[0].ID})
+		require.NoError(t, err)
+		require.Len(t, scripts, 3)
+		require.Equal(t, "backup", scripts[0].Name)
+		require.Equal(t, database.WorkspaceAgentScriptRunOnStop, scripts[0].RunOn)
+		require.Equal(t, "dotfiles", scripts[1].Name)
+		require.Equal(t, "Dotfiles", scripts[1].DisplayName)
+		require.EqualValues(t, 60, scripts[1].TimeoutSeconds)
+		require.True(t, scripts[1].StartBlocksLogin)
+		require.Equal(t, database.WorkspaceAgentScriptRunOnStart, scripts[1].RunOn)
+		require.Equal(t, database.WorkspaceAgentScriptStatusPending, scripts[1].Status)
+		require.Equal(t, "services", scripts[2].Name)
+		require.Equal(t, "services", scripts[2].DisplayName)
+		require.Equal(t, []string{"dotfiles"}, scripts[2].DependsOn)
+	})
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
@@ -1533,6 +1642,8 @@ func setup(t *testing.T, ignoreLogErrors bool) *provisionerdserver.Server {
 		Database:                    db,
 		Pubsub:                      ps,
 		Telemetry:                   telemetry.NewNoop(),
+		Webhooks:                    webhook.NewNop(),
+		Notifications:               notification.NewNop(),
 		Auditor:                     mockAuditor(),
 		TemplateScheduleStore:       testTemplateScheduleStore(),
 		UserQuietHoursScheduleStore: testUserQuietHoursScheduleStore(),
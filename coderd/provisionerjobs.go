@@ -77,11 +77,7 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	// nolint:gocritic // GetWorkspaceResourcesByJobID is a system function.
-	resources, err := api.Database.GetWorkspaceResourcesByJobID(dbauthz.AsSystemRestricted(ctx), job.ID)
-	if errors.Is(err, sql.ErrNoRows) {
-		err = nil
-	}
+	apiResources, err := api.fetchProvisionerJobResources(ctx, job)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching job resources.",
@@ -89,6 +85,21 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 		})
 		return
 	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, apiResources)
+}
+
+// fetchProvisionerJobResources returns the resources created by a completed
+// job, including their agents, sorted by name.
+func (api *API) fetchProvisionerJobResources(ctx context.Context, job database.ProvisionerJob) ([]codersdk.WorkspaceResource, error) {
+	// nolint:gocritic // GetWorkspaceResourcesByJobID is a system function.
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(dbauthz.AsSystemRestricted(ctx), job.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace resources: %w", err)
+	}
 	resourceIDs := make([]uuid.UUID, 0)
 	for _, resource := range resources {
 		resourceIDs = append(resourceIDs, resource.ID)
@@ -100,11 +111,7 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 		err = nil
 	}
 	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching workspace agent.",
-			Detail:  err.Error(),
-		})
-		return
+		return nil, xerrors.Errorf("get workspace agents: %w", err)
 	}
 	resourceAgentIDs := make([]uuid.UUID, 0)
 	for _, agent := range resourceAgents {
@@ -117,21 +124,19 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 		err = nil
 	}
 	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching workspace applications.",
-			Detail:  err.Error(),
-		})
-		return
+		return nil, xerrors.Errorf("get workspace apps: %w", err)
+	}
+
+	// nolint:gocritic // GetWorkspaceAgentScriptsByAgentIDs is a system function.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), resourceAgentIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace agent scripts: %w", err)
 	}
 
 	// nolint:gocritic // GetWorkspaceResourceMetadataByResourceIDs is a system function.
 	resourceMetadata, err := api.Database.GetWorkspaceResourceMetadataByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
 	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching workspace metadata.",
-			Detail:  err.Error(),
-		})
-		return
+		return nil, xerrors.Errorf("get workspace resource metadata: %w", err)
 	}
 
 	apiResources := make([]codersdk.WorkspaceResource, 0)
@@ -147,17 +152,19 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 					dbApps = append(dbApps, app)
 				}
 			}
+			dbScripts := make([]database.WorkspaceAgentScript, 0)
+			for _, script := range scripts {
+				if script.AgentID == agent.ID {
+					dbScripts = append(dbScripts, script)
+				}
+			}
 
 			apiAgent, err := convertWorkspaceAgent(
-				api.DERPMap, *api.TailnetCoordinator.Load(), agent, convertApps(dbApps), api.AgentInactiveDisconnectTimeout,
+				api.DERPMap, *api.TailnetCoordinator.Load(), agent, convertApps(dbApps), convertWorkspaceAgentScripts(dbScripts), api.AgentInactiveDisconnectTimeout,
 				api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 			)
 			if err != nil {
-				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-					Message: "Internal error reading job agent.",
-					Detail:  err.Error(),
-				})
-				return
+				return nil, xerrors.Errorf("convert workspace agent: %w", err)
 			}
 			agents = append(agents, apiAgent)
 		}
@@ -173,7 +180,7 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 		return apiResources[i].Name < apiResources[j].Name
 	})
 
-	httpapi.Write(ctx, rw, http.StatusOK, apiResources)
+	return apiResources, nil
 }
 
 func convertProvisionerJobLogs(provisionerJobLogs []database.ProvisionerJobLog) []codersdk.ProvisionerJobLog {
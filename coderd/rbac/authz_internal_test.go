@@ -1021,6 +1021,77 @@ func TestAuthorizeScope(t *testing.T) {
 			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me"), actions: []Action{ActionCreate}, allow: false},
 		},
 	)
+
+	// This scope allows all files, but only a single template.
+	templateID := uuid.New()
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleMember())),
+			must(RoleByName(RoleOrgAdmin(defOrg))),
+		},
+		Scope: must(RestrictedScope(ScopeTemplatePush, []string{
+			templateID.String(),
+			AllowAllOfType(ResourceFile),
+		})),
+	}
+
+	testAuthorize(t, "RestrictedTemplatePushScope", user,
+		// Other templates and resources of other types are not allowed.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []Action{ActionCreate, ActionRead, ActionUpdate}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceTemplate.InOrg(defOrg)},
+			{resource: ResourceTemplate.WithID(uuid.New()).InOrg(defOrg)},
+			{resource: ResourceWorkspace.WithID(templateID).InOrg(defOrg).WithOwner(user.ID)},
+		}),
+
+		// Files of any ID and the template are allowed.
+		[]authTestCase{
+			{resource: ResourceFile.WithOwner(user.ID), actions: []Action{ActionCreate, ActionRead}, allow: true},
+			{resource: ResourceFile.WithID(uuid.New()).WithOwner(user.ID), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []Action{ActionCreate, ActionRead, ActionUpdate}, allow: true},
+			// The scope does not include deleting templates.
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []Action{ActionDelete}, allow: false},
+		},
+	)
+
+	// This scope allows a single workspace, and the user and organization
+	// only by their type.
+	workspaceID = uuid.New()
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleMember())),
+			must(RoleByName(RoleOrgMember(defOrg))),
+		},
+		Scope: must(RestrictedScope(ScopeAll, []string{
+			workspaceID.String(),
+			AllowResource(ResourceUser, "me"),
+			AllowResource(ResourceOrganization, defOrg.String()),
+		})),
+	}
+
+	testAuthorize(t, "RestrictedTypedScope", user,
+		// Resources of other types that share the ID are not allowed.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []Action{ActionCreate, ActionRead, ActionUpdate, ActionDelete}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceUserData.WithIDString("me").WithOwner("me")},
+			{resource: ResourceOrganization.WithID(unusedID).InOrg(unusedID)},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID)},
+		}),
+
+		[]authTestCase{
+			{resource: ResourceUser.WithIDString("me"), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceOrganization.WithID(defOrg).InOrg(defOrg), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead, ActionUpdate}, allow: true},
+		},
+	)
 }
 
 // cases applies a given function to all test cases. This makes generalities easier to create.
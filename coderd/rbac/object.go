@@ -30,9 +30,10 @@ var (
 
 	// ResourceWorkspaceBuild refers to permissions necessary to
 	// insert a workspace build job.
-	// create/delete = ?
+	// create = start or stop a workspace
+	// delete = delete a workspace
 	// read = read workspace builds
-	// update = insert/update workspace builds.
+	// update = update workspace builds.
 	ResourceWorkspaceBuild = Object{
 		Type: "workspace_build",
 	}
@@ -69,6 +70,15 @@ var (
 		Type: "application_connect",
 	}
 
+	// ResourceWorkspaceSessionRecording CRUD. Org + User owner
+	//	create = upload a recording of a terminal session
+	// 	read = list and download recordings
+	//	update = ?
+	// 	delete = ?
+	ResourceWorkspaceSessionRecording = Object{
+		Type: "workspace_session_recording",
+	}
+
 	// ResourceAuditLog
 	// read = access audit log
 	ResourceAuditLog = Object{
@@ -124,6 +134,16 @@ var (
 		Type: "assign_org_role",
 	}
 
+	// ResourceCustomRole is a role defined by admins in the 'custom_roles'
+	// table. Site wide roles never have an org, organization roles are in the
+	// org they can be assigned in.
+	//	create/delete = define or remove a role
+	//	read = view the permissions of roles
+	//	update = change the display name or permissions of a role
+	ResourceCustomRole = Object{
+		Type: "custom_role",
+	}
+
 	// ResourceAPIKey is owned by a user.
 	//	create  = Create a new api key for user
 	//	update  = ??
@@ -166,6 +186,42 @@ var (
 		Type: "license",
 	}
 
+	// ResourceWebhook is an outbound webhook in the 'webhooks' table.
+	// ResourceWebhook is site wide.
+	//	create/delete = register or remove a webhook.
+	//	read = view webhooks and their deliveries
+	//	update = change the endpoint, secret or events of a webhook
+	ResourceWebhook = Object{
+		Type: "webhook",
+	}
+
+	// ResourceOAuth2ProviderApp is a third-party app in the
+	// 'oauth2_provider_apps' table that uses Coder as an OAuth2 provider.
+	// ResourceOAuth2ProviderApp is site wide.
+	//	create/delete = register or remove an app.
+	//	read = view apps, all users can see an app to authorize it
+	//	update = change the name, icon or callback URL of an app
+	ResourceOAuth2ProviderApp = Object{
+		Type: "oauth2_app",
+	}
+
+	// ResourceOAuth2ProviderAppSecret is a client secret of an OAuth2 app.
+	// ResourceOAuth2ProviderAppSecret is site wide.
+	//	create/delete = generate or revoke a client secret.
+	//	read = view the secrets of an app, never the secret itself
+	ResourceOAuth2ProviderAppSecret = Object{
+		Type: "oauth2_app_secret",
+	}
+
+	// ResourceOAuth2ProviderAppCodeToken is an authorization code or token
+	// an OAuth2 app holds on behalf of a user. It has an owner.
+	//	create = authorize an app
+	//	read = view the apps a user has authorized
+	//	delete = revoke the access of an app
+	ResourceOAuth2ProviderAppCodeToken = Object{
+		Type: "oauth2_app_code_token",
+	}
+
 	// ResourceDeploymentValues
 	ResourceDeploymentValues = Object{
 		Type: "deployment_config",
@@ -5,12 +5,16 @@ func AllResources() []Object {
 	return []Object{
 		ResourceAPIKey,
 		ResourceAuditLog,
+		ResourceCustomRole,
 		ResourceDebugInfo,
 		ResourceDeploymentStats,
 		ResourceDeploymentValues,
 		ResourceFile,
 		ResourceGroup,
 		ResourceLicense,
+		ResourceOAuth2ProviderApp,
+		ResourceOAuth2ProviderAppCodeToken,
+		ResourceOAuth2ProviderAppSecret,
 		ResourceOrgRoleAssignment,
 		ResourceOrganization,
 		ResourceOrganizationMember,
@@ -22,6 +26,7 @@ func AllResources() []Object {
 		ResourceTemplate,
 		ResourceUser,
 		ResourceUserData,
+		ResourceWebhook,
 		ResourceWildcard,
 		ResourceWorkspace,
 		ResourceWorkspaceApplicationConnect,
@@ -29,5 +34,6 @@ func AllResources() []Object {
 		ResourceWorkspaceExecution,
 		ResourceWorkspaceLocked,
 		ResourceWorkspaceProxy,
+		ResourceWorkspaceSessionRecording,
 	}
 }
@@ -161,6 +161,25 @@ scope_allow_list {
 	input.object.id in input.subject.scope.allow_list
 }
 
+scope_allow_list {
+	# Every resource of a type is allowed with '<type>:*'. The object type is
+	# always known, so partial compilations never include this.
+	not "*" in input.subject.scope.allow_list
+	concat(":", [input.object.type, "*"]) in input.subject.scope.allow_list
+}
+
+scope_allow_list {
+	# A single resource of a type is allowed with '<type>:<id>'. Unlike a bare
+	# ID, it does not allow other types of resources with the same ID.
+	not "*" in input.subject.scope.allow_list
+	some entry in input.subject.scope.allow_list
+	parts := split(entry, ":")
+	count(parts) == 2
+	parts[0] == input.object.type
+	parts[1] != "*"
+	parts[1] == input.object.id
+}
+
 # The allow block is quite simple. Any set with `-1` cascades down in levels.
 # Authorization looks for any `allow` statement that is true. Multiple can be true!
 # Note that the absence of `allow` means "unauthorized".
@@ -150,6 +150,8 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 			ResourceRoleAssignment.Type: {ActionRead},
 			// All users can see the provisioner daemons.
 			ResourceProvisionerDaemon.Type: {ActionRead},
+			// All users can see OAuth2 apps so they can authorize them.
+			ResourceOAuth2ProviderApp.Type: {ActionRead},
 		}),
 		Org:  map[string][]Permission{},
 		User: allPermsExcept(ResourceWorkspaceLocked),
@@ -310,6 +312,15 @@ var assignRoles = map[string]map[string]bool{
 	},
 }
 
+// assignCustomRoles is the set of roles that can assign custom roles. Custom
+// organization roles can only be assigned in the organization of the actor
+// role.
+var assignCustomRoles = map[string]bool{
+	"system": true,
+	owner:    true,
+	orgAdmin: true,
+}
+
 // ExpandableRoles is any type that can be expanded into a []Role. This is implemented
 // as an interface so we can have RoleNames for user defined roles, and implement
 // custom ExpandableRoles for system type users (eg autostart/autostop system role).
@@ -366,7 +377,7 @@ func (roles Roles) Expand() ([]Role, error) {
 func (roles Roles) Names() []string {
 	names := make([]string, 0, len(roles))
 	for _, r := range roles {
-		return append(names, r.Name)
+		names = append(names, r.Name)
 	}
 	return names
 }
@@ -402,6 +413,10 @@ func CanAssignRole(expandable ExpandableRoles, assignedRole string) bool {
 		if allowed[assigned] {
 			return true
 		}
+
+		if !IsBuiltInRole(assigned) && assignCustomRoles[role] {
+			return true
+		}
 	}
 	return false
 }
@@ -434,6 +449,34 @@ func RoleByName(name string) (Role, error) {
 	return role, nil
 }
 
+// IsBuiltInRole returns true if the role name is a built-in role. Any other
+// role name is a custom role stored in the database.
+func IsBuiltInRole(name string) bool {
+	roleName, _, err := roleSplit(name)
+	if err != nil {
+		return false
+	}
+	if _, ok := builtInRoles[roleName]; ok {
+		return true
+	}
+	// The system role is never assigned, but a custom role must not take its
+	// name either.
+	_, ok := assignRoles[roleName]
+	return ok
+}
+
+// SplitRoleName returns the name and organization ID of a role. The
+// organization ID is empty for site wide roles.
+func SplitRoleName(role string) (name string, orgID string, err error) {
+	return roleSplit(role)
+}
+
+// RoleName returns the full name of a role as it is assigned to users. The
+// organization ID is empty for site wide roles.
+func RoleName(name string, orgID string) string {
+	return roleName(name, orgID)
+}
+
 func rolesByNames(roleNames []string) ([]Role, error) {
 	roles := make([]Role, 0, len(roleNames))
 	for _, n := range roleNames {
@@ -327,6 +327,15 @@ func TestRolePermissions(t *testing.T) {
 				false: {memberMe, orgAdmin, userAdmin, otherOrgAdmin, otherOrgMember, orgMemberMe, owner, templateAdmin},
 			},
 		},
+		{
+			Name:     "MyWorkspaceInOrgSessionRecording",
+			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionRead},
+			Resource: rbac.ResourceWorkspaceSessionRecording.WithID(workspaceID).InOrg(orgID).WithOwner(currentUser.String()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin, orgMemberMe},
+				false: {memberMe, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
 		{
 			Name:     "WorkspaceBuild",
 			Actions:  rbac.AllActions(),
@@ -336,6 +345,69 @@ func TestRolePermissions(t *testing.T) {
 				false: {userAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, memberMe},
 			},
 		},
+		{
+			Name:     "Webhook",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceWebhook.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "OAuth2ProviderAppRead",
+			Actions:  []rbac.Action{rbac.ActionRead},
+			Resource: rbac.ResourceOAuth2ProviderApp.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+				false: {},
+			},
+		},
+		{
+			Name:     "OAuth2ProviderAppManage",
+			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+			Resource: rbac.ResourceOAuth2ProviderApp.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "OAuth2ProviderAppSecret",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceOAuth2ProviderAppSecret.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "OAuth2ProviderAppCodeToken",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceOAuth2ProviderAppCodeToken.WithID(uuid.New()).WithOwner(currentUser.String()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, memberMe, orgMemberMe},
+				false: {orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "CustomSiteRole",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceCustomRole.WithID(uuid.New()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner},
+				false: {memberMe, orgMemberMe, orgAdmin, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
+		{
+			Name:     "CustomOrgRole",
+			Actions:  rbac.AllActions(),
+			Resource: rbac.ResourceCustomRole.WithID(uuid.New()).InOrg(orgID),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin},
+				false: {memberMe, orgMemberMe, otherOrgAdmin, otherOrgMember, templateAdmin, userAdmin},
+			},
+		},
 	}
 
 	for _, c := range testCases {
@@ -426,6 +498,39 @@ func TestIsOrgRole(t *testing.T) {
 	}
 }
 
+func TestCanAssignCustomRole(t *testing.T) {
+	t.Parallel()
+	orgID := uuid.New()
+	otherOrg := uuid.New()
+
+	testCases := []struct {
+		Name       string
+		Actor      rbac.RoleNames
+		Assigned   string
+		Assignable bool
+	}{
+		{Name: "OwnerSiteRole", Actor: rbac.RoleNames{rbac.RoleOwner()}, Assigned: "viewer", Assignable: true},
+		{Name: "OwnerOrgRole", Actor: rbac.RoleNames{rbac.RoleOwner()}, Assigned: rbac.RoleName("viewer", orgID.String()), Assignable: true},
+		{Name: "UserAdminSiteRole", Actor: rbac.RoleNames{rbac.RoleUserAdmin()}, Assigned: "viewer"},
+		{Name: "OrgAdminSiteRole", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(orgID)}, Assigned: "viewer"},
+		{Name: "OrgAdminOrgRole", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(orgID)}, Assigned: rbac.RoleName("viewer", orgID.String()), Assignable: true},
+		{Name: "OrgAdminOtherOrgRole", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(orgID)}, Assigned: rbac.RoleName("viewer", otherOrg.String())},
+		{Name: "MemberSiteRole", Actor: rbac.RoleNames{rbac.RoleMember()}, Assigned: "viewer"},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.Assignable, rbac.CanAssignRole(c.Actor, c.Assigned))
+		})
+	}
+
+	require.True(t, rbac.IsBuiltInRole(rbac.RoleOrgAdmin(orgID)))
+	require.True(t, rbac.IsBuiltInRole("system"))
+	require.False(t, rbac.IsBuiltInRole("viewer"))
+}
+
 func TestListRoles(t *testing.T) {
 	t.Parallel()
 
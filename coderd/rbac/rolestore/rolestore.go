@@ -0,0 +1,113 @@
+// Package rolestore expands role names that include custom roles. Built-in
+// roles are defined by the rbac package, custom roles are stored in the
+// database and compiled into an rbac.Role when they are used.
+package rolestore
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/rbac"
+)
+
+// Expand returns the roles of the given names for an rbac.Subject. An error is
+// returned if any of the roles does not exist.
+//
+// The database is only queried if a custom role is included. In that case
+// the compiled roles are returned, otherwise the names are expanded by the
+// rbac package when authorizing.
+func Expand(ctx context.Context, db database.Store, names []string) (rbac.ExpandableRoles, error) {
+	customNames := make([]string, 0)
+	for _, name := range names {
+		if !rbac.IsBuiltInRole(name) {
+			customNames = append(customNames, name)
+			continue
+		}
+		if _, err := rbac.RoleByName(name); err != nil {
+			return nil, xerrors.Errorf("%q is not a supported role", name)
+		}
+	}
+	if len(customNames) == 0 {
+		return rbac.RoleNames(names), nil
+	}
+
+	customRoles, err := CustomRoles(ctx, db, customNames)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(rbac.Roles, 0, len(names))
+	for _, name := range names {
+		if rbac.IsBuiltInRole(name) {
+			role, err := rbac.RoleByName(name)
+			if err != nil {
+				return nil, xerrors.Errorf("%q is not a supported role", name)
+			}
+			roles = append(roles, role)
+			continue
+		}
+		customRole, ok := customRoles[name]
+		if !ok {
+			return nil, xerrors.Errorf("%q is not a supported role", name)
+		}
+		role, err := Role(customRole)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// CustomRoles returns the custom roles of the given names, keyed by the name
+// they are assigned by. Names of roles that don't exist are left out.
+func CustomRoles(ctx context.Context, db database.Store, names []string) (map[string]database.CustomRole, error) {
+	baseNames := make([]string, 0, len(names))
+	for _, name := range names {
+		baseName, _, err := rbac.SplitRoleName(name)
+		if err != nil {
+			return nil, xerrors.Errorf("%q is not a supported role", name)
+		}
+		baseNames = append(baseNames, baseName)
+	}
+	if len(baseNames) == 0 {
+		return map[string]database.CustomRole{}, nil
+	}
+
+	customRoles, err := db.GetCustomRoles(ctx, baseNames)
+	if err != nil {
+		return nil, xerrors.Errorf("get custom roles: %w", err)
+	}
+	roles := make(map[string]database.CustomRole, len(customRoles))
+	for _, role := range customRoles {
+		roles[role.RoleName()] = role
+	}
+	return roles, nil
+}
+
+// Role compiles a custom role into an rbac.Role. The permissions of a site
+// wide role apply everywhere, the permissions of an organization role apply
+// to its organization.
+func Role(customRole database.CustomRole) (rbac.Role, error) {
+	perms := []rbac.Permission{}
+	err := json.Unmarshal(customRole.Permissions, &perms)
+	if err != nil {
+		return rbac.Role{}, xerrors.Errorf("decode permissions of role %q: %w", customRole.RoleName(), err)
+	}
+
+	role := rbac.Role{
+		Name:        customRole.RoleName(),
+		DisplayName: customRole.DisplayName,
+		Site:        []rbac.Permission{},
+		Org:         map[string][]rbac.Permission{},
+		User:        []rbac.Permission{},
+	}
+	if customRole.OrganizationID.Valid {
+		role.Org[customRole.OrganizationID.UUID.String()] = perms
+	} else {
+		role.Site = perms
+	}
+	return role, nil
+}
@@ -31,12 +31,40 @@ func WorkspaceAgentScope(workspaceID, ownerID uuid.UUID) Scope {
 	}
 }
 
+// AllowAllOfType returns the allow list entry that allows every resource of
+// the given type, no matter its ID.
+func AllowAllOfType(resource Object) string {
+	return resource.Type + ":" + WildcardSymbol
+}
+
+// AllowResource returns the allow list entry that allows a single resource
+// of the given type. Unlike the bare ID, it does not allow resources of other
+// types that share the ID.
+func AllowResource(resource Object, id string) string {
+	return resource.Type + ":" + id
+}
+
+// RestrictedScope returns the builtin scope with an allow list that only
+// contains the given entries. Entries are resource IDs or the result of
+// AllowAllOfType or AllowResource.
+func RestrictedScope(name ScopeName, allowList []string) (Scope, error) {
+	scope, err := ExpandScope(name)
+	if err != nil {
+		return Scope{}, err
+	}
+	scope.AllowIDList = allowList
+	return scope, nil
+}
+
 const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
+	ScopeReadOnly           ScopeName = "read_only"
+	ScopeWorkspaceStartStop ScopeName = "workspace_start_stop"
+	ScopeTemplatePush       ScopeName = "template_push"
+	ScopeUserRead           ScopeName = "user_read"
 )
 
-// TODO: Support passing in scopeID list for allowlisting resources.
 var builtinScopes = map[ScopeName]Scope{
 	// ScopeAll is a special scope that allows access to all resources. During
 	// authorize checks it is usually not used directly and skips scope checks.
@@ -65,6 +93,69 @@ var builtinScopes = map[ScopeName]Scope{
 		},
 		AllowIDList: []string{WildcardSymbol},
 	},
+
+	ScopeReadOnly: {
+		Role: Role{
+			Name:        fmt.Sprintf("Scope_%s", ScopeReadOnly),
+			DisplayName: "Read only access",
+			Site: Permissions(map[string][]Action{
+				ResourceWildcard.Type: {ActionRead},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
+		AllowIDList: []string{WildcardSymbol},
+	},
+
+	ScopeWorkspaceStartStop: {
+		Role: Role{
+			Name:        fmt.Sprintf("Scope_%s", ScopeWorkspaceStartStop),
+			DisplayName: "Ability to start and stop workspaces",
+			Site: Permissions(map[string][]Action{
+				// Starting and stopping creates a build. Changing or
+				// deleting the workspace is not allowed.
+				ResourceWorkspace.Type:          {ActionRead},
+				ResourceWorkspaceBuild.Type:     {ActionRead, ActionCreate},
+				ResourceTemplate.Type:           {ActionRead},
+				ResourceUser.Type:               {ActionRead},
+				ResourceOrganization.Type:       {ActionRead},
+				ResourceOrganizationMember.Type: {ActionRead},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
+		AllowIDList: []string{WildcardSymbol},
+	},
+
+	ScopeTemplatePush: {
+		Role: Role{
+			Name:        fmt.Sprintf("Scope_%s", ScopeTemplatePush),
+			DisplayName: "Ability to push new template versions",
+			Site: Permissions(map[string][]Action{
+				ResourceTemplate.Type:           {ActionRead, ActionCreate, ActionUpdate},
+				ResourceFile.Type:               {ActionRead, ActionCreate},
+				ResourceUser.Type:               {ActionRead},
+				ResourceOrganization.Type:       {ActionRead},
+				ResourceOrganizationMember.Type: {ActionRead},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
+		AllowIDList: []string{WildcardSymbol},
+	},
+
+	ScopeUserRead: {
+		Role: Role{
+			Name:        fmt.Sprintf("Scope_%s", ScopeUserRead),
+			DisplayName: "Ability to read users",
+			Site: Permissions(map[string][]Action{
+				ResourceUser.Type: {ActionRead},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
+		AllowIDList: []string{WildcardSymbol},
+	},
 }
 
 type ExpandableScope interface {
@@ -1,13 +1,19 @@
 package coderd
 
 import (
+	"context"
 	"net/http"
 
-	"github.com/coder/coder/coderd/httpmw"
-	"github.com/coder/coder/codersdk"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
+	"github.com/coder/coder/codersdk"
 )
 
 // assignableSiteRoles returns all site wide roles that can be assigned.
@@ -28,6 +34,15 @@ func (api *API) assignableSiteRoles(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	roles := rbac.SiteRoles()
+	customRoles, err := api.customRoles(ctx, uuid.NullUUID{})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	roles = append(roles, customRoles...)
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
 }
 
@@ -52,9 +67,56 @@ func (api *API) assignableOrgRoles(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	roles := rbac.OrganizationRoles(organization.ID)
+	customRoles, err := api.customRoles(ctx, uuid.NullUUID{UUID: organization.ID, Valid: true})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	roles = append(roles, customRoles...)
 	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
 }
 
+// userSubject returns the subject to authorize as a user with all of their
+// roles. Custom roles are expanded from the database.
+func (api *API) userSubject(ctx context.Context, roles database.GetAuthorizationUserRolesRow) (rbac.Subject, error) {
+	//nolint:gocritic // Custom roles are expanded for the user.
+	actorRoles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), api.Database, roles.Roles)
+	if err != nil {
+		return rbac.Subject{}, xerrors.Errorf("expand roles: %w", err)
+	}
+	return rbac.Subject{
+		ID:     roles.ID.String(),
+		Roles:  actorRoles,
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}, nil
+}
+
+// customRoles returns the compiled custom roles of an organization, or the
+// site wide custom roles if no organization is given.
+func (api *API) customRoles(ctx context.Context, orgID uuid.NullUUID) ([]rbac.Role, error) {
+	//nolint:gocritic // Users that can read role assignments can see all roles.
+	customRoles, err := api.Database.GetCustomRoles(dbauthz.AsSystemRestricted(ctx), []string{})
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]rbac.Role, 0, len(customRoles))
+	for _, customRole := range customRoles {
+		if customRole.OrganizationID != orgID {
+			continue
+		}
+		role, err := rolestore.Role(customRole)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
 func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []codersdk.AssignableRoles {
 	assignable := make([]codersdk.AssignableRoles, 0)
 	for _, role := range roles {
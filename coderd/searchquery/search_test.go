@@ -253,6 +253,15 @@ func TestSearchAudit(t *testing.T) {
 				ResourceTarget: "foo",
 			},
 		},
+		{
+			Name:  "Connections",
+			Query: "action:connect resource_type:workspace_agent connection_type:ssh",
+			Expected: database.GetAuditLogsOffsetParams{
+				Action:         string(database.AuditActionConnect),
+				ResourceType:   string(database.ResourceTypeWorkspaceAgent),
+				ConnectionType: string(codersdk.ConnectionTypeSSH),
+			},
+		},
 	}
 
 	for _, c := range testCases {
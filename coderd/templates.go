@@ -17,6 +17,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/prebuilds"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
@@ -542,6 +543,12 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.LockedTTLMillis < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "locked_ttl_ms", Detail: "Must be a positive integer."})
 	}
+	if req.PrebuiltWorkspaces < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "prebuilt_workspaces", Detail: "Must be a positive integer."})
+	}
+	if req.PrebuiltWorkspaces > prebuilds.MaxPoolSize {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "prebuilt_workspaces", Detail: fmt.Sprintf("Must be less than or equal to %d.", prebuilds.MaxPoolSize)})
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -566,7 +573,9 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.RestartRequirement.Weeks == scheduleOpts.RestartRequirement.Weeks &&
 			req.FailureTTLMillis == time.Duration(template.FailureTTL).Milliseconds() &&
 			req.InactivityTTLMillis == time.Duration(template.InactivityTTL).Milliseconds() &&
-			req.LockedTTLMillis == time.Duration(template.LockedTTL).Milliseconds() {
+			req.LockedTTLMillis == time.Duration(template.LockedTTL).Milliseconds() &&
+			req.RecordSessions == template.RecordSessions &&
+			req.PrebuiltWorkspaces == template.PrebuiltWorkspaces {
 			return nil
 		}
 
@@ -585,6 +594,8 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			Description:                  req.Description,
 			Icon:                         req.Icon,
 			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
+			RecordSessions:               req.RecordSessions,
+			PrebuiltWorkspaces:           req.PrebuiltWorkspaces,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -762,5 +773,7 @@ func (api *API) convertTemplate(
 			DaysOfWeek: codersdk.BitmapToWeekdays(uint8(template.RestartRequirementDaysOfWeek)),
 			Weeks:      template.RestartRequirementWeeks,
 		},
+		RecordSessions:     template.RecordSessions,
+		PrebuiltWorkspaces: template.PrebuiltWorkspaces,
 	}
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/prebuilds"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
@@ -569,6 +570,27 @@ func TestPatchTemplateMeta(t *testing.T) {
 		assert.Equal(t, updated.DefaultTTLMillis, template.DefaultTTLMillis)
 	})
 
+	t.Run("PrebuiltWorkspacesTooMany", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			PrebuiltWorkspaces: prebuilds.MaxPoolSize + 1,
+		})
+		require.ErrorContains(t, err, "prebuilt_workspaces: Must be less than or equal to")
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		assert.Zero(t, updated.PrebuiltWorkspaces)
+	})
+
 	t.Run("MaxTTL", func(t *testing.T) {
 		t.Parallel()
 
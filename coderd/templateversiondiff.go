@@ -0,0 +1,473 @@
+package coderd
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pkg/diff"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get diff between template versions
+// @ID get-diff-between-template-versions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID to compare from" format(uuid)
+// @Param othertemplateversion path string true "Template version ID to compare to" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionDiff
+// @Router /templateversions/{templateversion}/diff/{othertemplateversion} [get]
+func (api *API) templateVersionDiff(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	fromVersion := httpmw.TemplateVersionParam(r)
+
+	toVersionID, ok := httpmw.ParseUUIDParam(rw, r, "othertemplateversion")
+	if !ok {
+		return
+	}
+	toVersion, err := api.Database.GetTemplateVersionByID(ctx, toVersionID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	from, err := api.fetchTemplateVersionContents(ctx, fromVersion)
+	if err != nil {
+		writeTemplateVersionContentsError(rw, r, err)
+		return
+	}
+	to, err := api.fetchTemplateVersionContents(ctx, toVersion)
+	if err != nil {
+		writeTemplateVersionContentsError(rw, r, err)
+		return
+	}
+
+	files, err := diffTemplateVersionFiles(from.files, to.files)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error comparing template files.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TemplateVersionDiff{
+		FromVersionID: fromVersion.ID,
+		ToVersionID:   toVersion.ID,
+		Files:         files,
+		Variables:     diffTemplateVersionVariables(from.variables, to.variables),
+		Parameters:    diffTemplateVersionParameters(from.parameters, to.parameters),
+		Resources:     diffTemplateVersionResources(from.resources, to.resources),
+	})
+}
+
+// errTemplateVersionJobIncomplete is returned when the import job of a
+// template version has not completed, so there is nothing to compare yet.
+var errTemplateVersionJobIncomplete = xerrors.New("template version job hasn't completed")
+
+// templateVersionContents is everything about a template version that can be
+// compared.
+type templateVersionContents struct {
+	files      map[string][]byte
+	variables  []database.TemplateVersionVariable
+	parameters []codersdk.TemplateVersionParameter
+	resources  []codersdk.WorkspaceResource
+}
+
+func (api *API) fetchTemplateVersionContents(ctx context.Context, templateVersion database.TemplateVersion) (templateVersionContents, error) {
+	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		return templateVersionContents{}, xerrors.Errorf("get provisioner job: %w", err)
+	}
+	if !job.CompletedAt.Valid {
+		return templateVersionContents{}, errTemplateVersionJobIncomplete
+	}
+
+	// Reading the files requires permission to update the template, just
+	// like downloading them.
+	file, err := api.Database.GetFileByID(ctx, job.FileID)
+	if err != nil {
+		return templateVersionContents{}, xerrors.Errorf("get file: %w", err)
+	}
+	files, err := readTemplateArchive(file.Data)
+	if err != nil {
+		return templateVersionContents{}, xerrors.Errorf("read template archive: %w", err)
+	}
+
+	variables, err := api.Database.GetTemplateVersionVariables(ctx, templateVersion.ID)
+	if err != nil {
+		return templateVersionContents{}, xerrors.Errorf("get template version variables: %w", err)
+	}
+	dbParameters, err := api.Database.GetTemplateVersionParameters(ctx, templateVersion.ID)
+	if err != nil {
+		return templateVersionContents{}, xerrors.Errorf("get template version parameters: %w", err)
+	}
+	parameters, err := convertTemplateVersionParameters(dbParameters)
+	if err != nil {
+		return templateVersionContents{}, xerrors.Errorf("convert template version parameters: %w", err)
+	}
+	resources, err := api.fetchProvisionerJobResources(ctx, job)
+	if err != nil {
+		return templateVersionContents{}, err
+	}
+
+	return templateVersionContents{
+		files:      files,
+		variables:  variables,
+		parameters: parameters,
+		resources:  resources,
+	}, nil
+}
+
+func writeTemplateVersionContentsError(rw http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	switch {
+	case errors.Is(err, errTemplateVersionJobIncomplete):
+		httpapi.Write(ctx, rw, http.StatusPreconditionFailed, codersdk.Response{
+			Message: "Job hasn't completed!",
+		})
+	case httpapi.Is404Error(err):
+		httpapi.ResourceNotFound(rw)
+	default:
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version contents.",
+			Detail:  err.Error(),
+		})
+	}
+}
+
+// readTemplateArchive returns the contents of the regular files in a tar
+// archive by their cleaned path.
+func readTemplateArchive(data []byte) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	reader := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", header.Name, err)
+		}
+		files[path.Clean("/" + header.Name)[1:]] = content
+	}
+	return files, nil
+}
+
+func diffTemplateVersionFiles(from, to map[string][]byte) ([]codersdk.TemplateVersionFileDiff, error) {
+	diffs := make([]codersdk.TemplateVersionFileDiff, 0)
+	for _, name := range unionKeys(from, to) {
+		fromContent, inFrom := from[name]
+		toContent, inTo := to[name]
+		fileDiff := codersdk.TemplateVersionFileDiff{
+			Path:   name,
+			Change: diffChange(inFrom, inTo),
+		}
+		if inFrom && inTo && bytes.Equal(fromContent, toContent) {
+			continue
+		}
+		if isBinary(fromContent) || isBinary(toContent) {
+			fileDiff.Binary = true
+			diffs = append(diffs, fileDiff)
+			continue
+		}
+
+		fromName, toName := "a/"+name, "b/"+name
+		if !inFrom {
+			fromName = "/dev/null"
+		}
+		if !inTo {
+			toName = "/dev/null"
+		}
+		// The contents must never be nil, otherwise they are read from the
+		// file system by name.
+		var buf bytes.Buffer
+		err := diff.Text(fromName, toName, string(fromContent), string(toContent), &buf)
+		if err != nil {
+			return nil, xerrors.Errorf("diff %q: %w", name, err)
+		}
+		fileDiff.Diff = buf.String()
+		diffs = append(diffs, fileDiff)
+	}
+	return diffs, nil
+}
+
+func diffTemplateVersionVariables(from, to []database.TemplateVersionVariable) []codersdk.TemplateVersionVariableDiff {
+	fromByName := map[string]database.TemplateVersionVariable{}
+	for _, variable := range from {
+		fromByName[variable.Name] = variable
+	}
+	toByName := map[string]database.TemplateVersionVariable{}
+	for _, variable := range to {
+		toByName[variable.Name] = variable
+	}
+
+	diffs := make([]codersdk.TemplateVersionVariableDiff, 0)
+	for _, name := range unionKeys(fromByName, toByName) {
+		fromVariable, inFrom := fromByName[name]
+		toVariable, inTo := toByName[name]
+		variableDiff := codersdk.TemplateVersionVariableDiff{
+			Name:   name,
+			Change: diffChange(inFrom, inTo),
+			Fields: []string{},
+		}
+		if inFrom && inTo {
+			// Sensitive values are compared before they are redacted, so
+			// that changes to them are reported without revealing them.
+			variableDiff.Fields = changedFields(fromVariable, toVariable,
+				"description", "type", "value", "default_value", "required", "sensitive")
+			if len(variableDiff.Fields) == 0 {
+				continue
+			}
+		}
+		if inFrom {
+			v := convertTemplateVersionVariable(fromVariable)
+			variableDiff.From = &v
+		}
+		if inTo {
+			v := convertTemplateVersionVariable(toVariable)
+			variableDiff.To = &v
+		}
+		diffs = append(diffs, variableDiff)
+	}
+	return diffs
+}
+
+func diffTemplateVersionParameters(from, to []codersdk.TemplateVersionParameter) []codersdk.TemplateVersionParameterDiff {
+	fromByName := map[string]codersdk.TemplateVersionParameter{}
+	for _, param := range from {
+		fromByName[param.Name] = param
+	}
+	toByName := map[string]codersdk.TemplateVersionParameter{}
+	for _, param := range to {
+		toByName[param.Name] = param
+	}
+
+	diffs := make([]codersdk.TemplateVersionParameterDiff, 0)
+	for _, name := range unionKeys(fromByName, toByName) {
+		fromParam, inFrom := fromByName[name]
+		toParam, inTo := toByName[name]
+		paramDiff := codersdk.TemplateVersionParameterDiff{
+			Name:   name,
+			Change: diffChange(inFrom, inTo),
+			Fields: []string{},
+		}
+		if inFrom && inTo {
+			paramDiff.Fields = changedFields(fromParam, toParam)
+			if len(paramDiff.Fields) == 0 {
+				continue
+			}
+		}
+		if inFrom {
+			paramDiff.From = &fromParam
+		}
+		if inTo {
+			paramDiff.To = &toParam
+		}
+		diffs = append(diffs, paramDiff)
+	}
+	return diffs
+}
+
+// templateVersionAgentFields are the fields of an agent that are defined by
+// the template, as opposed to being reported by a running agent.
+var templateVersionAgentFields = []string{
+	"architecture",
+	"operating_system",
+	"environment_variables",
+	"directory",
+	"startup_script",
+	"startup_script_behavior",
+	"startup_script_timeout_seconds",
+	"shutdown_script",
+	"shutdown_script_timeout_seconds",
+	"connection_timeout_seconds",
+	"troubleshooting_url",
+	"apps",
+	"scripts",
+}
+
+func diffTemplateVersionResources(from, to []codersdk.WorkspaceResource) []codersdk.TemplateVersionResourceDiff {
+	key := func(resource codersdk.WorkspaceResource) string {
+		return resource.Type + "." + resource.Name
+	}
+	fromByKey := map[string]codersdk.WorkspaceResource{}
+	for _, resource := range from {
+		fromByKey[key(resource)] = resource
+	}
+	toByKey := map[string]codersdk.WorkspaceResource{}
+	for _, resource := range to {
+		toByKey[key(resource)] = resource
+	}
+
+	diffs := make([]codersdk.TemplateVersionResourceDiff, 0)
+	for _, k := range unionKeys(fromByKey, toByKey) {
+		fromResource, inFrom := fromByKey[k]
+		toResource, inTo := toByKey[k]
+		resource := toResource
+		if !inTo {
+			resource = fromResource
+		}
+		resourceDiff := codersdk.TemplateVersionResourceDiff{
+			Type:   resource.Type,
+			Name:   resource.Name,
+			Change: diffChange(inFrom, inTo),
+			Fields: changedFields(fromResource, toResource, "hide", "icon", "daily_cost", "metadata"),
+			Agents: diffTemplateVersionAgents(fromResource.Agents, toResource.Agents),
+		}
+		if !inFrom || !inTo {
+			resourceDiff.Fields = []string{}
+		} else if len(resourceDiff.Fields) == 0 && len(resourceDiff.Agents) == 0 {
+			continue
+		}
+		diffs = append(diffs, resourceDiff)
+	}
+	return diffs
+}
+
+func diffTemplateVersionAgents(from, to []codersdk.WorkspaceAgent) []codersdk.TemplateVersionAgentDiff {
+	fromByName := map[string]codersdk.WorkspaceAgent{}
+	for _, agent := range from {
+		fromByName[agent.Name] = normalizeTemplateVersionAgent(agent)
+	}
+	toByName := map[string]codersdk.WorkspaceAgent{}
+	for _, agent := range to {
+		toByName[agent.Name] = normalizeTemplateVersionAgent(agent)
+	}
+
+	diffs := make([]codersdk.TemplateVersionAgentDiff, 0)
+	for _, name := range unionKeys(fromByName, toByName) {
+		fromAgent, inFrom := fromByName[name]
+		toAgent, inTo := toByName[name]
+		agentDiff := codersdk.TemplateVersionAgentDiff{
+			Name:   name,
+			Change: diffChange(inFrom, inTo),
+			Fields: []string{},
+		}
+		if inFrom && inTo {
+			agentDiff.Fields = changedFields(fromAgent, toAgent, templateVersionAgentFields...)
+			if len(agentDiff.Fields) == 0 {
+				continue
+			}
+		}
+		diffs = append(diffs, agentDiff)
+	}
+	return diffs
+}
+
+// normalizeTemplateVersionAgent clears the fields of apps and scripts that
+// differ between every import, so that only changes to the template remain.
+func normalizeTemplateVersionAgent(agent codersdk.WorkspaceAgent) codersdk.WorkspaceAgent {
+	apps := make([]codersdk.WorkspaceApp, 0, len(agent.Apps))
+	for _, app := range agent.Apps {
+		app.ID = uuid.Nil
+		app.Health = ""
+		apps = append(apps, app)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Slug < apps[j].Slug
+	})
+	agent.Apps = apps
+
+	scripts := make([]codersdk.WorkspaceAgentScript, 0, len(agent.Scripts))
+	for _, script := range agent.Scripts {
+		script.ID = uuid.Nil
+		script.Status = ""
+		script.ExitCode = nil
+		script.StartedAt = nil
+		script.EndedAt = nil
+		scripts = append(scripts, script)
+	}
+	agent.Scripts = scripts
+	return agent
+}
+
+func diffChange(inFrom, inTo bool) codersdk.TemplateVersionDiffChange {
+	switch {
+	case !inFrom:
+		return codersdk.TemplateVersionDiffChangeAdded
+	case !inTo:
+		return codersdk.TemplateVersionDiffChangeRemoved
+	default:
+		return codersdk.TemplateVersionDiffChangeModified
+	}
+}
+
+// changedFields returns the sorted JSON names of the fields that differ
+// between from and to. If no fields are given, all fields except "name" are
+// compared.
+func changedFields(from, to any, fields ...string) []string {
+	fromFields, toFields := jsonFields(from), jsonFields(to)
+	if len(fields) == 0 {
+		for _, field := range unionKeys(fromFields, toFields) {
+			if field != "name" {
+				fields = append(fields, field)
+			}
+		}
+	}
+	changed := []string{}
+	for _, field := range fields {
+		if !bytes.Equal(fromFields[field], toFields[field]) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func jsonFields(v any) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+// isBinary reports whether content does not look like text.
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) != -1 || !utf8.Valid(content)
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,8 +1,11 @@
 package coderd_test
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"regexp"
 	"strings"
@@ -19,6 +22,7 @@ import (
 	"github.com/coder/coder/coderd/gitauth"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/examples"
 	"github.com/coder/coder/provisioner/echo"
@@ -1473,3 +1477,233 @@ func TestTemplateVersionParameters_Order(t *testing.T) {
 	require.Equal(t, secondParameterName, templateRichParameters[3].Name)
 	require.Equal(t, thirdParameterName, templateRichParameters[4].Name)
 }
+
+func TestTemplateVersionDiff(t *testing.T) {
+	t.Parallel()
+
+	// createVersion uploads the echo responses together with the given
+	// files, which the echo provisioner ignores.
+	createVersion := func(t *testing.T, client *codersdk.Client, organizationID, templateID uuid.UUID, responses *echo.Responses, files map[string]string) codersdk.TemplateVersion {
+		t.Helper()
+		data, err := echo.Tar(responses)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		writer := tar.NewWriter(&buf)
+		reader := tar.NewReader(bytes.NewReader(data))
+		for {
+			header, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteHeader(header))
+			_, err = io.Copy(writer, reader)
+			require.NoError(t, err)
+		}
+		for name, content := range files {
+			require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Mode: 0o644}))
+			_, err = writer.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		file, err := client.Upload(ctx, codersdk.ContentTypeTar, &buf)
+		require.NoError(t, err)
+		version, err := client.CreateTemplateVersion(ctx, organizationID, codersdk.CreateTemplateVersionRequest{
+			TemplateID:    templateID,
+			FileID:        file.ID,
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		return version
+	}
+	responses := func(variables []*proto.TemplateVariable, parameters []*proto.RichParameter, resources []*proto.Resource) *echo.Responses {
+		return &echo.Responses{
+			Parse: []*proto.Parse_Response{{
+				Type: &proto.Parse_Response_Complete{
+					Complete: &proto.Parse_Complete{
+						TemplateVariables: variables,
+					},
+				},
+			}},
+			ProvisionPlan: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Parameters: parameters,
+						Resources:  resources,
+					},
+				},
+			}},
+			ProvisionApply: echo.ProvisionComplete,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+
+		from := createVersion(t, client, user.OrganizationID, uuid.Nil, responses(
+			[]*proto.TemplateVariable{
+				{Name: "region", Type: "string", DefaultValue: "us"},
+				{Name: "token", Type: "string", DefaultValue: "secret", Sensitive: true},
+				{Name: "unchanged", Type: "string", DefaultValue: "same"},
+			},
+			[]*proto.RichParameter{
+				{Name: "cpu", Type: "number", DefaultValue: "2"},
+				{Name: "legacy", Type: "string", DefaultValue: "x"},
+			},
+			[]*proto.Resource{{
+				Name: "dev",
+				Type: "compute",
+				Agents: []*proto.Agent{{
+					Id:              uuid.NewString(),
+					Name:            "main",
+					OperatingSystem: "linux",
+					Architecture:    "amd64",
+					Auth:            &proto.Agent_Token{Token: uuid.NewString()},
+				}},
+			}},
+		), map[string]string{
+			"main.tf":   "resource \"compute\" \"dev\" {\n  size = 1\n}\n",
+			"old.tf":    "# removed\n",
+			"image.png": "\x00\x01",
+			"same.txt":  "unchanged\n",
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, from.ID)
+
+		to := createVersion(t, client, user.OrganizationID, template.ID, responses(
+			[]*proto.TemplateVariable{
+				{Name: "region", Type: "string", DefaultValue: "eu"},
+				{Name: "token", Type: "string", DefaultValue: "rotated", Sensitive: true},
+				{Name: "unchanged", Type: "string", DefaultValue: "same"},
+			},
+			[]*proto.RichParameter{
+				{Name: "cpu", Type: "number", DefaultValue: "4", ValidationMin: ptr.Ref(int32(1)), ValidationMax: ptr.Ref(int32(8))},
+				{Name: "disk", Type: "number", DefaultValue: "10"},
+			},
+			[]*proto.Resource{{
+				Name: "dev",
+				Type: "compute",
+				Agents: []*proto.Agent{{
+					Id:              uuid.NewString(),
+					Name:            "main",
+					OperatingSystem: "linux",
+					Architecture:    "arm64",
+					Auth:            &proto.Agent_Token{Token: uuid.NewString()},
+				}},
+			}, {
+				Name: "cache",
+				Type: "volume",
+			}},
+		), map[string]string{
+			"main.tf":   "resource \"compute\" \"dev\" {\n  size = 2\n}\n",
+			"image.png": "\x00\x02",
+			"same.txt":  "unchanged\n",
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		diff, err := client.TemplateVersionDiff(ctx, from.ID, to.ID)
+		require.NoError(t, err)
+		require.Equal(t, from.ID, diff.FromVersionID)
+		require.Equal(t, to.ID, diff.ToVersionID)
+
+		// The echo provisioner responses are part of the archive too, so
+		// only the files we added are checked.
+		files := map[string]codersdk.TemplateVersionFileDiff{}
+		for _, file := range diff.Files {
+			files[file.Path] = file
+		}
+		require.NotContains(t, files, "same.txt")
+		require.True(t, files["image.png"].Binary)
+		require.Empty(t, files["image.png"].Diff)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeModified, files["main.tf"].Change)
+		require.Contains(t, files["main.tf"].Diff, "--- a/main.tf")
+		require.Contains(t, files["main.tf"].Diff, "-  size = 1")
+		require.Contains(t, files["main.tf"].Diff, "+  size = 2")
+		require.Equal(t, codersdk.TemplateVersionDiffChangeRemoved, files["old.tf"].Change)
+		require.Contains(t, files["old.tf"].Diff, "+++ /dev/null")
+
+		require.Len(t, diff.Variables, 2)
+		require.Equal(t, "region", diff.Variables[0].Name)
+		require.Equal(t, []string{"default_value"}, diff.Variables[0].Fields)
+		require.Equal(t, "us", diff.Variables[0].From.DefaultValue)
+		require.Equal(t, "eu", diff.Variables[0].To.DefaultValue)
+		// Sensitive values are reported as changed, but never revealed.
+		require.Equal(t, "token", diff.Variables[1].Name)
+		require.Equal(t, []string{"default_value"}, diff.Variables[1].Fields)
+		require.Equal(t, "*redacted*", diff.Variables[1].From.DefaultValue)
+		require.Equal(t, "*redacted*", diff.Variables[1].To.DefaultValue)
+
+		require.Len(t, diff.Parameters, 3)
+		require.Equal(t, "cpu", diff.Parameters[0].Name)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeModified, diff.Parameters[0].Change)
+		require.Equal(t, []string{"default_value", "validation_max", "validation_min"}, diff.Parameters[0].Fields)
+		require.Equal(t, "disk", diff.Parameters[1].Name)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeAdded, diff.Parameters[1].Change)
+		require.Nil(t, diff.Parameters[1].From)
+		require.Equal(t, "legacy", diff.Parameters[2].Name)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeRemoved, diff.Parameters[2].Change)
+		require.Nil(t, diff.Parameters[2].To)
+
+		require.Len(t, diff.Resources, 2)
+		require.Equal(t, "dev", diff.Resources[0].Name)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeModified, diff.Resources[0].Change)
+		require.Empty(t, diff.Resources[0].Fields)
+		require.Len(t, diff.Resources[0].Agents, 1)
+		require.Equal(t, "main", diff.Resources[0].Agents[0].Name)
+		require.Equal(t, []string{"architecture"}, diff.Resources[0].Agents[0].Fields)
+		require.Equal(t, "cache", diff.Resources[1].Name)
+		require.Equal(t, codersdk.TemplateVersionDiffChangeAdded, diff.Resources[1].Change)
+	})
+
+	t.Run("Same", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		diff, err := client.TemplateVersionDiff(ctx, version.ID, version.ID)
+		require.NoError(t, err)
+		require.Empty(t, diff.Files)
+		require.Empty(t, diff.Variables)
+		require.Empty(t, diff.Parameters)
+		require.Empty(t, diff.Resources)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.TemplateVersionDiff(ctx, version.ID, uuid.New())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		// Members can read templates, but not their source files.
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := member.TemplateVersionDiff(ctx, version.ID, version.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,99 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/totp"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	// The SHA1 test vectors from RFC 6238 appendix B, truncated to six
+	// digits.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	} {
+		code, err := totp.Code(secret, totp.Step(time.Unix(tc.unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code, "unix %d", tc.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	now := time.Now()
+	step := totp.Step(now)
+	code, err := totp.Code(secret, step)
+	require.NoError(t, err)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		got, ok := totp.Validate(secret, code, now, 0)
+		require.True(t, ok)
+		require.Equal(t, step, got)
+	})
+	t.Run("Skew", func(t *testing.T) {
+		t.Parallel()
+		_, ok := totp.Validate(secret, code, now.Add(totp.Period), 0)
+		require.True(t, ok)
+		_, ok = totp.Validate(secret, code, now.Add(3*totp.Period), 0)
+		require.False(t, ok)
+	})
+	t.Run("Replay", func(t *testing.T) {
+		t.Parallel()
+		_, ok := totp.Validate(secret, code, now, step)
+		require.False(t, ok)
+	})
+	t.Run("Wrong", func(t *testing.T) {
+		t.Parallel()
+		_, ok := totp.Validate(secret, "abcdef", now, 0)
+		require.False(t, ok)
+		_, ok = totp.Validate(secret, "1234567", now, 0)
+		require.False(t, ok)
+	})
+}
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	u, err := url.Parse(totp.URL("Coder", "kyle@coder.com", "SECRET"))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Coder:kyle@coder.com", u.Path)
+	require.Equal(t, "SECRET", u.Query().Get("secret"))
+	require.Equal(t, "Coder", u.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	codes, err := totp.GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	for _, code := range codes {
+		require.Len(t, code, 11)
+		require.False(t, totp.IsCode(code))
+	}
+	require.Equal(t, totp.HashRecoveryCode(codes[0]), totp.HashRecoveryCode(" "+codes[0][:5]+codes[0][6:]+" "))
+	require.NotEqual(t, totp.HashRecoveryCode(codes[0]), totp.HashRecoveryCode(codes[1]))
+	require.True(t, totp.IsCode("012345"))
+}
@@ -2,11 +2,16 @@ package coderd
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/mail"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,10 +19,12 @@ import (
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-ldap/ldap/v3"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/go-github/v43/github"
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/exp/slices"
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 
@@ -28,7 +35,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
-	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/coderd/userpassword"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/cryptorand"
@@ -48,6 +55,9 @@ type OAuthConvertStateClaims struct {
 	State         string             `json:"state"`
 	FromLoginType codersdk.LoginType `json:"from_login_type"`
 	ToLoginType   codersdk.LoginType `json:"to_login_type"`
+	// ToProvider is the ID of the generic OAuth2 login provider if
+	// ToLoginType is "oauth2".
+	ToProvider string `json:"to_provider,omitempty"`
 }
 
 // postConvertLoginType replies with an oauth state token capable of converting
@@ -93,7 +103,15 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	switch req.ToType {
 	case codersdk.LoginTypeGithub, codersdk.LoginTypeOIDC:
 		// Allowed!
-	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken:
+		req.ToProvider = ""
+	case codersdk.LoginTypeOAuth2:
+		if api.oauth2LoginProvider(req.ToProvider) == nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Unknown OAuth2 login provider %q.", req.ToProvider),
+			})
+			return
+		}
+	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken, codersdk.LoginTypeOAuth2ProviderApp, codersdk.LoginTypeLDAP:
 		// These login types are not allowed to be converted to at this time.
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Cannot convert to login type %q.", req.ToType),
@@ -107,13 +125,22 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	// This handles the email/pass checking.
-	user, _, ok := api.loginRequest(ctx, rw, codersdk.LoginWithPasswordRequest{
+	user, _, ok := api.loginRequest(ctx, rw, r.RemoteAddr, codersdk.LoginWithPasswordRequest{
 		Email:    user.Email,
 		Password: req.Password,
 	})
 	if !ok {
 		return
 	}
+	// The user is already logged in, so the password is the last factor.
+	err := api.clearLoginFailures(ctx, user)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error clearing failed logins.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 
 	// Only support converting from password auth.
 	if user.LoginType != database.LoginTypePassword {
@@ -162,6 +189,7 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 		State:         stateString,
 		FromLoginType: codersdk.LoginType(user.LoginType),
 		ToLoginType:   req.ToType,
+		ToProvider:    req.ToProvider,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
@@ -196,6 +224,7 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 		StateString: stateString,
 		ExpiresAt:   claims.ExpiresAt.Time,
 		ToType:      claims.ToLoginType,
+		ToProvider:  claims.ToProvider,
 		UserID:      claims.UserID,
 	})
 }
@@ -230,7 +259,7 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, roles, ok := api.loginRequest(ctx, rw, loginWithPassword)
+	user, roles, ok := api.loginRequest(ctx, rw, r.RemoteAddr, loginWithPassword)
 	// 'user.ID' will be empty, or will be an actual value. Either is correct
 	// here.
 	aReq.UserID = user.ID
@@ -239,11 +268,28 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userSubj := rbac.Subject{
-		ID:     user.ID.String(),
-		Roles:  rbac.RoleNames(roles.Roles),
-		Groups: roles.Groups,
-		Scope:  rbac.ScopeAll,
+	userSubj, err := api.userSubject(ctx, roles)
+	if err != nil {
+		logger.Error(ctx, "unable to expand user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+
+	//nolint:gocritic // Verifying the code as the user instead of as system.
+	recoveryCodes, ok := api.verifyLoginMFA(dbauthz.As(ctx, userSubj), rw, r.RemoteAddr, user, roles.Roles, loginWithPassword.TOTPCode)
+	if !ok {
+		return
+	}
+
+	err = api.clearLoginFailures(ctx, user)
+	if err != nil {
+		logger.Error(ctx, "unable to clear failed logins", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
 	}
 
 	//nolint:gocritic // Creating the API key as the user instead of as system.
@@ -267,7 +313,8 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 	http.SetCookie(rw, cookie)
 
 	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
-		SessionToken: cookie.Value,
+		SessionToken:  cookie.Value,
+		RecoveryCodes: recoveryCodes,
 	})
 }
 
@@ -277,7 +324,10 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 //
 // The user struct is always returned, even if authentication failed. This is
 // to support knowing what user attempted to login.
-func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req codersdk.LoginWithPasswordRequest) (database.User, database.GetAuthorizationUserRolesRow, bool) {
+//
+// Failed logins are not cleared, callers clear them once every factor of the
+// login is verified.
+func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, remoteAddr string, req codersdk.LoginWithPasswordRequest) (database.User, database.GetAuthorizationUserRolesRow, bool) {
 	logger := api.Logger.Named(userAuthLoggerName)
 
 	//nolint:gocritic // In order to login, we need to get the user first!
@@ -292,6 +342,21 @@ func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req co
 		return user, database.GetAuthorizationUserRolesRow{}, false
 	}
 
+	limited, err := api.loginRateLimited(ctx, user, remoteAddr)
+	if err != nil {
+		logger.Error(ctx, "unable to check failed logins", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return user, database.GetAuthorizationUserRolesRow{}, false
+	}
+	if limited {
+		httpapi.Write(ctx, rw, http.StatusTooManyRequests, codersdk.Response{
+			Message: "Too many failed logins. Try again later.",
+		})
+		return user, database.GetAuthorizationUserRolesRow{}, false
+	}
+
 	// If the user doesn't exist, it will be a default struct.
 	equal, err := userpassword.Compare(string(user.HashedPassword), req.Password)
 	if err != nil {
@@ -303,8 +368,13 @@ func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req co
 	}
 
 	if !equal {
+		err = api.recordLoginFailure(ctx, user, remoteAddr)
+		if err != nil {
+			logger.Error(ctx, "unable to record failed login", slog.Error(err))
+		}
 		// This message is the same as above to remove ease in detecting whether
 		// users are registered or not. Attackers still could with a timing attack.
+		// Users only learn that they are locked once they enter their password.
 		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
 			Message: "Incorrect email or password.",
 		})
@@ -337,7 +407,14 @@ func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req co
 		return user, database.GetAuthorizationUserRolesRow{}, false
 	}
 
-	// If the user logged into a suspended account, reject the login request.
+	// If the user logged into a locked or suspended account, reject the login
+	// request.
+	if roles.Status == database.UserStatusLocked {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account is locked after too many failed logins. Contact an admin to unlock your account.",
+		})
+		return user, database.GetAuthorizationUserRolesRow{}, false
+	}
 	if roles.Status != database.UserStatusActive {
 		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
 			Message: "Your account is suspended. Contact an admin to reactivate your account.",
@@ -453,6 +530,21 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 	if api.OIDCConfig != nil {
 		iconURL = api.OIDCConfig.IconURL
 	}
+	oauth2AuthMethods := make([]codersdk.OAuth2AuthMethod, 0, len(api.OAuth2LoginProviders))
+	for _, provider := range api.OAuth2LoginProviders {
+		oauth2AuthMethods = append(oauth2AuthMethods, codersdk.OAuth2AuthMethod{
+			ID:          provider.ID,
+			DisplayName: provider.DisplayName,
+			IconURL:     provider.IconURL,
+		})
+	}
+	var ldapAuthMethod codersdk.LDAPAuthMethod
+	if api.LDAPConfig != nil {
+		ldapAuthMethod = codersdk.LDAPAuthMethod{
+			AuthMethod: codersdk.AuthMethod{Enabled: true},
+			SignInText: api.LDAPConfig.SignInText,
+		}
+	}
 
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.AuthMethods{
 		ConvertToOIDCEnabled: api.Experiments.Enabled(codersdk.ExperimentConvertToOIDC),
@@ -465,6 +557,8 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 			SignInText: signInText,
 			IconURL:    iconURL,
 		},
+		LDAP:   ldapAuthMethod,
+		OAuth2: oauth2AuthMethods,
 	})
 }
 
@@ -1066,6 +1160,681 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
 }
 
+// OAuth2LoginProvider is a generic OAuth2 login provider, such as GitLab,
+// Bitbucket or Gitea.
+type OAuth2LoginProvider struct {
+	httpmw.OAuth2Config
+	// ID is a unique identifier of the provider used in the callback URL.
+	ID          string
+	DisplayName string
+	IconURL     string
+	// UserInfoURL returns a JSON document describing the authenticated user.
+	UserInfoURL string
+	// IDField, UsernameField and EmailField are paths into the user info,
+	// like "user.emails[0]". The ID must be stable for the lifetime of the
+	// upstream account.
+	IDField       string
+	UsernameField string
+	EmailField    string
+	// EmailVerifiedField is a path into the user info to a boolean that is
+	// false if the email is not verified. Logins with unverified emails are
+	// rejected unless IgnoreEmailVerified is set.
+	EmailVerifiedField  string
+	IgnoreEmailVerified bool
+	// GroupsField is a path into the user info that lists the groups of the
+	// user, like "groups[*].name". If it is the empty string, then no group
+	// updates will ever come from the provider.
+	GroupsField string
+	// GroupMapping controls how groups returned by the provider get mapped
+	// to groups within Coder.
+	// map[providerGroup]coderGroupName
+	GroupMapping map[string]string
+	// AllowedGroups restricts login to members of any of the groups.
+	AllowedGroups []string
+	AllowSignups  bool
+	// EmailDomain restricts login to emails of the domains.
+	EmailDomain []string
+}
+
+// oauth2LoginProvider returns the generic OAuth2 login provider with the ID,
+// or nil if there is none.
+func (api *API) oauth2LoginProvider(id string) *OAuth2LoginProvider {
+	for _, provider := range api.OAuth2LoginProviders {
+		if provider.ID == id {
+			return provider
+		}
+	}
+	return nil
+}
+
+// @Summary OAuth 2.0 login provider callback
+// @ID oauth-20-login-provider-callback
+// @Security CoderSessionToken
+// @Tags Users
+// @Param provider path string true "Provider ID"
+// @Success 307
+// @Router /users/oauth2/{provider}/callback [get]
+func (api *API) userOAuth2LoginProvider(provider *OAuth2LoginProvider) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		var (
+			// userOAuth2LoginProvider is a system function.
+			//nolint:gocritic
+			ctx               = dbauthz.AsSystemRestricted(r.Context())
+			state             = httpmw.OAuth2(r)
+			auditor           = api.Auditor.Load()
+			logger            = api.Logger.Named(userAuthLoggerName).With(slog.F("provider", provider.ID))
+			aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+				Audit:   *auditor,
+				Log:     api.Logger,
+				Request: r,
+				Action:  database.AuditActionLogin,
+			})
+		)
+		aReq.Old = database.APIKey{}
+		defer commitAudit()
+
+		oauthClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(state.Token))
+		info, err := fetchOAuth2UserInfo(ctx, oauthClient, provider.UserInfoURL)
+		if err != nil {
+			logger.Error(ctx, "oauth2: unable to fetch user info", slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: fmt.Sprintf("Internal error fetching the %s user.", provider.DisplayName),
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		id, _ := oauth2UserInfoString(info, provider.IDField)
+		if id == "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("No ID found in the %q field of the %s user!", provider.IDField, provider.DisplayName),
+			})
+			return
+		}
+		email, _ := oauth2UserInfoString(info, provider.EmailField)
+		if email == "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("No email found in the %q field of the %s user!", provider.EmailField, provider.DisplayName),
+			})
+			return
+		}
+		if values := lookupOAuth2UserInfo(info, provider.EmailVerifiedField); provider.EmailVerifiedField != "" && len(values) > 0 {
+			verified, ok := values[0].(bool)
+			if ok && !verified {
+				if !provider.IgnoreEmailVerified {
+					httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+						Message: fmt.Sprintf("Verify the %q email address on %s to authenticate!", email, provider.DisplayName),
+					})
+					return
+				}
+				logger.Warn(ctx, "allowing unverified oauth2 email", slog.F("email", email))
+			}
+		}
+		if len(provider.EmailDomain) > 0 && !emailInDomains(email, provider.EmailDomain) {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("Your email %q is not in domains %q !", email, provider.EmailDomain),
+			})
+			return
+		}
+
+		var groups []string
+		if provider.GroupsField != "" || len(provider.AllowedGroups) > 0 {
+			for _, value := range lookupOAuth2UserInfo(info, provider.GroupsField) {
+				// The field may be an array of groups, or a single group.
+				values, ok := value.([]interface{})
+				if !ok {
+					values = []interface{}{value}
+				}
+				for _, value := range values {
+					group, ok := oauth2UserInfoValueString(value)
+					if ok && group != "" {
+						groups = append(groups, group)
+					}
+				}
+			}
+		}
+		if len(provider.AllowedGroups) > 0 {
+			allowed := false
+			for _, group := range groups {
+				if slices.Contains(provider.AllowedGroups, group) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+					Message: fmt.Sprintf("You aren't a member of the authorized %s groups!", provider.DisplayName),
+				})
+				return
+			}
+		}
+		usingGroups := provider.GroupsField != ""
+		if usingGroups {
+			for i, group := range groups {
+				if mapped, ok := provider.GroupMapping[group]; ok {
+					groups[i] = mapped
+				}
+			}
+		}
+
+		// The username is a required property in Coder. We make a best-effort
+		// attempt at using what the provider returns, but if that fails we
+		// will generate a username from the email.
+		username, _ := oauth2UserInfoString(info, provider.UsernameField)
+		if httpapi.NameValid(username) != nil {
+			if username == "" {
+				username = email
+			}
+			username = httpapi.UsernameFrom(username)
+		}
+
+		linkedID := oauth2LoginProviderLinkedID(provider.ID, id)
+		user, link, err := findLinkedUser(ctx, api.Database, linkedID, email)
+		if err != nil {
+			logger.Error(ctx, "oauth2: unable to find linked user", slog.F("email", email), slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to find linked user.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		// All generic providers share a login type, so the link tells which
+		// provider the user signed up with.
+		if link.UserID != uuid.Nil && link.LoginType == database.LoginTypeOAuth2 &&
+			link.LinkedID != "" && link.LinkedID != linkedID {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("Your account is linked to another OAuth2 provider than %s!", provider.DisplayName),
+			})
+			return
+		}
+
+		// If a new user is authenticating for the first time
+		// the audit action is 'register', not 'login'
+		if user.ID == uuid.Nil {
+			aReq.Action = database.AuditActionRegister
+		}
+
+		params := (&oauthLoginParams{
+			User:             user,
+			Link:             link,
+			State:            state,
+			LinkedID:         linkedID,
+			LoginType:        database.LoginTypeOAuth2,
+			OAuth2ProviderID: provider.ID,
+			AllowSignups:     provider.AllowSignups,
+			Email:            email,
+			Username:         username,
+			UsingGroups:      usingGroups,
+			Groups:           groups,
+		}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
+			return audit.InitRequest[database.User](rw, params)
+		})
+		cookies, key, err := api.oauthLogin(r, params)
+		defer params.CommitAuditLogs()
+		var httpErr httpError
+		if xerrors.As(err, &httpErr) {
+			httpErr.Write(rw, r)
+			return
+		}
+		if err != nil {
+			logger.Error(ctx, "oauth2: login failed", slog.F("user", user.Username), slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to process OAuth login.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		aReq.New = key
+		aReq.UserID = key.UserID
+
+		for _, cookie := range cookies {
+			http.SetCookie(rw, cookie)
+		}
+
+		redirect := state.Redirect
+		if redirect == "" {
+			redirect = "/"
+		}
+		http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
+	}
+}
+
+func fetchOAuth2UserInfo(ctx context.Context, client *http.Client, userInfoURL string) (interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+		return nil, xerrors.Errorf("status %d: %s", res.StatusCode, body)
+	}
+
+	var info interface{}
+	decoder := json.NewDecoder(res.Body)
+	// Numeric IDs must not lose precision.
+	decoder.UseNumber()
+	err = decoder.Decode(&info)
+	if err != nil {
+		return nil, xerrors.Errorf("decode user info: %w", err)
+	}
+	return info, nil
+}
+
+// lookupOAuth2UserInfo returns the values at a path like "user.emails[0]" or
+// "groups[*].name" in the user info. "[*]" matches every element of an array.
+// A leading "$." is ignored.
+func lookupOAuth2UserInfo(info interface{}, path string) []interface{} {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	values := []interface{}{info}
+	if path == "" {
+		return values
+	}
+	for _, segment := range strings.Split(path, ".") {
+		name, indexes := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, indexes = segment[:i], segment[i:]
+		}
+
+		next := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			if name != "" {
+				object, ok := value.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				value, ok = object[name]
+				if !ok {
+					continue
+				}
+			}
+			next = append(next, value)
+		}
+
+		for indexes != "" {
+			end := strings.IndexByte(indexes, ']')
+			if !strings.HasPrefix(indexes, "[") || end < 0 {
+				return nil
+			}
+			index := indexes[1:end]
+			indexes = indexes[end+1:]
+
+			elements := make([]interface{}, 0, len(next))
+			for _, value := range next {
+				array, ok := value.([]interface{})
+				if !ok {
+					continue
+				}
+				if index == "*" {
+					elements = append(elements, array...)
+					continue
+				}
+				i, err := strconv.Atoi(index)
+				if err != nil || i < 0 || i >= len(array) {
+					continue
+				}
+				elements = append(elements, array[i])
+			}
+			next = elements
+		}
+		values = next
+	}
+	return values
+}
+
+// oauth2UserInfoString returns the first value at the path as a string.
+func oauth2UserInfoString(info interface{}, path string) (string, bool) {
+	values := lookupOAuth2UserInfo(info, path)
+	if len(values) == 0 {
+		return "", false
+	}
+	return oauth2UserInfoValueString(values[0])
+}
+
+func oauth2UserInfoValueString(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		return "", false
+	}
+}
+
+// emailInDomains returns whether the domain of the email, the part after the
+// last "@", is one of the domains. Domains are compared case-insensitively.
+func emailInDomains(email string, domains []string) bool {
+	i := strings.LastIndexByte(email, '@')
+	if i < 0 {
+		return false
+	}
+	for _, domain := range domains {
+		if strings.EqualFold(email[i+1:], strings.TrimPrefix(domain, "@")) {
+			return true
+		}
+	}
+	return false
+}
+
+// LDAPConfig configures login with an LDAP or Active Directory server.
+type LDAPConfig struct {
+	// URL is the address of the server, with the ldap or ldaps scheme.
+	URL string
+	// StartTLS upgrades ldap connections to TLS.
+	StartTLS bool
+	// TLSConfig is used for ldaps and StartTLS connections.
+	TLSConfig *tls.Config
+	// BindDN and BindPassword are the credentials of the service account
+	// that searches for users.
+	BindDN       string
+	BindPassword string
+	// SearchBaseDN is the DN under which users are searched for.
+	SearchBaseDN string
+	// SearchFilter finds the entry of a user. "%s" is replaced with the
+	// escaped username.
+	SearchFilter string
+	// UsernameAttribute selects the attribute to be used as the created
+	// user's username.
+	UsernameAttribute string
+	// EmailAttribute selects the attribute to be used as the created user's
+	// email.
+	EmailAttribute string
+	// GroupsAttribute selects the attribute that lists the groups of a user.
+	// If it is the empty string, then no group updates will ever come from
+	// the LDAP server.
+	GroupsAttribute string
+	// GroupMapping controls how LDAP groups get mapped to groups within
+	// Coder. Groups are matched by DN or by the value of their first RDN.
+	// map[ldapGroup]coderGroupName
+	GroupMapping map[string]string
+	AllowSignups bool
+	// SignInText is the text to display on the LDAP login form.
+	SignInText string
+}
+
+// LDAPUser is the entry of a user in the LDAP directory.
+type LDAPUser struct {
+	DN       string
+	Username string
+	Email    string
+	Groups   []string
+}
+
+// ldapTimeout bounds every connection and request to the LDAP server.
+const ldapTimeout = 10 * time.Second
+
+var errLDAPInvalidCredentials = xerrors.New("invalid LDAP credentials")
+
+// Authenticate searches for the entry of the user with the service account
+// and verifies the password by binding as the user. errLDAPInvalidCredentials
+// is returned if the user is not found or the password is wrong.
+func (cfg *LDAPConfig) Authenticate(username, password string) (LDAPUser, error) {
+	// Binding with an empty password is an unauthenticated bind, which
+	// servers accept for any DN.
+	if username == "" || password == "" {
+		return LDAPUser{}, errLDAPInvalidCredentials
+	}
+
+	conn, err := cfg.dial()
+	if err != nil {
+		return LDAPUser{}, err
+	}
+	defer conn.Close()
+
+	if cfg.BindDN != "" {
+		err = conn.Bind(cfg.BindDN, cfg.BindPassword)
+		if err != nil {
+			return LDAPUser{}, xerrors.Errorf("bind as service account: %w", err)
+		}
+	}
+
+	attributes := []string{cfg.UsernameAttribute, cfg.EmailAttribute}
+	if cfg.GroupsAttribute != "" {
+		attributes = append(attributes, cfg.GroupsAttribute)
+	}
+	res, err := conn.Search(ldap.NewSearchRequest(
+		cfg.SearchBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		// Two entries are enough to know the username is ambiguous.
+		2, int(ldapTimeout.Seconds()), false,
+		strings.ReplaceAll(cfg.SearchFilter, "%s", ldap.EscapeFilter(username)),
+		attributes, nil,
+	))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return LDAPUser{}, errLDAPInvalidCredentials
+	}
+	if err != nil {
+		return LDAPUser{}, xerrors.Errorf("search user: %w", err)
+	}
+	if len(res.Entries) != 1 {
+		return LDAPUser{}, errLDAPInvalidCredentials
+	}
+	entry := res.Entries[0]
+
+	err = conn.Bind(entry.DN, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return LDAPUser{}, errLDAPInvalidCredentials
+	}
+	if err != nil {
+		return LDAPUser{}, xerrors.Errorf("bind as user: %w", err)
+	}
+
+	user := LDAPUser{
+		DN:       entry.DN,
+		Username: entry.GetEqualFoldAttributeValue(cfg.UsernameAttribute),
+		Email:    entry.GetEqualFoldAttributeValue(cfg.EmailAttribute),
+	}
+	if cfg.GroupsAttribute != "" {
+		user.Groups = entry.GetEqualFoldAttributeValues(cfg.GroupsAttribute)
+	}
+	return user, nil
+}
+
+// CoderGroups maps the groups of an LDAP user to groups within Coder.
+func (cfg *LDAPConfig) CoderGroups(groups []string) []string {
+	coderGroups := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if mapped, ok := cfg.GroupMapping[group]; ok {
+			coderGroups = append(coderGroups, mapped)
+			continue
+		}
+		// Groups are usually listed by DN, "CN=devs,OU=Groups,DC=corp"
+		// becomes "devs".
+		name := group
+		dn, err := ldap.ParseDN(group)
+		if err == nil && len(dn.RDNs) > 0 && len(dn.RDNs[0].Attributes) > 0 {
+			name = dn.RDNs[0].Attributes[0].Value
+		}
+		if mapped, ok := cfg.GroupMapping[name]; ok {
+			name = mapped
+		}
+		coderGroups = append(coderGroups, name)
+	}
+	return coderGroups
+}
+
+func (cfg *LDAPConfig) dial() (*ldap.Conn, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.TLSConfig != nil {
+		tlsConfig = cfg.TLSConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	conn, err := ldap.DialURL(cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: ldapTimeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("dial: %w", err)
+	}
+	conn.SetTimeout(ldapTimeout)
+	if cfg.StartTLS {
+		err = conn.StartTLS(tlsConfig)
+		if err != nil {
+			conn.Close()
+			return nil, xerrors.Errorf("start tls: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// Authenticates the user with a username and password checked against the
+// LDAP server.
+//
+// @Summary Log in user with LDAP
+// @ID log-in-user-with-ldap
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithLDAPRequest true "Login request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/ldap/login [post]
+func (api *API) postLoginLDAP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		// Users are looked up and created by the system.
+		//nolint:gocritic
+		sysCtx            = dbauthz.AsSystemRestricted(ctx)
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if api.LDAPConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "LDAP authentication is not enabled.",
+		})
+		return
+	}
+
+	var req codersdk.LoginWithLDAPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	ldapUser, err := api.LDAPConfig.Authenticate(req.Username, req.Password)
+	if errors.Is(err, errLDAPInvalidCredentials) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Incorrect username or password.",
+		})
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "ldap: unable to authenticate", slog.F("username", req.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to authenticate with LDAP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	email := ldapUser.Email
+	if email == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("No email found in the %q attribute of the LDAP entry!", api.LDAPConfig.EmailAttribute),
+		})
+		return
+	}
+
+	// The username is a required property in Coder. We make a best-effort
+	// attempt at using the attribute, but if that fails we will generate a
+	// username from the email.
+	username := ldapUser.Username
+	if httpapi.NameValid(username) != nil {
+		if username == "" {
+			username = email
+		}
+		username = httpapi.UsernameFrom(username)
+	}
+
+	var groups []string
+	usingGroups := api.LDAPConfig.GroupsAttribute != ""
+	if usingGroups {
+		groups = api.LDAPConfig.CoderGroups(ldapUser.Groups)
+	}
+
+	user, link, err := findLinkedUser(sysCtx, api.Database, ldapUser.DN, email)
+	if err != nil {
+		logger.Error(ctx, "ldap: unable to find linked user", slog.F("email", email), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// If a new user is authenticating for the first time
+	// the audit action is 'register', not 'login'
+	if user.ID == uuid.Nil {
+		aReq.Action = database.AuditActionRegister
+	}
+
+	params := (&oauthLoginParams{
+		User: user,
+		Link: link,
+		// LDAP has no OAuth2 token to store in the user link.
+		State:        httpmw.OAuth2State{Token: &oauth2.Token{}},
+		LinkedID:     ldapUser.DN,
+		LoginType:    database.LoginTypeLDAP,
+		AllowSignups: api.LDAPConfig.AllowSignups,
+		Email:        email,
+		Username:     username,
+		UsingGroups:  usingGroups,
+		Groups:       groups,
+	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
+		return audit.InitRequest[database.User](rw, params)
+	})
+	cookies, key, err := api.oauthLogin(r, params)
+	defer params.CommitAuditLogs()
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		httpErr.Write(rw, r)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "ldap: login failed", slog.F("user", user.Username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process LDAP login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	var sessionToken string
+	for _, cookie := range cookies {
+		if cookie.Name == codersdk.SessionTokenCookie {
+			sessionToken = cookie.Value
+		}
+		http.SetCookie(rw, cookie)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: sessionToken,
+	})
+}
+
 // claimFields returns the sorted list of fields in the claims map.
 func claimFields(claims map[string]interface{}) []string {
 	fields := []string{}
@@ -1115,6 +1884,9 @@ type oauthLoginParams struct {
 	Email        string
 	Username     string
 	AvatarURL    string
+	// OAuth2ProviderID is the ID of the generic OAuth2 login provider if
+	// LoginType is LoginTypeOAuth2.
+	OAuth2ProviderID string
 	// Is UsingGroups is true, then the user will be assigned
 	// to the Groups provided.
 	UsingGroups bool
@@ -1189,7 +1961,10 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 		logger  = api.Logger.Named(userAuthLoggerName)
 	)
 
-	var isConvertLoginType bool
+	var (
+		isConvertLoginType bool
+		isNewUser          bool
+	)
 	err := api.Database.InTx(func(tx database.Store) error {
 		var (
 			link database.UserLink
@@ -1287,6 +2062,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			if err != nil {
 				return xerrors.Errorf("create user: %w", err)
 			}
+			isNewUser = true
 		}
 
 		if link.UserID == uuid.Nil {
@@ -1332,7 +2108,8 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			ignored := make([]string, 0)
 			filtered := make([]string, 0, len(params.Roles))
 			for _, role := range params.Roles {
-				if _, err := rbac.RoleByName(role); err == nil {
+				//nolint:gocritic // Custom roles are looked up by the system.
+				if _, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), tx, []string{role}); err == nil {
 					filtered = append(filtered, role)
 				} else {
 					ignored = append(ignored, role)
@@ -1403,6 +2180,9 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 	if err != nil {
 		return nil, database.APIKey{}, xerrors.Errorf("in tx: %w", err)
 	}
+	if isNewUser {
+		api.Webhooks.User(ctx, database.WebhookEventUserCreated, user)
+	}
 
 	var key database.APIKey
 	if oldKey, ok := httpmw.APIKeyOptional(r); ok && isConvertLoginType {
@@ -1523,7 +2303,8 @@ func (api *API) convertUserToOauth(ctx context.Context, r *http.Request, db data
 	// user.
 	if user.ID != claims.UserID ||
 		codersdk.LoginType(user.LoginType) != claims.FromLoginType ||
-		codersdk.LoginType(params.LoginType) != claims.ToLoginType {
+		codersdk.LoginType(params.LoginType) != claims.ToLoginType ||
+		params.OAuth2ProviderID != claims.ToProvider {
 		return database.User{}, httpError{
 			code: http.StatusForbidden,
 			msg:  fmt.Sprintf("Request to convert login type from %s to %s failed", user.LoginType, params.LoginType),
@@ -1560,6 +2341,12 @@ func oidcLinkedID(tok *oidc.IDToken) string {
 	return strings.Join([]string{tok.Issuer, tok.Subject}, "||")
 }
 
+// oauth2LoginProviderLinkedID returns the unique ID for a user of a generic
+// OAuth2 login provider.
+func oauth2LoginProviderLinkedID(providerID, id string) string {
+	return strings.Join([]string{providerID, id}, "||")
+}
+
 // findLinkedUser tries to find a user by their unique OAuth-linked ID.
 // If it doesn't not find it, it returns the user by their email.
 func findLinkedUser(ctx context.Context, db database.Store, linkedID string, emails ...string) (database.User, database.UserLink, error) {
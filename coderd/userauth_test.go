@@ -3,10 +3,13 @@ package coderd_test
 import (
 	"context"
 	"crypto"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -24,6 +27,7 @@ import (
 	"github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/coderdtest/ldaptest"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbgen"
 	"github.com/coder/coder/coderd/database/dbtestutil"
@@ -920,6 +924,464 @@ func TestUserOIDC(t *testing.T) {
 	})
 }
 
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+
+	const baseDN = "ou=people,dc=coder,dc=com"
+	alice := ldaptest.Entry{
+		DN:       "uid=alice," + baseDN,
+		Password: "alicepassword",
+		Attributes: map[string][]string{
+			"uid":      {"alice"},
+			"mail":     {"alice@coder.com"},
+			"memberOf": {"cn=devs,ou=groups,dc=coder,dc=com"},
+		},
+	}
+	service := ldaptest.Entry{
+		DN:       "cn=coder,dc=coder,dc=com",
+		Password: "servicepassword",
+	}
+	ldapConfig := func(srv *ldaptest.Server) *coderd.LDAPConfig {
+		return &coderd.LDAPConfig{
+			URL:               srv.URL,
+			TLSConfig:         &tls.Config{RootCAs: srv.RootCAs, MinVersion: tls.VersionTLS12},
+			BindDN:            service.DN,
+			BindPassword:      service.Password,
+			SearchBaseDN:      baseDN,
+			SearchFilter:      "(uid=%s)",
+			UsernameAttribute: "uid",
+			EmailAttribute:    "mail",
+			AllowSignups:      true,
+		}
+	}
+
+	t.Run("Login", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, ldaptest.ModePlain, service, alice)
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor:    auditor,
+			LDAPConfig: ldapConfig(srv),
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.True(t, methods.LDAP.Enabled)
+
+		numLogs := len(auditor.AuditLogs())
+		res, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: alice.Password,
+		})
+		require.NoError(t, err)
+		numLogs++ // add an audit log for login
+		require.Equal(t, []string{service.DN, alice.DN}, srv.Binds())
+
+		client.SetSessionToken(res.SessionToken)
+		user, err := client.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, "alice", user.Username)
+		require.Equal(t, "alice@coder.com", user.Email)
+		require.Equal(t, codersdk.LoginTypeLDAP, user.LoginType)
+
+		require.Len(t, auditor.AuditLogs(), numLogs)
+		require.NotEqual(t, auditor.AuditLogs()[numLogs-1].UserID, uuid.Nil)
+		require.Equal(t, database.AuditActionRegister, auditor.AuditLogs()[numLogs-1].Action)
+	})
+
+	t.Run("InvalidPassword", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, ldaptest.ModePlain, service, alice)
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: ldapConfig(srv),
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, req := range []codersdk.LoginWithLDAPRequest{
+			{Username: "alice", Password: "wrong"},
+			{Username: "bob", Password: alice.Password},
+			{Username: "*", Password: alice.Password},
+		} {
+			_, err := client.LoginWithLDAP(ctx, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		}
+	})
+
+	t.Run("NoSignups", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, ldaptest.ModePlain, service, alice)
+		config := ldapConfig(srv)
+		config.AllowSignups = false
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: config,
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: alice.Password,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.False(t, methods.LDAP.Enabled)
+
+		_, err = client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: alice.Password,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	for _, tc := range []struct {
+		name     string
+		mode     ldaptest.Mode
+		startTLS bool
+	}{
+		{name: "StartTLS", mode: ldaptest.ModeStartTLS, startTLS: true},
+		{name: "LDAPS", mode: ldaptest.ModeLDAPS},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			srv := ldaptest.New(t, tc.mode, service, alice)
+			config := ldapConfig(srv)
+			config.StartTLS = tc.startTLS
+
+			user, err := config.Authenticate("alice", alice.Password)
+			require.NoError(t, err)
+			require.Equal(t, alice.DN, user.DN)
+
+			// The server certificate must be trusted.
+			config.TLSConfig = nil
+			_, err = config.Authenticate("alice", alice.Password)
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("Groups", func(t *testing.T) {
+		t.Parallel()
+		srv := ldaptest.New(t, ldaptest.ModePlain, service, alice)
+		config := ldapConfig(srv)
+		config.GroupsAttribute = "memberOf"
+		config.GroupMapping = map[string]string{
+			"admins": "coder-admins",
+		}
+
+		user, err := config.Authenticate("alice", alice.Password)
+		require.NoError(t, err)
+		require.Equal(t, alice.Attributes["memberOf"], user.Groups)
+
+		require.Equal(t, []string{"devs", "coder-admins", "coder-admins", "plain"}, config.CoderGroups([]string{
+			"cn=devs,ou=groups,dc=coder,dc=com",
+			"CN=admins,OU=Groups,DC=coder,DC=com",
+			"admins",
+			"plain",
+		}))
+	})
+}
+
+func TestUserOAuth2LoginProvider(t *testing.T) {
+	t.Parallel()
+
+	// userInfoServer serves the user info to the bearer of "access_token".
+	userInfoServer := func(t *testing.T, info string) string {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer access_token" {
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(info))
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+	gitlab := func(t *testing.T, info string) *coderd.OAuth2LoginProvider {
+		return &coderd.OAuth2LoginProvider{
+			OAuth2Config:       &testutil.OAuth2Config{},
+			ID:                 "gitlab",
+			DisplayName:        "GitLab",
+			IconURL:            "/icon/gitlab.svg",
+			UserInfoURL:        userInfoServer(t, info),
+			IDField:            "id",
+			UsernameField:      "username",
+			EmailField:         "email",
+			EmailVerifiedField: "email_verified",
+			AllowSignups:       true,
+		}
+	}
+	const kyle = `{"id": 1234, "username": "kyle", "email": "kyle@coder.com", "groups": ["coder/devs"]}`
+
+	t.Run("Signup", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor:              auditor,
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{gitlab(t, kyle)},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.OAuth2AuthMethod{{
+			ID:          "gitlab",
+			DisplayName: "GitLab",
+			IconURL:     "/icon/gitlab.svg",
+		}}, methods.OAuth2)
+
+		numLogs := len(auditor.AuditLogs())
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		numLogs++ // add an audit log for login
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		client.SetSessionToken(authCookieValue(resp.Cookies()))
+		user, err := client.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, "kyle", user.Username)
+		require.Equal(t, "kyle@coder.com", user.Email)
+		require.Equal(t, codersdk.LoginTypeOAuth2, user.LoginType)
+
+		require.Len(t, auditor.AuditLogs(), numLogs)
+		require.NotEqual(t, auditor.AuditLogs()[numLogs-1].UserID, uuid.Nil)
+		require.Equal(t, database.AuditActionRegister, auditor.AuditLogs()[numLogs-1].Action)
+
+		// Logging in again uses the same user.
+		resp = oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		client.SetSessionToken(authCookieValue(resp.Cookies()))
+		again, err := client.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, again.ID)
+	})
+
+	t.Run("FieldPaths", func(t *testing.T) {
+		t.Parallel()
+		provider := gitlab(t, `{"user": {"login": {"name": "Kyle Carberry"}, "emails": ["kyle@coder.com", "kyle@example.com"]}, "sub": "abc"}`)
+		provider.IDField = "$.sub"
+		provider.UsernameField = "user.login.name"
+		provider.EmailField = "user.emails[0]"
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{provider},
+		})
+
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		client.SetSessionToken(authCookieValue(resp.Cookies()))
+		user, err := client.User(testutil.Context(t, testutil.WaitLong), "me")
+		require.NoError(t, err)
+		// The username isn't valid, so it's sanitized.
+		require.Equal(t, "KyleCarberry", user.Username)
+		require.Equal(t, "kyle@coder.com", user.Email)
+	})
+
+	t.Run("NoEmail", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{gitlab(t, `{"id": 1234, "username": "kyle"}`)},
+		})
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("AllowedGroups", func(t *testing.T) {
+		t.Parallel()
+		provider := gitlab(t, `{"id": 1234, "username": "kyle", "email": "kyle@coder.com", "groups": [{"name": "coder/devs"}]}`)
+		provider.GroupsField = "groups[*].name"
+		provider.AllowedGroups = []string{"coder/devs"}
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{provider},
+		})
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+	})
+
+	t.Run("NotInAllowedGroups", func(t *testing.T) {
+		t.Parallel()
+		provider := gitlab(t, kyle)
+		provider.GroupsField = "groups"
+		provider.AllowedGroups = []string{"coder/admins", "coder"}
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{provider},
+		})
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("EmailDomain", func(t *testing.T) {
+		t.Parallel()
+		for _, tc := range []struct {
+			Name       string
+			Email      string
+			Domains    []string
+			StatusCode int
+		}{{
+			Name:       "Other",
+			Email:      "kyle@coder.com",
+			Domains:    []string{"example.com"},
+			StatusCode: http.StatusForbidden,
+		}, {
+			Name:       "CaseInsensitive",
+			Email:      "kyle@CODER.com",
+			Domains:    []string{"example.com", "coder.com"},
+			StatusCode: http.StatusTemporaryRedirect,
+		}, {
+			// The domain must match exactly, not only end with an allowed
+			// domain.
+			Name:       "Suffix",
+			Email:      "kyle@evilcoder.com",
+			Domains:    []string{"coder.com"},
+			StatusCode: http.StatusForbidden,
+		}, {
+			Name:       "Subdomain",
+			Email:      "kyle@evil.coder.com",
+			Domains:    []string{"coder.com"},
+			StatusCode: http.StatusForbidden,
+		}, {
+			Name:       "LastAt",
+			Email:      `"kyle@coder.com"@evil.com`,
+			Domains:    []string{"coder.com"},
+			StatusCode: http.StatusForbidden,
+		}} {
+			tc := tc
+			t.Run(tc.Name, func(t *testing.T) {
+				t.Parallel()
+				info, err := json.Marshal(map[string]interface{}{
+					"id":       1234,
+					"username": "kyle",
+					"email":    tc.Email,
+				})
+				require.NoError(t, err)
+				provider := gitlab(t, string(info))
+				provider.EmailDomain = tc.Domains
+				client := coderdtest.New(t, &coderdtest.Options{
+					OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{provider},
+				})
+				resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+				require.Equal(t, tc.StatusCode, resp.StatusCode)
+			})
+		}
+	})
+
+	t.Run("EmailNotVerified", func(t *testing.T) {
+		t.Parallel()
+		const unverified = `{"id": 1234, "username": "kyle", "email": "kyle@coder.com", "email_verified": false}`
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{gitlab(t, unverified)},
+		})
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("IgnoreEmailVerified", func(t *testing.T) {
+		t.Parallel()
+		const unverified = `{"id": 1234, "username": "kyle", "email": "kyle@coder.com", "email_verified": false}`
+		provider := gitlab(t, unverified)
+		provider.IgnoreEmailVerified = true
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{provider},
+		})
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+	})
+
+	t.Run("EmailVerifiedField", func(t *testing.T) {
+		t.Parallel()
+		const unverified = `{"id": 1234, "username": "kyle", "email": "kyle@coder.com", "email_verified": true, "user": {"confirmed": false}}`
+		provider := gitlab(t, unverified)
+		provider.EmailVerifiedField = "user.confirmed"
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{provider},
+		})
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("BlockSignups", func(t *testing.T) {
+		t.Parallel()
+		provider := gitlab(t, kyle)
+		provider.AllowSignups = false
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{provider},
+		})
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("OtherProvider", func(t *testing.T) {
+		t.Parallel()
+		bitbucket := gitlab(t, kyle)
+		bitbucket.ID = "bitbucket"
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{gitlab(t, kyle), bitbucket},
+		})
+		resp := oauth2LoginProviderCallback(t, client, "gitlab", "somestate")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		// The same email from another provider must not take over the user.
+		resp = oauth2LoginProviderCallback(t, client, "bitbucket", "somestate")
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		t.Parallel()
+		cfg := coderdtest.DeploymentValues(t)
+		cfg.Experiments = clibase.StringArray{string(codersdk.ExperimentConvertToOIDC)}
+		client := coderdtest.New(t, &coderdtest.Options{
+			OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{gitlab(t, kyle)},
+			DeploymentValues:     cfg,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		user, _ := coderdtest.CreateAnotherUserMutators(t, client, owner.OrganizationID, nil, func(r *codersdk.CreateUserRequest) {
+			r.Email = "kyle@coder.com"
+			r.Username = "kyle"
+		})
+
+		var err error
+		user.HTTPClient.Jar, err = cookiejar.New(nil)
+		require.NoError(t, err)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err = user.ConvertLoginType(ctx, codersdk.ConvertLoginRequest{
+			ToType:     codersdk.LoginTypeOAuth2,
+			ToProvider: "bitbucket",
+			Password:   "SomeSecurePassword!",
+		})
+		require.Error(t, err)
+
+		convertResponse, err := user.ConvertLoginType(ctx, codersdk.ConvertLoginRequest{
+			ToType:     codersdk.LoginTypeOAuth2,
+			ToProvider: "gitlab",
+			Password:   "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "gitlab", convertResponse.ToProvider)
+
+		resp := oauth2LoginProviderCallback(t, user, "gitlab", convertResponse.StateString)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		me, err := user.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, codersdk.LoginTypeOAuth2, me.LoginType)
+	})
+}
+
 func TestUserLogout(t *testing.T) {
 	t.Parallel()
 
@@ -1046,6 +1508,28 @@ func oauth2Callback(t *testing.T, client *codersdk.Client) *http.Response {
 	return res
 }
 
+func oauth2LoginProviderCallback(t *testing.T, client *codersdk.Client, provider, state string) *http.Response {
+	t.Helper()
+
+	client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	oauthURL, err := client.URL.Parse(fmt.Sprintf("/api/v2/users/oauth2/%s/callback?code=asd&state=%s", provider, state))
+	require.NoError(t, err)
+	req, err := http.NewRequestWithContext(context.Background(), "GET", oauthURL.String(), nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{
+		Name:  codersdk.OAuth2StateCookie,
+		Value: state,
+	})
+	res, err := client.HTTPClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = res.Body.Close()
+	})
+	return res
+}
+
 func oidcCallback(t *testing.T, client *codersdk.Client, code string) *http.Response {
 	return oidcCallbackWithState(t, client, code, "somestate")
 }
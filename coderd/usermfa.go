@@ -0,0 +1,588 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/totp"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	// totpIssuer is shown next to the account in authenticator apps.
+	totpIssuer = "Coder"
+	// recoveryCodeCount is the number of recovery codes a user gets each
+	// time they are generated.
+	recoveryCodeCount = 10
+)
+
+// mfaRequired returns true if users with the roles must use multi-factor
+// authentication to log in with a password.
+func (api *API) mfaRequired(roles []string) bool {
+	if !api.DeploymentValues.RequireAdminMFA.Value() {
+		return false
+	}
+	return slices.Contains(roles, rbac.RoleOwner()) || slices.Contains(roles, rbac.RoleUserAdmin())
+}
+
+// verifyLoginMFA checks the multi-factor authentication code of a user that
+// logged in with a password. If the user had to enroll an authenticator app,
+// their new recovery codes are returned. If 'false' is returned, the
+// authentication failed and the appropriate error will be written to the
+// ResponseWriter.
+func (api *API) verifyLoginMFA(ctx context.Context, rw http.ResponseWriter, remoteAddr string, user database.User, roles []string, code string) ([]string, bool) {
+	logger := api.Logger.Named(userAuthLoggerName)
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Error(ctx, "unable to fetch user totp", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return nil, false
+	}
+
+	if !userTOTP.Enabled {
+		if !api.mfaRequired(roles) {
+			return nil, true
+		}
+		if code == "" || userTOTP.Secret == "" {
+			httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+				Message: "Multi-factor authentication is required for your account.",
+				Validations: []codersdk.ValidationError{{
+					Field:  codersdk.TOTPEnrollmentField,
+					Detail: "Enroll an authenticator app to continue.",
+				}},
+			})
+			return nil, false
+		}
+		// The code verifies the secret the user enrolled while logging in.
+		valid, err := api.useTOTPCode(ctx, userTOTP, code)
+		if err != nil {
+			logger.Error(ctx, "unable to use totp code", slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error.",
+			})
+			return nil, false
+		}
+		if !valid {
+			api.recordLoginMFAFailure(ctx, rw, user, remoteAddr)
+			return nil, false
+		}
+		recoveryCodes, err := api.regenerateRecoveryCodes(ctx, user.ID)
+		if err != nil {
+			logger.Error(ctx, "unable to generate recovery codes", slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error.",
+			})
+			return nil, false
+		}
+		return recoveryCodes, true
+	}
+
+	if code == "" {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Multi-factor authentication code required.",
+			Validations: []codersdk.ValidationError{{
+				Field:  codersdk.TOTPCodeField,
+				Detail: "Enter a code from your authenticator app or a recovery code.",
+			}},
+		})
+		return nil, false
+	}
+
+	var valid bool
+	if totp.IsCode(code) {
+		valid, err = api.useTOTPCode(ctx, userTOTP, code)
+	} else {
+		_, err = api.Database.UseUserTOTPRecoveryCode(ctx, database.UseUserTOTPRecoveryCodeParams{
+			UsedAt:     sql.NullTime{Time: database.Now(), Valid: true},
+			UserID:     user.ID,
+			HashedCode: totp.HashRecoveryCode(code),
+		})
+		valid = err == nil
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+	}
+	if err != nil {
+		logger.Error(ctx, "unable to verify multi-factor authentication code", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return nil, false
+	}
+	if !valid {
+		api.recordLoginMFAFailure(ctx, rw, user, remoteAddr)
+		return nil, false
+	}
+	return nil, true
+}
+
+// recordLoginMFAFailure counts an incorrect code as a failed login, so codes
+// can't be guessed without locking the account, and writes the error.
+func (api *API) recordLoginMFAFailure(ctx context.Context, rw http.ResponseWriter, user database.User, remoteAddr string) {
+	err := api.recordLoginFailure(ctx, user, remoteAddr)
+	if err != nil {
+		api.Logger.Named(userAuthLoggerName).Error(ctx, "unable to record failed login", slog.Error(err))
+	}
+	httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+		Message: "Incorrect multi-factor authentication code.",
+	})
+}
+
+// useTOTPCode validates the code against the secret of the user, and
+// records its time step so it can't be used again. Using a code enables the
+// secret if it was pending verification.
+func (api *API) useTOTPCode(ctx context.Context, userTOTP database.UserTOTP, code string) (bool, error) {
+	step, ok := totp.Validate(userTOTP.Secret, code, time.Now(), userTOTP.LastUsedStep)
+	if !ok {
+		return false, nil
+	}
+	_, err := api.Database.UpdateUserTOTPUsed(ctx, database.UpdateUserTOTPUsedParams{
+		LastUsedStep: step,
+		UpdatedAt:    database.Now(),
+		UserID:       userTOTP.UserID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// The code was used concurrently.
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("update user totp: %w", err)
+	}
+	return true, nil
+}
+
+// regenerateRecoveryCodes replaces the recovery codes of the user. The codes
+// are only stored hashed, so they must be shown to the user now.
+func (api *API) regenerateRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	recoveryCodes, err := totp.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashedCodes := make([][]byte, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashedCodes = append(hashedCodes, totp.HashRecoveryCode(code))
+	}
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteUserTOTPRecoveryCodesByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf("delete recovery codes: %w", err)
+		}
+		err = tx.InsertUserTOTPRecoveryCodes(ctx, database.InsertUserTOTPRecoveryCodesParams{
+			UserID:      userID,
+			HashedCodes: hashedCodes,
+			CreatedAt:   database.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("insert recovery codes: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// enrollTOTP generates a new secret for the user that is pending
+// verification.
+func (api *API) enrollTOTP(ctx context.Context, user database.User) (codersdk.TOTPEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return codersdk.TOTPEnrollment{}, err
+	}
+	_, err = api.Database.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: database.Now(),
+	})
+	if err != nil {
+		return codersdk.TOTPEnrollment{}, xerrors.Errorf("upsert user totp: %w", err)
+	}
+	return codersdk.TOTPEnrollment{
+		Secret: secret,
+		URL:    totp.URL(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// @Summary Enroll authenticator app with password
+// @Description Enrolls an authenticator app for a user that must use multi-factor
+// @Description authentication before they can log in. The secret is verified by
+// @Description logging in with a code from the app.
+// @ID enroll-authenticator-app-with-password
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.EnrollTOTPWithPasswordRequest true "Enroll request"
+// @Success 201 {object} codersdk.TOTPEnrollment
+// @Router /users/login/totp [post]
+func (api *API) postLoginTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = api.Logger.Named(userAuthLoggerName)
+	)
+
+	var req codersdk.EnrollTOTPWithPasswordRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	user, roles, ok := api.loginRequest(ctx, rw, r.RemoteAddr, codersdk.LoginWithPasswordRequest{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if !ok {
+		return
+	}
+
+	userSubj, err := api.userSubject(ctx, roles)
+	if err != nil {
+		logger.Error(ctx, "unable to expand user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	//nolint:gocritic // Enrolling as the user instead of as system.
+	ctx = dbauthz.As(ctx, userSubj)
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.Error(ctx, "unable to fetch user totp", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	if userTOTP.Enabled {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "An authenticator app is already enrolled.",
+		})
+		return
+	}
+	if !api.mfaRequired(roles.Roles) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Multi-factor authentication isn't required for your account. Log in to enroll an authenticator app.",
+		})
+		return
+	}
+
+	enrollment, err := api.enrollTOTP(ctx, user)
+	if err != nil {
+		logger.Error(ctx, "unable to enroll totp", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, enrollment)
+}
+
+// @Summary Get user multi-factor authentication status
+// @ID get-user-multi-factor-authentication-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserMFA
+// @Router /users/{user}/mfa [get]
+func (api *API) userMFA(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	// Fetching the recovery codes first checks the actor can read the
+	// user's data.
+	recoveryCodes, err := api.Database.GetUserTOTPRecoveryCodesByUserID(ctx, user.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	userTOTP, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching authenticator app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	remaining := 0
+	for _, code := range recoveryCodes {
+		if !code.UsedAt.Valid {
+			remaining++
+		}
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UserMFA{
+		TOTPEnabled:            userTOTP.Enabled,
+		RecoveryCodesRemaining: remaining,
+		Required:               user.LoginType == database.LoginTypePassword && api.mfaRequired(user.RBACRoles),
+	})
+}
+
+// @Summary Enroll authenticator app
+// @Description Generates a new secret for an authenticator app. It must be verified
+// @Description with a code from the app before it is used to log in.
+// @ID enroll-authenticator-app
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.TOTPEnrollment
+// @Router /users/{user}/mfa/totp [post]
+func (api *API) postUserTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	if user.ID != apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only enroll an authenticator app for yourself.",
+		})
+		return
+	}
+	if user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Multi-factor authentication is only supported for password login.",
+		})
+		return
+	}
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching authenticator app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if userTOTP.Enabled {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "An authenticator app is already enrolled.",
+			Detail:  "Ask an admin to reset multi-factor authentication to enroll a different app.",
+		})
+		return
+	}
+
+	enrollment, err := api.enrollTOTP(ctx, user)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error enrolling authenticator app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, enrollment)
+}
+
+// @Summary Verify authenticator app
+// @Description Enables the enrolled authenticator app and returns recovery codes.
+// @Description The recovery codes are not shown again.
+// @ID verify-authenticator-app
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.VerifyTOTPRequest true "Verify request"
+// @Success 200 {object} codersdk.TOTPRecoveryCodes
+// @Router /users/{user}/mfa/totp/verify [post]
+func (api *API) postUserTOTPVerify(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	var req codersdk.VerifyTOTPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if user.ID != apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only verify an authenticator app for yourself.",
+		})
+		return
+	}
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Enroll an authenticator app first.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching authenticator app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if userTOTP.Enabled {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The authenticator app is already verified.",
+		})
+		return
+	}
+
+	valid, err := api.useTOTPCode(ctx, userTOTP, req.Code)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error verifying code.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Incorrect multi-factor authentication code.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "code",
+				Detail: "The code doesn't match the authenticator app.",
+			}},
+		})
+		return
+	}
+
+	recoveryCodes, err := api.regenerateRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TOTPRecoveryCodes{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replaces the recovery codes of the user. The old codes stop working.
+// @ID regenerate-recovery-codes
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.TOTPRecoveryCodes
+// @Router /users/{user}/mfa/recovery-codes [post]
+func (api *API) postUserRecoveryCodes(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+
+	if user.ID != apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only regenerate recovery codes for yourself.",
+		})
+		return
+	}
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching authenticator app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !userTOTP.Enabled {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Enroll an authenticator app first.",
+		})
+		return
+	}
+
+	recoveryCodes, err := api.regenerateRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.TOTPRecoveryCodes{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// @Summary Reset user multi-factor authentication
+// @Description Removes the authenticator app and recovery codes of the user, for
+// @Description when they have lost access to both.
+// @ID reset-user-multi-factor-authentication
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /users/{user}/mfa [delete]
+func (api *API) deleteUserMFA(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	// Users can't reset their own multi-factor authentication, otherwise a
+	// stolen session could remove it.
+	if !api.Authorize(r, rbac.ActionUpdate, user.RBACObject()) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteUserTOTPByUserID(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete totp: %w", err)
+		}
+		err = tx.DeleteUserTOTPRecoveryCodesByUserID(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete recovery codes: %w", err)
+		}
+		return nil
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resetting multi-factor authentication.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
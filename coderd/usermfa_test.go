@@ -0,0 +1,260 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/totp"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestUserTOTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Enroll", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		mfa, err := memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, mfa.TOTPEnabled)
+		require.False(t, mfa.Required)
+
+		enrollment, err := memberClient.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.NotEmpty(t, enrollment.Secret)
+		require.Contains(t, enrollment.URL, enrollment.Secret)
+
+		// The secret isn't used until it is verified.
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+
+		_, err = memberClient.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{Code: "000000"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		step := totp.Step(time.Now())
+		recoveryCodes, err := memberClient.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, step),
+		})
+		require.NoError(t, err)
+		require.Len(t, recoveryCodes.RecoveryCodes, 10)
+
+		mfa, err = memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, mfa.TOTPEnabled)
+		require.Equal(t, 10, mfa.RecoveryCodesRemaining)
+
+		// A second enrollment would replace the verified secret.
+		_, err = memberClient.EnrollTOTP(ctx, codersdk.Me)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.True(t, codersdk.IsTOTPCodeRequired(err), err)
+
+		// The code used to verify can't be used again.
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+			TOTPCode: totpCode(t, enrollment.Secret, step),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		require.False(t, codersdk.IsTOTPCodeRequired(err))
+
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+			TOTPCode: totpCode(t, enrollment.Secret, step+1),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("RecoveryCodes", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		recoveryCodes := enrollTOTP(t, memberClient)
+
+		login := codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+			TOTPCode: recoveryCodes[0],
+		}
+		_, err := client.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+		_, err = client.LoginWithPassword(ctx, login)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		mfa, err := memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 9, mfa.RecoveryCodesRemaining)
+
+		regenerated, err := memberClient.RegenerateTOTPRecoveryCodes(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, regenerated.RecoveryCodes, 10)
+
+		// The old codes stop working.
+		login.TOTPCode = recoveryCodes[1]
+		_, err = client.LoginWithPassword(ctx, login)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		login.TOTPCode = regenerated.RecoveryCodes[1]
+		_, err = client.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		userAdminClient, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID, rbac.RoleUserAdmin())
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_ = enrollTOTP(t, memberClient)
+
+		// Users can't reset their own authenticator app.
+		err := memberClient.ResetUserMFA(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = userAdminClient.ResetUserMFA(ctx, member.ID.String())
+		require.NoError(t, err)
+
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    member.Email,
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+		mfa, err := memberClient.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, mfa.TOTPEnabled)
+		require.Zero(t, mfa.RecoveryCodesRemaining)
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := otherClient.UserMFA(ctx, member.ID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Not even owners can enroll an authenticator app for someone else.
+		_, err = client.EnrollTOTP(ctx, member.ID.String())
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("RequireAdminMFA", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.RequireAdminMFA = true
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		first, err := client.CreateFirstUser(ctx, coderdtest.FirstUserParams)
+		require.NoError(t, err)
+		login := codersdk.LoginWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		}
+
+		_, err = client.LoginWithPassword(ctx, login)
+		require.True(t, codersdk.IsTOTPEnrollmentRequired(err), err)
+
+		enrollment, err := client.EnrollTOTPWithPassword(ctx, codersdk.EnrollTOTPWithPasswordRequest{
+			Email:    login.Email,
+			Password: login.Password,
+		})
+		require.NoError(t, err)
+		// Enrolling requires the password.
+		_, err = client.EnrollTOTPWithPassword(ctx, codersdk.EnrollTOTPWithPasswordRequest{
+			Email:    login.Email,
+			Password: "wrong",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		step := totp.Step(time.Now())
+		login.TOTPCode = totpCode(t, enrollment.Secret, step)
+		resp, err := client.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+		require.Len(t, resp.RecoveryCodes, 10)
+		client.SetSessionToken(resp.SessionToken)
+
+		mfa, err := client.UserMFA(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, mfa.TOTPEnabled)
+		require.True(t, mfa.Required)
+
+		login.TOTPCode = ""
+		_, err = client.LoginWithPassword(ctx, login)
+		require.True(t, codersdk.IsTOTPCodeRequired(err), err)
+		login.TOTPCode = totpCode(t, enrollment.Secret, step+1)
+		resp, err = client.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+		require.Empty(t, resp.RecoveryCodes)
+
+		// Members don't need an authenticator app.
+		_, _ = coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+		// The app can't be enrolled with a password once it is verified.
+		_, err = client.EnrollTOTPWithPassword(ctx, codersdk.EnrollTOTPWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	code, err := totp.Code(secret, step)
+	require.NoError(t, err)
+	return code
+}
+
+// enrollTOTP enrolls an authenticator app for the user of the client and
+// returns their recovery codes.
+func enrollTOTP(t *testing.T, client *codersdk.Client) []string {
+	t.Helper()
+	ctx := testutil.Context(t, testutil.WaitLong)
+	enrollment, err := client.EnrollTOTP(ctx, codersdk.Me)
+	require.NoError(t, err)
+	recoveryCodes, err := client.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+		Code: totpCode(t, enrollment.Secret, totp.Step(time.Now())),
+	})
+	require.NoError(t, err)
+	return recoveryCodes.RecoveryCodes
+}
@@ -0,0 +1,80 @@
+package userpassword
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/xerrors"
+)
+
+//go:embed denylist.txt
+var denylistFile string
+
+// denylist is the set of common passwords that are rejected by Policy.
+var denylist = func() map[string]struct{} {
+	denied := map[string]struct{}{}
+	scanner := bufio.NewScanner(strings.NewReader(denylistFile))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denied[strings.ToLower(line)] = struct{}{}
+	}
+	return denied
+}()
+
+// Policy is the password policy of a deployment. It is enforced in addition to
+// Validate whenever a user sets their password.
+type Policy struct {
+	// MinLength is the minimum number of characters of a password.
+	MinLength int
+	// CharacterClasses is the number of character classes (lowercase letters,
+	// uppercase letters, digits and symbols) a password must contain.
+	CharacterClasses int
+}
+
+// Validate checks the password against the policy and rejects common
+// passwords.
+func (p Policy) Validate(password string) error {
+	err := Validate(password)
+	if err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return xerrors.Errorf("password must be at least %d characters", p.MinLength)
+	}
+	if characterClasses(password) < p.CharacterClasses {
+		return xerrors.Errorf("password must contain at least %d of lowercase letters, uppercase letters, digits and symbols", p.CharacterClasses)
+	}
+	if _, denied := denylist[strings.ToLower(password)]; denied {
+		return xerrors.New("password is too common")
+	}
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, has := range []bool{lower, upper, digit, symbol} {
+		if has {
+			classes++
+		}
+	}
+	return classes
+}
@@ -0,0 +1,56 @@
+package userpassword_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/userpassword"
+)
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Name     string
+		Policy   userpassword.Policy
+		Password string
+		Error    string
+	}{{
+		Name:     "Empty",
+		Password: "SomeSecurePassword!",
+	}, {
+		Name:     "Entropy",
+		Password: "password",
+		Error:    "insecure password",
+	}, {
+		Name:     "MinLength",
+		Policy:   userpassword.Policy{MinLength: 20},
+		Password: "SomeSecurePassword!",
+		Error:    "at least 20 characters",
+	}, {
+		Name:     "CharacterClasses",
+		Policy:   userpassword.Policy{CharacterClasses: 4},
+		Password: "SomeSecurePassword!",
+		Error:    "at least 4 of",
+	}, {
+		Name:     "AllCharacterClasses",
+		Policy:   userpassword.Policy{MinLength: 19, CharacterClasses: 4},
+		Password: "SomeSecurePassword1!",
+	}, {
+		Name:     "Denylist",
+		Password: "Password1234",
+		Error:    "too common",
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.Policy.Validate(tc.Password)
+			if tc.Error == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.Error)
+		})
+	}
+}
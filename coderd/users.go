@@ -20,6 +20,7 @@ import (
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/rbac/rolestore"
 	"github.com/coder/coder/coderd/searchquery"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/userpassword"
@@ -108,7 +109,7 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err = userpassword.Validate(createUser.Password)
+	err = api.passwordPolicy().Validate(createUser.Password)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Password not strong enough!",
@@ -360,12 +361,18 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if req.ServiceAccount && req.Password != "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Cannot set password for a service account.",
+		})
+		return
+	}
 
 	var loginType database.LoginType
-	if req.DisableLogin {
+	if req.DisableLogin || req.ServiceAccount {
 		loginType = database.LoginTypeNone
 	} else {
-		err = userpassword.Validate(req.Password)
+		err = api.passwordPolicy().Validate(req.Password)
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message: "Password not strong enough!",
@@ -403,6 +410,7 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Users: []telemetry.User{telemetry.ConvertUser(user)},
 	})
+	api.Webhooks.User(ctx, database.WebhookEventUserCreated, user)
 
 	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.User(user, []uuid.UUID{req.OrganizationID}))
 }
@@ -691,6 +699,21 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 			return
 		}
 		aReq.New = suspendedUser
+		if status == database.UserStatusSuspended && user.Status != database.UserStatusSuspended {
+			api.Webhooks.User(ctx, database.WebhookEventUserSuspended, suspendedUser)
+		}
+		if status == database.UserStatusActive {
+			// Activating a locked user unlocks them, so their previous failed
+			// logins must not lock them again.
+			err = api.clearLoginFailures(ctx, user)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error clearing user's failed logins.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		}
 
 		organizations, err := userOrganizationIDs(ctx, api, user)
 		if err != nil {
@@ -734,7 +757,19 @@ func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := userpassword.Validate(params.Password)
+	if !api.Authorize(r, rbac.ActionUpdate, user.UserDataRBACObject()) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	if user.IsServiceAccount {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Service accounts cannot have a password.",
+		})
+		return
+	}
+
+	err := api.passwordPolicy().Validate(params.Password)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Invalid password.",
@@ -780,6 +815,20 @@ func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	reused, err := api.passwordReused(ctx, user, params.Password)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error checking password history.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if reused {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "New password cannot match a previous password.",
+		})
+		return
+	}
 
 	hashedPassword, err := userpassword.Hash(params.Password)
 	if err != nil {
@@ -791,6 +840,11 @@ func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	err = api.Database.InTx(func(tx database.Store) error {
+		err = api.rememberPassword(ctx, tx, user)
+		if err != nil {
+			return err
+		}
+
 		err = tx.UpdateUserHashedPassword(ctx, database.UpdateUserHashedPasswordParams{
 			ID:             user.ID,
 			HashedPassword: []byte(hashedPassword),
@@ -945,10 +999,12 @@ func UpdateSiteUserRoles(ctx context.Context, db database.Store, args database.U
 		if _, ok := rbac.IsOrgRole(r); ok {
 			return database.User{}, xerrors.Errorf("Must only update site wide roles")
 		}
+	}
 
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.User{}, xerrors.Errorf("%q is not a supported role", r)
-		}
+	// All roles should be valid roles, custom roles must exist.
+	//nolint:gocritic // Custom roles are looked up regardless of who assigns them.
+	if _, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), db, args.GrantedRoles); err != nil {
+		return database.User{}, err
 	}
 
 	updatedUser, err := db.UpdateUserRoles(ctx, args)
@@ -1082,8 +1138,9 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 			CreatedAt: database.Now(),
 			UpdatedAt: database.Now(),
 			// All new users are defaulted to members of the site.
-			RBACRoles: []string{},
-			LoginType: req.LoginType,
+			RBACRoles:        []string{},
+			LoginType:        req.LoginType,
+			IsServiceAccount: req.ServiceAccount,
 		}
 		// If a user signs up with OAuth, they can have no password!
 		if req.Password != "" {
@@ -1171,5 +1228,6 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		Scope:           codersdk.APIKeyScope(k.Scope),
 		LifetimeSeconds: k.LifetimeSeconds,
 		TokenName:       k.TokenName,
+		AllowList:       k.AllowList,
 	}
 }
@@ -537,6 +537,51 @@ func TestPostUsers(t *testing.T) {
 		assert.Equal(t, firstUser.OrganizationID, user.OrganizationIDs[0])
 	})
 
+	t.Run("ServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		firstUser := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			OrganizationID: firstUser.OrganizationID,
+			Email:          "bot@coder.com",
+			Username:       "bot",
+			Password:       "SomeSecurePassword!",
+			ServiceAccount: true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		user, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			OrganizationID: firstUser.OrganizationID,
+			Email:          "bot@coder.com",
+			Username:       "bot",
+			ServiceAccount: true,
+		})
+		require.NoError(t, err)
+		require.True(t, user.ServiceAccount)
+		require.Equal(t, codersdk.LoginTypeNone, user.LoginType)
+
+		err = client.UpdateUserPassword(ctx, user.ID.String(), codersdk.UpdateUserPasswordRequest{
+			Password: "SomeSecurePassword!",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// The service account authenticates with a token created by an admin.
+		token, err := client.CreateToken(ctx, user.ID.String(), codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+		botClient := codersdk.New(client.URL)
+		botClient.SetSessionToken(token.Key)
+		me, err := botClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, me.ID)
+		require.True(t, me.ServiceAccount)
+	})
+
 	t.Run("LastSeenAt", func(t *testing.T) {
 		t.Parallel()
 		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
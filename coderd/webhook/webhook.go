@@ -0,0 +1,482 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/codersdk"
+)
+
+const (
+	// DefaultInterval is how often due deliveries and imminent autostops are
+	// checked when no interval is provided.
+	DefaultInterval = 10 * time.Second
+	// DefaultMaxAttempts is the number of attempts after which a delivery is
+	// marked as failed when no maximum is provided.
+	DefaultMaxAttempts = 8
+	// DefaultRetryBackoff is the delay before the first retry of a delivery
+	// when no backoff is provided. It doubles with every attempt.
+	DefaultRetryBackoff = 30 * time.Second
+	// DefaultAutostopImminentWindow is how long before the deadline of a
+	// workspace the autostop imminent event is sent when no window is
+	// provided.
+	DefaultAutostopImminentWindow = 30 * time.Minute
+	// DefaultTimeout is the timeout of a single delivery attempt when no
+	// timeout is provided.
+	DefaultTimeout = 10 * time.Second
+
+	// maxRetryBackoff caps the exponential backoff between attempts.
+	maxRetryBackoff = time.Hour
+	// deliveriesPerRun is the maximum number of deliveries attempted at once.
+	deliveriesPerRun = 10
+	// maxErrorLength is the maximum length of a response body stored as the
+	// error of an attempt.
+	maxErrorLength = 1024
+)
+
+// Publisher enqueues webhook deliveries for events. Errors are logged rather
+// than returned, as webhooks must never fail the operation that triggered
+// the event.
+type Publisher interface {
+	// WorkspaceBuild publishes a workspace build started, succeeded or failed
+	// event. jobError is the error of the provisioner job, if any.
+	WorkspaceBuild(ctx context.Context, event database.WebhookEvent, buildID uuid.UUID, jobError string)
+	// TemplateVersionActivated publishes that the version became the active
+	// version of the template.
+	TemplateVersionActivated(ctx context.Context, template database.Template, version database.TemplateVersion)
+	// User publishes a user created or suspended event.
+	User(ctx context.Context, event database.WebhookEvent, user database.User)
+}
+
+// NewNop returns a Publisher that does nothing.
+func NewNop() Publisher {
+	return nop{}
+}
+
+type nop struct{}
+
+func (nop) WorkspaceBuild(context.Context, database.WebhookEvent, uuid.UUID, string) {}
+
+func (nop) TemplateVersionActivated(context.Context, database.Template, database.TemplateVersion) {}
+
+func (nop) User(context.Context, database.WebhookEvent, database.User) {}
+
+type Options struct {
+	Database   database.Store
+	Logger     slog.Logger
+	HTTPClient *http.Client
+
+	// Interval is how often due deliveries and imminent autostops are
+	// checked. Deliveries of new events are attempted immediately.
+	Interval time.Duration
+	// MaxAttempts is the number of attempts after which a delivery is
+	// marked as failed.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry of a delivery. It
+	// doubles with every attempt, up to an hour.
+	RetryBackoff time.Duration
+	// AutostopImminentWindow is how long before the deadline of a workspace
+	// the autostop imminent event is sent.
+	AutostopImminentWindow time.Duration
+	// Timeout is the timeout of a single delivery attempt.
+	Timeout time.Duration
+}
+
+// Dispatcher stores events as deliveries in the database and delivers them
+// to the subscribed webhooks. Deliveries are acquired with a lease, so
+// several replicas can run a dispatcher concurrently.
+type Dispatcher struct {
+	opts   Options
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	notify chan struct{}
+
+	// autostops holds the autostop imminent events that were already
+	// published, with the deadline they were published for.
+	autostopsMu sync.Mutex
+	autostops   map[uuid.UUID]time.Time
+}
+
+var _ Publisher = (*Dispatcher)(nil)
+
+// New starts a dispatcher. Close must be called to stop it.
+func New(ctx context.Context, opts Options) *Dispatcher {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = DefaultRetryBackoff
+	}
+	if opts.AutostopImminentWindow == 0 {
+		opts.AutostopImminentWindow = DefaultAutostopImminentWindow
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	//nolint:gocritic // The dispatcher reads all webhooks and deliveries.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	d := &Dispatcher{
+		opts:      opts,
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		notify:    make(chan struct{}, 1),
+		autostops: map[uuid.UUID]time.Time{},
+	}
+	go d.loop()
+	return d
+}
+
+// Close stops the dispatcher. Deliveries that are in flight are retried
+// after their lease expires.
+func (d *Dispatcher) Close() error {
+	d.cancel()
+	<-d.done
+	return nil
+}
+
+func (d *Dispatcher) WorkspaceBuild(ctx context.Context, event database.WebhookEvent, buildID uuid.UUID, jobError string) {
+	d.publish(ctx, uuid.New(), event, func(ctx context.Context, payload *codersdk.WebhookPayload) error {
+		// The build is fetched when the event is published, so the payload
+		// contains the deadline of a completed build.
+		build, err := d.opts.Database.GetWorkspaceBuildByID(ctx, buildID)
+		if err != nil {
+			return xerrors.Errorf("get workspace build: %w", err)
+		}
+		return d.fillWorkspaceBuild(ctx, payload, build, jobError)
+	})
+}
+
+func (d *Dispatcher) TemplateVersionActivated(ctx context.Context, template database.Template, version database.TemplateVersion) {
+	d.publish(ctx, uuid.New(), database.WebhookEventTemplateVersionActivated, func(_ context.Context, payload *codersdk.WebhookPayload) error {
+		payload.Template = &codersdk.WebhookPayloadTemplate{
+			ID:              template.ID,
+			Name:            template.Name,
+			ActiveVersionID: version.ID,
+		}
+		payload.TemplateVersion = &codersdk.WebhookPayloadTemplateVersion{
+			ID:   version.ID,
+			Name: version.Name,
+		}
+		return nil
+	})
+}
+
+func (d *Dispatcher) User(ctx context.Context, event database.WebhookEvent, user database.User) {
+	d.publish(ctx, uuid.New(), event, func(_ context.Context, payload *codersdk.WebhookPayload) error {
+		payload.User = &codersdk.WebhookPayloadUser{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Status:   codersdk.UserStatus(user.Status),
+		}
+		return nil
+	})
+}
+
+// publish stores a delivery of the event for every enabled webhook that
+// subscribes to it. The payload is only built if there is at least one.
+func (d *Dispatcher) publish(ctx context.Context, eventID uuid.UUID, event database.WebhookEvent, fill func(ctx context.Context, payload *codersdk.WebhookPayload) error) {
+	err := d.enqueue(ctx, eventID, event, fill)
+	if err != nil {
+		d.opts.Logger.Error(ctx, "publish webhook event",
+			slog.F("event", event),
+			slog.F("event_id", eventID),
+			slog.Error(err),
+		)
+		return
+	}
+	select {
+	case d.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, eventID uuid.UUID, event database.WebhookEvent, fill func(ctx context.Context, payload *codersdk.WebhookPayload) error) error {
+	//nolint:gocritic // The dispatcher reads all webhooks and the objects of the event.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	webhooks, err := d.opts.Database.GetEnabledWebhooksByEvent(ctx, event)
+	if err != nil {
+		return xerrors.Errorf("get webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	now := database.Now()
+	payload := codersdk.WebhookPayload{
+		ID:        eventID,
+		Event:     codersdk.WebhookEvent(event),
+		Timestamp: now,
+	}
+	err = fill(ctx, &payload)
+	if err != nil {
+		return xerrors.Errorf("build payload: %w", err)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		err = d.opts.Database.InsertWebhookDelivery(ctx, database.InsertWebhookDeliveryParams{
+			// The ID is derived from the event, so that the same event
+			// published by several replicas is only delivered once.
+			ID:            uuid.NewSHA1(eventID, webhook.ID[:]),
+			WebhookID:     webhook.ID,
+			CreatedAt:     now,
+			Event:         event,
+			Payload:       raw,
+			NextAttemptAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert delivery for webhook %q: %w", webhook.Name, err)
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) fillWorkspaceBuild(ctx context.Context, payload *codersdk.WebhookPayload, build database.WorkspaceBuild, jobError string) error {
+	workspace, err := d.opts.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	owner, err := d.opts.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		return xerrors.Errorf("get workspace owner: %w", err)
+	}
+	payload.Workspace = &codersdk.WebhookPayloadWorkspace{
+		ID:         workspace.ID,
+		Name:       workspace.Name,
+		OwnerID:    owner.ID,
+		OwnerName:  owner.Username,
+		TemplateID: workspace.TemplateID,
+	}
+	payload.WorkspaceBuild = &codersdk.WebhookPayloadWorkspaceBuild{
+		ID:                build.ID,
+		BuildNumber:       build.BuildNumber,
+		Transition:        codersdk.WorkspaceTransition(build.Transition),
+		Reason:            codersdk.BuildReason(build.Reason),
+		InitiatorID:       build.InitiatorID,
+		TemplateVersionID: build.TemplateVersionID,
+		Error:             jobError,
+	}
+	if !build.Deadline.IsZero() {
+		deadline := build.Deadline
+		payload.WorkspaceBuild.Deadline = &deadline
+	}
+	return nil
+}
+
+func (d *Dispatcher) loop() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+	for {
+		d.publishAutostopsImminent(d.ctx)
+		if d.deliver(d.ctx) {
+			// A full batch was delivered, so there may be more.
+			select {
+			case d.notify <- struct{}{}:
+			default:
+			}
+		}
+
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.notify:
+		}
+	}
+}
+
+// publishAutostopsImminent publishes an event for every running workspace
+// that will be stopped automatically within the window. The event ID is
+// derived from the build and its deadline, so an event is published again
+// if the deadline changes.
+func (d *Dispatcher) publishAutostopsImminent(ctx context.Context) {
+	now := database.Now()
+	builds, err := d.opts.Database.GetLatestWorkspaceBuildsWithDeadlineBetween(ctx, database.GetLatestWorkspaceBuildsWithDeadlineBetweenParams{
+		StartTime: now,
+		EndTime:   now.Add(d.opts.AutostopImminentWindow),
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			d.opts.Logger.Error(ctx, "get workspace builds with imminent autostop", slog.Error(err))
+		}
+		return
+	}
+
+	d.autostopsMu.Lock()
+	defer d.autostopsMu.Unlock()
+	for id, deadline := range d.autostops {
+		if deadline.Before(now) {
+			delete(d.autostops, id)
+		}
+	}
+	for _, build := range builds {
+		build := build
+		var deadline [8]byte
+		binary.BigEndian.PutUint64(deadline[:], uint64(build.Deadline.UnixNano()))
+		eventID := uuid.NewSHA1(build.ID, deadline[:])
+		if _, ok := d.autostops[eventID]; ok {
+			continue
+		}
+		d.autostops[eventID] = build.Deadline
+		d.publish(ctx, eventID, database.WebhookEventWorkspaceAutostopImminent, func(ctx context.Context, payload *codersdk.WebhookPayload) error {
+			return d.fillWorkspaceBuild(ctx, payload, build, "")
+		})
+	}
+}
+
+// deliver attempts the deliveries that are due. It returns true if the
+// number of deliveries reached the limit of a single run.
+func (d *Dispatcher) deliver(ctx context.Context) bool {
+	now := database.Now()
+	deliveries, err := d.opts.Database.AcquireWebhookDeliveries(ctx, database.AcquireWebhookDeliveriesParams{
+		Now: now,
+		// Other replicas skip the deliveries until the attempts would have
+		// timed out.
+		LeaseExpiresAt: now.Add(2 * d.opts.Timeout),
+		LimitOpt:       deliveriesPerRun,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			d.opts.Logger.Error(ctx, "acquire webhook deliveries", slog.Error(err))
+		}
+		return false
+	}
+
+	// We only use errgroup here for convenience of API, not for early
+	// cancellation. Failures are stored with the delivery.
+	var eg errgroup.Group
+	for _, delivery := range deliveries {
+		delivery := delivery
+		eg.Go(func() error {
+			d.attempt(ctx, delivery)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return len(deliveries) == deliveriesPerRun
+}
+
+// attempt sends a delivery to its webhook and stores the result.
+func (d *Dispatcher) attempt(ctx context.Context, delivery database.WebhookDelivery) {
+	logger := d.opts.Logger.With(
+		slog.F("delivery_id", delivery.ID),
+		slog.F("webhook_id", delivery.WebhookID),
+		slog.F("event", delivery.Event),
+		slog.F("attempt", delivery.Attempts),
+	)
+	webhook, err := d.opts.Database.GetWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error(ctx, "get webhook", slog.Error(err))
+		}
+		return
+	}
+
+	statusCode, err := d.send(ctx, webhook, delivery)
+	if ctx.Err() != nil {
+		// The delivery is retried by the next dispatcher that acquires it.
+		return
+	}
+
+	now := database.Now()
+	params := database.UpdateWebhookDeliveryByIDParams{
+		ID:            delivery.ID,
+		Status:        database.WebhookDeliveryStatusDelivered,
+		NextAttemptAt: now,
+		DeliveredAt:   sql.NullTime{Time: now, Valid: true},
+	}
+	if statusCode != 0 {
+		params.StatusCode = sql.NullInt32{Int32: int32(statusCode), Valid: true}
+	}
+	if err != nil {
+		logger.Debug(ctx, "webhook delivery attempt failed", slog.Error(err))
+		params.Error = err.Error()
+		params.DeliveredAt = sql.NullTime{}
+		if int(delivery.Attempts) >= d.opts.MaxAttempts {
+			params.Status = database.WebhookDeliveryStatusFailed
+		} else {
+			params.Status = database.WebhookDeliveryStatusPending
+			params.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		}
+	}
+	err = d.opts.Database.UpdateWebhookDeliveryByID(ctx, params)
+	if err != nil && ctx.Err() == nil {
+		logger.Error(ctx, "update webhook delivery", slog.Error(err))
+	}
+}
+
+// send posts the signed payload of the delivery to the webhook. The status
+// code is returned if a response was received.
+func (d *Dispatcher) send(ctx context.Context, webhook database.Webhook, delivery database.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Coder-Webhook")
+	req.Header.Set(codersdk.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(codersdk.WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(codersdk.WebhookSignatureHeader, codersdk.WebhookSignature(webhook.Secret, delivery.Payload))
+
+	res, err := d.opts.HTTPClient.Do(req)
+	if err != nil {
+		return 0, xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorLength))
+		msg := fmt.Sprintf("unexpected status code %d", res.StatusCode)
+		if len(body) > 0 {
+			msg += ": " + string(body)
+		}
+		return res.StatusCode, xerrors.New(msg)
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxErrorLength))
+	return res.StatusCode, nil
+}
+
+// backoff returns the delay before the attempt after the given one.
+func (d *Dispatcher) backoff(attempts int32) time.Duration {
+	backoff := d.opts.RetryBackoff
+	for i := int32(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,327 @@
+package webhook_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/webhook"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type receivedDelivery struct {
+	Header  http.Header
+	Body    []byte
+	Payload codersdk.WebhookPayload
+}
+
+// receiver returns a local HTTP server that records deliveries. The status
+// function returns the status code of each attempt.
+func receiver(t *testing.T, status func(attempt int64) int) (string, <-chan receivedDelivery) {
+	t.Helper()
+	received := make(chan receivedDelivery, 32)
+	var attempts atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		code := status(attempts.Add(1))
+		rw.WriteHeader(code)
+		if code >= 300 {
+			_, _ = rw.Write([]byte("try again later"))
+			return
+		}
+		var payload codersdk.WebhookPayload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		received <- receivedDelivery{
+			Header:  r.Header.Clone(),
+			Body:    body,
+			Payload: payload,
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, received
+}
+
+func alwaysOK(int64) int {
+	return http.StatusOK
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deliver", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		url, received := receiver(t, alwaysOK)
+		hook := dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Secret:  "supersecret",
+			Events:  []database.WebhookEvent{database.WebhookEventUserCreated},
+			Enabled: true,
+		})
+		// Neither of these should receive the event.
+		_ = dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Events:  []database.WebhookEvent{database.WebhookEventUserCreated},
+			Enabled: false,
+		})
+		_ = dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Events:  []database.WebhookEvent{database.WebhookEventUserSuspended},
+			Enabled: true,
+		})
+
+		dispatcher := webhook.New(ctx, webhook.Options{
+			Database: db,
+			Logger:   slogtest.Make(t, nil),
+		})
+		defer dispatcher.Close()
+
+		user := dbgen.User(t, db, database.User{})
+		dispatcher.User(ctx, database.WebhookEventUserCreated, user)
+
+		var delivery receivedDelivery
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for delivery")
+		case delivery = <-received:
+		}
+		require.Equal(t, string(codersdk.WebhookEventUserCreated), delivery.Header.Get(codersdk.WebhookEventHeader))
+		require.True(t, codersdk.VerifyWebhookSignature("supersecret", delivery.Body, delivery.Header.Get(codersdk.WebhookSignatureHeader)))
+		require.False(t, codersdk.VerifyWebhookSignature("wrong", delivery.Body, delivery.Header.Get(codersdk.WebhookSignatureHeader)))
+		require.Equal(t, codersdk.WebhookEventUserCreated, delivery.Payload.Event)
+		require.NotNil(t, delivery.Payload.User)
+		require.Equal(t, user.ID, delivery.Payload.User.ID)
+		require.Equal(t, user.Username, delivery.Payload.User.Username)
+
+		require.Eventually(t, func() bool {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: hook.ID,
+			})
+			return assert.NoError(t, err) && len(deliveries) == 1 &&
+				deliveries[0].Status == database.WebhookDeliveryStatusDelivered &&
+				deliveries[0].ID.String() == delivery.Header.Get(codersdk.WebhookDeliveryHeader)
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		select {
+		case delivery := <-received:
+			t.Fatalf("unexpected delivery: %s", delivery.Body)
+		default:
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		url, received := receiver(t, func(attempt int64) int {
+			if attempt < 3 {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusOK
+		})
+		hook := dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Events:  []database.WebhookEvent{database.WebhookEventUserSuspended},
+			Enabled: true,
+		})
+
+		dispatcher := webhook.New(ctx, webhook.Options{
+			Database:     db,
+			Logger:       slogtest.Make(t, nil),
+			Interval:     testutil.IntervalFast,
+			RetryBackoff: time.Millisecond,
+		})
+		defer dispatcher.Close()
+
+		user := dbgen.User(t, db, database.User{Status: database.UserStatusSuspended})
+		dispatcher.User(ctx, database.WebhookEventUserSuspended, user)
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for delivery")
+		case <-received:
+		}
+		require.Eventually(t, func() bool {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: hook.ID,
+			})
+			return assert.NoError(t, err) && len(deliveries) == 1 &&
+				deliveries[0].Status == database.WebhookDeliveryStatusDelivered &&
+				deliveries[0].Attempts == 3 &&
+				deliveries[0].StatusCode.Int32 == http.StatusOK
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		url, _ := receiver(t, func(int64) int {
+			return http.StatusInternalServerError
+		})
+		hook := dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Events:  []database.WebhookEvent{database.WebhookEventUserCreated},
+			Enabled: true,
+		})
+
+		dispatcher := webhook.New(ctx, webhook.Options{
+			Database:     db,
+			Logger:       slogtest.Make(t, nil),
+			Interval:     testutil.IntervalFast,
+			RetryBackoff: time.Millisecond,
+			MaxAttempts:  2,
+		})
+		defer dispatcher.Close()
+
+		dispatcher.User(ctx, database.WebhookEventUserCreated, dbgen.User(t, db, database.User{}))
+
+		require.Eventually(t, func() bool {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: hook.ID,
+			})
+			return assert.NoError(t, err) && len(deliveries) == 1 &&
+				deliveries[0].Status == database.WebhookDeliveryStatusFailed &&
+				deliveries[0].Attempts == 2 &&
+				deliveries[0].StatusCode.Int32 == http.StatusInternalServerError &&
+				deliveries[0].Error == "unexpected status code 500: try again later"
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("WorkspaceBuild", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		url, received := receiver(t, alwaysOK)
+		_ = dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Events:  []database.WebhookEvent{database.WebhookEventWorkspaceBuildFailed},
+			Enabled: true,
+		})
+
+		dispatcher := webhook.New(ctx, webhook.Options{
+			Database: db,
+			Logger:   slogtest.Make(t, nil),
+		})
+		defer dispatcher.Close()
+
+		user := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			Transition:  database.WorkspaceTransitionStart,
+		})
+		dispatcher.WorkspaceBuild(ctx, database.WebhookEventWorkspaceBuildFailed, build.ID, "terraform apply failed")
+
+		var delivery receivedDelivery
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for delivery")
+		case delivery = <-received:
+		}
+		require.Equal(t, codersdk.WebhookEventWorkspaceBuildFailed, delivery.Payload.Event)
+		require.NotNil(t, delivery.Payload.Workspace)
+		require.Equal(t, workspace.ID, delivery.Payload.Workspace.ID)
+		require.Equal(t, user.Username, delivery.Payload.Workspace.OwnerName)
+		require.NotNil(t, delivery.Payload.WorkspaceBuild)
+		require.Equal(t, build.ID, delivery.Payload.WorkspaceBuild.ID)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, delivery.Payload.WorkspaceBuild.Transition)
+		require.Equal(t, "terraform apply failed", delivery.Payload.WorkspaceBuild.Error)
+	})
+
+	t.Run("AutostopImminent", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db := dbfake.New()
+		url, received := receiver(t, alwaysOK)
+		hook := dbgen.Webhook(t, db, database.Webhook{
+			Url:     url,
+			Events:  []database.WebhookEvent{database.WebhookEventWorkspaceAutostopImminent},
+			Enabled: true,
+		})
+
+		user := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID})
+		job := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			CompletedAt: sql.NullTime{Time: database.Now(), Valid: true},
+		})
+		deadline := database.Now().Add(10 * time.Minute)
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			JobID:       job.ID,
+			Transition:  database.WorkspaceTransitionStart,
+			Deadline:    deadline,
+		})
+		// A workspace that is stopped later should not be notified yet.
+		laterJob := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			CompletedAt: sql.NullTime{Time: database.Now(), Valid: true},
+		})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID}).ID,
+			JobID:       laterJob.ID,
+			Transition:  database.WorkspaceTransitionStart,
+			Deadline:    database.Now().Add(2 * time.Hour),
+		})
+
+		// Several dispatchers simulate several replicas.
+		for i := 0; i < 2; i++ {
+			dispatcher := webhook.New(ctx, webhook.Options{
+				Database: db,
+				Logger:   slogtest.Make(t, nil),
+				Interval: testutil.IntervalFast,
+			})
+			defer dispatcher.Close()
+		}
+
+		var delivery receivedDelivery
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for delivery")
+		case delivery = <-received:
+		}
+		require.Equal(t, codersdk.WebhookEventWorkspaceAutostopImminent, delivery.Payload.Event)
+		require.Equal(t, build.ID, delivery.Payload.WorkspaceBuild.ID)
+		require.NotNil(t, delivery.Payload.WorkspaceBuild.Deadline)
+		require.WithinDuration(t, deadline, *delivery.Payload.WorkspaceBuild.Deadline, time.Second)
+
+		// Let the dispatchers tick a few more times to ensure the event is
+		// only delivered once.
+		time.Sleep(10 * testutil.IntervalFast)
+		deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: hook.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+	})
+
+	t.Run("Nop", func(t *testing.T) {
+		t.Parallel()
+		publisher := webhook.NewNop()
+		publisher.User(context.Background(), database.WebhookEventUserCreated, database.User{ID: uuid.New()})
+	})
+}
@@ -0,0 +1,353 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get webhooks
+// @ID get-webhooks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Success 200 {array} codersdk.Webhook
+// @Router /webhooks [get]
+func (api *API) webhooks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceWebhook) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	webhooks, err := api.Database.GetWebhooks(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhooks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		converted = append(converted, convertWebhook(webhook))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get webhook by ID
+// @ID get-webhook-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [get]
+func (api *API) webhook(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, convertWebhook(httpmw.WebhookParam(r)))
+}
+
+// @Summary Create webhook
+// @ID create-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param request body codersdk.CreateWebhookRequest true "Create webhook request"
+// @Success 201 {object} codersdk.Webhook
+// @Router /webhooks [post]
+func (api *API) postWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	events, ok := convertWebhookEvents(ctx, rw, req.Events)
+	if !ok {
+		return
+	}
+
+	now := database.Now()
+	webhook, err := api.Database.InsertWebhook(ctx, database.InsertWebhookParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      req.Name,
+		Url:       req.URL,
+		Secret:    req.Secret,
+		Events:    events,
+		Enabled:   req.Enabled,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Webhook with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = webhook
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWebhook(webhook))
+}
+
+// @Summary Update webhook
+// @ID update-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param request body codersdk.UpdateWebhookRequest true "Update webhook request"
+// @Success 200 {object} codersdk.Webhook
+// @Router /webhooks/{webhook} [patch]
+func (api *API) patchWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		webhook           = httpmw.WebhookParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = webhook
+
+	var req codersdk.UpdateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	events, ok := convertWebhookEvents(ctx, rw, req.Events)
+	if !ok {
+		return
+	}
+
+	secret := webhook.Secret
+	if req.Secret != "" {
+		secret = req.Secret
+	}
+	updated, err := api.Database.UpdateWebhookByID(ctx, database.UpdateWebhookByIDParams{
+		ID:        webhook.ID,
+		UpdatedAt: database.Now(),
+		Name:      req.Name,
+		Url:       req.URL,
+		Secret:    secret,
+		Events:    events,
+		Enabled:   req.Enabled,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Webhook with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = updated
+	httpapi.Write(ctx, rw, http.StatusOK, convertWebhook(updated))
+}
+
+// @Summary Delete webhook
+// @ID delete-webhook
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /webhooks/{webhook} [delete]
+func (api *API) deleteWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		webhook           = httpmw.WebhookParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = webhook
+
+	err := api.Database.DeleteWebhookByID(ctx, webhook.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Webhook has been deleted!",
+	})
+}
+
+// @Summary Get webhook deliveries
+// @ID get-webhook-deliveries
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param limit query int false "Maximum number of deliveries to return, most recent first"
+// @Success 200 {array} codersdk.WebhookDelivery
+// @Router /webhooks/{webhook}/deliveries [get]
+func (api *API) webhookDeliveries(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		webhook = httpmw.WebhookParam(r)
+	)
+
+	vals := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	limit := p.UInt(vals, 0, "limit")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	deliveries, err := api.Database.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: webhook.ID,
+		LimitOpt:  int32(limit),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook deliveries.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		converted = append(converted, convertWebhookDelivery(delivery))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// convertWebhookEvents validates the events of a webhook request, writing
+// a response and returning false if any of them is unknown.
+func convertWebhookEvents(ctx context.Context, rw http.ResponseWriter, events []codersdk.WebhookEvent) ([]database.WebhookEvent, bool) {
+	converted := make([]database.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(codersdk.WebhookEvents, event) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Unknown webhook event %q.", event),
+				Validations: []codersdk.ValidationError{{
+					Field:  "events",
+					Detail: fmt.Sprintf("Must be one of: %v", codersdk.WebhookEvents),
+				}},
+			})
+			return nil, false
+		}
+		if slices.Contains(converted, database.WebhookEvent(event)) {
+			continue
+		}
+		converted = append(converted, database.WebhookEvent(event))
+	}
+	return converted, true
+}
+
+func convertWebhook(webhook database.Webhook) codersdk.Webhook {
+	events := make([]codersdk.WebhookEvent, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, codersdk.WebhookEvent(event))
+	}
+	return codersdk.Webhook{
+		ID:        webhook.ID,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+		Name:      webhook.Name,
+		URL:       webhook.Url,
+		Events:    events,
+		Enabled:   webhook.Enabled,
+	}
+}
+
+func convertWebhookDelivery(delivery database.WebhookDelivery) codersdk.WebhookDelivery {
+	converted := codersdk.WebhookDelivery{
+		ID:        delivery.ID,
+		WebhookID: delivery.WebhookID,
+		CreatedAt: delivery.CreatedAt,
+		Event:     codersdk.WebhookEvent(delivery.Event),
+		Status:    codersdk.WebhookDeliveryStatus(delivery.Status),
+		Attempts:  delivery.Attempts,
+		Error:     delivery.Error,
+		Payload:   delivery.Payload,
+	}
+	if delivery.Status == database.WebhookDeliveryStatusPending {
+		converted.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if delivery.LastAttemptAt.Valid {
+		converted.LastAttemptAt = &delivery.LastAttemptAt.Time
+	}
+	if delivery.DeliveredAt.Valid {
+		converted.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+	if delivery.StatusCode.Valid {
+		converted.StatusCode = &delivery.StatusCode.Int32
+	}
+	return converted
+}
@@ -0,0 +1,167 @@
+package coderd_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		auditor.ResetLogs()
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "chat",
+			URL:    "http://localhost/hook",
+			Secret: "secret",
+			Events: []codersdk.WebhookEvent{
+				codersdk.WebhookEventUserCreated,
+				codersdk.WebhookEventUserCreated,
+			},
+			Enabled: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "chat", webhook.Name)
+		require.Equal(t, []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated}, webhook.Events)
+
+		_, err = client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "chat",
+			URL:    "http://localhost/other",
+			Secret: "secret",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		webhook, err = client.UpdateWebhook(ctx, webhook.ID, codersdk.UpdateWebhookRequest{
+			Name:   "chat",
+			URL:    "http://localhost/hook",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed},
+		})
+		require.NoError(t, err)
+		require.False(t, webhook.Enabled)
+		require.Equal(t, []codersdk.WebhookEvent{codersdk.WebhookEventWorkspaceBuildFailed}, webhook.Events)
+
+		webhooks, err := client.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, webhook, webhooks[0])
+
+		err = client.DeleteWebhook(ctx, webhook.ID)
+		require.NoError(t, err)
+		_, err = client.Webhook(ctx, webhook.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, 3)
+		assert.Equal(t, database.AuditActionCreate, logs[0].Action)
+		assert.Equal(t, database.ResourceTypeWebhook, logs[0].ResourceType)
+		assert.Equal(t, database.AuditActionWrite, logs[1].Action)
+		assert.Equal(t, database.AuditActionDelete, logs[2].Action)
+	})
+
+	t.Run("UnknownEvent", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "chat",
+			URL:    "http://localhost/hook",
+			Secret: "secret",
+			Events: []codersdk.WebhookEvent{"workspace_deleted"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.Webhooks(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:   "chat",
+			URL:    "http://localhost/hook",
+			Secret: "secret",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("DeliverUserCreated", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		headers := make(chan http.Header, 1)
+		bodies := make(chan []byte, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			headers <- r.Header.Clone()
+			bodies <- body
+		}))
+		t.Cleanup(srv.Close)
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			Name:    "receiver",
+			URL:     srv.URL,
+			Secret:  "secret",
+			Events:  []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+			Enabled: true,
+		})
+		require.NoError(t, err)
+
+		_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		var header http.Header
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for delivery")
+		case header = <-headers:
+		}
+		body := <-bodies
+		require.Equal(t, string(codersdk.WebhookEventUserCreated), header.Get(codersdk.WebhookEventHeader))
+		require.True(t, codersdk.VerifyWebhookSignature("secret", body, header.Get(codersdk.WebhookSignatureHeader)))
+		require.Contains(t, string(body), user.ID.String())
+
+		require.Eventually(t, func() bool {
+			deliveries, err := client.WebhookDeliveries(ctx, webhook.ID, 0)
+			if !assert.NoError(t, err) || len(deliveries) != 1 {
+				return false
+			}
+			return deliveries[0].Status == codersdk.WebhookDeliveryStatusDelivered
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+}
@@ -0,0 +1,318 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/workspaceapps"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/tailnet"
+)
+
+// attributedConnectionTTL is how long the attribution of a connection is kept
+// if the agent never reports its disconnect.
+const attributedConnectionTTL = 24 * time.Hour
+
+// tailnetClients tracks the users that coordinate connections to agents
+// through this replica. Agents only see tailnet addresses, so this is how
+// connections reported by agents are attributed to users and their real IP.
+// Connections of clients coordinating through other replicas can't be
+// attributed, and are audited without a user.
+type tailnetClients struct {
+	mu sync.Mutex
+	// clients maps agent IDs to the clients connecting to them.
+	clients map[uuid.UUID]map[uuid.UUID]tailnetClient
+	// servers are the tailnet addresses this replica uses to proxy apps
+	// to agents.
+	servers map[netip.Addr]struct{}
+	// connections keeps the attribution of open connections, so the
+	// disconnect is attributed even if the client has gone away.
+	connections map[uuid.UUID]tailnetClient
+}
+
+type tailnetClient struct {
+	userID uuid.UUID
+	ip     string
+	added  time.Time
+}
+
+func newTailnetClients() *tailnetClients {
+	return &tailnetClients{
+		clients:     map[uuid.UUID]map[uuid.UUID]tailnetClient{},
+		servers:     map[netip.Addr]struct{}{},
+		connections: map[uuid.UUID]tailnetClient{},
+	}
+}
+
+// addServer registers a tailnet address this replica proxies apps from. The
+// returned func unregisters it.
+func (t *tailnetClients) addServer(addr netip.Addr) (remove func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.servers[addr] = struct{}{}
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.servers, addr)
+	}
+}
+
+func (t *tailnetClients) isServer(addr netip.Addr) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.servers[addr]
+	return ok
+}
+
+// add registers a client coordinating with an agent. The returned func
+// unregisters it.
+func (t *tailnetClients) add(agentID, clientID, userID uuid.UUID, remoteAddr string) (remove func()) {
+	ip := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		ip = host
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.clients[agentID] == nil {
+		t.clients[agentID] = map[uuid.UUID]tailnetClient{}
+	}
+	t.clients[agentID][clientID] = tailnetClient{userID: userID, ip: ip, added: time.Now()}
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.clients[agentID], clientID)
+		if len(t.clients[agentID]) == 0 {
+			delete(t.clients, agentID)
+		}
+	}
+}
+
+// attribute finds the client whose tailnet node has the given address. The
+// result is cached by connection ID until the connection closes.
+func (t *tailnetClients) attribute(coordinator tailnet.Coordinator, agentID, connectionID uuid.UUID, addr netip.Addr, closed bool) (tailnetClient, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if client, ok := t.connections[connectionID]; ok {
+		if closed {
+			delete(t.connections, connectionID)
+		}
+		return client, true
+	}
+	for clientID, client := range t.clients[agentID] {
+		node := coordinator.Node(clientID)
+		if node == nil {
+			continue
+		}
+		for _, prefix := range node.Addresses {
+			if prefix.Addr() != addr {
+				continue
+			}
+			if !closed {
+				for id, conn := range t.connections {
+					if time.Since(conn.added) > attributedConnectionTTL {
+						delete(t.connections, id)
+					}
+				}
+				t.connections[connectionID] = tailnetClient{userID: client.userID, ip: client.ip, added: time.Now()}
+			}
+			return client, true
+		}
+	}
+	return tailnetClient{}, false
+}
+
+// @Summary Report workspace agent connection
+// @ID report-workspace-agent-connection
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostConnectionRequest true "Connection event"
+// @Success 204
+// @Router /workspaceagents/me/report-connection [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentReportConnection(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostConnectionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Action != codersdk.AuditActionConnect && req.Action != codersdk.AuditActionDisconnect {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid connection action.",
+			Detail:  fmt.Sprintf("invalid connection action: %q", req.Action),
+		})
+		return
+	}
+	if req.Type != codersdk.ConnectionTypeSSH && req.Type != codersdk.ConnectionTypePortForward {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid connection type.",
+			Detail:  fmt.Sprintf("invalid connection type: %q", req.Type),
+		})
+		return
+	}
+	addr, err := netip.ParseAddr(req.IP)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid connection IP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	event := workspaceapps.ConnectionEvent{
+		ID:          req.ID,
+		Action:      req.Action,
+		Type:        req.Type,
+		Time:        req.Time,
+		WorkspaceID: workspace.ID,
+		AgentID:     workspaceAgent.ID,
+		IP:          req.IP,
+		DurationMS:  req.DurationMS,
+	}
+	if req.Port != 0 {
+		event.AppSlugOrPort = strconv.Itoa(int(req.Port))
+	}
+	client, ok := api.tailnetClients.attribute(*api.TailnetCoordinator.Load(), workspaceAgent.ID, req.ID, addr, req.Action == codersdk.AuditActionDisconnect)
+	switch {
+	case ok:
+		event.UserID = client.userID
+		event.IP = client.ip
+	case req.Type == codersdk.ConnectionTypePortForward && api.tailnetClients.isServer(addr):
+		// This replica forwards app traffic to the agent. Those connections
+		// are audited when the app is accessed.
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		// The client may coordinate through another replica, which this
+		// replica doesn't know about. The connection is still audited with
+		// the address the agent saw, but without a user.
+		api.Logger.Info(ctx, "auditing agent connection without a known user",
+			slog.F("workspace_agent_id", workspaceAgent.ID),
+			slog.F("connection_id", req.ID),
+			slog.F("connection_type", req.Type),
+			slog.F("action", req.Action),
+			slog.F("ip", req.IP),
+		)
+	}
+
+	api.AuditConnection(ctx, event)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// AuditConnection writes an audit log for a connection to a workspace agent
+// or app.
+func (api *API) AuditConnection(ctx context.Context, event workspaceapps.ConnectionEvent) {
+	//nolint:gocritic // Connections are audited on behalf of the user.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	logger := api.Logger.With(
+		slog.F("connection_id", event.ID),
+		slog.F("workspace_agent_id", event.AgentID),
+	)
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, event.WorkspaceID)
+	if err != nil {
+		logger.Error(ctx, "audit connection: get workspace", slog.Error(err))
+		return
+	}
+	owner, err := api.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		logger.Error(ctx, "audit connection: get workspace owner", slog.Error(err))
+		return
+	}
+	agent, err := api.Database.GetWorkspaceAgentByID(ctx, event.AgentID)
+	if err != nil {
+		logger.Error(ctx, "audit connection: get workspace agent", slog.Error(err))
+		return
+	}
+
+	fields := audit.AdditionalFields{
+		WorkspaceName:  workspace.Name,
+		WorkspaceOwner: owner.Username,
+		ConnectionType: string(event.Type),
+		AgentName:      agent.Name,
+		DurationMS:     event.DurationMS,
+	}
+	var app database.WorkspaceApp
+	if port, err := strconv.ParseUint(event.AppSlugOrPort, 10, 16); err == nil {
+		fields.Port = uint16(port)
+	} else if event.AppSlugOrPort != "" {
+		fields.AppSlug = event.AppSlugOrPort
+		app, err = api.Database.GetWorkspaceAppByAgentIDAndSlug(ctx, database.GetWorkspaceAppByAgentIDAndSlugParams{
+			AgentID: agent.ID,
+			Slug:    event.AppSlugOrPort,
+		})
+		if err != nil && !httpapi.Is404Error(err) {
+			logger.Error(ctx, "audit connection: get workspace app", slog.Error(err))
+			return
+		}
+	}
+	additionalFields, err := json.Marshal(fields)
+	if err != nil {
+		logger.Error(ctx, "audit connection: marshal additional fields", slog.Error(err))
+		return
+	}
+
+	auditor := *api.Auditor.Load()
+	action := database.AuditAction(event.Action)
+	// Old and New are the same, so the diff is empty.
+	if app.ID != uuid.Nil {
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.WorkspaceApp]{
+			Audit:            auditor,
+			Log:              logger,
+			UserID:           event.UserID,
+			OrganizationID:   workspace.OrganizationID,
+			RequestID:        event.ID,
+			Time:             event.Time,
+			Status:           http.StatusOK,
+			IP:               event.IP,
+			UserAgent:        event.UserAgent,
+			Action:           action,
+			AdditionalFields: additionalFields,
+			New:              app,
+			Old:              app,
+		})
+		return
+	}
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.WorkspaceAgent]{
+		Audit:            auditor,
+		Log:              logger,
+		UserID:           event.UserID,
+		OrganizationID:   workspace.OrganizationID,
+		RequestID:        event.ID,
+		Time:             event.Time,
+		Status:           http.StatusOK,
+		IP:               event.IP,
+		UserAgent:        event.UserAgent,
+		Action:           action,
+		AdditionalFields: additionalFields,
+		New:              agent,
+		Old:              agent,
+	})
+}
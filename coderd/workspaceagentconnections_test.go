@@ -0,0 +1,186 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspaceAgentReportConnection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client, daemonCloser := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			Auditor: auditor,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		daemonCloser.Close()
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+		})
+		defer agentCloser.Close()
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+		agentID := resources[0].Agents[0].ID
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		conn, err := client.DialWorkspaceAgent(ctx, agentID, &codersdk.DialWorkspaceAgentOptions{
+			Logger: slogtest.Make(t, nil).Named("client").Leveled(slog.LevelDebug),
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		output, err := session.CombinedOutput("echo test")
+		require.NoError(t, err)
+		require.Equal(t, "test", strings.TrimSpace(string(output)))
+		_ = session.Close()
+		_ = sshClient.Close()
+
+		var connect, disconnect database.AuditLog
+		require.Eventually(t, func() bool {
+			for _, alog := range auditor.AuditLogs() {
+				if alog.ResourceType != database.ResourceTypeWorkspaceAgent {
+					continue
+				}
+				switch alog.Action {
+				case database.AuditActionConnect:
+					connect = alog
+				case database.AuditActionDisconnect:
+					disconnect = alog
+				}
+			}
+			return connect.ID != uuid.Nil && disconnect.ID != uuid.Nil
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		assert.Equal(t, agentID, connect.ResourceID)
+		assert.Equal(t, user.UserID, connect.UserID)
+		assert.Equal(t, user.UserID, disconnect.UserID)
+		assert.Equal(t, connect.RequestID, disconnect.RequestID)
+		assert.Equal(t, "127.0.0.1", connect.Ip.IPNet.IP.String())
+
+		var fields audit.AdditionalFields
+		err = json.Unmarshal(disconnect.AdditionalFields, &fields)
+		require.NoError(t, err)
+		assert.Equal(t, string(codersdk.ConnectionTypeSSH), fields.ConnectionType)
+		assert.Equal(t, workspace.Name, fields.WorkspaceName)
+		assert.Equal(t, resources[0].Agents[0].Name, fields.AgentName)
+	})
+
+	t.Run("Unattributed", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Auditor:                  auditor,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+
+		// No client coordinates with the agent through this replica, as if
+		// the client was connected to another replica.
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := agentClient.PostConnection(ctx, agentsdk.PostConnectionRequest{
+			ID:     uuid.New(),
+			Action: codersdk.AuditActionConnect,
+			Type:   codersdk.ConnectionTypeSSH,
+			Time:   time.Now(),
+			IP:     "fd7a:115c:a1e0::1",
+		})
+		require.NoError(t, err)
+
+		// The connection is audited with the address the agent saw, but
+		// without a user.
+		var connect database.AuditLog
+		require.Eventually(t, func() bool {
+			for _, alog := range auditor.AuditLogs() {
+				if alog.ResourceType == database.ResourceTypeWorkspaceAgent && alog.Action == database.AuditActionConnect {
+					connect = alog
+					return true
+				}
+			}
+			return false
+		}, testutil.WaitShort, testutil.IntervalFast)
+		assert.Equal(t, uuid.Nil, connect.UserID)
+		assert.Equal(t, "fd7a:115c:a1e0::1", connect.Ip.IPNet.IP.String())
+	})
+
+	t.Run("InvalidType", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		err := agentClient.PostConnection(ctx, agentsdk.PostConnectionRequest{
+			ID:     uuid.New(),
+			Action: codersdk.AuditActionConnect,
+			Type:   codersdk.ConnectionTypeWorkspaceApp,
+			Time:   time.Now(),
+			IP:     "fd7a:115c:a1e0::1",
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+}
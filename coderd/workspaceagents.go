@@ -62,8 +62,17 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	// nolint:gocritic // Getting workspace agent scripts by agent IDs is a system function.
+	dbScripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps), api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, convertApps(dbApps), convertWorkspaceAgentScripts(dbScripts), api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -88,7 +97,7 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -116,6 +125,16 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// nolint:gocritic // Getting workspace agent scripts by agent IDs is a system function.
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent scripts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -149,6 +168,18 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// The agent may not be able to read the template if its ACL has
+	// changed, but whether sessions are recorded must not depend on it.
+	// nolint:gocritic // The agent needs the recording policy.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	vscodeProxyURI := strings.ReplaceAll(api.AppHostname, "*",
 		fmt.Sprintf("%s://{{port}}--%s--%s--%s",
 			api.AccessURL.Scheme,
@@ -173,8 +204,10 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		StartupScriptTimeout:     time.Duration(apiAgent.StartupScriptTimeoutSeconds) * time.Second,
 		ShutdownScript:           apiAgent.ShutdownScript,
 		ShutdownScriptTimeout:    time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
+		Scripts:                  convertWorkspaceAgentScripts(scripts),
 		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		Metadata:                 convertWorkspaceAgentMetadataDesc(metadata),
+		RecordSessions:           api.DeploymentValues.RecordSessions.Value() || template.RecordSessions,
 	})
 }
 
@@ -192,7 +225,7 @@ func (api *API) postWorkspaceAgentStartup(rw http.ResponseWriter, r *http.Reques
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -260,10 +293,12 @@ func (api *API) patchWorkspaceAgentStartupLogs(rw http.ResponseWriter, r *http.R
 	createdAt := make([]time.Time, 0)
 	output := make([]string, 0)
 	level := make([]database.LogLevel, 0)
+	source := make([]string, 0)
 	outputLength := 0
 	for _, logEntry := range req.Logs {
 		createdAt = append(createdAt, logEntry.CreatedAt)
 		output = append(output, logEntry.Output)
+		source = append(source, logEntry.Source)
 		outputLength += len(logEntry.Output)
 		if logEntry.Level == "" {
 			// Default to "info" to support older agents that didn't have the level field.
@@ -278,6 +313,13 @@ func (api *API) patchWorkspaceAgentStartupLogs(rw http.ResponseWriter, r *http.R
 			return
 		}
 		level = append(level, parsedLevel)
+		if len(logEntry.Source) > 64 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid log source provided.",
+				Detail:  fmt.Sprintf("log source must be at most 64 characters: %q", logEntry.Source),
+			})
+			return
+		}
 	}
 
 	logs, err := api.Database.InsertWorkspaceAgentStartupLogs(ctx, database.InsertWorkspaceAgentStartupLogsParams{
@@ -285,6 +327,7 @@ func (api *API) patchWorkspaceAgentStartupLogs(rw http.ResponseWriter, r *http.R
 		CreatedAt:    createdAt,
 		Output:       output,
 		Level:        level,
+		Source:       source,
 		OutputLength: int32(outputLength),
 	})
 	if err != nil {
@@ -590,7 +633,7 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	workspaceAgent := httpmw.WorkspaceAgentParam(r)
 
 	apiAgent, err := convertWorkspaceAgent(
-		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DERPMap, *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 	)
 	if err != nil {
@@ -686,8 +729,9 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 // See: https://github.com/coder/coder/issues/8218
 func (api *API) _dialWorkspaceAgentTailnet(agentID uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
 	clientConn, serverConn := net.Pipe()
+	ip := tailnet.IP()
 	conn, err := tailnet.NewConn(&tailnet.Options{
-		Addresses:      []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+		Addresses:      []netip.Prefix{netip.PrefixFrom(ip, 128)},
 		DERPMap:        api.DERPMap,
 		Logger:         api.Logger.Named("tailnet"),
 		BlockEndpoints: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
@@ -720,10 +764,12 @@ func (api *API) _dialWorkspaceAgentTailnet(agentID uuid.UUID) (*codersdk.Workspa
 		return nil
 	})
 	conn.SetNodeCallback(sendNodes)
+	removeServer := api.tailnetClients.addServer(ip)
 	agentConn := codersdk.NewWorkspaceAgentConn(conn, codersdk.WorkspaceAgentConnOptions{
 		AgentID: agentID,
 		AgentIP: codersdk.WorkspaceAgentIP,
 		CloseFunc: func() error {
+			removeServer()
 			cancel()
 			_ = clientConn.Close()
 			_ = serverConn.Close()
@@ -1096,13 +1142,54 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	go httpapi.Heartbeat(ctx, conn)
 
 	defer conn.Close(websocket.StatusNormalClosure, "")
-	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), workspaceAgent.ID)
+	clientID := uuid.New()
+	// Workspace proxies coordinate on behalf of their users, whose
+	// connections are audited by the proxy.
+	if apiKey, ok := httpmw.APIKeyOptional(r); ok {
+		remove := api.tailnetClients.add(workspaceAgent.ID, clientID, apiKey.UserID, r.RemoteAddr)
+		defer remove()
+	}
+	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, clientID, workspaceAgent.ID)
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, err.Error())
 		return
 	}
 }
 
+func convertWorkspaceAgentScripts(dbScripts []database.WorkspaceAgentScript) []codersdk.WorkspaceAgentScript {
+	scripts := make([]codersdk.WorkspaceAgentScript, 0)
+	for _, dbScript := range dbScripts {
+		script := codersdk.WorkspaceAgentScript{
+			ID:               dbScript.ID,
+			Name:             dbScript.Name,
+			DisplayName:      dbScript.DisplayName,
+			Script:           dbScript.Script,
+			RunOn:            codersdk.WorkspaceAgentScriptRunOn(dbScript.RunOn),
+			TimeoutSeconds:   dbScript.TimeoutSeconds,
+			StartBlocksLogin: dbScript.StartBlocksLogin,
+			DependsOn:        dbScript.DependsOn,
+			Status:           codersdk.WorkspaceAgentScriptStatus(dbScript.Status),
+		}
+		if script.DependsOn == nil {
+			script.DependsOn = []string{}
+		}
+		if dbScript.ExitCode.Valid {
+			exitCode := dbScript.ExitCode.Int32
+			script.ExitCode = &exitCode
+		}
+		if dbScript.StartedAt.Valid {
+			startedAt := dbScript.StartedAt.Time
+			script.StartedAt = &startedAt
+		}
+		if dbScript.EndedAt.Valid {
+			endedAt := dbScript.EndedAt.Time
+			script.EndedAt = &endedAt
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts
+}
+
 func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 	apps := make([]codersdk.WorkspaceApp, 0)
 	for _, dbApp := range dbApps {
@@ -1141,7 +1228,7 @@ func convertWorkspaceAgentMetadataDesc(mds []database.WorkspaceAgentMetadatum) [
 	return metadata
 }
 
-func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
+func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, scripts []codersdk.WorkspaceAgentScript, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
 	var envs map[string]string
 	if dbAgent.EnvironmentVariables.Valid {
 		err := json.Unmarshal(dbAgent.EnvironmentVariables.RawMessage, &envs)
@@ -1172,6 +1259,7 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 		Directory:                    dbAgent.Directory,
 		ExpandedDirectory:            dbAgent.ExpandedDirectory,
 		Apps:                         apps,
+		Scripts:                      scripts,
 		ConnectionTimeoutSeconds:     dbAgent.ConnectionTimeoutSeconds,
 		TroubleshootingURL:           troubleshootingURL,
 		LifecycleState:               codersdk.WorkspaceAgentLifecycle(dbAgent.LifecycleState),
@@ -1647,6 +1735,68 @@ func (api *API) workspaceAgentReportLifecycle(rw http.ResponseWriter, r *http.Re
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// @Summary Submit workspace agent script status
+// @ID submit-workspace-agent-script-status
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostScriptStatusRequest true "Workspace agent script status request"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/report-script-status [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentReportScriptStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var req agentsdk.PostScriptStatusRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	status := database.WorkspaceAgentScriptStatus(req.Status)
+	if !status.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid script status.",
+			Detail:  fmt.Sprintf("Invalid script status %q, must be be one of %q.", req.Status, database.AllWorkspaceAgentScriptStatusValues()),
+		})
+		return
+	}
+
+	var exitCode sql.NullInt32
+	if req.ExitCode != nil {
+		exitCode = sql.NullInt32{Int32: *req.ExitCode, Valid: true}
+	}
+	_, err = api.Database.UpdateWorkspaceAgentScriptStatusByID(ctx, database.UpdateWorkspaceAgentScriptStatusByIDParams{
+		ID:        req.ScriptID,
+		AgentID:   workspaceAgent.ID,
+		Status:    status,
+		ExitCode:  exitCode,
+		StartedAt: sql.NullTime{Time: req.StartedAt, Valid: !req.StartedAt.IsZero()},
+		EndedAt:   sql.NullTime{Time: req.EndedAt, Valid: !req.EndedAt.IsZero()},
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
 // @Summary Submit workspace agent application health
 // @ID submit-workspace-agent-application-health
 // @Security CoderSessionToken
@@ -2011,6 +2161,7 @@ func convertWorkspaceAgentStartupLog(logEntry database.WorkspaceAgentStartupLog)
 		CreatedAt: logEntry.CreatedAt,
 		Output:    logEntry.Output,
 		Level:     codersdk.LogLevel(logEntry.Level),
+		Source:    logEntry.Source,
 	}
 }
 
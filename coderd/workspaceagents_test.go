@@ -1166,6 +1166,106 @@ func TestWorkspaceAgent_Metadata(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWorkspaceAgent_Scripts(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.ProvisionComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							Scripts: []*proto.Agent_Script{{
+								Name:           "dotfiles",
+								Script:         "coder dotfiles -y",
+								TimeoutSeconds: 60,
+							}, {
+								Name:      "services",
+								Script:    "start-services",
+								DependsOn: []string{"dotfiles"},
+							}},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	manifest, err := agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.Len(t, manifest.Scripts, 2)
+	require.Equal(t, "dotfiles", manifest.Scripts[0].Name)
+	require.Equal(t, codersdk.WorkspaceAgentScriptRunOnStart, manifest.Scripts[0].RunOn)
+	require.EqualValues(t, 60, manifest.Scripts[0].TimeoutSeconds)
+	require.Equal(t, "services", manifest.Scripts[1].Name)
+	require.Equal(t, []string{"dotfiles"}, manifest.Scripts[1].DependsOn)
+
+	startedAt := time.Now().Add(-time.Minute)
+	endedAt := time.Now()
+	exitCode := int32(0)
+	err = agentClient.PostScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+		ScriptID:  manifest.Scripts[0].ID,
+		Status:    codersdk.WorkspaceAgentScriptStatusSuccess,
+		ExitCode:  &exitCode,
+		StartedAt: startedAt,
+		EndedAt:   endedAt,
+	})
+	require.NoError(t, err)
+
+	// Invalid statuses and unknown scripts are rejected.
+	err = agentClient.PostScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+		ScriptID: manifest.Scripts[1].ID,
+		Status:   "bogus",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	err = agentClient.PostScriptStatus(ctx, agentsdk.PostScriptStatusRequest{
+		ScriptID: uuid.New(),
+		Status:   codersdk.WorkspaceAgentScriptStatusRunning,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	agent, err := client.WorkspaceAgent(ctx, build.Resources[0].Agents[0].ID)
+	require.NoError(t, err)
+	require.Len(t, agent.Scripts, 2)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusSuccess, agent.Scripts[0].Status)
+	require.NotNil(t, agent.Scripts[0].ExitCode)
+	require.EqualValues(t, 0, *agent.Scripts[0].ExitCode)
+	require.NotNil(t, agent.Scripts[0].StartedAt)
+	require.WithinDuration(t, startedAt, *agent.Scripts[0].StartedAt, time.Second)
+	require.NotNil(t, agent.Scripts[0].EndedAt)
+	require.Equal(t, codersdk.WorkspaceAgentScriptStatusPending, agent.Scripts[1].Status)
+	require.Nil(t, agent.Scripts[1].ExitCode)
+
+	// Scripts are also included in workspace builds.
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, workspace.LatestBuild.Resources[0].Agents[0].Scripts, 2)
+}
+
 func TestWorkspaceAgent_Startup(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,181 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+)
+
+// maxSessionRecordingRequestSize bounds the size of an uploaded session
+// recording. Agents cap recordings at 10 MiB, which grows by a third when
+// base64 encoded in the JSON request.
+const maxSessionRecordingRequestSize = 16 << 20
+
+// @Summary Upload workspace agent session recording
+// @ID upload-workspace-agent-session-recording
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.PostSessionRecordingRequest true "Session recording"
+// @Success 201 {object} codersdk.WorkspaceAgentSessionRecording
+// @Router /workspaceagents/me/session-recordings [post]
+// @x-apidocgen {"skip": true}
+func (api *API) postWorkspaceAgentSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	r.Body = http.MaxBytesReader(rw, r.Body, maxSessionRecordingRequestSize)
+	var req agentsdk.PostSessionRecordingRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	recordingType := database.SessionRecordingType(req.Type)
+	if !recordingType.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid session recording type provided.",
+			Detail:  fmt.Sprintf("invalid session recording type: %q", req.Type),
+		})
+		return
+	}
+	if len(req.Data) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "No session recording data provided.",
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	recording, err := api.Database.InsertWorkspaceAgentSessionRecording(ctx, database.InsertWorkspaceAgentSessionRecordingParams{
+		ID:          req.ID,
+		CreatedAt:   database.Now(),
+		WorkspaceID: workspace.ID,
+		AgentID:     workspaceAgent.ID,
+		Type:        recordingType,
+		StartedAt:   req.StartedAt,
+		EndedAt:     req.EndedAt,
+		Truncated:   req.Truncated,
+		Size:        int64(len(req.Data)),
+		Data:        req.Data,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "Session recording has already been uploaded.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWorkspaceAgentSessionRecording(database.GetWorkspaceAgentSessionRecordingsByAgentIDRow{
+		ID:          recording.ID,
+		CreatedAt:   recording.CreatedAt,
+		WorkspaceID: recording.WorkspaceID,
+		AgentID:     recording.AgentID,
+		Type:        recording.Type,
+		StartedAt:   recording.StartedAt,
+		EndedAt:     recording.EndedAt,
+		Truncated:   recording.Truncated,
+		Size:        recording.Size,
+	}))
+}
+
+// @Summary Get session recordings by workspace agent
+// @ID get-session-recordings-by-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceAgentSessionRecording
+// @Router /workspaceagents/{workspaceagent}/session-recordings [get]
+func (api *API) workspaceAgentSessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	recordings, err := api.Database.GetWorkspaceAgentSessionRecordingsByAgentID(ctx, workspaceAgent.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recordings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiRecordings := make([]codersdk.WorkspaceAgentSessionRecording, 0, len(recordings))
+	for _, recording := range recordings {
+		apiRecordings = append(apiRecordings, convertWorkspaceAgentSessionRecording(recording))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiRecordings)
+}
+
+// @Summary Download session recording
+// @ID download-session-recording
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param recording path string true "Session recording ID" format(uuid)
+// @Success 200
+// @Router /workspaceagents/{workspaceagent}/session-recordings/{recording} [get]
+func (api *API) workspaceAgentSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	recordingID, ok := httpmw.ParseUUIDParam(rw, r, "recording")
+	if !ok {
+		return
+	}
+
+	recording, err := api.Database.GetWorkspaceAgentSessionRecordingByID(ctx, recordingID)
+	if httpapi.Is404Error(err) || (err == nil && recording.AgentID != workspaceAgent.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching session recording.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-asciicast")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", recording.ID.String()+".cast"))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(recording.Data)
+}
+
+func convertWorkspaceAgentSessionRecording(recording database.GetWorkspaceAgentSessionRecordingsByAgentIDRow) codersdk.WorkspaceAgentSessionRecording {
+	return codersdk.WorkspaceAgentSessionRecording{
+		ID:          recording.ID,
+		WorkspaceID: recording.WorkspaceID,
+		AgentID:     recording.AgentID,
+		Type:        codersdk.WorkspaceAgentSessionRecordingType(recording.Type),
+		CreatedAt:   recording.CreatedAt,
+		StartedAt:   recording.StartedAt,
+		EndedAt:     recording.EndedAt,
+		Truncated:   recording.Truncated,
+		Size:        recording.Size,
+	}
+}
@@ -0,0 +1,133 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspaceAgentSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, codersdk.Template, *agentsdk.Client, uuid.UUID) {
+		t.Helper()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		return client, user, template, agentClient, build.Resources[0].Agents[0].ID
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client, user, _, agentClient, agentID := setup(t)
+
+		data := []byte(`{"version": 2, "width": 80, "height": 24}` + "\n" + `[0.1, "o", "hello"]` + "\n")
+		req := agentsdk.PostSessionRecordingRequest{
+			ID:        uuid.New(),
+			Type:      codersdk.WorkspaceAgentSessionRecordingTypeSSH,
+			StartedAt: time.Now().Add(-time.Minute),
+			EndedAt:   time.Now(),
+			Data:      data,
+		}
+		err := agentClient.PostSessionRecording(ctx, req)
+		require.NoError(t, err)
+
+		// Uploading the same recording again must not create a duplicate.
+		err = agentClient.PostSessionRecording(ctx, req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		recordings, err := client.WorkspaceAgentSessionRecordings(ctx, agentID)
+		require.NoError(t, err)
+		require.Len(t, recordings, 1)
+		require.Equal(t, req.ID, recordings[0].ID)
+		require.Equal(t, agentID, recordings[0].AgentID)
+		require.Equal(t, codersdk.WorkspaceAgentSessionRecordingTypeSSH, recordings[0].Type)
+		require.EqualValues(t, len(data), recordings[0].Size)
+		require.False(t, recordings[0].Truncated)
+
+		got, err := client.WorkspaceAgentSessionRecording(ctx, agentID, req.ID)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+
+		// Other users must not be able to see the recordings.
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err = member.WorkspaceAgentSessionRecordings(ctx, agentID)
+		require.Error(t, err)
+		_, err = member.WorkspaceAgentSessionRecording(ctx, agentID, req.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidType", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, _, _, agentClient, _ := setup(t)
+
+		err := agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+			ID:        uuid.New(),
+			Type:      "telnet",
+			StartedAt: time.Now(),
+			EndedAt:   time.Now(),
+			Data:      []byte("{}"),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client, _, _, _, agentID := setup(t)
+
+		_, err := client.WorkspaceAgentSessionRecording(ctx, agentID, uuid.New())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("TemplatePolicy", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client, _, template, agentClient, _ := setup(t)
+
+		manifest, err := agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		require.False(t, manifest.RecordSessions)
+
+		template, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RecordSessions: true,
+		})
+		require.NoError(t, err)
+		require.True(t, template.RecordSessions)
+
+		manifest, err = agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		require.True(t, manifest.RecordSessions)
+	})
+}
@@ -0,0 +1,186 @@
+package workspaceapps
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/codersdk"
+)
+
+// DefaultAppSessionTimeout is how long an app session stays open after its
+// last request. Apps are proxied one request at a time, so this is how the
+// end of a session is detected.
+const DefaultAppSessionTimeout = 5 * time.Minute
+
+// ConnectionEvent is reported when a user connects to or disconnects from a
+// workspace app, a port or the web terminal.
+type ConnectionEvent struct {
+	// ID is the same for the connect and disconnect events of a connection.
+	ID          uuid.UUID               `json:"id" format:"uuid"`
+	Action      codersdk.AuditAction    `json:"action" enums:"connect,disconnect"`
+	Type        codersdk.ConnectionType `json:"type" enums:"reconnecting_pty,port_forward,workspace_app"`
+	Time        time.Time               `json:"time" format:"date-time"`
+	UserID      uuid.UUID               `json:"user_id" format:"uuid"`
+	WorkspaceID uuid.UUID               `json:"workspace_id" format:"uuid"`
+	AgentID     uuid.UUID               `json:"agent_id" format:"uuid"`
+	// AppSlugOrPort is empty for the web terminal.
+	AppSlugOrPort string `json:"app_slug_or_port,omitempty"`
+	IP            string `json:"ip"`
+	UserAgent     string `json:"user_agent"`
+	// DurationMS is only set on disconnect events.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+}
+
+type appSessionKey struct {
+	userID        uuid.UUID
+	agentID       uuid.UUID
+	appSlugOrPort string
+	ip            string
+}
+
+type appSession struct {
+	connect  ConnectionEvent
+	inflight int
+	lastSeen time.Time
+	// generation is incremented whenever the idle timer is replaced, so a
+	// timer that fired late doesn't end an active session.
+	generation int
+	timer      *time.Timer
+}
+
+func newConnectionEvent(r *http.Request, token SignedToken, connType codersdk.ConnectionType) ConnectionEvent {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ConnectionEvent{
+		ID:            uuid.New(),
+		Action:        codersdk.AuditActionConnect,
+		Type:          connType,
+		Time:          time.Now(),
+		UserID:        token.UserID,
+		WorkspaceID:   token.WorkspaceID,
+		AgentID:       token.AgentID,
+		AppSlugOrPort: token.AppSlugOrPort,
+		IP:            ip,
+		UserAgent:     r.UserAgent(),
+	}
+}
+
+// disconnectEvent returns the disconnect event for a connect event.
+func disconnectEvent(connect ConnectionEvent, end time.Time) ConnectionEvent {
+	event := connect
+	event.Action = codersdk.AuditActionDisconnect
+	event.Time = end
+	event.DurationMS = end.Sub(connect.Time).Milliseconds()
+	return event
+}
+
+// trackAppSession reports a connect event for the first request of a user to
+// an app, and returns a func that must be called when the request is done.
+// The session ends when no requests were made for AppSessionTimeout.
+func (s *Server) trackAppSession(r *http.Request, token SignedToken) (done func()) {
+	if s.ReportConnection == nil {
+		return func() {}
+	}
+
+	connType := codersdk.ConnectionTypeWorkspaceApp
+	if _, err := strconv.ParseUint(token.AppSlugOrPort, 10, 16); err == nil {
+		connType = codersdk.ConnectionTypePortForward
+	}
+	event := newConnectionEvent(r, token, connType)
+	key := appSessionKey{
+		userID:        token.UserID,
+		agentID:       token.AgentID,
+		appSlugOrPort: token.AppSlugOrPort,
+		ip:            event.IP,
+	}
+
+	s.appSessionsMu.Lock()
+	if s.appSessionsClosed {
+		s.appSessionsMu.Unlock()
+		return func() {}
+	}
+	if s.appSessions == nil {
+		s.appSessions = map[appSessionKey]*appSession{}
+	}
+	session, ok := s.appSessions[key]
+	if !ok {
+		session = &appSession{connect: event}
+		s.appSessions[key] = session
+	}
+	if session.timer != nil {
+		session.timer.Stop()
+		session.timer = nil
+		session.generation++
+	}
+	session.inflight++
+	s.appSessionsMu.Unlock()
+
+	if !ok {
+		s.ReportConnection(r.Context(), event)
+	}
+
+	return func() {
+		s.appSessionsMu.Lock()
+		defer s.appSessionsMu.Unlock()
+		session.inflight--
+		session.lastSeen = time.Now()
+		if session.inflight > 0 || s.appSessionsClosed {
+			return
+		}
+		timeout := s.AppSessionTimeout
+		if timeout <= 0 {
+			timeout = DefaultAppSessionTimeout
+		}
+		session.generation++
+		generation := session.generation
+		session.timer = time.AfterFunc(timeout, func() {
+			s.endAppSession(key, session, generation)
+		})
+	}
+}
+
+func (s *Server) endAppSession(key appSessionKey, session *appSession, generation int) {
+	s.appSessionsMu.Lock()
+	if s.appSessions[key] != session || session.generation != generation || session.inflight > 0 {
+		s.appSessionsMu.Unlock()
+		return
+	}
+	delete(s.appSessions, key)
+	end := session.lastSeen
+	s.appSessionsMu.Unlock()
+
+	s.ReportConnection(context.Background(), disconnectEvent(session.connect, end))
+}
+
+// CloseAppSessions ends all open app sessions and reports their disconnect
+// events. New sessions are not tracked afterwards.
+func (s *Server) CloseAppSessions() {
+	s.appSessionsMu.Lock()
+	s.appSessionsClosed = true
+	sessions := s.appSessions
+	s.appSessions = nil
+	now := time.Now()
+	events := make([]ConnectionEvent, 0, len(sessions))
+	for _, session := range sessions {
+		if session.timer != nil {
+			session.timer.Stop()
+		}
+		end := session.lastSeen
+		if session.inflight > 0 || end.IsZero() {
+			end = now
+		}
+		events = append(events, disconnectEvent(session.connect, end))
+	}
+	s.appSessionsMu.Unlock()
+
+	for _, event := range events {
+		s.ReportConnection(context.Background(), event)
+	}
+}
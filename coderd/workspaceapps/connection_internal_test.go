@@ -0,0 +1,129 @@
+package workspaceapps
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+type connectionRecorder struct {
+	mu     sync.Mutex
+	events []ConnectionEvent
+}
+
+func (r *connectionRecorder) report(_ context.Context, event ConnectionEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *connectionRecorder) get() []ConnectionEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ConnectionEvent{}, r.events...)
+}
+
+func TestTrackAppSession(t *testing.T) {
+	t.Parallel()
+
+	token := SignedToken{
+		Request: Request{
+			AccessMethod:  AccessMethodSubdomain,
+			AppSlugOrPort: "code-server",
+		},
+		UserID:      uuid.New(),
+		WorkspaceID: uuid.New(),
+		AgentID:     uuid.New(),
+	}
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		recorder := &connectionRecorder{}
+		s := &Server{
+			ReportConnection:  recorder.report,
+			AppSessionTimeout: 100 * time.Millisecond,
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "1.2.3.4:5678"
+		// Many requests belong to the same session.
+		for i := 0; i < 3; i++ {
+			done := s.trackAppSession(r, token)
+			done()
+		}
+		events := recorder.get()
+		require.Len(t, events, 1)
+		assert.Equal(t, codersdk.AuditActionConnect, events[0].Action)
+		assert.Equal(t, codersdk.ConnectionTypeWorkspaceApp, events[0].Type)
+		assert.Equal(t, "1.2.3.4", events[0].IP)
+		assert.Equal(t, token.UserID, events[0].UserID)
+
+		require.Eventually(t, func() bool {
+			return len(recorder.get()) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		events = recorder.get()
+		assert.Equal(t, codersdk.AuditActionDisconnect, events[1].Action)
+		assert.Equal(t, events[0].ID, events[1].ID)
+
+		// A new session is started after the timeout.
+		done := s.trackAppSession(r, token)
+		done()
+		events = recorder.get()
+		require.Len(t, events, 3)
+		assert.NotEqual(t, events[0].ID, events[2].ID)
+		s.CloseAppSessions()
+	})
+
+	t.Run("InFlight", func(t *testing.T) {
+		t.Parallel()
+		recorder := &connectionRecorder{}
+		s := &Server{
+			ReportConnection:  recorder.report,
+			AppSessionTimeout: time.Millisecond,
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		done := s.trackAppSession(r, token)
+		// The session doesn't end while a request is in flight, like a
+		// WebSocket.
+		time.Sleep(10 * time.Millisecond)
+		require.Len(t, recorder.get(), 1)
+		done()
+		require.Eventually(t, func() bool {
+			return len(recorder.get()) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+		recorder := &connectionRecorder{}
+		s := &Server{
+			ReportConnection: recorder.report,
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		portToken := token
+		portToken.AppSlugOrPort = "8080"
+		done := s.trackAppSession(r, portToken)
+		done()
+		s.CloseAppSessions()
+		events := recorder.get()
+		require.Len(t, events, 2)
+		assert.Equal(t, codersdk.ConnectionTypePortForward, events[0].Type)
+		assert.Equal(t, codersdk.AuditActionDisconnect, events[1].Action)
+
+		// Sessions aren't tracked after closing.
+		done = s.trackAppSession(r, portToken)
+		done()
+		require.Len(t, recorder.get(), 2)
+	})
+}
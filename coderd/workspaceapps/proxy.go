@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -111,13 +112,25 @@ type Server struct {
 
 	AgentProvider AgentProvider
 
+	// ReportConnection is called when a user connects to or disconnects from
+	// an app, a port or the web terminal. It is optional.
+	ReportConnection func(ctx context.Context, event ConnectionEvent)
+	// AppSessionTimeout defaults to DefaultAppSessionTimeout.
+	AppSessionTimeout time.Duration
+
 	websocketWaitMutex sync.Mutex
 	websocketWaitGroup sync.WaitGroup
+
+	appSessionsMu     sync.Mutex
+	appSessions       map[appSessionKey]*appSession
+	appSessionsClosed bool
 }
 
-// Close waits for all reconnecting-pty WebSocket connections to drain before
-// returning.
+// Close ends open app sessions and waits for all reconnecting-pty WebSocket
+// connections to drain before returning.
 func (s *Server) Close() error {
+	s.CloseAppSessions()
+
 	s.websocketWaitMutex.Lock()
 	s.websocketWaitGroup.Wait()
 	s.websocketWaitMutex.Unlock()
@@ -586,6 +599,9 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 	// end span so we don't get long lived trace data
 	tracing.EndHTTPSpan(r, http.StatusOK, trace.SpanFromContext(ctx))
 
+	done := s.trackAppSession(r, appToken)
+	defer done()
+
 	proxy.ServeHTTP(rw, r)
 }
 
@@ -679,8 +695,18 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	}
 	defer ptNetConn.Close()
 	log.Debug(ctx, "obtained PTY")
+
+	var connect ConnectionEvent
+	if s.ReportConnection != nil {
+		connect = newConnectionEvent(r, *appToken, codersdk.ConnectionTypeReconnectingPTY)
+		s.ReportConnection(ctx, connect)
+	}
 	agentssh.Bicopy(ctx, wsNetConn, ptNetConn)
 	log.Debug(ctx, "pty Bicopy finished")
+	if s.ReportConnection != nil {
+		// The request context is canceled by now.
+		s.ReportConnection(context.Background(), disconnectEvent(connect, time.Now()))
+	}
 }
 
 // wsNetConn wraps net.Conn created by websocket.NetConn(). Cancel func
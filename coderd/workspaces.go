@@ -17,8 +17,10 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/prebuilds"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/searchquery"
@@ -176,6 +178,15 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspacesResponse{
+		Workspaces: applyWorkspacesPostFilter(wss, postFilter),
+		Count:      int(workspaceRows[0].Count),
+	})
+}
+
+// applyWorkspacesPostFilter applies the filters of a workspace search query
+// that cannot be expressed in SQL.
+func applyWorkspacesPostFilter(wss []codersdk.Workspace, postFilter searchquery.PostFilter) []codersdk.Workspace {
 	var filteredWorkspaces []codersdk.Workspace
 	// apply post filters, if they exist
 	if postFilter.DeletingBy == nil {
@@ -193,11 +204,7 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 			filteredWorkspaces = append(filteredWorkspaces, v)
 		}
 	}
-
-	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspacesResponse{
-		Workspaces: filteredWorkspaces,
-		Count:      int(workspaceRows[0].Count),
-	})
+	return filteredWorkspaces
 }
 
 // @Summary Get workspace metadata by user and workspace name
@@ -421,28 +428,53 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 	var (
 		provisionerJob *database.ProvisionerJob
 		workspaceBuild *database.WorkspaceBuild
+		claimed        bool
 	)
 	err = api.Database.InTx(func(db database.Store) error {
-		now := database.Now()
-		// Workspaces are created without any versions.
-		workspace, err = db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
-			ID:                uuid.New(),
-			CreatedAt:         now,
-			UpdatedAt:         now,
-			OwnerID:           user.ID,
-			OrganizationID:    template.OrganizationID,
-			TemplateID:        template.ID,
-			Name:              createWorkspace.Name,
-			AutostartSchedule: dbAutostartSchedule,
-			Ttl:               dbTTL,
-			// The workspaces page will sort by last used at, and it's useful to
-			// have the newly created workspace at the top of the list!
-			LastUsedAt: database.Now(),
-		})
-		if err != nil {
-			return xerrors.Errorf("insert workspace: %w", err)
+		claimed = false
+		if template.PrebuiltWorkspaces > 0 {
+			// The prebuilt workspaces are owned by the prebuilds user, which
+			// the requester can't read.
+			//nolint:gocritic // Authorization happens when starting the build.
+			workspace, err = prebuilds.Claim(dbauthz.AsPrebuilds(ctx), db, template, prebuilds.ClaimParams{
+				OwnerID:             user.ID,
+				Name:                createWorkspace.Name,
+				AutostartSchedule:   dbAutostartSchedule,
+				Ttl:                 dbTTL,
+				RichParameterValues: createWorkspace.RichParameterValues,
+			})
+			switch {
+			case err == nil:
+				claimed = true
+			case !errors.Is(err, prebuilds.ErrNoneAvailable):
+				return xerrors.Errorf("claim prebuilt workspace: %w", err)
+			}
 		}
 
+		if !claimed {
+			now := database.Now()
+			// Workspaces are created without any versions.
+			workspace, err = db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
+				ID:                uuid.New(),
+				CreatedAt:         now,
+				UpdatedAt:         now,
+				OwnerID:           user.ID,
+				OrganizationID:    template.OrganizationID,
+				TemplateID:        template.ID,
+				Name:              createWorkspace.Name,
+				AutostartSchedule: dbAutostartSchedule,
+				Ttl:               dbTTL,
+				// The workspaces page will sort by last used at, and it's useful to
+				// have the newly created workspace at the top of the list!
+				LastUsedAt: database.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert workspace: %w", err)
+			}
+		}
+
+		// A claimed workspace is already provisioned, so starting it again
+		// only hands the resources over to the new owner.
 		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
 			Reason(database.BuildReasonInitiator).
 			Initiator(apiKey.UserID).
@@ -470,6 +502,13 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 	aReq.New = workspace
+	if template.PrebuiltWorkspaces > 0 {
+		if claimed {
+			api.PrebuildsMetrics.Claimed(template.Name)
+		} else {
+			api.PrebuildsMetrics.Missed(template.Name)
+		}
+	}
 
 	initiator, err := api.Database.GetUserByID(ctx, workspaceBuild.InitiatorID)
 	if err != nil {
@@ -498,6 +537,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		[]database.WorkspaceResourceMetadatum{},
 		[]database.WorkspaceAgent{},
 		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentScript{},
 		database.TemplateVersion{},
 	)
 	if err != nil {
@@ -564,6 +604,10 @@ func (api *API) patchWorkspace(rw http.ResponseWriter, r *http.Request) {
 		Name: name,
 	})
 	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
 		// The query protects against updating deleted workspaces and
 		// the existence of the workspace is checked in the request,
 		// if we get ErrNoRows it means the workspace was deleted.
@@ -660,6 +704,10 @@ func (api *API) putWorkspaceAutostart(rw http.ResponseWriter, r *http.Request) {
 		AutostartSchedule: dbSched,
 	})
 	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error updating workspace autostart schedule.",
 			Detail:  err.Error(),
@@ -746,6 +794,10 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, resp)
 			return
 		}
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
 
 		resp.Detail = err.Error()
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, resp)
@@ -801,6 +853,10 @@ func (api *API) putWorkspaceLock(rw http.ResponseWriter, r *http.Request) {
 		LockedAt: lockedAt,
 	})
 	if err != nil {
+		if dbauthz.IsNotAuthorizedError(err) {
+			httpapi.Forbidden(rw)
+			return
+		}
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error updating workspace locked status.",
 			Detail:  err.Error(),
@@ -1050,6 +1106,7 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.scripts,
 		data.templateVersions,
 	)
 	if err != nil {
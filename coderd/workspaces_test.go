@@ -2759,3 +2759,334 @@ func TestWorkspaceLock(t *testing.T) {
 		coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStop, database.WorkspaceTransitionStart)
 	})
 }
+
+func TestWorkspacesBulk(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.BulkWorkspaceAction(ctx, codersdk.BulkWorkspaceActionRequest{
+			Action: codersdk.BulkWorkspaceActionStop,
+			Query:  "template:" + template.Name,
+			DryRun: true,
+		})
+		require.NoError(t, err)
+		require.True(t, res.DryRun)
+		require.Len(t, res.Results, 1)
+		require.Equal(t, workspace.ID, res.Results[0].WorkspaceID)
+		require.Nil(t, res.Results[0].BuildID)
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, otherVersion.ID)
+		other := coderdtest.CreateTemplate(t, client, user.OrganizationID, otherVersion.ID)
+		first := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		second := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		untouched := coderdtest.CreateWorkspace(t, client, user.OrganizationID, other.ID)
+		for _, workspace := range []codersdk.Workspace{first, second, untouched} {
+			coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		}
+		auditor.ResetLogs()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.BulkWorkspaceAction(ctx, codersdk.BulkWorkspaceActionRequest{
+			Action: codersdk.BulkWorkspaceActionStop,
+			Query:  "template:" + template.Name + " status:running",
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 2)
+		stopped := map[uuid.UUID]bool{}
+		for _, result := range res.Results {
+			require.Empty(t, result.Error)
+			require.NotNil(t, result.BuildID)
+			build := coderdtest.AwaitWorkspaceBuildJob(t, client, *result.BuildID)
+			require.Equal(t, codersdk.WorkspaceTransitionStop, build.Transition)
+			stopped[result.WorkspaceID] = true
+		}
+		require.True(t, stopped[first.ID])
+		require.True(t, stopped[second.ID])
+
+		audited := map[uuid.UUID]bool{}
+		for _, log := range auditor.AuditLogs() {
+			if log.ResourceType == database.ResourceTypeWorkspaceBuild && log.Action == database.AuditActionStop && log.StatusCode == http.StatusCreated {
+				audited[log.ResourceID] = true
+			}
+		}
+		require.Len(t, audited, 2)
+		for _, result := range res.Results {
+			require.True(t, audited[*result.BuildID])
+		}
+	})
+
+	t.Run("UpdateOutdated", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		outdated := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, outdated.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: newVersion.ID,
+		})
+		require.NoError(t, err)
+		current := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, current.LatestBuild.ID)
+		auditor.ResetLogs()
+
+		res, err := client.BulkWorkspaceAction(ctx, codersdk.BulkWorkspaceActionRequest{
+			Action: codersdk.BulkWorkspaceActionUpdate,
+			Query:  "template:" + template.Name,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 1)
+		require.Equal(t, outdated.ID, res.Results[0].WorkspaceID)
+		require.NotNil(t, res.Results[0].BuildID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, *res.Results[0].BuildID)
+		require.Equal(t, newVersion.ID, build.TemplateVersionID)
+
+		// The diff is tested in enterprise, where it's computed.
+		var found bool
+		for _, log := range auditor.AuditLogs() {
+			if log.ResourceID == build.ID && log.StatusCode == http.StatusCreated {
+				found = true
+			}
+		}
+		require.True(t, found)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+		client, closer := coderdtest.NewWithProvisionerCloser(t, nil)
+		defer closer.Close()
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		closer.Close()
+		// The build of the workspace never completes, so another build
+		// cannot be created.
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.BulkWorkspaceAction(ctx, codersdk.BulkWorkspaceActionRequest{
+			Action: codersdk.BulkWorkspaceActionStop,
+			Query:  "template:" + template.Name,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 1)
+		require.Equal(t, workspace.ID, res.Results[0].WorkspaceID)
+		require.Nil(t, res.Results[0].BuildID)
+		require.NotEmpty(t, res.Results[0].Error)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.BulkWorkspaceAction(ctx, codersdk.BulkWorkspaceActionRequest{
+			Action: codersdk.BulkWorkspaceActionStart,
+			Query:  "unknown:value",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestWorkspaceTransfer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		db, pubsub := dbtestutil.NewDB(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database:                 db,
+			Pubsub:                   pubsub,
+			IncludeProvisionerDaemon: true,
+			Auditor:                  auditor,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		authToken := uuid.New()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken.String()),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		auditor.ResetLogs()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		// nolint:gocritic // Reading agents directly from the database.
+		agent, err := db.GetWorkspaceAgentByAuthToken(dbauthz.AsSystemRestricted(ctx), authToken)
+		require.NoError(t, err)
+
+		build, err := client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			Owner: member.Username,
+		})
+		require.NoError(t, err)
+		require.Equal(t, workspace.LatestBuild.BuildNumber+1, build.BuildNumber)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, build.Transition)
+		require.Equal(t, member.ID, build.WorkspaceOwnerID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, member.ID, workspace.OwnerID)
+
+		// The agent of the previous build must no longer accept its token.
+		// nolint:gocritic // Reading agents directly from the database.
+		agent, err = db.GetWorkspaceAgentByID(dbauthz.AsSystemRestricted(ctx), agent.ID)
+		require.NoError(t, err)
+		require.NotEqual(t, authToken, agent.AuthToken)
+
+		owners := map[string]bool{}
+		for _, log := range auditor.AuditLogs() {
+			if log.ResourceType != database.ResourceTypeWorkspace || log.Action != database.AuditActionWrite {
+				continue
+			}
+			require.EqualValues(t, http.StatusOK, log.StatusCode)
+			var fields audit.AdditionalFields
+			require.NoError(t, json.Unmarshal(log.AdditionalFields, &fields))
+			owners[fields.WorkspaceOwner] = true
+		}
+		require.Len(t, owners, 2)
+		require.True(t, owners[coderdtest.FirstUserParams.Username])
+		require.True(t, owners[member.Username])
+	})
+
+	t.Run("SameOwner", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			Owner: user.UserID.String(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			Owner: "nobody",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "owner", apiErr.Validations[0].Field)
+	})
+
+	t.Run("NameConflict", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		existing := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.Name = workspace.Name
+		})
+		coderdtest.AwaitWorkspaceBuildJob(t, client, existing.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			Owner: member.Username,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, user.UserID, workspace.OwnerID)
+	})
+
+	t.Run("NoTemplateAccess", func(t *testing.T) {
+		t.Parallel()
+		db, pubsub := dbtestutil.NewDB(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database:                 db,
+			Pubsub:                   pubsub,
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		// Revoke the access of the everyone group to the template.
+		// nolint:gocritic // Updating the template ACL directly in the database.
+		err := db.UpdateTemplateACLByID(dbauthz.AsSystemRestricted(ctx), database.UpdateTemplateACLByIDParams{
+			ID:       template.ID,
+			GroupACL: database.TemplateACL{},
+			UserACL:  database.TemplateACL{},
+		})
+		require.NoError(t, err)
+
+		_, err = client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			Owner: member.Username,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,241 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/searchquery"
+	"github.com/coder/coder/coderd/wsbuilder"
+	"github.com/coder/coder/codersdk"
+)
+
+// bulkWorkspaceActionConcurrency limits the number of workspace builds that
+// are created at the same time to avoid overloading the database.
+const bulkWorkspaceActionConcurrency = 10
+
+// @Summary Start, stop, update or delete workspaces in bulk
+// @ID start-stop-update-or-delete-workspaces-in-bulk
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param request body codersdk.BulkWorkspaceActionRequest true "Bulk workspace action request"
+// @Success 200 {object} codersdk.BulkWorkspaceActionResponse
+// @Router /workspaces/bulk [post]
+func (api *API) postWorkspacesBulk(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	var req codersdk.BulkWorkspaceActionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	filter, postFilter, errs := searchquery.Workspaces(req.Query, codersdk.Pagination{}, api.AgentInactiveDisconnectTimeout)
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid workspace search query.",
+			Validations: errs,
+		})
+		return
+	}
+	if filter.OwnerUsername == "me" {
+		filter.OwnerID = apiKey.UserID
+		filter.OwnerUsername = ""
+	}
+
+	prepared, err := api.HTTPAuth.AuthorizeSQLFilter(r, rbac.ActionRead, rbac.ResourceWorkspace.Type)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error preparing sql filter.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	workspaceRows, err := api.Database.GetAuthorizedWorkspaces(ctx, filter, prepared)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	workspaces := database.ConvertWorkspaceRows(workspaceRows)
+	data, err := api.workspaceData(ctx, workspaces)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	wss, err := convertWorkspaces(workspaces, data)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	workspacesByID := make(map[uuid.UUID]database.Workspace, len(workspaces))
+	for _, workspace := range workspaces {
+		workspacesByID[workspace.ID] = workspace
+	}
+	activeVersionIDs := make(map[uuid.UUID]uuid.UUID, len(data.templates))
+	for _, template := range data.templates {
+		activeVersionIDs[template.ID] = template.ActiveVersionID
+	}
+	var targets []codersdk.Workspace
+	for _, ws := range applyWorkspacesPostFilter(wss, postFilter) {
+		// Only outdated workspaces can be updated.
+		if req.Action == codersdk.BulkWorkspaceActionUpdate && !ws.Outdated {
+			continue
+		}
+		targets = append(targets, ws)
+	}
+
+	results := make([]codersdk.BulkWorkspaceActionResult, len(targets))
+	for i, ws := range targets {
+		results[i] = codersdk.BulkWorkspaceActionResult{
+			WorkspaceID:   ws.ID,
+			WorkspaceName: ws.Name,
+			OwnerName:     ws.OwnerName,
+		}
+	}
+	if req.DryRun {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.BulkWorkspaceActionResponse{
+			Action:  req.Action,
+			DryRun:  true,
+			Results: results,
+		})
+		return
+	}
+
+	// We only use errgroup here for convenience of API, not for early
+	// cancellation. Failures are reported per workspace.
+	var eg errgroup.Group
+	eg.SetLimit(bulkWorkspaceActionConcurrency)
+	for i, ws := range targets {
+		i := i
+		workspace := workspacesByID[ws.ID]
+		activeVersionID := activeVersionIDs[workspace.TemplateID]
+		eg.Go(func() error {
+			buildID, err := api.bulkWorkspaceAction(ctx, r, workspace, req.Action, activeVersionID)
+			if err != nil {
+				results[i].Error = err.Error()
+				return nil
+			}
+			results[i].BuildID = &buildID
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.BulkWorkspaceActionResponse{
+		Action:  req.Action,
+		Results: results,
+	})
+}
+
+// bulkWorkspaceAction creates the build for a single workspace of a bulk
+// action and records it in the audit log.
+func (api *API) bulkWorkspaceAction(ctx context.Context, r *http.Request, workspace database.Workspace, action codersdk.BulkWorkspaceAction, activeVersionID uuid.UUID) (uuid.UUID, error) {
+	apiKey := httpmw.APIKey(r)
+
+	var (
+		transition  database.WorkspaceTransition
+		auditAction database.AuditAction
+	)
+	switch action {
+	case codersdk.BulkWorkspaceActionStart, codersdk.BulkWorkspaceActionUpdate:
+		transition = database.WorkspaceTransitionStart
+		auditAction = database.AuditActionStart
+	case codersdk.BulkWorkspaceActionStop:
+		transition = database.WorkspaceTransitionStop
+		auditAction = database.AuditActionStop
+	case codersdk.BulkWorkspaceActionDelete:
+		transition = database.WorkspaceTransitionDelete
+		auditAction = database.AuditActionDelete
+	default:
+		return uuid.Nil, xerrors.Errorf("unknown action %q", action)
+	}
+
+	// The build is audited like builds created by the provisioner, with
+	// the previous build as the old state.
+	previousBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get latest build: %w", err)
+	}
+
+	builder := wsbuilder.New(workspace, transition).
+		Initiator(apiKey.UserID).
+		DeploymentValues(api.Options.DeploymentValues)
+	if action == codersdk.BulkWorkspaceActionUpdate {
+		builder = builder.VersionID(activeVersionID)
+	}
+
+	status := http.StatusCreated
+	workspaceBuild, _, err := builder.Build(
+		ctx,
+		api.Database,
+		func(action rbac.Action, object rbac.Objecter) bool {
+			return api.Authorize(r, action, object)
+		},
+	)
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) {
+		var authErr dbauthz.NotAuthorizedError
+		if xerrors.As(err, &authErr) {
+			buildErr.Status = http.StatusUnauthorized
+		}
+		if buildErr.Status == http.StatusInternalServerError {
+			api.Logger.Error(ctx, "workspace build error", slog.Error(buildErr.Wrapped))
+		}
+		status = buildErr.Status
+		err = xerrors.New(buildErr.Message)
+	} else if err != nil {
+		status = http.StatusInternalServerError
+	}
+
+	additionalFields := audit.AdditionalFields{
+		WorkspaceName: workspace.Name,
+		BuildReason:   database.BuildReasonInitiator,
+	}
+	var newBuild database.WorkspaceBuild
+	if workspaceBuild != nil {
+		newBuild = *workspaceBuild
+		additionalFields.BuildNumber = strconv.FormatInt(int64(workspaceBuild.BuildNumber), 10)
+	}
+	additionalFieldsRaw, marshalErr := json.Marshal(additionalFields)
+	if marshalErr != nil {
+		api.Logger.Error(ctx, "marshal workspace audit fields", slog.Error(marshalErr))
+	}
+	audit.ExportRequest(ctx, &audit.RequestParams{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           auditAction,
+		AdditionalFields: additionalFieldsRaw,
+	}, status, previousBuild, newBuild)
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+	return workspaceBuild.ID, nil
+}
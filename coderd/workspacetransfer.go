@@ -0,0 +1,385 @@
+package coderd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/provisionerdserver"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/wsbuilder"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Transfer workspace ownership
+// @ID transfer-workspace-ownership
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.TransferWorkspaceRequest true "Transfer workspace request"
+// @Success 200 {object} codersdk.WorkspaceBuild
+// @Router /workspaces/{workspace}/owner [put]
+func (api *API) putWorkspaceOwner(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+		auditor   = api.Auditor.Load()
+		// The transfer is audited twice, once for the previous owner and
+		// once for the new owner, so that it shows up in the audit trail
+		// of both.
+		oldOwnerAuditParams = &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		}
+		newOwnerAuditParams = &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		}
+		oldOwnerAReq, commitOldOwnerAudit = audit.InitRequest[database.Workspace](rw, oldOwnerAuditParams)
+		newOwnerAReq, commitNewOwnerAudit = audit.InitRequest[database.Workspace](rw, newOwnerAuditParams)
+	)
+	defer commitOldOwnerAudit()
+	defer commitNewOwnerAudit()
+	oldOwnerAReq.Old = workspace
+	newOwnerAReq.Old = workspace
+
+	var req codersdk.TransferWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	newOwner, ok := api.transferWorkspaceNewOwner(rw, r, workspace, req.Owner)
+	if !ok {
+		return
+	}
+	oldOwner, err := api.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace owner.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	oldOwnerAuditParams.AdditionalFields = transferWorkspaceAuditFields(workspace, oldOwner)
+	newOwnerAuditParams.AdditionalFields = transferWorkspaceAuditFields(workspace, newOwner)
+
+	latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Rebuilding a workspace that is being deleted would delete it, which
+	// is surely not what the caller intended.
+	if latestBuild.Transition == database.WorkspaceTransitionDelete {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q is being deleted and cannot be transferred.", workspace.Name),
+		})
+		return
+	}
+	if !api.transferWorkspaceWithinQuota(rw, r, newOwner, latestBuild) {
+		return
+	}
+
+	var (
+		newWorkspace database.Workspace
+		build        *database.WorkspaceBuild
+		job          *database.ProvisionerJob
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		var err error
+		now := database.Now()
+		newWorkspace, err = tx.UpdateWorkspaceOwnerByID(ctx, database.UpdateWorkspaceOwnerByIDParams{
+			ID:        workspace.ID,
+			OwnerID:   newOwner.ID,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace owner: %w", err)
+		}
+		// Agent tokens may have been handed to the previous owner, so new
+		// ones are issued for every agent of the workspace.
+		err = tx.UpdateWorkspaceAgentAuthTokensByWorkspaceID(ctx, database.UpdateWorkspaceAgentAuthTokensByWorkspaceIDParams{
+			WorkspaceID: workspace.ID,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return xerrors.Errorf("rotate workspace agent tokens: %w", err)
+		}
+		// The session token issued to the previous owner for this workspace
+		// must not outlive the transfer. The provisioner issues a new one
+		// for the new owner during the build below.
+		//nolint:gocritic // The token belongs to the previous owner.
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		sessionToken, err := tx.GetAPIKeyByName(sysCtx, database.GetAPIKeyByNameParams{
+			UserID:    oldOwner.ID,
+			TokenName: provisionerdserver.WorkspaceSessionTokenName(workspace),
+		})
+		if err == nil {
+			err = tx.DeleteAPIKeyByID(sysCtx, sessionToken.ID)
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("delete workspace session token: %w", err)
+		}
+
+		// Rebuild the workspace so that the provisioner picks up the new
+		// owner.
+		builder := wsbuilder.New(newWorkspace, latestBuild.Transition).
+			Initiator(apiKey.UserID).
+			DeploymentValues(api.Options.DeploymentValues)
+		build, job, err = builder.Build(ctx, tx, func(action rbac.Action, object rbac.Objecter) bool {
+			return api.Authorize(r, action, object)
+		})
+		return err
+	}, nil)
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) {
+		var authErr dbauthz.NotAuthorizedError
+		if xerrors.As(err, &authErr) {
+			buildErr.Status = http.StatusUnauthorized
+		}
+		if buildErr.Status == http.StatusInternalServerError {
+			api.Logger.Error(ctx, "workspace build error", slog.Error(buildErr.Wrapped))
+		}
+		httpapi.Write(ctx, rw, buildErr.Status, codersdk.Response{
+			Message: buildErr.Message,
+			Detail:  buildErr.Error(),
+		})
+		return
+	}
+	if err != nil {
+		// The query protects against updating deleted workspaces, so
+		// ErrNoRows means the workspace was deleted.
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusMethodNotAllowed, codersdk.Response{
+				Message: fmt.Sprintf("Workspace %q is deleted and cannot be transferred.", workspace.Name),
+			})
+			return
+		}
+		if database.IsUniqueViolation(err) {
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("User %q already has a workspace named %q.", newOwner.Username, workspace.Name),
+				Validations: []codersdk.ValidationError{{
+					Field:  "owner",
+					Detail: "The new owner must not have a workspace with the same name.",
+				}},
+			})
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error transferring workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	oldOwnerAReq.New = newWorkspace
+	newOwnerAReq.New = newWorkspace
+
+	users, err := api.Database.GetUsersByIDs(ctx, []uuid.UUID{
+		newWorkspace.OwnerID,
+		build.InitiatorID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error getting user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		*build,
+		newWorkspace,
+		database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: *job,
+			QueuePosition:  0,
+		},
+		users,
+		[]database.WorkspaceResource{},
+		[]database.WorkspaceResourceMetadatum{},
+		[]database.WorkspaceAgent{},
+		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentScript{},
+		database.TemplateVersion{},
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusOK, apiBuild)
+}
+
+// transferWorkspaceNewOwner resolves the ID or username of the new owner of
+// a workspace and ensures they are allowed to own it.
+func (api *API) transferWorkspaceNewOwner(rw http.ResponseWriter, r *http.Request, workspace database.Workspace, owner string) (database.User, bool) {
+	ctx := r.Context()
+
+	var (
+		user database.User
+		err  error
+	)
+	if id, parseErr := uuid.Parse(owner); parseErr == nil {
+		user, err = api.Database.GetUserByID(ctx, id)
+	} else {
+		user, err = api.Database.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+			Username: owner,
+		})
+	}
+	if httpapi.Is404Error(err) || (err == nil && user.Deleted) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q does not exist.", owner),
+			Validations: []codersdk.ValidationError{{
+				Field:  "owner",
+				Detail: "User does not exist.",
+			}},
+		})
+		return database.User{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return database.User{}, false
+	}
+	if user.ID == workspace.OwnerID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q is already owned by %q.", workspace.Name, user.Username),
+		})
+		return database.User{}, false
+	}
+	if user.Status != database.UserStatusActive {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q is %s and cannot own workspaces.", user.Username, user.Status),
+		})
+		return database.User{}, false
+	}
+
+	_, err = api.Database.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+		OrganizationID: workspace.OrganizationID,
+		UserID:         user.ID,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q is not a member of the organization of the workspace.", user.Username),
+		})
+		return database.User{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization member.",
+			Detail:  err.Error(),
+		})
+		return database.User{}, false
+	}
+
+	// The new owner must be able to use the template, as if they were
+	// creating the workspace themselves.
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return database.User{}, false
+	}
+	//nolint:gocritic // Reading the roles of another user is a system function.
+	roles, err := api.Database.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user roles.",
+			Detail:  err.Error(),
+		})
+		return database.User{}, false
+	}
+	subject, err := api.userSubject(ctx, roles)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error expanding user roles.",
+			Detail:  err.Error(),
+		})
+		return database.User{}, false
+	}
+	err = api.Authorizer.Authorize(ctx, subject.WithCachedASTValue(), rbac.ActionRead, template.RBACObject())
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("User %q does not have access to the template %q.", user.Username, template.Name),
+		})
+		return database.User{}, false
+	}
+
+	return user, true
+}
+
+// transferWorkspaceWithinQuota ensures the cost of the workspace fits the
+// quota of the new owner. Quotas are only enforced when a quota committer is
+// configured.
+func (api *API) transferWorkspaceWithinQuota(rw http.ResponseWriter, r *http.Request, newOwner database.User, latestBuild database.WorkspaceBuild) bool {
+	ctx := r.Context()
+	if api.QuotaCommitter.Load() == nil || latestBuild.DailyCost == 0 {
+		return true
+	}
+
+	consumed, err := api.Database.GetQuotaConsumedForUser(ctx, newOwner.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota consumption.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	allowance, err := api.Database.GetQuotaAllowanceForUser(ctx, newOwner.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota allowance.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if consumed+int64(latestBuild.DailyCost) > allowance {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Transferring the workspace would exceed the quota of %q.", newOwner.Username),
+			Detail:  fmt.Sprintf("The workspace costs %d, %d of %d is already consumed.", latestBuild.DailyCost, consumed, allowance),
+		})
+		return false
+	}
+	return true
+}
+
+func transferWorkspaceAuditFields(workspace database.Workspace, owner database.User) json.RawMessage {
+	fields, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceName:  workspace.Name,
+		WorkspaceOwner: owner.Username,
+	})
+	if err != nil {
+		return nil
+	}
+	return fields
+}
@@ -632,17 +632,24 @@ func (b *Builder) authorize(authFunc func(action rbac.Action, object rbac.Object
 	// Doing this up front saves a lot of work if the user doesn't have permission.
 	// This is checked again in the dbauthz layer, but the check is cached
 	// and will be a noop later.
-	var action rbac.Action
+	var (
+		action rbac.Action
+		object rbac.Objecter
+	)
 	switch b.trans {
 	case database.WorkspaceTransitionDelete:
 		action = rbac.ActionDelete
+		object = b.workspace
 	case database.WorkspaceTransitionStart, database.WorkspaceTransitionStop:
-		action = rbac.ActionUpdate
+		// Starting and stopping creates a build, it does not change the
+		// workspace itself.
+		action = rbac.ActionCreate
+		object = b.workspace.WorkspaceBuildRBAC(b.trans)
 	default:
 		msg := fmt.Sprintf("Transition %q not supported.", b.trans)
 		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
 	}
-	if !authFunc(action, b.workspace) {
+	if !authFunc(action, object) {
 		// We use the same wording as the httpapi to avoid leaking the existence of the workspace
 		return BuildError{http.StatusNotFound, httpapi.ResourceNotFoundResponse.Message, xerrors.New(httpapi.ResourceNotFoundResponse.Message)}
 	}
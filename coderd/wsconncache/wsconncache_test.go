@@ -263,3 +263,15 @@ func (*client) PatchStartupLogs(_ context.Context, _ agentsdk.PatchStartupLogs)
 func (*client) GetServiceBanner(_ context.Context) (codersdk.ServiceBannerConfig, error) {
 	return codersdk.ServiceBannerConfig{}, nil
 }
+
+func (*client) PostSessionRecording(_ context.Context, _ agentsdk.PostSessionRecordingRequest) error {
+	return nil
+}
+
+func (*client) PostConnection(_ context.Context, _ agentsdk.PostConnectionRequest) error {
+	return nil
+}
+
+func (*client) PostScriptStatus(_ context.Context, _ agentsdk.PostScriptStatusRequest) error {
+	return nil
+}
@@ -96,8 +96,12 @@ type Manifest struct {
 	MOTDFile                 string                                       `json:"motd_file"`
 	ShutdownScript           string                                       `json:"shutdown_script"`
 	ShutdownScriptTimeout    time.Duration                                `json:"shutdown_script_timeout"`
+	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
+	// RecordSessions instructs the agent to record interactive terminal
+	// sessions and upload them with PostSessionRecording.
+	RecordSessions bool `json:"record_sessions"`
 }
 
 // Manifest fetches manifest for the currently authenticated workspace agent.
@@ -549,6 +553,29 @@ func (c *Client) PostLifecycle(ctx context.Context, req PostLifecycleRequest) er
 	return nil
 }
 
+// PostScriptStatusRequest reports the progress of a script to coderd.
+// StartedAt and EndedAt are left zero until the script starts and ends.
+type PostScriptStatusRequest struct {
+	ScriptID  uuid.UUID                           `json:"script_id" format:"uuid"`
+	Status    codersdk.WorkspaceAgentScriptStatus `json:"status"`
+	ExitCode  *int32                              `json:"exit_code,omitempty"`
+	StartedAt time.Time                           `json:"started_at"`
+	EndedAt   time.Time                           `json:"ended_at"`
+}
+
+func (c *Client) PostScriptStatus(ctx context.Context, req PostScriptStatusRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/report-script-status", req)
+	if err != nil {
+		return xerrors.Errorf("agent script status post request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+
+	return nil
+}
+
 type PostStartupRequest struct {
 	Version           string                  `json:"version"`
 	ExpandedDirectory string                  `json:"expanded_directory"`
@@ -567,10 +594,67 @@ func (c *Client) PostStartup(ctx context.Context, req PostStartupRequest) error
 	return nil
 }
 
+type PostSessionRecordingRequest struct {
+	ID        uuid.UUID                                   `json:"id" format:"uuid"`
+	Type      codersdk.WorkspaceAgentSessionRecordingType `json:"type"`
+	StartedAt time.Time                                   `json:"started_at" format:"date-time"`
+	EndedAt   time.Time                                   `json:"ended_at" format:"date-time"`
+	Truncated bool                                        `json:"truncated"`
+	// Data is the recording in the asciicast v2 format.
+	Data []byte `json:"data"`
+}
+
+// PostSessionRecording uploads a recording of an interactive terminal
+// session.
+func (c *Client) PostSessionRecording(ctx context.Context, req PostSessionRecordingRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/session-recordings", req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// PostConnectionRequest reports that a connection to the agent opened or
+// closed.
+type PostConnectionRequest struct {
+	// ID is the same for the connect and disconnect events of a connection.
+	ID     uuid.UUID               `json:"id" format:"uuid"`
+	Action codersdk.AuditAction    `json:"action" enums:"connect,disconnect"`
+	Type   codersdk.ConnectionType `json:"type" enums:"ssh,port_forward"`
+	Time   time.Time               `json:"time" format:"date-time"`
+	// IP is the address the connection came from. This is usually an
+	// address in the tailnet.
+	IP string `json:"ip"`
+	// Port is the forwarded port of port_forward connections.
+	Port uint16 `json:"port,omitempty"`
+	// DurationMS is how long the connection was open, set when it closes.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+}
+
+// PostConnection reports a connection to the agent, so it can be audited.
+func (c *Client) PostConnection(ctx context.Context, req PostConnectionRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/report-connection", req)
+	if err != nil {
+		return xerrors.Errorf("agent connection post request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type StartupLog struct {
 	CreatedAt time.Time         `json:"created_at"`
 	Output    string            `json:"output"`
 	Level     codersdk.LogLevel `json:"level"`
+	// Source is the name of the script that produced the log, or empty
+	// for the startup script.
+	Source string `json:"source,omitempty"`
 }
 
 type PatchStartupLogs struct {
@@ -19,9 +19,12 @@ type APIKey struct {
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
 	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,read_only,workspace_start_stop,template_push,user_read"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
+	// AllowList are the resources the key is restricted to, in the form
+	// <type>:<id>. The key is not restricted if the list is empty.
+	AllowList []string `json:"allow_list"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -31,12 +34,19 @@ const (
 	LoginTypePassword LoginType = "password"
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
+	LoginTypeLDAP     LoginType = "ldap"
 	LoginTypeToken    LoginType = "token"
 	// LoginTypeNone is used if no login method is available for this user.
 	// If this is set, the user has no method of logging in.
 	// API keys can still be created by an owner and used by the user.
 	// These keys would use the `LoginTypeToken` type.
 	LoginTypeNone LoginType = "none"
+	// LoginTypeOAuth2ProviderApp is used for API keys issued to OAuth2 apps
+	// on behalf of a user.
+	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
+	// LoginTypeOAuth2 is used by generic OAuth2 login providers, such as
+	// GitLab or Gitea.
+	LoginTypeOAuth2 LoginType = "oauth2"
 )
 
 type APIKeyScope string
@@ -47,12 +57,34 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeReadOnly is a scope that allows the user to read
+	// everything they can read, but not change anything.
+	APIKeyScopeReadOnly APIKeyScope = "read_only"
+	// APIKeyScopeWorkspaceStartStop is a scope that allows the user to
+	// start and stop their workspaces.
+	APIKeyScopeWorkspaceStartStop APIKeyScope = "workspace_start_stop"
+	// APIKeyScopeTemplatePush is a scope that allows the user to create
+	// templates and push new template versions.
+	APIKeyScopeTemplatePush APIKeyScope = "template_push"
+	// APIKeyScopeUserRead is a scope that allows the user to read users.
+	APIKeyScopeUserRead APIKeyScope = "user_read"
 )
 
+// APIKeyAllowListEntry returns the allow list entry that restricts a token to
+// the workspace or template with the given ID.
+func APIKeyAllowListEntry(resource RBACResource, id uuid.UUID) string {
+	return fmt.Sprintf("%s:%s", resource, id)
+}
+
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
-	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
+	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect,read_only,workspace_start_stop,template_push,user_read"`
 	TokenName string        `json:"token_name"`
+	// AllowList restricts the token to workspaces and templates. Entries are
+	// in the form <type>:<id>, where type is "workspace" or "template".
+	// Restricting a token to a workspace also allows it to use the template
+	// of the workspace.
+	AllowList []string `json:"allow_list,omitempty"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
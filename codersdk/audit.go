@@ -3,6 +3,7 @@ package codersdk
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/netip"
 	"strings"
@@ -14,16 +15,22 @@ import (
 type ResourceType string
 
 const (
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeGitSSHKey       ResourceType = "git_ssh_key"
-	ResourceTypeAPIKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
+	ResourceTypeTemplate                ResourceType = "template"
+	ResourceTypeTemplateVersion         ResourceType = "template_version"
+	ResourceTypeUser                    ResourceType = "user"
+	ResourceTypeWorkspace               ResourceType = "workspace"
+	ResourceTypeWorkspaceBuild          ResourceType = "workspace_build"
+	ResourceTypeGitSSHKey               ResourceType = "git_ssh_key"
+	ResourceTypeAPIKey                  ResourceType = "api_key"
+	ResourceTypeGroup                   ResourceType = "group"
+	ResourceTypeLicense                 ResourceType = "license"
+	ResourceTypeConvertLogin            ResourceType = "convert_login"
+	ResourceTypeWebhook                 ResourceType = "webhook"
+	ResourceTypeOAuth2ProviderApp       ResourceType = "oauth2_provider_app"
+	ResourceTypeOAuth2ProviderAppSecret ResourceType = "oauth2_provider_app_secret"
+	ResourceTypeCustomRole              ResourceType = "custom_role"
+	ResourceTypeWorkspaceAgent          ResourceType = "workspace_agent"
+	ResourceTypeWorkspaceApp            ResourceType = "workspace_app"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -50,6 +57,18 @@ func (r ResourceType) FriendlyString() string {
 		return "license"
 	case ResourceTypeConvertLogin:
 		return "login type conversion"
+	case ResourceTypeWebhook:
+		return "webhook"
+	case ResourceTypeOAuth2ProviderApp:
+		return "oauth2 app"
+	case ResourceTypeOAuth2ProviderAppSecret:
+		return "oauth2 app secret"
+	case ResourceTypeCustomRole:
+		return "custom role"
+	case ResourceTypeWorkspaceAgent:
+		return "workspace agent"
+	case ResourceTypeWorkspaceApp:
+		return "workspace app"
 	default:
 		return "unknown"
 	}
@@ -58,14 +77,16 @@ func (r ResourceType) FriendlyString() string {
 type AuditAction string
 
 const (
-	AuditActionCreate   AuditAction = "create"
-	AuditActionWrite    AuditAction = "write"
-	AuditActionDelete   AuditAction = "delete"
-	AuditActionStart    AuditAction = "start"
-	AuditActionStop     AuditAction = "stop"
-	AuditActionLogin    AuditAction = "login"
-	AuditActionLogout   AuditAction = "logout"
-	AuditActionRegister AuditAction = "register"
+	AuditActionCreate     AuditAction = "create"
+	AuditActionWrite      AuditAction = "write"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionStart      AuditAction = "start"
+	AuditActionStop       AuditAction = "stop"
+	AuditActionLogin      AuditAction = "login"
+	AuditActionLogout     AuditAction = "logout"
+	AuditActionRegister   AuditAction = "register"
+	AuditActionConnect    AuditAction = "connect"
+	AuditActionDisconnect AuditAction = "disconnect"
 )
 
 func (a AuditAction) Friendly() string {
@@ -86,11 +107,26 @@ func (a AuditAction) Friendly() string {
 		return "logged out"
 	case AuditActionRegister:
 		return "registered"
+	case AuditActionConnect:
+		return "connected to"
+	case AuditActionDisconnect:
+		return "disconnected from"
 	default:
 		return "unknown"
 	}
 }
 
+// ConnectionType is the kind of connection recorded by connect and disconnect
+// audit logs.
+type ConnectionType string
+
+const (
+	ConnectionTypeSSH             ConnectionType = "ssh"
+	ConnectionTypeReconnectingPTY ConnectionType = "reconnecting_pty"
+	ConnectionTypePortForward     ConnectionType = "port_forward"
+	ConnectionTypeWorkspaceApp    ConnectionType = "workspace_app"
+)
+
 type AuditDiff map[string]AuditDiffField
 
 type AuditDiffField struct {
@@ -141,6 +177,77 @@ type CreateTestAuditLogRequest struct {
 	BuildReason      BuildReason     `json:"build_reason,omitempty" enums:"autostart,autostop,initiator"`
 }
 
+// AuditLogVerificationIssueType is the kind of tampering found when verifying
+// the audit log hash chain.
+type AuditLogVerificationIssueType string
+
+const (
+	// AuditLogVerificationIssueModified means the content of an audit log
+	// doesn't match its hash.
+	AuditLogVerificationIssueModified AuditLogVerificationIssueType = "modified"
+	// AuditLogVerificationIssueMissing means an audit log was deleted, but
+	// not by the retention policy.
+	AuditLogVerificationIssueMissing AuditLogVerificationIssueType = "missing"
+	// AuditLogVerificationIssueBrokenLink means a hash doesn't link to the
+	// hash before it.
+	AuditLogVerificationIssueBrokenLink AuditLogVerificationIssueType = "broken_link"
+	// AuditLogVerificationIssueGap means entries were removed from the
+	// chain.
+	AuditLogVerificationIssueGap AuditLogVerificationIssueType = "gap"
+	// AuditLogVerificationIssueUnchained means an audit log was added
+	// without being appended to the chain.
+	AuditLogVerificationIssueUnchained AuditLogVerificationIssueType = "unchained"
+	// AuditLogVerificationIssueCheckpoint means a signed checkpoint doesn't
+	// match the chain or its signature is invalid.
+	AuditLogVerificationIssueCheckpoint AuditLogVerificationIssueType = "checkpoint"
+	// AuditLogVerificationIssueTruncated means the chain ends before the
+	// latest checkpoint.
+	AuditLogVerificationIssueTruncated AuditLogVerificationIssueType = "truncated"
+	// AuditLogVerificationIssuePruned means an audit log is marked as deleted
+	// by the retention policy, but still exists, was within its retention
+	// period or was deleted before older audit logs.
+	AuditLogVerificationIssuePruned AuditLogVerificationIssueType = "pruned"
+	// AuditLogVerificationIssueUnsigned means audit logs were not covered by
+	// a signed checkpoint in time, although a signing key is configured.
+	AuditLogVerificationIssueUnsigned AuditLogVerificationIssueType = "unsigned"
+)
+
+type AuditLogVerificationIssue struct {
+	Type AuditLogVerificationIssueType `json:"type" enums:"modified,missing,broken_link,gap,unchained,checkpoint,truncated,pruned,unsigned"`
+	// Sequence is the position in the chain, or 0 for unchained audit logs.
+	Sequence   int64     `json:"sequence"`
+	AuditLogID uuid.UUID `json:"audit_log_id" format:"uuid"`
+	Detail     string    `json:"detail"`
+}
+
+// AuditLogVerification is the result of verifying the audit log hash chain.
+type AuditLogVerification struct {
+	// Valid is true if no issues were found.
+	Valid bool `json:"valid"`
+	// Verified is the number of audit logs that match their hash.
+	Verified int64 `json:"verified"`
+	// Pruned is the number of audit logs deleted by the retention policy.
+	// Their hashes are still part of the chain.
+	Pruned int64 `json:"pruned"`
+	// Legacy is the number of audit logs created before hash chaining was
+	// introduced, so they cannot be verified.
+	Legacy int64 `json:"legacy"`
+	// LastSequence is the position of the last audit log in the chain.
+	LastSequence int64 `json:"last_sequence"`
+	// Checkpoints is the number of signed checkpoints that match the chain.
+	Checkpoints int64 `json:"checkpoints"`
+	// LastCheckpointAt is when the chain was last signed.
+	LastCheckpointAt *time.Time `json:"last_checkpoint_at,omitempty" format:"date-time"`
+	// PublicKey is the hex encoded public key checkpoint signatures were
+	// verified with. Signatures are not verified if it's empty, because no
+	// signing key is configured.
+	PublicKey string `json:"public_key"`
+	// Issues lists the tampering found, up to a limit.
+	Issues []AuditLogVerificationIssue `json:"issues"`
+	// IssuesTruncated is true if more issues were found than listed.
+	IssuesTruncated bool `json:"issues_truncated"`
+}
+
 // AuditLogs retrieves audit logs from the given page.
 func (c *Client) AuditLogs(ctx context.Context, req AuditLogsRequest) (AuditLogResponse, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit", nil, req.Pagination.asRequestOption(), func(r *http.Request) {
@@ -170,6 +277,44 @@ func (c *Client) AuditLogs(ctx context.Context, req AuditLogsRequest) (AuditLogR
 	return logRes, nil
 }
 
+// ExportAuditLogs returns the audit logs created from the given time and
+// before the to time, as gzip compressed JSON lines. This is the format of the
+// archives written by the audit log retention policy. The caller must close
+// the returned reader.
+func (c *Client) ExportAuditLogs(ctx context.Context, from, to time.Time) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/export", nil, func(r *http.Request) {
+		q := r.URL.Query()
+		q.Set("from", from.Format(time.RFC3339Nano))
+		q.Set("to", to.Format(time.RFC3339Nano))
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// VerifyAuditLogs walks the audit log hash chain and reports audit logs that
+// were modified, deleted or added without being chained.
+func (c *Client) VerifyAuditLogs(ctx context.Context) (AuditLogVerification, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/verify", nil)
+	if err != nil {
+		return AuditLogVerification{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return AuditLogVerification{}, ReadBodyAsError(res)
+	}
+
+	var verification AuditLogVerification
+	return verification, json.NewDecoder(res.Body).Decode(&verification)
+}
+
 // CreateTestAuditLog creates a fake audit log. Only owners of the organization
 // can perform this action. It's used for testing purposes.
 func (c *Client) CreateTestAuditLog(ctx context.Context, req CreateTestAuditLogRequest) error {
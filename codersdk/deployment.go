@@ -139,6 +139,11 @@ type DeploymentValues struct {
 	PostgresURL                     clibase.String                  `json:"pg_connection_url,omitempty" typescript:",notnull"`
 	OAuth2                          OAuth2Config                    `json:"oauth2,omitempty" typescript:",notnull"`
 	OIDC                            OIDCConfig                      `json:"oidc,omitempty" typescript:",notnull"`
+	LDAP                            LDAPConfig                      `json:"ldap,omitempty" typescript:",notnull"`
+	PasswordPolicy                  PasswordPolicyConfig            `json:"password_policy,omitempty" typescript:",notnull"`
+	AuditLogExport                  AuditLogExportConfig            `json:"audit_log_export,omitempty" typescript:",notnull"`
+	AuditLogRetention               AuditLogRetentionConfig         `json:"audit_log_retention,omitempty" typescript:",notnull"`
+	AuditLogSigningKey              clibase.String                  `json:"audit_log_signing_key,omitempty" typescript:",notnull"`
 	Telemetry                       TelemetryConfig                 `json:"telemetry,omitempty" typescript:",notnull"`
 	TLS                             TLSConfig                       `json:"tls,omitempty" typescript:",notnull"`
 	Trace                           TraceConfig                     `json:"trace,omitempty" typescript:",notnull"`
@@ -163,6 +168,7 @@ type DeploymentValues struct {
 	SessionDuration                 clibase.Duration                `json:"max_session_expiry,omitempty" typescript:",notnull"`
 	DisableSessionExpiryRefresh     clibase.Bool                    `json:"disable_session_expiry_refresh,omitempty" typescript:",notnull"`
 	DisablePasswordAuth             clibase.Bool                    `json:"disable_password_auth,omitempty" typescript:",notnull"`
+	RequireAdminMFA                 clibase.Bool                    `json:"require_admin_mfa,omitempty" typescript:",notnull"`
 	Support                         SupportConfig                   `json:"support,omitempty" typescript:",notnull"`
 	GitAuthProviders                clibase.Struct[[]GitAuthConfig] `json:"git_auth,omitempty" typescript:",notnull"`
 	SSHConfig                       SSHConfig                       `json:"config_ssh,omitempty" typescript:",notnull"`
@@ -171,6 +177,8 @@ type DeploymentValues struct {
 	ProxyHealthStatusInterval       clibase.Duration                `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
 	EnableTerraformDebugMode        clibase.Bool                    `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
 	UserQuietHoursSchedule          UserQuietHoursScheduleConfig    `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
+	RecordSessions                  clibase.Bool                    `json:"record_sessions,omitempty" typescript:",notnull"`
+	Notifications                   NotificationsConfig             `json:"notifications,omitempty" typescript:",notnull"`
 
 	Config      clibase.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig clibase.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -246,7 +254,8 @@ type PprofConfig struct {
 }
 
 type OAuth2Config struct {
-	Github OAuth2GithubConfig `json:"github" typescript:",notnull"`
+	Github         OAuth2GithubConfig                          `json:"github" typescript:",notnull"`
+	LoginProviders clibase.Struct[[]OAuth2LoginProviderConfig] `json:"login_providers" typescript:",notnull"`
 }
 
 type OAuth2GithubConfig struct {
@@ -259,6 +268,36 @@ type OAuth2GithubConfig struct {
 	EnterpriseBaseURL clibase.String      `json:"enterprise_base_url" typescript:",notnull"`
 }
 
+// OAuth2LoginProviderConfig configures a generic OAuth2 login provider, such
+// as GitLab, Bitbucket or Gitea. The fields are looked up in the response of
+// the user info URL with paths like "user.emails[0]" or "groups[*].name".
+type OAuth2LoginProviderConfig struct {
+	ID            string   `json:"id"`
+	DisplayName   string   `json:"display_name"`
+	IconURL       string   `json:"icon_url"`
+	ClientID      string   `json:"client_id"`
+	ClientSecret  string   `json:"-" yaml:"client_secret"`
+	AuthURL       string   `json:"auth_url"`
+	TokenURL      string   `json:"token_url"`
+	UserInfoURL   string   `json:"user_info_url"`
+	Scopes        []string `json:"scopes"`
+	IDField       string   `json:"id_field"`
+	UsernameField string   `json:"username_field"`
+	EmailField    string   `json:"email_field"`
+	// EmailVerifiedField is a path into the user info to a boolean that is
+	// false if the email is not verified.
+	EmailVerifiedField  string `json:"email_verified_field"`
+	IgnoreEmailVerified bool   `json:"ignore_email_verified"`
+	// GroupsField enables group sync if set.
+	GroupsField  string            `json:"groups_field"`
+	GroupMapping map[string]string `json:"group_mapping"`
+	// AllowedGroups restricts login to members of any of the groups.
+	AllowedGroups []string `json:"allowed_groups"`
+	AllowSignups  bool     `json:"allow_signups"`
+	// EmailDomain restricts login to emails of the domains.
+	EmailDomain []string `json:"email_domain"`
+}
+
 type OIDCConfig struct {
 	AllowSignups        clibase.Bool                        `json:"allow_signups" typescript:",notnull"`
 	ClientID            clibase.String                      `json:"client_id" typescript:",notnull"`
@@ -280,6 +319,51 @@ type OIDCConfig struct {
 	IconURL             clibase.URL                         `json:"icon_url" typescript:",notnull"`
 }
 
+type LDAPConfig struct {
+	URL               clibase.URL                       `json:"url" typescript:",notnull"`
+	StartTLS          clibase.Bool                      `json:"start_tls" typescript:",notnull"`
+	TLSCAFile         clibase.String                    `json:"tls_ca_file" typescript:",notnull"`
+	BindDN            clibase.String                    `json:"bind_dn" typescript:",notnull"`
+	BindPassword      clibase.String                    `json:"bind_password" typescript:",notnull"`
+	SearchBaseDN      clibase.String                    `json:"search_base_dn" typescript:",notnull"`
+	SearchFilter      clibase.String                    `json:"search_filter" typescript:",notnull"`
+	UsernameAttribute clibase.String                    `json:"username_attribute" typescript:",notnull"`
+	EmailAttribute    clibase.String                    `json:"email_attribute" typescript:",notnull"`
+	GroupsAttribute   clibase.String                    `json:"groups_attribute" typescript:",notnull"`
+	GroupMapping      clibase.Struct[map[string]string] `json:"group_mapping" typescript:",notnull"`
+	AllowSignups      clibase.Bool                      `json:"allow_signups" typescript:",notnull"`
+	SignInText        clibase.String                    `json:"sign_in_text" typescript:",notnull"`
+}
+
+type PasswordPolicyConfig struct {
+	MinLength        clibase.Int64    `json:"min_length" typescript:",notnull"`
+	CharacterClasses clibase.Int64    `json:"character_classes" typescript:",notnull"`
+	History          clibase.Int64    `json:"history" typescript:",notnull"`
+	LockoutThreshold clibase.Int64    `json:"lockout_threshold" typescript:",notnull"`
+	LockoutWindow    clibase.Duration `json:"lockout_window" typescript:",notnull"`
+}
+
+type AuditLogExportConfig struct {
+	SyslogAddress        clibase.String      `json:"syslog_address" typescript:",notnull"`
+	SyslogTLSCAFile      clibase.String      `json:"syslog_tls_ca_file" typescript:",notnull"`
+	WebhookURL           clibase.URL         `json:"webhook_url" typescript:",notnull"`
+	WebhookHeaders       clibase.StringArray `json:"webhook_headers" typescript:",notnull"`
+	WebhookBatchSize     clibase.Int64       `json:"webhook_batch_size" typescript:",notnull"`
+	WebhookFlushInterval clibase.Duration    `json:"webhook_flush_interval" typescript:",notnull"`
+	WebhookSpoolDir      clibase.String      `json:"webhook_spool_dir" typescript:",notnull"`
+	WebhookSpoolMaxMB    clibase.Int64       `json:"webhook_spool_max_mb" typescript:",notnull"`
+	FilePath             clibase.String      `json:"file_path" typescript:",notnull"`
+	FileMaxSizeMB        clibase.Int64       `json:"file_max_size_mb" typescript:",notnull"`
+	FileMaxAge           clibase.Duration    `json:"file_max_age" typescript:",notnull"`
+	FileMaxBackups       clibase.Int64       `json:"file_max_backups" typescript:",notnull"`
+}
+
+type AuditLogRetentionConfig struct {
+	Period        clibase.Duration                  `json:"period" typescript:",notnull"`
+	ResourceTypes clibase.Struct[map[string]string] `json:"resource_types" typescript:",notnull"`
+	ArchiveDir    clibase.String                    `json:"archive_dir" typescript:",notnull"`
+}
+
 type TelemetryConfig struct {
 	Enable clibase.Bool `json:"enable" typescript:",notnull"`
 	Trace  clibase.Bool `json:"trace" typescript:",notnull"`
@@ -358,6 +442,18 @@ type UserQuietHoursScheduleConfig struct {
 	// WindowDuration  clibase.Duration `json:"window_duration" typescript:",notnull"`
 }
 
+type NotificationsConfig struct {
+	Email NotificationsEmailConfig `json:"email" typescript:",notnull"`
+}
+
+type NotificationsEmailConfig struct {
+	From      clibase.String   `json:"from" typescript:",notnull"`
+	Smarthost clibase.HostPort `json:"smarthost" typescript:",notnull"`
+	Username  clibase.String   `json:"username" typescript:",notnull"`
+	Password  clibase.String   `json:"password" typescript:",notnull"`
+	ForceTLS  clibase.Bool     `json:"force_tls" typescript:",notnull"`
+}
+
 const (
 	annotationEnterpriseKey = "enterprise"
 	annotationSecretKey     = "secret"
@@ -469,6 +565,26 @@ func (c *DeploymentValues) Options() clibase.OptionSet {
 			Name: "OIDC",
 			YAML: "oidc",
 		}
+		deploymentGroupLDAP = clibase.Group{
+			Name:        "LDAP",
+			Description: "Configure login and user-provisioning with an LDAP or Active Directory server.",
+			YAML:        "ldap",
+		}
+		deploymentGroupPasswordPolicy = clibase.Group{
+			Name:        "Password Policy",
+			Description: "Configure the requirements for user passwords and how accounts are locked after failed logins.",
+			YAML:        "passwordPolicy",
+		}
+		deploymentGroupAuditLogExport = clibase.Group{
+			Name:        "Audit Log Export",
+			Description: "Export audit logs to external systems, in addition to storing them in the database. Each destination is enabled by setting its address, URL or path.",
+			YAML:        "auditLogExport",
+		}
+		deploymentGroupAuditLogRetention = clibase.Group{
+			Name:        "Audit Log Retention",
+			Description: "Configure how long audit logs are kept, and archive them before they are deleted.",
+			YAML:        "auditLogRetention",
+		}
 		deploymentGroupTelemetry = clibase.Group{
 			Name: "Telemetry",
 			YAML: "telemetry",
@@ -486,6 +602,17 @@ when required by your organization's security policy.`,
 			Description: "Allow users to set quiet hours schedules each day for workspaces to avoid workspaces stopping during the day due to template max TTL.",
 			YAML:        "userQuietHoursSchedule",
 		}
+		deploymentGroupNotifications = clibase.Group{
+			Name:        "Notifications",
+			Description: "Configure how users are notified about their workspaces and tokens.",
+			YAML:        "notifications",
+		}
+		deploymentGroupNotificationsEmail = clibase.Group{
+			Parent:      &deploymentGroupNotifications,
+			Name:        "Email",
+			Description: "Send notifications by email through an SMTP server.",
+			YAML:        "email",
+		}
 		deploymentGroupDangerous = clibase.Group{
 			Name: "⚠️ Dangerous",
 			YAML: "dangerous",
@@ -917,6 +1044,17 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOAuth2GitHub,
 			YAML:        "enterpriseBaseURL",
 		},
+		{
+			// Env handling is done in cli.ReadOAuth2LoginProvidersFromEnv
+			Name:        "OAuth2 Login Providers",
+			Description: "Generic OAuth2 login providers, such as GitLab, Bitbucket or Gitea.",
+			Value:       &c.OAuth2.LoginProviders,
+			Group:       &deploymentGroupOAuth2,
+			YAML:        "loginProviders",
+			// The providers are hidden until they are defined in the YAML or
+			// environment.
+			Hidden: true,
+		},
 		// OIDC settings.
 		{
 			Name:        "OIDC Allow Signups",
@@ -1099,6 +1237,337 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "iconURL",
 		},
+		// LDAP settings.
+		{
+			Name:        "LDAP URL",
+			Description: "URL of the LDAP server, with the ldap or ldaps scheme. Login with LDAP is enabled if set.",
+			Flag:        "ldap-url",
+			Env:         "CODER_LDAP_URL",
+			Value:       &c.LDAP.URL,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "url",
+		},
+		{
+			Name:        "LDAP StartTLS",
+			Description: "Upgrade ldap connections to TLS with StartTLS.",
+			Flag:        "ldap-start-tls",
+			Env:         "CODER_LDAP_START_TLS",
+			Value:       &c.LDAP.StartTLS,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "startTLS",
+		},
+		{
+			Name:        "LDAP TLS CA File",
+			Description: "PEM-encoded certificate authorities used to verify the certificate of the LDAP server. The system pool is used if unset.",
+			Flag:        "ldap-tls-ca-file",
+			Env:         "CODER_LDAP_TLS_CA_FILE",
+			Value:       &c.LDAP.TLSCAFile,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "tlsCAFile",
+		},
+		{
+			Name:        "LDAP Bind DN",
+			Description: "DN of the service account used to search for users.",
+			Flag:        "ldap-bind-dn",
+			Env:         "CODER_LDAP_BIND_DN",
+			Value:       &c.LDAP.BindDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "bindDN",
+		},
+		{
+			Name:        "LDAP Bind Password",
+			Description: "Password of the service account used to search for users.",
+			Flag:        "ldap-bind-password",
+			Env:         "CODER_LDAP_BIND_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.LDAP.BindPassword,
+			Group:       &deploymentGroupLDAP,
+		},
+		{
+			Name:        "LDAP Search Base DN",
+			Description: "DN under which users are searched for.",
+			Flag:        "ldap-search-base-dn",
+			Env:         "CODER_LDAP_SEARCH_BASE_DN",
+			Value:       &c.LDAP.SearchBaseDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "searchBaseDN",
+		},
+		{
+			Name:        "LDAP Search Filter",
+			Description: "Filter that finds the entry of a user. %s is replaced with the escaped username. Use (sAMAccountName=%s) for Active Directory.",
+			Flag:        "ldap-search-filter",
+			Env:         "CODER_LDAP_SEARCH_FILTER",
+			Default:     "(uid=%s)",
+			Value:       &c.LDAP.SearchFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "searchFilter",
+		},
+		{
+			Name:        "LDAP Username Attribute",
+			Description: "LDAP attribute to use as the username.",
+			Flag:        "ldap-username-attribute",
+			Env:         "CODER_LDAP_USERNAME_ATTRIBUTE",
+			Default:     "uid",
+			Value:       &c.LDAP.UsernameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "usernameAttribute",
+		},
+		{
+			Name:        "LDAP Email Attribute",
+			Description: "LDAP attribute to use as the email.",
+			Flag:        "ldap-email-attribute",
+			Env:         "CODER_LDAP_EMAIL_ATTRIBUTE",
+			Default:     "mail",
+			Value:       &c.LDAP.EmailAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "emailAttribute",
+		},
+		{
+			Name:        "LDAP Groups Attribute",
+			Description: "This field must be set if using the group sync feature. Set to the attribute that lists the groups of a user, such as memberOf.",
+			Flag:        "ldap-groups-attribute",
+			Env:         "CODER_LDAP_GROUPS_ATTRIBUTE",
+			// This value is intentionally blank. If this is empty, then LDAP
+			// group sync is disabled.
+			Default: "",
+			Value:   &c.LDAP.GroupsAttribute,
+			Group:   &deploymentGroupLDAP,
+			YAML:    "groupsAttribute",
+		},
+		{
+			Name:        "LDAP Group Mapping",
+			Description: "A map of LDAP groups and the group in Coder it should map to. Groups are matched by DN or by the value of their first RDN, which is also the Coder group name of unmapped groups.",
+			Flag:        "ldap-group-mapping",
+			Env:         "CODER_LDAP_GROUP_MAPPING",
+			Default:     "{}",
+			Value:       &c.LDAP.GroupMapping,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupMapping",
+		},
+		{
+			Name:        "LDAP Allow Signups",
+			Description: "Whether new users can sign up with LDAP.",
+			Flag:        "ldap-allow-signups",
+			Env:         "CODER_LDAP_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.LDAP.AllowSignups,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "LDAP sign in text",
+			Description: "The text to show on the LDAP sign in form.",
+			Flag:        "ldap-sign-in-text",
+			Env:         "CODER_LDAP_SIGN_IN_TEXT",
+			Default:     "LDAP",
+			Value:       &c.LDAP.SignInText,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "signInText",
+		},
+		// Password policy settings.
+		{
+			Name:        "Password Minimum Length",
+			Description: "The minimum number of characters of user passwords.",
+			Flag:        "password-min-length",
+			Env:         "CODER_PASSWORD_MIN_LENGTH",
+			Default:     "8",
+			Value:       &c.PasswordPolicy.MinLength,
+			Group:       &deploymentGroupPasswordPolicy,
+			YAML:        "minLength",
+		},
+		{
+			Name:        "Password Character Classes",
+			Description: "The number of character classes (lowercase letters, uppercase letters, digits and symbols) user passwords must contain.",
+			Flag:        "password-character-classes",
+			Env:         "CODER_PASSWORD_CHARACTER_CLASSES",
+			Default:     "0",
+			Value:       &c.PasswordPolicy.CharacterClasses,
+			Group:       &deploymentGroupPasswordPolicy,
+			YAML:        "characterClasses",
+		},
+		{
+			Name:        "Password History",
+			Description: "The number of previous passwords users cannot reuse when they change their password. Previous passwords are only remembered while this is set.",
+			Flag:        "password-history",
+			Env:         "CODER_PASSWORD_HISTORY",
+			Default:     "0",
+			Value:       &c.PasswordPolicy.History,
+			Group:       &deploymentGroupPasswordPolicy,
+			YAML:        "history",
+		},
+		{
+			Name:        "Password Lockout Threshold",
+			Description: "The number of failed password logins within the lockout window after which a user's account is locked. Locked users must be activated by an admin. Users with the owner role are never locked. Instead, their logins from the same IP address are rejected until the window passes. Set to 0 to disable.",
+			Flag:        "password-lockout-threshold",
+			Env:         "CODER_PASSWORD_LOCKOUT_THRESHOLD",
+			Default:     "0",
+			Value:       &c.PasswordPolicy.LockoutThreshold,
+			Group:       &deploymentGroupPasswordPolicy,
+			YAML:        "lockoutThreshold",
+		},
+		{
+			Name:        "Password Lockout Window",
+			Description: "The period in which failed password logins are counted towards the lockout threshold.",
+			Flag:        "password-lockout-window",
+			Env:         "CODER_PASSWORD_LOCKOUT_WINDOW",
+			Default:     (15 * time.Minute).String(),
+			Value:       &c.PasswordPolicy.LockoutWindow,
+			Group:       &deploymentGroupPasswordPolicy,
+			YAML:        "lockoutWindow",
+		},
+		// Audit log export settings.
+		{
+			Name:        "Audit Syslog Address",
+			Description: "Address of a syslog server to export audit logs to in the RFC 5424 format, as udp://, tcp:// or tls://host:port.",
+			Flag:        "audit-syslog-address",
+			Env:         "CODER_AUDIT_SYSLOG_ADDRESS",
+			Value:       &c.AuditLogExport.SyslogAddress,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "syslogAddress",
+		},
+		{
+			Name:        "Audit Syslog TLS CA File",
+			Description: "PEM-encoded certificate authorities used to verify the certificate of a tls:// syslog server. The system pool is used if unset.",
+			Flag:        "audit-syslog-tls-ca-file",
+			Env:         "CODER_AUDIT_SYSLOG_TLS_CA_FILE",
+			Value:       &c.AuditLogExport.SyslogTLSCAFile,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "syslogTLSCAFile",
+		},
+		{
+			Name:        "Audit Webhook URL",
+			Description: "URL that receives POST requests with batches of audit logs as a JSON array.",
+			Flag:        "audit-webhook-url",
+			Env:         "CODER_AUDIT_WEBHOOK_URL",
+			Value:       &c.AuditLogExport.WebhookURL,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "webhookURL",
+		},
+		{
+			Name:        "Audit Webhook Headers",
+			Description: "Headers added to audit webhook requests, such as for authentication. Each value is in the \"Name: value\" format.",
+			Flag:        "audit-webhook-headers",
+			Env:         "CODER_AUDIT_WEBHOOK_HEADERS",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.AuditLogExport.WebhookHeaders,
+			Group:       &deploymentGroupAuditLogExport,
+		},
+		{
+			Name:        "Audit Webhook Batch Size",
+			Description: "The maximum number of audit logs sent in a webhook request.",
+			Flag:        "audit-webhook-batch-size",
+			Env:         "CODER_AUDIT_WEBHOOK_BATCH_SIZE",
+			Default:     "100",
+			Value:       &c.AuditLogExport.WebhookBatchSize,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "webhookBatchSize",
+		},
+		{
+			Name:        "Audit Webhook Flush Interval",
+			Description: "How often buffered audit logs are sent to the webhook.",
+			Flag:        "audit-webhook-flush-interval",
+			Env:         "CODER_AUDIT_WEBHOOK_FLUSH_INTERVAL",
+			Default:     (5 * time.Second).String(),
+			Value:       &c.AuditLogExport.WebhookFlushInterval,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "webhookFlushInterval",
+		},
+		{
+			Name:        "Audit Webhook Spool Directory",
+			Description: "Directory where audit logs that could not be sent to the webhook are stored until they are retried. Defaults to a directory in the cache directory.",
+			Flag:        "audit-webhook-spool-dir",
+			Env:         "CODER_AUDIT_WEBHOOK_SPOOL_DIR",
+			Value:       &c.AuditLogExport.WebhookSpoolDir,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "webhookSpoolDir",
+		},
+		{
+			Name:        "Audit Webhook Spool Max Size",
+			Description: "The size in megabytes of the spool directory after which the oldest audit logs are dropped.",
+			Flag:        "audit-webhook-spool-max-size",
+			Env:         "CODER_AUDIT_WEBHOOK_SPOOL_MAX_SIZE",
+			Default:     "100",
+			Value:       &c.AuditLogExport.WebhookSpoolMaxMB,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "webhookSpoolMaxSize",
+		},
+		{
+			Name:        "Audit File Path",
+			Description: "File to append audit logs to as JSON lines.",
+			Flag:        "audit-file-path",
+			Env:         "CODER_AUDIT_FILE_PATH",
+			Value:       &c.AuditLogExport.FilePath,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "filePath",
+		},
+		{
+			Name:        "Audit File Max Size",
+			Description: "The size in megabytes after which the audit log file is rotated.",
+			Flag:        "audit-file-max-size",
+			Env:         "CODER_AUDIT_FILE_MAX_SIZE",
+			Default:     "100",
+			Value:       &c.AuditLogExport.FileMaxSizeMB,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "fileMaxSize",
+		},
+		{
+			Name:        "Audit File Max Age",
+			Description: "The duration after which the audit log file is rotated. Set to 0 to only rotate by size.",
+			Flag:        "audit-file-max-age",
+			Env:         "CODER_AUDIT_FILE_MAX_AGE",
+			Default:     (24 * time.Hour).String(),
+			Value:       &c.AuditLogExport.FileMaxAge,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "fileMaxAge",
+		},
+		{
+			Name:        "Audit File Max Backups",
+			Description: "The number of rotated audit log files to keep. Set to 0 to keep all of them.",
+			Flag:        "audit-file-max-backups",
+			Env:         "CODER_AUDIT_FILE_MAX_BACKUPS",
+			Default:     "10",
+			Value:       &c.AuditLogExport.FileMaxBackups,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "fileMaxBackups",
+		},
+		// Audit log retention settings.
+		{
+			Name:        "Audit Log Retention Period",
+			Description: "How long audit logs are kept before they are deleted. Set to 0 to keep them forever.",
+			Flag:        "audit-log-retention",
+			Env:         "CODER_AUDIT_LOG_RETENTION",
+			Default:     "0",
+			Value:       &c.AuditLogRetention.Period,
+			Group:       &deploymentGroupAuditLogRetention,
+			YAML:        "period",
+		},
+		{
+			Name:        "Audit Log Retention Resource Types",
+			Description: "A map of resource types, such as workspace_build, to how long their audit logs are kept. Overrides the retention period for these resource types. Set a period to 0 to keep them forever.",
+			Flag:        "audit-log-retention-resource-types",
+			Env:         "CODER_AUDIT_LOG_RETENTION_RESOURCE_TYPES",
+			Default:     "{}",
+			Value:       &c.AuditLogRetention.ResourceTypes,
+			Group:       &deploymentGroupAuditLogRetention,
+			YAML:        "resourceTypes",
+		},
+		{
+			Name:        "Audit Log Archive Directory",
+			Description: "Directory where audit logs are archived as gzip compressed JSON lines before they are deleted. Audit logs are deleted without being archived if unset.",
+			Flag:        "audit-log-archive-dir",
+			Env:         "CODER_AUDIT_LOG_ARCHIVE_DIR",
+			Value:       &c.AuditLogRetention.ArchiveDir,
+			Group:       &deploymentGroupAuditLogRetention,
+			YAML:        "archiveDir",
+		},
+		{
+			Name:        "Audit Log Signing Key",
+			Description: "Hex encoded Ed25519 seed used to sign checkpoints of the audit log hash chain, so the chain cannot be rewritten by someone with access to the database. Generate one with \"openssl rand -hex 32\". Checkpoints are not created if unset.",
+			Flag:        "audit-log-signing-key",
+			Env:         "CODER_AUDIT_LOG_SIGNING_KEY",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.AuditLogSigningKey,
+		},
 		// Telemetry settings
 		{
 			Name:        "Telemetry Enable",
@@ -1520,6 +1989,15 @@ when required by your organization's security policy.`,
 			YAML:        "disableOwnerWorkspaceAccess",
 			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Record Sessions",
+			Description: "Record interactive terminal sessions (SSH and the web terminal) in all workspaces. Recordings are stored in the asciicast v2 format and can be downloaded by workspace owners and administrators. Recording can also be enabled for individual templates.",
+			Flag:        "record-sessions",
+			Env:         "CODER_RECORD_SESSIONS",
+			Default:     "false",
+			Value:       &c.RecordSessions,
+			YAML:        "recordSessions",
+		},
 		{
 			Name:        "Session Duration",
 			Description: "The token expiry duration for browser sessions. Sessions may last longer if they are actively making requests, but this functionality can be disabled via --disable-session-expiry-refresh.",
@@ -1550,6 +2028,16 @@ when required by your organization's security policy.`,
 			Group: &deploymentGroupNetworkingHTTP,
 			YAML:  "disablePasswordAuth",
 		},
+		{
+			Name:        "Require Admin MFA",
+			Description: "Require users with the owner or user admin role to enroll an authenticator app and enter a code from it when they log in with a password. Users that haven't enrolled are prompted to do so on their next login.",
+			Flag:        "require-admin-mfa",
+			Env:         "CODER_REQUIRE_ADMIN_MFA",
+
+			Value: &c.RequireAdminMFA,
+			Group: &deploymentGroupNetworkingHTTP,
+			YAML:  "requireAdminMFA",
+		},
 		{
 			Name:          "Config Path",
 			Description:   `Specify a YAML file to load configuration from.`,
@@ -1642,6 +2130,53 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupUserQuietHoursSchedule,
 			YAML:        "defaultQuietHoursSchedule",
 		},
+		{
+			Name:        "Notifications: Email From",
+			Description: "The sender address of notification emails. Email notifications are disabled if this is not set.",
+			Flag:        "notifications-email-from",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FROM",
+			Value:       &c.Notifications.Email.From,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "from",
+		},
+		{
+			Name:        "Notifications: Email Smarthost",
+			Description: "The SMTP server (host:port) that notification emails are sent through.",
+			Flag:        "notifications-email-smarthost",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_SMARTHOST",
+			Default:     "localhost:587",
+			Value:       &c.Notifications.Email.Smarthost,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "smarthost",
+		},
+		{
+			Name:        "Notifications: Email Username",
+			Description: "The username to authenticate with the SMTP server. Authentication is skipped if this is not set.",
+			Flag:        "notifications-email-username",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_USERNAME",
+			Value:       &c.Notifications.Email.Username,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "username",
+		},
+		{
+			Name:        "Notifications: Email Password",
+			Description: "The password to authenticate with the SMTP server.",
+			Flag:        "notifications-email-password",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.Notifications.Email.Password,
+			Group:       &deploymentGroupNotificationsEmail,
+		},
+		{
+			Name:        "Notifications: Email Force TLS",
+			Description: "Connect to the SMTP server over TLS, usually on port 465. Otherwise STARTTLS is used if the server supports it.",
+			Flag:        "notifications-email-force-tls",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FORCE_TLS",
+			Default:     "false",
+			Value:       &c.Notifications.Email.ForceTLS,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "forceTLS",
+		},
 	}
 	return opts
 }
@@ -1682,6 +2217,11 @@ func (c *DeploymentValues) WithoutSecrets() (*DeploymentValues, error) {
 			if err != nil {
 				panic(err)
 			}
+		case *clibase.StringArray:
+			err := v.Replace(nil)
+			if err != nil {
+				panic(err)
+			}
 		default:
 			return nil, xerrors.Errorf("unsupported type %T", v)
 		}
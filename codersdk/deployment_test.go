@@ -1,6 +1,7 @@
 package codersdk_test
 
 import (
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -57,6 +58,18 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"SCIM API Key": {
 			yaml: true,
 		},
+		"Notifications: Email Password": {
+			yaml: true,
+		},
+		"LDAP Bind Password": {
+			yaml: true,
+		},
+		"Audit Webhook Headers": {
+			yaml: true,
+		},
+		"Audit Log Signing Key": {
+			yaml: true,
+		},
 		// These complex objects should be configured through YAML.
 		"Support Links": {
 			flag: true,
@@ -68,6 +81,11 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 			flag: true,
 			env:  true,
 		},
+		"OAuth2 Login Providers": {
+			// Provided through the env by cli.ReadOAuth2LoginProvidersFromEnv.
+			flag: true,
+			env:  true,
+		},
 	}
 
 	set := (&codersdk.DeploymentValues{}).Options()
@@ -118,6 +136,23 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 	}
 }
 
+func TestDeploymentValues_WithoutSecrets(t *testing.T) {
+	t.Parallel()
+
+	var values codersdk.DeploymentValues
+	values.AuditLogExport.WebhookURL = clibase.URL(*must(url.Parse("https://example.com/audit")))
+	values.AuditLogExport.WebhookHeaders = clibase.StringArray{"Authorization: Bearer secret"}
+	values.AuditLogSigningKey = "secret"
+
+	stripped, err := values.WithoutSecrets()
+	require.NoError(t, err)
+	require.Empty(t, stripped.AuditLogExport.WebhookHeaders)
+	require.Empty(t, stripped.AuditLogSigningKey.String())
+	require.Equal(t, "https://example.com/audit", stripped.AuditLogExport.WebhookURL.String())
+	// The original values are not modified.
+	require.Equal(t, []string{"Authorization: Bearer secret"}, values.AuditLogExport.WebhookHeaders.Value())
+}
+
 func TestSSHConfig_ParseOptions(t *testing.T) {
 	t.Parallel()
 
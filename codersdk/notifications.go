@@ -0,0 +1,67 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type NotificationKind string
+
+const (
+	NotificationKindWorkspaceAutostop    NotificationKind = "workspace_autostop"
+	NotificationKindWorkspaceBuildFailed NotificationKind = "workspace_build_failed"
+	NotificationKindWorkspaceLocked      NotificationKind = "workspace_locked"
+	NotificationKindTokenExpiring        NotificationKind = "token_expiring"
+)
+
+// NotificationKinds lists all kinds of notifications sent to users.
+var NotificationKinds = []NotificationKind{
+	NotificationKindWorkspaceAutostop,
+	NotificationKindWorkspaceBuildFailed,
+	NotificationKindWorkspaceLocked,
+	NotificationKindTokenExpiring,
+}
+
+// NotificationPreference reports whether a user receives a kind of
+// notification.
+type NotificationPreference struct {
+	Kind    NotificationKind `json:"kind"`
+	Enabled bool             `json:"enabled"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	// Preferences that are omitted are left unchanged.
+	Preferences []NotificationPreference `json:"preferences" validate:"required"`
+}
+
+// NotificationPreferences returns whether the user receives each kind of
+// notification.
+func (c *Client) NotificationPreferences(ctx context.Context, userIdent string) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", userIdent), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []NotificationPreference
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateNotificationPreferences opts the user in or out of kinds of
+// notifications.
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, userIdent string, req UpdateNotificationPreferencesRequest) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", userIdent), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []NotificationPreference
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -0,0 +1,319 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// OAuth2ProviderApp is a third-party app that uses Coder as an OAuth2
+// provider to call the API on behalf of users.
+type OAuth2ProviderApp struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	Name        string    `json:"name"`
+	CallbackURL string    `json:"callback_url"`
+	Icon        string    `json:"icon"`
+	// Endpoints are included in the app response for easier discovery. The
+	// OAuth2 spec does not have a defined place to find these (for comparison,
+	// OIDC has a '/.well-known/openid-configuration' endpoint).
+	Endpoints OAuth2AppEndpoints `json:"endpoints"`
+}
+
+type OAuth2AppEndpoints struct {
+	// Authorization is the page users are sent to in order to authorize the
+	// app.
+	Authorization string `json:"authorization"`
+	// Token is where apps exchange authorization codes and refresh tokens.
+	Token string `json:"token"`
+}
+
+type PostOAuth2ProviderAppRequest struct {
+	Name        string `json:"name" validate:"required,oauth2_app_name"`
+	CallbackURL string `json:"callback_url" validate:"required,http_url"`
+	Icon        string `json:"icon" validate:"omitempty"`
+}
+
+type PutOAuth2ProviderAppRequest struct {
+	Name        string `json:"name" validate:"required,oauth2_app_name"`
+	CallbackURL string `json:"callback_url" validate:"required,http_url"`
+	Icon        string `json:"icon" validate:"omitempty"`
+}
+
+// OAuth2ProviderAppSecret is a client secret of an OAuth2 app. Only the last
+// few characters of the secret are returned.
+type OAuth2ProviderAppSecret struct {
+	ID                    uuid.UUID  `json:"id" format:"uuid"`
+	CreatedAt             time.Time  `json:"created_at" format:"date-time"`
+	LastUsedAt            *time.Time `json:"last_used_at,omitempty" format:"date-time"`
+	ClientSecretTruncated string     `json:"client_secret_truncated"`
+}
+
+// OAuth2ProviderAppSecretFull includes the full secret. It is only returned
+// when the secret is created.
+type OAuth2ProviderAppSecretFull struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	ClientSecretFull string    `json:"client_secret_full"`
+}
+
+// OAuth2AuthorizeRequest is an OAuth2 authorization request as sent by an
+// app. It is passed as query parameters.
+type OAuth2AuthorizeRequest struct {
+	ClientID    uuid.UUID `json:"client_id" format:"uuid"`
+	RedirectURI string    `json:"redirect_uri,omitempty"`
+	// Scope is the scope of the API key the app gets. It defaults to "all".
+	Scope APIKeyScope `json:"scope,omitempty"`
+	State string      `json:"state,omitempty"`
+	// ResponseType must be "code".
+	ResponseType string `json:"response_type"`
+	// CodeChallenge is the PKCE code challenge. Only the "S256" method is
+	// supported.
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+// QueryParams returns the request as the query parameters of an
+// authorization URL.
+func (r OAuth2AuthorizeRequest) QueryParams() url.Values {
+	q := url.Values{}
+	q.Set("client_id", r.ClientID.String())
+	q.Set("response_type", r.ResponseType)
+	q.Set("code_challenge", r.CodeChallenge)
+	q.Set("code_challenge_method", r.CodeChallengeMethod)
+	if r.RedirectURI != "" {
+		q.Set("redirect_uri", r.RedirectURI)
+	}
+	if r.Scope != "" {
+		q.Set("scope", string(r.Scope))
+	}
+	if r.State != "" {
+		q.Set("state", r.State)
+	}
+	return q
+}
+
+// OAuth2Authorization is what a user is shown before they consent to an
+// authorization request.
+type OAuth2Authorization struct {
+	App   OAuth2ProviderApp `json:"app"`
+	Scope APIKeyScope       `json:"scope"`
+	// RedirectURI is where the user is sent after the request, with either
+	// the code or an error.
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// OAuth2AuthorizeResponse is returned when a user consents to an
+// authorization request.
+type OAuth2AuthorizeResponse struct {
+	// RedirectURI includes the authorization code and the state of the
+	// request.
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// OAuth2TokenResponse is returned by the token endpoint, as defined by
+// RFC 6749. The access token is an API key.
+type OAuth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// OAuth2Error is returned by the token endpoint on failure, as defined by
+// RFC 6749.
+type OAuth2Error struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OAuth2AuthorizedApp is an app a user has authorized.
+type OAuth2AuthorizedApp struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	Name        string    `json:"name"`
+	Icon        string    `json:"icon"`
+	CallbackURL string    `json:"callback_url"`
+	// TokenCount is the number of tokens the app holds for the user.
+	TokenCount int64     `json:"token_count"`
+	LastUsedAt time.Time `json:"last_used_at" format:"date-time"`
+}
+
+func (c *Client) OAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/oauth2-provider/apps", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var apps []OAuth2ProviderApp
+	return apps, json.NewDecoder(res.Body).Decode(&apps)
+}
+
+func (c *Client) OAuth2ProviderApp(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), nil)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+func (c *Client) PostOAuth2ProviderApp(ctx context.Context, req PostOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/oauth2-provider/apps", req)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+func (c *Client) PutOAuth2ProviderApp(ctx context.Context, id uuid.UUID, req PutOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), req)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+// DeleteOAuth2ProviderApp deletes an app, its secrets and revokes all the
+// tokens it was issued.
+func (c *Client) DeleteOAuth2ProviderApp(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func (c *Client) OAuth2ProviderAppSecrets(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var secrets []OAuth2ProviderAppSecret
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+func (c *Client) PostOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID) (OAuth2ProviderAppSecretFull, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID), nil)
+	if err != nil {
+		return OAuth2ProviderAppSecretFull{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderAppSecretFull{}, ReadBodyAsError(res)
+	}
+	var secret OAuth2ProviderAppSecretFull
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+// DeleteOAuth2ProviderAppSecret deletes a secret and revokes all the tokens
+// that were issued with it.
+func (c *Client) DeleteOAuth2ProviderAppSecret(ctx context.Context, appID, secretID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets/%s", appID, secretID), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// OAuth2Authorization validates an authorization request and returns what
+// the user consents to.
+func (c *Client) OAuth2Authorization(ctx context.Context, req OAuth2AuthorizeRequest) (OAuth2Authorization, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/oauth2/authorize?"+req.QueryParams().Encode(), nil)
+	if err != nil {
+		return OAuth2Authorization{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return OAuth2Authorization{}, ReadBodyAsError(res)
+	}
+	var authorization OAuth2Authorization
+	return authorization, json.NewDecoder(res.Body).Decode(&authorization)
+}
+
+// AuthorizeOAuth2ProviderApp consents to an authorization request on behalf
+// of the authenticated user.
+func (c *Client) AuthorizeOAuth2ProviderApp(ctx context.Context, req OAuth2AuthorizeRequest) (OAuth2AuthorizeResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/oauth2/authorize?"+req.QueryParams().Encode(), nil)
+	if err != nil {
+		return OAuth2AuthorizeResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return OAuth2AuthorizeResponse{}, ReadBodyAsError(res)
+	}
+	var authorized OAuth2AuthorizeResponse
+	return authorized, json.NewDecoder(res.Body).Decode(&authorized)
+}
+
+// OAuth2AuthorizedApps returns the apps a user has authorized.
+func (c *Client) OAuth2AuthorizedApps(ctx context.Context, user string) ([]OAuth2AuthorizedApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/oauth2-provider/apps", user), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var apps []OAuth2AuthorizedApp
+	return apps, json.NewDecoder(res.Body).Decode(&apps)
+}
+
+// RevokeOAuth2ProviderApp revokes all the codes and tokens an app holds for
+// a user.
+func (c *Client) RevokeOAuth2ProviderApp(ctx context.Context, user string, appID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/oauth2-provider/apps/%s", user, appID), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
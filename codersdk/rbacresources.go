@@ -15,6 +15,7 @@ const (
 	ResourceOrganization                RBACResource = "organization"
 	ResourceRoleAssignment              RBACResource = "assign_role"
 	ResourceOrgRoleAssignment           RBACResource = "assign_org_role"
+	ResourceCustomRole                  RBACResource = "custom_role"
 	ResourceAPIKey                      RBACResource = "api_key"
 	ResourceUser                        RBACResource = "user"
 	ResourceUserData                    RBACResource = "user_data"
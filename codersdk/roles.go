@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -46,3 +47,137 @@ func (c *Client) ListOrganizationRoles(ctx context.Context, org uuid.UUID) ([]As
 	var roles []AssignableRoles
 	return roles, json.NewDecoder(res.Body).Decode(&roles)
 }
+
+// CustomRole is a role defined by admins in addition to the built-in roles.
+// It is assigned by its name like the built-in roles. The name of an
+// organization role is suffixed with the organization ID when assigned.
+type CustomRole struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	CreatedAt   time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt   time.Time `json:"updated_at" format:"date-time"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name"`
+	// OrganizationID is only set for organization roles. The permissions of
+	// organization roles apply to the organization, the permissions of site
+	// wide roles apply everywhere.
+	OrganizationID *uuid.UUID   `json:"organization_id,omitempty" format:"uuid"`
+	Permissions    []Permission `json:"permissions"`
+	// AssignName is the name to assign the role by.
+	AssignName string `json:"assign_name"`
+}
+
+// Permission allows an action on a resource type, or denies it if negated.
+type Permission struct {
+	Negate       bool         `json:"negate"`
+	ResourceType RBACResource `json:"resource_type"`
+	Action       string       `json:"action" enums:"create,read,update,delete,*"`
+}
+
+type CreateCustomRoleRequest struct {
+	Name        string       `json:"name" validate:"required,username"`
+	DisplayName string       `json:"display_name"`
+	Permissions []Permission `json:"permissions" validate:"required"`
+}
+
+type UpdateCustomRoleRequest struct {
+	DisplayName string       `json:"display_name"`
+	Permissions []Permission `json:"permissions" validate:"required"`
+}
+
+// CustomSiteRoles lists the custom site wide roles.
+func (c *Client) CustomSiteRoles(ctx context.Context) ([]CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/roles", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var roles []CustomRole
+	return roles, json.NewDecoder(res.Body).Decode(&roles)
+}
+
+// CustomOrganizationRoles lists the custom roles of an organization.
+func (c *Client) CustomOrganizationRoles(ctx context.Context, org uuid.UUID) ([]CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/roles", org.String()), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var roles []CustomRole
+	return roles, json.NewDecoder(res.Body).Decode(&roles)
+}
+
+// CreateCustomSiteRole creates a custom site wide role.
+func (c *Client) CreateCustomSiteRole(ctx context.Context, req CreateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/roles", req)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// CreateCustomOrganizationRole creates a custom role in an organization.
+func (c *Client) CreateCustomOrganizationRole(ctx context.Context, org uuid.UUID, req CreateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/roles", org.String()), req)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// CustomRole returns a custom site wide or organization role.
+func (c *Client) CustomRole(ctx context.Context, id uuid.UUID) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/roles/%s", id.String()), nil)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// UpdateCustomRole replaces the display name and permissions of a custom
+// role. The new permissions apply to all users the role is assigned to.
+func (c *Client) UpdateCustomRole(ctx context.Context, id uuid.UUID, req UpdateCustomRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/roles/%s", id.String()), req)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// DeleteCustomRole deletes a custom role and unassigns it from all users.
+func (c *Client) DeleteCustomRole(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/roles/%s", id.String()), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
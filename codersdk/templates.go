@@ -50,6 +50,15 @@ type Template struct {
 	FailureTTLMillis    int64 `json:"failure_ttl_ms"`
 	InactivityTTLMillis int64 `json:"inactivity_ttl_ms"`
 	LockedTTLMillis     int64 `json:"locked_ttl_ms"`
+
+	// RecordSessions records interactive terminal sessions in workspaces
+	// created from this template. Sessions are always recorded if the
+	// deployment enables recording for all workspaces.
+	RecordSessions bool `json:"record_sessions"`
+
+	// PrebuiltWorkspaces is the number of workspaces that are kept ready for
+	// users to claim when they create a workspace from this template.
+	PrebuiltWorkspaces int32 `json:"prebuilt_workspaces"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -185,6 +194,8 @@ type UpdateTemplateMeta struct {
 	FailureTTLMillis             int64                       `json:"failure_ttl_ms,omitempty"`
 	InactivityTTLMillis          int64                       `json:"inactivity_ttl_ms,omitempty"`
 	LockedTTLMillis              int64                       `json:"locked_ttl_ms,omitempty"`
+	RecordSessions               bool                        `json:"record_sessions,omitempty"`
+	PrebuiltWorkspaces           int32                       `json:"prebuilt_workspaces,omitempty"`
 }
 
 type TemplateExample struct {
@@ -91,6 +91,74 @@ type PatchTemplateVersionRequest struct {
 	Message *string `json:"message,omitempty" validate:"omitempty,lt=1048577"`
 }
 
+type TemplateVersionDiffChange string
+
+const (
+	TemplateVersionDiffChangeAdded    TemplateVersionDiffChange = "added"
+	TemplateVersionDiffChangeRemoved  TemplateVersionDiffChange = "removed"
+	TemplateVersionDiffChangeModified TemplateVersionDiffChange = "modified"
+)
+
+// TemplateVersionDiff describes what changed between two template versions.
+// Only entries that changed are included.
+type TemplateVersionDiff struct {
+	FromVersionID uuid.UUID                      `json:"from_version_id" format:"uuid"`
+	ToVersionID   uuid.UUID                      `json:"to_version_id" format:"uuid"`
+	Files         []TemplateVersionFileDiff      `json:"files"`
+	Variables     []TemplateVersionVariableDiff  `json:"variables"`
+	Parameters    []TemplateVersionParameterDiff `json:"parameters"`
+	Resources     []TemplateVersionResourceDiff  `json:"resources"`
+}
+
+// TemplateVersionFileDiff describes a file in the template archive that
+// changed.
+type TemplateVersionFileDiff struct {
+	Path   string                    `json:"path"`
+	Change TemplateVersionDiffChange `json:"change" enums:"added,removed,modified"`
+	// Binary is true if either version of the file is not text, in which
+	// case Diff is empty.
+	Binary bool `json:"binary"`
+	// Diff is a unified diff of the file.
+	Diff string `json:"diff"`
+}
+
+// TemplateVersionVariableDiff describes a template variable that changed.
+// Fields lists the JSON names of the fields that were modified.
+type TemplateVersionVariableDiff struct {
+	Name   string                    `json:"name"`
+	Change TemplateVersionDiffChange `json:"change" enums:"added,removed,modified"`
+	Fields []string                  `json:"fields"`
+	From   *TemplateVersionVariable  `json:"from,omitempty"`
+	To     *TemplateVersionVariable  `json:"to,omitempty"`
+}
+
+// TemplateVersionParameterDiff describes a rich parameter that changed.
+// Fields lists the JSON names of the fields that were modified.
+type TemplateVersionParameterDiff struct {
+	Name   string                    `json:"name"`
+	Change TemplateVersionDiffChange `json:"change" enums:"added,removed,modified"`
+	Fields []string                  `json:"fields"`
+	From   *TemplateVersionParameter `json:"from,omitempty"`
+	To     *TemplateVersionParameter `json:"to,omitempty"`
+}
+
+// TemplateVersionResourceDiff describes a resource that changed. Resources
+// are identified by their type and name.
+type TemplateVersionResourceDiff struct {
+	Type   string                     `json:"type"`
+	Name   string                     `json:"name"`
+	Change TemplateVersionDiffChange  `json:"change" enums:"added,removed,modified"`
+	Fields []string                   `json:"fields"`
+	Agents []TemplateVersionAgentDiff `json:"agents"`
+}
+
+// TemplateVersionAgentDiff describes an agent of a resource that changed.
+type TemplateVersionAgentDiff struct {
+	Name   string                    `json:"name"`
+	Change TemplateVersionDiffChange `json:"change" enums:"added,removed,modified"`
+	Fields []string                  `json:"fields"`
+}
+
 // TemplateVersion returns a template version by ID.
 func (c *Client) TemplateVersion(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s", id), nil)
@@ -174,6 +242,20 @@ func (c *Client) TemplateVersionVariables(ctx context.Context, version uuid.UUID
 	return variables, json.NewDecoder(res.Body).Decode(&variables)
 }
 
+// TemplateVersionDiff returns the changes between two template versions.
+func (c *Client) TemplateVersionDiff(ctx context.Context, from, to uuid.UUID) (TemplateVersionDiff, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/diff/%s", from, to), nil)
+	if err != nil {
+		return TemplateVersionDiff{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionDiff{}, ReadBodyAsError(res)
+	}
+	var diff TemplateVersionDiff
+	return diff, json.NewDecoder(res.Body).Decode(&diff)
+}
+
 // TemplateVersionLogsAfter streams logs for a template version that occurred after a specific log ID.
 func (c *Client) TemplateVersionLogsAfter(ctx context.Context, version uuid.UUID, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
 	return c.provisionerJobLogsAfter(ctx, fmt.Sprintf("/api/v2/templateversions/%s/logs", version), after)
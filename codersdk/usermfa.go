@@ -0,0 +1,165 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// TOTPCodeField is the validation field returned when logging in with a
+	// password requires a code from the user's authenticator app.
+	TOTPCodeField = "totp_code"
+	// TOTPEnrollmentField is the validation field returned when logging in
+	// with a password requires the user to enroll an authenticator app first.
+	TOTPEnrollmentField = "totp_enrollment"
+)
+
+// UserMFA is the multi-factor authentication status of a user.
+type UserMFA struct {
+	TOTPEnabled            bool `json:"totp_enabled"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+	// Required is true if the deployment requires the user to use
+	// multi-factor authentication to log in with a password.
+	Required bool `json:"required"`
+}
+
+// TOTPEnrollment is a new authenticator app secret. It must be verified
+// with a code before it is used to log in.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URL is an otpauth:// URL that authenticator apps can import.
+	URL string `json:"url"`
+}
+
+type VerifyTOTPRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TOTPRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// EnrollTOTPWithPasswordRequest enrolls an authenticator app for a user that
+// can't log in until they have one.
+type EnrollTOTPWithPasswordRequest struct {
+	Email    string `json:"email" validate:"required,email" format:"email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// UserMFA returns the multi-factor authentication status of the user.
+func (c *Client) UserMFA(ctx context.Context, user string) (UserMFA, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/mfa", user), nil)
+	if err != nil {
+		return UserMFA{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserMFA{}, ReadBodyAsError(res)
+	}
+	var mfa UserMFA
+	return mfa, json.NewDecoder(res.Body).Decode(&mfa)
+}
+
+// EnrollTOTP generates a new authenticator app secret for the user. It
+// replaces any existing secret, and must be verified with VerifyTOTP.
+func (c *Client) EnrollTOTP(ctx context.Context, user string) (TOTPEnrollment, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/mfa/totp", user), nil)
+	if err != nil {
+		return TOTPEnrollment{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPEnrollment{}, ReadBodyAsError(res)
+	}
+	var enrollment TOTPEnrollment
+	return enrollment, json.NewDecoder(res.Body).Decode(&enrollment)
+}
+
+// VerifyTOTP enables the enrolled authenticator app and returns new recovery
+// codes. They are not shown again.
+func (c *Client) VerifyTOTP(ctx context.Context, user string, req VerifyTOTPRequest) (TOTPRecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/mfa/totp/verify", user), req)
+	if err != nil {
+		return TOTPRecoveryCodes{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TOTPRecoveryCodes{}, ReadBodyAsError(res)
+	}
+	var codes TOTPRecoveryCodes
+	return codes, json.NewDecoder(res.Body).Decode(&codes)
+}
+
+// RegenerateTOTPRecoveryCodes replaces the recovery codes of the user.
+func (c *Client) RegenerateTOTPRecoveryCodes(ctx context.Context, user string) (TOTPRecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/mfa/recovery-codes", user), nil)
+	if err != nil {
+		return TOTPRecoveryCodes{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPRecoveryCodes{}, ReadBodyAsError(res)
+	}
+	var codes TOTPRecoveryCodes
+	return codes, json.NewDecoder(res.Body).Decode(&codes)
+}
+
+// ResetUserMFA removes the authenticator app and recovery codes of the user.
+// Admins use it when a user has lost access to both.
+func (c *Client) ResetUserMFA(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/mfa", user), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// EnrollTOTPWithPassword generates an authenticator app secret for a user
+// that must enroll before they can log in. The secret is verified by passing
+// a code to LoginWithPassword.
+func (c *Client) EnrollTOTPWithPassword(ctx context.Context, req EnrollTOTPWithPasswordRequest) (TOTPEnrollment, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/totp", req)
+	if err != nil {
+		return TOTPEnrollment{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPEnrollment{}, ReadBodyAsError(res)
+	}
+	var enrollment TOTPEnrollment
+	return enrollment, json.NewDecoder(res.Body).Decode(&enrollment)
+}
+
+// IsTOTPCodeRequired returns true if the error is from logging in with a
+// password without the code from an authenticator app.
+func IsTOTPCodeRequired(err error) bool {
+	return hasValidationField(err, TOTPCodeField)
+}
+
+// IsTOTPEnrollmentRequired returns true if the error is from logging in with
+// a password as a user that must enroll an authenticator app first.
+func IsTOTPEnrollmentRequired(err error) bool {
+	return hasValidationField(err, TOTPEnrollmentField)
+}
+
+func hasValidationField(err error, field string) bool {
+	var sdkErr *Error
+	if !errors.As(err, &sdkErr) || sdkErr.StatusCode() != http.StatusUnauthorized {
+		return false
+	}
+	for _, validation := range sdkErr.Validations {
+		if validation.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -20,6 +20,9 @@ type UserStatus string
 const (
 	UserStatusActive    UserStatus = "active"
 	UserStatusSuspended UserStatus = "suspended"
+	// UserStatusLocked is set when a user exceeds the failed login threshold.
+	// Activating the user unlocks their account.
+	UserStatusLocked UserStatus = "locked"
 )
 
 type UsersRequest struct {
@@ -49,11 +52,14 @@ type User struct {
 	CreatedAt  time.Time `json:"created_at" validate:"required" table:"created at" format:"date-time"`
 	LastSeenAt time.Time `json:"last_seen_at" format:"date-time"`
 
-	Status          UserStatus  `json:"status" table:"status" enums:"active,suspended"`
+	Status          UserStatus  `json:"status" table:"status" enums:"active,suspended,locked"`
 	OrganizationIDs []uuid.UUID `json:"organization_ids" format:"uuid"`
 	Roles           []Role      `json:"roles"`
 	AvatarURL       string      `json:"avatar_url" format:"uri"`
 	LoginType       LoginType   `json:"login_type"`
+	// ServiceAccount is true for non-human users that authenticate with
+	// tokens only.
+	ServiceAccount bool `json:"service_account" table:"service account"`
 }
 
 type GetUsersResponse struct {
@@ -77,11 +83,15 @@ type CreateFirstUserResponse struct {
 type CreateUserRequest struct {
 	Email    string `json:"email" validate:"required,email" format:"email"`
 	Username string `json:"username" validate:"required,username"`
-	Password string `json:"password" validate:"required_if=DisableLogin false"`
+	Password string `json:"password" validate:"required_if=DisableLogin false ServiceAccount false"`
 	// DisableLogin sets the user's login type to 'none'. This prevents the user
 	// from being able to use a password or any other authentication method to login.
 	DisableLogin   bool      `json:"disable_login"`
 	OrganizationID uuid.UUID `json:"organization_id" validate:"" format:"uuid"`
+	// ServiceAccount creates a non-human user. Service accounts cannot log in,
+	// authenticate with tokens created by an admin, and do not count towards
+	// the licensed user limit.
+	ServiceAccount bool `json:"service_account"`
 }
 
 type UpdateUserProfileRequest struct {
@@ -132,25 +142,43 @@ type UserRoles struct {
 
 type ConvertLoginRequest struct {
 	// ToType is the login type to convert to.
-	ToType   LoginType `json:"to_type" validate:"required"`
-	Password string    `json:"password" validate:"required"`
+	ToType LoginType `json:"to_type" validate:"required"`
+	// ToProvider is the ID of the OAuth2 login provider to convert to. It
+	// is required if ToType is "oauth2".
+	ToProvider string `json:"to_provider,omitempty"`
+	Password   string `json:"password" validate:"required"`
 }
 
 // LoginWithPasswordRequest enables callers to authenticate with email and password.
 type LoginWithPasswordRequest struct {
 	Email    string `json:"email" validate:"required,email" format:"email"`
 	Password string `json:"password" validate:"required"`
+	// TOTPCode is a code from the user's authenticator app, or one of their
+	// recovery codes. It is required if the user has enrolled in
+	// multi-factor authentication.
+	TOTPCode string `json:"totp_code,omitempty"`
+}
+
+// LoginWithLDAPRequest enables callers to authenticate with a directory
+// username and password.
+type LoginWithLDAPRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
 type LoginWithPasswordResponse struct {
 	SessionToken string `json:"session_token" validate:"required"`
+	// RecoveryCodes are returned once when multi-factor authentication is
+	// enrolled during login.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 type OAuthConversionResponse struct {
 	StateString string    `json:"state_string"`
 	ExpiresAt   time.Time `json:"expires_at" format:"date-time"`
 	ToType      LoginType `json:"to_type"`
+	ToProvider  string    `json:"to_provider,omitempty"`
 	UserID      uuid.UUID `json:"user_id" format:"uuid"`
 }
 
@@ -164,6 +192,9 @@ type AuthMethods struct {
 	Password             AuthMethod     `json:"password"`
 	Github               AuthMethod     `json:"github"`
 	OIDC                 OIDCAuthMethod `json:"oidc"`
+	LDAP                 LDAPAuthMethod `json:"ldap"`
+	// OAuth2 lists the generic OAuth2 login providers.
+	OAuth2 []OAuth2AuthMethod `json:"oauth2"`
 }
 
 type AuthMethod struct {
@@ -180,6 +211,19 @@ type OIDCAuthMethod struct {
 	IconURL    string `json:"iconUrl"`
 }
 
+type LDAPAuthMethod struct {
+	AuthMethod
+	SignInText string `json:"signInText"`
+}
+
+// OAuth2AuthMethod is a generic OAuth2 login provider. Users sign in at
+// /api/v2/users/oauth2/{id}/callback.
+type OAuth2AuthMethod struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	IconURL     string `json:"icon_url"`
+}
+
 // HasFirstUser returns whether the first user has been created.
 func (c *Client) HasFirstUser(ctx context.Context) (bool, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/first", nil)
@@ -354,6 +398,26 @@ func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordReq
 	return resp, nil
 }
 
+// LoginWithLDAP creates a session token authenticating with a username and
+// password checked against the LDAP server of the deployment.
+// Call `SetSessionToken()` to apply the newly acquired token to the client.
+func (c *Client) LoginWithLDAP(ctx context.Context, req LoginWithLDAPRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/ldap/login", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	return resp, nil
+}
+
 // ConvertLoginType will send a request to convert the user from password
 // based authentication to oauth based. The response has the oauth state code
 // to use in the oauth flow.
@@ -0,0 +1,266 @@
+package codersdk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// WebhookEventHeader is the header that contains the event of a webhook
+	// delivery.
+	WebhookEventHeader = "X-Coder-Event"
+	// WebhookDeliveryHeader is the header that contains the unique ID of a
+	// webhook delivery. Retries of the same delivery use the same ID.
+	WebhookDeliveryHeader = "X-Coder-Delivery"
+	// WebhookSignatureHeader is the header that contains the HMAC-SHA256
+	// signature of the delivery body, in the format "sha256=<hex>".
+	WebhookSignatureHeader = "X-Coder-Signature-256"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceBuildStarted     WebhookEvent = "workspace_build_started"
+	WebhookEventWorkspaceBuildSucceeded   WebhookEvent = "workspace_build_succeeded"
+	WebhookEventWorkspaceBuildFailed      WebhookEvent = "workspace_build_failed"
+	WebhookEventWorkspaceAutostopImminent WebhookEvent = "workspace_autostop_imminent"
+	WebhookEventTemplateVersionActivated  WebhookEvent = "template_version_activated"
+	WebhookEventUserCreated               WebhookEvent = "user_created"
+	WebhookEventUserSuspended             WebhookEvent = "user_suspended"
+)
+
+// WebhookEvents lists all events a webhook can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookEventWorkspaceBuildStarted,
+	WebhookEventWorkspaceBuildSucceeded,
+	WebhookEventWorkspaceBuildFailed,
+	WebhookEventWorkspaceAutostopImminent,
+	WebhookEventTemplateVersionActivated,
+	WebhookEventUserCreated,
+	WebhookEventUserSuspended,
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// Webhook is an endpoint that receives signed event payloads. The secret
+// is never returned.
+type Webhook struct {
+	ID        uuid.UUID      `json:"id" format:"uuid"`
+	CreatedAt time.Time      `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time      `json:"updated_at" format:"date-time"`
+	Name      string         `json:"name"`
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	Enabled   bool           `json:"enabled"`
+}
+
+type CreateWebhookRequest struct {
+	Name string `json:"name" validate:"required,username"`
+	URL  string `json:"url" validate:"required,url"`
+	// Secret is used to sign the payloads delivered to the URL.
+	Secret  string         `json:"secret" validate:"required"`
+	Events  []WebhookEvent `json:"events" validate:"required,min=1"`
+	Enabled bool           `json:"enabled"`
+}
+
+type UpdateWebhookRequest struct {
+	Name string `json:"name" validate:"required,username"`
+	URL  string `json:"url" validate:"required,url"`
+	// Secret replaces the secret of the webhook. An empty secret keeps the
+	// current one.
+	Secret  string         `json:"secret,omitempty"`
+	Events  []WebhookEvent `json:"events" validate:"required,min=1"`
+	Enabled bool           `json:"enabled"`
+}
+
+// WebhookDelivery is an attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID            uuid.UUID             `json:"id" format:"uuid"`
+	WebhookID     uuid.UUID             `json:"webhook_id" format:"uuid"`
+	CreatedAt     time.Time             `json:"created_at" format:"date-time"`
+	Event         WebhookEvent          `json:"event"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int32                 `json:"attempts"`
+	NextAttemptAt *time.Time            `json:"next_attempt_at,omitempty" format:"date-time"`
+	LastAttemptAt *time.Time            `json:"last_attempt_at,omitempty" format:"date-time"`
+	DeliveredAt   *time.Time            `json:"delivered_at,omitempty" format:"date-time"`
+	// StatusCode is the HTTP status code of the last attempt, if a response
+	// was received.
+	StatusCode *int32          `json:"status_code,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// WebhookPayload is the JSON body delivered to webhooks. Only the objects
+// relevant to the event are set.
+type WebhookPayload struct {
+	// ID is the unique ID of the event. It is the same for all webhooks
+	// that receive the event.
+	ID              uuid.UUID                      `json:"id" format:"uuid"`
+	Event           WebhookEvent                   `json:"event"`
+	Timestamp       time.Time                      `json:"timestamp" format:"date-time"`
+	Workspace       *WebhookPayloadWorkspace       `json:"workspace,omitempty"`
+	WorkspaceBuild  *WebhookPayloadWorkspaceBuild  `json:"workspace_build,omitempty"`
+	Template        *WebhookPayloadTemplate        `json:"template,omitempty"`
+	TemplateVersion *WebhookPayloadTemplateVersion `json:"template_version,omitempty"`
+	User            *WebhookPayloadUser            `json:"user,omitempty"`
+}
+
+type WebhookPayloadWorkspace struct {
+	ID         uuid.UUID `json:"id" format:"uuid"`
+	Name       string    `json:"name"`
+	OwnerID    uuid.UUID `json:"owner_id" format:"uuid"`
+	OwnerName  string    `json:"owner_name"`
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+}
+
+type WebhookPayloadWorkspaceBuild struct {
+	ID                uuid.UUID           `json:"id" format:"uuid"`
+	BuildNumber       int32               `json:"build_number"`
+	Transition        WorkspaceTransition `json:"transition"`
+	Reason            BuildReason         `json:"reason"`
+	InitiatorID       uuid.UUID           `json:"initiator_id" format:"uuid"`
+	TemplateVersionID uuid.UUID           `json:"template_version_id" format:"uuid"`
+	Deadline          *time.Time          `json:"deadline,omitempty" format:"date-time"`
+	Error             string              `json:"error,omitempty"`
+}
+
+type WebhookPayloadTemplate struct {
+	ID              uuid.UUID `json:"id" format:"uuid"`
+	Name            string    `json:"name"`
+	ActiveVersionID uuid.UUID `json:"active_version_id" format:"uuid"`
+}
+
+type WebhookPayloadTemplateVersion struct {
+	ID   uuid.UUID `json:"id" format:"uuid"`
+	Name string    `json:"name"`
+}
+
+type WebhookPayloadUser struct {
+	ID       uuid.UUID  `json:"id" format:"uuid"`
+	Username string     `json:"username"`
+	Email    string     `json:"email"`
+	Status   UserStatus `json:"status"`
+}
+
+// WebhookSignature returns the value of the WebhookSignatureHeader for a
+// payload signed with the secret of a webhook.
+func WebhookSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether the signature matches the payload
+// signed with the secret. Receivers should use it to authenticate
+// deliveries.
+func VerifyWebhookSignature(secret string, payload []byte, signature string) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	return hmac.Equal([]byte(WebhookSignature(secret, payload)), []byte(signature))
+}
+
+func (c *Client) Webhooks(ctx context.Context) ([]Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/webhooks", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var webhooks []Webhook
+	return webhooks, json.NewDecoder(res.Body).Decode(&webhooks)
+}
+
+func (c *Client) Webhook(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/webhooks", req)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, id uuid.UUID, req UpdateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/webhooks/%s", id), req)
+	if err != nil {
+		return Webhook{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebhookDeliveries returns the most recent deliveries of a webhook. A limit
+// of zero returns all deliveries.
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID, limit int) ([]WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s/deliveries", id), nil,
+		WithQueryParam("limit", strconv.Itoa(limit)),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var deliveries []WebhookDelivery
+	return deliveries, json.NewDecoder(res.Body).Decode(&deliveries)
+}
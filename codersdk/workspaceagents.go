@@ -108,6 +108,61 @@ const (
 	WorkspaceAgentStartupScriptBehaviorNonBlocking WorkspaceAgentStartupScriptBehavior = "non-blocking"
 )
 
+// WorkspaceAgentScriptRunOn defines when a script is run by the agent.
+type WorkspaceAgentScriptRunOn string
+
+const (
+	WorkspaceAgentScriptRunOnStart WorkspaceAgentScriptRunOn = "start"
+	WorkspaceAgentScriptRunOnStop  WorkspaceAgentScriptRunOn = "stop"
+)
+
+// WorkspaceAgentScriptStatus is the state of a script run by the agent.
+type WorkspaceAgentScriptStatus string
+
+const (
+	WorkspaceAgentScriptStatusPending WorkspaceAgentScriptStatus = "pending"
+	WorkspaceAgentScriptStatusRunning WorkspaceAgentScriptStatus = "running"
+	WorkspaceAgentScriptStatusSuccess WorkspaceAgentScriptStatus = "success"
+	WorkspaceAgentScriptStatusError   WorkspaceAgentScriptStatus = "error"
+	WorkspaceAgentScriptStatusTimeout WorkspaceAgentScriptStatus = "timeout"
+	// WorkspaceAgentScriptStatusSkipped means the script did not run
+	// because one of its dependencies did not succeed.
+	WorkspaceAgentScriptStatusSkipped WorkspaceAgentScriptStatus = "skipped"
+)
+
+// Done returns true if the script is no longer expected to run.
+func (s WorkspaceAgentScriptStatus) Done() bool {
+	switch s {
+	case WorkspaceAgentScriptStatusSuccess, WorkspaceAgentScriptStatusError,
+		WorkspaceAgentScriptStatusTimeout, WorkspaceAgentScriptStatusSkipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// WorkspaceAgentScript is a named script run by the agent when the
+// workspace starts or stops. Each script writes to its own log source.
+type WorkspaceAgentScript struct {
+	ID          uuid.UUID                 `json:"id" format:"uuid"`
+	Name        string                    `json:"name"`
+	DisplayName string                    `json:"display_name"`
+	Script      string                    `json:"script"`
+	RunOn       WorkspaceAgentScriptRunOn `json:"run_on" enums:"start,stop"`
+	// TimeoutSeconds is the number of seconds the script may run before it
+	// is killed. Zero means there is no timeout.
+	TimeoutSeconds int32 `json:"timeout_seconds"`
+	// StartBlocksLogin prevents logins until the script has completed.
+	StartBlocksLogin bool `json:"start_blocks_login"`
+	// DependsOn are the names of scripts that must complete successfully
+	// before this script runs.
+	DependsOn []string                   `json:"depends_on"`
+	Status    WorkspaceAgentScriptStatus `json:"status" enums:"pending,running,success,error,timeout,skipped"`
+	ExitCode  *int32                     `json:"exit_code,omitempty"`
+	StartedAt *time.Time                 `json:"started_at,omitempty" format:"date-time"`
+	EndedAt   *time.Time                 `json:"ended_at,omitempty" format:"date-time"`
+}
+
 type WorkspaceAgentMetadataResult struct {
 	CollectedAt time.Time `json:"collected_at" format:"date-time"`
 	// Age is the number of seconds since the metadata was collected.
@@ -159,6 +214,7 @@ type WorkspaceAgent struct {
 	ExpandedDirectory           string                              `json:"expanded_directory,omitempty"`
 	Version                     string                              `json:"version"`
 	Apps                        []WorkspaceApp                      `json:"apps"`
+	Scripts                     []WorkspaceAgentScript              `json:"scripts"`
 	// DERPLatency is mapped by region name (e.g. "New York City", "Seattle").
 	DERPLatency              map[string]DERPRegion `json:"latency,omitempty"`
 	ConnectionTimeoutSeconds int32                 `json:"connection_timeout_seconds"`
@@ -678,6 +734,9 @@ type WorkspaceAgentStartupLog struct {
 	CreatedAt time.Time `json:"created_at" format:"date-time"`
 	Output    string    `json:"output"`
 	Level     LogLevel  `json:"level"`
+	// Source is the name of the script that produced the log. It is empty
+	// for the startup script.
+	Source string `json:"source"`
 }
 
 type AgentSubsystem string
@@ -685,3 +744,56 @@ type AgentSubsystem string
 const (
 	AgentSubsystemEnvbox AgentSubsystem = "envbox"
 )
+
+type WorkspaceAgentSessionRecordingType string
+
+const (
+	WorkspaceAgentSessionRecordingTypeSSH             WorkspaceAgentSessionRecordingType = "ssh"
+	WorkspaceAgentSessionRecordingTypeReconnectingPTY WorkspaceAgentSessionRecordingType = "reconnecting_pty"
+)
+
+// WorkspaceAgentSessionRecording describes a recording of an interactive
+// terminal session. The recording itself is in the asciicast v2 format and
+// is fetched separately.
+type WorkspaceAgentSessionRecording struct {
+	ID          uuid.UUID                          `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID                          `json:"workspace_id" format:"uuid"`
+	AgentID     uuid.UUID                          `json:"agent_id" format:"uuid"`
+	Type        WorkspaceAgentSessionRecordingType `json:"type" enums:"ssh,reconnecting_pty"`
+	CreatedAt   time.Time                          `json:"created_at" format:"date-time"`
+	StartedAt   time.Time                          `json:"started_at" format:"date-time"`
+	EndedAt     time.Time                          `json:"ended_at" format:"date-time"`
+	// Truncated is true if the session produced more output than the agent
+	// was willing to record.
+	Truncated bool  `json:"truncated"`
+	Size      int64 `json:"size"`
+}
+
+// WorkspaceAgentSessionRecordings lists the recorded terminal sessions of
+// an agent, most recent first.
+func (c *Client) WorkspaceAgentSessionRecordings(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentSessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/session-recordings", agentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []WorkspaceAgentSessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// WorkspaceAgentSessionRecording downloads a recorded terminal session in
+// the asciicast v2 format.
+func (c *Client) WorkspaceAgentSessionRecording(ctx context.Context, agentID, recordingID uuid.UUID) ([]byte, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/session-recordings/%s", agentID, recordingID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	return io.ReadAll(res.Body)
+}
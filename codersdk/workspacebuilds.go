@@ -135,6 +135,25 @@ func (c *Client) WorkspaceBuildLogsAfter(ctx context.Context, build uuid.UUID, a
 	return c.provisionerJobLogsAfter(ctx, fmt.Sprintf("/api/v2/workspacebuilds/%s/logs", build), after)
 }
 
+// WorkspaceBuildLogs returns the logs for a workspace build that have been
+// written so far and occurred after a specific log ID.
+func (c *Client) WorkspaceBuildLogs(ctx context.Context, build uuid.UUID, after int64) ([]ProvisionerJobLog, error) {
+	path := fmt.Sprintf("/api/v2/workspacebuilds/%s/logs", build)
+	if after != 0 {
+		path += fmt.Sprintf("?after=%d", after)
+	}
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []ProvisionerJobLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
+
 // WorkspaceBuildState returns the provisioner state of the build.
 func (c *Client) WorkspaceBuildState(ctx context.Context, build uuid.UUID) ([]byte, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/state", build), nil)
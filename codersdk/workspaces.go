@@ -308,6 +308,89 @@ func (c *Client) UpdateWorkspaceLock(ctx context.Context, id uuid.UUID, req Upda
 	return nil
 }
 
+// TransferWorkspaceRequest is a request to transfer a workspace to another
+// user.
+type TransferWorkspaceRequest struct {
+	// Owner is the ID or username of the new owner.
+	Owner string `json:"owner" validate:"required"`
+}
+
+// TransferWorkspace transfers a workspace to another user. The workspace is
+// rebuilt for the new owner, and the returned build can be used to follow
+// its progress.
+func (c *Client) TransferWorkspace(ctx context.Context, id uuid.UUID, req TransferWorkspaceRequest) (WorkspaceBuild, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/owner", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return WorkspaceBuild{}, xerrors.Errorf("transfer workspace: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
+
+type WorkspaceRole string
+
+const (
+	// WorkspaceRoleView allows viewing the workspace and its builds.
+	WorkspaceRoleView WorkspaceRole = "view"
+	// WorkspaceRoleConnect additionally allows connecting to the workspace
+	// over SSH, the web terminal, port forwarding and apps.
+	WorkspaceRoleConnect WorkspaceRole = "connect"
+	WorkspaceRoleDeleted WorkspaceRole = ""
+)
+
+type WorkspaceACL struct {
+	Users  []WorkspaceUser  `json:"users"`
+	Groups []WorkspaceGroup `json:"group"`
+}
+
+type WorkspaceGroup struct {
+	Group
+	Role WorkspaceRole `json:"role" enums:"view,connect"`
+}
+
+type WorkspaceUser struct {
+	User
+	Role WorkspaceRole `json:"role" enums:"view,connect"`
+}
+
+type UpdateWorkspaceACL struct {
+	// UserPerms should be a mapping of user id to role. The user id must be the
+	// uuid of the user, not a username or email address.
+	UserPerms map[string]WorkspaceRole `json:"user_perms,omitempty" example:"<user_id>:connect,4df59e74-c027-470b-ab4d-cbba8963a5e9:view"`
+	// GroupPerms should be a mapping of group id to role.
+	GroupPerms map[string]WorkspaceRole `json:"group_perms,omitempty" example:"<group_id>:connect,8bd26b20-f3e8-48be-a903-46bb920cf671:view"`
+}
+
+func (c *Client) UpdateWorkspaceACL(ctx context.Context, workspaceID uuid.UUID, req UpdateWorkspaceACL) error {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/workspaces/%s/acl", workspaceID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func (c *Client) WorkspaceACL(ctx context.Context, workspaceID uuid.UUID) (WorkspaceACL, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/acl", workspaceID), nil)
+	if err != nil {
+		return WorkspaceACL{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceACL{}, ReadBodyAsError(res)
+	}
+	var acl WorkspaceACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
 type WorkspaceFilter struct {
 	// Owner can be "me" or a username
 	Owner string `json:"owner,omitempty" typescript:"-"`
@@ -395,6 +478,57 @@ func (c *Client) WorkspaceByOwnerAndName(ctx context.Context, owner string, name
 	return workspace, json.NewDecoder(res.Body).Decode(&workspace)
 }
 
+type BulkWorkspaceAction string
+
+const (
+	BulkWorkspaceActionStart BulkWorkspaceAction = "start"
+	BulkWorkspaceActionStop  BulkWorkspaceAction = "stop"
+	// BulkWorkspaceActionUpdate starts outdated workspaces with the active
+	// version of their template.
+	BulkWorkspaceActionUpdate BulkWorkspaceAction = "update"
+	BulkWorkspaceActionDelete BulkWorkspaceAction = "delete"
+)
+
+type BulkWorkspaceActionRequest struct {
+	Action BulkWorkspaceAction `json:"action" validate:"oneof=start stop update delete,required"`
+	// Query selects the workspaces in the same format as the workspaces
+	// search query, e.g. "template:foo status:running".
+	Query string `json:"q" validate:"required"`
+	// DryRun returns the selected workspaces without building them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+type BulkWorkspaceActionResult struct {
+	WorkspaceID   uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName string    `json:"workspace_name"`
+	OwnerName     string    `json:"owner_name"`
+	// BuildID is the build that was created for the workspace. It is unset
+	// for dry runs and failures.
+	BuildID *uuid.UUID `json:"build_id,omitempty" format:"uuid"`
+	Error   string     `json:"error,omitempty"`
+}
+
+type BulkWorkspaceActionResponse struct {
+	Action  BulkWorkspaceAction         `json:"action"`
+	DryRun  bool                        `json:"dry_run"`
+	Results []BulkWorkspaceActionResult `json:"results"`
+}
+
+// BulkWorkspaceAction starts, stops, updates or deletes all workspaces
+// matching a search query. Failures are reported per workspace.
+func (c *Client) BulkWorkspaceAction(ctx context.Context, req BulkWorkspaceActionRequest) (BulkWorkspaceActionResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaces/bulk", req)
+	if err != nil {
+		return BulkWorkspaceActionResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return BulkWorkspaceActionResponse{}, ReadBodyAsError(res)
+	}
+	var resp BulkWorkspaceActionResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 type WorkspaceQuota struct {
 	CreditsConsumed int `json:"credits_consumed"`
 	Budget          int `json:"budget"`
@@ -2,6 +2,7 @@ package audit
 
 import (
 	"context"
+	"errors"
 
 	"golang.org/x/xerrors"
 
@@ -41,6 +42,9 @@ func (a *auditor) Export(ctx context.Context, alog database.AuditLog) error {
 		return xerrors.Errorf("filter check: %w", err)
 	}
 
+	// Export to every backend even if one fails, so an unreachable external
+	// backend doesn't prevent the audit log from being stored.
+	var errs []error
 	for _, backend := range a.backends {
 		if decision&backend.Decision() != backend.Decision() {
 			continue
@@ -48,11 +52,9 @@ func (a *auditor) Export(ctx context.Context, alog database.AuditLog) error {
 
 		err = backend.Export(ctx, alog)
 		if err != nil {
-			// naively return the first error. should probably make this smarter
-			// by returning multiple errors.
-			return xerrors.Errorf("export audit log to backend: %w", err)
+			errs = append(errs, xerrors.Errorf("export audit log to backend: %w", err))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -109,6 +109,22 @@ func TestAuditor(t *testing.T) {
 	}
 }
 
+func TestAuditorBackendError(t *testing.T) {
+	t.Parallel()
+
+	// A failing backend doesn't prevent exporting to the others.
+	var (
+		backendErr = xerrors.New("backend errored")
+		failing    = &testBackend{decision: audit.FilterDecisionExport, err: backendErr}
+		backend    = &testBackend{decision: audit.FilterDecisionExport}
+		exporter   = audit.NewAuditor(audit.DefaultFilter, failing, backend)
+	)
+
+	err := exporter.Export(context.Background(), audittest.RandomLog())
+	require.ErrorIs(t, err, backendErr)
+	require.Len(t, backend.alogs, 1)
+}
+
 type testBackend struct {
 	decision audit.FilterDecision
 	err      error
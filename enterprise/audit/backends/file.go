@@ -0,0 +1,130 @@
+package backends
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	agplaudit "github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/enterprise/audit"
+)
+
+type FileOptions struct {
+	// Path is the file audit logs are appended to as JSON lines.
+	Path string
+	// MaxSizeMB is the size in megabytes after which the file is rotated.
+	MaxSizeMB int
+	// MaxAge is the duration after which the file is rotated. Zero disables
+	// time based rotation.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of
+	// them.
+	MaxBackups int
+}
+
+type fileBackend struct {
+	metrics *Metrics
+	closeCh chan struct{}
+	done    chan struct{}
+
+	mu      sync.Mutex
+	logger  *lumberjack.Logger
+	written bool
+	closed  bool
+}
+
+// NewFile appends audit logs to a file as JSON lines. Rotated files are
+// renamed with a timestamp, like audit-2023-01-02T15-04-05.000.jsonl.
+func NewFile(metrics *Metrics, opts FileOptions) (audit.Backend, error) {
+	if opts.Path == "" {
+		return nil, xerrors.New("audit log file path must be set")
+	}
+	b := &fileBackend{
+		metrics: metrics,
+		closeCh: make(chan struct{}),
+		done:    make(chan struct{}),
+		logger: &lumberjack.Logger{
+			Filename:   opts.Path,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+		},
+	}
+	go b.rotateLoop(opts.MaxAge)
+	return b, nil
+}
+
+func (b *fileBackend) rotateLoop(maxAge time.Duration) {
+	defer close(b.done)
+	if maxAge <= 0 {
+		<-b.closeCh
+		return
+	}
+
+	ticker := time.NewTicker(maxAge)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		b.mu.Lock()
+		// Don't fill the directory with empty files.
+		if b.written {
+			b.written = false
+			if err := b.logger.Rotate(); err != nil {
+				b.metrics.errors.WithLabelValues("file").Inc()
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+func (*fileBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *fileBackend) Export(_ context.Context, alog database.AuditLog) error {
+	line, err := agplaudit.MarshalLog(alog)
+	if err != nil {
+		return xerrors.Errorf("marshal audit log: %w", err)
+	}
+	line = append(line, '\n')
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		b.metrics.dropped.WithLabelValues("file").Inc()
+		return xerrors.New("audit log file is closed")
+	}
+	_, err = b.logger.Write(line)
+	if err != nil {
+		b.metrics.errors.WithLabelValues("file").Inc()
+		b.metrics.dropped.WithLabelValues("file").Inc()
+		return xerrors.Errorf("write audit log file: %w", err)
+	}
+	b.written = true
+	b.metrics.delivered.WithLabelValues("file").Inc()
+	return nil
+}
+
+func (b *fileBackend) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	// lumberjack reopens the file when written after Close, so further
+	// writes must be prevented.
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.closeCh)
+	<-b.done
+	return b.logger.Close()
+}
@@ -0,0 +1,91 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/enterprise/audit/audittest"
+	"github.com/coder/coder/enterprise/audit/backends"
+	"github.com/coder/coder/testutil"
+)
+
+func TestFileBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		registry := prometheus.NewRegistry()
+		backend, err := backends.NewFile(backends.NewMetrics(registry), backends.FileOptions{
+			Path:      path,
+			MaxSizeMB: 1,
+		})
+		require.NoError(t, err)
+
+		logs := []string{}
+		for i := 0; i < 3; i++ {
+			alog := audittest.RandomLog()
+			logs = append(logs, alog.ID.String())
+			err = backend.Export(ctx, alog)
+			require.NoError(t, err)
+		}
+		err = backend.(io.Closer).Close()
+		require.NoError(t, err)
+
+		// Logs are rejected once closed.
+		err = backend.Export(ctx, audittest.RandomLog())
+		require.Error(t, err)
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		ids := []string{}
+		for scanner.Scan() {
+			var alog map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &alog))
+			ids = append(ids, alog["id"].(string))
+		}
+		require.NoError(t, scanner.Err())
+		require.Equal(t, logs, ids)
+		require.Equal(t, 3.0, metricValue(t, registry, "coderd_audit_export_delivered_logs_total", "file"))
+		require.Equal(t, 1.0, metricValue(t, registry, "coderd_audit_export_dropped_logs_total", "file"))
+	})
+
+	t.Run("RotateByAge", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		dir := t.TempDir()
+		backend, err := backends.NewFile(backends.NewMetrics(prometheus.NewRegistry()), backends.FileOptions{
+			Path:   filepath.Join(dir, "audit.jsonl"),
+			MaxAge: 10 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		defer backend.(io.Closer).Close()
+
+		err = backend.Export(ctx, audittest.RandomLog())
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			rotated, err := filepath.Glob(filepath.Join(dir, "audit-*.jsonl"))
+			return err == nil && len(rotated) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// Nothing was written since, so the file isn't rotated again.
+		time.Sleep(50 * time.Millisecond)
+		rotated, err := filepath.Glob(filepath.Join(dir, "audit-*.jsonl"))
+		require.NoError(t, err)
+		require.Len(t, rotated, 1)
+	})
+}
@@ -0,0 +1,53 @@
+package backends
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics count the delivery of audit logs by the export backends. Each
+// backend is a label value, so a Metrics should be shared by all backends.
+type Metrics struct {
+	delivered *prometheus.CounterVec
+	dropped   *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+	pending   *prometheus.GaugeVec
+}
+
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	delivered := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "audit_export",
+		Name:      "delivered_logs_total",
+		Help:      "The number of audit logs delivered by a backend.",
+	}, []string{"backend"})
+	registerer.MustRegister(delivered)
+
+	dropped := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "audit_export",
+		Name:      "dropped_logs_total",
+		Help:      "The number of audit logs a backend failed to deliver and gave up on.",
+	}, []string{"backend"})
+	registerer.MustRegister(dropped)
+
+	errors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "audit_export",
+		Name:      "errors_total",
+		Help:      "The number of failed delivery attempts of a backend.",
+	}, []string{"backend"})
+	registerer.MustRegister(errors)
+
+	pending := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "audit_export",
+		Name:      "pending_logs",
+		Help:      "The number of audit logs buffered by a backend for later delivery.",
+	}, []string{"backend"})
+	registerer.MustRegister(pending)
+
+	return &Metrics{
+		delivered: delivered,
+		dropped:   dropped,
+		errors:    errors,
+		pending:   pending,
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"golang.org/x/xerrors"
 
+	agplaudit "github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/enterprise/audit"
 )
@@ -31,7 +32,7 @@ func (b *postgresBackend) Decision() audit.FilterDecision {
 }
 
 func (b *postgresBackend) Export(ctx context.Context, alog database.AuditLog) error {
-	_, err := b.db.InsertAuditLog(ctx, database.InsertAuditLogParams(alog))
+	_, err := agplaudit.InsertLog(ctx, b.db, database.InsertAuditLogParams(alog))
 	if err != nil {
 		return xerrors.Errorf("insert audit log: %w", err)
 	}
@@ -35,5 +35,11 @@ func TestPostgresBackend(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, got, 1)
 		require.Equal(t, alog.ID, got[0].ID)
+
+		// The audit log is appended to the hash chain.
+		hash, err := db.GetLatestAuditLogHash(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), hash.Sequence)
+		require.Equal(t, alog.ID, hash.AuditLogID)
 	})
 }
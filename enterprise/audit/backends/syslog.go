@@ -0,0 +1,234 @@
+package backends
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	agplaudit "github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/enterprise/audit"
+)
+
+const (
+	// syslogPriority is the "log audit" facility with the "informational"
+	// severity.
+	syslogPriority = 13*8 + 6
+	syslogTimeout  = 5 * time.Second
+)
+
+type SyslogOptions struct {
+	// Address is the address of the syslog server, as udp://, tcp:// or
+	// tls://host:port.
+	Address string
+	// TLSConfig is used for tls addresses.
+	TLSConfig *tls.Config
+	// QueueSize is the number of logs waiting to be sent after which new
+	// logs are dropped. Defaults to 1000.
+	QueueSize int
+}
+
+type syslogBackend struct {
+	log       slog.Logger
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	hostname  string
+	metrics   *Metrics
+	queue     chan []byte
+	closeCh   chan struct{}
+	done      chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+
+	// conn is only used by the sending goroutine.
+	conn net.Conn
+}
+
+// NewSyslog exports audit logs to a syslog server in the RFC 5424 format,
+// with the JSON audit log as the message. TCP and TLS messages are framed by
+// octet counting as described in RFC 6587. Logs are queued and sent by a
+// background goroutine, so a slow server doesn't block requests. The
+// connection is established on the first send, and reestablished if a write
+// fails.
+func NewSyslog(logger slog.Logger, metrics *Metrics, opts SyslogOptions) (audit.Backend, error) {
+	u, err := url.Parse(opts.Address)
+	if err != nil {
+		return nil, xerrors.Errorf("parse syslog address: %w", err)
+	}
+	switch u.Scheme {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, xerrors.Errorf("syslog address scheme must be udp, tcp or tls, got %q", u.Scheme)
+	}
+	if u.Port() == "" {
+		return nil, xerrors.Errorf("syslog address %q must have a port", opts.Address)
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	b := &syslogBackend{
+		log:       logger,
+		network:   u.Scheme,
+		address:   u.Host,
+		tlsConfig: opts.TLSConfig,
+		hostname:  hostname,
+		metrics:   metrics,
+		queue:     make(chan []byte, opts.QueueSize),
+		closeCh:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go b.run()
+	return b, nil
+}
+
+func (*syslogBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+// Export queues the audit log. It is sent by a background goroutine, and
+// dropped if the queue is full.
+func (b *syslogBackend) Export(_ context.Context, alog database.AuditLog) error {
+	msg, err := agplaudit.MarshalLog(alog)
+	if err != nil {
+		return xerrors.Errorf("marshal audit log: %w", err)
+	}
+	frame := []byte(fmt.Sprintf("<%d>1 %s %s coder %d audit - %s",
+		syslogPriority,
+		alog.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		b.hostname,
+		os.Getpid(),
+		msg,
+	))
+	if b.network != "udp" {
+		frame = append([]byte(fmt.Sprintf("%d ", len(frame))), frame...)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		b.metrics.dropped.WithLabelValues("syslog").Inc()
+		return xerrors.New("syslog backend is closed")
+	}
+	select {
+	case b.queue <- frame:
+		b.metrics.pending.WithLabelValues("syslog").Inc()
+		return nil
+	default:
+		b.metrics.dropped.WithLabelValues("syslog").Inc()
+		return xerrors.New("syslog queue is full")
+	}
+}
+
+// run sends queued logs until the backend is closed, and then sends the
+// logs still in the queue.
+func (b *syslogBackend) run() {
+	defer close(b.done)
+	defer func() {
+		if b.conn != nil {
+			_ = b.conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for {
+		select {
+		case frame := <-b.queue:
+			b.send(ctx, frame)
+		case <-b.closeCh:
+			drainCtx, drainCancel := context.WithTimeout(ctx, syslogTimeout)
+			defer drainCancel()
+			for {
+				select {
+				case frame := <-b.queue:
+					b.send(drainCtx, frame)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *syslogBackend) send(ctx context.Context, frame []byte) {
+	defer b.metrics.pending.WithLabelValues("syslog").Dec()
+
+	err := ctx.Err()
+	if err != nil {
+		b.metrics.dropped.WithLabelValues("syslog").Inc()
+		return
+	}
+	// Retry once with a new connection, since the server may have closed
+	// the previous one.
+	for attempt := 0; attempt < 2; attempt++ {
+		err = b.write(ctx, frame)
+		if err == nil {
+			b.metrics.delivered.WithLabelValues("syslog").Inc()
+			return
+		}
+		b.metrics.errors.WithLabelValues("syslog").Inc()
+	}
+	b.metrics.dropped.WithLabelValues("syslog").Inc()
+	b.log.Warn(ctx, "write audit log to syslog", slog.Error(err))
+}
+
+func (b *syslogBackend) write(ctx context.Context, frame []byte) error {
+	if b.conn == nil {
+		ctx, cancel := context.WithTimeout(ctx, syslogTimeout)
+		defer cancel()
+		var (
+			conn net.Conn
+			err  error
+		)
+		if b.network == "tls" {
+			dialer := &tls.Dialer{Config: b.tlsConfig}
+			conn, err = dialer.DialContext(ctx, "tcp", b.address)
+		} else {
+			var dialer net.Dialer
+			conn, err = dialer.DialContext(ctx, b.network, b.address)
+		}
+		if err != nil {
+			return xerrors.Errorf("dial: %w", err)
+		}
+		b.conn = conn
+	}
+
+	_ = b.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	_, err := b.conn.Write(frame)
+	if err != nil {
+		_ = b.conn.Close()
+		b.conn = nil
+		return err
+	}
+	return nil
+}
+
+// Close sends the queued logs, giving up on the rest after a timeout.
+func (b *syslogBackend) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.closeCh)
+	<-b.done
+	return nil
+}
@@ -0,0 +1,213 @@
+package backends_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/enterprise/audit/audittest"
+	"github.com/coder/coder/enterprise/audit/backends"
+	"github.com/coder/coder/testutil"
+)
+
+func TestSyslogBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TCP", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		frames := make(chan string, 2)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			for {
+				// Frames are prefixed with their length.
+				prefix, err := reader.ReadString(' ')
+				if err != nil {
+					return
+				}
+				length, err := strconv.Atoi(strings.TrimSpace(prefix))
+				if err != nil {
+					return
+				}
+				frame := make([]byte, length)
+				_, err = io.ReadFull(reader, frame)
+				if err != nil {
+					return
+				}
+				frames <- string(frame)
+			}
+		}()
+
+		registry := prometheus.NewRegistry()
+		backend, err := backends.NewSyslog(slogtest.Make(t, nil), backends.NewMetrics(registry), backends.SyslogOptions{
+			Address: "tcp://" + listener.Addr().String(),
+		})
+		require.NoError(t, err)
+		defer backend.(io.Closer).Close()
+
+		alog := audittest.RandomLog()
+		err = backend.Export(ctx, alog)
+		require.NoError(t, err)
+		err = backend.Export(ctx, audittest.RandomLog())
+		require.NoError(t, err)
+
+		var frame string
+		select {
+		case frame = <-frames:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for syslog message")
+		}
+		requireSyslogMessage(t, frame, alog.ID.String())
+		select {
+		case <-frames:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for syslog message")
+		}
+		// The metric is updated after the frame is written.
+		require.Eventually(t, func() bool {
+			return metricValue(t, registry, "coderd_audit_export_delivered_logs_total", "syslog") == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("UDP", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		backend, err := backends.NewSyslog(slogtest.Make(t, nil), backends.NewMetrics(prometheus.NewRegistry()), backends.SyslogOptions{
+			Address: "udp://" + conn.LocalAddr().String(),
+		})
+		require.NoError(t, err)
+		defer backend.(io.Closer).Close()
+
+		alog := audittest.RandomLog()
+		err = backend.Export(ctx, alog)
+		require.NoError(t, err)
+
+		buf := make([]byte, 64<<10)
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		requireSyslogMessage(t, string(buf[:n]), alog.ID.String())
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		_ = listener.Close()
+
+		registry := prometheus.NewRegistry()
+		backend, err := backends.NewSyslog(slogtest.Make(t, nil), backends.NewMetrics(registry), backends.SyslogOptions{
+			Address: "tcp://" + address,
+		})
+		require.NoError(t, err)
+
+		defer backend.(io.Closer).Close()
+
+		// Logs are sent in the background, so the export succeeds.
+		err = backend.Export(ctx, audittest.RandomLog())
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return metricValue(t, registry, "coderd_audit_export_dropped_logs_total", "syslog") == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("QueueFull", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		// The server never completes the TLS handshake, so the first log
+		// blocks the sender.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}()
+
+		registry := prometheus.NewRegistry()
+		backend, err := backends.NewSyslog(slogtest.Make(t, nil), backends.NewMetrics(registry), backends.SyslogOptions{
+			Address:   "tls://" + listener.Addr().String(),
+			TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+			QueueSize: 1,
+		})
+		require.NoError(t, err)
+
+		// The sender holds at most one log and the queue another, so
+		// exports don't block and the rest are dropped.
+		var dropped int
+		for i := 0; i < 3; i++ {
+			err = backend.Export(ctx, audittest.RandomLog())
+			if err != nil {
+				require.ErrorContains(t, err, "queue is full")
+				dropped++
+			}
+		}
+		require.GreaterOrEqual(t, dropped, 1)
+		require.Equal(t, float64(dropped), metricValue(t, registry, "coderd_audit_export_dropped_logs_total", "syslog"))
+
+		select {
+		case conn := <-accepted:
+			_ = conn.Close()
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for connection")
+		}
+		_ = listener.Close()
+		require.NoError(t, backend.(io.Closer).Close())
+	})
+
+	t.Run("InvalidAddress", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := backends.NewSyslog(slogtest.Make(t, nil), backends.NewMetrics(prometheus.NewRegistry()), backends.SyslogOptions{
+			Address: "http://localhost:514",
+		})
+		require.Error(t, err)
+		_, err = backends.NewSyslog(slogtest.Make(t, nil), backends.NewMetrics(prometheus.NewRegistry()), backends.SyslogOptions{
+			Address: "tcp://localhost",
+		})
+		require.Error(t, err)
+	})
+}
+
+func requireSyslogMessage(t *testing.T, frame, id string) {
+	t.Helper()
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+	parts := strings.SplitN(frame, " ", 8)
+	require.Len(t, parts, 8)
+	require.Equal(t, "<110>1", parts[0])
+	require.Equal(t, "coder", parts[3])
+	require.Equal(t, "audit", parts[5])
+	var msg map[string]any
+	require.NoError(t, json.Unmarshal([]byte(parts[7]), &msg))
+	require.Equal(t, id, msg["id"])
+}
@@ -0,0 +1,475 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	agplaudit "github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/enterprise/audit"
+)
+
+const (
+	webhookAttempts = 3
+	webhookTimeout  = 30 * time.Second
+	// webhookMaxBufferedBatches is the number of batches kept in memory while
+	// a flush is in progress. More logs are handed to the spooler.
+	webhookMaxBufferedBatches = 10
+	// webhookSpoolQueueSize is the number of overflowing buffers waiting to
+	// be spooled. Logs are dropped once it is full.
+	webhookSpoolQueueSize = 10
+)
+
+type WebhookOptions struct {
+	// URL receives POST requests with a JSON array of audit logs.
+	URL string
+	// Headers are added to every request, such as for authentication.
+	Headers http.Header
+	// BatchSize is the maximum number of audit logs sent in a request.
+	BatchSize int
+	// FlushInterval is how often buffered audit logs are sent.
+	FlushInterval time.Duration
+	// SpoolDir is where batches that could not be delivered are stored until
+	// they can be retried.
+	SpoolDir string
+	// SpoolMaxBytes is the size of SpoolDir after which the oldest batches
+	// are dropped.
+	SpoolMaxBytes int64
+	// HTTPClient is used to send requests. Defaults to a client with a
+	// timeout.
+	HTTPClient *http.Client
+}
+
+type webhookBackend struct {
+	log     slog.Logger
+	metrics *Metrics
+	opts    WebhookOptions
+	notify  chan struct{}
+	closeCh chan struct{}
+	done    chan struct{}
+
+	// The spooler goroutine owns the spool directory. Logs are spooled by
+	// sending them on spoolCh, and flushes take the oldest spooled batch
+	// from nextCh and report whether it was delivered on ackCh.
+	spoolCh      chan [][]byte
+	nextCh       chan chan *spooledBatch
+	ackCh        chan spoolAck
+	spoolerClose chan struct{}
+	spoolerDone  chan struct{}
+	// spoolID is only used by the spooler.
+	spoolID int
+
+	mu     sync.Mutex
+	buffer [][]byte
+	closed bool
+}
+
+// NewWebhook sends audit logs in batches to an HTTP endpoint. Batches are
+// sent when they are full or every FlushInterval, and retried a few times.
+// Batches that still fail are spooled to disk and retried first on the next
+// flush, so logs survive outages of the endpoint and restarts of Coder.
+func NewWebhook(logger slog.Logger, metrics *Metrics, opts WebhookOptions) (audit.Backend, error) {
+	if opts.URL == "" {
+		return nil, xerrors.New("webhook URL must be set")
+	}
+	if opts.SpoolDir == "" {
+		return nil, xerrors.New("webhook spool directory must be set")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: webhookTimeout}
+	}
+	err := os.MkdirAll(opts.SpoolDir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create spool directory: %w", err)
+	}
+
+	b := &webhookBackend{
+		log:          logger,
+		metrics:      metrics,
+		opts:         opts,
+		notify:       make(chan struct{}, 1),
+		closeCh:      make(chan struct{}),
+		done:         make(chan struct{}),
+		spoolCh:      make(chan [][]byte, webhookSpoolQueueSize),
+		nextCh:       make(chan chan *spooledBatch),
+		ackCh:        make(chan spoolAck),
+		spoolerClose: make(chan struct{}),
+		spoolerDone:  make(chan struct{}),
+	}
+	// Batches spooled before a restart are still pending.
+	spooled, err := b.spooled()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range spooled {
+		b.metrics.pending.WithLabelValues("webhook").Add(float64(file.count))
+	}
+	go b.spooler()
+	go b.run()
+	return b, nil
+}
+
+func (*webhookBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+// Export buffers the audit log. It is sent by a background goroutine.
+func (b *webhookBackend) Export(_ context.Context, alog database.AuditLog) error {
+	raw, err := agplaudit.MarshalLog(alog)
+	if err != nil {
+		return xerrors.Errorf("marshal audit log: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		b.metrics.dropped.WithLabelValues("webhook").Inc()
+		return xerrors.New("webhook backend is closed")
+	}
+	b.buffer = append(b.buffer, raw)
+	b.metrics.pending.WithLabelValues("webhook").Inc()
+	if len(b.buffer) >= b.opts.BatchSize*webhookMaxBufferedBatches {
+		// The endpoint is slow or a flush is retrying, so spool instead of
+		// growing the buffer.
+		logs := b.buffer
+		b.buffer = nil
+		select {
+		case b.spoolCh <- logs:
+			return nil
+		default:
+			b.metrics.dropped.WithLabelValues("webhook").Add(float64(len(logs)))
+			b.metrics.pending.WithLabelValues("webhook").Sub(float64(len(logs)))
+			return xerrors.New("webhook spool queue is full")
+		}
+	}
+	if len(b.buffer) >= b.opts.BatchSize {
+		select {
+		case b.notify <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (b *webhookBackend) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-b.closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-b.closeCh:
+			return
+		case <-ticker.C:
+		case <-b.notify:
+		}
+		b.flush(ctx)
+	}
+}
+
+// flush sends spooled batches, oldest first, and then the buffered logs.
+// Once a request fails, the remaining logs are spooled.
+func (b *webhookBackend) flush(ctx context.Context) {
+	failed := false
+	for {
+		reply := make(chan *spooledBatch, 1)
+		b.nextCh <- reply
+		batch := <-reply
+		if batch == nil {
+			break
+		}
+		err := b.send(ctx, batch.body)
+		if err != nil {
+			b.log.Warn(ctx, "send spooled audit logs", slog.Error(err))
+		}
+		b.ackCh <- spoolAck{file: batch.file, delivered: err == nil}
+		if err != nil {
+			failed = true
+			break
+		}
+	}
+
+	b.mu.Lock()
+	logs := b.buffer
+	b.buffer = nil
+	b.mu.Unlock()
+
+	for len(logs) > 0 {
+		n := len(logs)
+		if n > b.opts.BatchSize {
+			n = b.opts.BatchSize
+		}
+		if !failed {
+			err := b.send(ctx, webhookBody(logs[:n]))
+			if err == nil {
+				b.delivered(n)
+				logs = logs[n:]
+				continue
+			}
+			b.log.Warn(ctx, "send audit logs", slog.Error(err))
+			failed = true
+		}
+		b.spoolCh <- logs[:n]
+		logs = logs[n:]
+	}
+}
+
+func (b *webhookBackend) delivered(n int) {
+	b.metrics.delivered.WithLabelValues("webhook").Add(float64(n))
+	b.metrics.pending.WithLabelValues("webhook").Sub(float64(n))
+}
+
+// send posts a batch, retrying with a backoff.
+func (b *webhookBackend) send(ctx context.Context, body []byte) error {
+	var err error
+	for attempt := 0; attempt < webhookAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+		err = b.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		b.metrics.errors.WithLabelValues("webhook").Inc()
+	}
+	return err
+}
+
+func (b *webhookBackend) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	for name, values := range b.opts.Headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := b.opts.HTTPClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("post: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+func webhookBody(logs [][]byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, raw := range logs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(raw)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+type spoolFile struct {
+	path  string
+	count int
+	size  int64
+}
+
+// spooled lists the spooled batches, oldest first. The file names are
+// <unix nanoseconds>-<sequence>-<number of logs>.json.
+func (b *webhookBackend) spooled() ([]spoolFile, error) {
+	entries, err := os.ReadDir(b.opts.SpoolDir)
+	if err != nil {
+		return nil, xerrors.Errorf("read spool directory: %w", err)
+	}
+	files := make([]spoolFile, 0, len(entries))
+	for _, entry := range entries {
+		parts := strings.Split(strings.TrimSuffix(entry.Name(), ".json"), "-")
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || len(parts) != 3 {
+			continue
+		}
+		count, err := strconv.Atoi(parts[2])
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, spoolFile{
+			path:  filepath.Join(b.opts.SpoolDir, entry.Name()),
+			count: count,
+			size:  info.Size(),
+		})
+	}
+	// The zero padded names sort by time.
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+	return files, nil
+}
+
+type spooledBatch struct {
+	file spoolFile
+	body []byte
+}
+
+type spoolAck struct {
+	file      spoolFile
+	delivered bool
+}
+
+// spooler serializes access to the spool directory. The batch handed to a
+// flush is never dropped to make room, so it can't be counted twice.
+func (b *webhookBackend) spooler() {
+	defer close(b.spoolerDone)
+
+	ctx := context.Background()
+	var sending string
+	for {
+		select {
+		case logs := <-b.spoolCh:
+			b.spool(ctx, logs, sending)
+		case reply := <-b.nextCh:
+			batch := b.next(ctx)
+			if batch != nil {
+				sending = batch.file.path
+			}
+			reply <- batch
+		case ack := <-b.ackCh:
+			sending = ""
+			if ack.delivered {
+				_ = os.Remove(ack.file.path)
+				b.delivered(ack.file.count)
+			}
+		case <-b.spoolerClose:
+			for {
+				select {
+				case logs := <-b.spoolCh:
+					b.spool(ctx, logs, sending)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// next reads the oldest spooled batch. Batches that can't be read are
+// dropped.
+func (b *webhookBackend) next(ctx context.Context) *spooledBatch {
+	files, err := b.spooled()
+	if err != nil {
+		b.log.Warn(ctx, "list spooled audit logs", slog.Error(err))
+		return nil
+	}
+	for _, file := range files {
+		body, err := os.ReadFile(file.path)
+		if err != nil {
+			b.log.Warn(ctx, "read spooled audit logs", slog.F("path", file.path), slog.Error(err))
+			b.dropSpooled(file)
+			continue
+		}
+		return &spooledBatch{file: file, body: body}
+	}
+	return nil
+}
+
+// spool writes logs to the spool directory, and drops the oldest batches
+// other than the one being sent if it grows larger than SpoolMaxBytes.
+func (b *webhookBackend) spool(ctx context.Context, logs [][]byte, sending string) {
+	b.spoolID++
+	name := fmt.Sprintf("%020d-%06d-%d.json", time.Now().UnixNano(), b.spoolID%1000000, len(logs))
+	err := os.WriteFile(filepath.Join(b.opts.SpoolDir, name), webhookBody(logs), 0o600)
+	if err != nil {
+		b.log.Error(ctx, "spool audit logs", slog.Error(err))
+		b.metrics.dropped.WithLabelValues("webhook").Add(float64(len(logs)))
+		b.metrics.pending.WithLabelValues("webhook").Sub(float64(len(logs)))
+		return
+	}
+	if b.opts.SpoolMaxBytes <= 0 {
+		return
+	}
+
+	files, err := b.spooled()
+	if err != nil {
+		b.log.Warn(ctx, "list spooled audit logs", slog.Error(err))
+		return
+	}
+	var size int64
+	for _, file := range files {
+		size += file.size
+	}
+	for _, file := range files {
+		if size <= b.opts.SpoolMaxBytes {
+			break
+		}
+		if file.path == sending {
+			continue
+		}
+		b.log.Warn(ctx, "audit log spool is full, dropping oldest logs", slog.F("path", file.path), slog.F("count", file.count))
+		b.dropSpooled(file)
+		size -= file.size
+	}
+}
+
+func (b *webhookBackend) dropSpooled(file spoolFile) {
+	_ = os.Remove(file.path)
+	b.metrics.dropped.WithLabelValues("webhook").Add(float64(file.count))
+	b.metrics.pending.WithLabelValues("webhook").Sub(float64(file.count))
+}
+
+// Close sends the buffered logs, or spools them if that fails.
+func (b *webhookBackend) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.closeCh)
+	<-b.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	b.flush(ctx)
+	close(b.spoolerClose)
+	<-b.spoolerDone
+	return nil
+}
@@ -0,0 +1,294 @@
+package backends_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/enterprise/audit/audittest"
+	"github.com/coder/coder/enterprise/audit/backends"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWebhookBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Batch", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		server := newWebhookServer(t)
+		registry := prometheus.NewRegistry()
+		backend, err := backends.NewWebhook(slogtest.Make(t, nil), backends.NewMetrics(registry), backends.WebhookOptions{
+			URL:           server.URL,
+			Headers:       http.Header{"Authorization": []string{"Bearer secret"}},
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+			SpoolDir:      t.TempDir(),
+		})
+		require.NoError(t, err)
+		defer backend.(io.Closer).Close()
+
+		alogs := []string{}
+		for i := 0; i < 2; i++ {
+			alog := audittest.RandomLog()
+			alogs = append(alogs, alog.ID.String())
+			err = backend.Export(ctx, alog)
+			require.NoError(t, err)
+		}
+
+		// A full batch is sent right away.
+		require.Eventually(t, func() bool {
+			return len(server.batches()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, [][]string{alogs}, server.batches())
+		server.mu.Lock()
+		require.Equal(t, "Bearer secret", server.header.Get("Authorization"))
+		require.Equal(t, "application/json", server.header.Get("Content-Type"))
+		server.mu.Unlock()
+		require.Equal(t, 2.0, metricValue(t, registry, "coderd_audit_export_delivered_logs_total", "webhook"))
+		require.Equal(t, 0.0, metricValue(t, registry, "coderd_audit_export_pending_logs", "webhook"))
+	})
+
+	t.Run("FlushOnClose", func(t *testing.T) {
+		t.Parallel()
+
+		server := newWebhookServer(t)
+		backend, err := backends.NewWebhook(slogtest.Make(t, nil), backends.NewMetrics(prometheus.NewRegistry()), backends.WebhookOptions{
+			URL:           server.URL,
+			FlushInterval: time.Hour,
+			SpoolDir:      t.TempDir(),
+		})
+		require.NoError(t, err)
+
+		alog := audittest.RandomLog()
+		err = backend.Export(context.Background(), alog)
+		require.NoError(t, err)
+		err = backend.(io.Closer).Close()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{alog.ID.String()}}, server.batches())
+	})
+
+	t.Run("Spool", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		spoolDir := t.TempDir()
+		server := newWebhookServer(t)
+		server.fail.Store(true)
+		registry := prometheus.NewRegistry()
+		backend, err := backends.NewWebhook(slogtest.Make(t, nil), backends.NewMetrics(registry), backends.WebhookOptions{
+			URL:           server.URL,
+			FlushInterval: time.Hour,
+			SpoolDir:      spoolDir,
+		})
+		require.NoError(t, err)
+
+		alogs := []string{}
+		for i := 0; i < 3; i++ {
+			alog := audittest.RandomLog()
+			alogs = append(alogs, alog.ID.String())
+			err = backend.Export(ctx, alog)
+			require.NoError(t, err)
+		}
+		// The batch is retried, and spooled when closing.
+		err = backend.(io.Closer).Close()
+		require.NoError(t, err)
+		require.Equal(t, 3.0, metricValue(t, registry, "coderd_audit_export_errors_total", "webhook"))
+		require.Equal(t, 3.0, metricValue(t, registry, "coderd_audit_export_pending_logs", "webhook"))
+		entries, err := os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		// Spooled logs are sent once the server recovers, even after a
+		// restart.
+		server.fail.Store(false)
+		registry = prometheus.NewRegistry()
+		backend, err = backends.NewWebhook(slogtest.Make(t, nil), backends.NewMetrics(registry), backends.WebhookOptions{
+			URL:           server.URL,
+			FlushInterval: testutil.IntervalFast,
+			SpoolDir:      spoolDir,
+		})
+		require.NoError(t, err)
+		defer backend.(io.Closer).Close()
+		require.Eventually(t, func() bool {
+			return len(server.batches()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, [][]string{alogs}, server.batches())
+		require.Equal(t, 3.0, metricValue(t, registry, "coderd_audit_export_delivered_logs_total", "webhook"))
+		require.Equal(t, 0.0, metricValue(t, registry, "coderd_audit_export_pending_logs", "webhook"))
+		entries, err = os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		spoolDir := t.TempDir()
+		server := newWebhookServer(t)
+		release := server.hold()
+		registry := prometheus.NewRegistry()
+		backend, err := backends.NewWebhook(slogtest.Make(t, nil), backends.NewMetrics(registry), backends.WebhookOptions{
+			URL:           server.URL,
+			BatchSize:     1,
+			FlushInterval: time.Hour,
+			SpoolDir:      spoolDir,
+		})
+		require.NoError(t, err)
+		defer backend.(io.Closer).Close()
+
+		// The first log is sent right away, and the request hangs.
+		err = backend.Export(ctx, audittest.RandomLog())
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return server.requests.Load() == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// Logs that don't fit in the buffer are spooled in the background.
+		for i := 0; i < 10; i++ {
+			err = backend.Export(ctx, audittest.RandomLog())
+			require.NoError(t, err)
+		}
+		require.Eventually(t, func() bool {
+			entries, err := os.ReadDir(spoolDir)
+			return err == nil && len(entries) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// Every log is delivered once.
+		release()
+		require.Eventually(t, func() bool {
+			return metricValue(t, registry, "coderd_audit_export_delivered_logs_total", "webhook") == 11
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, 0.0, metricValue(t, registry, "coderd_audit_export_pending_logs", "webhook"))
+		entries, err := os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("SpoolFull", func(t *testing.T) {
+		t.Parallel()
+
+		spoolDir := t.TempDir()
+		server := newWebhookServer(t)
+		server.fail.Store(true)
+		registry := prometheus.NewRegistry()
+		backend, err := backends.NewWebhook(slogtest.Make(t, nil), backends.NewMetrics(registry), backends.WebhookOptions{
+			URL:           server.URL,
+			FlushInterval: time.Hour,
+			SpoolDir:      spoolDir,
+			SpoolMaxBytes: 1,
+		})
+		require.NoError(t, err)
+
+		err = backend.Export(context.Background(), audittest.RandomLog())
+		require.NoError(t, err)
+		err = backend.(io.Closer).Close()
+		require.NoError(t, err)
+		require.Equal(t, 1.0, metricValue(t, registry, "coderd_audit_export_dropped_logs_total", "webhook"))
+		require.Equal(t, 0.0, metricValue(t, registry, "coderd_audit_export_pending_logs", "webhook"))
+		entries, err := os.ReadDir(spoolDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
+
+type webhookServer struct {
+	*httptest.Server
+	fail     atomic.Bool
+	requests atomic.Int64
+
+	mu      sync.Mutex
+	gate    chan struct{}
+	header  http.Header
+	batched [][]string
+}
+
+// newWebhookServer records the IDs of the audit logs it receives, unless fail
+// is set.
+func newWebhookServer(t *testing.T) *webhookServer {
+	s := &webhookServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		gate := s.gate
+		s.mu.Unlock()
+		if gate != nil {
+			<-gate
+		}
+		if s.fail.Load() {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var alogs []map[string]any
+		err := json.NewDecoder(r.Body).Decode(&alogs)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ids := []string{}
+		for _, alog := range alogs {
+			ids = append(ids, alog["id"].(string))
+		}
+		s.mu.Lock()
+		s.header = r.Header.Clone()
+		s.batched = append(s.batched, ids)
+		s.mu.Unlock()
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// hold makes requests hang until the returned func is called.
+func (s *webhookServer) hold() (release func()) {
+	gate := make(chan struct{})
+	s.mu.Lock()
+	s.gate = gate
+	s.mu.Unlock()
+	return func() {
+		close(gate)
+	}
+}
+
+func (s *webhookServer) batches() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.batched...)
+}
+
+// metricValue returns the value of a counter or gauge of a backend.
+func metricValue(t *testing.T, registry *prometheus.Registry, name, backend string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() != "backend" || label.GetValue() != backend {
+					continue
+				}
+				if metric.GetCounter() != nil {
+					return metric.GetCounter().GetValue()
+				}
+				return metric.GetGauge().GetValue()
+			}
+		}
+	}
+	return 0
+}
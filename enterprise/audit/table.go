@@ -16,15 +16,21 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"GitSSHKey":               {codersdk.AuditActionCreate},
+	"Template":                {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":         {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                    {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceBuild":          {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                   {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                  {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":                 {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"Webhook":                 {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"OAuth2ProviderApp":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"OAuth2ProviderAppSecret": {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"CustomRole":              {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceAgent":          {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
+	"WorkspaceApp":            {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
 }
 
 type Action string
@@ -84,6 +90,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"failure_ttl":                      ActionTrack,
 		"inactivity_ttl":                   ActionTrack,
 		"locked_ttl":                       ActionTrack,
+		"prebuilt_workspaces":              ActionTrack,
+		"record_sessions":                  ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                    ActionTrack,
@@ -114,6 +122,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"last_seen_at":         ActionIgnore,
 		"deleted":              ActionTrack,
 		"quiet_hours_schedule": ActionTrack,
+		"is_service_account":   ActionTrack,
 	},
 	&database.Workspace{}: {
 		"id":                 ActionTrack,
@@ -129,6 +138,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"last_used_at":       ActionIgnore,
 		"locked_at":          ActionTrack,
 		"deleting_at":        ActionTrack,
+		"user_acl":           ActionTrack,
+		"group_acl":          ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                      ActionIgnore,
@@ -169,6 +180,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"ip_address":       ActionIgnore,
 		"scope":            ActionIgnore,
 		"token_name":       ActionIgnore,
+		"allow_list":       ActionIgnore,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -198,6 +210,93 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"deleted":             ActionIgnore,
 		"token_hashed_secret": ActionSecret,
 	},
+	&database.Webhook{}: {
+		"id":         ActionTrack,
+		"created_at": ActionIgnore,
+		"updated_at": ActionIgnore,
+		"name":       ActionTrack,
+		"url":        ActionTrack,
+		"secret":     ActionSecret,
+		"events":     ActionTrack,
+		"enabled":    ActionTrack,
+	},
+	&database.OAuth2ProviderApp{}: {
+		"id":           ActionTrack,
+		"created_at":   ActionIgnore,
+		"updated_at":   ActionIgnore,
+		"name":         ActionTrack,
+		"icon":         ActionTrack,
+		"callback_url": ActionTrack,
+	},
+	&database.OAuth2ProviderAppSecret{}: {
+		"id":             ActionIgnore,
+		"created_at":     ActionIgnore,
+		"last_used_at":   ActionIgnore,
+		"hashed_secret":  ActionIgnore,
+		"display_secret": ActionIgnore,
+		"app_id":         ActionIgnore,
+	},
+	&database.CustomRole{}: {
+		"id":              ActionTrack,
+		"created_at":      ActionIgnore,
+		"updated_at":      ActionIgnore,
+		"name":            ActionTrack,
+		"display_name":    ActionTrack,
+		"organization_id": ActionTrack,
+		"permissions":     ActionTrack,
+	},
+	&database.WorkspaceAgent{}: {
+		"id":                              ActionTrack,
+		"created_at":                      ActionIgnore,
+		"updated_at":                      ActionIgnore,
+		"name":                            ActionTrack,
+		"first_connected_at":              ActionIgnore,
+		"last_connected_at":               ActionIgnore,
+		"disconnected_at":                 ActionIgnore,
+		"resource_id":                     ActionIgnore,
+		"auth_token":                      ActionSecret,
+		"auth_instance_id":                ActionIgnore,
+		"architecture":                    ActionIgnore,
+		"environment_variables":           ActionIgnore,
+		"operating_system":                ActionIgnore,
+		"startup_script":                  ActionIgnore,
+		"instance_metadata":               ActionIgnore,
+		"resource_metadata":               ActionIgnore,
+		"directory":                       ActionIgnore,
+		"version":                         ActionIgnore,
+		"last_connected_replica_id":       ActionIgnore,
+		"connection_timeout_seconds":      ActionIgnore,
+		"troubleshooting_url":             ActionIgnore,
+		"motd_file":                       ActionIgnore,
+		"lifecycle_state":                 ActionIgnore,
+		"startup_script_timeout_seconds":  ActionIgnore,
+		"expanded_directory":              ActionIgnore,
+		"shutdown_script":                 ActionIgnore,
+		"shutdown_script_timeout_seconds": ActionIgnore,
+		"startup_logs_length":             ActionIgnore,
+		"startup_logs_overflowed":         ActionIgnore,
+		"subsystem":                       ActionIgnore,
+		"startup_script_behavior":         ActionIgnore,
+		"started_at":                      ActionIgnore,
+		"ready_at":                        ActionIgnore,
+	},
+	&database.WorkspaceApp{}: {
+		"id":                    ActionTrack,
+		"created_at":            ActionIgnore,
+		"agent_id":              ActionIgnore,
+		"display_name":          ActionTrack,
+		"icon":                  ActionIgnore,
+		"command":               ActionIgnore,
+		"url":                   ActionIgnore,
+		"healthcheck_url":       ActionIgnore,
+		"healthcheck_interval":  ActionIgnore,
+		"healthcheck_threshold": ActionIgnore,
+		"health":                ActionIgnore,
+		"subdomain":             ActionIgnore,
+		"sharing_level":         ActionIgnore,
+		"slug":                  ActionTrack,
+		"external":              ActionIgnore,
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
@@ -4,10 +4,16 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"errors"
 	"io"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
@@ -49,9 +55,24 @@ func (r *RootCmd) server() *clibase.Cmd {
 			}
 		}
 		options.DERPServer.SetMeshKey(meshKey)
+		exportBackends, err := auditExportBackends(options)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("configure audit log export: %w", err)
+		}
+		closeExportBackends := func() error {
+			var errs []error
+			for _, backend := range exportBackends {
+				if closer, ok := backend.(io.Closer); ok {
+					errs = append(errs, closer.Close())
+				}
+			}
+			return errors.Join(errs...)
+		}
 		options.Auditor = audit.NewAuditor(audit.DefaultFilter,
-			backends.NewPostgres(options.Database, true),
-			backends.NewSlog(options.Logger),
+			append([]audit.Backend{
+				backends.NewPostgres(options.Database, true),
+				backends.NewSlog(options.Logger),
+			}, exportBackends...)...,
 		)
 
 		options.TrialGenerator = trialer.New(options.Database, "https://v2-licensor.coder.com/trial", coderd.Keys)
@@ -70,9 +91,104 @@ func (r *RootCmd) server() *clibase.Cmd {
 
 		api, err := coderd.New(ctx, o)
 		if err != nil {
+			_ = closeExportBackends()
 			return nil, nil, err
 		}
-		return api.AGPL, api, nil
+		// Close the API first, so audit logs of requests that are still in
+		// flight are exported.
+		return api.AGPL, closeFunc(func() error {
+			return errors.Join(api.Close(), closeExportBackends())
+		}), nil
 	})
 	return cmd
 }
+
+type closeFunc func() error
+
+func (c closeFunc) Close() error {
+	return c()
+}
+
+// auditExportBackends returns the audit backends that export to external
+// systems, as configured by the audit log export options.
+func auditExportBackends(options *agplcoderd.Options) (exportBackends []audit.Backend, err error) {
+	cfg := options.DeploymentValues.AuditLogExport
+	if cfg.SyslogAddress == "" && cfg.WebhookURL.String() == "" && cfg.FilePath == "" {
+		return nil, nil
+	}
+	metrics := backends.NewMetrics(options.PrometheusRegistry)
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, backend := range exportBackends {
+			if closer, ok := backend.(io.Closer); ok {
+				_ = closer.Close()
+			}
+		}
+	}()
+
+	if cfg.SyslogAddress != "" {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+		if cfg.SyslogTLSCAFile != "" {
+			data, err := os.ReadFile(cfg.SyslogTLSCAFile.String())
+			if err != nil {
+				return nil, xerrors.Errorf("read %q: %w", cfg.SyslogTLSCAFile.String(), err)
+			}
+			caPool := x509.NewCertPool()
+			if !caPool.AppendCertsFromPEM(data) {
+				return nil, xerrors.Errorf("failed to parse CA certificate in audit-syslog-tls-ca-file")
+			}
+			tlsConfig.RootCAs = caPool
+		}
+		backend, err := backends.NewSyslog(options.Logger.Named("audit_syslog"), metrics, backends.SyslogOptions{
+			Address:   cfg.SyslogAddress.String(),
+			TLSConfig: tlsConfig,
+		})
+		if err != nil {
+			return nil, err
+		}
+		exportBackends = append(exportBackends, backend)
+	}
+	if cfg.WebhookURL.String() != "" {
+		headers := http.Header{}
+		for _, header := range cfg.WebhookHeaders {
+			name, value, ok := strings.Cut(header, ":")
+			if !ok {
+				return nil, xerrors.New("audit webhook headers must be in the \"Name: value\" format")
+			}
+			headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		spoolDir := cfg.WebhookSpoolDir.String()
+		if spoolDir == "" {
+			spoolDir = filepath.Join(options.DeploymentValues.CacheDir.String(), "audit-webhook-spool")
+		}
+		backend, err := backends.NewWebhook(options.Logger.Named("audit_webhook"), metrics, backends.WebhookOptions{
+			URL:           cfg.WebhookURL.String(),
+			Headers:       headers,
+			BatchSize:     int(cfg.WebhookBatchSize.Value()),
+			FlushInterval: cfg.WebhookFlushInterval.Value(),
+			SpoolDir:      spoolDir,
+			SpoolMaxBytes: cfg.WebhookSpoolMaxMB.Value() << 20,
+		})
+		if err != nil {
+			return nil, err
+		}
+		exportBackends = append(exportBackends, backend)
+	}
+	if cfg.FilePath != "" {
+		backend, err := backends.NewFile(metrics, backends.FileOptions{
+			Path:       cfg.FilePath.String(),
+			MaxSizeMB:  int(cfg.FileMaxSizeMB.Value()),
+			MaxAge:     cfg.FileMaxAge.Value(),
+			MaxBackups: int(cfg.FileMaxBackups.Value()),
+		})
+		if err != nil {
+			return nil, err
+		}
+		exportBackends = append(exportBackends, backend)
+	}
+	return exportBackends, nil
+}
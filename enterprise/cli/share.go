@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/cli"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) share() *clibase.Cmd {
+	var (
+		users        []string
+		groups       []string
+		removeUsers  []string
+		removeGroups []string
+	)
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]shareTableRow{}, nil),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "share <workspace>",
+		Short: "Share a workspace with other users and groups",
+		Long: "Users and groups with the \"connect\" role can use SSH, port forwarding and apps of the workspace. " +
+			"The \"view\" role only allows viewing it. " +
+			"Without flags, the users and groups the workspace is shared with are listed.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			workspace, err := agpl.NamedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			req := codersdk.UpdateWorkspaceACL{
+				UserPerms:  map[string]codersdk.WorkspaceRole{},
+				GroupPerms: map[string]codersdk.WorkspaceRole{},
+			}
+			for _, arg := range users {
+				name, role, err := parseShareArg(arg)
+				if err != nil {
+					return xerrors.Errorf("parse user %q: %w", arg, err)
+				}
+				user, err := client.User(ctx, name)
+				if err != nil {
+					return xerrors.Errorf("get user %q: %w", name, err)
+				}
+				req.UserPerms[user.ID.String()] = role
+			}
+			for _, name := range removeUsers {
+				user, err := client.User(ctx, name)
+				if err != nil {
+					return xerrors.Errorf("get user %q: %w", name, err)
+				}
+				req.UserPerms[user.ID.String()] = codersdk.WorkspaceRoleDeleted
+			}
+			for _, arg := range groups {
+				name, role, err := parseShareArg(arg)
+				if err != nil {
+					return xerrors.Errorf("parse group %q: %w", arg, err)
+				}
+				group, err := client.GroupByOrgAndName(ctx, workspace.OrganizationID, name)
+				if err != nil {
+					return xerrors.Errorf("get group %q: %w", name, err)
+				}
+				req.GroupPerms[group.ID.String()] = role
+			}
+			for _, name := range removeGroups {
+				group, err := client.GroupByOrgAndName(ctx, workspace.OrganizationID, name)
+				if err != nil {
+					return xerrors.Errorf("get group %q: %w", name, err)
+				}
+				req.GroupPerms[group.ID.String()] = codersdk.WorkspaceRoleDeleted
+			}
+
+			if len(req.UserPerms) > 0 || len(req.GroupPerms) > 0 {
+				err = client.UpdateWorkspaceACL(ctx, workspace.ID, req)
+				if err != nil {
+					return xerrors.Errorf("update workspace ACL: %w", err)
+				}
+			}
+
+			acl, err := client.WorkspaceACL(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get workspace ACL: %w", err)
+			}
+
+			if len(acl.Users) == 0 && len(acl.Groups) == 0 {
+				_, _ = fmt.Fprintf(inv.Stderr, "%s Workspace %s is not shared with anyone.\n", agpl.Caret, cliui.DefaultStyles.Keyword.Render(workspace.Name))
+				return nil
+			}
+
+			out, err := formatter.Format(ctx, workspaceACLToRows(acl))
+			if err != nil {
+				return xerrors.Errorf("display workspace ACL: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "user",
+			Description: "Share the workspace with a user, given as <username>[:<role>]. The role is \"connect\" (default) or \"view\".",
+			Value:       clibase.StringArrayOf(&users),
+		},
+		{
+			Flag:        "group",
+			Description: "Share the workspace with a group, given as <name>[:<role>]. The role is \"connect\" (default) or \"view\".",
+			Value:       clibase.StringArrayOf(&groups),
+		},
+		{
+			Flag:        "remove-user",
+			Description: "Stop sharing the workspace with a user.",
+			Value:       clibase.StringArrayOf(&removeUsers),
+		},
+		{
+			Flag:        "remove-group",
+			Description: "Stop sharing the workspace with a group.",
+			Value:       clibase.StringArrayOf(&removeGroups),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+
+	return cmd
+}
+
+// parseShareArg splits a "name[:role]" argument. The role defaults to
+// connect.
+func parseShareArg(arg string) (string, codersdk.WorkspaceRole, error) {
+	name, role, ok := strings.Cut(arg, ":")
+	if !ok {
+		return name, codersdk.WorkspaceRoleConnect, nil
+	}
+	switch codersdk.WorkspaceRole(role) {
+	case codersdk.WorkspaceRoleConnect, codersdk.WorkspaceRoleView:
+		return name, codersdk.WorkspaceRole(role), nil
+	}
+	return "", "", xerrors.Errorf("role %q must be %q or %q", role, codersdk.WorkspaceRoleConnect, codersdk.WorkspaceRoleView)
+}
+
+type shareTableRow struct {
+	Name string                 `json:"name" table:"name,default_sort"`
+	Type string                 `json:"type" table:"type"`
+	Role codersdk.WorkspaceRole `json:"role" table:"role"`
+}
+
+func workspaceACLToRows(acl codersdk.WorkspaceACL) []shareTableRow {
+	rows := make([]shareTableRow, 0, len(acl.Users)+len(acl.Groups))
+	for _, user := range acl.Users {
+		rows = append(rows, shareTableRow{
+			Name: user.Username,
+			Type: "user",
+			Role: user.Role,
+		})
+	}
+	for _, group := range acl.Groups {
+		rows = append(rows, shareTableRow{
+			Name: group.Name,
+			Type: "group",
+			Role: group.Role,
+		})
+	}
+
+	return rows
+}
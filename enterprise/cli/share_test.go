@@ -0,0 +1,109 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/enterprise/coderd/license"
+	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestShare(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, codersdk.Workspace) {
+		client, admin := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, admin.OrganizationID, template.ID)
+		return client, admin, workspace
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client, admin, workspace := setup(t)
+		_, user1 := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		_, user2 := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "alpha",
+		})
+		require.NoError(t, err)
+
+		inv, conf := newCLI(
+			t,
+			"share", workspace.Name,
+			"--user", user1.Username,
+			"--user", user2.Username+":view",
+			"--group", group.Name+":connect",
+		)
+		pty := ptytest.New(t)
+		inv.Stdout = pty.Output()
+		clitest.SetupConfig(t, client, conf)
+
+		err = inv.Run()
+		require.NoError(t, err)
+		pty.ExpectMatch(group.Name)
+
+		acl, err := client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 2)
+		require.Contains(t, acl.Users, codersdk.WorkspaceUser{User: user1, Role: codersdk.WorkspaceRoleConnect})
+		require.Contains(t, acl.Users, codersdk.WorkspaceUser{User: user2, Role: codersdk.WorkspaceRoleView})
+		require.Len(t, acl.Groups, 1)
+		require.Equal(t, codersdk.WorkspaceRoleConnect, acl.Groups[0].Role)
+
+		inv, conf = newCLI(
+			t,
+			"share", workspace.Name,
+			"--remove-user", user1.Username,
+			"--remove-group", group.Name,
+		)
+		clitest.SetupConfig(t, client, conf)
+
+		err = inv.Run()
+		require.NoError(t, err)
+
+		acl, err = client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, user2.ID, acl.Users[0].ID)
+		require.Empty(t, acl.Groups)
+	})
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		t.Parallel()
+
+		client, admin, workspace := setup(t)
+		_, user := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+		inv, conf := newCLI(
+			t,
+			"share", workspace.Name,
+			"--user", user.Username+":admin",
+		)
+		clitest.SetupConfig(t, client, conf)
+
+		err := inv.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `role "admin" must be "connect" or "view"`)
+	})
+}
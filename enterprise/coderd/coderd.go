@@ -159,6 +159,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Post("/issue-signed-app-token", api.workspaceProxyIssueSignedAppToken)
 				r.Post("/register", api.workspaceProxyRegister)
 				r.Post("/goingaway", api.workspaceProxyGoingAway)
+				r.Post("/report-connection", api.workspaceProxyReportConnection)
 			})
 			r.Route("/{workspaceproxy}", func(r chi.Router) {
 				r.Use(
@@ -205,6 +206,15 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Get("/", api.templateACL)
 			r.Patch("/", api.patchTemplateACL)
 		})
+		r.Route("/workspaces/{workspace}/acl", func(r chi.Router) {
+			r.Use(
+				api.templateRBACEnabledMW,
+				apiKeyMiddleware,
+				httpmw.ExtractWorkspaceParam(api.Database),
+			)
+			r.Get("/", api.workspaceACL)
+			r.Patch("/", api.patchWorkspaceACL)
+		})
 		r.Route("/groups/{group}", func(r chi.Router) {
 			r.Use(
 				api.templateRBACEnabledMW,
@@ -267,6 +277,20 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Get("/{id}", api.scimGetUser)
 				r.Patch("/{id}", api.scimPatchUser)
 			})
+			r.Route("/Groups", func(r chi.Router) {
+				r.Use(api.templateRBACEnabledMW)
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Put("/{id}", api.scimPutGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
+			r.Get("/ServiceProviderConfig", api.scimGetServiceProviderConfig)
+			r.Get("/Schemas", api.scimGetSchemas)
+			r.Get("/Schemas/{id}", api.scimGetSchema)
+			r.Get("/ResourceTypes", api.scimGetResourceTypes)
+			r.Get("/ResourceTypes/{id}", api.scimGetResourceType)
 		})
 	}
 
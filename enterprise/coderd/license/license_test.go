@@ -280,6 +280,32 @@ func TestEntitlements(t *testing.T) {
 		require.True(t, entitlements.HasLicense)
 		require.Contains(t, entitlements.Warnings, "Your deployment has 2 active users but is only licensed for 1.")
 	})
+	t.Run("ServiceAccountsNotCounted", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		db.InsertUser(context.Background(), database.InsertUserParams{
+			Username:  "test1",
+			LoginType: database.LoginTypePassword,
+		})
+		db.InsertUser(context.Background(), database.InsertUserParams{
+			Username:         "bot",
+			LoginType:        database.LoginTypeNone,
+			IsServiceAccount: true,
+		})
+		db.InsertLicense(context.Background(), database.InsertLicenseParams{
+			JWT: coderdenttest.GenerateLicense(t, coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureUserLimit: 1,
+				},
+			}),
+			Exp: time.Now().Add(time.Hour),
+		})
+		entitlements, err := license.Entitlements(context.Background(), db, slog.Logger{}, 1, 1, coderdenttest.Keys, empty)
+		require.NoError(t, err)
+		require.True(t, entitlements.HasLicense)
+		require.NotContains(t, entitlements.Warnings, "Your deployment has 2 active users but is only licensed for 1.")
+		require.EqualValues(t, 1, *entitlements.Features[codersdk.FeatureUserLimit].Actual)
+	})
 	t.Run("MaximizeUserLimit", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
@@ -225,6 +225,8 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		Pubsub:                      api.Pubsub,
 		Provisioners:                daemon.Provisioners,
 		Telemetry:                   api.Telemetry,
+		Webhooks:                    api.AGPL.Webhooks,
+		Notifications:               api.AGPL.Notifications,
 		Auditor:                     &api.AGPL.Auditor,
 		TemplateScheduleStore:       api.AGPL.TemplateScheduleStore,
 		UserQuietHoursScheduleStore: api.AGPL.UserQuietHoursScheduleStore,
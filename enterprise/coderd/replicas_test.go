@@ -5,12 +5,15 @@ import (
 	"crypto/tls"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbtestutil"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/enterprise/coderd/coderdenttest"
@@ -147,4 +150,63 @@ func TestReplicas(t *testing.T) {
 			require.Empty(t, replica.Error)
 		}
 	})
+	t.Run("ConnectionAuditAcrossReplicas", func(t *testing.T) {
+		t.Parallel()
+		db, pubsub := dbtestutil.NewDB(t)
+		auditor := audit.NewMock()
+		firstClient, firstUser := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+				Database:                 db,
+				Pubsub:                   pubsub,
+				Auditor:                  auditor,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureHighAvailability: 1,
+					codersdk.FeatureAuditLog:         1,
+				},
+			},
+		})
+
+		secondClient, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+			DontAddLicense:   true,
+			DontAddFirstUser: true,
+		})
+		secondClient.SetSessionToken(firstClient.SessionToken())
+
+		// The agent reports connections to the first replica, while the
+		// client coordinates through the second one.
+		_, agent := setupWorkspaceAgent(t, firstClient, firstUser, 0)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		conn, err := secondClient.DialWorkspaceAgent(ctx, agent.ID, &codersdk.DialWorkspaceAgentOptions{
+			BlockEndpoints: true,
+			Logger:         slogtest.Make(t, nil).Named("client").Leveled(slog.LevelDebug),
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		_, err = session.CombinedOutput("echo test")
+		require.NoError(t, err)
+		_ = session.Close()
+		_ = sshClient.Close()
+
+		// The first replica can't attribute the connection to a user, but
+		// it must still audit it.
+		require.Eventually(t, func() bool {
+			for _, alog := range auditor.AuditLogs() {
+				if alog.ResourceType == database.ResourceTypeWorkspaceAgent && alog.UserID == uuid.Nil {
+					return true
+				}
+			}
+			return false
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
 }
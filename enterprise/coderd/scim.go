@@ -1,6 +1,7 @@
 package coderd
 
 import (
+	"context"
 	"crypto/subtle"
 	"database/sql"
 	"encoding/json"
@@ -42,6 +43,23 @@ func (api *API) scimVerifyAuthHeader(r *http.Request) bool {
 	return len(api.SCIMAPIKey) != 0 && subtle.ConstantTimeCompare(hdr, api.SCIMAPIKey) == 1
 }
 
+// scimOrganizationID returns the organization SCIM users and groups are
+// provisioned in, or uuid.Nil if there is no organization yet.
+func (api *API) scimOrganizationID(ctx context.Context) (uuid.UUID, error) {
+	//nolint:gocritic // needed for SCIM
+	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get organizations: %w", err)
+	}
+	if len(organizations) == 0 {
+		return uuid.Nil, nil
+	}
+	// Use the first organization. Once multi-organization support is added,
+	// we should enable a configuration map of users and groups to
+	// organizations.
+	return organizations[0].ID, nil
+}
+
 // scimGetUsers intentionally always returns no users. This is done to always force
 // Okta to try and create each user individually, this way we don't need to
 // implement fetching users twice.
@@ -185,21 +203,12 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		sUser.UserName = httpapi.UsernameFrom(sUser.UserName)
 	}
 
-	var organizationID uuid.UUID
-	//nolint:gocritic
-	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	organizationID, err := api.scimOrganizationID(ctx)
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
 
-	if len(organizations) > 0 {
-		// Add the user to the first organization. Once multi-organization
-		// support is added, we should enable a configuration map of user
-		// email to organization.
-		organizationID = organizations[0].ID
-	}
-
 	//nolint:gocritic // needed for SCIM
 	user, _, err = api.AGPL.CreateUser(dbauthz.AsSystemRestricted(ctx), api.Database, agpl.CreateUserRequest{
 		CreateUserRequest: codersdk.CreateUserRequest{
@@ -213,6 +222,7 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
+	api.AGPL.Webhooks.User(ctx, database.WebhookEventUserCreated, user)
 
 	sUser.ID = user.ID.String()
 	sUser.UserName = user.Username
@@ -269,7 +279,7 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	//nolint:gocritic // needed for SCIM
-	_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
+	updatedUser, err := api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
 		ID:        dbUser.ID,
 		Status:    status,
 		UpdatedAt: database.Now(),
@@ -278,6 +288,365 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
+	if status == database.UserStatusSuspended && dbUser.Status != database.UserStatusSuspended {
+		api.AGPL.Webhooks.User(ctx, database.WebhookEventUserSuspended, updatedUser)
+	}
 
 	httpapi.Write(ctx, rw, http.StatusOK, sUser)
 }
+
+const (
+	scimUserSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimGroupSchema        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaSchema       = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	scimResourceTypeSchema = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	scimProviderSchema     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimListSchema         = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+)
+
+// scimWriteError writes err as a SCIM error with the status of the
+// *spec.Error it wraps, or as an internal error. handlerutil.WriteError only
+// checks the error err directly wraps.
+func scimWriteError(rw http.ResponseWriter, err error) {
+	scimErr := spec.ErrInternal
+	_ = xerrors.As(err, &scimErr)
+	_ = handlerutil.WriteError(rw, scimDetailedError{detail: err.Error(), cause: scimErr})
+}
+
+type scimDetailedError struct {
+	detail string
+	cause  *spec.Error
+}
+
+func (e scimDetailedError) Error() string { return e.detail }
+func (e scimDetailedError) Unwrap() error { return e.cause }
+
+func scimUnauthorized(rw http.ResponseWriter) {
+	scimWriteError(rw, xerrors.Errorf("invalid authorization: %w", &spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"}))
+}
+
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+type scimListResponse[T any] struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []T      `json:"Resources"`
+}
+
+func newSCIMListResponse[T any](resources []T) scimListResponse[T] {
+	return scimListResponse[T]{
+		Schemas:      []string{scimListSchema},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+type scimSupported struct {
+	Supported bool `json:"supported"`
+}
+
+type scimServiceProviderConfig struct {
+	Schemas        []string      `json:"schemas"`
+	Patch          scimSupported `json:"patch"`
+	ChangePassword scimSupported `json:"changePassword"`
+	Sort           scimSupported `json:"sort"`
+	ETag           scimSupported `json:"etag"`
+	Bulk           struct {
+		Supported      bool `json:"supported"`
+		MaxOperations  int  `json:"maxOperations"`
+		MaxPayloadSize int  `json:"maxPayloadSize"`
+	} `json:"bulk"`
+	Filter struct {
+		Supported  bool `json:"supported"`
+		MaxResults int  `json:"maxResults"`
+	} `json:"filter"`
+	AuthenticationSchemes []scimAuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  scimMeta                   `json:"meta"`
+}
+
+type scimAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+type scimResourceType struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoint    string   `json:"endpoint"`
+	Description string   `json:"description"`
+	Schema      string   `json:"schema"`
+	Meta        scimMeta `json:"meta"`
+}
+
+type scimSchema struct {
+	Schemas     []string              `json:"schemas"`
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Attributes  []scimSchemaAttribute `json:"attributes"`
+	Meta        scimMeta              `json:"meta"`
+}
+
+type scimSchemaAttribute struct {
+	Name          string                `json:"name"`
+	Type          string                `json:"type"`
+	MultiValued   bool                  `json:"multiValued"`
+	Description   string                `json:"description"`
+	Required      bool                  `json:"required"`
+	CaseExact     bool                  `json:"caseExact"`
+	Mutability    string                `json:"mutability"`
+	Returned      string                `json:"returned"`
+	Uniqueness    string                `json:"uniqueness"`
+	SubAttributes []scimSchemaAttribute `json:"subAttributes,omitempty"`
+}
+
+// scimStringAttribute returns a single valued string attribute with the
+// defaults of RFC 7643.
+func scimStringAttribute(name, description string) scimSchemaAttribute {
+	return scimSchemaAttribute{
+		Name:        name,
+		Type:        "string",
+		Description: description,
+		Mutability:  "readWrite",
+		Returned:    "default",
+		Uniqueness:  "none",
+	}
+}
+
+// scimSchemas are the attributes of users and groups that are supported.
+// Other attributes are accepted and ignored.
+func scimSchemas() []scimSchema {
+	userName := scimStringAttribute("userName", "Unique identifier for the user. Used as the Coder username, with invalid characters removed.")
+	userName.Required = true
+	userName.Uniqueness = "server"
+	givenName := scimStringAttribute("givenName", "The given name of the user.")
+	familyName := scimStringAttribute("familyName", "The family name of the user.")
+	emailValue := scimStringAttribute("value", "Email address of the user.")
+	emailType := scimStringAttribute("type", "The type of the email address.")
+	emailDisplay := scimStringAttribute("display", "A human-readable name of the email address.")
+	displayName := scimStringAttribute("displayName", "The name of the group. Used as the Coder group name.")
+	displayName.Required = true
+	displayName.Uniqueness = "server"
+	memberValue := scimStringAttribute("value", "The ID of the member user.")
+	memberValue.Mutability = "immutable"
+	memberDisplay := scimStringAttribute("display", "The username of the member user.")
+	memberDisplay.Mutability = "readOnly"
+
+	return []scimSchema{
+		{
+			Schemas:     []string{scimSchemaSchema},
+			ID:          scimUserSchema,
+			Name:        "User",
+			Description: "User Account",
+			Attributes: []scimSchemaAttribute{
+				userName,
+				{
+					Name:          "name",
+					Type:          "complex",
+					Description:   "The components of the user's name.",
+					Mutability:    "readWrite",
+					Returned:      "default",
+					Uniqueness:    "none",
+					SubAttributes: []scimSchemaAttribute{givenName, familyName},
+				},
+				{
+					Name:        "emails",
+					Type:        "complex",
+					MultiValued: true,
+					Description: "Email addresses of the user. The primary email address is used as the Coder email.",
+					Required:    true,
+					Mutability:  "readWrite",
+					Returned:    "default",
+					Uniqueness:  "none",
+					SubAttributes: []scimSchemaAttribute{
+						emailValue,
+						emailType,
+						emailDisplay,
+						{
+							Name:        "primary",
+							Type:        "boolean",
+							Description: "Whether this is the primary email address of the user.",
+							Mutability:  "readWrite",
+							Returned:    "default",
+							Uniqueness:  "none",
+						},
+					},
+				},
+				{
+					Name:        "active",
+					Type:        "boolean",
+					Description: "Whether the user is active. Inactive users are suspended.",
+					Mutability:  "readWrite",
+					Returned:    "default",
+					Uniqueness:  "none",
+				},
+			},
+			Meta: scimMeta{ResourceType: "Schema", Location: "/scim/v2/Schemas/" + scimUserSchema},
+		},
+		{
+			Schemas:     []string{scimSchemaSchema},
+			ID:          scimGroupSchema,
+			Name:        "Group",
+			Description: "Group",
+			Attributes: []scimSchemaAttribute{
+				displayName,
+				{
+					Name:          "members",
+					Type:          "complex",
+					MultiValued:   true,
+					Description:   "The members of the group.",
+					Mutability:    "readWrite",
+					Returned:      "default",
+					Uniqueness:    "none",
+					SubAttributes: []scimSchemaAttribute{memberValue, memberDisplay},
+				},
+			},
+			Meta: scimMeta{ResourceType: "Schema", Location: "/scim/v2/Schemas/" + scimGroupSchema},
+		},
+	}
+}
+
+func scimResourceTypes() []scimResourceType {
+	return []scimResourceType{
+		{
+			Schemas:     []string{scimResourceTypeSchema},
+			ID:          "User",
+			Name:        "User",
+			Endpoint:    "/Users",
+			Description: "User Account",
+			Schema:      scimUserSchema,
+			Meta:        scimMeta{ResourceType: "ResourceType", Location: "/scim/v2/ResourceTypes/User"},
+		},
+		{
+			Schemas:     []string{scimResourceTypeSchema},
+			ID:          "Group",
+			Name:        "Group",
+			Endpoint:    "/Groups",
+			Description: "Group",
+			Schema:      scimGroupSchema,
+			Meta:        scimMeta{ResourceType: "ResourceType", Location: "/scim/v2/ResourceTypes/Group"},
+		},
+	}
+}
+
+// scimGetServiceProviderConfig lets identity providers discover the
+// supported SCIM features.
+//
+// @Summary SCIM 2.0: Get service provider config
+// @ID scim-get-service-provider-config
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (api *API) scimGetServiceProviderConfig(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	config := scimServiceProviderConfig{
+		Schemas: []string{scimProviderSchema},
+		Patch:   scimSupported{Supported: true},
+		AuthenticationSchemes: []scimAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "SCIM API key",
+			Description: "The SCIM API key of the deployment, sent as the Authorization header.",
+			Primary:     true,
+		}},
+		Meta: scimMeta{ResourceType: "ServiceProviderConfig", Location: "/scim/v2/ServiceProviderConfig"},
+	}
+	config.Filter.Supported = true
+	config.Filter.MaxResults = scimMaxResults
+	httpapi.Write(r.Context(), rw, http.StatusOK, config)
+}
+
+// @Summary SCIM 2.0: Get schemas
+// @ID scim-get-schemas
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/Schemas [get]
+func (api *API) scimGetSchemas(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, newSCIMListResponse(scimSchemas()))
+}
+
+// @Summary SCIM 2.0: Get schema by ID
+// @ID scim-get-schema-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Schema URN"
+// @Success 200
+// @Router /scim/v2/Schemas/{id} [get]
+func (api *API) scimGetSchema(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	for _, schema := range scimSchemas() {
+		if schema.ID == id {
+			httpapi.Write(r.Context(), rw, http.StatusOK, schema)
+			return
+		}
+	}
+	scimWriteError(rw, xerrors.Errorf("schema %q not found: %w", id, spec.ErrNotFound))
+}
+
+// @Summary SCIM 2.0: Get resource types
+// @ID scim-get-resource-types
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/ResourceTypes [get]
+func (api *API) scimGetResourceTypes(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, newSCIMListResponse(scimResourceTypes()))
+}
+
+// @Summary SCIM 2.0: Get resource type by ID
+// @ID scim-get-resource-type-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Resource type"
+// @Success 200
+// @Router /scim/v2/ResourceTypes/{id} [get]
+func (api *API) scimGetResourceType(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	for _, resourceType := range scimResourceTypes() {
+		if resourceType.ID == id {
+			httpapi.Write(r.Context(), rw, http.StatusOK, resourceType)
+			return
+		}
+	}
+	scimWriteError(rw, xerrors.Errorf("resource type %q not found: %w", id, spec.ErrNotFound))
+}
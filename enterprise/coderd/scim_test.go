@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/cryptorand"
 	"github.com/coder/coder/enterprise/coderd"
@@ -285,4 +288,178 @@ func TestScim(t *testing.T) {
 			assert.Equal(t, codersdk.UserStatusSuspended, userRes.Users[0].Status)
 		})
 	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+
+		setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, []byte) {
+			scimAPIKey := []byte("hi")
+			client, first := coderdenttest.New(t, &coderdenttest.Options{
+				SCIMAPIKey: scimAPIKey,
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					AccountID: "coolin",
+					Features: license.Features{
+						codersdk.FeatureSCIM:         1,
+						codersdk.FeatureTemplateRBAC: 1,
+					},
+				},
+			})
+			return client, first, scimAPIKey
+		}
+
+		request := func(t *testing.T, client *codersdk.Client, key []byte, method, path string, body interface{}, status int, out interface{}) {
+			ctx := testutil.Context(t, testutil.WaitLong)
+			res, err := client.Request(ctx, method, path, body, setScimAuth(key))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, status, res.StatusCode)
+			if out != nil {
+				require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+			}
+		}
+
+		t.Run("noAuth", func(t *testing.T) {
+			t.Parallel()
+			client, _, _ := setup(t)
+
+			request(t, client, []byte("bye"), "GET", "/scim/v2/Groups", nil, http.StatusUnauthorized, nil)
+			request(t, client, []byte("bye"), "POST", "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "eng"}, http.StatusUnauthorized, nil)
+		})
+
+		t.Run("OK", func(t *testing.T) {
+			t.Parallel()
+			client, first, key := setup(t)
+			_, alice := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+			_, bob := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+			var group coderd.SCIMGroup
+			request(t, client, key, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+				DisplayName: "eng",
+				Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}},
+			}, http.StatusCreated, &group)
+			require.Equal(t, "eng", group.DisplayName)
+			require.Equal(t, []coderd.SCIMGroupMember{{Value: alice.ID.String(), Display: alice.Username}}, group.Members)
+
+			// The group exists in Coder.
+			ctx := testutil.Context(t, testutil.WaitLong)
+			cGroup, err := client.GroupByOrgAndName(ctx, first.OrganizationID, "eng")
+			require.NoError(t, err)
+			require.Equal(t, group.ID, cGroup.ID.String())
+			require.Len(t, cGroup.Members, 1)
+
+			// Creating it again conflicts.
+			request(t, client, key, "POST", "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "eng"}, http.StatusConflict, nil)
+
+			var list struct {
+				TotalResults int                `json:"totalResults"`
+				Resources    []coderd.SCIMGroup `json:"Resources"`
+			}
+			request(t, client, key, "GET", "/scim/v2/Groups?filter="+url.QueryEscape(`displayName eq "ENG"`), nil, http.StatusOK, &list)
+			require.Equal(t, 1, list.TotalResults)
+			require.Equal(t, group.ID, list.Resources[0].ID)
+			request(t, client, key, "GET", "/scim/v2/Groups?filter="+url.QueryEscape(`displayName eq "ops"`), nil, http.StatusOK, &list)
+			require.Equal(t, 0, list.TotalResults)
+			request(t, client, key, "GET", "/scim/v2/Groups?filter="+url.QueryEscape(`displayName co "e"`), nil, http.StatusBadRequest, nil)
+
+			// Add bob, remove alice and rename the group.
+			request(t, client, key, "PATCH", "/scim/v2/Groups/"+group.ID, coderd.SCIMPatchRequest{
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "add", Path: "members", Value: json.RawMessage(fmt.Sprintf(`[{"value":%q}]`, bob.ID))},
+					{Op: "remove", Path: fmt.Sprintf(`members[value eq %q]`, alice.ID)},
+					{Op: "replace", Value: json.RawMessage(`{"id":"ignored","displayName":"engineering"}`)},
+				},
+			}, http.StatusOK, &group)
+			require.Equal(t, "engineering", group.DisplayName)
+			require.Equal(t, []coderd.SCIMGroupMember{{Value: bob.ID.String(), Display: bob.Username}}, group.Members)
+
+			// Adding an existing member is a no-op.
+			request(t, client, key, "PATCH", "/scim/v2/Groups/"+group.ID, coderd.SCIMPatchRequest{
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "Add", Path: "members", Value: json.RawMessage(fmt.Sprintf(`[{"value":%q}]`, bob.ID))},
+				},
+			}, http.StatusOK, &group)
+			require.Len(t, group.Members, 1)
+
+			// Unknown users can't be added.
+			request(t, client, key, "PATCH", "/scim/v2/Groups/"+group.ID, coderd.SCIMPatchRequest{
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "add", Path: "members", Value: json.RawMessage(fmt.Sprintf(`[{"value":%q}]`, uuid.New()))},
+				},
+			}, http.StatusBadRequest, nil)
+
+			request(t, client, key, "PUT", "/scim/v2/Groups/"+group.ID, coderd.SCIMGroup{
+				DisplayName: "engineering",
+				Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}, {Value: bob.ID.String()}},
+			}, http.StatusOK, &group)
+			require.Len(t, group.Members, 2)
+
+			var withoutMembers coderd.SCIMGroup
+			request(t, client, key, "GET", "/scim/v2/Groups/"+group.ID+"?excludedAttributes=members", nil, http.StatusOK, &withoutMembers)
+			require.Equal(t, "engineering", withoutMembers.DisplayName)
+			require.Empty(t, withoutMembers.Members)
+
+			request(t, client, key, "DELETE", "/scim/v2/Groups/"+group.ID, nil, http.StatusNoContent, nil)
+			request(t, client, key, "GET", "/scim/v2/Groups/"+group.ID, nil, http.StatusNotFound, nil)
+			_, err = client.GroupByOrgAndName(ctx, first.OrganizationID, "engineering")
+			require.Error(t, err)
+		})
+
+		t.Run("AllUsersGroupHidden", func(t *testing.T) {
+			t.Parallel()
+			client, first, key := setup(t)
+
+			var list struct {
+				TotalResults int `json:"totalResults"`
+			}
+			request(t, client, key, "GET", "/scim/v2/Groups", nil, http.StatusOK, &list)
+			require.Equal(t, 0, list.TotalResults)
+			// The ID of the group of all users is the organization ID.
+			request(t, client, key, "DELETE", "/scim/v2/Groups/"+first.OrganizationID.String(), nil, http.StatusNotFound, nil)
+		})
+	})
+
+	t.Run("discovery", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scimAPIKey := []byte("hi")
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			SCIMAPIKey: scimAPIKey,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				AccountID: "coolin",
+				Features: license.Features{
+					codersdk.FeatureSCIM: 1,
+				},
+			},
+		})
+
+		for _, path := range []string{
+			"/scim/v2/ServiceProviderConfig",
+			"/scim/v2/Schemas",
+			"/scim/v2/Schemas/urn:ietf:params:scim:schemas:core:2.0:Group",
+			"/scim/v2/ResourceTypes",
+			"/scim/v2/ResourceTypes/User",
+		} {
+			res, err := client.Request(ctx, "GET", path, nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode, path)
+		}
+
+		res, err := client.Request(ctx, "GET", "/scim/v2/ServiceProviderConfig", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var config struct {
+			Patch struct {
+				Supported bool `json:"supported"`
+			} `json:"patch"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&config))
+		require.True(t, config.Patch.Supported)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Schemas/unknown", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
 }
@@ -0,0 +1,685 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+)
+
+// scimMaxResults is the maximum number of groups returned by a single list
+// request.
+const scimMaxResults = 1000
+
+// SCIMGroup maps onto a Coder group in the SCIM organization. The
+// displayName is the group name, and members are referenced by their Coder
+// user ID as returned when creating SCIM users.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members,omitempty"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+		Location     string `json:"location"`
+	} `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+// SCIMPatchRequest is a SCIM PATCH request as described in RFC 7644 section
+// 3.5.2.
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op" enums:"add,remove,replace"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value" swaggertype:"object"`
+}
+
+// scimGroupUpdate is the state a group is changed to by a SCIM request.
+type scimGroupUpdate struct {
+	name    string
+	members map[uuid.UUID]struct{}
+}
+
+func (u *scimGroupUpdate) setMembers(members []SCIMGroupMember) error {
+	u.members = make(map[uuid.UUID]struct{}, len(members))
+	return u.addMembers(members)
+}
+
+func (u *scimGroupUpdate) addMembers(members []SCIMGroupMember) error {
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return xerrors.Errorf("member %q must be a user ID: %w", member.Value, spec.ErrInvalidValue)
+		}
+		u.members[id] = struct{}{}
+	}
+	return nil
+}
+
+func (u *scimGroupUpdate) removeMembers(members []SCIMGroupMember) error {
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return xerrors.Errorf("member %q must be a user ID: %w", member.Value, spec.ErrInvalidValue)
+		}
+		delete(u.members, id)
+	}
+	return nil
+}
+
+// apply applies a PATCH operation. Paths other than displayName and members
+// are not stored by Coder, and operations on externalId are ignored.
+func (u *scimGroupUpdate) apply(op SCIMPatchOperation) error {
+	attr, filterValue, err := scimPatchPath(op.Path)
+	if err != nil {
+		return err
+	}
+	if filterValue != "" && (attr != "members" || !strings.EqualFold(op.Op, "remove")) {
+		return xerrors.Errorf("filters are only supported when removing members: %w", spec.ErrInvalidPath)
+	}
+
+	decode := func(v interface{}) error {
+		if err := json.Unmarshal(op.Value, v); err != nil {
+			return xerrors.Errorf("invalid value for %q: %w", op.Path, spec.ErrInvalidValue)
+		}
+		return nil
+	}
+
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+		replace := strings.EqualFold(op.Op, "replace")
+		switch attr {
+		case "":
+			// Without a path the value contains the attributes to change.
+			var value struct {
+				DisplayName *string            `json:"displayName"`
+				Members     *[]SCIMGroupMember `json:"members"`
+			}
+			if err := decode(&value); err != nil {
+				return err
+			}
+			if value.DisplayName != nil {
+				u.name = *value.DisplayName
+			}
+			if value.Members == nil {
+				return nil
+			}
+			if replace {
+				return u.setMembers(*value.Members)
+			}
+			return u.addMembers(*value.Members)
+		case "displayname":
+			return decode(&u.name)
+		case "members":
+			var members []SCIMGroupMember
+			if err := decode(&members); err != nil {
+				return err
+			}
+			if replace {
+				return u.setMembers(members)
+			}
+			return u.addMembers(members)
+		case "externalid":
+			return nil
+		}
+	case "remove":
+		switch attr {
+		case "members":
+			if filterValue != "" {
+				return u.removeMembers([]SCIMGroupMember{{Value: filterValue}})
+			}
+			// Some identity providers list the members to remove in the
+			// value instead of a filter.
+			if len(op.Value) == 0 || string(op.Value) == "null" {
+				return u.setMembers(nil)
+			}
+			var members []SCIMGroupMember
+			if err := decode(&members); err != nil {
+				return err
+			}
+			return u.removeMembers(members)
+		case "externalid":
+			return nil
+		case "displayname":
+			return xerrors.Errorf("displayName is required: %w", spec.ErrMutability)
+		}
+	default:
+		return xerrors.Errorf("unsupported operation %q: %w", op.Op, spec.ErrInvalidSyntax)
+	}
+	return xerrors.Errorf("unsupported path %q: %w", op.Path, spec.ErrInvalidPath)
+}
+
+// scimPatchPath parses the path of a PATCH operation. Only the attribute
+// name, lowercased, and an optional `members[value eq "<id>"]` filter are
+// supported.
+func scimPatchPath(path string) (attr string, filterValue string, err error) {
+	path = strings.TrimSpace(path)
+	attr, filter, hasFilter := strings.Cut(path, "[")
+	attr = strings.ToLower(strings.TrimPrefix(attr, scimGroupSchema+":"))
+	if !hasFilter {
+		return attr, "", nil
+	}
+	filter, ok := strings.CutSuffix(filter, "]")
+	if !ok {
+		return "", "", xerrors.Errorf("invalid path %q: %w", path, spec.ErrInvalidPath)
+	}
+	filterAttr, filterValue, err := scimEqualityFilter(filter)
+	if err != nil {
+		return "", "", xerrors.Errorf("invalid path %q: %w", path, spec.ErrInvalidPath)
+	}
+	if !strings.EqualFold(filterAttr, "value") {
+		return "", "", xerrors.Errorf("path %q can only filter on value: %w", path, spec.ErrInvalidPath)
+	}
+	return attr, filterValue, nil
+}
+
+// scimEqualityFilter parses a filter of the form `<attribute> eq "<value>"`.
+// This is the only kind of filter identity providers use to look up
+// resources, so other operators are not supported.
+func scimEqualityFilter(filter string) (attr string, value string, err error) {
+	attr, rest, _ := strings.Cut(strings.TrimSpace(filter), " ")
+	op, literal, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	if attr == "" || !strings.EqualFold(op, "eq") {
+		return "", "", xerrors.Errorf(`filter %q must be of the form <attribute> eq "<value>": %w`, filter, spec.ErrInvalidFilter)
+	}
+	value, err = strconv.Unquote(strings.TrimSpace(literal))
+	if err != nil {
+		return "", "", xerrors.Errorf("filter %q must compare to a string: %w", filter, spec.ErrInvalidFilter)
+	}
+	return attr, value, nil
+}
+
+func (api *API) scimGroup(group database.Group, members []database.User) SCIMGroup {
+	sGroup := SCIMGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          group.ID.String(),
+		DisplayName: group.Name,
+		Members:     make([]SCIMGroupMember, 0, len(members)),
+	}
+	for _, member := range members {
+		sGroup.Members = append(sGroup.Members, SCIMGroupMember{
+			Value:   member.ID.String(),
+			Display: member.Username,
+		})
+	}
+	sGroup.Meta.ResourceType = "Group"
+	sGroup.Meta.Location = api.AGPL.AccessURL.JoinPath("/scim/v2/Groups", sGroup.ID).String()
+	return sGroup
+}
+
+// scimGroupByID returns the group with the ID of the request. The group of
+// all users is managed by Coder, so it is hidden from SCIM.
+func (api *API) scimGroupByID(ctx context.Context, r *http.Request) (database.Group, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return database.Group{}, xerrors.Errorf("invalid group ID: %w", spec.ErrNotFound)
+	}
+	//nolint:gocritic // needed for SCIM
+	group, err := api.Database.GetGroupByID(dbauthz.AsSystemRestricted(ctx), id)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && group.Name == database.AllUsersGroup) {
+		return database.Group{}, xerrors.Errorf("group %q not found: %w", id, spec.ErrNotFound)
+	}
+	if err != nil {
+		return database.Group{}, xerrors.Errorf("get group: %w", err)
+	}
+	return group, nil
+}
+
+// scimUpdateGroup renames the group and adds and removes members to match
+// the update. Members must be users of the group's organization. It should be
+// called in a transaction.
+func scimUpdateGroup(ctx context.Context, tx database.Store, group database.Group, update scimGroupUpdate) (database.Group, []database.User, error) {
+	if update.name == "" {
+		return database.Group{}, nil, xerrors.Errorf("displayName is required: %w", spec.ErrInvalidValue)
+	}
+	if update.name == database.AllUsersGroup {
+		return database.Group{}, nil, xerrors.Errorf("%q is a reserved group name: %w", database.AllUsersGroup, spec.ErrUniqueness)
+	}
+
+	//nolint:gocritic // needed for SCIM
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	var err error
+	if update.name != group.Name {
+		group, err = tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+			ID:             group.ID,
+			Name:           update.name,
+			AvatarURL:      group.AvatarURL,
+			QuotaAllowance: group.QuotaAllowance,
+		})
+		if database.IsUniqueViolation(err) {
+			return database.Group{}, nil, xerrors.Errorf("group %q already exists: %w", update.name, spec.ErrUniqueness)
+		}
+		if err != nil {
+			return database.Group{}, nil, xerrors.Errorf("update group: %w", err)
+		}
+	}
+
+	current, err := tx.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		return database.Group{}, nil, xerrors.Errorf("get group members: %w", err)
+	}
+	currentIDs := make(map[uuid.UUID]struct{}, len(current))
+	for _, member := range current {
+		currentIDs[member.ID] = struct{}{}
+		if _, ok := update.members[member.ID]; ok {
+			continue
+		}
+		err = tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+			UserID:  member.ID,
+			GroupID: group.ID,
+		})
+		if err != nil {
+			return database.Group{}, nil, xerrors.Errorf("remove group member %q: %w", member.ID, err)
+		}
+	}
+	for id := range update.members {
+		if _, ok := currentIDs[id]; ok {
+			continue
+		}
+		_, err = tx.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: group.OrganizationID,
+			UserID:         id,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return database.Group{}, nil, xerrors.Errorf("user %q is not a member of the organization: %w", id, spec.ErrInvalidValue)
+		}
+		if err != nil {
+			return database.Group{}, nil, xerrors.Errorf("get organization member: %w", err)
+		}
+		err = tx.InsertGroupMember(ctx, database.InsertGroupMemberParams{
+			UserID:  id,
+			GroupID: group.ID,
+		})
+		if err != nil {
+			return database.Group{}, nil, xerrors.Errorf("add group member %q: %w", id, err)
+		}
+	}
+
+	members, err := tx.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		return database.Group{}, nil, xerrors.Errorf("get group members: %w", err)
+	}
+	return group, members, nil
+}
+
+// scimGetGroups lists the groups of the SCIM organization. Identity
+// providers look up groups with a filter on displayName.
+//
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter on displayName or id with the eq operator"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
+// @Param excludedAttributes query string false "Set to members to omit members"
+// @Success 200
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	query := r.URL.Query()
+	var filterAttr, filterValue string
+	if filter := query.Get("filter"); filter != "" {
+		var err error
+		filterAttr, filterValue, err = scimEqualityFilter(filter)
+		if err != nil {
+			scimWriteError(rw, err)
+			return
+		}
+		filterAttr = strings.ToLower(filterAttr)
+		if filterAttr != "displayname" && filterAttr != "id" {
+			scimWriteError(rw, xerrors.Errorf("filtering on %q is not supported: %w", filterAttr, spec.ErrInvalidFilter))
+			return
+		}
+	}
+	startIndex, count := 1, scimMaxResults
+	for name, value := range map[string]*int{"startIndex": &startIndex, "count": &count} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			scimWriteError(rw, xerrors.Errorf("%s must be a number: %w", name, spec.ErrInvalidValue))
+			return
+		}
+		*value = parsed
+	}
+	// Per RFC 7644 section 3.4.2.4, values below 1 are treated as 1 and
+	// negative counts as 0.
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > scimMaxResults {
+		count = scimMaxResults
+	}
+	excludeMembers := strings.EqualFold(query.Get("excludedAttributes"), "members")
+
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+	//nolint:gocritic // needed for SCIM
+	groups, err := api.Database.GetGroupsByOrganizationID(dbauthz.AsSystemRestricted(ctx), organizationID)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	matched := make([]database.Group, 0, len(groups))
+	for _, group := range groups {
+		if group.Name == database.AllUsersGroup {
+			continue
+		}
+		switch filterAttr {
+		case "displayname":
+			if !strings.EqualFold(group.Name, filterValue) {
+				continue
+			}
+		case "id":
+			if group.ID.String() != filterValue {
+				continue
+			}
+		}
+		matched = append(matched, group)
+	}
+
+	resp := newSCIMListResponse([]SCIMGroup{})
+	resp.TotalResults = len(matched)
+	resp.StartIndex = startIndex
+	if startIndex <= len(matched) {
+		matched = matched[startIndex-1:]
+	} else {
+		matched = nil
+	}
+	if len(matched) > count {
+		matched = matched[:count]
+	}
+	for _, group := range matched {
+		var members []database.User
+		if !excludeMembers {
+			//nolint:gocritic // needed for SCIM
+			members, err = api.Database.GetGroupMembers(dbauthz.AsSystemRestricted(ctx), group.ID)
+			if err != nil {
+				scimWriteError(rw, err)
+				return
+			}
+		}
+		sGroup := api.scimGroup(group, members)
+		if excludeMembers {
+			sGroup.Members = nil
+		}
+		resp.Resources = append(resp.Resources, sGroup)
+	}
+	resp.ItemsPerPage = len(resp.Resources)
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	group, err := api.scimGroupByID(ctx, r)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+	//nolint:gocritic // needed for SCIM
+	members, err := api.Database.GetGroupMembers(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	sGroup := api.scimGroup(group, members)
+	if strings.EqualFold(r.URL.Query().Get("excludedAttributes"), "members") {
+		sGroup.Members = nil
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, sGroup)
+}
+
+// scimPostGroup creates a group in the SCIM organization.
+//
+// @Summary SCIM 2.0: Create group
+// @ID scim-create-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("invalid group: %w", spec.ErrInvalidSyntax))
+		return
+	}
+	update := scimGroupUpdate{name: sGroup.DisplayName}
+	err = update.setMembers(sGroup.Members)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	var (
+		group   database.Group
+		members []database.User
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		//nolint:gocritic // needed for SCIM
+		group, err = tx.InsertGroup(dbauthz.AsSystemRestricted(ctx), database.InsertGroupParams{
+			ID:             uuid.New(),
+			Name:           update.name,
+			OrganizationID: organizationID,
+		})
+		if database.IsUniqueViolation(err) {
+			return xerrors.Errorf("group %q already exists: %w", update.name, spec.ErrUniqueness)
+		}
+		if err != nil {
+			return xerrors.Errorf("insert group: %w", err)
+		}
+		group, members, err = scimUpdateGroup(ctx, tx, group, update)
+		return err
+	}, nil)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, api.scimGroup(group, members))
+}
+
+// scimPutGroup replaces the name and members of a group.
+//
+// @Summary SCIM 2.0: Replace group
+// @ID scim-replace-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMGroup true "Replace group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+func (api *API) scimPutGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("invalid group: %w", spec.ErrInvalidSyntax))
+		return
+	}
+
+	api.scimChangeGroup(rw, r, func(update *scimGroupUpdate) error {
+		update.name = sGroup.DisplayName
+		return update.setMembers(sGroup.Members)
+	})
+}
+
+// scimPatchGroup renames groups and adds and removes members. Members are
+// removed with a `members[value eq "<id>"]` path, or by listing them in the
+// value of a remove operation on `members`.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMPatchRequest true "Patch group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	var req SCIMPatchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("invalid patch request: %w", spec.ErrInvalidSyntax))
+		return
+	}
+
+	api.scimChangeGroup(rw, r, func(update *scimGroupUpdate) error {
+		for _, op := range req.Operations {
+			err := update.apply(op)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// scimChangeGroup updates the group of the request to the state set by
+// change, which starts with the current state of the group.
+func (api *API) scimChangeGroup(rw http.ResponseWriter, r *http.Request, change func(update *scimGroupUpdate) error) {
+	ctx := r.Context()
+
+	group, err := api.scimGroupByID(ctx, r)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	var members []database.User
+	err = api.Database.InTx(func(tx database.Store) error {
+		//nolint:gocritic // needed for SCIM
+		current, err := tx.GetGroupMembers(dbauthz.AsSystemRestricted(ctx), group.ID)
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+		update := scimGroupUpdate{
+			name:    group.Name,
+			members: make(map[uuid.UUID]struct{}, len(current)),
+		}
+		for _, member := range current {
+			update.members[member.ID] = struct{}{}
+		}
+		err = change(&update)
+		if err != nil {
+			return err
+		}
+
+		group, members, err = scimUpdateGroup(ctx, tx, group, update)
+		return err
+	}, nil)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.scimGroup(group, members))
+}
+
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	group, err := api.scimGroupByID(ctx, r)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+	//nolint:gocritic // needed for SCIM
+	err = api.Database.DeleteGroupByID(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -120,9 +120,9 @@ func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	validErrs := validateTemplateACLPerms(ctx, api.Database, req.UserPerms, "user_perms", true)
+	validErrs := validateACLPerms(ctx, req.UserPerms, "user_perms", validateTemplateRole, api.Database.GetUserByID)
 	validErrs = append(validErrs,
-		validateTemplateACLPerms(ctx, api.Database, req.GroupPerms, "group_perms", false)...)
+		validateACLPerms(ctx, req.GroupPerms, "group_perms", validateTemplateRole, api.Database.GetGroupByID)...)
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -189,35 +189,27 @@ func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// nolint TODO fix stupid flag.
-func validateTemplateACLPerms(ctx context.Context, db database.Store, perms map[string]codersdk.TemplateRole, field string, isUser bool) []codersdk.ValidationError {
+// validateACLPerms validates the user or group permissions of a template or
+// workspace ACL update. getByID fetches the user or group an ID refers to.
+func validateACLPerms[R ~string, T any](ctx context.Context, perms map[string]R, field string, validateRole func(R) error, getByID func(context.Context, uuid.UUID) (T, error)) []codersdk.ValidationError {
 	var validErrs []codersdk.ValidationError
 	for k, v := range perms {
-		if err := validateTemplateRole(v); err != nil {
+		if err := validateRole(v); err != nil {
 			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: err.Error()})
 			continue
 		}
 
 		id, err := uuid.Parse(k)
 		if err != nil {
-			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: "ID " + k + "must be a valid UUID."})
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: "ID " + k + " must be a valid UUID."})
 			continue
 		}
 
-		if isUser {
-			// This could get slow if we get a ton of user perm updates.
-			_, err = db.GetUserByID(ctx, id)
-			if err != nil {
-				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find resource with ID %q: %v", k, err.Error())})
-				continue
-			}
-		} else {
-			// This could get slow if we get a ton of group perm updates.
-			_, err = db.GetGroupByID(ctx, id)
-			if err != nil {
-				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find resource with ID %q: %v", k, err.Error())})
-				continue
-			}
+		// This could get slow if we get a ton of perm updates.
+		_, err = getByID(ctx, id)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find resource with ID %q: %v", k, err.Error())})
+			continue
 		}
 	}
 
@@ -2,9 +2,12 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/coder/coder/enterprise/coderd/license"
@@ -16,6 +19,7 @@ import (
 
 	"github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/coderdtest/ldaptest"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/enterprise/coderd/coderdenttest"
@@ -352,6 +356,166 @@ func TestUserOIDC(t *testing.T) {
 	})
 }
 
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+	t.Run("Groups", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		const baseDN = "ou=people,dc=coder,dc=com"
+		alice := ldaptest.Entry{
+			DN:       "uid=alice," + baseDN,
+			Password: "alicepassword",
+			Attributes: map[string][]string{
+				"uid":      {"alice"},
+				"mail":     {"alice@coder.com"},
+				"memberOf": {"cn=devs,ou=groups,dc=coder,dc=com", "cn=ops,ou=groups,dc=coder,dc=com"},
+			},
+		}
+		srv := ldaptest.New(t, ldaptest.ModePlain, alice)
+
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				LDAPConfig: &coderd.LDAPConfig{
+					URL:               srv.URL,
+					SearchBaseDN:      baseDN,
+					SearchFilter:      "(uid=%s)",
+					UsernameAttribute: "uid",
+					EmailAttribute:    "mail",
+					GroupsAttribute:   "memberOf",
+					GroupMapping:      map[string]string{"ops": "bingbong"},
+					AllowSignups:      true,
+				},
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{codersdk.FeatureTemplateRBAC: 1},
+			},
+		})
+
+		admin, err := client.User(ctx, "me")
+		require.NoError(t, err)
+		require.Len(t, admin.OrganizationIDs, 1)
+
+		devs, err := client.CreateGroup(ctx, admin.OrganizationIDs[0], codersdk.CreateGroupRequest{
+			Name: "devs",
+		})
+		require.NoError(t, err)
+		mapped, err := client.CreateGroup(ctx, admin.OrganizationIDs[0], codersdk.CreateGroupRequest{
+			Name: "bingbong",
+		})
+		require.NoError(t, err)
+
+		_, err = client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: alice.Password,
+		})
+		require.NoError(t, err)
+
+		devs, err = client.Group(ctx, devs.ID)
+		require.NoError(t, err)
+		require.Len(t, devs.Members, 1)
+		mapped, err = client.Group(ctx, mapped.ID)
+		require.NoError(t, err)
+		require.Len(t, mapped.Members, 1)
+
+		// Removing the user from a group in the directory removes them from
+		// the Coder group on the next login.
+		alice.Attributes["memberOf"] = []string{"cn=devs,ou=groups,dc=coder,dc=com"}
+		srv.SetEntries(alice)
+		_, err = client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: "alice",
+			Password: alice.Password,
+		})
+		require.NoError(t, err)
+
+		mapped, err = client.Group(ctx, mapped.ID)
+		require.NoError(t, err)
+		require.Len(t, mapped.Members, 0)
+	})
+}
+
+// nolint:bodyclose
+func TestUserOAuth2LoginProvider(t *testing.T) {
+	t.Parallel()
+	t.Run("Groups", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		groups := []string{"coder/devs", "coder/ops"}
+		var groupsMu sync.Mutex
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			groupsMu.Lock()
+			defer groupsMu.Unlock()
+			rw.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+				"id":       1234,
+				"username": "alice",
+				"email":    "alice@coder.com",
+				"groups":   groups,
+			})
+		}))
+		t.Cleanup(srv.Close)
+
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				OAuth2LoginProviders: []*coderd.OAuth2LoginProvider{{
+					OAuth2Config:  &testutil.OAuth2Config{},
+					ID:            "gitlab",
+					DisplayName:   "GitLab",
+					UserInfoURL:   srv.URL,
+					IDField:       "id",
+					UsernameField: "username",
+					EmailField:    "email",
+					GroupsField:   "groups",
+					GroupMapping: map[string]string{
+						"coder/devs": "devs",
+						"coder/ops":  "bingbong",
+					},
+					AllowSignups: true,
+				}},
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{codersdk.FeatureTemplateRBAC: 1},
+			},
+		})
+
+		admin, err := client.User(ctx, "me")
+		require.NoError(t, err)
+		require.Len(t, admin.OrganizationIDs, 1)
+
+		devs, err := client.CreateGroup(ctx, admin.OrganizationIDs[0], codersdk.CreateGroupRequest{
+			Name: "devs",
+		})
+		require.NoError(t, err)
+		mapped, err := client.CreateGroup(ctx, admin.OrganizationIDs[0], codersdk.CreateGroupRequest{
+			Name: "bingbong",
+		})
+		require.NoError(t, err)
+
+		resp := oauth2LoginProviderCallback(t, client, "gitlab")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		devs, err = client.Group(ctx, devs.ID)
+		require.NoError(t, err)
+		require.Len(t, devs.Members, 1)
+		mapped, err = client.Group(ctx, mapped.ID)
+		require.NoError(t, err)
+		require.Len(t, mapped.Members, 1)
+
+		// Leaving a group upstream removes the user from the Coder group on
+		// the next login.
+		groupsMu.Lock()
+		groups = []string{"coder/devs"}
+		groupsMu.Unlock()
+		resp = oauth2LoginProviderCallback(t, client, "gitlab")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		mapped, err = client.Group(ctx, mapped.ID)
+		require.NoError(t, err)
+		require.Len(t, mapped.Members, 0)
+	})
+}
+
 func oidcCallback(t *testing.T, client *codersdk.Client, code string) *http.Response {
 	t.Helper()
 	client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
@@ -373,3 +537,25 @@ func oidcCallback(t *testing.T, client *codersdk.Client, code string) *http.Resp
 	t.Log(string(data))
 	return res
 }
+
+func oauth2LoginProviderCallback(t *testing.T, client *codersdk.Client, provider string) *http.Response {
+	t.Helper()
+	client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	oauthURL, err := client.URL.Parse(fmt.Sprintf("/api/v2/users/oauth2/%s/callback?code=asd&state=somestate", provider))
+	require.NoError(t, err)
+	req, err := http.NewRequestWithContext(context.Background(), "GET", oauthURL.String(), nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{
+		Name:  codersdk.OAuth2StateCookie,
+		Value: "somestate",
+	})
+	res, err := client.HTTPClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	t.Log(string(data))
+	return res
+}
@@ -590,6 +590,46 @@ func (api *API) workspaceProxyGoingAway(rw http.ResponseWriter, r *http.Request)
 	})
 }
 
+// workspaceProxyReportConnection audits a connection to a workspace app, port
+// or terminal made through the proxy.
+//
+// @Summary Report workspace proxy connection
+// @ID report-workspace-proxy-connection
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Enterprise
+// @Param request body workspaceapps.ConnectionEvent true "Connection event"
+// @Success 204
+// @Router /workspaceproxies/me/report-connection [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceProxyReportConnection(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req workspaceapps.ConnectionEvent
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Action != codersdk.AuditActionConnect && req.Action != codersdk.AuditActionDisconnect {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid connection action.",
+			Detail:  fmt.Sprintf("invalid connection action: %q", req.Action),
+		})
+		return
+	}
+	switch req.Type {
+	case codersdk.ConnectionTypeReconnectingPTY, codersdk.ConnectionTypePortForward, codersdk.ConnectionTypeWorkspaceApp:
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid connection type.",
+			Detail:  fmt.Sprintf("invalid connection type: %q", req.Type),
+		})
+		return
+	}
+
+	api.AGPL.AuditConnection(ctx, req)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // reconnectingPTYSignedToken issues a signed app token for use when connecting
 // to the reconnecting PTY websocket on an external workspace proxy. This is set
 // by the client as a query parameter when connecting.
@@ -11,6 +11,7 @@ import (
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/prebuilds"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/provisionerd/proto"
@@ -45,6 +46,19 @@ func (c *committer) CommitQuota(
 	)
 	err = c.Database.InTx(func(s database.Store) error {
 		var err error
+		if workspace.OwnerID == prebuilds.OwnerID {
+			// Prebuilt workspaces are charged to the user claiming them.
+			err = s.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
+				ID:        build.ID,
+				DailyCost: request.DailyCost,
+			})
+			if err != nil {
+				return err
+			}
+			permit = true
+			return nil
+		}
+
 		consumed, err = s.GetQuotaConsumedForUser(ctx, workspace.OwnerID)
 		if err != nil {
 			return err
@@ -63,7 +77,9 @@ func (c *committer) CommitQuota(
 			BuildNumber: build.BuildNumber - 1,
 		})
 		if err == nil {
-			if build.DailyCost < previousBuild.DailyCost {
+			// The previous build of a claimed workspace was never charged
+			// to the user.
+			if build.DailyCost < previousBuild.DailyCost && previousBuild.InitiatorID != prebuilds.OwnerID {
 				netIncrease = false
 			}
 		} else if !xerrors.Is(err, sql.ErrNoRows) {
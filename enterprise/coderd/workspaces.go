@@ -0,0 +1,250 @@
+package coderd
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get workspace ACLs
+// @ID get-workspace-acls
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceACL
+// @Router /workspaces/{workspace}/acl [get]
+func (api *API) workspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	users, err := api.Database.GetWorkspaceUserRoles(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	dbGroups, err := api.Database.GetWorkspaceGroupRoles(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	dbGroups, err = coderd.AuthorizeFilter(api.AGPL.HTTPAuth, r, rbac.ActionRead, dbGroups)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching groups.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	orgIDsByMemberIDsRows, err := api.Database.GetOrganizationIDsByMemberIDs(r.Context(), userIDs)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	organizationIDsByUserID := map[uuid.UUID][]uuid.UUID{}
+	for _, organizationIDsByMemberIDsRow := range orgIDsByMemberIDsRows {
+		organizationIDsByUserID[organizationIDsByMemberIDsRow.UserID] = organizationIDsByMemberIDsRow.OrganizationIDs
+	}
+
+	groups := make([]codersdk.WorkspaceGroup, 0, len(dbGroups))
+	for _, group := range dbGroups {
+		members, err := api.Database.GetGroupMembers(ctx, group.ID)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		groups = append(groups, codersdk.WorkspaceGroup{
+			Group: convertGroup(group.Group, members),
+			Role:  convertToWorkspaceRole(group.Actions),
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceACL{
+		Users:  convertWorkspaceUsers(users, organizationIDsByUserID),
+		Groups: groups,
+	})
+}
+
+// @Summary Update workspace ACL
+// @ID update-workspace-acl
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceACL true "Update workspace ACL request"
+// @Success 200 {object} codersdk.Response
+// @Router /workspaces/{workspace}/acl [patch]
+func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	// Users the workspace is shared with can read it, but only those that
+	// can update it may share it further.
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateWorkspaceACL
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	validErrs := validateACLPerms(ctx, req.UserPerms, "user_perms", validateWorkspaceRole, api.Database.GetUserByID)
+	validErrs = append(validErrs,
+		validateACLPerms(ctx, req.GroupPerms, "group_perms", validateWorkspaceRole, api.Database.GetGroupByID)...)
+	if _, ok := req.UserPerms[workspace.OwnerID.String()]; ok {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "user_perms", Detail: "The owner of a workspace cannot be added to its ACL."})
+	}
+
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update workspace ACL!",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by ID: %w", err)
+		}
+		if workspace.UserACL == nil {
+			workspace.UserACL = database.WorkspaceACL{}
+		}
+		if workspace.GroupACL == nil {
+			workspace.GroupACL = database.WorkspaceACL{}
+		}
+
+		for id, role := range req.UserPerms {
+			// A user with an empty string implies
+			// deletion.
+			if role == "" {
+				delete(workspace.UserACL, id)
+				continue
+			}
+			workspace.UserACL[id] = convertSDKWorkspaceRole(role)
+		}
+
+		for id, role := range req.GroupPerms {
+			// An id with an empty string implies
+			// deletion.
+			if role == "" {
+				delete(workspace.GroupACL, id)
+				continue
+			}
+			workspace.GroupACL[id] = convertSDKWorkspaceRole(role)
+		}
+
+		err = tx.UpdateWorkspaceACLByID(ctx, database.UpdateWorkspaceACLByIDParams{
+			ID:       workspace.ID,
+			UserACL:  workspace.UserACL,
+			GroupACL: workspace.GroupACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace ACL by ID: %w", err)
+		}
+		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get updated workspace by ID: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = workspace
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Successfully updated workspace ACL list.",
+	})
+}
+
+func convertWorkspaceUsers(wus []database.WorkspaceUser, orgIDsByUserIDs map[uuid.UUID][]uuid.UUID) []codersdk.WorkspaceUser {
+	users := make([]codersdk.WorkspaceUser, 0, len(wus))
+
+	for _, wu := range wus {
+		users = append(users, codersdk.WorkspaceUser{
+			User: convertUser(wu.User, orgIDsByUserIDs[wu.User.ID]),
+			Role: convertToWorkspaceRole(wu.Actions),
+		})
+	}
+
+	return users
+}
+
+func validateWorkspaceRole(role codersdk.WorkspaceRole) error {
+	actions := convertSDKWorkspaceRole(role)
+	if actions == nil && role != codersdk.WorkspaceRoleDeleted {
+		return xerrors.Errorf("role %q is not a valid Workspace role", role)
+	}
+
+	return nil
+}
+
+func convertToWorkspaceRole(actions []rbac.Action) codersdk.WorkspaceRole {
+	switch {
+	case len(actions) == 1 && actions[0] == rbac.ActionRead:
+		return codersdk.WorkspaceRoleView
+	case len(actions) == 2 && actions[0] == rbac.ActionRead && actions[1] == rbac.ActionCreate:
+		return codersdk.WorkspaceRoleConnect
+	}
+
+	return ""
+}
+
+// convertSDKWorkspaceRole returns the actions granted by a role. The ACL of a
+// workspace also applies to its execution and application connect resources,
+// where "create" is the action for connecting.
+func convertSDKWorkspaceRole(role codersdk.WorkspaceRole) []rbac.Action {
+	switch role {
+	case codersdk.WorkspaceRoleConnect:
+		return []rbac.Action{rbac.ActionRead, rbac.ActionCreate}
+	case codersdk.WorkspaceRoleView:
+		return []rbac.Action{rbac.ActionRead}
+	}
+
+	return nil
+}
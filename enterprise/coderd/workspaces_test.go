@@ -2,8 +2,10 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,12 +13,14 @@ import (
 
 	"cdr.dev/slog/sloggers/slogtest"
 
+	agplaudit "github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/autobuild"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database"
 	agplschedule "github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/audit"
 	"github.com/coder/coder/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/enterprise/coderd/license"
 	"github.com/coder/coder/enterprise/coderd/schedule"
@@ -741,3 +745,280 @@ func TestWorkspaceLock(t *testing.T) {
 		require.True(t, workspace.LastUsedAt.After(lastUsedAt))
 	})
 }
+
+func TestWorkspaceACL(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, *codersdk.Client, codersdk.Workspace) {
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		ownerClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, ownerClient, user.OrganizationID, template.ID)
+		return client, user, ownerClient, workspace
+	}
+
+	// access returns whether the client can read and connect to the
+	// workspace.
+	access := func(ctx context.Context, t *testing.T, client *codersdk.Client, workspace codersdk.Workspace) (read bool, connect bool) {
+		resp, err := client.AuthCheck(ctx, codersdk.AuthorizationRequest{
+			Checks: map[string]codersdk.AuthorizationCheck{
+				"read": {
+					Object: codersdk.AuthorizationObject{
+						ResourceType: codersdk.ResourceWorkspace,
+						ResourceID:   workspace.ID.String(),
+					},
+					Action: "read",
+				},
+				"connect": {
+					Object: codersdk.AuthorizationObject{
+						ResourceType: codersdk.ResourceWorkspaceExecution,
+						ResourceID:   workspace.ID.String(),
+					},
+					Action: "create",
+				},
+			},
+		})
+		require.NoError(t, err)
+		return resp["read"], resp["connect"]
+	}
+
+	t.Run("UserRoles", func(t *testing.T) {
+		t.Parallel()
+
+		client, user, ownerClient, workspace := setup(t)
+		viewerClient, viewer := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		connectClient, connecter := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		err := ownerClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserPerms: map[string]codersdk.WorkspaceRole{
+				viewer.ID.String():    codersdk.WorkspaceRoleView,
+				connecter.ID.String(): codersdk.WorkspaceRoleConnect,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := ownerClient.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 2)
+		require.Contains(t, acl.Users, codersdk.WorkspaceUser{User: viewer, Role: codersdk.WorkspaceRoleView})
+		require.Contains(t, acl.Users, codersdk.WorkspaceUser{User: connecter, Role: codersdk.WorkspaceRoleConnect})
+
+		read, connect := access(ctx, t, viewerClient, workspace)
+		require.True(t, read)
+		require.False(t, connect)
+
+		read, connect = access(ctx, t, connectClient, workspace)
+		require.True(t, read)
+		require.True(t, connect)
+
+		read, connect = access(ctx, t, otherClient, workspace)
+		require.False(t, read)
+		require.False(t, connect)
+
+		// Users the workspace is shared with cannot share it further.
+		err = connectClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserPerms: map[string]codersdk.WorkspaceRole{
+				viewer.ID.String(): codersdk.WorkspaceRoleConnect,
+			},
+		})
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+
+		// Removing a user revokes their access.
+		err = ownerClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserPerms: map[string]codersdk.WorkspaceRole{
+				connecter.ID.String(): codersdk.WorkspaceRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+
+		read, connect = access(ctx, t, connectClient, workspace)
+		require.False(t, read)
+		require.False(t, connect)
+	})
+
+	t.Run("GroupRoles", func(t *testing.T) {
+		t.Parallel()
+
+		client, user, ownerClient, workspace := setup(t)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{member.ID.String()},
+		})
+		require.NoError(t, err)
+
+		err = ownerClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			GroupPerms: map[string]codersdk.WorkspaceRole{
+				group.ID.String(): codersdk.WorkspaceRoleConnect,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Groups, 1)
+		require.Equal(t, group.ID, acl.Groups[0].ID)
+		require.Equal(t, codersdk.WorkspaceRoleConnect, acl.Groups[0].Role)
+
+		read, connect := access(ctx, t, memberClient, workspace)
+		require.True(t, read)
+		require.True(t, connect)
+	})
+
+	t.Run("StartStopScope", func(t *testing.T) {
+		t.Parallel()
+
+		client, user, ownerClient, workspace := setup(t)
+		_, viewer := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// A token that may only start and stop the workspace can't share it.
+		token, err := ownerClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope: codersdk.APIKeyScopeWorkspaceStartStop,
+		})
+		require.NoError(t, err)
+		startStop := codersdk.New(ownerClient.URL)
+		startStop.SetSessionToken(token.Key)
+
+		err = startStop.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserPerms: map[string]codersdk.WorkspaceRole{
+				viewer.ID.String(): codersdk.WorkspaceRoleView,
+			},
+		})
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+
+		acl, err := ownerClient.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, acl.Users)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		t.Parallel()
+
+		client, user, ownerClient, workspace := setup(t)
+		_, other := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		owner, err := ownerClient.User(testutil.Context(t, testutil.WaitLong), codersdk.Me)
+		require.NoError(t, err)
+
+		for name, perms := range map[string]map[string]codersdk.WorkspaceRole{
+			"Owner":       {owner.ID.String(): codersdk.WorkspaceRoleView},
+			"InvalidRole": {other.ID.String(): "admin"},
+			"InvalidID":   {"not-a-uuid": codersdk.WorkspaceRoleView},
+		} {
+			ctx := testutil.Context(t, testutil.WaitLong)
+			err := ownerClient.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+				UserPerms: perms,
+			})
+			require.Error(t, err, name)
+			cerr, ok := codersdk.AsError(err)
+			require.True(t, ok, name)
+			require.Equal(t, http.StatusBadRequest, cerr.StatusCode(), name)
+		}
+	})
+}
+
+func TestWorkspacesBulkAudit(t *testing.T) {
+	t.Parallel()
+
+	backend := &auditBackend{}
+	client, user := coderdenttest.New(t, &coderdenttest.Options{
+		AuditLogging: true,
+		Options: &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Auditor:                  audit.NewAuditor(audit.DefaultFilter, backend),
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAuditLog: 1,
+			},
+		},
+	})
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+	err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+		ID: newVersion.ID,
+	})
+	require.NoError(t, err)
+
+	res, err := client.BulkWorkspaceAction(ctx, codersdk.BulkWorkspaceActionRequest{
+		Action: codersdk.BulkWorkspaceActionUpdate,
+		Query:  "template:" + template.Name,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Results, 1)
+	require.NotNil(t, res.Results[0].BuildID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, *res.Results[0].BuildID)
+
+	// The diff must show the template version changing from the previous
+	// build to the new one.
+	var alog *database.AuditLog
+	for _, l := range backend.AuditLogs() {
+		l := l
+		if l.ResourceID == *res.Results[0].BuildID && l.StatusCode == http.StatusCreated {
+			alog = &l
+		}
+	}
+	require.NotNil(t, alog)
+	var diff agplaudit.Map
+	require.NoError(t, json.Unmarshal(alog.Diff, &diff))
+	require.Contains(t, diff, "template_version_id")
+	require.Equal(t, version.ID.String(), diff["template_version_id"].Old)
+	require.Equal(t, newVersion.ID.String(), diff["template_version_id"].New)
+}
+
+// auditBackend records the audit logs it's sent.
+type auditBackend struct {
+	mu    sync.Mutex
+	alogs []database.AuditLog
+}
+
+func (*auditBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionStore
+}
+
+func (b *auditBackend) Export(_ context.Context, alog database.AuditLog) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.alogs = append(b.alogs, alog)
+	return nil
+}
+
+func (b *auditBackend) AuditLogs() []database.AuditLog {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]database.AuditLog(nil), b.alogs...)
+}
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -109,6 +110,9 @@ type Server struct {
 	// Used for graceful shutdown. Required for the dialer.
 	ctx    context.Context
 	cancel context.CancelFunc
+	// reportWait tracks connection events being sent to the primary.
+	reportWaitMutex sync.Mutex
+	reportWait      sync.WaitGroup
 }
 
 // New creates a new workspace proxy server. This requires a primary coderd
@@ -214,6 +218,7 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		AgentProvider:    agentProvider,
 		DisablePathApps:  opts.DisablePathApps,
 		SecureAuthCookie: opts.SecureAuthCookie,
+		ReportConnection: s.reportConnection,
 	}
 
 	// The primary coderd dashboard needs to make some GET requests to
@@ -295,6 +300,12 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 func (s *Server) Close() error {
 	s.cancel()
 
+	// Report the disconnects of open app sessions before going away.
+	s.AppServer.CloseAppSessions()
+	s.reportWaitMutex.Lock()
+	s.reportWait.Wait()
+	s.reportWaitMutex.Unlock()
+
 	// A timeout to prevent the SDK from blocking the server shutdown.
 	tmp, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -304,6 +315,24 @@ func (s *Server) Close() error {
 	return s.AppServer.Close()
 }
 
+// reportConnection sends a connection event to the primary to be audited. It
+// is sent in the background so requests aren't slowed down.
+func (s *Server) reportConnection(_ context.Context, event workspaceapps.ConnectionEvent) {
+	s.reportWaitMutex.Lock()
+	s.reportWait.Add(1)
+	s.reportWaitMutex.Unlock()
+	go func() {
+		defer s.reportWait.Done()
+		// The event is sent even if the proxy is shutting down.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := s.SDKClient.ReportConnection(ctx, event)
+		if err != nil {
+			s.Logger.Warn(ctx, "report connection", slog.F("connection_id", event.ID), slog.Error(err))
+		}
+	}()
+}
+
 func (s *Server) DialWorkspaceAgent(id uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
 	return s.SDKClient.DialWorkspaceAgent(s.ctx, id, nil)
 }
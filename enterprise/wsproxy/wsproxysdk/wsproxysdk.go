@@ -196,6 +196,24 @@ func (c *Client) WorkspaceProxyGoingAway(ctx context.Context) error {
 	return nil
 }
 
+// ReportConnection reports a connection to a workspace app, port or terminal
+// made through the proxy, so it can be audited.
+func (c *Client) ReportConnection(ctx context.Context, event workspaceapps.ConnectionEvent) error {
+	res, err := c.Request(ctx, http.MethodPost,
+		"/api/v2/workspaceproxies/me/report-connection",
+		event,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type CoordinateMessageType int
 
 const (
@@ -180,6 +180,7 @@ const createTemplateVersionTar = async (
               motdFile: "",
               name: "dev",
               operatingSystem: "linux",
+              scripts: [],
               shutdownScript: "",
               shutdownScriptTimeoutSeconds: 0,
               startupScript: "",
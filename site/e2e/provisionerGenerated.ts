@@ -116,6 +116,7 @@ export interface Agent {
   shutdownScriptTimeoutSeconds: number
   metadata: Agent_Metadata[]
   startupScriptBehavior: string
+  scripts: Agent_Script[]
 }
 
 export interface Agent_Metadata {
@@ -126,6 +127,21 @@ export interface Agent_Metadata {
   timeout: number
 }
 
+export interface Agent_Script {
+  name: string
+  displayName: string
+  script: string
+  timeoutSeconds: number
+  startBlocksLogin: boolean
+  /** run_on is either "start" or "stop". */
+  runOn: string
+  /**
+   * depends_on lists the names of scripts that must complete
+   * successfully before this script runs.
+   */
+  dependsOn: string[]
+}
+
 export interface Agent_EnvEntry {
   key: string
   value: string
@@ -491,6 +507,9 @@ export const Agent = {
     if (message.startupScriptBehavior !== "") {
       writer.uint32(154).string(message.startupScriptBehavior)
     }
+    for (const v of message.scripts) {
+      Agent_Script.encode(v!, writer.uint32(162).fork()).ldelim()
+    }
     return writer
   },
 }
@@ -519,6 +538,36 @@ export const Agent_Metadata = {
   },
 }
 
+export const Agent_Script = {
+  encode(
+    message: Agent_Script,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.name !== "") {
+      writer.uint32(10).string(message.name)
+    }
+    if (message.displayName !== "") {
+      writer.uint32(18).string(message.displayName)
+    }
+    if (message.script !== "") {
+      writer.uint32(26).string(message.script)
+    }
+    if (message.timeoutSeconds !== 0) {
+      writer.uint32(32).int32(message.timeoutSeconds)
+    }
+    if (message.startBlocksLogin === true) {
+      writer.uint32(40).bool(message.startBlocksLogin)
+    }
+    if (message.runOn !== "") {
+      writer.uint32(50).string(message.runOn)
+    }
+    for (const v of message.dependsOn) {
+      writer.uint32(58).string(v!)
+    }
+    return writer
+  },
+}
+
 export const Agent_EnvEntry = {
   encode(
     message: Agent_EnvEntry,
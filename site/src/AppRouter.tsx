@@ -26,6 +26,9 @@ const NotFoundPage = lazy(() => import("./pages/404Page/404Page"))
 const CliAuthenticationPage = lazy(
   () => import("./pages/CliAuthPage/CliAuthPage"),
 )
+const OAuth2AuthorizePage = lazy(
+  () => import("./pages/OAuth2AuthorizePage/OAuth2AuthorizePage"),
+)
 const AccountPage = lazy(
   () => import("./pages/UserSettingsPage/AccountPage/AccountPage"),
 )
@@ -314,12 +317,13 @@ export const AppRouter: FC = () => {
               </Route>
             </Route>
 
-            {/* Terminal and CLI auth pages don't have the dashboard layout */}
+            {/* Terminal, CLI auth and OAuth2 pages don't have the dashboard layout */}
             <Route
               path="/:username/:workspace/terminal"
               element={<TerminalPage renderer="canvas" />}
             />
             <Route path="cli-auth" element={<CliAuthenticationPage />} />
+            <Route path="oauth2/authorize" element={<OAuth2AuthorizePage />} />
           </Route>
 
           {/* Using path="*"" means "match anything", so this route
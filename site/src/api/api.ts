@@ -91,10 +91,12 @@ export const provisioners: TypesGen.ProvisionerDaemon[] = [
 export const login = async (
   email: string,
   password: string,
+  totpCode?: string,
 ): Promise<TypesGen.LoginWithPasswordResponse> => {
   const payload = JSON.stringify({
     email,
     password,
+    totp_code: totpCode,
   })
 
   const response = await axios.post<TypesGen.LoginWithPasswordResponse>(
@@ -108,6 +110,33 @@ export const login = async (
   return response.data
 }
 
+export const loginWithLDAP = async (
+  username: string,
+  password: string,
+): Promise<TypesGen.LoginWithPasswordResponse> => {
+  const payload: TypesGen.LoginWithLDAPRequest = {
+    username,
+    password,
+  }
+
+  const response = await axios.post<TypesGen.LoginWithPasswordResponse>(
+    "/api/v2/users/ldap/login",
+    payload,
+  )
+
+  return response.data
+}
+
+export const enrollTOTPWithPassword = async (
+  request: TypesGen.EnrollTOTPWithPasswordRequest,
+): Promise<TypesGen.TOTPEnrollment> => {
+  const response = await axios.post<TypesGen.TOTPEnrollment>(
+    "/api/v2/users/login/totp",
+    request,
+  )
+  return response.data
+}
+
 export const convertToOAUTH = async (request: TypesGen.ConvertLoginRequest) => {
   const response = await axios.post<TypesGen.OAuthConversionResponse>(
     "/api/v2/users/me/convert-login",
@@ -166,6 +195,24 @@ export const getApiKey = async (): Promise<TypesGen.GenerateAPIKeyResponse> => {
   return response.data
 }
 
+export const getOAuth2Authorization = async (
+  params: URLSearchParams,
+): Promise<TypesGen.OAuth2Authorization> => {
+  const response = await axios.get<TypesGen.OAuth2Authorization>(
+    `/api/v2/oauth2/authorize?${params}`,
+  )
+  return response.data
+}
+
+export const authorizeOAuth2ProviderApp = async (
+  params: URLSearchParams,
+): Promise<TypesGen.OAuth2AuthorizeResponse> => {
+  const response = await axios.post<TypesGen.OAuth2AuthorizeResponse>(
+    `/api/v2/oauth2/authorize?${params}`,
+  )
+  return response.data
+}
+
 export const getTokens = async (
   params: TypesGen.TokensFilter,
 ): Promise<TypesGen.APIKeyWithOwner[]> => {
@@ -706,6 +753,48 @@ export const updateUserPassword = async (
 ): Promise<undefined> =>
   axios.put(`/api/v2/users/${userId}/password`, updatePassword)
 
+export const getUserMFA = async (
+  userId = "me",
+): Promise<TypesGen.UserMFA> => {
+  const response = await axios.get<TypesGen.UserMFA>(
+    `/api/v2/users/${userId}/mfa`,
+  )
+  return response.data
+}
+
+export const enrollTOTP = async (
+  userId = "me",
+): Promise<TypesGen.TOTPEnrollment> => {
+  const response = await axios.post<TypesGen.TOTPEnrollment>(
+    `/api/v2/users/${userId}/mfa/totp`,
+  )
+  return response.data
+}
+
+export const verifyTOTP = async (
+  userId: string,
+  request: TypesGen.VerifyTOTPRequest,
+): Promise<TypesGen.TOTPRecoveryCodes> => {
+  const response = await axios.post<TypesGen.TOTPRecoveryCodes>(
+    `/api/v2/users/${userId}/mfa/totp/verify`,
+    request,
+  )
+  return response.data
+}
+
+export const regenerateTOTPRecoveryCodes = async (
+  userId = "me",
+): Promise<TypesGen.TOTPRecoveryCodes> => {
+  const response = await axios.post<TypesGen.TOTPRecoveryCodes>(
+    `/api/v2/users/${userId}/mfa/recovery-codes`,
+  )
+  return response.data
+}
+
+export const resetUserMFA = async (userId: string): Promise<void> => {
+  await axios.delete(`/api/v2/users/${userId}/mfa`)
+}
+
 export const getSiteRoles = async (): Promise<
   Array<TypesGen.AssignableRoles>
 > => {
@@ -34,6 +34,14 @@ export const isApiValidationError = (error: unknown): error is ApiError => {
   return isApiError(error) && hasApiFieldErrors(error)
 }
 
+/**
+ * hasApiFieldError returns true if the error is an ApiError with a validation
+ * for the field.
+ */
+export const hasApiFieldError = (error: unknown, field: string): boolean =>
+  isApiValidationError(error) &&
+  error.response.data.validations?.some((v) => v.field === field) === true
+
 export const hasError = (error: unknown) =>
   error !== undefined && error !== null
 
@@ -12,6 +12,7 @@ export interface APIKey {
   readonly scope: APIKeyScope
   readonly token_name: string
   readonly lifetime_seconds: number
+  readonly allow_list: string[]
 }
 
 // From codersdk/apikey.go
@@ -86,12 +87,59 @@ export interface AuditLog {
   readonly user?: User
 }
 
+// From codersdk/deployment.go
+export interface AuditLogExportConfig {
+  readonly syslog_address: string
+  readonly syslog_tls_ca_file: string
+  readonly webhook_url: string
+  readonly webhook_headers: string[]
+  readonly webhook_batch_size: number
+  readonly webhook_flush_interval: number
+  readonly webhook_spool_dir: string
+  readonly webhook_spool_max_mb: number
+  readonly file_path: string
+  readonly file_max_size_mb: number
+  readonly file_max_age: number
+  readonly file_max_backups: number
+}
+
+// From codersdk/deployment.go
+export interface AuditLogRetentionConfig {
+  readonly period: number
+  // Named type "github.com/coder/coder/cli/clibase.Struct[map[string]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly resource_types: any
+  readonly archive_dir: string
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
   readonly audit_logs: AuditLog[]
   readonly count: number
 }
 
+// From codersdk/audit.go
+export interface AuditLogVerification {
+  readonly valid: boolean
+  readonly verified: number
+  readonly pruned: number
+  readonly legacy: number
+  readonly last_sequence: number
+  readonly checkpoints: number
+  readonly last_checkpoint_at?: string
+  readonly public_key: string
+  readonly issues: AuditLogVerificationIssue[]
+  readonly issues_truncated: boolean
+}
+
+// From codersdk/audit.go
+export interface AuditLogVerificationIssue {
+  readonly type: AuditLogVerificationIssueType
+  readonly sequence: number
+  readonly audit_log_id: string
+  readonly detail: string
+}
+
 // From codersdk/audit.go
 export interface AuditLogsRequest extends Pagination {
   readonly q?: string
@@ -108,6 +156,8 @@ export interface AuthMethods {
   readonly password: AuthMethod
   readonly github: AuthMethod
   readonly oidc: OIDCAuthMethod
+  readonly ldap: LDAPAuthMethod
+  readonly oauth2: OAuth2AuthMethod[]
 }
 
 // From codersdk/authorization.go
@@ -140,6 +190,29 @@ export interface BuildInfoResponse {
   readonly workspace_proxy: boolean
 }
 
+// From codersdk/workspaces.go
+export interface BulkWorkspaceActionRequest {
+  readonly action: BulkWorkspaceAction
+  readonly q: string
+  readonly dry_run?: boolean
+}
+
+// From codersdk/workspaces.go
+export interface BulkWorkspaceActionResponse {
+  readonly action: BulkWorkspaceAction
+  readonly dry_run: boolean
+  readonly results: BulkWorkspaceActionResult[]
+}
+
+// From codersdk/workspaces.go
+export interface BulkWorkspaceActionResult {
+  readonly workspace_id: string
+  readonly workspace_name: string
+  readonly owner_name: string
+  readonly build_id?: string
+  readonly error?: string
+}
+
 // From codersdk/insights.go
 export interface ConnectionLatency {
   readonly p50: number
@@ -149,9 +222,17 @@ export interface ConnectionLatency {
 // From codersdk/users.go
 export interface ConvertLoginRequest {
   readonly to_type: LoginType
+  readonly to_provider?: string
   readonly password: string
 }
 
+// From codersdk/roles.go
+export interface CreateCustomRoleRequest {
+  readonly name: string
+  readonly display_name: string
+  readonly permissions: Permission[]
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
   readonly email: string
@@ -233,6 +314,7 @@ export interface CreateTokenRequest {
   readonly lifetime: number
   readonly scope: APIKeyScope
   readonly token_name: string
+  readonly allow_list?: string[]
 }
 
 // From codersdk/users.go
@@ -242,6 +324,16 @@ export interface CreateUserRequest {
   readonly password: string
   readonly disable_login: boolean
   readonly organization_id: string
+  readonly service_account: boolean
+}
+
+// From codersdk/webhooks.go
+export interface CreateWebhookRequest {
+  readonly name: string
+  readonly url: string
+  readonly secret: string
+  readonly events: WebhookEvent[]
+  readonly enabled: boolean
 }
 
 // From codersdk/workspaces.go
@@ -271,6 +363,18 @@ export interface CreateWorkspaceRequest {
   readonly rich_parameter_values?: WorkspaceBuildParameter[]
 }
 
+// From codersdk/roles.go
+export interface CustomRole {
+  readonly id: string
+  readonly created_at: string
+  readonly updated_at: string
+  readonly name: string
+  readonly display_name: string
+  readonly organization_id?: string
+  readonly permissions: Permission[]
+  readonly assign_name: string
+}
+
 // From codersdk/deployment.go
 export interface DAUEntry {
   readonly date: string
@@ -356,6 +460,11 @@ export interface DeploymentValues {
   readonly pg_connection_url?: string
   readonly oauth2?: OAuth2Config
   readonly oidc?: OIDCConfig
+  readonly ldap?: LDAPConfig
+  readonly password_policy?: PasswordPolicyConfig
+  readonly audit_log_export?: AuditLogExportConfig
+  readonly audit_log_retention?: AuditLogRetentionConfig
+  readonly audit_log_signing_key?: string
   readonly telemetry?: TelemetryConfig
   readonly tls?: TLSConfig
   readonly trace?: TraceConfig
@@ -382,6 +491,7 @@ export interface DeploymentValues {
   readonly max_session_expiry?: number
   readonly disable_session_expiry_refresh?: boolean
   readonly disable_password_auth?: boolean
+  readonly require_admin_mfa?: boolean
   readonly support?: SupportConfig
   // Named type "github.com/coder/coder/cli/clibase.Struct[[]github.com/coder/coder/codersdk.GitAuthConfig]" unknown, using "any"
   // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
@@ -392,6 +502,8 @@ export interface DeploymentValues {
   readonly proxy_health_status_interval?: number
   readonly enable_terraform_debug_mode?: boolean
   readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig
+  readonly record_sessions?: boolean
+  readonly notifications?: NotificationsConfig
   // This is likely an enum in an external package ("github.com/coder/coder/cli/clibase.YAMLConfigPath")
   readonly config?: string
   readonly write_config?: boolean
@@ -400,6 +512,12 @@ export interface DeploymentValues {
   readonly address?: any
 }
 
+// From codersdk/usermfa.go
+export interface EnrollTOTPWithPasswordRequest {
+  readonly email: string
+  readonly password: string
+}
+
 // From codersdk/deployment.go
 export interface Entitlements {
   readonly features: Record<FeatureName, Feature>
@@ -525,6 +643,30 @@ export interface IssueReconnectingPTYSignedTokenResponse {
   readonly signed_token: string
 }
 
+// From codersdk/users.go
+export interface LDAPAuthMethod extends AuthMethod {
+  readonly signInText: string
+}
+
+// From codersdk/deployment.go
+export interface LDAPConfig {
+  readonly url: string
+  readonly start_tls: boolean
+  readonly tls_ca_file: string
+  readonly bind_dn: string
+  readonly bind_password: string
+  readonly search_base_dn: string
+  readonly search_filter: string
+  readonly username_attribute: string
+  readonly email_attribute: string
+  readonly groups_attribute: string
+  // Named type "github.com/coder/coder/cli/clibase.Struct[map[string]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly group_mapping: any
+  readonly allow_signups: boolean
+  readonly sign_in_text: string
+}
+
 // From codersdk/licenses.go
 export interface License {
   readonly id: number
@@ -549,15 +691,23 @@ export interface LoggingConfig {
   readonly stackdriver: string
 }
 
+// From codersdk/users.go
+export interface LoginWithLDAPRequest {
+  readonly username: string
+  readonly password: string
+}
+
 // From codersdk/users.go
 export interface LoginWithPasswordRequest {
   readonly email: string
   readonly password: string
+  readonly totp_code?: string
 }
 
 // From codersdk/users.go
 export interface LoginWithPasswordResponse {
   readonly session_token: string
+  readonly recovery_codes?: string[]
 }
 
 // From codersdk/users.go
@@ -567,9 +717,86 @@ export interface MinimalUser {
   readonly avatar_url: string
 }
 
+// From codersdk/notifications.go
+export interface NotificationPreference {
+  readonly kind: NotificationKind
+  readonly enabled: boolean
+}
+
+// From codersdk/deployment.go
+export interface NotificationsConfig {
+  readonly email: NotificationsEmailConfig
+}
+
+// From codersdk/deployment.go
+export interface NotificationsEmailConfig {
+  readonly from: string
+  // Named type "github.com/coder/coder/cli/clibase.HostPort" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly smarthost: any
+  readonly username: string
+  readonly password: string
+  readonly force_tls: boolean
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2AppEndpoints {
+  readonly authorization: string
+  readonly token: string
+}
+
+// From codersdk/users.go
+export interface OAuth2AuthMethod {
+  readonly id: string
+  readonly display_name: string
+  readonly icon_url: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2Authorization {
+  readonly app: OAuth2ProviderApp
+  readonly scope: APIKeyScope
+  readonly redirect_uri: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2AuthorizeRequest {
+  readonly client_id: string
+  readonly redirect_uri?: string
+  readonly scope?: APIKeyScope
+  readonly state?: string
+  readonly response_type: string
+  readonly code_challenge: string
+  readonly code_challenge_method: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2AuthorizeResponse {
+  readonly redirect_uri: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2AuthorizedApp {
+  readonly id: string
+  readonly name: string
+  readonly icon: string
+  readonly callback_url: string
+  readonly token_count: number
+  readonly last_used_at: string
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
   readonly github: OAuth2GithubConfig
+  // Named type "github.com/coder/coder/cli/clibase.Struct[[]github.com/coder/coder/codersdk.OAuth2LoginProviderConfig]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly login_providers: any
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2Error {
+  readonly error: string
+  readonly error_description?: string
 }
 
 // From codersdk/deployment.go
@@ -585,11 +812,65 @@ export interface OAuth2GithubConfig {
   readonly enterprise_base_url: string
 }
 
+// From codersdk/deployment.go
+export interface OAuth2LoginProviderConfig {
+  readonly id: string
+  readonly display_name: string
+  readonly icon_url: string
+  readonly client_id: string
+  readonly auth_url: string
+  readonly token_url: string
+  readonly user_info_url: string
+  readonly scopes: string[]
+  readonly id_field: string
+  readonly username_field: string
+  readonly email_field: string
+  readonly email_verified_field: string
+  readonly ignore_email_verified: boolean
+  readonly groups_field: string
+  readonly group_mapping: Record<string, string>
+  readonly allowed_groups: string[]
+  readonly allow_signups: boolean
+  readonly email_domain: string[]
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderApp {
+  readonly id: string
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+  readonly endpoints: OAuth2AppEndpoints
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppSecret {
+  readonly id: string
+  readonly created_at: string
+  readonly last_used_at?: string
+  readonly client_secret_truncated: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2ProviderAppSecretFull {
+  readonly id: string
+  readonly client_secret_full: string
+}
+
+// From codersdk/oauth2.go
+export interface OAuth2TokenResponse {
+  readonly access_token: string
+  readonly token_type: string
+  readonly expires_in: number
+  readonly refresh_token: string
+}
+
 // From codersdk/users.go
 export interface OAuthConversionResponse {
   readonly state_string: string
   readonly expires_at: string
   readonly to_type: LoginType
+  readonly to_provider?: string
   readonly user_id: string
 }
 
@@ -654,6 +935,15 @@ export interface Pagination {
   readonly offset?: number
 }
 
+// From codersdk/deployment.go
+export interface PasswordPolicyConfig {
+  readonly min_length: number
+  readonly character_classes: number
+  readonly history: number
+  readonly lockout_threshold: number
+  readonly lockout_window: number
+}
+
 // From codersdk/groups.go
 export interface PatchGroupRequest {
   readonly add_users: string[]
@@ -678,6 +968,20 @@ export interface PatchWorkspaceProxy {
   readonly regenerate_token: boolean
 }
 
+// From codersdk/roles.go
+export interface Permission {
+  readonly negate: boolean
+  readonly resource_type: RBACResource
+  readonly action: string
+}
+
+// From codersdk/oauth2.go
+export interface PostOAuth2ProviderAppRequest {
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+}
+
 // From codersdk/deployment.go
 export interface PprofConfig {
   readonly enable: boolean
@@ -753,6 +1057,13 @@ export interface PutExtendWorkspaceRequest {
   readonly deadline: string
 }
 
+// From codersdk/oauth2.go
+export interface PutOAuth2ProviderAppRequest {
+  readonly name: string
+  readonly callback_url: string
+  readonly icon: string
+}
+
 // From codersdk/deployment.go
 export interface RateLimitConfig {
   readonly disable_all: boolean
@@ -865,6 +1176,17 @@ export interface TLSConfig {
   readonly client_key_file: string
 }
 
+// From codersdk/usermfa.go
+export interface TOTPEnrollment {
+  readonly secret: string
+  readonly url: string
+}
+
+// From codersdk/usermfa.go
+export interface TOTPRecoveryCodes {
+  readonly recovery_codes: string[]
+}
+
 // From codersdk/deployment.go
 export interface TelemetryConfig {
   readonly enable: boolean
@@ -897,6 +1219,8 @@ export interface Template {
   readonly failure_ttl_ms: number
   readonly inactivity_ttl_ms: number
   readonly locked_ttl_ms: number
+  readonly record_sessions: boolean
+  readonly prebuilt_workspaces: number
 }
 
 // From codersdk/templates.go
@@ -995,6 +1319,31 @@ export interface TemplateVersion {
   readonly warnings?: TemplateVersionWarning[]
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionAgentDiff {
+  readonly name: string
+  readonly change: TemplateVersionDiffChange
+  readonly fields: string[]
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionDiff {
+  readonly from_version_id: string
+  readonly to_version_id: string
+  readonly files: TemplateVersionFileDiff[]
+  readonly variables: TemplateVersionVariableDiff[]
+  readonly parameters: TemplateVersionParameterDiff[]
+  readonly resources: TemplateVersionResourceDiff[]
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionFileDiff {
+  readonly path: string
+  readonly change: TemplateVersionDiffChange
+  readonly binary: boolean
+  readonly diff: string
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionGitAuth {
   readonly id: string
@@ -1023,6 +1372,15 @@ export interface TemplateVersionParameter {
   readonly ephemeral: boolean
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionParameterDiff {
+  readonly name: string
+  readonly change: TemplateVersionDiffChange
+  readonly fields: string[]
+  readonly from?: TemplateVersionParameter
+  readonly to?: TemplateVersionParameter
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionParameterOption {
   readonly name: string
@@ -1031,6 +1389,15 @@ export interface TemplateVersionParameterOption {
   readonly icon: string
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionResourceDiff {
+  readonly type: string
+  readonly name: string
+  readonly change: TemplateVersionDiffChange
+  readonly fields: string[]
+  readonly agents: TemplateVersionAgentDiff[]
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionVariable {
   readonly name: string
@@ -1042,6 +1409,15 @@ export interface TemplateVersionVariable {
   readonly sensitive: boolean
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionVariableDiff {
+  readonly name: string
+  readonly change: TemplateVersionDiffChange
+  readonly fields: string[]
+  readonly from?: TemplateVersionVariable
+  readonly to?: TemplateVersionVariable
+}
+
 // From codersdk/templates.go
 export interface TemplateVersionsByTemplateRequest extends Pagination {
   readonly template_id: string
@@ -1065,6 +1441,11 @@ export interface TraceConfig {
   readonly capture_logs: boolean
 }
 
+// From codersdk/workspaces.go
+export interface TransferWorkspaceRequest {
+  readonly owner: string
+}
+
 // From codersdk/templates.go
 export interface TransitionStats {
   readonly P50?: number
@@ -1089,6 +1470,17 @@ export interface UpdateCheckResponse {
   readonly url: string
 }
 
+// From codersdk/roles.go
+export interface UpdateCustomRoleRequest {
+  readonly display_name: string
+  readonly permissions: Permission[]
+}
+
+// From codersdk/notifications.go
+export interface UpdateNotificationPreferencesRequest {
+  readonly preferences: NotificationPreference[]
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
   readonly roles: string[]
@@ -1115,6 +1507,8 @@ export interface UpdateTemplateMeta {
   readonly failure_ttl_ms?: number
   readonly inactivity_ttl_ms?: number
   readonly locked_ttl_ms?: number
+  readonly record_sessions?: boolean
+  readonly prebuilt_workspaces?: number
 }
 
 // From codersdk/users.go
@@ -1133,6 +1527,21 @@ export interface UpdateUserQuietHoursScheduleRequest {
   readonly schedule: string
 }
 
+// From codersdk/webhooks.go
+export interface UpdateWebhookRequest {
+  readonly name: string
+  readonly url: string
+  readonly secret?: string
+  readonly events: WebhookEvent[]
+  readonly enabled: boolean
+}
+
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceACL {
+  readonly user_perms?: Record<string, WorkspaceRole>
+  readonly group_perms?: Record<string, WorkspaceRole>
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutostartRequest {
   readonly schedule?: string
@@ -1176,6 +1585,7 @@ export interface User {
   readonly roles: Role[]
   readonly avatar_url: string
   readonly login_type: LoginType
+  readonly service_account: boolean
 }
 
 // From codersdk/insights.go
@@ -1212,6 +1622,13 @@ export interface UserLoginType {
   readonly login_type: LoginType
 }
 
+// From codersdk/usermfa.go
+export interface UserMFA {
+  readonly totp_enabled: boolean
+  readonly recovery_codes_remaining: number
+  readonly required: boolean
+}
+
 // From codersdk/deployment.go
 export interface UserQuietHoursScheduleConfig {
   readonly default_schedule: string
@@ -1249,6 +1666,92 @@ export interface VariableValue {
   readonly value: string
 }
 
+// From codersdk/usermfa.go
+export interface VerifyTOTPRequest {
+  readonly code: string
+}
+
+// From codersdk/webhooks.go
+export interface Webhook {
+  readonly id: string
+  readonly created_at: string
+  readonly updated_at: string
+  readonly name: string
+  readonly url: string
+  readonly events: WebhookEvent[]
+  readonly enabled: boolean
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDelivery {
+  readonly id: string
+  readonly webhook_id: string
+  readonly created_at: string
+  readonly event: WebhookEvent
+  readonly status: WebhookDeliveryStatus
+  readonly attempts: number
+  readonly next_attempt_at?: string
+  readonly last_attempt_at?: string
+  readonly delivered_at?: string
+  readonly status_code?: number
+  readonly error?: string
+  readonly payload: Record<string, string>
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayload {
+  readonly id: string
+  readonly event: WebhookEvent
+  readonly timestamp: string
+  readonly workspace?: WebhookPayloadWorkspace
+  readonly workspace_build?: WebhookPayloadWorkspaceBuild
+  readonly template?: WebhookPayloadTemplate
+  readonly template_version?: WebhookPayloadTemplateVersion
+  readonly user?: WebhookPayloadUser
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayloadTemplate {
+  readonly id: string
+  readonly name: string
+  readonly active_version_id: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayloadTemplateVersion {
+  readonly id: string
+  readonly name: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayloadUser {
+  readonly id: string
+  readonly username: string
+  readonly email: string
+  readonly status: UserStatus
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayloadWorkspace {
+  readonly id: string
+  readonly name: string
+  readonly owner_id: string
+  readonly owner_name: string
+  readonly template_id: string
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayloadWorkspaceBuild {
+  readonly id: string
+  readonly build_number: number
+  readonly transition: WorkspaceTransition
+  readonly reason: BuildReason
+  readonly initiator_id: string
+  readonly template_version_id: string
+  readonly deadline?: string
+  readonly error?: string
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
   readonly id: string
@@ -1273,6 +1776,12 @@ export interface Workspace {
   readonly health: WorkspaceHealth
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceACL {
+  readonly users: WorkspaceUser[]
+  readonly group: WorkspaceGroup[]
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgent {
   readonly id: string
@@ -1300,6 +1809,7 @@ export interface WorkspaceAgent {
   readonly expanded_directory?: string
   readonly version: string
   readonly apps: WorkspaceApp[]
+  readonly scripts: WorkspaceAgentScript[]
   readonly latency?: Record<string, DERPRegion>
   readonly connection_timeout_seconds: number
   readonly troubleshooting_url: string
@@ -1351,12 +1861,42 @@ export interface WorkspaceAgentMetadataResult {
   readonly error: string
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentScript {
+  readonly id: string
+  readonly name: string
+  readonly display_name: string
+  readonly script: string
+  readonly run_on: WorkspaceAgentScriptRunOn
+  readonly timeout_seconds: number
+  readonly start_blocks_login: boolean
+  readonly depends_on: string[]
+  readonly status: WorkspaceAgentScriptStatus
+  readonly exit_code?: number
+  readonly started_at?: string
+  readonly ended_at?: string
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentSessionRecording {
+  readonly id: string
+  readonly workspace_id: string
+  readonly agent_id: string
+  readonly type: WorkspaceAgentSessionRecordingType
+  readonly created_at: string
+  readonly started_at: string
+  readonly ended_at: string
+  readonly truncated: boolean
+  readonly size: number
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentStartupLog {
   readonly id: number
   readonly created_at: string
   readonly output: string
   readonly level: LogLevel
+  readonly source: string
 }
 
 // From codersdk/workspaceapps.go
@@ -1433,6 +1973,11 @@ export interface WorkspaceFilter {
   readonly q?: string
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceGroup extends Group {
+  readonly role: WorkspaceRole
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceHealth {
   readonly healthy: boolean
@@ -1493,6 +2038,11 @@ export interface WorkspaceResourceMetadata {
   readonly sensitive: boolean
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceUser extends User {
+  readonly role: WorkspaceRole
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
   readonly q?: string
@@ -1505,8 +2055,21 @@ export interface WorkspacesResponse {
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect"
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"]
+export type APIKeyScope =
+  | "all"
+  | "application_connect"
+  | "read_only"
+  | "template_push"
+  | "user_read"
+  | "workspace_start_stop"
+export const APIKeyScopes: APIKeyScope[] = [
+  "all",
+  "application_connect",
+  "read_only",
+  "template_push",
+  "user_read",
+  "workspace_start_stop",
+]
 
 // From codersdk/workspaceagents.go
 export type AgentSubsystem = "envbox"
@@ -1514,8 +2077,10 @@ export const AgentSubsystems: AgentSubsystem[] = ["envbox"]
 
 // From codersdk/audit.go
 export type AuditAction =
+  | "connect"
   | "create"
   | "delete"
+  | "disconnect"
   | "login"
   | "logout"
   | "register"
@@ -1523,8 +2088,10 @@ export type AuditAction =
   | "stop"
   | "write"
 export const AuditActions: AuditAction[] = [
+  "connect",
   "create",
   "delete",
+  "disconnect",
   "login",
   "logout",
   "register",
@@ -1533,6 +2100,30 @@ export const AuditActions: AuditAction[] = [
   "write",
 ]
 
+// From codersdk/audit.go
+export type AuditLogVerificationIssueType =
+  | "broken_link"
+  | "checkpoint"
+  | "gap"
+  | "missing"
+  | "modified"
+  | "pruned"
+  | "truncated"
+  | "unchained"
+  | "unsigned"
+export const AuditLogVerificationIssueTypes: AuditLogVerificationIssueType[] =
+  [
+    "broken_link",
+    "checkpoint",
+    "gap",
+    "missing",
+    "modified",
+    "pruned",
+    "truncated",
+    "unchained",
+    "unsigned",
+  ]
+
 // From codersdk/workspacebuilds.go
 export type BuildReason = "autostart" | "autostop" | "initiator"
 export const BuildReasons: BuildReason[] = [
@@ -1541,8 +2132,30 @@ export const BuildReasons: BuildReason[] = [
   "initiator",
 ]
 
+// From codersdk/workspaces.go
+export type BulkWorkspaceAction = "delete" | "start" | "stop" | "update"
+export const BulkWorkspaceActions: BulkWorkspaceAction[] = [
+  "delete",
+  "start",
+  "stop",
+  "update",
+]
+
+// From codersdk/audit.go
+export type ConnectionType =
+  | "port_forward"
+  | "reconnecting_pty"
+  | "ssh"
+  | "workspace_app"
+export const ConnectionTypes: ConnectionType[] = [
+  "port_forward",
+  "reconnecting_pty",
+  "ssh",
+  "workspace_app",
+]
+
 // From codersdk/deployment.go
-export type Entitlement = "entitled" | "grace_period" | "not_entitled"
+export type Entitlement =  "entitled" | "grace_period" | "not_entitled"
 export const Entitlements: Entitlement[] = [
   "entitled",
   "grace_period",
@@ -1628,15 +2241,39 @@ export type LogSource = "provisioner" | "provisioner_daemon"
 export const LogSources: LogSource[] = ["provisioner", "provisioner_daemon"]
 
 // From codersdk/apikey.go
-export type LoginType = "github" | "none" | "oidc" | "password" | "token"
+export type LoginType =
+  | "github"
+  | "ldap"
+  | "none"
+  | "oauth2"
+  | "oauth2_provider_app"
+  | "oidc"
+  | "password"
+  | "token"
 export const LoginTypes: LoginType[] = [
   "github",
+  "ldap",
   "none",
+  "oauth2",
+  "oauth2_provider_app",
   "oidc",
   "password",
   "token",
 ]
 
+// From codersdk/notifications.go
+export type NotificationKind =
+  | "token_expiring"
+  | "workspace_autostop"
+  | "workspace_build_failed"
+  | "workspace_locked"
+export const NotificationKinds: NotificationKind[] = [
+  "token_expiring",
+  "workspace_autostop",
+  "workspace_build_failed",
+  "workspace_locked",
+]
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
   | "canceled"
@@ -1686,6 +2323,7 @@ export type RBACResource =
   | "assign_org_role"
   | "assign_role"
   | "audit_log"
+  | "custom_role"
   | "debug_info"
   | "deployment_config"
   | "deployment_stats"
@@ -1709,6 +2347,7 @@ export const RBACResources: RBACResource[] = [
   "assign_org_role",
   "assign_role",
   "audit_log",
+  "custom_role",
   "debug_info",
   "deployment_config",
   "deployment_stats",
@@ -1732,24 +2371,36 @@ export const RBACResources: RBACResource[] = [
 export type ResourceType =
   | "api_key"
   | "convert_login"
+  | "custom_role"
   | "git_ssh_key"
   | "group"
   | "license"
+  | "oauth2_provider_app"
+  | "oauth2_provider_app_secret"
   | "template"
   | "template_version"
   | "user"
+  | "webhook"
   | "workspace"
+  | "workspace_agent"
+  | "workspace_app"
   | "workspace_build"
 export const ResourceTypes: ResourceType[] = [
   "api_key",
   "convert_login",
+  "custom_role",
   "git_ssh_key",
   "group",
   "license",
+  "oauth2_provider_app",
+  "oauth2_provider_app_secret",
   "template",
   "template_version",
   "user",
+  "webhook",
   "workspace",
+  "workspace_agent",
+  "workspace_app",
   "workspace_build",
 ]
 
@@ -1769,6 +2420,14 @@ export const TemplateAppsTypes: TemplateAppsType[] = ["builtin"]
 export type TemplateRole = "" | "admin" | "use"
 export const TemplateRoles: TemplateRole[] = ["", "admin", "use"]
 
+// From codersdk/templateversions.go
+export type TemplateVersionDiffChange = "added" | "modified" | "removed"
+export const TemplateVersionDiffChanges: TemplateVersionDiffChange[] = [
+  "added",
+  "modified",
+  "removed",
+]
+
 // From codersdk/templateversions.go
 export type TemplateVersionWarning = "UNSUPPORTED_WORKSPACES"
 export const TemplateVersionWarnings: TemplateVersionWarning[] = [
@@ -1776,8 +2435,8 @@ export const TemplateVersionWarnings: TemplateVersionWarning[] = [
 ]
 
 // From codersdk/users.go
-export type UserStatus = "active" | "suspended"
-export const UserStatuses: UserStatus[] = ["active", "suspended"]
+export type UserStatus = "active" | "locked" | "suspended"
+export const UserStatuses: UserStatus[] = ["active", "locked", "suspended"]
 
 // From codersdk/templateversions.go
 export type ValidationMonotonicOrder = "decreasing" | "increasing"
@@ -1786,6 +2445,33 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
   "increasing",
 ]
 
+// From codersdk/webhooks.go
+export type WebhookDeliveryStatus = "delivered" | "failed" | "pending"
+export const WebhookDeliveryStatuses: WebhookDeliveryStatus[] = [
+  "delivered",
+  "failed",
+  "pending",
+]
+
+// From codersdk/webhooks.go
+export type WebhookEvent =
+  | "template_version_activated"
+  | "user_created"
+  | "user_suspended"
+  | "workspace_autostop_imminent"
+  | "workspace_build_failed"
+  | "workspace_build_started"
+  | "workspace_build_succeeded"
+export const WebhookEvents: WebhookEvent[] = [
+  "template_version_activated",
+  "user_created",
+  "user_suspended",
+  "workspace_autostop_imminent",
+  "workspace_build_failed",
+  "workspace_build_started",
+  "workspace_build_succeeded",
+]
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLifecycle =
   | "created"
@@ -1809,6 +2495,35 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
   "starting",
 ]
 
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentScriptRunOn = "start" | "stop"
+export const WorkspaceAgentScriptRunOns: WorkspaceAgentScriptRunOn[] = [
+  "start",
+  "stop",
+]
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentScriptStatus =
+  | "error"
+  | "pending"
+  | "running"
+  | "skipped"
+  | "success"
+  | "timeout"
+export const WorkspaceAgentScriptStatuses: WorkspaceAgentScriptStatus[] = [
+  "error",
+  "pending",
+  "running",
+  "skipped",
+  "success",
+  "timeout",
+]
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentSessionRecordingType = "reconnecting_pty" | "ssh"
+export const WorkspaceAgentSessionRecordingTypes: WorkspaceAgentSessionRecordingType[] =
+  ["reconnecting_pty", "ssh"]
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentStartupScriptBehavior = "blocking" | "non-blocking"
 export const WorkspaceAgentStartupScriptBehaviors: WorkspaceAgentStartupScriptBehavior[] =
@@ -1848,6 +2563,10 @@ export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
   "public",
 ]
 
+// From codersdk/workspaces.go
+export type WorkspaceRole = "" | "connect" | "view"
+export const WorkspaceRoles: WorkspaceRole[] = ["", "connect", "view"]
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
   | "canceled"
@@ -111,6 +111,13 @@ export const AuditLogRow: React.FC<AuditLogRowProps> = ({
                   spacing={1}
                 >
                   <AuditLogDescription auditLog={auditLog} />
+                  {auditLog.user?.service_account && (
+                    <Pill
+                      text={t("table.logRow.serviceAccountLabel")}
+                      type="info"
+                      lightBorder
+                    />
+                  )}
                   {auditLog.is_deleted && (
                     <span className={styles.deletedLabel}>
                       <>{t("table.logRow.deletedLabel")}</>
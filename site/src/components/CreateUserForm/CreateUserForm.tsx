@@ -51,6 +51,7 @@ export const CreateUserForm: FC<
         username: "",
         organization_id: myOrgId,
         disable_login: false,
+        service_account: false,
       },
       validationSchema,
       onSubmit,
@@ -11,7 +11,10 @@ export const RequireAuth: FC = () => {
   const [authState] = useAuth()
   const location = useLocation()
   const isHomePage = location.pathname === "/"
-  const navigateTo = isHomePage ? "/login" : embedRedirect(location.pathname)
+  // The query is kept so pages like the OAuth2 consent page work after login.
+  const navigateTo = isHomePage
+    ? "/login"
+    : embedRedirect(`${location.pathname}${location.search}`)
 
   if (authState.matches("signedOut")) {
     return <Navigate to={navigateTo} state={{ isRedirect: !isHomePage }} />
@@ -75,6 +75,35 @@ export const OAuthSignInForm: FC<OAuthSignInFormProps> = ({
           </Button>
         </Link>
       )}
+
+      {authMethods?.oauth2.map((provider) => (
+        <Link
+          key={provider.id}
+          href={`/api/v2/users/oauth2/${provider.id}/callback?redirect=${encodeURIComponent(
+            redirectTo,
+          )}`}
+        >
+          <Button
+            size="large"
+            startIcon={
+              provider.icon_url ? (
+                <img
+                  alt={`${provider.display_name} icon`}
+                  src={provider.icon_url}
+                  className={styles.buttonIcon}
+                />
+              ) : (
+                <KeyIcon className={styles.buttonIcon} />
+              )
+            }
+            disabled={isSigningIn}
+            fullWidth
+            type="submit"
+          >
+            {provider.display_name}
+          </Button>
+        </Link>
+      ))}
     </Box>
   )
 }
@@ -5,26 +5,43 @@ import { LoadingButton } from "../LoadingButton/LoadingButton"
 import { Language } from "./SignInForm"
 import { FormikContextType, FormikTouched, useFormik } from "formik"
 import * as Yup from "yup"
-import { FC } from "react"
+import { FC, useEffect, useState } from "react"
+import { useMutation } from "@tanstack/react-query"
 import { BuiltInAuthFormValues } from "./SignInForm.types"
+import { enrollTOTPWithPassword } from "api/api"
+import { hasApiFieldError } from "api/errors"
+import { CodeExample } from "components/CodeExample/CodeExample"
 
 type PasswordSignInFormProps = {
-  onSubmit: (credentials: { email: string; password: string }) => void
+  onSubmit: (credentials: {
+    email: string
+    password: string
+    totpCode?: string
+  }) => void
   initialTouched?: FormikTouched<BuiltInAuthFormValues>
   isSigningIn: boolean
+  error?: unknown
 }
 
 export const PasswordSignInForm: FC<PasswordSignInFormProps> = ({
   onSubmit,
   initialTouched,
   isSigningIn,
+  error,
 }) => {
+  // Once the password is correct, the code field stays visible even if the
+  // code is wrong.
+  const [showTOTPCode, setShowTOTPCode] = useState(false)
+  const enrollMutation = useMutation(enrollTOTPWithPassword)
+  const enrollment = enrollMutation.data
+
   const validationSchema = Yup.object({
     email: Yup.string()
       .trim()
       .email(Language.emailInvalid)
       .required(Language.emailRequired),
     password: Yup.string(),
+    totpCode: Yup.string().trim(),
   })
 
   const form: FormikContextType<BuiltInAuthFormValues> =
@@ -32,13 +49,32 @@ export const PasswordSignInForm: FC<PasswordSignInFormProps> = ({
       initialValues: {
         email: "",
         password: "",
+        totpCode: "",
       },
       validationSchema,
-      onSubmit,
+      onSubmit: ({ email, password, totpCode }) =>
+        onSubmit({
+          email,
+          password,
+          totpCode: showTOTPCode && totpCode ? totpCode : undefined,
+        }),
       initialTouched,
     })
   const getFieldHelpers = getFormHelpers<BuiltInAuthFormValues>(form)
 
+  const totpCodeRequired = hasApiFieldError(error, "totp_code")
+  const totpEnrollmentRequired = hasApiFieldError(error, "totp_enrollment")
+  const { email, password } = form.values
+  useEffect(() => {
+    if (totpCodeRequired || totpEnrollmentRequired) {
+      setShowTOTPCode(true)
+    }
+    if (totpEnrollmentRequired && !enrollMutation.data) {
+      enrollMutation.mutate({ email, password })
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps -- Only enroll once, with the credentials that were just submitted.
+  }, [totpCodeRequired, totpEnrollmentRequired])
+
   return (
     <form onSubmit={form.handleSubmit}>
       <Stack spacing={2.5}>
@@ -59,10 +95,30 @@ export const PasswordSignInForm: FC<PasswordSignInFormProps> = ({
           label={Language.passwordLabel}
           type="password"
         />
+        {enrollment && (
+          <>
+            <span>{Language.totpEnroll}</span>
+            <CodeExample code={enrollment.secret} />
+            <CodeExample code={enrollment.url} />
+          </>
+        )}
+        {showTOTPCode && (
+          <TextField
+            {...getFieldHelpers("totpCode")}
+            onChange={onChangeTrimmed(form)}
+            autoFocus
+            autoComplete="one-time-code"
+            fullWidth
+            id="totpCode"
+            label={
+              enrollment ? Language.totpEnrollCodeLabel : Language.totpCodeLabel
+            }
+          />
+        )}
         <div>
           <LoadingButton
             size="large"
-            loading={isSigningIn}
+            loading={isSigningIn || enrollMutation.isLoading}
             fullWidth
             type="submit"
           >
@@ -32,6 +32,8 @@ SigningIn.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false, signInText: "" },
+    oauth2: [],
   },
 }
 
@@ -52,6 +54,20 @@ WithError.args = {
   },
 }
 
+export const WithTOTPCodeRequired = Template.bind({})
+WithTOTPCodeRequired.args = {
+  ...SignedOut.args,
+  error: mockApiError({
+    message: "Multi-factor authentication code required.",
+    validations: [
+      {
+        field: "totp_code",
+        detail: "Enter a code from your authenticator app or a recovery code.",
+      },
+    ],
+  }),
+}
+
 export const WithGithub = Template.bind({})
 WithGithub.args = {
   ...SignedOut.args,
@@ -60,6 +76,8 @@ WithGithub.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false, signInText: "" },
+    oauth2: [],
   },
 }
 
@@ -71,6 +89,8 @@ WithOIDC.args = {
     password: { enabled: true },
     github: { enabled: false },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    ldap: { enabled: false, signInText: "" },
+    oauth2: [],
   },
 }
 
@@ -82,6 +102,8 @@ WithOIDCWithoutPassword.args = {
     password: { enabled: false },
     github: { enabled: false },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    ldap: { enabled: false, signInText: "" },
+    oauth2: [],
   },
 }
 
@@ -93,6 +115,8 @@ WithoutAny.args = {
     password: { enabled: false },
     github: { enabled: false },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false, signInText: "" },
+    oauth2: [],
   },
 }
 
@@ -104,5 +128,36 @@ WithGithubAndOIDC.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    ldap: { enabled: false, signInText: "" },
+    oauth2: [],
+  },
+}
+
+export const WithLDAP = Template.bind({})
+WithLDAP.args = {
+  ...SignedOut.args,
+  authMethods: {
+    convert_to_oidc_enabled: false,
+    password: { enabled: true },
+    github: { enabled: false },
+    oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: true, signInText: "Active Directory" },
+    oauth2: [],
+  },
+}
+
+export const WithOAuth2Providers = Template.bind({})
+WithOAuth2Providers.args = {
+  ...SignedOut.args,
+  authMethods: {
+    convert_to_oidc_enabled: false,
+    password: { enabled: true },
+    github: { enabled: false },
+    oidc: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false, signInText: "" },
+    oauth2: [
+      { id: "gitlab", display_name: "GitLab", icon_url: "/icon/gitlab.svg" },
+      { id: "gitea", display_name: "Gitea", icon_url: "" },
+    ],
   },
 }
@@ -6,6 +6,7 @@ import { useTranslation } from "react-i18next"
 import { Maybe } from "../Conditionals/Maybe"
 import { PasswordSignInForm } from "./PasswordSignInForm"
 import { OAuthSignInForm } from "./OAuthSignInForm"
+import { LDAPSignInForm } from "./LDAPSignInForm"
 import { BuiltInAuthFormValues } from "./SignInForm.types"
 import Button from "@mui/material/Button"
 import EmailIcon from "@mui/icons-material/EmailOutlined"
@@ -17,9 +18,16 @@ export const Language = {
   passwordLabel: "Password",
   emailInvalid: "Please enter a valid email address.",
   emailRequired: "Please enter an email address.",
+  usernameLabel: "Username",
+  usernameRequired: "Please enter a username.",
   passwordSignIn: "Sign In",
   githubSignIn: "GitHub",
   oidcSignIn: "OpenID Connect",
+  ldapSignIn: "LDAP",
+  totpCodeLabel: "Authenticator app code or recovery code",
+  totpEnrollCodeLabel: "Authenticator app code",
+  totpEnroll:
+    "Your account requires multi-factor authentication. Add this secret to your authenticator app, or import the URL, and enter the code it shows.",
 }
 
 const useStyles = makeStyles((theme) => ({
@@ -71,7 +79,13 @@ export interface SignInFormProps {
   error?: unknown
   info?: string
   authMethods?: AuthMethods
-  onSubmit: (credentials: { email: string; password: string }) => void
+  // ldap is set when the email is the username of an LDAP account.
+  onSubmit: (credentials: {
+    email: string
+    password: string
+    ldap?: boolean
+    totpCode?: string
+  }) => void
   // initialTouched is only used for testing the error state of the form.
   initialTouched?: FormikTouched<BuiltInAuthFormValues>
 }
@@ -86,11 +100,16 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
   initialTouched,
 }) => {
   const oAuthEnabled = Boolean(
-    authMethods?.github.enabled || authMethods?.oidc.enabled,
+    authMethods?.github.enabled ||
+      authMethods?.oidc.enabled ||
+      authMethods?.oauth2.length,
   )
+  const ldapEnabled = Boolean(authMethods?.ldap?.enabled)
   const passwordEnabled = authMethods?.password.enabled ?? true
-  // Hide password auth by default if any OAuth method is enabled
-  const [showPasswordAuth, setShowPasswordAuth] = useState(!oAuthEnabled)
+  // Hide password auth by default if any OAuth or LDAP method is enabled
+  const [showPasswordAuth, setShowPasswordAuth] = useState(
+    !oAuthEnabled && !ldapEnabled,
+  )
   const styles = useStyles()
   const commonTranslation = useTranslation("common")
   const loginPageTranslation = useTranslation("loginPage")
@@ -111,9 +130,26 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
           <Alert severity="info">{info}</Alert>
         </div>
       </Maybe>
+      <Maybe condition={ldapEnabled}>
+        <LDAPSignInForm
+          onSubmit={({ username, password }) =>
+            onSubmit({ email: username, password, ldap: true })
+          }
+          isSigningIn={isSigningIn}
+          signInText={authMethods?.ldap.signInText}
+        />
+      </Maybe>
+      <Maybe condition={ldapEnabled && (showPasswordAuth || oAuthEnabled)}>
+        <div className={styles.divider}>
+          <div className={styles.dividerLine} />
+          <div className={styles.dividerLabel}>Or</div>
+          <div className={styles.dividerLine} />
+        </div>
+      </Maybe>
       <Maybe condition={passwordEnabled && showPasswordAuth}>
         <PasswordSignInForm
           onSubmit={onSubmit}
+          error={error}
           initialTouched={initialTouched}
           isSigningIn={isSigningIn}
         />
@@ -133,7 +169,7 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
         />
       </Maybe>
 
-      <Maybe condition={!passwordEnabled && !oAuthEnabled}>
+      <Maybe condition={!passwordEnabled && !oAuthEnabled && !ldapEnabled}>
         <Alert severity="error">No authentication methods configured!</Alert>
       </Maybe>
 
@@ -6,4 +6,16 @@
 export interface BuiltInAuthFormValues {
   email: string
   password: string
+  // totpCode is a code from an authenticator app or a recovery code. It is
+  // only asked for once the password is correct.
+  totpCode?: string
+}
+
+/**
+ * LDAPAuthFormValues describes the form to sign in with the username and
+ * password of an LDAP or Active Directory account.
+ */
+export interface LDAPAuthFormValues {
+  username: string
+  password: string
 }
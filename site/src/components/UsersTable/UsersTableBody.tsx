@@ -153,14 +153,15 @@ export const UsersTableBody: FC<
                           })}
                         />
                       ))}
+                      {user.service_account && (
+                        <Pill text="Service account" type="info" lightBorder />
+                      )}
                     </Stack>
                   </TableCell>
                   <TableCell
                     className={combineClasses([
                       styles.status,
-                      user.status === "suspended"
-                        ? styles.suspended
-                        : undefined,
+                      user.status !== "active" ? styles.suspended : undefined,
                     ])}
                   >
                     {user.status}
@@ -65,6 +65,8 @@ describe("LoginPage", () => {
       password: { enabled: true },
       github: { enabled: true },
       oidc: { enabled: true, signInText: "", iconUrl: "" },
+      ldap: { enabled: false, signInText: "" },
+      oauth2: [],
     }
 
     // Given
@@ -117,6 +119,8 @@ describe("LoginPage", () => {
       password: { enabled: true },
       github: { enabled: true },
       oidc: { enabled: true, signInText: "", iconUrl: "" },
+      ldap: { enabled: false, signInText: "" },
+      oauth2: [],
     }
 
     // Given
@@ -29,8 +29,8 @@ export const LoginPage: FC = () => {
           context={authState.context}
           isLoading={authState.matches("loadingInitialAuthData")}
           isSigningIn={authState.matches("signingIn")}
-          onSignIn={({ email, password }) => {
-            authSend({ type: "SIGN_IN", email, password })
+          onSignIn={({ email, password, ldap, totpCode }) => {
+            authSend({ type: "SIGN_IN", email, password, ldap, totpCode })
           }}
         />
       </>
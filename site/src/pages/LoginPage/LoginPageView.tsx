@@ -11,7 +11,12 @@ export interface LoginPageViewProps {
   context: AuthContext
   isLoading: boolean
   isSigningIn: boolean
-  onSignIn: (credentials: { email: string; password: string }) => void
+  onSignIn: (credentials: {
+    email: string
+    password: string
+    ldap?: boolean
+    totpCode?: string
+  }) => void
 }
 
 export const LoginPageView: FC<LoginPageViewProps> = ({
@@ -0,0 +1,63 @@
+import { FC, useEffect, useState } from "react"
+import { Helmet } from "react-helmet-async"
+import { useSearchParams } from "react-router-dom"
+import { authorizeOAuth2ProviderApp, getOAuth2Authorization } from "api/api"
+import { OAuth2Authorization } from "api/typesGenerated"
+import { pageTitle } from "utils/page"
+import { OAuth2AuthorizePageView } from "./OAuth2AuthorizePageView"
+
+export const OAuth2AuthorizePage: FC = () => {
+  const [searchParams] = useSearchParams()
+  const [authorization, setAuthorization] = useState<OAuth2Authorization>()
+  const [error, setError] = useState<unknown>()
+  const [isAuthorizing, setIsAuthorizing] = useState(false)
+
+  useEffect(() => {
+    getOAuth2Authorization(searchParams)
+      .then(setAuthorization)
+      .catch(setError)
+  }, [searchParams])
+
+  const onAuthorize = () => {
+    setIsAuthorizing(true)
+    authorizeOAuth2ProviderApp(searchParams)
+      .then(({ redirect_uri }) => {
+        window.location.href = redirect_uri
+      })
+      .catch((error) => {
+        setIsAuthorizing(false)
+        setError(error)
+      })
+  }
+
+  const onDeny = () => {
+    if (!authorization) {
+      return
+    }
+    // The app is told the user denied the request, as defined by RFC 6749.
+    const redirect = new URL(authorization.redirect_uri)
+    redirect.searchParams.set("error", "access_denied")
+    const state = searchParams.get("state")
+    if (state) {
+      redirect.searchParams.set("state", state)
+    }
+    window.location.href = redirect.toString()
+  }
+
+  return (
+    <>
+      <Helmet>
+        <title>{pageTitle("Authorize App")}</title>
+      </Helmet>
+      <OAuth2AuthorizePageView
+        authorization={authorization}
+        error={error}
+        isAuthorizing={isAuthorizing}
+        onAuthorize={onAuthorize}
+        onDeny={onDeny}
+      />
+    </>
+  )
+}
+
+export default OAuth2AuthorizePage
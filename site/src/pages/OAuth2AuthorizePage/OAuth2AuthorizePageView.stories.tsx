@@ -0,0 +1,33 @@
+import { Meta, StoryObj } from "@storybook/react"
+import { MockOAuth2Authorization, mockApiError } from "testHelpers/entities"
+import { OAuth2AuthorizePageView } from "./OAuth2AuthorizePageView"
+
+const meta: Meta<typeof OAuth2AuthorizePageView> = {
+  title: "pages/OAuth2AuthorizePageView",
+  component: OAuth2AuthorizePageView,
+  args: {
+    authorization: MockOAuth2Authorization,
+    isAuthorizing: false,
+  },
+}
+
+export default meta
+type Story = StoryObj<typeof OAuth2AuthorizePageView>
+
+export const Example: Story = {}
+
+export const InvalidRequest: Story = {
+  args: {
+    authorization: undefined,
+    error: mockApiError({
+      message: "Invalid OAuth2 authorization request.",
+      validations: [
+        {
+          field: "redirect_uri",
+          detail:
+            "The redirect URI must be the callback URL of the app or below it.",
+        },
+      ],
+    }),
+  },
+}
@@ -0,0 +1,99 @@
+import Button from "@mui/material/Button"
+import { makeStyles } from "@mui/styles"
+import { OAuth2Authorization } from "api/typesGenerated"
+import { ErrorAlert } from "components/Alert/ErrorAlert"
+import { FullScreenLoader } from "components/Loader/FullScreenLoader"
+import { SignInLayout } from "components/SignInLayout/SignInLayout"
+import { Welcome } from "components/Welcome/Welcome"
+import { FC } from "react"
+
+export interface OAuth2AuthorizePageViewProps {
+  authorization?: OAuth2Authorization
+  error?: unknown
+  isAuthorizing: boolean
+  onAuthorize: () => void
+  onDeny: () => void
+}
+
+export const OAuth2AuthorizePageView: FC<OAuth2AuthorizePageViewProps> = ({
+  authorization,
+  error,
+  isAuthorizing,
+  onAuthorize,
+  onDeny,
+}) => {
+  const styles = useStyles()
+
+  if (!authorization && !error) {
+    return <FullScreenLoader />
+  }
+
+  return (
+    <SignInLayout>
+      <Welcome
+        message={
+          authorization ? (
+            <>
+              Authorize <strong>{authorization.app.name}</strong>
+            </>
+          ) : (
+            "Authorize app"
+          )
+        }
+      />
+
+      {error ? <ErrorAlert error={error} className={styles.error} /> : null}
+
+      {authorization && (
+        <>
+          <p className={styles.text}>
+            <strong>{authorization.app.name}</strong> wants to access Coder on
+            your behalf with the <strong>{authorization.scope}</strong> scope.
+            You will be redirected to{" "}
+            <strong className={styles.uri}>{authorization.redirect_uri}</strong>
+            .
+          </p>
+
+          <div className={styles.buttons}>
+            <Button size="large" variant="outlined" onClick={onDeny}>
+              Cancel
+            </Button>
+            <Button
+              size="large"
+              variant="contained"
+              disabled={isAuthorizing}
+              onClick={onAuthorize}
+            >
+              Authorize
+            </Button>
+          </div>
+        </>
+      )}
+    </SignInLayout>
+  )
+}
+
+const useStyles = makeStyles((theme) => ({
+  text: {
+    fontSize: 16,
+    color: theme.palette.text.secondary,
+    marginBottom: theme.spacing(4),
+    textAlign: "center",
+    lineHeight: "160%",
+  },
+
+  uri: {
+    wordBreak: "break-all",
+  },
+
+  error: {
+    marginTop: theme.spacing(2),
+  },
+
+  buttons: {
+    display: "flex",
+    justifyContent: "flex-end",
+    gap: theme.spacing(1),
+    paddingTop: theme.spacing(1),
+  },
+}))
@@ -0,0 +1,189 @@
+import { useState } from "react"
+import { Section } from "../../../components/SettingsLayout/Section"
+import TextField from "@mui/material/TextField"
+import Box from "@mui/material/Box"
+import Button from "@mui/material/Button"
+import Skeleton from "@mui/material/Skeleton"
+import CheckCircleOutlined from "@mui/icons-material/CheckCircleOutlined"
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query"
+import {
+  enrollTOTP,
+  getUserMFA,
+  regenerateTOTPRecoveryCodes,
+  verifyTOTP,
+} from "api/api"
+import { TOTPEnrollment, UserMFA } from "api/typesGenerated"
+import { Stack } from "components/Stack/Stack"
+import { Alert } from "components/Alert/Alert"
+import { ErrorAlert } from "components/Alert/ErrorAlert"
+import { CodeExample } from "components/CodeExample/CodeExample"
+import { LoadingButton } from "components/LoadingButton/LoadingButton"
+
+export const useMultiFactorSection = () => {
+  const queryClient = useQueryClient()
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>()
+  const { data: mfa } = useQuery({
+    queryKey: ["userMFA"],
+    queryFn: () => getUserMFA(),
+  })
+  const onRecoveryCodes = (data: { recovery_codes: string[] }) => {
+    setRecoveryCodes(data.recovery_codes)
+    void queryClient.invalidateQueries(["userMFA"])
+  }
+  const enrollMutation = useMutation(() => enrollTOTP())
+  const verifyMutation = useMutation(
+    (code: string) => verifyTOTP("me", { code }),
+    {
+      onSuccess: (data) => {
+        enrollMutation.reset()
+        onRecoveryCodes(data)
+      },
+    },
+  )
+  const regenerateMutation = useMutation(() => regenerateTOTPRecoveryCodes(), {
+    onSuccess: onRecoveryCodes,
+  })
+
+  return {
+    mfa,
+    enrollment: enrollMutation.data,
+    recoveryCodes,
+    enroll: () => enrollMutation.mutate(),
+    verify: (code: string) => verifyMutation.mutate(code),
+    regenerate: () => regenerateMutation.mutate(),
+    isUpdating:
+      enrollMutation.isLoading ||
+      verifyMutation.isLoading ||
+      regenerateMutation.isLoading,
+    error:
+      enrollMutation.error ?? verifyMutation.error ?? regenerateMutation.error,
+  }
+}
+
+type MultiFactorSectionProps = {
+  mfa?: UserMFA
+  enrollment?: TOTPEnrollment
+  recoveryCodes?: string[]
+  enroll: () => void
+  verify: (code: string) => void
+  regenerate: () => void
+  isUpdating: boolean
+  error: unknown
+}
+
+export const MultiFactorSection = ({
+  mfa,
+  enrollment,
+  recoveryCodes,
+  enroll,
+  verify,
+  regenerate,
+  isUpdating,
+  error,
+}: MultiFactorSectionProps) => {
+  const [code, setCode] = useState("")
+
+  return (
+    <Section
+      id="mfa-section"
+      title="Multi-Factor Authentication"
+      description="Require a code from an authenticator app when you sign in with your password"
+    >
+      <Stack spacing={2}>
+        {Boolean(error) && <ErrorAlert error={error} />}
+        {recoveryCodes && (
+          <>
+            <Alert severity="info">
+              Save these recovery codes somewhere safe. Each one can be used
+              once to sign in if you lose your authenticator app. They will not
+              be shown again.
+            </Alert>
+            <CodeExample code={recoveryCodes.join(" ")} />
+          </>
+        )}
+        {!mfa ? (
+          <Skeleton
+            variant="rectangular"
+            sx={{ height: 40, borderRadius: 1 }}
+          />
+        ) : mfa.totp_enabled ? (
+          <>
+            <Box
+              sx={{
+                background: (theme) => theme.palette.background.paper,
+                borderRadius: 1,
+                border: (theme) => `1px solid ${theme.palette.divider}`,
+                padding: 2,
+                display: "flex",
+                gap: 2,
+                alignItems: "center",
+                fontSize: 14,
+              }}
+            >
+              <CheckCircleOutlined
+                sx={{
+                  color: (theme) => theme.palette.success.light,
+                  fontSize: 16,
+                }}
+              />
+              <span>
+                Authenticator app enabled with{" "}
+                <strong>{mfa.recovery_codes_remaining}</strong> recovery codes
+                remaining
+              </span>
+            </Box>
+            <div>
+              <LoadingButton loading={isUpdating} onClick={regenerate}>
+                Regenerate recovery codes
+              </LoadingButton>
+            </div>
+          </>
+        ) : enrollment ? (
+          <>
+            <span>
+              Add this secret to your authenticator app, or import the URL.
+              Then enter the code it shows to finish enrolling.
+            </span>
+            <CodeExample code={enrollment.secret} />
+            <CodeExample code={enrollment.url} />
+            <TextField
+              autoFocus
+              fullWidth
+              id="totp-code"
+              label="Code"
+              value={code}
+              onChange={(e) => setCode(e.currentTarget.value.trim())}
+              onKeyDown={(event) => {
+                if (event.key === "Enter") {
+                  verify(code)
+                }
+              }}
+            />
+            <div>
+              <LoadingButton
+                loading={isUpdating}
+                disabled={code === ""}
+                onClick={() => verify(code)}
+              >
+                Verify
+              </LoadingButton>
+            </div>
+          </>
+        ) : (
+          <>
+            {mfa.required && (
+              <Alert severity="warning">
+                Your role requires multi-factor authentication.
+              </Alert>
+            )}
+            <div>
+              <Button disabled={isUpdating} onClick={enroll}>
+                Enroll authenticator app
+              </Button>
+            </div>
+          </>
+        )}
+      </Stack>
+    </Section>
+  )
+}
@@ -10,6 +10,10 @@ import {
   SingleSignOnSection,
   useSingleSignOnSection,
 } from "./SingleSignOnSection"
+import {
+  MultiFactorSection,
+  useMultiFactorSection,
+} from "./MultiFactorSection"
 import { Loader } from "components/Loader/Loader"
 import { Stack } from "components/Stack/Stack"
 
@@ -33,6 +37,7 @@ export const SecurityPage: FC = () => {
     queryFn: getUserLoginType,
   })
   const singleSignOnSection = useSingleSignOnSection()
+  const multiFactorSection = useMultiFactorSection()
 
   if (!authMethods || !userLoginType) {
     return <Loader />
@@ -53,6 +58,13 @@ export const SecurityPage: FC = () => {
           },
         },
       }}
+      mfa={
+        userLoginType.login_type === "password"
+          ? {
+              section: multiFactorSection,
+            }
+          : undefined
+      }
       oidc={
         authMethods.convert_to_oidc_enabled
           ? {
@@ -70,11 +82,15 @@ export const SecurityPage: FC = () => {
 
 export const SecurityPageView = ({
   security,
+  mfa,
   oidc,
 }: {
   security: {
     form: ComponentProps<typeof SecurityForm>
   }
+  mfa?: {
+    section: ComponentProps<typeof MultiFactorSection>
+  }
   oidc?: {
     section: ComponentProps<typeof SingleSignOnSection>
   }
@@ -84,6 +100,7 @@ export const SecurityPageView = ({
       <Section title="Security" description="Update your account password">
         <SecurityForm {...security.form} />
       </Section>
+      {mfa && <MultiFactorSection {...mfa.section} />}
       {oidc && <SingleSignOnSection {...oidc.section} />}
     </Stack>
   )
@@ -4,6 +4,8 @@ import { action } from "@storybook/addon-actions"
 import {
   MockAuthMethods,
   MockAuthMethodsWithPasswordType,
+  MockTOTPEnrollment,
+  MockUserMFA,
 } from "testHelpers/entities"
 import { ComponentProps } from "react"
 import set from "lodash/fp/set"
@@ -17,6 +19,16 @@ const defaultArgs: ComponentProps<typeof SecurityPageView> = {
       onSubmit: action("onSubmit"),
     },
   },
+  mfa: {
+    section: {
+      mfa: MockUserMFA,
+      enroll: action("enroll"),
+      verify: action("verify"),
+      regenerate: action("regenerate"),
+      isUpdating: false,
+      error: undefined,
+    },
+  },
   oidc: {
     section: {
       userLoginType: {
@@ -70,3 +82,19 @@ export const ConfirmingOIDCConversion: Story = {
     defaultArgs,
   ),
 }
+
+export const EnrollingAuthenticatorApp: Story = {
+  args: set("mfa.section.enrollment", MockTOTPEnrollment, defaultArgs),
+}
+
+export const AuthenticatorAppEnabled: Story = {
+  args: set(
+    "mfa.section",
+    {
+      ...defaultArgs.mfa?.section,
+      mfa: { ...MockUserMFA, totp_enabled: true, recovery_codes_remaining: 8 },
+      recoveryCodes: ["abcde-fghjk", "mnpqr-stuvw"],
+    },
+    defaultArgs,
+  ),
+}
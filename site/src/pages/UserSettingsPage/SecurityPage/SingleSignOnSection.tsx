@@ -23,6 +23,9 @@ type LoginTypeConfirmation =
   | {
       open: true
       selectedType: LoginType
+      // selectedProvider is the ID of the OAuth2 login provider if
+      // selectedType is "oauth2".
+      selectedProvider?: string
     }
 
 export const redirectToOIDCAuth = (
@@ -40,9 +43,15 @@ export const useSingleSignOnSection = () => {
   const mutation = useMutation(convertToOAUTH, {
     onSuccess: (data) => {
       const loginTypeMsg =
-        data.to_type === "github" ? "Github" : "OpenID Connect"
+        data.to_type === "github"
+          ? "Github"
+          : data.to_type === "oauth2"
+          ? data.to_provider
+          : "OpenID Connect"
       redirectToOIDCAuth(
-        data.to_type,
+        data.to_type === "oauth2"
+          ? `oauth2/${data.to_provider}`
+          : data.to_type,
         data.state_string,
         // The redirect on success should be back to the login page with a nice message.
         // The user should be logged out if this worked.
@@ -53,8 +62,11 @@ export const useSingleSignOnSection = () => {
     },
   })
 
-  const openConfirmation = (selectedType: LoginType) => {
-    setLoginTypeConfirmation({ open: true, selectedType })
+  const openConfirmation = (
+    selectedType: LoginType,
+    selectedProvider?: string,
+  ) => {
+    setLoginTypeConfirmation({ open: true, selectedType, selectedProvider })
   }
 
   const closeConfirmation = () => {
@@ -68,6 +80,7 @@ export const useSingleSignOnSection = () => {
     }
     mutation.mutate({
       to_type: loginTypeConfirmation.selectedType,
+      to_provider: loginTypeConfirmation.selectedProvider,
       password,
     })
   }
@@ -132,6 +145,18 @@ export const SingleSignOnSection = ({
                     {getOIDCLabel(authMethods)}
                   </Button>
                 )}
+                {authMethods.oauth2.map((provider) => (
+                  <Button
+                    key={provider.id}
+                    size="large"
+                    startIcon={<OAuth2Icon iconUrl={provider.icon_url} />}
+                    fullWidth
+                    disabled={isUpdating}
+                    onClick={() => openConfirmation("oauth2", provider.id)}
+                  >
+                    {provider.display_name}
+                  </Button>
+                ))}
               </>
             ) : (
               <Box
@@ -157,12 +182,16 @@ export const SingleSignOnSection = ({
                   <strong>
                     {userLoginType.login_type === "github"
                       ? "GitHub"
+                      : userLoginType.login_type === "oauth2"
+                      ? "OAuth2"
                       : getOIDCLabel(authMethods)}
                   </strong>
                 </span>
                 <Box sx={{ ml: "auto", lineHeight: 1 }}>
                   {userLoginType.login_type === "github" ? (
                     <GitHubIcon sx={{ width: 16, height: 16 }} />
+                  ) : userLoginType.login_type === "oauth2" ? (
+                    <OAuth2Icon />
                   ) : (
                     <OIDCIcon authMethods={authMethods} />
                   )}
@@ -202,6 +231,19 @@ const OIDCIcon = ({ authMethods }: { authMethods: AuthMethods }) => {
   )
 }
 
+const OAuth2Icon = ({ iconUrl }: { iconUrl?: string }) => {
+  return iconUrl ? (
+    <Box
+      component="img"
+      alt="OAuth2 icon"
+      src={iconUrl}
+      sx={{ width: 16, height: 16 }}
+    />
+  ) : (
+    <KeyIcon sx={{ width: 16, height: 16 }} />
+  )
+}
+
 const getOIDCLabel = (authMethods: AuthMethods) => {
   return authMethods.oidc.signInText || "OpenID Connect"
 }
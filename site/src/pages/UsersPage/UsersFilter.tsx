@@ -25,6 +25,7 @@ export const useStatusFilterMenu = ({
   const statusOptions: StatusOption[] = [
     { value: "active", label: "Active", color: "success" },
     { value: "suspended", label: "Suspended", color: "secondary" },
+    { value: "locked", label: "Locked", color: "warning" },
   ]
   return useFilterMenu({
     onChange,
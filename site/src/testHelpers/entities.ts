@@ -50,6 +50,7 @@ export const MockToken: TypesGen.APIKeyWithOwner = {
   login_type: "token",
   scope: "all",
   lifetime_seconds: 2592000,
+  allow_list: [],
   token_name: "token-one",
   username: "admin",
 }
@@ -66,6 +67,7 @@ export const MockTokens: TypesGen.APIKeyWithOwner[] = [
     login_type: "token",
     scope: "all",
     lifetime_seconds: 2592000,
+    allow_list: [],
     token_name: "token-two",
     username: "admin",
   },
@@ -258,6 +260,23 @@ export const MockMemberPermissions = {
   viewAuditLog: false,
 }
 
+export const MockOAuth2ProviderApp: TypesGen.OAuth2ProviderApp = {
+  id: "f0bf3c1a-5a6b-4fa3-9c2e-1f7b0d0e5d6a",
+  name: "my-app",
+  callback_url: "https://example.com/callback",
+  icon: "/icon/code.svg",
+  endpoints: {
+    authorization: "https://coder.example.com/oauth2/authorize",
+    token: "https://coder.example.com/api/v2/oauth2/tokens",
+  },
+}
+
+export const MockOAuth2Authorization: TypesGen.OAuth2Authorization = {
+  app: MockOAuth2ProviderApp,
+  scope: "all",
+  redirect_uri: "https://example.com/callback",
+}
+
 export const MockUser: TypesGen.User = {
   id: "test-user",
   username: "TestUser",
@@ -269,6 +288,7 @@ export const MockUser: TypesGen.User = {
   avatar_url: "https://avatars.githubusercontent.com/u/95932066?s=200&v=4",
   last_seen_at: "",
   login_type: "password",
+  service_account: false,
 }
 
 export const MockUserAdmin: TypesGen.User = {
@@ -282,6 +302,7 @@ export const MockUserAdmin: TypesGen.User = {
   avatar_url: "",
   last_seen_at: "",
   login_type: "password",
+  service_account: false,
 }
 
 export const MockUser2: TypesGen.User = {
@@ -295,6 +316,7 @@ export const MockUser2: TypesGen.User = {
   avatar_url: "",
   last_seen_at: "2022-09-14T19:12:21Z",
   login_type: "oidc",
+  service_account: false,
 }
 
 export const SuspendedMockUser: TypesGen.User = {
@@ -308,6 +330,7 @@ export const SuspendedMockUser: TypesGen.User = {
   avatar_url: "",
   last_seen_at: "",
   login_type: "password",
+  service_account: false,
 }
 
 export const MockProvisioner: TypesGen.ProvisionerDaemon = {
@@ -439,6 +462,8 @@ export const MockTemplate: TypesGen.Template = {
   failure_ttl_ms: 0,
   inactivity_ttl_ms: 0,
   locked_ttl_ms: 0,
+  record_sessions: false,
+  prebuilt_workspaces: 0,
   allow_user_autostart: false,
   allow_user_autostop: false,
 }
@@ -530,6 +555,7 @@ export const MockWorkspaceApp: TypesGen.WorkspaceApp = {
 
 export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
   apps: [MockWorkspaceApp],
+  scripts: [],
   architecture: "amd64",
   created_at: "",
   environment_variables: {},
@@ -1109,6 +1135,8 @@ export const MockAuthMethods: TypesGen.AuthMethods = {
   password: { enabled: true },
   github: { enabled: false },
   oidc: { enabled: false, signInText: "", iconUrl: "" },
+  ldap: { enabled: false, signInText: "" },
+  oauth2: [],
   convert_to_oidc_enabled: true,
 }
 
@@ -1118,6 +1146,17 @@ export const MockAuthMethodsWithPasswordType: TypesGen.AuthMethods = {
   oidc: { enabled: true, signInText: "", iconUrl: "" },
 }
 
+export const MockUserMFA: TypesGen.UserMFA = {
+  totp_enabled: false,
+  recovery_codes_remaining: 0,
+  required: false,
+}
+
+export const MockTOTPEnrollment: TypesGen.TOTPEnrollment = {
+  secret: "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP",
+  url: "otpauth://totp/Coder:test@coder.com?issuer=Coder&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP",
+}
+
 export const MockGitSSHKey: TypesGen.GitSSHKey = {
   user_id: "1fa0200f-7331-4524-a364-35770666caa7",
   created_at: "2022-05-16T14:30:34.148205897Z",
@@ -1791,6 +1830,7 @@ export const MockStartupLogs: TypesGen.WorkspaceAgentStartupLog[] = [
     created_at: "2023-05-04T11:30:41.402072Z",
     output: "+ curl -fsSL https://code-server.dev/install.sh",
     level: "info",
+    source: "",
   },
   {
     id: 166664,
@@ -1798,18 +1838,21 @@ export const MockStartupLogs: TypesGen.WorkspaceAgentStartupLog[] = [
     output:
       "+ sh -s -- --method=standalone --prefix=/tmp/code-server --version 4.8.3",
     level: "info",
+    source: "",
   },
   {
     id: 166665,
     created_at: "2023-05-04T11:30:42.590731Z",
     output: "Ubuntu 22.04.2 LTS",
     level: "info",
+    source: "",
   },
   {
     id: 166666,
     created_at: "2023-05-04T11:30:42.593686Z",
     output: "Installing v4.8.3 of the amd64 release from GitHub.",
     level: "info",
+    source: "",
   },
 ]
 
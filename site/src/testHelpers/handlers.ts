@@ -192,6 +192,9 @@ export const handlers = [
 
     return res(ctx.status(200), ctx.json(response))
   }),
+  rest.get("/api/v2/users/:userId/mfa", async (req, res, ctx) => {
+    return res(ctx.status(200), ctx.json(M.MockUserMFA))
+  }),
   rest.get("/api/v2/users/:userId/gitsshkey", async (req, res, ctx) => {
     return res(ctx.status(200), ctx.json(M.MockGitSSHKey))
   }),
@@ -154,8 +154,14 @@ const loadInitialAuthData = async (): Promise<AuthData> => {
 const signIn = async (
   email: string,
   password: string,
+  ldap?: boolean,
+  totpCode?: string,
 ): Promise<AuthenticatedData> => {
-  await API.login(email, password)
+  if (ldap) {
+    await API.loginWithLDAP(email, password)
+  } else {
+    await API.login(email, password, totpCode)
+  }
   const [user, permissions] = await Promise.all([
     API.getAuthenticatedUser(),
     API.checkAuthorization({
@@ -188,7 +194,13 @@ export interface AuthContext {
 
 export type AuthEvent =
   | { type: "SIGN_OUT" }
-  | { type: "SIGN_IN"; email: string; password: string }
+  | {
+      type: "SIGN_IN"
+      email: string
+      password: string
+      ldap?: boolean
+      totpCode?: string
+    }
   | { type: "UPDATE_PROFILE"; data: TypesGen.UpdateUserProfileRequest }
 
 export const authMachine =
@@ -348,7 +360,8 @@ export const authMachine =
     {
       services: {
         loadInitialAuthData,
-        signIn: (_, { email, password }) => signIn(email, password),
+        signIn: (_, { email, password, ldap, totpCode }) =>
+          signIn(email, password, ldap, totpCode),
         signOut,
         updateProfile: async ({ data }, event) => {
           if (!data) {
@@ -67,6 +67,10 @@ type Options struct {
 	BlockEndpoints bool
 	Logger         slog.Logger
 	ListenPort     uint16
+	// ForwardTCPCallback is called when a connection is forwarded to a local
+	// port, and the returned func when the connection closes. It is
+	// optional.
+	ForwardTCPCallback func(remoteAddr net.Addr, port uint16) (closed func())
 }
 
 // NewConn constructs a new Wireguard server that will accept connections from the addresses provided.
@@ -238,7 +242,8 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		wireguardRouter: &router.Config{
 			LocalAddrs: netMap.Addresses,
 		},
-		wireguardEngine: wireguardEngine,
+		wireguardEngine:    wireguardEngine,
+		forwardTCPCallback: options.ForwardTCPCallback,
 	}
 	defer func() {
 		if err != nil {
@@ -357,6 +362,7 @@ type Conn struct {
 	lastDERPForcedWebsockets map[int]string
 	lastNetInfo              *tailcfg.NetInfo
 	nodeCallback             func(node *Node)
+	forwardTCPCallback       func(remoteAddr net.Addr, port uint16) (closed func())
 
 	trafficStats *connstats.Statistics
 }
@@ -841,6 +847,10 @@ func (c *Conn) forwardTCPToLocal(conn net.Conn, port uint16) {
 		return
 	}
 	defer server.Close()
+	if c.forwardTCPCallback != nil {
+		closed := c.forwardTCPCallback(conn.RemoteAddr(), port)
+		defer closed()
+	}
 
 	connClosed := make(chan error, 2)
 	go func() {